@@ -0,0 +1,59 @@
+package main
+
+import (
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/source"
+)
+
+// setPathSource는 config.yaml 경로의 소스 설정을 데이터베이스 스트림에 반영합니다
+// (예비 소스가 없는 경로는 빈 목록으로 저장해 이전 예비 소스를 지움)
+func setPathSource(dbStream *database.Stream, pathConfig core.PathConfig) {
+	dbStream.Source = pathConfig.Source
+	dbStream.SourceOnDemand = pathConfig.SourceOnDemand
+	dbStream.RTSPTransport = pathConfig.RTSPTransport
+	dbStream.BackupSources = append([]string{}, pathConfig.BackupSources...)
+	dbStream.FailbackAfter = 0
+	if d, err := time.ParseDuration(pathConfig.FailbackAfter); err == nil {
+		dbStream.FailbackAfter = int(d / time.Second)
+	}
+}
+
+// streamPathConfig는 데이터베이스 스트림의 소스 설정을 경로 설정으로 변환합니다
+func streamPathConfig(dbStream *database.Stream) core.PathConfig {
+	pathConfig := core.PathConfig{
+		Source:         dbStream.Source,
+		SourceOnDemand: dbStream.SourceOnDemand,
+		RTSPTransport:  dbStream.RTSPTransport,
+		BackupSources:  dbStream.BackupSources,
+	}
+	if dbStream.FailbackAfter > 0 {
+		pathConfig.FailbackAfter = (time.Duration(dbStream.FailbackAfter) * time.Second).String()
+	}
+	return pathConfig
+}
+
+// sourceStatus는 예비 소스가 있는 스트림의 현재 활성 소스 상태를 반환합니다
+func (app *Application) sourceStatus(streamID string) (source.FailoverStatus, bool) {
+	client, exists := app.getSource(streamID)
+	if !exists {
+		return source.FailoverStatus{}, false
+	}
+	failover, ok := client.(*source.Failover)
+	if !ok {
+		return source.FailoverStatus{}, false
+	}
+	return failover.Status(), true
+}
+
+// connectedSourceType은 연결된 소스의 종류를 반환합니다 (예비 소스로 전환된 경우 활성 소스의 종류)
+func connectedSourceType(client source.Source, sourceType string) string {
+	if failover, ok := client.(*source.Failover); ok {
+		if activeType, err := source.TypeOf(failover.Status().URL); err == nil {
+			return activeType
+		}
+	}
+	return sourceType
+}
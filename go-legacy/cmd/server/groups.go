@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/signaling"
+	"github.com/yourusername/cctv3/internal/webrtc"
+	"github.com/yourusername/cctv3/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// groupSession은 스트림 그룹 WebRTC 시청 세션입니다 (클라이언트/그룹당 1개)
+// 화질을 전환하면 새 화질 스트림을 추가로 구독하고, 키프레임에서 전환한 후 이전 구독을 해제합니다
+type groupSession struct {
+	clientID string
+	groupID  string
+	peer     *webrtc.Peer
+	codec    string // 협상된 비디오 코덱 (같은 코덱의 화질로만 재협상 없이 전환 가능)
+
+	mutex      sync.Mutex // 화질 전환 직렬화
+	rendition  string     // 선택한 화질 이름 (전환 대기 중이면 전환 대상)
+	streamID   string     // 선택한 화질의 스트림 ID
+	renditions []database.StreamRendition
+}
+
+// renditionState는 "rendition" 메시지로 보내는 화질 상태입니다
+type renditionState struct {
+	Rendition  string                     `json:"rendition"`
+	StreamID   string                     `json:"stream_id"`
+	Switching  bool                       `json:"switching"`  // 새 화질의 키프레임 대기 중
+	Renditions []database.StreamRendition `json:"renditions"` // 시청 가능한 화질 목록
+}
+
+// state는 현재 화질 상태를 반환합니다 (session.mutex를 잡은 상태로 호출)
+func (s *groupSession) state() renditionState {
+	active, _ := s.peer.ActiveSource()
+	return renditionState{
+		Rendition:  s.rendition,
+		StreamID:   s.streamID,
+		Switching:  active != s.streamID,
+		Renditions: s.renditions,
+	}
+}
+
+// handleRendition은 시그널링의 스트림 그룹 요청(Offer가 있으면 시청 시작, 없으면 화질 전환)을 처리합니다
+func (app *Application) handleRendition(req signaling.RenditionRequest, client *signaling.Client) (string, interface{}, error) {
+	group, err := app.groupRepo.Get(req.GroupID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 시청 권한이 있는 화질만 사용
+	identity := client.GetIdentity()
+	viewable := make([]database.StreamRendition, 0, len(group.Renditions))
+	for _, rendition := range group.Renditions {
+		if identity.CanView(rendition.StreamID) {
+			viewable = append(viewable, rendition)
+		}
+	}
+	group.Renditions = viewable
+	if len(group.Renditions) == 0 {
+		return "", nil, fmt.Errorf("access to stream group %s is not allowed", req.GroupID)
+	}
+
+	rendition, err := selectRendition(group, req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := client.GetID() + "/" + req.GroupID
+
+	if req.SDP != "" {
+		return app.startGroupSession(key, group, rendition, req.SDP, client)
+	}
+
+	app.groupMutex.Lock()
+	session, exists := app.groupSessions[key]
+	app.groupMutex.Unlock()
+
+	if !exists {
+		return "", nil, fmt.Errorf("no session for stream group %s", req.GroupID)
+	}
+
+	state, err := app.switchRendition(session, group, rendition, client)
+	if err != nil {
+		return "", nil, err
+	}
+	return "", state, nil
+}
+
+// selectRendition은 요청한 화질 이름이나 대역폭 추정치로 화질을 선택합니다
+// 둘 다 없으면 시청 시작 시 그룹의 기본 화질(첫 번째)을 사용합니다
+func selectRendition(group *database.StreamGroup, req signaling.RenditionRequest) (database.StreamRendition, error) {
+	switch {
+	case req.Rendition != "":
+		rendition, ok := group.Rendition(req.Rendition)
+		if !ok {
+			return rendition, fmt.Errorf("rendition %s not found in stream group %s", req.Rendition, group.ID)
+		}
+		return rendition, nil
+	case req.Bandwidth > 0:
+		rendition, _ := group.RenditionForBandwidth(req.Bandwidth)
+		return rendition, nil
+	case req.SDP != "":
+		return group.Renditions[0], nil
+	}
+	return database.StreamRendition{}, fmt.Errorf("rendition or bandwidth is required")
+}
+
+// startGroupSession은 스트림 그룹 시청 피어를 만들고 선택한 화질 스트림을 구독합니다
+func (app *Application) startGroupSession(key string, group *database.StreamGroup, rendition database.StreamRendition, offer string, client *signaling.Client) (string, interface{}, error) {
+	// 같은 클라이언트의 기존 그룹 세션은 교체
+	app.closeGroupSession(key)
+
+	stream, err := app.readyStream(rendition.StreamID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// WebRTC는 H.264/H.265만 전송 가능
+	codec := stream.GetVideoCodec()
+	if codec != "" && codec != "H264" && codec != "H265" {
+		return "", nil, fmt.Errorf("stream codec %s is not supported by WebRTC, use RTSP", codec)
+	}
+
+	peer, err := app.webrtcManager.CreatePeer(rendition.StreamID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create peer: %w", err)
+	}
+
+	answer, err := peer.CreateOffer(offer, codec)
+	if err != nil {
+		app.webrtcManager.RemovePeer(peer.GetID())
+		return "", nil, fmt.Errorf("failed to create answer: %w", err)
+	}
+
+	if err := stream.Subscribe(peer.Source(rendition.StreamID)); err != nil {
+		app.webrtcManager.RemovePeer(peer.GetID())
+		return "", nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	// 피어-스트림 매핑 저장 (피어 종료 시 현재 화질 구독 해제)
+	app.peerMutex.Lock()
+	app.peerStreams[peer.GetID()] = rendition.StreamID
+	app.peerMutex.Unlock()
+
+	session := &groupSession{
+		clientID:   client.GetID(),
+		groupID:    group.ID,
+		peer:       peer,
+		codec:      codec,
+		rendition:  rendition.Name,
+		streamID:   rendition.StreamID,
+		renditions: group.Renditions,
+	}
+
+	app.groupMutex.Lock()
+	app.groupSessions[key] = session
+	app.groupMutex.Unlock()
+
+	app.startPeerAudit(peer, rendition.StreamID, audit.ProtocolWebRTC, client)
+
+	logger.Info("Stream group session started",
+		zap.String("client_id", client.GetID()),
+		zap.String("group_id", group.ID),
+		zap.String("rendition", rendition.Name),
+		zap.String("stream_id", rendition.StreamID),
+		zap.String("peer_id", peer.GetID()),
+	)
+
+	session.mutex.Lock()
+	state := session.state()
+	session.mutex.Unlock()
+
+	return answer, state, nil
+}
+
+// switchRendition은 그룹 세션의 화질을 전환합니다 (새 화질의 다음 키프레임부터 전송)
+// 코덱이 다른 화질은 재협상이 필요하므로 새 Offer를 보내야 합니다
+func (app *Application) switchRendition(session *groupSession, group *database.StreamGroup, rendition database.StreamRendition, client *signaling.Client) (renditionState, error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	session.renditions = group.Renditions
+
+	if rendition.StreamID == session.streamID {
+		session.rendition = rendition.Name
+		return session.state(), nil
+	}
+
+	stream, err := app.readyStream(rendition.StreamID)
+	if err != nil {
+		return renditionState{}, err
+	}
+
+	codec := stream.GetVideoCodec()
+	if codec != session.codec {
+		return renditionState{}, fmt.Errorf("rendition %s uses codec %s but the session negotiated %s, send a new offer",
+			rendition.Name, codec, session.codec)
+	}
+
+	peerID := session.peer.GetID()
+	active, pending := session.peer.ActiveSource()
+
+	// 끝나지 않은 이전 전환의 구독 해제
+	if pending != "" && pending != rendition.StreamID {
+		app.unsubscribePeer(pending, peerID)
+	}
+
+	if rendition.StreamID == active {
+		// 현재 화질로 되돌리기 (대기 중인 전환 취소)
+		session.peer.SwitchSource(active, codec, nil)
+	} else {
+		if pending != rendition.StreamID {
+			if err := stream.Subscribe(session.peer.Source(rendition.StreamID)); err != nil {
+				return renditionState{}, fmt.Errorf("failed to subscribe: %w", err)
+			}
+		}
+
+		session.peer.SwitchSource(rendition.StreamID, codec, func(from, to string) {
+			app.completeRenditionSwitch(session, from, to, client)
+		})
+	}
+
+	logger.Info("Stream group rendition switching",
+		zap.String("group_id", session.groupID),
+		zap.String("from", session.rendition),
+		zap.String("to", rendition.Name),
+		zap.String("peer_id", peerID),
+	)
+
+	session.rendition = rendition.Name
+	session.streamID = rendition.StreamID
+	return session.state(), nil
+}
+
+// completeRenditionSwitch는 키프레임에서 화질 전환이 끝나면 이전 화질 구독을 해제하고 클라이언트에 알립니다
+func (app *Application) completeRenditionSwitch(session *groupSession, from, to string, client *signaling.Client) {
+	peerID := session.peer.GetID()
+	app.unsubscribePeer(from, peerID)
+
+	app.peerMutex.Lock()
+	if _, exists := app.peerStreams[peerID]; exists {
+		app.peerStreams[peerID] = to
+	}
+	app.peerMutex.Unlock()
+
+	session.mutex.Lock()
+	state := session.state()
+	session.mutex.Unlock()
+
+	logger.Info("Stream group rendition switched",
+		zap.String("group_id", session.groupID),
+		zap.String("from", from),
+		zap.String("to", to),
+		zap.String("peer_id", peerID),
+	)
+
+	client.SendRenditionState(state, session.groupID)
+}
+
+// unsubscribePeer는 피어를 스트림 구독에서 제거합니다 (이미 제거되었으면 무시)
+func (app *Application) unsubscribePeer(streamID, peerID string) {
+	if stream, err := app.streamManager.GetStream(streamID); err == nil {
+		stream.Unsubscribe(peerID)
+	}
+}
+
+// closeGroupSession은 그룹 세션을 종료합니다 (현재 화질 구독은 cleanupPeer에서 해제)
+func (app *Application) closeGroupSession(key string) {
+	app.groupMutex.Lock()
+	session, exists := app.groupSessions[key]
+	if exists {
+		delete(app.groupSessions, key)
+	}
+	app.groupMutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	// 전환 대기 중인 화질 구독 해제
+	if _, pending := session.peer.ActiveSource(); pending != "" {
+		app.unsubscribePeer(pending, session.peer.GetID())
+	}
+	session.peer.Close()
+
+	logger.Info("Stream group session closed",
+		zap.String("client_id", session.clientID),
+		zap.String("group_id", session.groupID),
+	)
+}
+
+// closeGroupSessionsWhere는 조건에 맞는 그룹 세션들을 종료합니다
+func (app *Application) closeGroupSessionsWhere(match func(session *groupSession) bool) {
+	app.groupMutex.Lock()
+	keys := make([]string, 0)
+	for key, session := range app.groupSessions {
+		if match(session) {
+			keys = append(keys, key)
+		}
+	}
+	app.groupMutex.Unlock()
+
+	for _, key := range keys {
+		app.closeGroupSession(key)
+	}
+}
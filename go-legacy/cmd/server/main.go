@@ -2,26 +2,44 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"os/signal"
 	"runtime"
 	"runtime/debug"
+	"slices"
 	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/rtp"
 	"github.com/yourusername/cctv3/internal/api"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/auth"
 	// "github.com/yourusername/cctv3/internal/cctv" // AIOT API 관련 - 향후 재사용을 위해 주석 처리
+	"github.com/yourusername/cctv3/internal/confwatcher"
 	"github.com/yourusername/cctv3/internal/core"
 	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/events"
 	"github.com/yourusername/cctv3/internal/hls"
+	"github.com/yourusername/cctv3/internal/hooks"
+	"github.com/yourusername/cctv3/internal/ingest"
+	"github.com/yourusername/cctv3/internal/metrics"
+	"github.com/yourusername/cctv3/internal/onvif"
+	"github.com/yourusername/cctv3/internal/playback"
 	"github.com/yourusername/cctv3/internal/process"
+	"github.com/yourusername/cctv3/internal/recorder"
 	"github.com/yourusername/cctv3/internal/rtsp"
+	"github.com/yourusername/cctv3/internal/secret"
 	"github.com/yourusername/cctv3/internal/signaling"
+	"github.com/yourusername/cctv3/internal/site"
+	"github.com/yourusername/cctv3/internal/snapshot"
+	"github.com/yourusername/cctv3/internal/source"
+	"github.com/yourusername/cctv3/internal/status"
+	"github.com/yourusername/cctv3/internal/transcode"
+	"github.com/yourusername/cctv3/internal/webhook"
 	"github.com/yourusername/cctv3/internal/webrtc"
 	"github.com/yourusername/cctv3/pkg/logger"
 	"go.uber.org/zap"
@@ -106,11 +124,10 @@ func main() {
 	logger.Info("Server configuration", logFields...)
 
 	// 서버 컴포넌트 초기화
-	app, err := initializeApplication(config)
+	app, err := initializeApplication(config, *configPath)
 	if err != nil {
 		logger.Fatal("Failed to initialize application", zap.Error(err))
 	}
-	defer app.cleanup()
 
 	logger.Info("All components initialized successfully")
 
@@ -119,6 +136,13 @@ func main() {
 		logger.Error("Failed to load streams", zap.Error(err))
 	}
 
+	// 설정 파일 변경 감시 (스트림 로드 후 시작해야 다시 로드와 겹치지 않음)
+	if config.Server.ConfigWatch {
+		if err := app.startConfigWatcher(); err != nil {
+			logger.Error("Failed to start config watcher", zap.Error(err))
+		}
+	}
+
 	// AIOT API 기반 CCTV 스트림 로드 - 향후 재사용을 위해 주석 처리
 	// if config.API != nil && config.API.Enabled {
 	// 	if err := app.cctvManager.Start(); err != nil {
@@ -131,24 +155,11 @@ func main() {
 	// 	}
 	// }
 
-	// 종료 시그널 대기
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	logger.Info("Server is running. Press Ctrl+C to stop.")
-
-	// 시그널 대기
-	sig := <-sigChan
-	logger.Info("Received shutdown signal",
-		zap.String("signal", sig.String()),
-	)
-
-	// TODO: 그레이스풀 셧다운
-	// 1. 새로운 연결 거부
-	// 2. 기존 연결 정리
-	// 3. 리소스 해제
-
-	logger.Info("Server stopped gracefully")
+	// 종료 시그널 대기 후 정리
+	if code := app.waitForShutdown(); code != 0 {
+		logger.Close()
+		os.Exit(code)
+	}
 }
 
 // maskRTSPURL은 RTSP URL의 비밀번호를 마스킹합니다
@@ -191,45 +202,115 @@ func maskRTSPURL(urlStr string) string {
 
 // Application은 애플리케이션 컴포넌트들을 관리합니다
 type Application struct {
-	config          *core.Config
+	config          atomic.Pointer[core.Config] // 설정 다시 로드 시 교체 (currentConfig()로 조회)
 	streamManager   *core.StreamManager
 	webrtcManager   *webrtc.Manager
-	hlsManager      *hls.Manager
+	hlsManager      atomic.Pointer[hls.Manager] // 비활성화면 nil, 설정 다시 로드 시 교체 (currentHLS()로 조회)
+	recorderManager *recorder.Manager
+	playbackManager *playback.Manager
 	signalingServer *signaling.Server
 	apiServer       *api.Server
 	processManager  *process.Manager
+	hooksManager    *hooks.Manager
+	authManager     *auth.Manager
 
 	// Database and repository
 	db         *database.DB
 	streamRepo *database.StreamRepository
+	groupRepo  *database.StreamGroupRepository
+
+	// 감사 로그 (비활성화면 nil)
+	auditRepo   *database.AuditRepository
+	auditLogger *audit.Logger
 
 	// AIOT API 관련 (향후 재사용을 위해 주석 처리)
 	// cctvManager     *cctv.CCTVManager
 
-	rtspClients map[string]*rtsp.Client // streamID -> RTSP client
-	rtspServer  *rtsp.ServerRTSP        // RTSP 서버 (ffmpeg publish/subscribe용)
+	sources      map[string]source.Source // streamID -> 소스 (RTSP/RTMP/SRT/HLS/UDP/MJPEG 클라이언트)
+	sourcesMutex sync.RWMutex             // sources 보호 (API/메트릭 고루틴에서도 조회)
+	rtspServer   *rtsp.ServerRTSP         // RTSP 서버 (ffmpeg publish/subscribe용)
+
+	// RTMP/SRT 송출 수신 (비활성화면 nil)
+	ingestManager *ingest.Manager
+
+	// ONVIF 카메라 검색/가져오기 (비활성화면 nil)
+	onvifManager *onvif.Manager
+
+	// 카메라 이벤트 수집 (비활성화면 nil)
+	eventRepo    *database.EventRepository
+	eventManager *events.Manager
+
+	// 스트림 스냅샷 (비활성화면 nil)
+	snapshotManager *snapshot.Manager
+
+	// 서버 측 트랜스코딩 (비활성화면 nil)
+	transcodeManager *transcode.Manager
+
+	// 웹훅 알림 (비활성화면 nil)
+	webhookManager *webhook.Manager
+
+	// 실시간 스트림 상태 (SSE, WebSocket 구독)
+	statusManager *status.Manager
+
+	// 사이트 (스트림 소속, 사이트별 동시 연결 제한과 시청 권한)
+	siteRepo    *database.SiteRepository
+	tagRepo     *database.TagRepository
+	siteManager *site.Manager
+	launchMutex sync.Mutex // 사이트 동시 연결 제한 확인과 소스 등록을 묶음
+
+	// Prometheus 메트릭 서버 (비활성화면 nil)
+	metricsServer *metrics.Server
+
+	// 설정 파일 경로와 변경 감시 (감시 비활성화면 configWatcher는 nil)
+	configPath    string
+	configWatcher *confwatcher.Watcher
+	reloadMutex   sync.Mutex // 설정 다시 로드 직렬화 (API, 파일 감시)
+
+	// 서버 시작 시각 (uptime)
+	startTime time.Time
 
 	// 피어와 스트림 매핑
-	peerStreams map[string]string // peerID -> streamID
+	peerStreams map[string]string     // peerID -> streamID
+	peerAudit   map[string]*peerAudit // peerID -> 시청 감사 세션
 	peerMutex   sync.RWMutex
 
+	// 녹화 재생 세션 (clientID/streamID -> session)
+	playbackSessions map[string]*playbackSession
+	playbackMutex    sync.Mutex
+
+	// 스트림 그룹 시청 세션 (clientID/groupID -> session)
+	groupSessions map[string]*groupSession
+	groupMutex    sync.Mutex
+
+	// 녹화/HLS 마무리 (shutdown과 cleanup 중 먼저 호출한 쪽에서 한 번만 시작, 끝나면 mediaDone 닫힘)
+	mediaOnce sync.Once
+	mediaDone chan struct{}
+
 	// Context for cancellation
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 }
 
 // initializeApplication은 애플리케이션을 초기화합니다
-func initializeApplication(config *core.Config) (*Application, error) {
+func initializeApplication(config *core.Config, configPath string) (*Application, error) {
 	// Context 생성 (프로세스 관리용)
 	ctx, cancel := context.WithCancel(context.Background())
 
 	app := &Application{
-		config:      config,
-		rtspClients: make(map[string]*rtsp.Client),
+		configPath:  configPath,
+		sources:     make(map[string]source.Source),
 		peerStreams: make(map[string]string),
+		peerAudit:   make(map[string]*peerAudit),
 		ctx:         ctx,
 		cancelFunc:  cancel,
+
+		playbackSessions: make(map[string]*playbackSession),
+		groupSessions:    make(map[string]*groupSession),
+		mediaDone:        make(chan struct{}),
+
+		startTime: time.Now(),
 	}
+	app.config.Store(config)
 
 	// 1. 스트림 관리자 초기화 (먼저 초기화해야 함)
 	// Phase 2.3: config 기반 비디오 버퍼 크기 전달
@@ -242,15 +323,103 @@ func initializeApplication(config *core.Config) (*Application, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 	app.db = db
-	app.streamRepo = database.NewStreamRepository(db, logger.Log)
+
+	// 카메라 인증 정보 암호화 키 로드
+	keyring, generated, err := secret.Load(secret.Config{
+		KeyFile:          config.Database.SecretKeyFile,
+		PreviousKeyFiles: config.Database.PreviousSecretKeyFiles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load secret key: %w", err)
+	}
+	if generated {
+		logger.Warn("New secret key generated - back it up, stored credentials cannot be decrypted without it",
+			zap.String("path", config.Database.SecretKeyFile),
+		)
+	}
+
+	app.streamRepo = database.NewStreamRepository(db, keyring, logger.Log)
+	app.groupRepo = database.NewStreamGroupRepository(db, logger.Log)
+	app.siteRepo = database.NewSiteRepository(db, logger.Log)
+	app.tagRepo = database.NewTagRepository(db, logger.Log)
+
+	// 평문 인증 정보 암호화 및 이전 키로 암호화된 값 재암호화 (키 교체)
+	encrypted, err := app.streamRepo.EncryptCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt stream credentials: %w", err)
+	}
+	if encrypted > 0 {
+		logger.Info("Stream credentials encrypted with current key", zap.Int("count", encrypted))
+	}
+
 	logger.Info("Database initialized successfully",
 		zap.String("path", config.Database.Path),
 	)
 
+	app.siteManager, err = site.NewManager(site.Config{
+		Repository: app.siteRepo,
+		Logger:     logger.Log,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sites: %w", err)
+	}
+
+	// 1.6. 감사 로그 초기화
+	if config.Audit.Enabled {
+		app.auditRepo = database.NewAuditRepository(db, logger.Log)
+		app.auditLogger = audit.NewLogger(audit.Config{
+			Enabled:   true,
+			Retention: time.Duration(config.Audit.RetentionDays) * 24 * time.Hour,
+		}, app.auditRepo, logger.Log)
+		app.auditLogger.Start(ctx)
+		logger.Info("Audit logger initialized",
+			zap.Int("retention_days", config.Audit.RetentionDays),
+		)
+	}
+
 	// 2. ProcessManager 초기화 (runOnDemand 프로세스 관리)
 	app.processManager = process.NewManager(logger.Log)
 	logger.Info("ProcessManager initialized")
 
+	// 2.1. 경로 훅 초기화 (runOnInit, runOnDemand, runOnReady/NotReady, runOnRead/Unread)
+	app.hooksManager = hooks.NewManager(hooks.Config{
+		RTSPPort:       config.RTSP.Server.Port,
+		ProcessManager: app.processManager,
+		StreamManager:  app.streamManager,
+		Paths: func() map[string]core.PathConfig {
+			return app.currentConfig().Paths
+		},
+		Logger: logger.Log,
+	})
+
+	// 2.2. 웹훅 초기화 (스트림/시청/녹화 이벤트 알림, 전송 대기열은 DB에 저장)
+	if config.Webhooks.Enabled {
+		app.webhookManager = webhook.NewManager(webhook.Config{
+			Timeout:      time.Duration(config.Webhooks.Timeout) * time.Second,
+			MaxAttempts:  config.Webhooks.MaxAttempts,
+			RetryBackoff: time.Duration(config.Webhooks.RetryBackoff) * time.Second,
+			MaxBackoff:   time.Duration(config.Webhooks.MaxBackoff) * time.Second,
+			Retention:    time.Duration(config.Webhooks.RetentionDays) * 24 * time.Hour,
+		}, database.NewWebhookRepository(db, keyring, logger.Log), logger.Log)
+		if err := app.webhookManager.Start(ctx); err != nil {
+			return nil, fmt.Errorf("failed to start webhooks: %w", err)
+		}
+		logger.Info("Webhook manager initialized",
+			zap.Int("max_attempts", config.Webhooks.MaxAttempts),
+			zap.Int("retention_days", config.Webhooks.RetentionDays),
+		)
+	}
+
+	// 2.3. 실시간 스트림 상태 초기화 (대시보드용 SSE/WebSocket 구독)
+	app.statusManager = status.NewManager(status.Config{
+		StreamManager: app.streamManager,
+		Interval:      time.Duration(config.Server.StatusInterval) * time.Second,
+		Logger:        logger.Log,
+	})
+	app.statusManager.Start(ctx)
+
+	app.streamManager.SetHooks(app.streamHooks())
+
 	// 3. CCTV Manager 초기화 (외부 API 연동) - 향후 재사용을 위해 주석 처리
 	// if config.API != nil && config.API.Enabled {
 	// 	app.cctvManager = cctv.NewCCTVManager(cctv.Config{
@@ -264,10 +433,49 @@ func initializeApplication(config *core.Config) (*Application, error) {
 	// 	logger.Info("CCTV manager initialized")
 	// }
 
+	// 3.5. 인증 관리자 초기화 (API, WebSocket, HLS, RTSP 공용)
+	apiKeys := make([]auth.APIKey, 0, len(config.Auth.APIKeys))
+	for _, key := range config.Auth.APIKeys {
+		role, err := auth.ParseRole(key.Role)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth api key '%s': %w", key.Name, err)
+		}
+		apiKeys = append(apiKeys, auth.APIKey{
+			Name:    key.Name,
+			Key:     key.Key,
+			Role:    role,
+			Streams: key.Streams,
+			Sites:   key.Sites,
+		})
+	}
+	app.authManager, err = auth.NewManager(auth.Config{
+		Enabled:         config.Auth.Enabled,
+		APIKeys:         apiKeys,
+		JWTJWKS:         config.Auth.JWT.JWKS,
+		JWTIssuer:       config.Auth.JWT.Issuer,
+		JWTAudience:     config.Auth.JWT.Audience,
+		JWTRoleClaim:    config.Auth.JWT.RoleClaim,
+		JWTStreamsClaim: config.Auth.JWT.StreamsClaim,
+		JWTSitesClaim:   config.Auth.JWT.SitesClaim,
+		TokenSecret:     config.Auth.Tokens.Secret,
+		TokenDefaultTTL: time.Duration(config.Auth.Tokens.DefaultTTL) * time.Second,
+		TokenMaxTTL:     time.Duration(config.Auth.Tokens.MaxTTL) * time.Second,
+	}, logger.Log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize auth manager: %w", err)
+	}
+	app.authManager.SetSiteResolver(app.siteManager.SiteOf)
+	logger.Info("Auth manager initialized",
+		zap.Bool("enabled", config.Auth.Enabled),
+		zap.Int("api_keys", len(apiKeys)),
+		zap.Bool("jwt", config.Auth.JWT.JWKS != ""),
+	)
+
 	// 4. WebRTC 관리자 초기화
 	app.webrtcManager = webrtc.NewManager(webrtc.ManagerConfig{
-		Logger:   logger.Log,
-		MaxPeers: config.WebRTC.Settings.MaxPeers,
+		Logger:     logger.Log,
+		MaxPeers:   config.WebRTC.Settings.MaxPeers,
+		ICEServers: iceServers(config.WebRTC.ICEServers),
 		OnPeerClosed: func(peerID string) {
 			app.cleanupPeer(peerID)
 		},
@@ -275,28 +483,98 @@ func initializeApplication(config *core.Config) (*Application, error) {
 	logger.Info("WebRTC manager initialized")
 
 	// 4.5. HLS 관리자 초기화
-	if config.HLS.Enabled {
-		app.hlsManager = hls.NewManager(hls.Config{
-			Enabled:           config.HLS.Enabled,
-			SegmentDuration:   config.HLS.SegmentDuration,
-			SegmentCount:      config.HLS.SegmentCount,
-			OutputDir:         config.HLS.OutputDir,
-			CleanupThreshold:  config.HLS.CleanupThreshold,
-			EnableCompression: config.HLS.EnableCompression,
+	app.hlsManager.Store(newHLSManager(config.HLS))
+
+	// 4.6. 녹화/재생 관리자 초기화
+	app.recorderManager = recorder.NewManager(recorder.Config{
+		Enabled:         config.Record.Enabled,
+		Path:            config.Record.Path,
+		SegmentDuration: time.Duration(config.Record.SegmentDuration) * time.Second,
+		PartDuration:    time.Duration(config.Record.PartDuration) * time.Millisecond,
+		DeleteAfter:     time.Duration(config.Record.DeleteAfter) * time.Hour,
+		EventPreRoll:    time.Duration(config.Events.Record.PreRoll) * time.Second,
+		EventPostRoll:   time.Duration(config.Events.Record.PostRoll) * time.Second,
+
+		OnSegmentComplete: app.segmentCompleted,
+	}, logger.Log)
+	// 녹화가 비활성화되어도 기존 녹화 파일은 재생 가능
+	app.playbackManager = playback.NewManager(config.Record.Path, logger.Log)
+	if config.Record.Enabled || app.eventRecordingEnabled() {
+		go app.recorderManager.StartCleaner(ctx)
+		logger.Info("Recorder manager initialized",
+			zap.String("path", config.Record.Path),
+			zap.Int("segment_duration", config.Record.SegmentDuration),
+			zap.Bool("event_recording", app.eventRecordingEnabled()),
+		)
+	}
+
+	// 4.7. ONVIF 관리자 초기화 (카메라 검색/가져오기)
+	if config.ONVIF.Enabled {
+		app.onvifManager = onvif.NewManager(onvif.Config{
+			DiscoveryTimeout: time.Duration(config.ONVIF.DiscoveryTimeout) * time.Second,
+			RequestTimeout:   time.Duration(config.ONVIF.RequestTimeout) * time.Second,
+			Interface:        config.ONVIF.Interface,
+			Logger:           logger.Log,
+		})
+		logger.Info("ONVIF manager initialized")
+	}
+
+	// 4.8. 카메라 이벤트 수집 초기화 (ONVIF PullPoint, HTTP 알람)
+	if config.Events.Enabled {
+		if err := app.initEvents(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 4.9. 스냅샷 관리자 초기화 (JPEG 썸네일, 디코더는 ProcessManager로 실행)
+	if config.Snapshot.Enabled {
+		app.snapshotManager = snapshot.NewManager(snapshot.Config{
+			Interval:       time.Duration(config.Snapshot.Interval) * time.Second,
+			DecoderCommand: config.Snapshot.DecoderCommand,
+			Timeout:        time.Duration(config.Snapshot.Timeout) * time.Second,
+			MaxConcurrent:  config.Snapshot.MaxConcurrent,
+			Quality:        config.Snapshot.Quality,
+			ProcessManager: app.processManager,
 		}, logger.Log)
-		logger.Info("HLS manager initialized",
-			zap.String("output_dir", config.HLS.OutputDir),
-			zap.Int("segment_duration", config.HLS.SegmentDuration),
-			zap.Int("segment_count", config.HLS.SegmentCount),
+		go app.snapshotManager.Start(ctx)
+		logger.Info("Snapshot manager initialized",
+			zap.Int("interval", config.Snapshot.Interval),
+			zap.Bool("decoder", config.Snapshot.DecoderCommand != ""),
+		)
+	}
+
+	// 4.10. 트랜스코딩 관리자 초기화 (파생 스트림 CAM1~h264_720p, 트랜스코더는 ProcessManager로 실행)
+	if config.Transcode.Enabled {
+		if err := app.initTranscode(); err != nil {
+			return nil, err
+		}
+		go app.transcodeManager.Run(ctx)
+	}
+
+	// 이전 버전은 모든 Origin을 허용했으므로, 다른 Origin의 대시보드/플레이어가 거부될 수 있음을 알림
+	if !slices.Contains(config.Server.AllowedOrigins, "*") {
+		logger.Warn("CORS/WebSocket only allow server.allowed_origins (all origins were allowed before) - add the origins of other dashboards/players or set [\"*\"]",
+			zap.Strings("allowed_origins", config.Server.AllowedOrigins),
 		)
 	}
 
 	// 5. 시그널링 서버 초기화
 	app.signalingServer = signaling.NewServer(signaling.ServerConfig{
-		Logger: logger.Log,
+		Logger:         logger.Log,
+		AllowedOrigins: config.Server.AllowedOrigins,
 		OnOffer: func(offer string, streamID string, client *signaling.Client) (string, error) {
 			return app.handleWebRTCOffer(offer, streamID, client)
 		},
+		OnPlayback: func(req signaling.PlaybackRequest, client *signaling.Client) (string, error) {
+			return app.handlePlayback(req, client)
+		},
+		OnPTZ: func(streamID string, payload json.RawMessage, client *signaling.Client) (interface{}, error) {
+			return app.handlePTZMessage(streamID, payload, client)
+		},
+		OnRendition: func(req signaling.RenditionRequest, client *signaling.Client) (string, interface{}, error) {
+			return app.handleRendition(req, client)
+		},
+		StatusManager: app.statusManager,
 		OnClose: func(clientID string) {
 			logger.Info("Client disconnected",
 				zap.String("client_id", clientID),
@@ -314,19 +592,19 @@ func initializeApplication(config *core.Config) (*Application, error) {
 		Port:       config.Server.HTTPPort,
 		Production: config.Server.Production,
 		Logger:     logger.Log,
+		StartTime:  app.startTime,
 		HealthHandler: func() map[string]interface{} {
 			return map[string]interface{}{
 				"status":  "ok",
 				"version": version,
-				"streams": len(app.rtspClients),
+				"streams": app.sourceCount(),
 				"clients": app.signalingServer.GetClientCount(),
 				"peers":   app.webrtcManager.GetPeerCount(),
 			}
 		},
 		StatsHandler: func() map[string]interface{} {
 			stats := map[string]interface{}{
-				"uptime":  "0h 0m 0s", // TODO: 실제 uptime 계산
-				"streams": len(app.rtspClients),
+				"streams": app.sourceCount(),
 				"clients": app.signalingServer.GetClientCount(),
 				"peers":   app.webrtcManager.GetPeerCount(),
 			}
@@ -372,10 +650,31 @@ func initializeApplication(config *core.Config) (*Application, error) {
 		StopStreamHandler: func(streamID string) error {
 			return app.stopStream(streamID)
 		},
-		StreamRepository: app.streamRepo,    // Database repository for CRUD operations
-		StreamManager:    app.streamManager, // Stream manager for runtime streams
+		SourceStatusHandler: app.sourceStatus,
+		StreamRepository:    app.streamRepo,    // Database repository for CRUD operations
+		GroupRepository:     app.groupRepo,     // 스트림 그룹 (메인/서브 화질)
+		SiteRepository:      app.siteRepo,      // 사이트 (설치 장소)
+		TagRepository:       app.tagRepo,       // 스트림 태그
+		SiteManager:         app.siteManager,   // 사이트 소속/동시 연결 제한
+		StreamManager:       app.streamManager, // Stream manager for runtime streams
 		// CCTVManager: app.cctvManager, // AIOT API 관련 - 향후 재사용을 위해 주석 처리
-		HLSManager: app.hlsManager,
+		HLSManager:      app.currentHLS(),
+		PlaybackManager: app.playbackManager,
+		AuthManager:     app.authManager,
+		AuditLogger:     app.auditLogger,
+		AuditRepository: app.auditRepo,
+		ONVIFManager:    app.onvifManager,
+		PTZHandler:      app.controlPTZ,
+		PTZRole:         auth.Role(config.ONVIF.PTZRole),
+		EventManager:    app.eventManager,
+		SnapshotManager: app.snapshotManager,
+		AllowedOrigins:  config.Server.AllowedOrigins,
+		TrustedProxies:  config.Server.TrustedProxies,
+
+		TranscodeManager:    app.transcodeManager,
+		WebhookManager:      app.webhookManager,
+		StatusManager:       app.statusManager,
+		ConfigReloadHandler: app.reloadConfig,
 	})
 
 	// API 서버 시작
@@ -384,12 +683,44 @@ func initializeApplication(config *core.Config) (*Application, error) {
 	}
 	logger.Info("API server started")
 
+	// 7.5. Prometheus 메트릭 서버 초기화 (API 서버와 별도 포트)
+	if config.Metrics.Enabled {
+		app.metricsServer = metrics.NewServer(metrics.Config{
+			Address:       fmt.Sprintf(":%d", config.Metrics.Port),
+			Interval:      time.Duration(config.Metrics.Interval) * time.Second,
+			Version:       version,
+			StartTime:     app.startTime,
+			StreamManager: app.streamManager,
+			WebRTCManager: app.webrtcManager,
+			HLSManager:    app.currentHLS(),
+			AuthManager:   app.authManager,
+			SourceStates:  app.sourceStates,
+			ClientCount:   app.signalingServer.GetClientCount,
+			Logger:        logger.Log,
+		})
+
+		if err := app.metricsServer.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start metrics server: %w", err)
+		}
+		logger.Info("Metrics server started", zap.Int("port", config.Metrics.Port))
+	} else {
+		logger.Info("Metrics disabled in configuration")
+	}
+
+	// 이벤트 수집 시작 (ONVIF 구독, 보관 기간 정리)
+	if app.eventManager != nil {
+		app.eventManager.Start(ctx)
+	}
+
 	// 8. RTSP 서버 초기화 (ffmpeg publish/subscribe용)
 	if config.RTSP.Server.Enabled {
 		app.rtspServer = rtsp.NewServerRTSP(rtsp.ServerRTSPConfig{
 			Address:       fmt.Sprintf(":%d", config.RTSP.Server.Port),
 			StreamManager: app.streamManager,
 			Logger:        logger.Log,
+			AuthManager:   app.authManager,
+			AuditLogger:   app.auditLogger,
+			EnsureStream:  app.ensureStream,
 		})
 
 		if err := app.rtspServer.Start(); err != nil {
@@ -400,8 +731,34 @@ func initializeApplication(config *core.Config) (*Application, error) {
 		logger.Info("RTSP server disabled in configuration")
 	}
 
+	// 8.5. RTMP/SRT 수신 초기화 (모바일 장비, RTSP 미지원 인코더용)
+	if config.Ingest.RTMP.Enabled || config.Ingest.SRT.Enabled {
+		app.ingestManager = ingest.NewManager(ingest.Config{
+			RTMPEnabled:      config.Ingest.RTMP.Enabled,
+			RTMPAddress:      fmt.Sprintf(":%d", config.Ingest.RTMP.Port),
+			SRTEnabled:       config.Ingest.SRT.Enabled,
+			SRTAddress:       fmt.Sprintf(":%d", config.Ingest.SRT.Port),
+			SRTPassphrase:    config.Ingest.SRT.Passphrase,
+			ReadTimeout:      time.Duration(config.Ingest.ReadTimeout) * time.Second,
+			StreamKeys:       config.Ingest.StreamKeys,
+			RequireStreamKey: config.Ingest.RequireStreamKey,
+			StreamManager:    app.streamManager,
+			AuthManager:      app.authManager,
+			Logger:           logger.Log,
+			OnPublish:        app.startStreamOutputs,
+			OnPacket:         app.writePacketToHLS,
+		})
+
+		if err := app.ingestManager.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start ingest listeners: %w", err)
+		}
+	} else {
+		logger.Info("RTMP/SRT ingest disabled in configuration")
+	}
+
 	// 9. ProcessManager 비활동 모니터 시작
 	go app.processManager.StartInactivityMonitor(ctx)
+	go app.hooksManager.Run(ctx)
 	logger.Info("ProcessManager inactivity monitor started")
 
 	return app, nil
@@ -409,19 +766,12 @@ func initializeApplication(config *core.Config) (*Application, error) {
 
 // loadStreamsFromConfigAndDB는 config.yaml과 데이터베이스에서 스트림을 로드합니다
 func (app *Application) loadStreamsFromConfigAndDB() error {
-	// 1. config.yaml을 Database에 동기화 (UPSERT)
-	if app.config.Paths != nil && len(app.config.Paths) > 0 {
-		logger.Info("Syncing config.yaml to database", zap.Int("count", len(app.config.Paths)))
-
-		for streamID, pathConfig := range app.config.Paths {
-			dbStream := &database.Stream{
-				ID:             streamID,
-				Name:           streamID, // config.yaml은 Name이 없으므로 ID 사용
-				Source:         pathConfig.Source,
-				SourceOnDemand: pathConfig.SourceOnDemand,
-				RTSPTransport:  pathConfig.RTSPTransport,
-			}
+	// 1. config.yaml을 Database에 동기화 (없는 경로만 INSERT)
+	paths := app.currentConfig().Paths
+	if len(paths) > 0 {
+		logger.Info("Syncing config.yaml to database", zap.Int("count", len(paths)))
 
+		for streamID, pathConfig := range paths {
 			// Database에 이미 존재하는지 확인
 			existing, err := app.streamRepo.Get(streamID)
 			if err == nil {
@@ -430,10 +780,19 @@ func (app *Application) loadStreamsFromConfigAndDB() error {
 					zap.String("stream_id", streamID),
 					zap.String("existing_name", existing.Name),
 				)
+				// 출처 표시 이전 버전에서 동기화된 행도 config.yaml 경로로 관리
+				app.adoptConfigStream(existing)
 				continue
 			}
 
 			// 존재하지 않으면 INSERT
+			dbStream := &database.Stream{
+				ID:     streamID,
+				Name:   streamID, // config.yaml은 Name이 없으므로 ID 사용
+				Origin: database.StreamOriginConfig,
+			}
+			setPathSource(dbStream, pathConfig)
+
 			if err := app.streamRepo.Create(dbStream); err != nil {
 				logger.Error("Failed to sync config stream to database",
 					zap.String("stream_id", streamID),
@@ -448,6 +807,11 @@ func (app *Application) loadStreamsFromConfigAndDB() error {
 		}
 	}
 
+	// config.yaml에서 삭제된 경로의 스트림 삭제 (설정 다시 로드와 동일, config.yaml에서 생성된 스트림만)
+	if err := app.pruneConfigStreams(paths); err != nil {
+		return err
+	}
+
 	// 2. Database를 Single Source of Truth로 사용
 	dbStreams, err := app.streamRepo.List()
 	if err != nil {
@@ -474,16 +838,22 @@ func (app *Application) loadStreamsFromConfigAndDB() error {
 			continue
 		}
 
-		// sourceOnDemand=false인 경우 즉시 RTSP 클라이언트 시작
-		if !dbStream.SourceOnDemand {
-			pathConfig := core.PathConfig{
-				Source:         dbStream.Source,
-				SourceOnDemand: dbStream.SourceOnDemand,
-				RTSPTransport:  dbStream.RTSPTransport,
-			}
+		// config.yaml 경로의 runOnInit 훅 시작
+		app.hooksManager.InitPath(dbStream.ID)
 
-			if err := app.startRTSPClient(dbStream.ID, pathConfig); err != nil {
-				logger.Error("Failed to start RTSP client",
+		if app.hooksManager.HasDemand(dbStream.ID) {
+			logger.Info("Stream loaded (runOnDemand)",
+				zap.String("stream_id", dbStream.ID),
+				zap.String("name", dbStream.Name),
+			)
+		} else if dbStream.CredentialsError && dbStream.Source == "" {
+			logger.Error("Stream credentials cannot be decrypted, source not started",
+				zap.String("stream_id", dbStream.ID),
+			)
+		} else if !dbStream.SourceOnDemand {
+			// sourceOnDemand=false인 경우 즉시 소스 시작
+			if err := app.startSource(dbStream.ID, streamPathConfig(dbStream)); err != nil {
+				logger.Error("Failed to start stream source",
 					zap.String("stream_id", dbStream.ID),
 					zap.Error(err),
 				)
@@ -507,24 +877,20 @@ func (app *Application) loadStreamsFromConfigAndDB() error {
 	return nil
 }
 
-// startRTSPClient는 RTSP 클라이언트를 시작하는 헬퍼 메서드입니다
-func (app *Application) startRTSPClient(streamID string, pathConfig core.PathConfig) error {
+// startSource는 스트림 소스를 시작하는 헬퍼 메서드입니다
+func (app *Application) startSource(streamID string, pathConfig core.PathConfig) error {
 	// Stream 가져오기
 	stream, err := app.streamManager.GetStream(streamID)
 	if err != nil {
 		return fmt.Errorf("stream not found: %w", err)
 	}
 
-	// RTSP 클라이언트 생성 및 시작
-	client, err := app.createRTSPClient(streamID, pathConfig.Source, pathConfig.RTSPTransport, stream)
-	if err != nil {
+	// 소스 생성, 시작 및 등록
+	if err := app.launchSource(streamID, pathConfig, stream); err != nil {
 		return err
 	}
 
-	// map에 저장
-	app.rtspClients[streamID] = client
-
-	logger.Info("RTSP stream started",
+	logger.Info("Stream source started",
 		zap.String("stream_id", streamID),
 		zap.String("url", maskRTSPURL(pathConfig.Source)),
 	)
@@ -532,22 +898,119 @@ func (app *Application) startRTSPClient(streamID string, pathConfig core.PathCon
 	return nil
 }
 
-// createRTSPClient은 RTSP 클라이언트를 생성하고 시작하는 헬퍼 메서드입니다
-func (app *Application) createRTSPClient(streamID, rtspURL, transport string, stream *core.Stream) (*rtsp.Client, error) {
-	// Transport 기본값 설정
+// launchSource는 사이트 동시 연결 제한을 확인한 뒤 소스를 생성/시작하고 등록합니다
+// 확인과 등록 사이에 다른 소스가 시작되어 제한을 넘지 않도록 launchMutex로 묶습니다
+func (app *Application) launchSource(streamID string, pathConfig core.PathConfig, stream *core.Stream) error {
+	app.launchMutex.Lock()
+	defer app.launchMutex.Unlock()
+
+	if err := app.siteManager.CheckLimit(streamID, app.runningSources()); err != nil {
+		logger.Warn("Stream source not started",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	client, err := app.createSource(streamID, pathConfig, stream)
+	if err != nil {
+		return err
+	}
+
+	// map에 저장
+	app.setSource(streamID, client)
+	return nil
+}
+
+// runningSources는 실행 중인 소스의 스트림 ID 목록을 반환합니다
+func (app *Application) runningSources() []string {
+	app.sourcesMutex.RLock()
+	defer app.sourcesMutex.RUnlock()
+
+	streamIDs := make([]string, 0, len(app.sources))
+	for streamID := range app.sources {
+		streamIDs = append(streamIDs, streamID)
+	}
+	return streamIDs
+}
+
+// setSource는 실행 중인 스트림 소스를 등록합니다
+func (app *Application) setSource(streamID string, client source.Source) {
+	app.sourcesMutex.Lock()
+	defer app.sourcesMutex.Unlock()
+	app.sources[streamID] = client
+}
+
+// getSource는 실행 중인 스트림 소스를 조회합니다
+func (app *Application) getSource(streamID string) (source.Source, bool) {
+	app.sourcesMutex.RLock()
+	defer app.sourcesMutex.RUnlock()
+	client, exists := app.sources[streamID]
+	return client, exists
+}
+
+// removeSource는 스트림 소스 등록을 해제하고 반환합니다 (중지는 호출자가 처리)
+func (app *Application) removeSource(streamID string) (source.Source, bool) {
+	app.sourcesMutex.Lock()
+	defer app.sourcesMutex.Unlock()
+	client, exists := app.sources[streamID]
+	delete(app.sources, streamID)
+	return client, exists
+}
+
+// sourceCount는 실행 중인 스트림 소스 수를 반환합니다
+func (app *Application) sourceCount() int {
+	app.sourcesMutex.RLock()
+	defer app.sourcesMutex.RUnlock()
+	return len(app.sources)
+}
+
+// sourceStates는 실행 중인 스트림 소스별 연결 상태를 반환합니다 (메트릭용)
+func (app *Application) sourceStates() map[string]bool {
+	app.sourcesMutex.RLock()
+	defer app.sourcesMutex.RUnlock()
+
+	states := make(map[string]bool, len(app.sources))
+	for streamID, client := range app.sources {
+		states[streamID] = client.IsConnected()
+	}
+	return states
+}
+
+// createSource는 URL 스킴에 맞는 소스(RTSP, RTMP, SRT, HLS, UDP MPEG-TS, HTTP MJPEG)를 생성하고 시작하는 헬퍼 메서드입니다
+// 예비 소스가 있으면 장애 시 순서대로 전환하는 Failover 소스를 생성합니다
+func (app *Application) createSource(streamID string, pathConfig core.PathConfig, stream *core.Stream) (source.Source, error) {
+	// Transport 기본값 설정 (RTSP 전용)
+	transport := pathConfig.RTSPTransport
 	if transport == "" {
 		transport = "tcp"
 	}
 
-	// RTSP 클라이언트 생성
-	client, err := rtsp.NewClient(rtsp.ClientConfig{
-		URL:        rtspURL,
-		Transport:  transport,
-		Timeout:    time.Duration(app.config.RTSP.Client.Timeout) * time.Second,
-		RetryCount: app.config.RTSP.Client.RetryCount,
-		RetryDelay: time.Duration(app.config.RTSP.Client.RetryDelay) * time.Second,
-		Logger:     logger.Log,
-		Stream:     stream,
+	sourceType, err := source.TypeOf(pathConfig.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	var failbackAfter time.Duration
+	if pathConfig.FailbackAfter != "" {
+		if failbackAfter, err = time.ParseDuration(pathConfig.FailbackAfter); err != nil {
+			return nil, fmt.Errorf("invalid failbackAfter: %w", err)
+		}
+	}
+
+	// 소스 생성 (재연결 동작은 모든 소스가 동일, 설정 다시 로드 후 시작되는 소스부터 새 값 적용)
+	clientConfig := app.currentConfig().RTSP.Client
+	var client source.Source
+	client, err = source.New(source.Config{
+		URL:           pathConfig.Source,
+		BackupURLs:    pathConfig.BackupSources,
+		FailbackAfter: failbackAfter,
+		Transport:     transport,
+		Timeout:       time.Duration(clientConfig.Timeout) * time.Second,
+		RetryCount:    clientConfig.RetryCount,
+		RetryDelay:    time.Duration(clientConfig.RetryDelay) * time.Second,
+		Logger:        logger.Log,
+		Stream:        stream,
 		OnPacket: func(pkt *rtp.Packet) {
 			// Stream Manager에 패킷 전달 (WebRTC용)
 			if err := stream.WritePacket(pkt); err != nil {
@@ -558,160 +1021,178 @@ func (app *Application) createRTSPClient(streamID, rtspURL, transport string, st
 			}
 
 			// HLS Manager에 패킷 전달
-			if app.hlsManager != nil && app.hlsManager.IsEnabled() {
-				if err := app.hlsManager.WritePacket(streamID, pkt); err != nil {
-					// HLS 패킷 쓰기 실패는 로그만 남기고 계속 진행
-					logger.Debug("Failed to write packet to HLS",
-						zap.String("stream_id", streamID),
-						zap.Error(err),
-					)
-				}
-			}
+			app.writePacketToHLS(streamID, pkt)
 		},
 		OnConnect: func() {
-			logger.Info("RTSP client connected", zap.String("stream_id", streamID))
-
-			// HLS Muxer 생성 (HLS가 활성화된 경우)
-			if app.hlsManager != nil && app.hlsManager.IsEnabled() {
-				// 스트림에서 실제 코덱 가져오기
-				stream, err := app.streamManager.GetStream(streamID)
-				if err != nil {
-					logger.Error("Failed to get stream for HLS muxer",
-						zap.String("stream_id", streamID),
-						zap.Error(err),
-					)
-					return
-				}
-
-				codec := stream.GetVideoCodec()
-				if codec == "" {
-					codec = "H264" // 기본값
-				}
-
-				// SPS/PPS는 RTP 패킷에서 동적 감지
-				if _, err := app.hlsManager.CreateMuxer(streamID, codec, nil, nil, nil); err != nil {
-					logger.Error("Failed to create HLS muxer",
-						zap.String("stream_id", streamID),
-						zap.String("codec", codec),
-						zap.Error(err),
-					)
-				} else {
-					logger.Info("HLS muxer created",
-						zap.String("stream_id", streamID),
-						zap.String("codec", codec),
-					)
-				}
-			}
+			connectedType := connectedSourceType(client, sourceType)
+			logger.Info("Source connected",
+				zap.String("stream_id", streamID),
+				zap.String("source_type", connectedType),
+			)
+			// 경로 훅 소스 종류는 mediamtx와 같이 rtspSource, rtmpSource, hlsSource 등
+			stream.SetReady(connectedType+"Source", "")
+			app.startStreamOutputs(streamID, stream)
 		},
 		OnDisconnect: func(err error) {
-			logger.Warn("RTSP client disconnected",
+			logger.Warn("Source disconnected",
 				zap.String("stream_id", streamID),
+				zap.String("source_type", sourceType),
 				zap.Error(err),
 			)
+			app.statusManager.SourceError(streamID, err)
+			stream.SetNotReady()
+			stream.AddSourceReconnect()
 		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create RTSP client: %w", err)
+		return nil, fmt.Errorf("failed to create %s source: %w", sourceType, err)
 	}
 
-	// RTSP 클라이언트 시작
+	// 소스 시작
 	if err := client.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start RTSP client: %w", err)
+		return nil, fmt.Errorf("failed to start %s source: %w", sourceType, err)
 	}
 
 	return client, nil
 }
 
-// addStream은 새로운 RTSP 스트림을 추가합니다
-func (app *Application) addStream(streamID, rtspURL string) error {
-	// 스트림 생성
-	stream, err := app.streamManager.CreateStream(streamID, streamID)
-	if err != nil {
-		return fmt.Errorf("failed to create stream: %w", err)
-	}
-
-	// RTSP 클라이언트 생성 및 시작
-	client, err := app.createRTSPClient(streamID, rtspURL, "tcp", stream)
-	if err != nil {
-		return err
+// writePacketToHLS는 RTP 패킷을 HLS Manager에 전달합니다 (HLS가 활성화된 경우)
+func (app *Application) writePacketToHLS(streamID string, pkt *rtp.Packet) {
+	if hlsManager := app.currentHLS(); hlsManager != nil && hlsManager.IsEnabled() {
+		if err := hlsManager.WritePacket(streamID, pkt); err != nil {
+			// HLS 패킷 쓰기 실패는 로그만 남기고 계속 진행
+			logger.Debug("Failed to write packet to HLS",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+		}
 	}
-
-	// map에 저장
-	app.rtspClients[streamID] = client
-
-	return nil
 }
 
-// cleanup은 애플리케이션 리소스를 정리합니다
-func (app *Application) cleanup() {
-	logger.Info("Cleaning up application resources")
+// startStreamOutputs는 소스 연결(RTSP 클라이언트 연결, RTMP/SRT 송출 시작) 시 스냅샷, 녹화와 HLS Muxer를 준비합니다
+func (app *Application) startStreamOutputs(streamID string, stream *core.Stream) {
+	// 스냅샷용 키프레임 보관 (MJPEG 포함)
+	if app.snapshotManager != nil {
+		if err := app.snapshotManager.Attach(stream); err != nil {
+			logger.Error("Failed to start snapshot cache",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+		}
+	}
 
-	// 1. Context 취소 (ProcessManager 모니터 종료)
-	if app.cancelFunc != nil {
-		app.cancelFunc()
-		logger.Info("Context cancelled")
+	// 녹화와 HLS는 H.264/H.265만 지원 (MJPEG 소스는 RTSP 재전송만 가능)
+	if codec := stream.GetVideoCodec(); codec != "" && codec != "H264" && codec != "H265" {
+		logger.Info("Recording and HLS are not available for codec",
+			zap.String("stream_id", streamID),
+			zap.String("codec", codec),
+		)
+		return
 	}
 
-	// 2. Database 종료
-	if app.db != nil {
-		if err := app.db.Close(); err != nil {
-			logger.Error("Failed to close database", zap.Error(err))
-		} else {
-			logger.Info("Database closed")
+	// 녹화 시작 (녹화가 활성화된 경우, 재연결 시에는 기존 녹화 유지)
+	if app.recorderManager.IsEnabled() {
+		if err := app.recorderManager.Start(stream); err != nil {
+			logger.Error("Failed to start recording",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
 		}
 	}
 
-	// 2.5. CCTV Manager 중지 - AIOT API 관련 (향후 재사용을 위해 주석 처리)
-	// if app.cctvManager != nil {
-	// 	app.cctvManager.Stop()
-	// 	logger.Info("CCTV manager stopped")
-	// }
-
-	// 3. 모든 외부 프로세스 중지
-	if app.processManager != nil {
-		app.processManager.StopAll()
-		logger.Info("All external processes stopped")
+	// 이벤트 녹화용 pre-roll 보관 (상시 녹화 중이면 이벤트 구간도 이미 녹화됨)
+	if app.eventRecordingEnabled() && !app.recorderManager.IsEnabled() {
+		if err := app.recorderManager.StartEventBuffer(stream); err != nil {
+			logger.Error("Failed to start event recording buffer",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+		}
 	}
 
-	// 3. RTSP 클라이언트 중지
-	for streamID, client := range app.rtspClients {
-		logger.Info("Stopping RTSP client", zap.String("stream_id", streamID))
-		client.Stop()
+	// HLS Muxer 생성 (HLS가 활성화된 경우)
+	if hlsManager := app.currentHLS(); hlsManager != nil && hlsManager.IsEnabled() {
+		app.createHLSMuxer(hlsManager, streamID, stream)
 	}
+}
 
-	// 3.5. HLS Manager 중지
-	if app.hlsManager != nil {
-		app.hlsManager.StopAll()
-		logger.Info("HLS manager stopped")
+// createHLSMuxer는 스트림의 HLS Muxer를 생성합니다
+func (app *Application) createHLSMuxer(hlsManager *hls.Manager, streamID string, stream *core.Stream) {
+	// 스트림에서 실제 코덱 가져오기
+	codec := stream.GetVideoCodec()
+	if codec == "" {
+		codec = "H264" // 기본값
 	}
 
-	// 4. RTSP 서버 종료
-	if app.rtspServer != nil {
-		app.rtspServer.Stop()
-		logger.Info("RTSP server stopped")
+	// SPS/PPS는 RTP 패킷에서 동적 감지
+	if _, err := hlsManager.CreateMuxer(streamID, codec, nil, nil, nil); err != nil {
+		logger.Error("Failed to create HLS muxer",
+			zap.String("stream_id", streamID),
+			zap.String("codec", codec),
+			zap.Error(err),
+		)
+	} else {
+		logger.Info("HLS muxer created",
+			zap.String("stream_id", streamID),
+			zap.String("codec", codec),
+		)
 	}
+}
 
-	// 5. API 서버 종료
-	if app.apiServer != nil {
-		app.apiServer.Stop()
+// currentHLS는 현재 HLS 관리자를 반환합니다 (비활성화면 nil)
+func (app *Application) currentHLS() *hls.Manager {
+	return app.hlsManager.Load()
+}
+
+// newHLSManager는 HLS 관리자를 생성합니다 (비활성화면 nil)
+func newHLSManager(config core.HLSConfig) *hls.Manager {
+	if !config.Enabled {
+		return nil
 	}
 
-	// 6. 시그널링 서버 종료
-	if app.signalingServer != nil {
-		app.signalingServer.Close()
+	manager := hls.NewManager(hls.Config{
+		Enabled:           config.Enabled,
+		SegmentDuration:   config.SegmentDuration,
+		SegmentCount:      config.SegmentCount,
+		OutputDir:         config.OutputDir,
+		CleanupThreshold:  config.CleanupThreshold,
+		EnableCompression: config.EnableCompression,
+	}, logger.Log)
+	logger.Info("HLS manager initialized",
+		zap.String("output_dir", config.OutputDir),
+		zap.Int("segment_duration", config.SegmentDuration),
+		zap.Int("segment_count", config.SegmentCount),
+	)
+	return manager
+}
+
+// iceServers는 config.yaml의 ICE 서버 목록을 WebRTC 설정으로 변환합니다
+func iceServers(servers []core.ICEServerConfig) []webrtc.ICEServer {
+	result := make([]webrtc.ICEServer, 0, len(servers))
+	for _, server := range servers {
+		result = append(result, webrtc.ICEServer{URLs: server.URLs})
 	}
+	return result
+}
 
-	// 7. WebRTC 관리자 종료
-	if app.webrtcManager != nil {
-		app.webrtcManager.Close()
+// addStream은 새로운 스트림을 추가합니다
+func (app *Application) addStream(streamID, sourceURL string) error {
+	// 스트림 생성
+	stream, err := app.streamManager.CreateStream(streamID, streamID)
+	if err != nil {
+		return fmt.Errorf("failed to create stream: %w", err)
 	}
 
-	// 8. 스트림 관리자 종료
-	if app.streamManager != nil {
-		app.streamManager.Close()
+	// 소스 생성 및 시작
+	client, err := app.createSource(streamID, core.PathConfig{Source: sourceURL}, stream)
+	if err != nil {
+		return err
 	}
 
-	logger.Info("Cleanup completed")
+	// map에 저장
+	app.setSource(streamID, client)
+
+	return nil
 }
 
 // handleWebRTCOffer는 클라이언트의 WebRTC Offer를 처리합니다
@@ -721,18 +1202,84 @@ func (app *Application) handleWebRTCOffer(offer string, streamID string, client
 		zap.String("stream_id", streamID),
 	)
 
-	// 요청한 스트림 가져오기
+	// 스트림 시청 권한 확인
+	if !client.GetIdentity().CanView(streamID) {
+		return "", fmt.Errorf("access to stream %s is not allowed", streamID)
+	}
+
+	// 파생 스트림(CAM1~h264_720p)은 트랜스코더를 시작한 후 구독
+	if _, _, derived := core.SplitDerivedStreamID(streamID); derived {
+		if app.transcodeManager == nil {
+			return "", fmt.Errorf("transcoding is not enabled")
+		}
+		stream, err := app.transcodeManager.Start(streamID)
+		if err != nil {
+			logger.Error("Failed to start transcoder",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+			return "", fmt.Errorf("failed to start transcoder: %w", err)
+		}
+		return app.subscribePeer(offer, streamID, stream, client)
+	}
+
+	// 요청한 스트림 가져오기 (실행 중이 아니면 온디맨드로 시작)
+	stream, err := app.readyStream(streamID)
+	if err != nil {
+		return "", err
+	}
+
+	// 브라우저가 원본 코덱을 재생할 수 없으면 트랜스코딩된 파생 스트림으로 전환
+	// (예: HEVC 미지원 브라우저에서 H.265 카메라 → CAM1~h264_720p)
+	if codec := stream.GetVideoCodec(); codec != "" && app.transcodeManager != nil && !webrtc.SupportsCodec(offer, codec) {
+		supports := func(codec string) bool { return webrtc.SupportsCodec(offer, codec) }
+		if derivedID, ok := app.transcodeManager.Fallback(streamID, supports); ok {
+			derived, err := app.transcodeManager.Start(derivedID)
+			if err != nil {
+				logger.Warn("Failed to start transcoder, using source stream",
+					zap.String("stream_id", derivedID),
+					zap.Error(err),
+				)
+			} else {
+				logger.Info("Client does not support stream codec, using transcoded stream",
+					zap.String("stream_id", streamID),
+					zap.String("codec", codec),
+					zap.String("transcoded_stream_id", derivedID),
+				)
+				return app.subscribePeer(offer, derivedID, derived, client)
+			}
+		}
+	}
+
+	return app.subscribePeer(offer, streamID, stream, client)
+}
+
+// readyStream은 스트림을 반환합니다
+// 스트림이 실행 중이 아니면 온디맨드로 시작하고 코덱이 설정될 때까지 기다립니다
+func (app *Application) readyStream(streamID string) (*core.Stream, error) {
 	stream, err := app.streamManager.GetStream(streamID)
 	if err != nil {
 		logger.Error("Failed to get stream",
 			zap.String("stream_id", streamID),
 			zap.Error(err),
 		)
-		return "", fmt.Errorf("stream not found: %w", err)
+		return nil, fmt.Errorf("stream not found: %w", err)
+	}
+
+	// runOnDemand 경로는 명령을 시작하고 송출될 때까지 대기
+	if app.hooksManager.HasDemand(streamID) {
+		if err := app.hooksManager.Demand(streamID, ""); err != nil {
+			logger.Error("Failed to start runOnDemand stream",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("failed to start stream: %w", err)
+		}
+		return stream, nil
 	}
 
 	// 스트림이 실행 중이 아니면 온디맨드로 시작 시도
-	if _, isRunning := app.rtspClients[streamID]; !isRunning {
+	if _, isRunning := app.getSource(streamID); !isRunning {
 		logger.Info("Stream not running, attempting to start on-demand",
 			zap.String("stream_id", streamID),
 		)
@@ -742,12 +1289,12 @@ func (app *Application) handleWebRTCOffer(offer string, streamID string, client
 				zap.String("stream_id", streamID),
 				zap.Error(err),
 			)
-			return "", fmt.Errorf("failed to start stream: %w", err)
+			return nil, fmt.Errorf("failed to start stream: %w", err)
 		}
 
 		// 스트림이 준비될 때까지 대기 (코덱이 설정되면 준비 완료)
 		// 폴링 방식으로 변경하여 불필요한 대기 시간 최소화
-		maxWaitTime := time.Duration(app.config.RTSP.Client.OnDemandWaitSec) * time.Second
+		maxWaitTime := time.Duration(app.currentConfig().RTSP.Client.OnDemandWaitSec) * time.Second
 		pollInterval := 100 * time.Millisecond
 		elapsed := time.Duration(0)
 
@@ -774,6 +1321,16 @@ func (app *Application) handleWebRTCOffer(offer string, streamID string, client
 		}
 	}
 
+	return stream, nil
+}
+
+// subscribePeer는 WebRTC 피어를 만들어 스트림 구독자로 등록하고 Answer를 반환합니다
+func (app *Application) subscribePeer(offer, streamID string, stream *core.Stream, client *signaling.Client) (string, error) {
+	// WebRTC는 H.264/H.265만 전송 가능
+	if codec := stream.GetVideoCodec(); codec != "" && codec != "H264" && codec != "H265" {
+		return "", fmt.Errorf("stream codec %s is not supported by WebRTC, use RTSP", codec)
+	}
+
 	// WebRTC 피어 생성
 	peer, err := app.webrtcManager.CreatePeer(streamID)
 	if err != nil {
@@ -808,6 +1365,8 @@ func (app *Application) handleWebRTCOffer(offer string, streamID string, client
 	app.peerStreams[peer.GetID()] = streamID
 	app.peerMutex.Unlock()
 
+	app.startPeerAudit(peer, streamID, audit.ProtocolWebRTC, client)
+
 	logger.Info("Peer subscribed to stream",
 		zap.String("peer_id", peer.GetID()),
 		zap.String("stream_id", streamID),
@@ -817,8 +1376,179 @@ func (app *Application) handleWebRTCOffer(offer string, streamID string, client
 	return answer, nil
 }
 
+// streamHooks는 스트림 상태 변화 콜백을 만듭니다 (경로 훅, 실시간 상태, 웹훅 알림)
+func (app *Application) streamHooks() core.StreamHooks {
+	list := []core.StreamHooks{app.hooksManager.StreamHooks(), app.statusManager.StreamHooks()}
+	if app.webhookManager != nil {
+		list = append(list, app.webhookManager.StreamHooks())
+	}
+	return core.CombineStreamHooks(list...)
+}
+
+// segmentCompleted는 녹화 세그먼트 파일이 완료되면 웹훅으로 알립니다
+func (app *Application) segmentCompleted(segment recorder.CompletedSegment) {
+	if app.webhookManager == nil {
+		return
+	}
+	app.webhookManager.Publish(webhook.EventRecordingSegmentComplete, webhook.SegmentData{
+		StreamID: segment.StreamID,
+		Path:     segment.Path,
+		Start:    segment.Start.UTC(),
+		Duration: segment.Duration.Seconds(),
+		Size:     segment.Size,
+	})
+}
+
+// eventRecordingEnabled는 이벤트 녹화(이벤트 전후 구간만 녹화) 사용 여부를 반환합니다
+func (app *Application) eventRecordingEnabled() bool {
+	config := app.currentConfig()
+	return config.Events.Enabled && config.Events.Record.Enabled
+}
+
+// initEvents는 이벤트 저장소와 관리자를 초기화합니다 (수집은 시그널링/API 서버 시작 후)
+func (app *Application) initEvents() error {
+	config := app.currentConfig()
+
+	for _, t := range config.Events.Record.Types {
+		if !events.IsValidType(t) {
+			return fmt.Errorf("invalid events.record.types entry '%s' (supported: %s)",
+				t, strings.Join(events.Types, ", "))
+		}
+	}
+
+	eventConfig := events.Config{
+		Retention:      time.Duration(config.Events.RetentionDays) * 24 * time.Hour,
+		RecordTypes:    config.Events.Record.Types,
+		RequestTimeout: time.Duration(config.ONVIF.RequestTimeout) * time.Second,
+		OnEvent: func(event *database.Event) {
+			app.signalingServer.BroadcastEvent(event.StreamID, event)
+		},
+	}
+	if config.Events.ONVIF && config.ONVIF.Enabled {
+		eventConfig.ONVIFTargets = app.onvifEventTargets
+	}
+	if app.eventRecordingEnabled() {
+		eventConfig.OnTrigger = app.recorderManager.TriggerEvent
+	}
+
+	app.eventRepo = database.NewEventRepository(app.db, logger.Log)
+	app.eventManager = events.NewManager(eventConfig, app.eventRepo, logger.Log)
+
+	logger.Info("Event manager initialized",
+		zap.Bool("onvif", eventConfig.ONVIFTargets != nil),
+		zap.Bool("record", app.eventRecordingEnabled()),
+		zap.Int("retention_days", config.Events.RetentionDays),
+	)
+	return nil
+}
+
+// ensureStream은 RTSP DESCRIBE 전에 runOnDemand 명령이나 파생 스트림의 트랜스코더를 시작합니다
+func (app *Application) ensureStream(streamID, query string) error {
+	if app.hooksManager.HasDemand(streamID) {
+		return app.hooksManager.Demand(streamID, query)
+	}
+	return app.ensureTranscodedStream(streamID)
+}
+
+// onvifEventTargets는 ONVIF로 가져온 스트림(장치 주소가 저장된 스트림)을 이벤트 구독 대상으로 반환합니다
+func (app *Application) onvifEventTargets() ([]events.ONVIFTarget, error) {
+	dbStreams, err := app.streamRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]events.ONVIFTarget, 0, len(dbStreams))
+	for _, dbStream := range dbStreams {
+		if dbStream.ONVIFAddress == "" {
+			continue
+		}
+
+		// 카메라 인증 정보는 소스 URL과 동일하게 사용
+		_, username, password, err := secret.SplitURLCredentials(dbStream.Source)
+		if err != nil {
+			logger.Warn("Invalid stream source, skipping ONVIF events",
+				zap.String("stream_id", dbStream.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		targets = append(targets, events.ONVIFTarget{
+			StreamID: dbStream.ID,
+			Address:  dbStream.ONVIFAddress,
+			Username: username,
+			Password: password,
+		})
+	}
+
+	return targets, nil
+}
+
+// peerAudit는 WebRTC 피어의 시청 감사 세션입니다
+type peerAudit struct {
+	session *audit.Session
+	peer    *webrtc.Peer
+}
+
+// startPeerAudit는 WebRTC 피어의 시청 시작을 감사 로그에 기록합니다
+func (app *Application) startPeerAudit(peer *webrtc.Peer, streamID, protocol string, client *signaling.Client) {
+	if app.auditLogger == nil {
+		return
+	}
+
+	actor := audit.ActorFromIdentity(client.GetIdentity(), client.GetRemoteAddr())
+	session := app.auditLogger.StartSession(actor, streamID, protocol)
+
+	app.peerMutex.Lock()
+	app.peerAudit[peer.GetID()] = &peerAudit{session: session, peer: peer}
+	app.peerMutex.Unlock()
+}
+
+// endPeerAudit는 WebRTC 피어의 시청 종료를 전송 바이트와 함께 기록합니다
+func (app *Application) endPeerAudit(peerID string) {
+	app.peerMutex.Lock()
+	pa, exists := app.peerAudit[peerID]
+	if exists {
+		delete(app.peerAudit, peerID)
+	}
+	app.peerMutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	_, bytesSent := pa.peer.GetStats()
+	pa.session.End(bytesSent)
+}
+
+// endAllPeerAudits는 모든 WebRTC 시청 세션의 종료를 기록합니다 (종료 시)
+func (app *Application) endAllPeerAudits() {
+	app.peerMutex.RLock()
+	peerIDs := make([]string, 0, len(app.peerAudit))
+	for peerID := range app.peerAudit {
+		peerIDs = append(peerIDs, peerID)
+	}
+	app.peerMutex.RUnlock()
+
+	for _, peerID := range peerIDs {
+		app.endPeerAudit(peerID)
+	}
+}
+
 // cleanupPeer는 피어와 관련된 리소스를 정리합니다
 func (app *Application) cleanupPeer(peerID string) {
+	app.endPeerAudit(peerID)
+
+	// 녹화 재생 피어인 경우 재생기 종료
+	app.closePlaybackSessionsWhere(func(session *playbackSession) bool {
+		return session.peer.GetID() == peerID
+	})
+
+	// 스트림 그룹 피어인 경우 그룹 세션 종료
+	app.closeGroupSessionsWhere(func(session *groupSession) bool {
+		return session.peer.GetID() == peerID
+	})
+
 	app.peerMutex.Lock()
 	streamID, exists := app.peerStreams[peerID]
 	if exists {
@@ -855,9 +1585,15 @@ func (app *Application) cleanupPeer(peerID string) {
 	}
 }
 
-// cleanupClientPeers는 클라이언트와 관련된 피어들을 정리합니다 (현재 사용되지 않음)
+// cleanupClientPeers는 클라이언트와 관련된 피어들을 정리합니다
+// 라이브 피어는 WebRTC 연결 상태 변화에서 자동으로 정리되며, 녹화 재생 세션과 스트림 그룹 세션만 여기서 종료합니다
 func (app *Application) cleanupClientPeers(clientID string) {
-	// 현재는 WebRTC 피어 연결 상태 변화에서 자동으로 정리됨
+	app.closePlaybackSessionsWhere(func(session *playbackSession) bool {
+		return session.clientID == clientID
+	})
+	app.closeGroupSessionsWhere(func(session *groupSession) bool {
+		return session.clientID == clientID
+	})
 }
 
 // loadStreamsFromCCTV는 CCTV 매니저에서 스트림을 로드합니다
@@ -899,9 +1635,9 @@ func (app *Application) cleanupClientPeers(clientID string) {
 
 // startOnDemandStream은 온디맨드 스트림을 시작합니다
 func (app *Application) startOnDemandStream(streamID string) error {
-	// 이미 RTSP 클라이언트가 실행 중인지 확인
-	if _, exists := app.rtspClients[streamID]; exists {
-		logger.Info("RTSP stream already running", zap.String("stream_id", streamID))
+	// 이미 소스가 실행 중인지 확인
+	if _, exists := app.getSource(streamID); exists {
+		logger.Info("Stream source already running", zap.String("stream_id", streamID))
 		return nil
 	}
 
@@ -915,8 +1651,8 @@ func (app *Application) startOnDemandStream(streamID string) error {
 	var pathConfig core.PathConfig
 	var found bool
 
-	if app.config.Paths != nil {
-		if cfg, exists := app.config.Paths[streamID]; exists {
+	if paths := app.currentConfig().Paths; paths != nil {
+		if cfg, exists := paths[streamID]; exists {
 			pathConfig = cfg
 			found = true
 			logger.Debug("Stream config found in config.yaml", zap.String("stream_id", streamID))
@@ -929,25 +1665,20 @@ func (app *Application) startOnDemandStream(streamID string) error {
 		if err != nil {
 			return fmt.Errorf("stream config not found in config or database: %w", err)
 		}
-
-		pathConfig = core.PathConfig{
-			Source:         dbStream.Source,
-			SourceOnDemand: dbStream.SourceOnDemand,
-			RTSPTransport:  dbStream.RTSPTransport,
+		if dbStream.CredentialsError && dbStream.Source == "" {
+			return fmt.Errorf("stream %s credentials cannot be decrypted", streamID)
 		}
+
+		pathConfig = streamPathConfig(dbStream)
 		logger.Debug("Stream config found in database", zap.String("stream_id", streamID))
 	}
 
-	// RTSP 클라이언트 생성 및 시작
-	client, err := app.createRTSPClient(streamID, pathConfig.Source, pathConfig.RTSPTransport, stream)
-	if err != nil {
+	// 소스 생성, 시작 및 등록
+	if err := app.launchSource(streamID, pathConfig, stream); err != nil {
 		return err
 	}
 
-	// map에 저장
-	app.rtspClients[streamID] = client
-
-	logger.Info("On-demand RTSP stream started",
+	logger.Info("On-demand stream source started",
 		zap.String("stream_id", streamID),
 		zap.String("url", maskRTSPURL(pathConfig.Source)),
 	)
@@ -1007,8 +1738,8 @@ func parseDuration(s string) (time.Duration, error) {
 // stopStream은 스트림을 중지합니다
 func (app *Application) stopStream(streamID string) error {
 	// HLS muxer 제거 (있으면)
-	if app.hlsManager != nil {
-		if err := app.hlsManager.RemoveMuxer(streamID); err != nil {
+	if hlsManager := app.currentHLS(); hlsManager != nil {
+		if err := hlsManager.RemoveMuxer(streamID); err != nil {
 			logger.Debug("No HLS muxer to remove (or already removed)",
 				zap.String("stream_id", streamID),
 				zap.Error(err),
@@ -1018,7 +1749,32 @@ func (app *Application) stopStream(streamID string) error {
 		}
 	}
 
-	// runOnDemand 프로세스 중지 시도
+	// 녹화 중지 (있으면)
+	if app.recorderManager.IsRecording(streamID) {
+		if stream, err := app.streamManager.GetStream(streamID); err == nil {
+			if err := app.recorderManager.Stop(stream); err != nil {
+				logger.Debug("Failed to stop recording",
+					zap.String("stream_id", streamID),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	// 이벤트 녹화와 스냅샷 키프레임 보관 중지 (진행 중인 이벤트 녹화는 마무리, 마지막 스냅샷은 유지)
+	if stream, err := app.streamManager.GetStream(streamID); err == nil {
+		app.recorderManager.StopEventBuffer(stream)
+		if app.snapshotManager != nil {
+			app.snapshotManager.Detach(stream)
+		}
+	}
+
+	// 원본을 읽는 트랜스코더 중지
+	if app.transcodeManager != nil {
+		app.transcodeManager.StopSource(streamID)
+	}
+
+	// runOnDemand 프로세스 중지 시도 (송출 세션 종료 시 준비 해제됨)
 	if app.processManager.IsRunning(streamID) {
 		logger.Info("Stopping runOnDemand process", zap.String("stream_id", streamID))
 		if err := app.processManager.Stop(streamID); err != nil {
@@ -1027,12 +1783,13 @@ func (app *Application) stopStream(streamID string) error {
 		return nil
 	}
 
-	// RTSP 클라이언트 중지 시도
-	client, exists := app.rtspClients[streamID]
-	if exists {
-		logger.Info("Stopping RTSP stream", zap.String("stream_id", streamID))
+	// 스트림 소스 중지 시도
+	if client, exists := app.removeSource(streamID); exists {
+		logger.Info("Stopping stream source", zap.String("stream_id", streamID))
 		client.Stop()
-		delete(app.rtspClients, streamID)
+		if stream, err := app.streamManager.GetStream(streamID); err == nil {
+			stream.SetNotReady()
+		}
 		return nil
 	}
 
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/playback"
+	"github.com/yourusername/cctv3/internal/signaling"
+	"github.com/yourusername/cctv3/internal/webrtc"
+	"github.com/yourusername/cctv3/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// playbackSession은 WebRTC로 녹화 영상을 재생하는 세션입니다
+type playbackSession struct {
+	clientID string
+	streamID string
+	peer     *webrtc.Peer
+	player   *playback.Player
+}
+
+// handlePlayback은 시그널링의 녹화 재생 요청(play/seek/pause/rate)을 처리합니다
+func (app *Application) handlePlayback(req signaling.PlaybackRequest, client *signaling.Client) (string, error) {
+	key := client.GetID() + "/" + req.StreamID
+
+	// 녹화 시청 권한 확인
+	if !client.GetIdentity().CanView(req.StreamID) {
+		return "", fmt.Errorf("access to stream %s is not allowed", req.StreamID)
+	}
+
+	// 새 재생 세션 (Offer 포함)
+	if req.Type == "play" && req.SDP != "" {
+		return app.startPlaybackSession(key, req, client)
+	}
+
+	app.playbackMutex.Lock()
+	session, exists := app.playbackSessions[key]
+	app.playbackMutex.Unlock()
+
+	if !exists {
+		return "", fmt.Errorf("no playback session for stream %s", req.StreamID)
+	}
+
+	var state playback.PlayerState
+	switch req.Type {
+	case "play":
+		// 재개 (시각/속도가 있으면 함께 적용)
+		if !req.Time.IsZero() {
+			session.player.Seek(req.Time)
+		}
+		if req.Rate > 0 {
+			if _, err := session.player.SetRate(req.Rate); err != nil {
+				return "", err
+			}
+		}
+		state = session.player.Resume()
+	case "seek":
+		state = session.player.Seek(req.Time)
+	case "pause":
+		state = session.player.Pause()
+	case "rate":
+		var err error
+		if state, err = session.player.SetRate(req.Rate); err != nil {
+			return "", err
+		}
+	}
+
+	client.SendPlaybackState(state, req.StreamID)
+	return "", nil
+}
+
+// startPlaybackSession은 WebRTC 피어와 녹화 재생기를 생성합니다
+func (app *Application) startPlaybackSession(key string, req signaling.PlaybackRequest, client *signaling.Client) (string, error) {
+	if req.Time.IsZero() {
+		return "", fmt.Errorf("play requires start time")
+	}
+
+	// 같은 클라이언트의 기존 재생 세션은 교체
+	app.closePlaybackSession(key)
+
+	peer, err := app.webrtcManager.CreatePeer(req.StreamID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create peer: %w", err)
+	}
+
+	streamID := req.StreamID
+	player, err := app.playbackManager.NewPlayer(playback.PlayerConfig{
+		StreamID: streamID,
+		Start:    req.Time,
+		Rate:     req.Rate,
+		Sink:     peer,
+		Ready:    peer.Connected(),
+		OnEnd: func(state playback.PlayerState) {
+			client.SendPlaybackState(state, streamID)
+		},
+		Logger: logger.Log,
+	})
+	if err != nil {
+		app.webrtcManager.RemovePeer(peer.GetID())
+		return "", fmt.Errorf("failed to start playback: %w", err)
+	}
+
+	answer, err := peer.CreateOffer(req.SDP, player.Codec())
+	if err != nil {
+		player.Close()
+		app.webrtcManager.RemovePeer(peer.GetID())
+		return "", fmt.Errorf("failed to create answer: %w", err)
+	}
+
+	app.playbackMutex.Lock()
+	app.playbackSessions[key] = &playbackSession{
+		clientID: client.GetID(),
+		streamID: streamID,
+		peer:     peer,
+		player:   player,
+	}
+	app.playbackMutex.Unlock()
+
+	app.startPeerAudit(peer, streamID, audit.ProtocolWebRTCPlayback, client)
+
+	logger.Info("Playback session started",
+		zap.String("client_id", client.GetID()),
+		zap.String("stream_id", streamID),
+		zap.String("peer_id", peer.GetID()),
+		zap.Time("start", req.Time),
+	)
+
+	// 재생기는 ICE 연결 후 전송 시작 (첫 키프레임 유실 방지)
+	player.Start()
+
+	go func() {
+		if err := peer.WaitConnected(10 * time.Second); err != nil {
+			logger.Warn("Playback peer not connected",
+				zap.String("peer_id", peer.GetID()),
+				zap.Error(err),
+			)
+			app.closePlaybackSession(key)
+			return
+		}
+		client.SendPlaybackState(player.State(), streamID)
+	}()
+
+	return answer, nil
+}
+
+// closePlaybackSession은 재생 세션을 종료합니다
+func (app *Application) closePlaybackSession(key string) {
+	app.playbackMutex.Lock()
+	session, exists := app.playbackSessions[key]
+	if exists {
+		delete(app.playbackSessions, key)
+	}
+	app.playbackMutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	session.player.Close()
+	session.peer.Close()
+
+	logger.Info("Playback session closed",
+		zap.String("client_id", session.clientID),
+		zap.String("stream_id", session.streamID),
+	)
+}
+
+// closePlaybackSessionsWhere는 조건에 맞는 재생 세션들을 종료합니다
+func (app *Application) closePlaybackSessionsWhere(match func(session *playbackSession) bool) {
+	app.playbackMutex.Lock()
+	keys := make([]string, 0)
+	for key, session := range app.playbackSessions {
+		if match(session) {
+			keys = append(keys, key)
+		}
+	}
+	app.playbackMutex.Unlock()
+
+	for _, key := range keys {
+		app.closePlaybackSession(key)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/onvif"
+	"github.com/yourusername/cctv3/internal/secret"
+	"github.com/yourusername/cctv3/internal/signaling"
+)
+
+// controlPTZ는 스트림의 카메라에 ONVIF PTZ 명령을 보냅니다
+// 가져오기로 등록된 스트림은 저장된 장치 주소/프로필을, 그 외에는 소스 호스트의 80 포트를 사용합니다
+func (app *Application) controlPTZ(ctx context.Context, streamID string, cmd onvif.PTZCommand) (*onvif.PTZResult, error) {
+	if app.onvifManager == nil {
+		return nil, fmt.Errorf("ONVIF is not enabled")
+	}
+
+	dbStream, err := app.streamRepo.Get(streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 카메라 인증 정보는 소스 URL과 동일하게 사용
+	_, username, password, err := secret.SplitURLCredentials(dbStream.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	address := dbStream.ONVIFAddress
+	if address == "" {
+		if address, err = onvif.AddressFromSource(dbStream.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	return app.onvifManager.PTZ(ctx, onvif.PTZTarget{
+		Address:  address,
+		Username: username,
+		Password: password,
+		Profile:  dbStream.ONVIFProfile,
+	}, cmd)
+}
+
+// handlePTZMessage는 시그널링의 PTZ 제어 메시지를 처리합니다
+func (app *Application) handlePTZMessage(streamID string, payload json.RawMessage, client *signaling.Client) (*onvif.PTZResult, error) {
+	identity := client.GetIdentity()
+	if !identity.HasRole(auth.Role(app.currentConfig().ONVIF.PTZRole)) || !identity.CanView(streamID) {
+		return nil, fmt.Errorf("PTZ control of stream %s is not allowed", streamID)
+	}
+
+	var cmd onvif.PTZCommand
+	if err := json.Unmarshal(payload, &cmd); err != nil {
+		return nil, fmt.Errorf("invalid PTZ command: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.currentConfig().ONVIF.RequestTimeout)*time.Second)
+	defer cancel()
+
+	result, err := app.controlPTZ(ctx, streamID, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.auditLogger != nil && cmd.Action != onvif.PTZPresets {
+		actor := audit.ActorFromIdentity(identity, client.GetRemoteAddr())
+		app.auditLogger.Record(actor, audit.ActionPTZ, streamID, cmd.String())
+	}
+
+	return result, nil
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/confwatcher"
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// currentConfig는 현재 적용 중인 설정을 반환합니다
+func (app *Application) currentConfig() *core.Config {
+	return app.config.Load()
+}
+
+// startConfigWatcher는 설정 파일이 바뀌면 자동으로 다시 로드하도록 감시를 시작합니다
+func (app *Application) startConfigWatcher() error {
+	app.configWatcher = confwatcher.New(confwatcher.Config{
+		Path:     app.configPath,
+		Interval: time.Duration(app.currentConfig().Server.ConfigWatchInterval) * time.Second,
+		OnChange: func() {
+			if _, err := app.reloadConfig(); err != nil {
+				logger.Error("Failed to reload config", zap.Error(err))
+			}
+		},
+		Logger: logger.Log,
+	})
+	return app.configWatcher.Start()
+}
+
+// reloadConfig는 config.yaml을 다시 읽어 변경된 항목만 재시작 없이 적용합니다
+// 재시작이 필요한 항목이 바뀌었으면 아무것도 적용하지 않고 *core.RestartRequiredError를 반환합니다
+func (app *Application) reloadConfig() (*core.ReloadResult, error) {
+	app.reloadMutex.Lock()
+	defer app.reloadMutex.Unlock()
+
+	newConfig, err := core.LoadConfig(app.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	oldConfig := app.currentConfig()
+	result := &core.ReloadResult{
+		Changed:        core.DiffConfig(oldConfig, newConfig),
+		StreamsAdded:   []string{},
+		StreamsUpdated: []string{},
+		StreamsRemoved: []string{},
+	}
+	if len(result.Changed) == 0 {
+		logger.Info("Config reloaded without changes")
+		return result, nil
+	}
+
+	if fields := core.RestartRequired(result.Changed); len(fields) > 0 {
+		logger.Warn("Config reload rejected, changes require restart",
+			zap.Strings("fields", fields),
+		)
+		return nil, &core.RestartRequiredError{Fields: fields}
+	}
+
+	app.config.Store(newConfig)
+
+	changed := func(prefix string) bool {
+		for _, field := range result.Changed {
+			if field == prefix || strings.HasPrefix(field, prefix+".") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// 로그 레벨 (이미 생성된 컴포넌트 로거에도 적용)
+	if changed("logging.level") {
+		if err := logger.SetLevel(newConfig.Logging.Level); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("logging.level: %v", err))
+		}
+	}
+
+	// WebRTC (연결된 피어는 유지, 이후 연결부터 적용)
+	if changed("webrtc.settings.max_peers") {
+		app.webrtcManager.SetMaxPeers(newConfig.WebRTC.Settings.MaxPeers)
+	}
+	if changed("webrtc.ice_servers") {
+		app.webrtcManager.SetICEServers(iceServers(newConfig.WebRTC.ICEServers))
+	}
+
+	// HLS (muxer를 새 설정으로 다시 생성)
+	if changed("hls") {
+		app.reloadHLS(newConfig.HLS)
+	}
+
+	// rtsp.client는 이후 시작되는 소스부터 적용 (경로 변경으로 다시 시작되는 소스 포함)
+
+	// 스트림 추가/변경/삭제
+	if changed("paths") {
+		app.reloadPaths(oldConfig.Paths, newConfig.Paths, result)
+	}
+
+	logger.Info("Config reloaded",
+		zap.Strings("changed", result.Changed),
+		zap.Strings("streams_added", result.StreamsAdded),
+		zap.Strings("streams_updated", result.StreamsUpdated),
+		zap.Strings("streams_removed", result.StreamsRemoved),
+		zap.Strings("errors", result.Errors),
+	)
+
+	return result, nil
+}
+
+// reloadHLS는 HLS 관리자를 새 설정으로 교체하고 실행 중인 스트림의 muxer를 다시 생성합니다
+// 시청 중인 HLS 클라이언트는 플레이리스트를 다시 받아 새 세그먼트부터 재생합니다
+func (app *Application) reloadHLS(config core.HLSConfig) {
+	oldManager := app.currentHLS()
+
+	// 기존 muxer가 있던 스트림과 연결된 소스의 스트림
+	streamIDs := make(map[string]struct{})
+	if oldManager != nil {
+		for _, streamID := range oldManager.GetAllStreams() {
+			streamIDs[streamID] = struct{}{}
+		}
+	}
+	for streamID, connected := range app.sourceStates() {
+		if connected {
+			streamIDs[streamID] = struct{}{}
+		}
+	}
+
+	newManager := newHLSManager(config)
+	app.hlsManager.Store(newManager)
+	app.apiServer.SetHLSManager(newManager)
+	if app.metricsServer != nil {
+		app.metricsServer.SetHLSManager(newManager)
+	}
+
+	if oldManager != nil {
+		oldManager.StopAll()
+		logger.Info("Previous HLS manager stopped")
+	}
+
+	if newManager == nil {
+		logger.Info("HLS disabled by config reload")
+		return
+	}
+
+	for streamID := range streamIDs {
+		stream, err := app.streamManager.GetStream(streamID)
+		if err != nil {
+			continue
+		}
+		// 녹화와 HLS는 H.264/H.265만 지원 (startStreamOutputs와 동일)
+		if codec := stream.GetVideoCodec(); codec != "" && codec != "H264" && codec != "H265" {
+			continue
+		}
+		app.createHLSMuxer(newManager, streamID, stream)
+	}
+}
+
+// reloadPaths는 config.yaml paths 변경을 데이터베이스와 실행 중인 스트림에 반영합니다
+// 추가/변경된 경로는 DB에 저장하고 소스를 다시 시작하며, 삭제된 경로는 스트림을 삭제합니다 (API 스트림 삭제와 동일, config.yaml 출처만)
+// 변경되지 않은 경로의 스트림은 그대로 유지됩니다
+func (app *Application) reloadPaths(oldPaths, newPaths map[string]core.PathConfig, result *core.ReloadResult) {
+	ids := make([]string, 0, len(oldPaths)+len(newPaths))
+	for streamID := range oldPaths {
+		ids = append(ids, streamID)
+	}
+	for streamID := range newPaths {
+		if _, exists := oldPaths[streamID]; !exists {
+			ids = append(ids, streamID)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, streamID := range ids {
+		oldPath, existed := oldPaths[streamID]
+		newPath, exists := newPaths[streamID]
+
+		switch {
+		case !exists:
+			if app.removeConfigStream(streamID) {
+				result.StreamsRemoved = append(result.StreamsRemoved, streamID)
+			}
+
+		case !existed || !reflect.DeepEqual(oldPath, newPath):
+			if err := app.applyConfigStream(streamID, newPath); err != nil {
+				logger.Error("Failed to apply config path",
+					zap.String("stream_id", streamID),
+					zap.Error(err),
+				)
+				result.Errors = append(result.Errors, fmt.Sprintf("paths.%s: %v", streamID, err))
+				continue
+			}
+			if existed {
+				result.StreamsUpdated = append(result.StreamsUpdated, streamID)
+			} else {
+				result.StreamsAdded = append(result.StreamsAdded, streamID)
+			}
+		}
+	}
+}
+
+// applyConfigStream은 추가/변경된 경로를 DB에 저장하고 스트림 소스를 다시 시작합니다
+// 온디맨드 스트림은 실행 중이었을 때만 다시 시작합니다
+func (app *Application) applyConfigStream(streamID string, pathConfig core.PathConfig) error {
+	dbStream, err := app.streamRepo.Get(streamID)
+	if err == nil {
+		setPathSource(dbStream, pathConfig)
+		if err := app.streamRepo.Update(dbStream); err != nil {
+			return err
+		}
+		app.adoptConfigStream(dbStream)
+	} else {
+		dbStream = &database.Stream{
+			ID:     streamID,
+			Name:   streamID, // config.yaml은 Name이 없으므로 ID 사용
+			Origin: database.StreamOriginConfig,
+		}
+		setPathSource(dbStream, pathConfig)
+		if err := app.streamRepo.Create(dbStream); err != nil {
+			return err
+		}
+	}
+
+	if _, err := app.streamManager.GetStream(streamID); err != nil {
+		if _, err := app.streamManager.CreateStream(streamID, dbStream.Name); err != nil {
+			return err
+		}
+	}
+
+	_, running := app.getSource(streamID)
+	if running || app.processManager.IsRunning(streamID) {
+		running = true
+		if err := app.stopStream(streamID); err != nil {
+			logger.Debug("Failed to stop stream before restart",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// 변경된 훅 명령으로 다시 시작 (runOnDemand는 다음 시청 요청 시 시작)
+	app.hooksManager.ClosePath(streamID)
+	app.hooksManager.InitPath(streamID)
+
+	if pathConfig.RunOnDemand != "" || (pathConfig.SourceOnDemand && !running) {
+		logger.Info("Config stream applied (on-demand)", zap.String("stream_id", streamID))
+		return nil
+	}
+
+	return app.startSource(streamID, pathConfig)
+}
+
+// adoptConfigStream은 config.yaml 경로와 ID가 같은 스트림을 config.yaml 출처로 표시합니다
+// (경로가 삭제되면 재시작과 다시 로드 모두 이 스트림을 삭제)
+func (app *Application) adoptConfigStream(stream *database.Stream) {
+	if stream.Origin == database.StreamOriginConfig {
+		return
+	}
+	if err := app.streamRepo.SetOrigin(stream.ID, database.StreamOriginConfig); err != nil {
+		logger.Warn("Failed to mark stream as config stream",
+			zap.String("stream_id", stream.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	stream.Origin = database.StreamOriginConfig
+}
+
+// pruneConfigStreams는 시작 시 config.yaml에 없는 config.yaml 출처 스트림을 데이터베이스에서 삭제합니다
+// (API로 생성된 스트림은 유지)
+func (app *Application) pruneConfigStreams(paths map[string]core.PathConfig) error {
+	dbStreams, err := app.streamRepo.List()
+	if err != nil {
+		return fmt.Errorf("failed to load streams from database: %w", err)
+	}
+
+	for _, dbStream := range dbStreams {
+		if dbStream.Origin != database.StreamOriginConfig {
+			continue
+		}
+		if _, exists := paths[dbStream.ID]; exists {
+			continue
+		}
+
+		logger.Info("Removing stream deleted from config", zap.String("stream_id", dbStream.ID))
+		app.deleteConfigStreamRecord(dbStream.ID)
+	}
+
+	return nil
+}
+
+// removeConfigStream은 config.yaml에서 삭제된 경로의 스트림을 삭제합니다
+// API로 생성된 스트림(출처가 config.yaml이 아님)은 유지하고 false를 반환합니다
+func (app *Application) removeConfigStream(streamID string) bool {
+	// API로 이미 삭제되었으면 DB 정리는 건너뛰고 나머지 정리는 진행
+	if dbStream, err := app.streamRepo.Get(streamID); err == nil {
+		if dbStream.Origin != database.StreamOriginConfig {
+			logger.Warn("Path removed from config but stream was not created from config, keeping it",
+				zap.String("stream_id", streamID),
+			)
+			return false
+		}
+		logger.Info("Removing stream deleted from config", zap.String("stream_id", streamID))
+		app.deleteConfigStreamRecord(streamID)
+	}
+
+	if err := app.stopStream(streamID); err != nil {
+		logger.Debug("Stream was not running", zap.String("stream_id", streamID))
+	}
+	app.hooksManager.ClosePath(streamID)
+	if err := app.streamManager.RemoveStream(streamID); err != nil {
+		logger.Debug("Stream was not in manager",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+	}
+	return true
+}
+
+// deleteConfigStreamRecord는 스트림을 데이터베이스와 그룹에서 삭제합니다
+func (app *Application) deleteConfigStreamRecord(streamID string) {
+	if err := app.streamRepo.Delete(streamID); err != nil {
+		logger.Warn("Failed to delete config stream from database",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+	}
+	if err := app.groupRepo.RemoveStream(streamID); err != nil {
+		logger.Warn("Failed to remove stream from groups",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+	}
+}
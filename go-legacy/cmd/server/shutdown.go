@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/cctv3/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// 종료 코드 (1은 시작 실패)
+const (
+	exitDrainTimeout = 2 // 종료 대기 시간 안에 정리를 마치지 못함
+	exitForced       = 3 // 두 번째 시그널로 강제 종료
+)
+
+// waitForShutdown은 종료 시그널(SIGINT, SIGTERM)을 기다린 뒤 드레인과 정리를 하고 종료 코드를 반환합니다
+// 0이면 정상 종료, 두 번째 시그널을 받으면 정리를 기다리지 않고 exitForced로 종료합니다
+func (app *Application) waitForShutdown() int {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	logger.Info("Server is running. Press Ctrl+C to stop.")
+
+	// 시그널 대기
+	sig := <-sigChan
+	logger.Info("Received shutdown signal",
+		zap.String("signal", sig.String()),
+	)
+
+	// 정리 중 두 번째 시그널을 받으면 기다리지 않고 종료
+	go func() {
+		sig := <-sigChan
+		logger.Warn("Received second signal, forcing exit",
+			zap.String("signal", sig.String()),
+		)
+		logger.Close()
+		os.Exit(exitForced)
+	}()
+
+	timeout := time.Duration(app.currentConfig().Server.ShutdownTimeout) * time.Second
+	deadline := time.Now().Add(timeout)
+	drained := app.shutdown(deadline)
+	app.cleanup(deadline)
+
+	if !drained {
+		logger.Warn("Shutdown deadline exceeded", zap.Duration("timeout", timeout))
+		return exitDrainTimeout
+	}
+
+	logger.Info("Server stopped gracefully")
+	return 0
+}
+
+// shutdown은 새 시청/송출을 거부하고 진행 중인 API 요청과 녹화/HLS 마무리를 기다립니다
+// deadline 전에 끝나면 true를 반환합니다 (이후 cleanup으로 나머지 리소스 해제)
+func (app *Application) shutdown(deadline time.Time) bool {
+	timeout := time.Until(deadline)
+	logger.Info("Draining server", zap.Duration("timeout", timeout))
+
+	// 설정 파일 감시 중지 (정리 중 다시 로드되지 않도록 가장 먼저)
+	if app.configWatcher != nil {
+		app.configWatcher.Stop()
+	}
+
+	// 1. 새 시청/송출 거부 (기존 연결은 유지)
+	if app.signalingServer != nil {
+		app.signalingServer.Shutdown("server shutting down", timeout)
+	}
+	// 상태 구독 종료 (SSE 요청이 API 서버 종료 대기를 막지 않도록)
+	if app.statusManager != nil {
+		app.statusManager.Close()
+	}
+	if app.rtspServer != nil {
+		app.rtspServer.Drain()
+	}
+	if app.ingestManager != nil {
+		app.ingestManager.Drain()
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	// 2. 진행 중인 API 요청 대기
+	if app.apiServer != nil {
+		if err := app.apiServer.Shutdown(ctx); err != nil {
+			logger.Warn("API server did not finish in-flight requests", zap.Error(err))
+		}
+	}
+
+	// 3. 녹화/HLS 세그먼트 마무리 (대기 시간을 넘겨도 마무리는 계속 진행)
+	if !app.stopMedia(ctx) {
+		return false
+	}
+
+	logger.Info("Server drained")
+	return true
+}
+
+// stopMedia는 녹화/HLS 마무리를 (아직 시작하지 않았으면) 시작하고
+// 끝나거나 ctx가 끝날 때까지 기다립니다. 마무리가 끝났으면 true를 반환합니다
+func (app *Application) stopMedia(ctx context.Context) bool {
+	app.mediaOnce.Do(func() {
+		go func() {
+			defer close(app.mediaDone)
+			app.finishMedia()
+		}()
+	})
+
+	// 이미 끝났으면 ctx와 관계없이 true
+	select {
+	case <-app.mediaDone:
+		return true
+	default:
+	}
+
+	select {
+	case <-app.mediaDone:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// finishMedia는 패킷 생산자(트랜스코더, 소스, 수신)를 멈춘 뒤 녹화와 HLS를 마무리합니다
+func (app *Application) finishMedia() {
+	// 일회성 경로 훅 취소 (장기 실행 훅은 ProcessManager와 함께 종료)
+	if app.hooksManager != nil {
+		app.hooksManager.Close()
+	}
+
+	// 트랜스코더 중지 (재시작되지 않도록 ProcessManager보다 먼저)
+	if app.transcodeManager != nil {
+		app.transcodeManager.Close()
+		logger.Info("Transcoders stopped")
+	}
+
+	// 모든 외부 프로세스 중지
+	if app.processManager != nil {
+		app.processManager.StopAll()
+		logger.Info("All external processes stopped")
+	}
+
+	// 스트림 소스 중지
+	app.sourcesMutex.Lock()
+	for streamID, client := range app.sources {
+		logger.Info("Stopping stream source", zap.String("stream_id", streamID))
+		client.Stop()
+	}
+	app.sourcesMutex.Unlock()
+
+	// RTMP/SRT 수신 중지
+	if app.ingestManager != nil {
+		app.ingestManager.Stop()
+	}
+
+	// 이벤트 수집 종료 (새 이벤트 녹화가 시작되지 않도록 녹화 중지 전에)
+	if app.eventManager != nil {
+		app.eventManager.Close()
+		logger.Info("Event manager closed")
+	}
+
+	// 녹화 중지 (현재 세그먼트 마무리)
+	if app.recorderManager != nil {
+		app.recorderManager.StopAll()
+		logger.Info("Recorder manager stopped")
+	}
+
+	// HLS Manager 중지
+	if hlsManager := app.currentHLS(); hlsManager != nil {
+		hlsManager.StopAll()
+		logger.Info("HLS manager stopped")
+	}
+}
+
+// cleanup은 애플리케이션 리소스를 의존 순서대로 정리합니다
+// 연결을 받는 서버 → 패킷 생산자 → 소비자(녹화, HLS, WebRTC) → 스트림 → 데이터베이스 순
+// deadline까지 녹화/HLS 마무리가 끝나지 않으면 아직 사용 중인 WebRTC, 스트림, 데이터베이스는 닫지 않습니다
+func (app *Application) cleanup(deadline time.Time) {
+	logger.Info("Cleaning up application resources")
+
+	if app.configWatcher != nil {
+		app.configWatcher.Stop()
+	}
+
+	// 1. 상태 구독과 시그널링 서버 종료 (SSE, WebSocket 클라이언트)
+	if app.statusManager != nil {
+		app.statusManager.Close()
+	}
+	if app.signalingServer != nil {
+		app.signalingServer.Close()
+	}
+
+	// 2. API/메트릭 서버 종료
+	if app.apiServer != nil {
+		app.apiServer.Stop()
+	}
+	if app.metricsServer != nil {
+		app.metricsServer.Stop()
+	}
+	// 재생 요청이 끝났으므로 Range 요청용 클립 파일 삭제
+	if app.playbackManager != nil {
+		app.playbackManager.Close()
+	}
+
+	// 3. RTSP 서버 종료 (재생/송출 세션)
+	if app.rtspServer != nil {
+		app.rtspServer.Stop()
+		logger.Info("RTSP server stopped")
+	}
+
+	// 3.5. CCTV Manager 중지 - AIOT API 관련 (향후 재사용을 위해 주석 처리)
+	// if app.cctvManager != nil {
+	// 	app.cctvManager.Stop()
+	// 	logger.Info("CCTV manager stopped")
+	// }
+
+	// 4. 패킷 생산자 중지 후 녹화/HLS 마무리 (shutdown에서 시작했으면 그 마무리를 기다림)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	mediaStopped := app.stopMedia(ctx)
+	cancel()
+	if !mediaStopped {
+		logger.Warn("Media shutdown still in progress, leaving WebRTC, streams and database open")
+	}
+
+	// 5. WebRTC 관리자 종료
+	if mediaStopped && app.webrtcManager != nil {
+		app.webrtcManager.Close()
+	}
+
+	// 6. Context 취소 (ProcessManager 모니터, 정리 작업 종료)
+	if app.cancelFunc != nil {
+		app.cancelFunc()
+		logger.Info("Context cancelled")
+	}
+
+	// 7. 감사 로그 종료 (진행 중인 WebRTC 시청 세션 종료 기록 후 대기열 기록)
+	if app.auditLogger != nil {
+		app.endAllPeerAudits()
+		app.auditLogger.Close()
+		logger.Info("Audit logger closed")
+	}
+
+	// 8. 스트림 관리자 종료 (쓰는 쪽이 모두 멈춘 뒤)
+	if mediaStopped && app.streamManager != nil {
+		app.streamManager.Close()
+	}
+
+	// 8.5. 웹훅 전송 중지 (보내지 못한 전송은 DB에 남아 다음 실행 때 이어서 전송)
+	if app.webhookManager != nil {
+		app.webhookManager.Close()
+		logger.Info("Webhook manager closed")
+	}
+
+	// 9. Database 종료 (마지막, 녹화 마무리가 세그먼트를 기록 중이면 닫지 않음)
+	if mediaStopped && app.db != nil {
+		if err := app.db.Close(); err != nil {
+			logger.Error("Failed to close database", zap.Error(err))
+		} else {
+			logger.Info("Database closed")
+		}
+	}
+
+	logger.Info("Cleanup completed")
+}
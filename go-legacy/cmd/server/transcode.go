@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/transcode"
+	"github.com/yourusername/cctv3/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// initTranscode는 트랜스코딩 프로필 저장소와 관리자를 초기화합니다
+// config.yaml의 프로필은 DB에 없을 때만 추가합니다 (API로 수정한 값이 우선)
+func (app *Application) initTranscode() error {
+	config := app.currentConfig()
+
+	repo := database.NewTranscodeProfileRepository(app.db, logger.Log)
+
+	names := make([]string, 0, len(config.Transcode.Profiles))
+	for name := range config.Transcode.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cfg := config.Transcode.Profiles[name]
+		profile := &database.TranscodeProfile{
+			Name:       name,
+			Codec:      cfg.Codec,
+			Resolution: cfg.Resolution,
+			Bitrate:    cfg.Bitrate,
+			GOP:        cfg.GOP,
+		}
+		if err := transcode.ValidateProfile(profile); err != nil {
+			return fmt.Errorf("invalid transcode.profiles.%s: %w", name, err)
+		}
+
+		exists, err := repo.Exists(name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := repo.Create(profile); err != nil {
+				return err
+			}
+		}
+	}
+
+	user, pass := app.authManager.InternalCredentials()
+	app.transcodeManager = transcode.NewManager(transcode.Config{
+		FFmpegPath:     config.Transcode.FFmpegPath,
+		H264Encoder:    config.Transcode.H264Encoder,
+		H265Encoder:    config.Transcode.H265Encoder,
+		RTSPAddress:    fmt.Sprintf("127.0.0.1:%d", config.RTSP.Server.Port),
+		RTSPUser:       user,
+		RTSPPass:       pass,
+		CloseAfter:     time.Duration(config.Transcode.CloseAfter) * time.Second,
+		StartTimeout:   time.Duration(config.Transcode.StartTimeout) * time.Second,
+		Fallback:       config.Transcode.Fallback,
+		ProcessManager: app.processManager,
+		StreamManager:  app.streamManager,
+		StartSource:    app.startOnDemandStream,
+	}, repo, logger.Log)
+
+	logger.Info("Transcode manager initialized",
+		zap.Int("profiles", len(names)),
+		zap.String("fallback", config.Transcode.Fallback),
+	)
+	return nil
+}
+
+// ensureTranscodedStream은 RTSP DESCRIBE 전에 파생 스트림의 트랜스코더를 시작합니다 (일반 스트림은 무시)
+func (app *Application) ensureTranscodedStream(streamID string) error {
+	if _, _, derived := core.SplitDerivedStreamID(streamID); !derived {
+		return nil
+	}
+	if app.transcodeManager == nil {
+		return fmt.Errorf("transcoding is not enabled")
+	}
+	_, err := app.transcodeManager.Start(streamID)
+	return err
+}
@@ -3,12 +3,19 @@ module github.com/yourusername/cctv3
 go 1.24.0
 
 require (
+	github.com/MicahParks/keyfunc/v3 v3.7.0
+	github.com/abema/go-mp4 v1.4.1
 	github.com/asticode/go-astits v1.14.0
 	github.com/bluenviron/gohlslib/v2 v2.2.3
+	github.com/bluenviron/gortmplib v0.1.1
 	github.com/bluenviron/gortsplib/v4 v4.16.2
+	github.com/bluenviron/gortsplib/v5 v5.1.0
+	github.com/bluenviron/mediacommon/v2 v2.5.1
+	github.com/datarhei/gosrt v0.9.0
 	github.com/gin-gonic/gin v1.10.0
+	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/google/uuid v1.6.0
-	github.com/gorilla/websocket v1.5.1
+	github.com/gorilla/websocket v1.5.3
 	github.com/grafov/m3u8 v0.12.1
 	github.com/pion/interceptor v0.1.41
 	github.com/pion/rtp v1.8.23
@@ -16,14 +23,17 @@ require (
 	github.com/pion/webrtc/v4 v4.1.6
 	github.com/stretchr/testify v1.11.1
 	go.uber.org/zap v1.27.0
+	golang.org/x/crypto v0.43.0
+	golang.org/x/net v0.46.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 	gopkg.in/yaml.v3 v3.0.1
+	modernc.org/sqlite v1.40.1
 )
 
 require (
-	github.com/abema/go-mp4 v1.4.1 // indirect
+	github.com/MicahParks/jwkset v0.11.0 // indirect
 	github.com/asticode/go-astikit v0.30.0 // indirect
-	github.com/bluenviron/mediacommon/v2 v2.4.2 // indirect
+	github.com/benburkert/openpgp v0.0.0-20160410205803-c2471f86866c // indirect
 	github.com/bytedance/sonic v1.11.6 // indirect
 	github.com/bytedance/sonic/loader v0.1.1 // indirect
 	github.com/cloudwego/base64x v0.1.4 // indirect
@@ -63,14 +73,12 @@ require (
 	github.com/wlynxg/anet v0.0.5 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/arch v0.8.0 // indirect
-	golang.org/x/crypto v0.41.0 // indirect
 	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b // indirect
-	golang.org/x/net v0.43.0 // indirect
-	golang.org/x/sys v0.36.0 // indirect
-	golang.org/x/text v0.28.0 // indirect
+	golang.org/x/sys v0.37.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
+	golang.org/x/time v0.9.0 // indirect
 	google.golang.org/protobuf v1.34.1 // indirect
 	modernc.org/libc v1.66.10 // indirect
 	modernc.org/mathutil v1.7.1 // indirect
 	modernc.org/memory v1.11.0 // indirect
-	modernc.org/sqlite v1.40.1 // indirect
 )
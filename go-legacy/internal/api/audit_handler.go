@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultAuditLimit는 감사 로그 조회 기본 개수
+	defaultAuditLimit = 100
+
+	// maxAuditLimit는 JSON 조회 시 최대 개수 (CSV 내보내기는 제한 없음)
+	maxAuditLimit = 1000
+)
+
+// auditActor는 요청의 인증 정보와 클라이언트 주소로 감사 로그 주체를 만듭니다
+func (s *Server) auditActor(c *gin.Context) audit.Actor {
+	return audit.ActorFromIdentity(identityFromContext(c), c.ClientIP())
+}
+
+// recordAudit는 관리 작업을 감사 로그에 기록합니다
+func (s *Server) recordAudit(c *gin.Context, action, streamID, details string) {
+	s.auditLogger.Record(s.auditActor(c), action, streamID, details)
+}
+
+// handleListAudit는 감사 로그를 조회합니다
+// GET /api/v1/audit?user=&stream=&action=&start=RFC3339&end=RFC3339&limit=&offset=&format=csv
+func (s *Server) handleListAudit(c *gin.Context) {
+	if s.auditRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Audit log is not enabled",
+		})
+		return
+	}
+
+	filter := database.AuditFilter{
+		User:     c.Query("user"),
+		StreamID: c.Query("stream"),
+		Action:   c.Query("action"),
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"start", &filter.Start},
+		{"end", &filter.End},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid %s (RFC3339 required): %s", param.name, value),
+			})
+			return
+		}
+		*param.target = &t
+	}
+
+	csvExport := c.Query("format") == "csv"
+
+	limit, err := queryInt(c, "limit", defaultAuditLimit)
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid limit: " + c.Query("limit"),
+		})
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid offset: " + c.Query("offset"),
+		})
+		return
+	}
+
+	if csvExport {
+		// CSV 내보내기는 limit을 명시하지 않으면 전체
+		if c.Query("limit") == "" {
+			limit = 0
+		}
+	} else if limit == 0 || limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	entries, total, err := s.auditRepo.Query(filter)
+	if err != nil {
+		s.logger.Error("Failed to query audit log", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to query audit log: " + err.Error(),
+		})
+		return
+	}
+
+	if csvExport {
+		s.writeAuditCSV(c, entries)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+		"total":   total,
+	})
+}
+
+// writeAuditCSV는 감사 로그를 CSV 파일로 응답합니다
+func (s *Server) writeAuditCSV(c *gin.Context, entries []*database.AuditEntry) {
+	filename := fmt.Sprintf("audit_%s.csv", time.Now().UTC().Format("20060102T150405Z"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{
+		"id", "time", "user", "role", "action", "stream_id", "protocol",
+		"remote_addr", "session_id", "bytes", "duration_ms", "details",
+	})
+
+	for _, entry := range entries {
+		w.Write([]string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.Time.Format(time.RFC3339Nano),
+			entry.User,
+			entry.Role,
+			entry.Action,
+			entry.StreamID,
+			entry.Protocol,
+			entry.RemoteAddr,
+			entry.SessionID,
+			strconv.FormatUint(entry.Bytes, 10),
+			strconv.FormatInt(entry.DurationMs, 10),
+			entry.Details,
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		s.logger.Warn("Failed to write audit CSV", zap.Error(err))
+	}
+}
+
+// queryInt는 정수 쿼리 파라미터를 읽습니다 (없으면 기본값)
+func queryInt(c *gin.Context, name string, def int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/secret"
+	"go.uber.org/zap"
+)
+
+// identityContextKey는 gin context에 Identity를 저장하는 키
+const identityContextKey = "identity"
+
+// authMiddleware는 요청을 인증하고 최소 역할을 확인합니다
+// 인증된 Identity는 gin context와 http.Request context 양쪽에 저장됩니다
+// (WebSocket 등 gin.WrapF로 감싼 핸들러에서도 사용할 수 있도록)
+func (s *Server) authMiddleware(min auth.Role) gin.HandlerFunc {
+	return s.authMiddlewareWithToken(min, false)
+}
+
+// streamTokenMiddleware는 authMiddleware(viewer)와 같지만 ?token= 스트림 재생 토큰도 허용합니다
+// 토큰으로 인증된 요청은 토큰에 지정된 스트림만 시청할 수 있습니다 (HLS, WebSocket 임베드용)
+func (s *Server) streamTokenMiddleware() gin.HandlerFunc {
+	return s.authMiddlewareWithToken(auth.RoleViewer, true)
+}
+
+// authMiddlewareWithToken은 인증 미들웨어 구현입니다
+func (s *Server) authMiddlewareWithToken(min auth.Role, allowToken bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := c.Get(identityContextKey)
+		if !ok {
+			id, err := s.authenticateRequest(c, allowToken)
+			if err != nil {
+				if !errors.Is(err, auth.ErrNoCredentials) {
+					s.logger.Warn("Authentication failed",
+						zap.String("path", c.Request.URL.Path),
+						zap.String("ip", c.ClientIP()),
+						zap.Error(err),
+					)
+					// 무차별 대입 방지
+					time.Sleep(auth.PauseAfterError)
+				}
+
+				c.Header("WWW-Authenticate", `Bearer realm="cctv3"`)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": "Authentication required",
+				})
+				return
+			}
+
+			c.Set(identityContextKey, id)
+			c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), id))
+			identity = id
+		}
+
+		if !identity.(*auth.Identity).HasRole(min) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": fmt.Sprintf("Role '%s' or higher is required", min),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authenticateRequest는 요청의 인증 정보(API 키, JWT, 스트림 토큰)를 검증합니다
+func (s *Server) authenticateRequest(c *gin.Context, allowToken bool) (*auth.Identity, error) {
+	if allowToken && s.authManager.IsEnabled() {
+		if token := c.Query("token"); token != "" {
+			return s.authManager.AuthenticateStreamToken(token, c.ClientIP())
+		}
+	}
+
+	return s.authManager.Authenticate(auth.CredentialsFromRequest(c.Request))
+}
+
+// streamAccessMiddleware는 경로 파라미터의 스트림에 대한 시청 권한을 확인합니다
+// authMiddleware 이후에 사용해야 합니다
+func (s *Server) streamAccessMiddleware(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		streamID := c.Param(param)
+		if !identityFromContext(c).CanView(streamID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": fmt.Sprintf("Access to stream %s is not allowed", streamID),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// identityFromContext는 gin context에서 인증된 Identity를 반환합니다
+func identityFromContext(c *gin.Context) *auth.Identity {
+	if v, ok := c.Get(identityContextKey); ok {
+		return v.(*auth.Identity)
+	}
+	return nil
+}
+
+// parseReveal은 ?reveal=true (인증 정보 원문 표시) 요청을 확인합니다
+// admin만 사용할 수 있으며, 권한이 없으면 403 응답 후 ok=false를 반환합니다
+func parseReveal(c *gin.Context) (reveal bool, ok bool) {
+	if c.Query("reveal") != "true" {
+		return false, true
+	}
+
+	if !identityFromContext(c).HasRole(auth.RoleAdmin) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Role 'admin' is required to reveal credentials",
+		})
+		return false, false
+	}
+
+	return true, true
+}
+
+// responseSource는 응답에 포함할 source URL을 반환합니다 (reveal이 아니면 비밀번호 마스킹)
+func responseSource(source string, reveal bool) string {
+	if reveal {
+		return source
+	}
+	return secret.MaskURL(source)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/core"
+	"go.uber.org/zap"
+)
+
+// handleConfigReload는 config.yaml을 다시 읽어 재시작 없이 적용합니다
+// POST /api/v1/config/reload
+// 재시작이 필요한 항목(포트, 데이터베이스 등)이 바뀌었으면 아무것도 적용하지 않고 409와 항목 목록을 반환합니다
+func (s *Server) handleConfigReload(c *gin.Context) {
+	if s.configReloadHandler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Config reload is not enabled",
+		})
+		return
+	}
+
+	result, err := s.configReloadHandler()
+	if err != nil {
+		var restartErr *core.RestartRequiredError
+		if errors.As(err, &restartErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":            err.Error(),
+				"restart_required": restartErr.Fields,
+			})
+			return
+		}
+
+		s.logger.Error("Failed to reload config", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to reload config: " + err.Error(),
+		})
+		return
+	}
+
+	if len(result.Changed) > 0 {
+		s.recordAudit(c, audit.ActionConfigReload, "", strings.Join(result.Changed, ", "))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
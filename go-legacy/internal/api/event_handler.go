@@ -0,0 +1,171 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/events"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultEventLimit는 이벤트 조회 기본 개수
+	defaultEventLimit = 100
+
+	// maxEventLimit는 이벤트 조회 최대 개수
+	maxEventLimit = 1000
+
+	// maxAlarmBodySize는 HTTP 알람 본문 최대 크기 (스냅샷 이미지가 포함된 multipart 고려)
+	maxAlarmBodySize = 1 << 20
+)
+
+// handleListEvents는 스트림의 카메라 이벤트 이력을 조회합니다
+// GET /api/v1/streams/:id/events?type=&start=RFC3339&end=RFC3339&limit=&offset=
+func (s *Server) handleListEvents(c *gin.Context) {
+	if s.eventManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Events are not enabled",
+		})
+		return
+	}
+
+	filter := database.EventFilter{
+		StreamID: c.Param("id"),
+		Type:     c.Query("type"),
+	}
+
+	if filter.Type != "" && !events.IsValidType(filter.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event type: " + filter.Type,
+		})
+		return
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"start", &filter.Start},
+		{"end", &filter.End},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid %s (RFC3339 required): %s", param.name, value),
+			})
+			return
+		}
+		*param.target = &t
+	}
+
+	limit, err := queryInt(c, "limit", defaultEventLimit)
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid limit: " + c.Query("limit"),
+		})
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid offset: " + c.Query("offset"),
+		})
+		return
+	}
+	if limit == 0 || limit > maxEventLimit {
+		limit = maxEventLimit
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	list, total, err := s.eventManager.Query(filter)
+	if err != nil {
+		s.logger.Error("Failed to query events", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to query events: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": list,
+		"count":  len(list),
+		"total":  total,
+	})
+}
+
+// handleIngestEvent는 카메라/외부 시스템의 HTTP 알람을 수신합니다
+// POST /api/v1/streams/:id/events
+// 카메라 알람 서버 설정에는 Basic 인증(비밀번호=API 키) 또는 ?api_key=를 사용합니다
+func (s *Server) handleIngestEvent(c *gin.Context) {
+	if s.eventManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Events are not enabled",
+		})
+		return
+	}
+
+	streamID := c.Param("id")
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxAlarmBodySize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": "Failed to read alarm body: " + err.Error(),
+		})
+		return
+	}
+
+	event, err := events.ParseHTTPAlarm(c.GetHeader("Content-Type"), body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if exists, err := s.streamRepo.Exists(streamID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check stream: " + err.Error(),
+		})
+		return
+	} else if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Stream %s not found", streamID),
+		})
+		return
+	}
+
+	event.StreamID = streamID
+
+	stored, err := s.eventManager.Publish(&event)
+	if err != nil {
+		s.logger.Error("Failed to store event",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to store event: " + err.Error(),
+		})
+		return
+	}
+
+	if !stored {
+		// 같은 상태의 반복 알림 (녹화 연장은 반영됨)
+		c.JSON(http.StatusOK, gin.H{
+			"stored":    false,
+			"duplicate": true,
+			"recording": event.Recording,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
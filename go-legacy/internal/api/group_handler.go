@@ -0,0 +1,341 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bluenviron/gohlslib/v2/pkg/codecparams"
+	"github.com/bluenviron/gohlslib/v2/pkg/codecs"
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+// viewableRenditions는 시청 권한이 있는 화질만 남긴 그룹 사본을 반환합니다
+// 화질이 있는데 하나도 볼 수 없으면 nil을 반환합니다
+func viewableRenditions(group *database.StreamGroup, identity *auth.Identity) *database.StreamGroup {
+	filtered := *group
+	filtered.Renditions = make([]database.StreamRendition, 0, len(group.Renditions))
+	for _, rendition := range group.Renditions {
+		if identity.CanView(rendition.StreamID) {
+			filtered.Renditions = append(filtered.Renditions, rendition)
+		}
+	}
+
+	if len(group.Renditions) > 0 && len(filtered.Renditions) == 0 {
+		return nil
+	}
+	return &filtered
+}
+
+// validateGroup은 그룹 요청을 검증합니다 (화질 스트림은 등록되어 있어야 함)
+func (s *Server) validateGroup(group *database.StreamGroup) error {
+	if group.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if strings.ContainsAny(group.ID, "/?#") {
+		return fmt.Errorf("id must not contain '/', '?' or '#'")
+	}
+	if group.Name == "" {
+		group.Name = group.ID
+	}
+
+	if len(group.Renditions) == 0 {
+		return fmt.Errorf("at least one rendition is required")
+	}
+
+	names := make(map[string]bool, len(group.Renditions))
+	for _, rendition := range group.Renditions {
+		if rendition.Name == "" {
+			return fmt.Errorf("rendition name is required")
+		}
+		if names[rendition.Name] {
+			return fmt.Errorf("duplicate rendition name: %s", rendition.Name)
+		}
+		names[rendition.Name] = true
+
+		if rendition.Bandwidth <= 0 {
+			return fmt.Errorf("rendition %s: bandwidth (bps) must be positive", rendition.Name)
+		}
+		if rendition.Width < 0 || rendition.Height < 0 || (rendition.Width == 0) != (rendition.Height == 0) {
+			return fmt.Errorf("rendition %s: width and height must both be set or both be omitted", rendition.Name)
+		}
+
+		exists, err := s.streamRepo.Exists(rendition.StreamID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("rendition %s: stream not found: %s", rendition.Name, rendition.StreamID)
+		}
+	}
+
+	return nil
+}
+
+// handleListGroups는 스트림 그룹 목록을 조회합니다 (시청 가능한 화질만 표시)
+// GET /api/v1/groups
+func (s *Server) handleListGroups(c *gin.Context) {
+	groups, err := s.groupRepo.List()
+	if err != nil {
+		s.logger.Error("Failed to list stream groups", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list stream groups: " + err.Error(),
+		})
+		return
+	}
+
+	identity := identityFromContext(c)
+	result := make([]*database.StreamGroup, 0, len(groups))
+	for _, group := range groups {
+		if filtered := viewableRenditions(group, identity); filtered != nil {
+			result = append(result, filtered)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"groups": result,
+		"count":  len(result),
+	})
+}
+
+// handleGetGroup은 스트림 그룹을 조회합니다
+// GET /api/v1/groups/:id
+func (s *Server) handleGetGroup(c *gin.Context) {
+	group, ok := s.viewableGroup(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// viewableGroup은 그룹을 조회해 시청 가능한 화질만 남겨 반환합니다
+// 없으면 404, 볼 수 있는 화질이 없으면 403 응답 후 ok=false를 반환합니다
+func (s *Server) viewableGroup(c *gin.Context, id string) (*database.StreamGroup, bool) {
+	group, err := s.groupRepo.Get(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Stream group %s not found", id),
+		})
+		return nil, false
+	}
+
+	filtered := viewableRenditions(group, identityFromContext(c))
+	if filtered == nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": fmt.Sprintf("Access to stream group %s is not allowed", id),
+		})
+		return nil, false
+	}
+
+	return filtered, true
+}
+
+// handleCreateGroup은 스트림 그룹을 생성합니다
+// POST /api/v1/groups {"id":"lobby","name":"로비","renditions":[{"name":"main","stream_id":"lobby_main","bandwidth":8000000,"width":3840,"height":2160},{"name":"sub","stream_id":"lobby_sub","bandwidth":1000000,"width":720,"height":480}]}
+// 첫 번째 화질이 기본 화질입니다 (HLS master 플레이리스트의 첫 variant, WebRTC 시청 시작 화질)
+func (s *Server) handleCreateGroup(c *gin.Context) {
+	var group database.StreamGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := s.validateGroup(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid stream group: " + err.Error(),
+		})
+		return
+	}
+
+	exists, err := s.groupRepo.Exists(group.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create stream group: " + err.Error(),
+		})
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("Stream group %s already exists", group.ID),
+		})
+		return
+	}
+
+	if err := s.groupRepo.Create(&group); err != nil {
+		s.logger.Error("Failed to create stream group", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create stream group: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionGroupCreate, "", fmt.Sprintf("id=%s renditions=%d", group.ID, len(group.Renditions)))
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// handleUpdateGroup은 스트림 그룹의 이름과 화질 목록을 교체합니다
+// PUT /api/v1/groups/:id
+func (s *Server) handleUpdateGroup(c *gin.Context) {
+	var group database.StreamGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	group.ID = c.Param("id")
+
+	if err := s.validateGroup(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid stream group: " + err.Error(),
+		})
+		return
+	}
+
+	existing, err := s.groupRepo.Get(group.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Stream group %s not found", group.ID),
+		})
+		return
+	}
+
+	if err := s.groupRepo.Update(&group); err != nil {
+		s.logger.Error("Failed to update stream group", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update stream group: " + err.Error(),
+		})
+		return
+	}
+	group.CreatedAt = existing.CreatedAt
+
+	s.recordAudit(c, audit.ActionGroupUpdate, "", fmt.Sprintf("id=%s renditions=%d", group.ID, len(group.Renditions)))
+
+	c.JSON(http.StatusOK, group)
+}
+
+// handleDeleteGroup은 스트림 그룹을 삭제합니다 (화질 스트림은 유지)
+// DELETE /api/v1/groups/:id
+func (s *Server) handleDeleteGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := s.groupRepo.Delete(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Stream group %s not found", id),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionGroupDelete, "", "id="+id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Stream group deleted successfully",
+		"id":      id,
+	})
+}
+
+// HLS master 플레이리스트 생성 값 (gohlslib muxer와 같은 값)
+const (
+	// hlsMultivariantVersion은 fMP4/Low-Latency 미디어 플레이리스트를 가리키는 multivariant 플레이리스트의 EXT-X-VERSION
+	hlsMultivariantVersion = 9
+	// hlsMediaPlaylist는 스트림별 미디어 플레이리스트 이름 (index.m3u8은 multivariant 플레이리스트)
+	hlsMediaPlaylist = "main_stream.m3u8"
+)
+
+// handleHLSMasterPlaylist는 스트림 그룹의 HLS master 플레이리스트를 생성합니다
+// GET /hls/groups/:groupId/master.m3u8 (?token= 스트림 재생 토큰 허용, 토큰의 스트림만 variant로 포함)
+// 시청 가능한 화질마다 BANDWIDTH/CODECS/RESOLUTION을 가진 variant를 그룹 순서대로 나열합니다 (첫 번째가 기본)
+// CODECS는 실행 중인 스트림에서 SPS를 받은 경우에만 포함합니다
+// 인증이 활성화되어 있으면 variant URI에 스트림 재생 토큰을 붙여 하위 플레이리스트와 세그먼트 요청을 인증합니다
+func (s *Server) handleHLSMasterPlaylist(c *gin.Context) {
+	if hlsManager := s.currentHLS(); hlsManager == nil || !hlsManager.IsEnabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "HLS is not enabled",
+		})
+		return
+	}
+
+	group, ok := s.viewableGroup(c, c.Param("groupId"))
+	if !ok {
+		return
+	}
+	if len(group.Renditions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Stream group %s has no renditions", group.ID),
+		})
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#EXT-X-VERSION:%d\n", hlsMultivariantVersion)
+	b.WriteString("#EXT-X-INDEPENDENT-SEGMENTS\n")
+
+	for _, rendition := range group.Renditions {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d", rendition.Bandwidth)
+		if codecs := s.renditionCodecs(rendition.StreamID); codecs != "" {
+			fmt.Fprintf(&b, ",CODECS=\"%s\"", codecs)
+		}
+		if rendition.Width > 0 && rendition.Height > 0 {
+			fmt.Fprintf(&b, ",RESOLUTION=%dx%d", rendition.Width, rendition.Height)
+		}
+		b.WriteString("\n")
+
+		// master 플레이리스트 기준 상대 경로 (/hls/groups/:groupId/ → /hls/:streamId/)
+		// 스트림의 index.m3u8은 그 자체가 multivariant 플레이리스트이므로 미디어 플레이리스트를 가리킴
+		uri := "../../" + url.PathEscape(rendition.StreamID) + "/" + hlsMediaPlaylist
+		if s.authManager.IsEnabled() {
+			token, _, err := s.authManager.IssueStreamToken(rendition.StreamID, 0, c.ClientIP())
+			if err != nil {
+				s.logger.Error("Failed to issue stream token for HLS variant",
+					zap.String("group_id", group.ID),
+					zap.String("stream_id", rendition.StreamID),
+					zap.Error(err),
+				)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to issue token: " + err.Error(),
+				})
+				return
+			}
+			uri = addTokenQuery(uri, token)
+		}
+		b.WriteString(uri + "\n")
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, "application/vnd.apple.mpegurl", []byte(b.String()))
+}
+
+// renditionCodecs는 화질 스트림의 RFC 6381 코덱 문자열을 반환합니다 (알 수 없으면 빈 문자열)
+func (s *Server) renditionCodecs(streamID string) string {
+	if s.streamManager == nil {
+		return ""
+	}
+	stream, err := s.streamManager.GetStream(streamID)
+	if err != nil {
+		return ""
+	}
+
+	sps := stream.GetVideoSPS()
+	if sps == nil {
+		return ""
+	}
+
+	switch stream.GetVideoCodec() {
+	case "H264":
+		return codecparams.Marshal(&codecs.H264{SPS: sps})
+	case "H265":
+		return codecparams.Marshal(&codecs.H265{SPS: sps})
+	}
+	return ""
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/onvif"
+	"github.com/yourusername/cctv3/internal/secret"
+	"github.com/yourusername/cctv3/internal/source"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxDiscoveryTimeout는 요청으로 지정할 수 있는 최대 검색 대기 시간
+	maxDiscoveryTimeout = 30 * time.Second
+
+	// onvifRequestDeadline은 검색/가져오기 요청 전체의 최대 처리 시간
+	onvifRequestDeadline = 2 * time.Minute
+)
+
+// handleONVIFDiscover는 ONVIF 카메라를 검색하고 스트림 프로필을 조회합니다
+// POST /api/v1/onvif/discover {"username": "", "password": "", "timeout": 초, "addresses": ["192.168.1.10"]}
+// addresses가 없으면 WS-Discovery 멀티캐스트로 같은 서브넷을 검색합니다
+func (s *Server) handleONVIFDiscover(c *gin.Context) {
+	if s.onvifManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "ONVIF is not enabled",
+		})
+		return
+	}
+
+	var request struct {
+		Username  string   `json:"username"`
+		Password  string   `json:"password"`
+		Timeout   int      `json:"timeout"` // 초 (0이면 설정값)
+		Addresses []string `json:"addresses"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	timeout := time.Duration(request.Timeout) * time.Second
+	if request.Timeout < 0 || timeout > maxDiscoveryTimeout {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Invalid timeout: must be 0-%d seconds", int(maxDiscoveryTimeout.Seconds())),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), onvifRequestDeadline)
+	defer cancel()
+
+	devices, err := s.onvifManager.Discover(ctx, onvif.DiscoverOptions{
+		Username:  request.Username,
+		Password:  request.Password,
+		Addresses: request.Addresses,
+		Timeout:   timeout,
+	})
+	if err != nil {
+		s.logger.Error("ONVIF discovery failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "ONVIF discovery failed: " + err.Error(),
+		})
+		return
+	}
+
+	if devices == nil {
+		devices = []onvif.Device{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices": devices,
+		"count":   len(devices),
+	})
+}
+
+// onvifImportDevice는 가져오기 요청의 장치 항목입니다
+type onvifImportDevice struct {
+	Address  string   `json:"address" binding:"required"`
+	Name     string   `json:"name" binding:"required"` // 스트림 ID 접두사
+	Streams  []string `json:"streams"`                 // main, sub, profileN (비어있으면 main)
+	Username *string  `json:"username"`                // 지정하면 공통 인증 정보 대신 사용
+	Password *string  `json:"password"`
+}
+
+// handleONVIFImport는 ONVIF 장치의 스트림 프로필을 스트림으로 등록합니다
+// POST /api/v1/onvif/import
+//
+//	{"username": "admin", "password": "...", "source_on_demand": true, "rtsp_transport": "tcp",
+//	 "devices": [{"address": "192.168.1.10", "name": "CAM1", "streams": ["main", "sub"]}]}
+//
+// 스트림 ID는 main이면 name, 그 외에는 name-kind (예: CAM1, CAM1-sub)
+func (s *Server) handleONVIFImport(c *gin.Context) {
+	if s.onvifManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "ONVIF is not enabled",
+		})
+		return
+	}
+
+	var request struct {
+		Username       string              `json:"username"`
+		Password       string              `json:"password"`
+		SourceOnDemand *bool               `json:"source_on_demand"` // 기본 true
+		RTSPTransport  string              `json:"rtsp_transport"`
+		Devices        []onvifImportDevice `json:"devices" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	onDemand := true
+	if request.SourceOnDemand != nil {
+		onDemand = *request.SourceOnDemand
+	}
+	if request.RTSPTransport == "" {
+		request.RTSPTransport = "tcp"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), onvifRequestDeadline)
+	defer cancel()
+
+	created := make([]gin.H, 0)
+	failed := make([]gin.H, 0)
+
+	fail := func(device onvifImportDevice, streamID string, err error) {
+		s.logger.Warn("ONVIF import failed",
+			zap.String("address", device.Address),
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+		failed = append(failed, gin.H{
+			"address":   device.Address,
+			"stream_id": streamID,
+			"error":     err.Error(),
+		})
+	}
+
+	for _, device := range request.Devices {
+		username, password := request.Username, request.Password
+		if device.Username != nil {
+			username = *device.Username
+		}
+		if device.Password != nil {
+			password = *device.Password
+		}
+
+		client, err := s.onvifManager.NewClient(device.Address, username, password)
+		if err != nil {
+			fail(device, "", err)
+			continue
+		}
+
+		profiles, err := client.GetStreamProfiles(ctx)
+		if err != nil {
+			fail(device, "", err)
+			continue
+		}
+
+		kinds := device.Streams
+		if len(kinds) == 0 {
+			kinds = []string{"main"}
+		}
+
+		for _, kind := range kinds {
+			streamID := device.Name
+			if kind != "main" {
+				streamID = device.Name + "-" + kind
+			}
+
+			profile := findStreamProfile(profiles, kind)
+			if profile == nil {
+				fail(device, streamID, fmt.Errorf("stream profile %q not found", kind))
+				continue
+			}
+
+			stream, err := newONVIFStream(streamID, profile, username, password, onDemand, request.RTSPTransport)
+			if err != nil {
+				fail(device, streamID, err)
+				continue
+			}
+			stream.ONVIFAddress = client.Address()
+
+			if exists, err := s.streamRepo.Exists(streamID); err != nil {
+				fail(device, streamID, err)
+				continue
+			} else if exists {
+				fail(device, streamID, fmt.Errorf("stream %s already exists", streamID))
+				continue
+			}
+
+			if err := s.createStream(c, stream); err != nil {
+				fail(device, streamID, err)
+				continue
+			}
+
+			created = append(created, gin.H{
+				"id":               stream.ID,
+				"kind":             profile.Kind,
+				"source":           secret.MaskURL(stream.Source),
+				"source_on_demand": stream.SourceOnDemand,
+				"onvif_address":    stream.ONVIFAddress,
+				"onvif_profile":    stream.ONVIFProfile,
+				"encoding":         profile.Encoding,
+				"width":            profile.Width,
+				"height":           profile.Height,
+			})
+		}
+	}
+
+	s.logger.Info("ONVIF import completed",
+		zap.Int("created", len(created)),
+		zap.Int("failed", len(failed)),
+	)
+
+	status := http.StatusCreated
+	switch {
+	case len(created) == 0:
+		status = http.StatusUnprocessableEntity
+	case len(failed) > 0:
+		status = http.StatusMultiStatus
+	}
+
+	c.JSON(status, gin.H{
+		"created": created,
+		"errors":  failed,
+		"count":   len(created),
+	})
+}
+
+// findStreamProfile은 kind(main/sub/profileN) 또는 프로필 토큰으로 프로필을 찾습니다
+func findStreamProfile(profiles []onvif.StreamProfile, kind string) *onvif.StreamProfile {
+	for i := range profiles {
+		if strings.EqualFold(profiles[i].Kind, kind) || profiles[i].Token == kind {
+			return &profiles[i]
+		}
+	}
+	return nil
+}
+
+// newONVIFStream은 프로필의 스트림 URI에 인증 정보를 넣어 스트림 레코드를 만듭니다
+func newONVIFStream(streamID string, profile *onvif.StreamProfile, username, password string, onDemand bool, transport string) (*database.Stream, error) {
+	src, err := secret.JoinURLCredentials(profile.URI, username, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := source.Validate(src); err != nil {
+		return nil, fmt.Errorf("invalid stream URI from camera: %w", err)
+	}
+
+	return &database.Stream{
+		ID:             streamID,
+		Name:           streamID,
+		Source:         src,
+		SourceOnDemand: onDemand,
+		RTSPTransport:  transport,
+		ONVIFProfile:   profile.Token,
+	}, nil
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/playback"
+	"go.uber.org/zap"
+)
+
+// playbackWriter는 첫 바이트 기록 시점에 응답 헤더를 설정합니다
+// (기록 전 오류는 JSON으로 응답하기 위함)
+type playbackWriter struct {
+	c       *gin.Context
+	written bool
+}
+
+func (w *playbackWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.written = true
+		w.c.Header("Accept-Ranges", "none")
+		w.c.Header("Content-Type", "video/mp4")
+	}
+	return w.c.Writer.Write(p)
+}
+
+// handleListRecordings는 스트림의 녹화 구간 목록을 반환합니다
+// GET /api/v1/recordings/:streamId?start=RFC3339&end=RFC3339
+func (s *Server) handleListRecordings(c *gin.Context) {
+	if s.playbackManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Recording is not enabled",
+		})
+		return
+	}
+
+	streamID := c.Param("streamId")
+
+	start, err := parseOptionalTime(c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid start: " + err.Error(),
+		})
+		return
+	}
+
+	end, err := parseOptionalTime(c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid end: " + err.Error(),
+		})
+		return
+	}
+
+	entries, err := s.playbackManager.List(streamID, start, end)
+	if err != nil {
+		if errors.Is(err, playback.ErrNoSegmentsFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("No recordings found for stream %s", streamID),
+			})
+			return
+		}
+		s.logger.Error("Failed to list recordings",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list recordings: " + err.Error(),
+		})
+		return
+	}
+
+	// 각 구간의 재생 URL 생성
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	for _, entry := range entries {
+		v := url.Values{}
+		v.Add("start", entry.Start.Format(time.RFC3339Nano))
+		v.Add("duration", strconv.FormatFloat(time.Duration(entry.Duration).Seconds(), 'f', -1, 64))
+		u := &url.URL{
+			Scheme:   scheme,
+			Host:     c.Request.Host,
+			Path:     "/playback/" + streamID,
+			RawQuery: v.Encode(),
+		}
+		entry.URL = u.String()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"streamId":   streamID,
+		"recordings": entries,
+		"count":      len(entries),
+	})
+}
+
+// handlePlayback은 녹화 구간을 MP4/fMP4 클립으로 서빙합니다
+// GET /playback/:streamId?start=RFC3339&duration=seconds&format=mp4|fmp4
+// 클립은 start 이전의 가장 가까운 키프레임에서 시작합니다
+// format=mp4는 Range 요청(탐색)을 지원하고, format=fmp4는 Range 없이 바로 전송합니다
+func (s *Server) handlePlayback(c *gin.Context) {
+	if s.playbackManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Recording is not enabled",
+		})
+		return
+	}
+
+	streamID := c.Param("streamId")
+
+	start, err := time.Parse(time.RFC3339, c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid start: " + err.Error(),
+		})
+		return
+	}
+
+	duration, err := playback.ParseDuration(c.Query("duration"))
+	if err != nil || duration <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid duration: " + c.Query("duration"),
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", playback.FormatFMP4)
+	if format != playback.FormatFMP4 && format != playback.FormatMP4 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid format: " + format,
+		})
+		return
+	}
+
+	defer s.recordPlaybackExport(c, streamID, start, duration, format)
+
+	// 긴 클립 다운로드가 서버 WriteTimeout에 의해 끊기지 않도록 해제
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	if format == playback.FormatMP4 {
+		s.servePlaybackMP4(c, streamID, start, duration)
+		return
+	}
+
+	w := &playbackWriter{c: c}
+	err = s.playbackManager.Write(w, streamID, start, duration, format)
+	if err != nil {
+		s.handlePlaybackError(c, streamID, err, w.written)
+	}
+}
+
+// servePlaybackMP4는 MP4 클립을 http.ServeContent로 서빙합니다
+// 클립 파일은 구간별로 한 번만 생성되어 이후 Range 요청(탐색)에 재사용됩니다
+func (s *Server) servePlaybackMP4(c *gin.Context, streamID string, start time.Time, duration time.Duration) {
+	f, err := s.playbackManager.OpenMP4(streamID, start, duration)
+	if err != nil {
+		s.handlePlaybackError(c, streamID, err, false)
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Type", "video/mp4")
+	http.ServeContent(c.Writer, c.Request, streamID+".mp4", start, f)
+}
+
+// recordPlaybackExport는 녹화 내보내기를 전송 바이트와 함께 감사 로그에 기록합니다
+func (s *Server) recordPlaybackExport(c *gin.Context, streamID string, start time.Time, duration time.Duration, format string) {
+	if s.auditLogger == nil || c.Writer.Status() >= http.StatusBadRequest {
+		return
+	}
+
+	var bytes uint64
+	if size := c.Writer.Size(); size > 0 {
+		bytes = uint64(size)
+	}
+	details := fmt.Sprintf("start=%s duration=%s format=%s", start.Format(time.RFC3339), duration, format)
+	if r := c.GetHeader("Range"); r != "" {
+		details += " range=" + r
+	}
+	s.auditLogger.RecordExport(s.auditActor(c), streamID, bytes, details)
+}
+
+// handlePlaybackError는 재생 오류를 처리합니다
+// 이미 응답이 시작된 경우에는 로그만 남깁니다
+func (s *Server) handlePlaybackError(c *gin.Context, streamID string, err error, written bool) {
+	// 클라이언트가 다운로드를 중단한 경우
+	var netErr *net.OpError
+	if errors.As(err, &netErr) {
+		return
+	}
+
+	if written {
+		s.logger.Error("Playback aborted",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if errors.Is(err, playback.ErrNoSegmentsFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("No recordings found for stream %s in requested range", streamID),
+		})
+		return
+	}
+
+	s.logger.Error("Playback failed",
+		zap.String("stream_id", streamID),
+		zap.Error(err),
+	)
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": "Playback failed: " + err.Error(),
+	})
+}
+
+// parseOptionalTime은 비어있지 않은 경우 RFC3339 시각을 파싱합니다
+func parseOptionalTime(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
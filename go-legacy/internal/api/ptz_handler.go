@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/onvif"
+	"go.uber.org/zap"
+)
+
+// handlePTZ는 스트림 카메라의 PTZ를 제어합니다
+// POST /api/v1/streams/:id/ptz
+//
+//	{"action": "continuous", "pan": 0.5, "tilt": 0, "zoom": 0, "timeout": 1000}
+//	{"action": "stop"}
+//	{"action": "absolute", "pan": 0, "tilt": 0, "zoom": 0.5, "speed": 1}
+//	{"action": "relative", "pan": 0.1}
+//	{"action": "goto_preset", "preset": "1"}
+//	{"action": "set_preset", "name": "Gate"}
+//	{"action": "presets"}
+func (s *Server) handlePTZ(c *gin.Context) {
+	streamID := c.Param("id")
+
+	if s.onvifManager == nil || s.ptzHandler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "ONVIF is not enabled",
+		})
+		return
+	}
+
+	var cmd onvif.PTZCommand
+	if err := c.ShouldBindJSON(&cmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if _, err := s.streamRepo.Get(streamID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Stream %s not found", streamID),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), onvifRequestDeadline)
+	defer cancel()
+
+	result, err := s.ptzHandler(ctx, streamID, cmd)
+	if err != nil {
+		s.logger.Warn("PTZ command failed",
+			zap.String("stream_id", streamID),
+			zap.String("command", cmd.String()),
+			zap.Error(err),
+		)
+
+		status := http.StatusBadGateway
+		switch {
+		case errors.Is(err, onvif.ErrInvalidPTZCommand):
+			status = http.StatusBadRequest
+		case errors.Is(err, onvif.ErrPTZNotSupported):
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, gin.H{
+			"error": "PTZ command failed: " + err.Error(),
+		})
+		return
+	}
+
+	if cmd.Action != onvif.PTZPresets {
+		s.recordAudit(c, audit.ActionPTZ, streamID, cmd.String())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,15 +1,31 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/auth"
 	// "github.com/yourusername/cctv3/internal/cctv" // AIOT API 관련 - 향후 재사용을 위해 주석 처리
 	"github.com/yourusername/cctv3/internal/core"
 	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/events"
 	"github.com/yourusername/cctv3/internal/hls"
+	"github.com/yourusername/cctv3/internal/onvif"
+	"github.com/yourusername/cctv3/internal/playback"
+	"github.com/yourusername/cctv3/internal/secret"
+	"github.com/yourusername/cctv3/internal/site"
+	"github.com/yourusername/cctv3/internal/snapshot"
+	"github.com/yourusername/cctv3/internal/source"
+	"github.com/yourusername/cctv3/internal/status"
+	"github.com/yourusername/cctv3/internal/transcode"
+	"github.com/yourusername/cctv3/internal/webhook"
 	"go.uber.org/zap"
 )
 
@@ -19,6 +35,7 @@ type Server struct {
 	httpServer *http.Server
 	router     *gin.Engine
 	port       int
+	startTime  time.Time // uptime 계산
 
 	// 핸들러
 	healthHandler      func() map[string]interface{}
@@ -27,8 +44,17 @@ type Server struct {
 	startStreamHandler func(streamID string) error // 스트림 시작 콜백
 	stopStreamHandler  func(streamID string) error // 스트림 정지 콜백
 
+	// 예비 소스가 있는 스트림의 활성 소스 상태 조회
+	sourceStatusHandler func(streamID string) (source.FailoverStatus, bool)
+
 	// Database repository for CRUD operations
 	streamRepo *database.StreamRepository
+	groupRepo  *database.StreamGroupRepository
+
+	// 사이트와 태그 (스트림 분류, 사이트별 동시 연결 제한)
+	siteRepo    *database.SiteRepository
+	tagRepo     *database.TagRepository
+	siteManager *site.Manager
 
 	// Stream manager for runtime streams
 	streamManager *core.StreamManager
@@ -36,7 +62,43 @@ type Server struct {
 	// AIOT API 관련 (향후 재사용을 위해 주석 처리)
 	// cctvManager cctv.Provider
 
-	hlsManager *hls.Manager
+	// 설정 다시 로드 시 교체될 수 있으므로 currentHLS()로 조회
+	hlsManager atomic.Pointer[hls.Manager]
+
+	// 녹화 재생 (nil이면 재생 API 비활성화)
+	playbackManager *playback.Manager
+
+	// 인증 (nil이거나 비활성화면 모든 요청 허용)
+	authManager *auth.Manager
+
+	// 감사 로그 (nil이면 기록/조회 비활성화)
+	auditLogger *audit.Logger
+	auditRepo   *database.AuditRepository
+
+	// ONVIF 검색/가져오기 (nil이면 비활성화)
+	onvifManager *onvif.Manager
+
+	// PTZ 제어 콜백과 최소 역할
+	ptzHandler func(ctx context.Context, streamID string, cmd onvif.PTZCommand) (*onvif.PTZResult, error)
+	ptzRole    auth.Role
+
+	// 카메라 이벤트 (nil이면 이벤트 API 비활성화)
+	eventManager *events.Manager
+
+	// 스냅샷 (nil이면 스냅샷 API 비활성화)
+	snapshotManager *snapshot.Manager
+
+	// 트랜스코딩 프로필 (nil이면 트랜스코딩 API 비활성화)
+	transcodeManager *transcode.Manager
+
+	// 웹훅 (nil이면 웹훅 API 비활성화)
+	webhookManager *webhook.Manager
+
+	// 실시간 스트림 상태 (nil이면 /api/v1/events 비활성화)
+	statusManager *status.Manager
+
+	// 설정 다시 로드 콜백 (nil이면 다시 로드 API 비활성화)
+	configReloadHandler func() (*core.ReloadResult, error)
 }
 
 // ServerConfig는 API 서버 설정
@@ -44,15 +106,27 @@ type ServerConfig struct {
 	Port               int
 	Production         bool
 	Logger             *zap.Logger
+	StartTime          time.Time // 서버 시작 시각 (비어있으면 API 서버 생성 시각)
 	HealthHandler      func() map[string]interface{}
 	StatsHandler       func() map[string]interface{}
 	WebSocketHandler   func(http.ResponseWriter, *http.Request)
 	StartStreamHandler func(streamID string) error // 스트림 시작 콜백
 	StopStreamHandler  func(streamID string) error // 스트림 정지 콜백
 
+	// 예비 소스가 있는 스트림의 활성 소스 상태 조회 (runtime_info.active_source)
+	SourceStatusHandler func(streamID string) (source.FailoverStatus, bool)
+
 	// Database repository for CRUD operations
 	StreamRepository *database.StreamRepository
 
+	// 스트림 그룹 (메인/서브 화질 묶음)
+	GroupRepository *database.StreamGroupRepository
+
+	// 사이트와 태그
+	SiteRepository *database.SiteRepository
+	TagRepository  *database.TagRepository
+	SiteManager    *site.Manager
+
 	// Stream manager for runtime streams
 	StreamManager *core.StreamManager
 
@@ -60,6 +134,39 @@ type ServerConfig struct {
 	// CCTVManager cctv.Provider
 
 	HLSManager *hls.Manager
+
+	PlaybackManager *playback.Manager
+
+	AuthManager *auth.Manager
+
+	AuditLogger     *audit.Logger
+	AuditRepository *database.AuditRepository
+
+	ONVIFManager *onvif.Manager
+
+	// PTZ 제어 콜백 (스트림의 카메라 주소/인증 정보로 명령 전달)
+	PTZHandler func(ctx context.Context, streamID string, cmd onvif.PTZCommand) (*onvif.PTZResult, error)
+	// PTZ 제어 최소 역할 (기본 operator)
+	PTZRole auth.Role
+
+	EventManager *events.Manager
+
+	SnapshotManager *snapshot.Manager
+
+	TranscodeManager *transcode.Manager
+
+	WebhookManager *webhook.Manager
+
+	StatusManager *status.Manager
+
+	// config.yaml 다시 로드 (재시작이 필요한 변경이면 *core.RestartRequiredError 반환)
+	ConfigReloadHandler func() (*core.ReloadResult, error)
+
+	// CORS 허용 Origin 목록 ("*"이면 전체 허용)
+	AllowedOrigins []string
+
+	// X-Forwarded-For/X-Real-IP를 신뢰할 프록시 (IP 또는 CIDR, 비어있으면 헤더 무시)
+	TrustedProxies []string
 }
 
 // NewServer는 새로운 API 서버를 생성합니다
@@ -71,23 +178,60 @@ func NewServer(config ServerConfig) *Server {
 	}
 
 	router := gin.New()
+
+	// 기본값(모든 프록시 신뢰)이면 누구나 X-Forwarded-For로 클라이언트 IP를 위조할 수 있으므로
+	// 설정된 프록시에서 온 요청만 헤더를 사용하고, 그 외에는 접속 주소를 c.ClientIP()로 사용
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		config.Logger.Error("Invalid trusted proxies, forwarded headers are ignored", zap.Error(err))
+		router.SetTrustedProxies(nil)
+	}
+
 	router.Use(gin.Recovery())
-	router.Use(corsMiddleware())
+	router.Use(corsMiddleware(config.AllowedOrigins))
 	router.Use(loggerMiddleware(config.Logger))
+	router.Use(remoteAddrMiddleware())
+
+	if config.StartTime.IsZero() {
+		config.StartTime = time.Now()
+	}
 
 	server := &Server{
-		logger:             config.Logger,
-		router:             router,
-		port:               config.Port,
-		healthHandler:      config.HealthHandler,
-		statsHandler:       config.StatsHandler,
-		websocketHandler:   config.WebSocketHandler,
-		startStreamHandler: config.StartStreamHandler,
-		stopStreamHandler:  config.StopStreamHandler,
-		streamRepo:         config.StreamRepository,
-		streamManager:      config.StreamManager,
+		logger:              config.Logger,
+		router:              router,
+		port:                config.Port,
+		startTime:           config.StartTime,
+		healthHandler:       config.HealthHandler,
+		statsHandler:        config.StatsHandler,
+		websocketHandler:    config.WebSocketHandler,
+		startStreamHandler:  config.StartStreamHandler,
+		stopStreamHandler:   config.StopStreamHandler,
+		sourceStatusHandler: config.SourceStatusHandler,
+		streamRepo:          config.StreamRepository,
+		groupRepo:           config.GroupRepository,
+		siteRepo:            config.SiteRepository,
+		tagRepo:             config.TagRepository,
+		siteManager:         config.SiteManager,
+		streamManager:       config.StreamManager,
 		// cctvManager:        config.CCTVManager, // AIOT API 관련 - 향후 재사용을 위해 주석 처리
-		hlsManager: config.HLSManager,
+		playbackManager: config.PlaybackManager,
+		authManager:     config.AuthManager,
+		auditLogger:     config.AuditLogger,
+		auditRepo:       config.AuditRepository,
+		onvifManager:    config.ONVIFManager,
+		ptzHandler:      config.PTZHandler,
+		ptzRole:         config.PTZRole,
+		eventManager:    config.EventManager,
+		snapshotManager: config.SnapshotManager,
+
+		transcodeManager:    config.TranscodeManager,
+		webhookManager:      config.WebhookManager,
+		statusManager:       config.StatusManager,
+		configReloadHandler: config.ConfigReloadHandler,
+	}
+	server.hlsManager.Store(config.HLSManager)
+
+	if server.ptzRole == "" {
+		server.ptzRole = auth.RoleOperator
 	}
 
 	server.setupRoutes()
@@ -96,7 +240,16 @@ func NewServer(config ServerConfig) *Server {
 }
 
 // setupRoutes는 라우트를 설정합니다
+// 역할별 권한: viewer=허용된 스트림 시청, operator=스트림 시작/정지/PTZ, admin=설정 변경
 func (s *Server) setupRoutes() {
+	viewer := s.authMiddleware(auth.RoleViewer)
+	operator := s.authMiddleware(auth.RoleOperator)
+	admin := s.authMiddleware(auth.RoleAdmin)
+	ptz := s.authMiddleware(s.ptzRole) // PTZ 제어 (operator 이상, 설정으로 admin 한정 가능)
+
+	// viewer + ?token= 스트림 재생 토큰 (HLS, WebSocket, <img> 스냅샷 임베드)
+	streamToken := s.streamTokenMiddleware()
+
 	// Health check
 	s.router.GET("/health", s.handleHealth)
 
@@ -104,7 +257,10 @@ func (s *Server) setupRoutes() {
 	v1 := s.router.Group("/api/v1")
 	{
 		v1.GET("/health", s.handleHealth)
-		v1.GET("/stats", s.handleStats)
+		v1.GET("/stats", viewer, s.handleStats)
+
+		// 실시간 스트림 상태 (Server-Sent Events, 시청 권한이 있는 스트림만, ?token= 허용)
+		v1.GET("/events", streamToken, s.handleStatusEvents)
 
 		// AIOT API 관련 - 향후 재사용을 위해 주석 처리
 		// v1.POST("/sync", s.handleSync)
@@ -112,37 +268,130 @@ func (s *Server) setupRoutes() {
 		// Stream CRUD endpoints
 		streams := v1.Group("/streams")
 		{
-			streams.POST("", s.handleCreateStream)          // Create stream
-			streams.GET("", s.handleListStreams)            // List all streams
-			streams.GET("/:id", s.handleGetStream)          // Get single stream
-			streams.PUT("/:id", s.handleUpdateStream)       // Update stream
-			streams.DELETE("/:id", s.handleDeleteStream)    // Delete stream (stop RTSP client)
-			streams.POST("/:id/start", s.handleStartStream) // Start on-demand stream
+			streamAccess := s.streamAccessMiddleware("id")
+
+			streams.POST("", admin, s.handleCreateStream)                                // Create stream
+			streams.GET("", viewer, s.handleListStreams)                                 // List all streams
+			streams.POST("/import", admin, s.handleImportStreams)                        // Bulk import (CSV, YAML, JSON)
+			streams.GET("/export", admin, s.handleExportStreams)                         // Bulk export
+			streams.GET("/:id", viewer, streamAccess, s.handleGetStream)                 // Get single stream
+			streams.PUT("/:id", admin, s.handleUpdateStream)                             // Update stream
+			streams.DELETE("/:id", admin, s.handleDeleteStream)                          // Delete stream (stop RTSP client)
+			streams.POST("/:id/start", operator, streamAccess, s.handleStartStream)      // Start on-demand stream
+			streams.POST("/:id/stop", operator, streamAccess, s.handleStopStream)        // Stop running stream
+			streams.POST("/:id/token", operator, streamAccess, s.handleIssueStreamToken) // Issue signed playback token
+			streams.POST("/:id/ptz", ptz, streamAccess, s.handlePTZ)                     // PTZ control (viewer 불가)
+			streams.GET("/:id/events", viewer, streamAccess, s.handleListEvents)         // Camera event history
+			streams.POST("/:id/events", operator, streamAccess, s.handleIngestEvent)     // HTTP alarm push
+
+			// JPEG 스냅샷 (?token= 허용)
+			streams.GET("/:id/snapshot.jpg", streamToken, streamAccess, s.handleSnapshot)
+
+			// 트랜스코딩 파생 스트림 (CAM1~h264_720p)
+			streams.GET("/:id/transcodes", viewer, streamAccess, s.handleListDerivedStreams)
+		}
+
+		// 사이트 (설치 장소별 스트림 묶음, 일괄 시작/정지, 동시 연결 제한)
+		sites := v1.Group("/sites")
+		{
+			sites.GET("", viewer, s.handleListSites)
+			sites.POST("", admin, s.handleCreateSite)
+			sites.GET("/:id", viewer, s.handleGetSite)
+			sites.PUT("/:id", admin, s.handleUpdateSite)
+			sites.DELETE("/:id", admin, s.handleDeleteSite)
+			sites.POST("/:id/streams", admin, s.handleAssignSiteStreams)
+			sites.DELETE("/:id/streams/:streamId", admin, s.handleUnassignSiteStream)
+			sites.POST("/:id/start", operator, s.handleStartSite)
+			sites.POST("/:id/stop", operator, s.handleStopSite)
+		}
+
+		// 스트림 태그
+		tags := v1.Group("/tags")
+		{
+			tags.GET("", viewer, s.handleListTags)
+			tags.POST("", admin, s.handleCreateTag)
+			tags.PUT("/:name", admin, s.handleUpdateTag)
+			tags.DELETE("/:name", admin, s.handleDeleteTag)
+		}
+
+		// 스트림 그룹 (메인/서브 화질 묶음)
+		groups := v1.Group("/groups")
+		{
+			groups.GET("", viewer, s.handleListGroups)
+			groups.POST("", admin, s.handleCreateGroup)
+			groups.GET("/:id", viewer, s.handleGetGroup)
+			groups.PUT("/:id", admin, s.handleUpdateGroup)
+			groups.DELETE("/:id", admin, s.handleDeleteGroup)
+		}
+
+		// 트랜스코딩 프로필
+		transcodeGroup := v1.Group("/transcode/profiles")
+		{
+			transcodeGroup.GET("", viewer, s.handleListTranscodeProfiles)
+			transcodeGroup.POST("", admin, s.handleCreateTranscodeProfile)
+			transcodeGroup.PUT("/:name", admin, s.handleUpdateTranscodeProfile)
+			transcodeGroup.DELETE("/:name", admin, s.handleDeleteTranscodeProfile)
 		}
 
 		// HLS API endpoints
-		hlsGroup := v1.Group("/hls")
+		hlsGroup := v1.Group("/hls", viewer)
 		{
 			hlsGroup.GET("/streams", s.handleHLSStreamsList)
-			hlsGroup.GET("/streams/:id", s.handleHLSStreamInfo)
-			hlsGroup.GET("/streams/:id/stats", s.handleHLSStreamStats)
+			hlsGroup.GET("/streams/:id", s.streamAccessMiddleware("id"), s.handleHLSStreamInfo)
+			hlsGroup.GET("/streams/:id/stats", s.streamAccessMiddleware("id"), s.handleHLSStreamStats)
+		}
+
+		// 녹화 구간 조회
+		v1.GET("/recordings/:streamId", viewer, s.streamAccessMiddleware("streamId"), s.handleListRecordings)
+
+		// 감사 로그
+		v1.GET("/audit", admin, s.handleListAudit)
+
+		// 웹훅 (외부 시스템 알림)
+		webhooks := v1.Group("/webhooks", admin)
+		{
+			webhooks.GET("", s.handleListWebhooks)
+			webhooks.POST("", s.handleCreateWebhook)
+			webhooks.GET("/deliveries", s.handleListWebhookDeliveries)
+			webhooks.GET("/:id", s.handleGetWebhook)
+			webhooks.PUT("/:id", s.handleUpdateWebhook)
+			webhooks.DELETE("/:id", s.handleDeleteWebhook)
+			webhooks.POST("/:id/test", s.handleTestWebhook)
+			webhooks.GET("/:id/deliveries", s.handleListWebhookDeliveries)
+		}
+
+		// 설정 파일 다시 로드
+		v1.POST("/config/reload", admin, s.handleConfigReload)
+
+		// ONVIF 카메라 검색/가져오기
+		onvifGroup := v1.Group("/onvif", admin)
+		{
+			onvifGroup.POST("/discover", s.handleONVIFDiscover)
+			onvifGroup.POST("/import", s.handleONVIFImport)
 		}
 	}
 
 	// API v3 - mediaMTX style endpoints
 	v3 := s.router.Group("/v3/config/paths")
 	{
-		v3.GET("/list", s.handlePathsList)
-		v3.POST("/add/:name", s.handlePathAdd)
-		v3.DELETE("/delete/:name", s.handlePathDelete)
+		v3.GET("/list", operator, s.handlePathsList)
+		v3.POST("/add/:name", admin, s.handlePathAdd)
+		v3.DELETE("/delete/:name", admin, s.handlePathDelete)
 	}
 
-	// HLS 플레이리스트 및 세그먼트 서빙
-	s.router.GET("/hls/:streamId/index.m3u8", s.handleHLSPlaylist)
-	s.router.GET("/hls/:streamId/:segment", s.handleHLSSegment)
+	// HLS 플레이리스트 및 세그먼트 서빙 (?token= 스트림 재생 토큰 허용)
+	hlsAccess := s.streamAccessMiddleware("streamId")
+	s.router.GET("/hls/:streamId/index.m3u8", streamToken, hlsAccess, s.handleHLSPlaylist)
+	s.router.GET("/hls/:streamId/:segment", streamToken, hlsAccess, s.handleHLSSegment)
+
+	// 스트림 그룹 HLS master 플레이리스트 (화질별 variant, ?token= 허용)
+	s.router.GET("/hls/groups/:groupId/master.m3u8", streamToken, s.handleHLSMasterPlaylist)
+
+	// 녹화 재생 (mp4/fmp4 클립)
+	s.router.GET("/playback/:streamId", viewer, s.streamAccessMiddleware("streamId"), s.handlePlayback)
 
-	// WebSocket signaling
-	s.router.GET("/ws", gin.WrapF(s.websocketHandler))
+	// WebSocket signaling (스트림별 권한은 시그널링 서버에서 확인, ?token= 허용)
+	s.router.GET("/ws", streamToken, gin.WrapF(s.websocketHandler))
 
 	// Static files
 	s.router.Static("/static", "./web/static")
@@ -174,6 +423,18 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// Shutdown은 새 요청을 받지 않고 진행 중인 요청이 끝날 때까지 기다립니다 (ctx 만료 시 에러)
+// WebSocket처럼 hijack된 연결은 기다리지 않습니다
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("Shutting down API server")
+
+	if s.httpServer != nil {
+		return s.httpServer.Shutdown(ctx)
+	}
+
+	return nil
+}
+
 // Stop은 API 서버를 종료합니다
 func (s *Server) Stop() error {
 	s.logger.Info("Stopping API server")
@@ -209,15 +470,27 @@ func (s *Server) handleStats(c *gin.Context) {
 		stats = s.statsHandler()
 	} else {
 		stats = map[string]interface{}{
-			"uptime":  "0h 0m 0s",
 			"streams": 0,
 			"clients": 0,
 		}
 	}
 
+	uptime := time.Since(s.startTime)
+	stats["uptime"] = formatUptime(uptime)
+	stats["uptime_seconds"] = int64(uptime.Seconds())
+
 	c.JSON(http.StatusOK, stats)
 }
 
+// formatUptime은 uptime을 "1h 2m 3s" 형식으로 변환합니다
+func formatUptime(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	hours := int64(d / time.Hour)
+	minutes := int64(d % time.Hour / time.Minute)
+	seconds := int64(d % time.Minute / time.Second)
+	return fmt.Sprintf("%dh %dm %ds", hours, minutes, seconds)
+}
+
 // AIOT API 관련 핸들러 - 향후 재사용을 위해 주석 처리
 // handleSync는 CCTV 목록 수동 동기화를 처리합니다
 // func (s *Server) handleSync(c *gin.Context) {
@@ -250,28 +523,29 @@ func (s *Server) handleStats(c *gin.Context) {
 
 // handlePathsList는 mediaMTX 스타일의 paths 목록을 반환합니다
 func (s *Server) handlePathsList(c *gin.Context) {
-	// Database에서 모든 스트림 가져오기 (Single Source of Truth)
-	dbStreams, err := s.streamRepo.List()
-	if err != nil {
-		s.logger.Error("Failed to list streams from database", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to list streams: " + err.Error(),
-		})
+	reveal, ok := parseReveal(c)
+	if !ok {
+		return
+	}
+
+	// Database에서 조건에 맞는 스트림 가져오기 (Single Source of Truth, ?page=&itemsPerPage= 페이지)
+	result, ok := s.queryStreams(c)
+	if !ok {
 		return
 	}
 
 	// mediaMTX 호환 형식으로 변환
-	items := make([]gin.H, 0, len(dbStreams))
-	for _, stream := range dbStreams {
+	items := make([]gin.H, 0, len(result.streams))
+	for _, stream := range result.streams {
 		items = append(items, gin.H{
 			"name":   stream.ID, // mediaMTX는 name 필드에 ID 사용
-			"source": stream.Source,
+			"source": responseSource(stream.Source, reveal),
 		})
 	}
 
 	response := gin.H{
-		"pageCount": 1,
-		"itemCount": len(items),
+		"pageCount": result.pageCount,
+		"itemCount": result.itemCount,
 		"items":     items,
 	}
 
@@ -301,9 +575,16 @@ func (s *Server) handlePathAdd(c *gin.Context) {
 		request.RTSPTransport = "tcp"
 	}
 
+	if err := source.Validate(request.Source); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid source: " + err.Error(),
+		})
+		return
+	}
+
 	s.logger.Info("Path add requested",
 		zap.String("name", pathName),
-		zap.String("source", request.Source),
+		zap.String("source", secret.MaskURL(request.Source)),
 		zap.Bool("sourceOnDemand", request.SourceOnDemand),
 	)
 
@@ -324,6 +605,8 @@ func (s *Server) handlePathAdd(c *gin.Context) {
 		return
 	}
 
+	s.recordAudit(c, audit.ActionStreamCreate, pathName, "source="+secret.MaskURL(request.Source))
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Path added successfully",
@@ -345,6 +628,8 @@ func (s *Server) handlePathDelete(c *gin.Context) {
 		return
 	}
 
+	s.recordAudit(c, audit.ActionStreamDelete, pathName, "")
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Path deleted successfully",
@@ -353,12 +638,17 @@ func (s *Server) handlePathDelete(c *gin.Context) {
 }
 
 // corsMiddleware는 CORS 미들웨어입니다
-func corsMiddleware() gin.HandlerFunc {
+// allowedOrigins에 포함된 Origin만 허용합니다 ("*"이면 전체 허용)
+func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.Request.Header.Get("Origin")
+		if origin != "" && auth.IsOriginAllowed(origin, allowedOrigins) {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, accept, origin, Cache-Control, X-Requested-With")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+			c.Writer.Header().Add("Vary", "Origin")
+		}
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -374,7 +664,7 @@ func loggerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
-		query := c.Request.URL.RawQuery
+		query := redactQuery(c.Request.URL.RawQuery)
 
 		c.Next()
 
@@ -391,16 +681,68 @@ func loggerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
+// remoteAddrMiddleware는 c.ClientIP()를 request context에 저장합니다
+// gin.WrapF로 감싼 핸들러(WebSocket 시그널링)도 신뢰 프록시를 반영한 클라이언트 IP를 사용하도록 함
+func remoteAddrMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(audit.WithRemoteAddr(c.Request.Context(), c.ClientIP()))
+		c.Next()
+	}
+}
+
+// redactQuery는 로그에 남기지 않을 인증 관련 쿼리 값(token, api_key, jwt)을 가립니다
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	redacted := false
+	for _, key := range []string{"token", "api_key", "jwt"} {
+		if values.Has(key) {
+			values.Set(key, secret.MaskedPassword)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+
+	return values.Encode()
+}
+
+// currentHLS는 현재 HLS 관리자를 반환합니다 (비활성화면 nil)
+func (s *Server) currentHLS() *hls.Manager {
+	return s.hlsManager.Load()
+}
+
+// SetHLSManager는 HLS 관리자를 교체합니다 (설정 다시 로드, nil이면 HLS API 비활성화)
+func (s *Server) SetHLSManager(manager *hls.Manager) {
+	s.hlsManager.Store(manager)
+}
+
 // handleHLSStreamsList는 모든 HLS 스트림 목록을 반환합니다
 func (s *Server) handleHLSStreamsList(c *gin.Context) {
-	if s.hlsManager == nil || !s.hlsManager.IsEnabled() {
+	hlsManager := s.currentHLS()
+	if hlsManager == nil || !hlsManager.IsEnabled() {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"error": "HLS is not enabled",
 		})
 		return
 	}
 
-	streams := s.hlsManager.GetAllStreams()
+	identity := identityFromContext(c)
+	all := hlsManager.GetAllStreams()
+	streams := make([]string, 0, len(all))
+	for _, streamID := range all {
+		if identity.CanView(streamID) {
+			streams = append(streams, streamID)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"streams": streams,
@@ -412,14 +754,15 @@ func (s *Server) handleHLSStreamsList(c *gin.Context) {
 func (s *Server) handleHLSStreamInfo(c *gin.Context) {
 	streamID := c.Param("id")
 
-	if s.hlsManager == nil || !s.hlsManager.IsEnabled() {
+	hlsManager := s.currentHLS()
+	if hlsManager == nil || !hlsManager.IsEnabled() {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"error": "HLS is not enabled handleHLSStreamInfo",
 		})
 		return
 	}
 
-	info, err := s.hlsManager.GetStreamInfo(streamID)
+	info, err := hlsManager.GetStreamInfo(streamID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": fmt.Sprintf("Stream %s not found", streamID),
@@ -434,14 +777,15 @@ func (s *Server) handleHLSStreamInfo(c *gin.Context) {
 func (s *Server) handleHLSStreamStats(c *gin.Context) {
 	streamID := c.Param("id")
 
-	if s.hlsManager == nil || !s.hlsManager.IsEnabled() {
+	hlsManager := s.currentHLS()
+	if hlsManager == nil || !hlsManager.IsEnabled() {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"error": "HLS is not enabled",
 		})
 		return
 	}
 
-	stats, err := s.hlsManager.GetStats(streamID)
+	stats, err := hlsManager.GetStats(streamID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": fmt.Sprintf("Stream %s not found", streamID),
@@ -456,14 +800,15 @@ func (s *Server) handleHLSStreamStats(c *gin.Context) {
 func (s *Server) handleHLSPlaylist(c *gin.Context) {
 	streamID := c.Param("streamId")
 
-	if s.hlsManager == nil || !s.hlsManager.IsEnabled() {
+	hlsManager := s.currentHLS()
+	if hlsManager == nil || !hlsManager.IsEnabled() {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "HLS is not enabled handleHLSPlaylist" + fmt.Sprintf("%v", s.hlsManager) + fmt.Sprintf("%v", s.hlsManager.IsEnabled()),
+			"error": "HLS is not enabled handleHLSPlaylist" + fmt.Sprintf("%v", hlsManager) + fmt.Sprintf("%v", hlsManager.IsEnabled()),
 		})
 		return
 	}
 
-	muxer, exists := s.hlsManager.GetMuxer(streamID)
+	muxer, exists := hlsManager.GetMuxer(streamID)
 	if !exists {
 		// Muxer가 없으면 스트림을 자동으로 시작
 		s.logger.Info("HLS Muxer not found, attempting to start stream",
@@ -487,7 +832,7 @@ func (s *Server) handleHLSPlaylist(c *gin.Context) {
 			for i := 0; i < maxRetries; i++ {
 				time.Sleep(retryInterval)
 
-				muxer, exists = s.hlsManager.GetMuxer(streamID)
+				muxer, exists = hlsManager.GetMuxer(streamID)
 				if exists {
 					s.logger.Info("HLS Muxer ready after retry",
 						zap.String("stream_id", streamID),
@@ -522,21 +867,22 @@ func (s *Server) handleHLSPlaylist(c *gin.Context) {
 	}
 
 	// gohlslib muxer의 Handle 메서드로 요청 전달
-	muxer.Handle(c.Writer, c.Request)
+	s.serveHLS(c, muxer)
 }
 
 // handleHLSSegment는 TS 세그먼트를 서빙합니다
 func (s *Server) handleHLSSegment(c *gin.Context) {
 	streamID := c.Param("streamId")
 
-	if s.hlsManager == nil || !s.hlsManager.IsEnabled() {
+	hlsManager := s.currentHLS()
+	if hlsManager == nil || !hlsManager.IsEnabled() {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"error": "HLS is not enabled",
 		})
 		return
 	}
 
-	muxer, exists := s.hlsManager.GetMuxer(streamID)
+	muxer, exists := hlsManager.GetMuxer(streamID)
 	if !exists {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": fmt.Sprintf("Stream %s not found", streamID),
@@ -545,7 +891,7 @@ func (s *Server) handleHLSSegment(c *gin.Context) {
 	}
 
 	// gohlslib muxer의 Handle 메서드로 요청 전달
-	muxer.Handle(c.Writer, c.Request)
+	s.serveHLS(c, muxer)
 }
 
 // ====================================================================================
@@ -567,20 +913,52 @@ func (s *Server) handleCreateStream(c *gin.Context) {
 	if request.ID == "" {
 		request.ID = request.Name
 	}
+	// 출처는 config.yaml 동기화에서만 설정
+	request.Origin = ""
+
+	// 구분자는 트랜스코딩 파생 스트림 ID(CAM1~h264_720p)에 예약됨
+	if strings.Contains(request.ID, core.DerivedStreamSeparator) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Stream ID must not contain '%s' (reserved for transcoded streams)", core.DerivedStreamSeparator),
+		})
+		return
+	}
 
 	// 기본값 설정
 	if request.RTSPTransport == "" {
 		request.RTSPTransport = "tcp"
 	}
 
+	if request.Source != "" {
+		if err := source.Validate(request.Source); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid source: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := validateBackupSources(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := s.validateStreamLabels(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	s.logger.Info("Creating new stream",
 		zap.String("id", request.ID),
 		zap.String("name", request.Name),
 		zap.Bool("source_on_demand", request.SourceOnDemand),
 	)
 
-	// 1. Database에 저장 (Single Source of Truth)
-	if err := s.streamRepo.Create(&request); err != nil {
+	if err := s.createStream(c, &request); err != nil {
 		s.logger.Error("Failed to create stream", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to create stream: " + err.Error(),
@@ -588,70 +966,125 @@ func (s *Server) handleCreateStream(c *gin.Context) {
 		return
 	}
 
+	request.Source = secret.MaskURL(request.Source)
+	request.BackupSources = responseBackupSources(request.BackupSources, false)
+	c.JSON(http.StatusCreated, request)
+}
+
+// createStream은 스트림을 DB와 StreamManager에 등록하고, 상시 스트림이면 소스를 시작합니다
+// DB 저장 이후의 실패는 로그만 남기고 성공으로 처리합니다
+func (s *Server) createStream(c *gin.Context, stream *database.Stream) error {
+	// 1. Database에 저장 (Single Source of Truth)
+	if err := s.streamRepo.Create(stream); err != nil {
+		return err
+	}
+
+	// 사이트 동시 연결 제한이 시작 전에 적용되도록 먼저 반영
+	if stream.SiteID != "" {
+		s.reloadSites()
+	}
+
+	s.activateStream(c, stream)
+	return nil
+}
+
+// activateStream은 DB에 저장된 새 스트림을 StreamManager에 등록하고, 상시 스트림이면 소스를 시작합니다
+func (s *Server) activateStream(c *gin.Context, stream *database.Stream) {
 	// 2. StreamManager에 Stream 객체 생성 (필수!)
 	if s.streamManager != nil {
-		if _, err := s.streamManager.CreateStream(request.ID, request.Name); err != nil {
+		if _, err := s.streamManager.CreateStream(stream.ID, stream.Name); err != nil {
 			s.logger.Error("Failed to create stream in manager", zap.Error(err))
 			// Database는 이미 저장되었으므로 계속 진행
 		}
 	}
 
 	// 3. sourceOnDemand=false면 즉시 RTSP 클라이언트 시작
-	if !request.SourceOnDemand && s.startStreamHandler != nil {
-		if err := s.startStreamHandler(request.ID); err != nil {
+	if !stream.SourceOnDemand && s.startStreamHandler != nil {
+		if err := s.startStreamHandler(stream.ID); err != nil {
 			s.logger.Error("Failed to start RTSP client for always-on stream",
-				zap.String("id", request.ID),
+				zap.String("id", stream.ID),
 				zap.Error(err),
 			)
-			// 에러가 나도 CREATE는 성공했으므로 201 반환
+			// 에러가 나도 CREATE는 성공으로 처리
 		} else {
-			s.logger.Info("RTSP client started for always-on stream", zap.String("id", request.ID))
+			s.logger.Info("RTSP client started for always-on stream", zap.String("id", stream.ID))
 		}
 	}
 
-	s.logger.Info("Stream created successfully", zap.String("id", request.ID))
+	s.logger.Info("Stream created successfully", zap.String("id", stream.ID))
 
-	c.JSON(http.StatusCreated, request)
+	details := "source=" + secret.MaskURL(stream.Source)
+	if len(stream.BackupSources) > 0 {
+		details += fmt.Sprintf(" backups=%d", len(stream.BackupSources))
+	}
+	if stream.ONVIFAddress != "" {
+		details += " onvif=" + stream.ONVIFAddress
+	}
+	s.recordAudit(c, audit.ActionStreamCreate, stream.ID, details)
 }
 
 // handleListStreams는 모든 스트림 목록을 반환합니다
 func (s *Server) handleListStreams(c *gin.Context) {
-	// Database에서 모든 스트림 가져오기 (Single Source of Truth)
-	dbStreams, err := s.streamRepo.List()
-	if err != nil {
-		s.logger.Error("Failed to list streams from database", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to list streams: " + err.Error(),
-		})
+	reveal, ok := parseReveal(c)
+	if !ok {
+		return
+	}
+
+	// Database에서 조건에 맞는 스트림 가져오기 (Single Source of Truth, viewer는 허용된 스트림만)
+	result, ok := s.queryStreams(c)
+	if !ok {
 		return
 	}
 
 	// 응답 데이터 구성 (runtime_info 추가)
-	streams := make([]gin.H, 0, len(dbStreams))
-	for _, dbStream := range dbStreams {
+	streams := make([]gin.H, 0, len(result.streams))
+	for _, dbStream := range result.streams {
 		streamData := gin.H{
 			"id":               dbStream.ID,
 			"name":             dbStream.Name,
-			"source":           dbStream.Source,
+			"source":           responseSource(dbStream.Source, reveal),
 			"source_on_demand": dbStream.SourceOnDemand,
 			"rtsp_transport":   dbStream.RTSPTransport,
 			"created_at":       dbStream.CreatedAt,
 			"updated_at":       dbStream.UpdatedAt,
 		}
+		if dbStream.ONVIFAddress != "" {
+			streamData["onvif_address"] = dbStream.ONVIFAddress
+			streamData["onvif_profile"] = dbStream.ONVIFProfile
+		}
+		if dbStream.SiteID != "" {
+			streamData["site_id"] = dbStream.SiteID
+		}
+		if dbStream.CredentialsError {
+			streamData["credentials_error"] = true
+		}
+		if dbStream.Origin != "" {
+			streamData["origin"] = dbStream.Origin
+		}
+		if len(dbStream.Tags) > 0 {
+			streamData["tags"] = dbStream.Tags
+		}
+		addBackupInfo(streamData, dbStream, reveal)
 
 		// StreamManager에서 runtime 정보 가져오기 (있으면)
 		if s.streamManager != nil {
 			if runtimeStream, err := s.streamManager.GetStream(dbStream.ID); err == nil {
 				packetsRecv, packetsSent, bytesRecv, bytesSent := runtimeStream.GetStats()
-				streamData["runtime_info"] = gin.H{
+				runtimeInfo := gin.H{
 					"is_active":        true,
+					"ready":            runtimeStream.IsReady(),
 					"codec":            runtimeStream.GetVideoCodec(),
 					"subscriber_count": runtimeStream.GetSubscriberCount(),
 					"packets_received": packetsRecv,
 					"packets_sent":     packetsSent,
 					"bytes_received":   bytesRecv,
 					"bytes_sent":       bytesSent,
+					"bitrate":          s.streamBitrate(dbStream.ID),
+				}
+				if activeSource := s.activeSourceInfo(dbStream.ID, reveal); activeSource != nil {
+					runtimeInfo["active_source"] = activeSource
 				}
+				streamData["runtime_info"] = runtimeInfo
 			}
 		}
 
@@ -659,8 +1092,10 @@ func (s *Server) handleListStreams(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"streams": streams,
-		"count":   len(streams),
+		"streams":   streams,
+		"count":     len(streams),
+		"itemCount": result.itemCount,
+		"pageCount": result.pageCount,
 	})
 }
 
@@ -668,6 +1103,11 @@ func (s *Server) handleListStreams(c *gin.Context) {
 func (s *Server) handleGetStream(c *gin.Context) {
 	streamID := c.Param("id")
 
+	reveal, ok := parseReveal(c)
+	if !ok {
+		return
+	}
+
 	// Database에서 찾기 (Single Source of Truth)
 	dbStream, err := s.streamRepo.Get(streamID)
 	if err != nil {
@@ -681,18 +1121,35 @@ func (s *Server) handleGetStream(c *gin.Context) {
 	response := gin.H{
 		"id":               dbStream.ID,
 		"name":             dbStream.Name,
-		"source":           dbStream.Source,
+		"source":           responseSource(dbStream.Source, reveal),
 		"source_on_demand": dbStream.SourceOnDemand,
 		"rtsp_transport":   dbStream.RTSPTransport,
 		"created_at":       dbStream.CreatedAt,
 		"updated_at":       dbStream.UpdatedAt,
 	}
+	if dbStream.ONVIFAddress != "" {
+		response["onvif_address"] = dbStream.ONVIFAddress
+		response["onvif_profile"] = dbStream.ONVIFProfile
+	}
+	if dbStream.SiteID != "" {
+		response["site_id"] = dbStream.SiteID
+	}
+	if dbStream.CredentialsError {
+		response["credentials_error"] = true
+	}
+	if dbStream.Origin != "" {
+		response["origin"] = dbStream.Origin
+	}
+	if len(dbStream.Tags) > 0 {
+		response["tags"] = dbStream.Tags
+	}
+	addBackupInfo(response, dbStream, reveal)
 
 	// StreamManager에서 runtime 정보 추가 (있으면)
 	if s.streamManager != nil {
 		if runtimeStream, err := s.streamManager.GetStream(streamID); err == nil {
 			packetsRecv, packetsSent, bytesRecv, bytesSent := runtimeStream.GetStats()
-			response["runtime_info"] = gin.H{
+			runtimeInfo := gin.H{
 				"is_active":        true,
 				"codec":            runtimeStream.GetVideoCodec(),
 				"subscriber_count": runtimeStream.GetSubscriberCount(),
@@ -701,6 +1158,10 @@ func (s *Server) handleGetStream(c *gin.Context) {
 				"bytes_received":   bytesRecv,
 				"bytes_sent":       bytesSent,
 			}
+			if activeSource := s.activeSourceInfo(streamID, reveal); activeSource != nil {
+				runtimeInfo["active_source"] = activeSource
+			}
+			response["runtime_info"] = runtimeInfo
 		}
 	}
 
@@ -722,10 +1183,50 @@ func (s *Server) handleUpdateStream(c *gin.Context) {
 	// ID는 URL 파라미터로부터 사용
 	request.ID = streamID
 
+	// 마스킹된 비밀번호(***)가 전달되면 기존 비밀번호 유지
+	// ONVIF 정보와 사이트는 요청에 없으면 기존 값 유지 (태그, 예비 소스는 nil이면 저장소에서 유지)
+	if existing, err := s.streamRepo.Get(streamID); err == nil {
+		request.Source = secret.UnmaskURL(request.Source, existing.Source)
+		request.BackupSources = unmaskBackupSources(request.BackupSources, existing.BackupSources)
+		if request.BackupSources == nil {
+			request.BackupSources = existing.BackupSources
+		}
+		if request.ONVIFAddress == "" {
+			request.ONVIFAddress = existing.ONVIFAddress
+			request.ONVIFProfile = existing.ONVIFProfile
+		}
+		if request.SiteID == "" {
+			request.SiteID = existing.SiteID
+		}
+	}
+
+	if request.Source != "" {
+		if err := source.Validate(request.Source); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid source: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := validateBackupSources(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := s.validateStreamLabels(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	s.logger.Info("Updating stream",
 		zap.String("id", streamID),
 		zap.String("name", request.Name),
-		zap.String("source", request.Source),
+		zap.String("source", secret.MaskURL(request.Source)),
 	)
 
 	// 1. Database 업데이트
@@ -737,6 +1238,18 @@ func (s *Server) handleUpdateStream(c *gin.Context) {
 		return
 	}
 
+	s.recordAudit(c, audit.ActionStreamUpdate, streamID, "source="+secret.MaskURL(request.Source))
+
+	s.reloadSites()
+	s.restartStream(streamID, request.SourceOnDemand)
+
+	request.Source = secret.MaskURL(request.Source)
+	request.BackupSources = responseBackupSources(request.BackupSources, false)
+	c.JSON(http.StatusOK, request)
+}
+
+// restartStream은 설정이 바뀐 스트림의 소스를 정지하고, 상시 스트림이면 다시 시작합니다
+func (s *Server) restartStream(streamID string, sourceOnDemand bool) {
 	// 2. 실행 중인 RTSP 클라이언트 재시작 (source가 변경되었을 경우)
 	// 먼저 정지
 	if s.stopStreamHandler != nil {
@@ -749,7 +1262,7 @@ func (s *Server) handleUpdateStream(c *gin.Context) {
 	}
 
 	// sourceOnDemand=false면 즉시 재시작
-	if !request.SourceOnDemand && s.startStreamHandler != nil {
+	if !sourceOnDemand && s.startStreamHandler != nil {
 		if err := s.startStreamHandler(streamID); err != nil {
 			s.logger.Error("Failed to restart stream after update",
 				zap.String("id", streamID),
@@ -760,8 +1273,6 @@ func (s *Server) handleUpdateStream(c *gin.Context) {
 			s.logger.Info("Stream restarted successfully after update", zap.String("id", streamID))
 		}
 	}
-
-	c.JSON(http.StatusOK, request)
 }
 
 // handleDeleteStream은 스트림을 삭제합니다
@@ -779,6 +1290,17 @@ func (s *Server) handleDeleteStream(c *gin.Context) {
 		return
 	}
 
+	s.recordAudit(c, audit.ActionStreamDelete, streamID, "")
+	s.reloadSites()
+
+	// 스트림 그룹의 화질 목록에서 제거
+	if err := s.groupRepo.RemoveStream(streamID); err != nil {
+		s.logger.Warn("Failed to remove stream from groups",
+			zap.String("id", streamID),
+			zap.Error(err),
+		)
+	}
+
 	// 2. 실행 중인 RTSP 클라이언트 정지 (stopStreamHandler 사용)
 	if s.stopStreamHandler != nil {
 		if err := s.stopStreamHandler(streamID); err != nil {
@@ -823,15 +1345,47 @@ func (s *Server) handleStartStream(c *gin.Context) {
 
 	if err := s.startStreamHandler(streamID); err != nil {
 		s.logger.Error("Failed to start stream", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(startErrorStatus(err), gin.H{
 			"error": "Failed to start stream: " + err.Error(),
 		})
 		return
 	}
 
+	s.recordAudit(c, audit.ActionStreamStart, streamID, "")
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Stream started successfully",
 		"id":      streamID,
 	})
 }
+
+// handleStopStream은 실행 중인 스트림을 정지합니다
+func (s *Server) handleStopStream(c *gin.Context) {
+	streamID := c.Param("id")
+
+	s.logger.Info("Stopping stream", zap.String("id", streamID))
+
+	if s.stopStreamHandler == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Stop stream handler not configured",
+		})
+		return
+	}
+
+	if err := s.stopStreamHandler(streamID); err != nil {
+		s.logger.Error("Failed to stop stream", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to stop stream: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionStreamStop, streamID, "")
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Stream stopped successfully",
+		"id":      streamID,
+	})
+}
@@ -0,0 +1,486 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/site"
+	"go.uber.org/zap"
+)
+
+// maxTagLength는 태그 이름의 최대 길이
+const maxTagLength = 64
+
+// startErrorStatus는 스트림 시작 실패의 HTTP 상태 코드를 반환합니다 (사이트 동시 연결 제한은 429)
+func startErrorStatus(err error) int {
+	if errors.Is(err, site.ErrLimitReached) {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
+// validateLabel은 사이트 ID와 태그 이름을 검증합니다 (URL 경로와 쉼표 구분 필터에 쓰임)
+func validateLabel(kind, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", kind)
+	}
+	if strings.ContainsAny(value, "/?#,") {
+		return fmt.Errorf("%s must not contain '/', '?', '#' or ','", kind)
+	}
+	return nil
+}
+
+// normalizeTags는 태그 앞뒤 공백을 제거하고 중복을 없애 정렬합니다 (nil은 그대로 nil)
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if err := validateLabel("tag", tag); err != nil {
+			return nil, err
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag must be at most %d characters: %s", maxTagLength, tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// validateStreamLabels는 스트림의 사이트가 존재하는지 확인하고 태그를 정리합니다
+func (s *Server) validateStreamLabels(stream *database.Stream) error {
+	tags, err := normalizeTags(stream.Tags)
+	if err != nil {
+		return fmt.Errorf("Invalid tags: %w", err)
+	}
+	stream.Tags = tags
+
+	if stream.SiteID == "" {
+		return nil
+	}
+	exists, err := s.siteRepo.Exists(stream.SiteID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Site %s not found", stream.SiteID)
+	}
+	return nil
+}
+
+// reloadSites는 사이트 소속이 바뀐 뒤 사이트 관리자의 캐시를 다시 읽습니다
+func (s *Server) reloadSites() {
+	if s.siteManager == nil {
+		return
+	}
+	if err := s.siteManager.Reload(); err != nil {
+		s.logger.Error("Failed to reload sites", zap.Error(err))
+	}
+}
+
+// viewableSiteStreams는 사이트에 속한 스트림 중 시청 가능한 스트림과 사이트 표시 여부를 반환합니다
+// 사이트 권한이 있거나 시청 가능한 스트림이 하나라도 있으면 표시합니다
+func (s *Server) viewableSiteStreams(siteID string, identity *auth.Identity) ([]string, bool) {
+	streams := s.siteManager.Streams(siteID)
+	if identity.CanViewSite(siteID) {
+		return streams, true
+	}
+
+	viewable := make([]string, 0, len(streams))
+	for _, streamID := range streams {
+		if identity.CanView(streamID) {
+			viewable = append(viewable, streamID)
+		}
+	}
+	return viewable, len(viewable) > 0
+}
+
+// siteResponse는 사이트 응답을 만듭니다 (스트림 수와 연결 중인 스트림 수 포함)
+func (s *Server) siteResponse(entry *database.Site, streams []string) gin.H {
+	online := 0
+	if s.streamManager != nil {
+		for _, streamID := range streams {
+			if stream, err := s.streamManager.GetStream(streamID); err == nil && stream.IsReady() {
+				online++
+			}
+		}
+	}
+
+	return gin.H{
+		"id":           entry.ID,
+		"name":         entry.Name,
+		"description":  entry.Description,
+		"max_streams":  entry.MaxStreams,
+		"stream_count": len(streams),
+		"online":       online,
+		"created_at":   entry.CreatedAt,
+		"updated_at":   entry.UpdatedAt,
+	}
+}
+
+// handleListSites는 사이트 목록을 조회합니다 (viewer는 시청 가능한 스트림이 있는 사이트만)
+// GET /api/v1/sites
+func (s *Server) handleListSites(c *gin.Context) {
+	list, err := s.siteRepo.List()
+	if err != nil {
+		s.logger.Error("Failed to list sites", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list sites: " + err.Error(),
+		})
+		return
+	}
+
+	identity := identityFromContext(c)
+	result := make([]gin.H, 0, len(list))
+	for _, entry := range list {
+		streams, ok := s.viewableSiteStreams(entry.ID, identity)
+		if !ok {
+			continue
+		}
+		result = append(result, s.siteResponse(entry, streams))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sites": result,
+		"count": len(result),
+	})
+}
+
+// handleGetSite는 사이트와 소속 스트림 ID 목록을 조회합니다
+// GET /api/v1/sites/:id
+func (s *Server) handleGetSite(c *gin.Context) {
+	siteID := c.Param("id")
+
+	entry, err := s.siteRepo.Get(siteID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Site %s not found", siteID),
+		})
+		return
+	}
+
+	streams, ok := s.viewableSiteStreams(siteID, identityFromContext(c))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": fmt.Sprintf("Access to site %s is not allowed", siteID),
+		})
+		return
+	}
+
+	response := s.siteResponse(entry, streams)
+	response["streams"] = streams
+	c.JSON(http.StatusOK, response)
+}
+
+// bindSite는 사이트 생성/수정 요청을 읽고 검증합니다
+func bindSite(c *gin.Context, entry *database.Site) bool {
+	if err := c.ShouldBindJSON(entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return false
+	}
+
+	if err := validateLabel("id", entry.ID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid site: " + err.Error(),
+		})
+		return false
+	}
+	if entry.Name == "" {
+		entry.Name = entry.ID
+	}
+	if entry.MaxStreams < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid site: max_streams must not be negative",
+		})
+		return false
+	}
+
+	return true
+}
+
+// handleCreateSite는 사이트를 생성합니다
+// POST /api/v1/sites {"id":"JEJU1","name":"제주 1","max_streams":8}
+func (s *Server) handleCreateSite(c *gin.Context) {
+	var entry database.Site
+	if !bindSite(c, &entry) {
+		return
+	}
+
+	exists, err := s.siteRepo.Exists(entry.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create site: " + err.Error(),
+		})
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("Site %s already exists", entry.ID),
+		})
+		return
+	}
+
+	if err := s.siteRepo.Create(&entry); err != nil {
+		s.logger.Error("Failed to create site", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create site: " + err.Error(),
+		})
+		return
+	}
+	s.reloadSites()
+
+	s.recordAudit(c, audit.ActionSiteCreate, "", fmt.Sprintf("id=%s max_streams=%d", entry.ID, entry.MaxStreams))
+
+	c.JSON(http.StatusCreated, s.siteResponse(&entry, nil))
+}
+
+// handleUpdateSite는 사이트의 이름, 설명, 동시 연결 제한을 수정합니다
+// 제한을 낮춰도 이미 연결된 스트림은 끊지 않고 새로 시작할 때부터 적용됩니다
+// PUT /api/v1/sites/:id
+func (s *Server) handleUpdateSite(c *gin.Context) {
+	entry := database.Site{ID: c.Param("id")}
+	if !bindSite(c, &entry) {
+		return
+	}
+	entry.ID = c.Param("id")
+
+	existing, err := s.siteRepo.Get(entry.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Site %s not found", entry.ID),
+		})
+		return
+	}
+	entry.CreatedAt = existing.CreatedAt
+
+	if err := s.siteRepo.Update(&entry); err != nil {
+		s.logger.Error("Failed to update site", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update site: " + err.Error(),
+		})
+		return
+	}
+	s.reloadSites()
+
+	s.recordAudit(c, audit.ActionSiteUpdate, "", fmt.Sprintf("id=%s max_streams=%d", entry.ID, entry.MaxStreams))
+
+	c.JSON(http.StatusOK, s.siteResponse(&entry, s.siteManager.Streams(entry.ID)))
+}
+
+// handleDeleteSite는 사이트를 삭제합니다 (소속 스트림은 삭제하지 않고 사이트 지정만 해제)
+// DELETE /api/v1/sites/:id
+func (s *Server) handleDeleteSite(c *gin.Context) {
+	siteID := c.Param("id")
+
+	if err := s.siteRepo.Delete(siteID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	s.reloadSites()
+
+	s.recordAudit(c, audit.ActionSiteDelete, "", "id="+siteID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Site deleted successfully",
+		"id":      siteID,
+	})
+}
+
+// handleAssignSiteStreams는 스트림들을 사이트에 지정합니다 (다른 사이트에 있으면 옮김)
+// POST /api/v1/sites/:id/streams {"streams":["CCTV-JEJU1-31","CCTV-JEJU1-32"]}
+func (s *Server) handleAssignSiteStreams(c *gin.Context) {
+	siteID := c.Param("id")
+
+	var request struct {
+		Streams []string `json:"streams" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	exists, err := s.siteRepo.Exists(siteID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to assign streams: " + err.Error(),
+		})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Site %s not found", siteID),
+		})
+		return
+	}
+
+	if err := s.siteRepo.AssignStreams(siteID, request.Streams); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to assign streams: " + err.Error(),
+		})
+		return
+	}
+	s.reloadSites()
+
+	for _, streamID := range request.Streams {
+		s.recordAudit(c, audit.ActionStreamUpdate, streamID, "site="+siteID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      siteID,
+		"streams": s.siteManager.Streams(siteID),
+	})
+}
+
+// handleUnassignSiteStream은 스트림의 사이트 지정을 해제합니다
+// DELETE /api/v1/sites/:id/streams/:streamId
+func (s *Server) handleUnassignSiteStream(c *gin.Context) {
+	siteID, streamID := c.Param("id"), c.Param("streamId")
+
+	if err := s.siteRepo.UnassignStream(siteID, streamID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	s.reloadSites()
+
+	s.recordAudit(c, audit.ActionStreamUpdate, streamID, "site=")
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      siteID,
+		"streams": s.siteManager.Streams(siteID),
+	})
+}
+
+// siteStreamsForOperation은 일괄 작업 대상 사이트의 스트림을 반환합니다 (없으면 404 응답 후 ok=false)
+func (s *Server) siteStreamsForOperation(c *gin.Context) (string, []string, bool) {
+	siteID := c.Param("id")
+
+	exists, err := s.siteRepo.Exists(siteID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return "", nil, false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Site %s not found", siteID),
+		})
+		return "", nil, false
+	}
+
+	return siteID, s.siteManager.Streams(siteID), true
+}
+
+// handleStartSite는 사이트의 모든 스트림 소스를 시작합니다
+// 동시 연결 제한에 걸린 스트림은 status=limited로 보고하고 나머지는 계속 시작합니다
+// POST /api/v1/sites/:id/start
+func (s *Server) handleStartSite(c *gin.Context) {
+	if s.startStreamHandler == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Start stream handler not configured",
+		})
+		return
+	}
+
+	siteID, streams, ok := s.siteStreamsForOperation(c)
+	if !ok {
+		return
+	}
+
+	results := make([]gin.H, 0, len(streams))
+	counts := map[string]int{}
+	for _, streamID := range streams {
+		result := gin.H{"id": streamID, "status": "started"}
+		if err := s.startStreamHandler(streamID); err != nil {
+			result["status"] = "failed"
+			if errors.Is(err, site.ErrLimitReached) {
+				result["status"] = "limited"
+			}
+			result["error"] = err.Error()
+		}
+		counts[result["status"].(string)]++
+		results = append(results, result)
+	}
+
+	s.logger.Info("Site streams started",
+		zap.String("site_id", siteID),
+		zap.Int("started", counts["started"]),
+		zap.Int("limited", counts["limited"]),
+		zap.Int("failed", counts["failed"]),
+	)
+	s.recordAudit(c, audit.ActionSiteStart, "",
+		fmt.Sprintf("id=%s started=%d limited=%d failed=%d", siteID, counts["started"], counts["limited"], counts["failed"]))
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      siteID,
+		"started": counts["started"],
+		"limited": counts["limited"],
+		"failed":  counts["failed"],
+		"results": results,
+	})
+}
+
+// handleStopSite는 사이트의 모든 스트림을 정지합니다 (실행 중이 아닌 스트림은 건너뜀)
+// POST /api/v1/sites/:id/stop
+func (s *Server) handleStopSite(c *gin.Context) {
+	if s.stopStreamHandler == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Stop stream handler not configured",
+		})
+		return
+	}
+
+	siteID, streams, ok := s.siteStreamsForOperation(c)
+	if !ok {
+		return
+	}
+
+	results := make([]gin.H, 0, len(streams))
+	stopped := 0
+	for _, streamID := range streams {
+		result := gin.H{"id": streamID, "status": "stopped"}
+		if err := s.stopStreamHandler(streamID); err != nil {
+			result["status"] = "not_running"
+			result["error"] = err.Error()
+		} else {
+			stopped++
+		}
+		results = append(results, result)
+	}
+
+	s.logger.Info("Site streams stopped",
+		zap.String("site_id", siteID),
+		zap.Int("stopped", stopped),
+	)
+	s.recordAudit(c, audit.ActionSiteStop, "", fmt.Sprintf("id=%s stopped=%d", siteID, stopped))
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      siteID,
+		"stopped": stopped,
+		"results": results,
+	})
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/snapshot"
+	"go.uber.org/zap"
+)
+
+// maxSnapshotSize는 스냅샷 width/height 최대값
+const maxSnapshotSize = 3840
+
+// handleSnapshot은 스트림의 마지막 키프레임으로 만든 JPEG 스냅샷을 반환합니다
+// GET /api/v1/streams/:id/snapshot.jpg?width=&height=
+// width/height는 선택이며 원본 비율을 유지하며 그 안에 들어가도록 줄입니다 (확대하지 않음)
+func (s *Server) handleSnapshot(c *gin.Context) {
+	if s.snapshotManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Snapshot is not enabled",
+		})
+		return
+	}
+
+	streamID := c.Param("id")
+
+	var size [2]int
+	for i, name := range []string{"width", "height"} {
+		value, err := queryInt(c, name, 0)
+		if err != nil || value < 0 || value > maxSnapshotSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid %s (0-%d): %s", name, maxSnapshotSize, c.Query(name)),
+			})
+			return
+		}
+		size[i] = value
+	}
+
+	snap, err := s.snapshotManager.Get(c.Request.Context(), streamID, size[0], size[1])
+	if err != nil {
+		switch {
+		case errors.Is(err, snapshot.ErrNoSnapshot):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("No snapshot available for stream %s (not running or no keyframe yet)", streamID),
+			})
+		case errors.Is(err, snapshot.ErrDecoderNotConfigured):
+			c.JSON(http.StatusNotImplemented, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			s.logger.Warn("Failed to create snapshot",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to create snapshot: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	// Last-Modified/If-Modified-Since로 갱신되지 않은 스냅샷은 304 응답
+	c.Header("Cache-Control", "no-cache")
+	http.ServeContent(c.Writer, c.Request, "snapshot.jpg", snap.Time, bytes.NewReader(snap.Data))
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/status"
+	"go.uber.org/zap"
+)
+
+// statusHeartbeatInterval은 SSE 연결 유지용 주석 전송 주기 (프록시 유휴 타임아웃 방지)
+const statusHeartbeatInterval = 15 * time.Second
+
+// splitQueryList는 쉼표로 구분된 쿼리 값을 집합으로 변환합니다 (비어있으면 nil)
+func splitQueryList(value string) map[string]struct{} {
+	var set map[string]struct{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			if set == nil {
+				set = make(map[string]struct{})
+			}
+			set[item] = struct{}{}
+		}
+	}
+	return set
+}
+
+// handleStatusEvents는 스트림 상태 변화를 Server-Sent Events로 보냅니다
+// 연결 직후 스트림별 현재 상태를 보낸 뒤 소스 연결/끊김, 코덱 감지, 구독자 수 변화, 주기적 비트레이트를 보냅니다
+// GET /api/v1/events?streams=CAM1,CAM2&types=stream.state,stream.bitrate
+func (s *Server) handleStatusEvents(c *gin.Context) {
+	if s.statusManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Status events are not enabled",
+		})
+		return
+	}
+
+	identity := identityFromContext(c)
+	streams := splitQueryList(c.Query("streams"))
+	types := splitQueryList(c.Query("types"))
+
+	// 서버 WriteTimeout이 장기 연결을 끊지 않도록 쓰기 기한 해제
+	controller := http.NewResponseController(c.Writer)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		s.logger.Warn("Failed to clear write deadline for status events", zap.Error(err))
+	}
+
+	sub := s.statusManager.Subscribe(func(streamID string) bool {
+		if streams != nil {
+			if _, ok := streams[streamID]; !ok {
+				return false
+			}
+		}
+		return identity.CanView(streamID)
+	})
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx 응답 버퍼링 해제
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(statusHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+
+		case event, ok := <-sub.Events():
+			if !ok {
+				// 서버 종료
+				return
+			}
+			if types != nil {
+				if _, ok := types[event.Type]; !ok {
+					continue
+				}
+			}
+			if err := writeStatusEvent(c.Writer, event); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeStatusEvent는 상태 이벤트를 SSE 형식(event: 종류, data: JSON)으로 씁니다
+func writeStatusEvent(w gin.ResponseWriter, event status.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/secret"
+	"github.com/yourusername/cctv3/internal/source"
+)
+
+// maxBackupSources는 스트림 하나에 지정할 수 있는 예비 소스 수
+const maxBackupSources = 8
+
+// validateBackupSources는 예비 소스 목록과 주 소스 복귀 대기 시간을 검증합니다
+func validateBackupSources(stream *database.Stream) error {
+	if stream.FailbackAfter < 0 {
+		return fmt.Errorf("failback_after must not be negative")
+	}
+	if len(stream.BackupSources) == 0 {
+		return nil
+	}
+	if stream.Source == "" {
+		return fmt.Errorf("backup_sources requires source")
+	}
+	if len(stream.BackupSources) > maxBackupSources {
+		return fmt.Errorf("too many backup_sources (max %d)", maxBackupSources)
+	}
+
+	seen := map[string]bool{stream.Source: true}
+	for i, backup := range stream.BackupSources {
+		if err := source.Validate(backup); err != nil {
+			return fmt.Errorf("invalid backup source %d: %w", i+1, err)
+		}
+		if seen[backup] {
+			return fmt.Errorf("backup source %d duplicates another source", i+1)
+		}
+		seen[backup] = true
+	}
+	return nil
+}
+
+// unmaskBackupSources는 예비 소스의 마스킹된 비밀번호를 기존 예비 소스의 비밀번호로 복원합니다
+// 같은 호스트/사용자의 기존 예비 소스를 우선하고, 없으면 같은 순번의 예비 소스를 사용합니다
+func unmaskBackupSources(backups, existing []string) []string {
+	if backups == nil {
+		return nil
+	}
+
+	result := make([]string, len(backups))
+	for i, backup := range backups {
+		previous := ""
+		if i < len(existing) {
+			previous = existing[i]
+		}
+		for _, candidate := range existing {
+			if sameSourceAccount(backup, candidate) {
+				previous = candidate
+				break
+			}
+		}
+		result[i] = secret.UnmaskURL(backup, previous)
+	}
+	return result
+}
+
+// sameSourceAccount는 두 source URL의 호스트와 사용자 이름이 같은지 확인합니다
+func sameSourceAccount(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil || ua.User == nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil || ub.User == nil {
+		return false
+	}
+	return ua.Host == ub.Host && ua.User.Username() == ub.User.Username()
+}
+
+// responseBackupSources는 응답에 포함할 예비 소스 목록을 반환합니다 (reveal이 아니면 비밀번호 마스킹)
+func responseBackupSources(backups []string, reveal bool) []string {
+	if backups == nil {
+		return nil
+	}
+
+	result := make([]string, len(backups))
+	for i, backup := range backups {
+		result[i] = responseSource(backup, reveal)
+	}
+	return result
+}
+
+// addBackupInfo는 스트림 응답에 예비 소스와 주 소스 복귀 대기 시간을 추가합니다
+func addBackupInfo(data gin.H, stream *database.Stream, reveal bool) {
+	if len(stream.BackupSources) > 0 {
+		data["backup_sources"] = responseBackupSources(stream.BackupSources, reveal)
+	}
+	if stream.FailbackAfter > 0 {
+		data["failback_after"] = stream.FailbackAfter
+	}
+}
+
+// activeSourceInfo는 예비 소스가 있는 실행 중인 스트림의 활성 소스 정보를 반환합니다 (없으면 nil)
+func (s *Server) activeSourceInfo(streamID string, reveal bool) gin.H {
+	if s.sourceStatusHandler == nil {
+		return nil
+	}
+
+	status, ok := s.sourceStatusHandler(streamID)
+	if !ok {
+		return nil
+	}
+
+	info := gin.H{
+		"index":     status.Active,
+		"url":       responseSource(status.URL, reveal),
+		"connected": status.Connected,
+		"failovers": status.Failovers,
+		"failbacks": status.Failbacks,
+		"probing":   status.Probing,
+	}
+	if !status.Since.IsZero() {
+		info["since"] = status.Since
+	}
+	return info
+}
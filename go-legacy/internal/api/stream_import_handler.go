@@ -0,0 +1,659 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/secret"
+	"github.com/yourusername/cctv3/internal/source"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// 가져오기/내보내기 형식
+const (
+	streamFormatCSV  = "csv"
+	streamFormatYAML = "yaml"
+	streamFormatJSON = "json"
+)
+
+// 기존 스트림과 ID가 겹칠 때의 처리 (?on_conflict=)
+const (
+	importConflictError  = "error"  // 오류로 보고 (기본)
+	importConflictSkip   = "skip"   // 기존 스트림 유지
+	importConflictUpdate = "update" // 파일 내용으로 덮어쓰기
+)
+
+// 행별 처리 결과
+const (
+	importActionCreate = "create"
+	importActionUpdate = "update"
+	importActionSkip   = "skip"
+	importActionError  = "error"
+)
+
+// maxImportSize는 가져오기 요청 본문의 최대 크기
+const maxImportSize = 8 << 20
+
+// streamCSVHeader는 CSV 열 순서 (내보내기 헤더, 가져오기 시 열 이름으로 매칭)
+// tags는 한 필드에 쉼표로, backup_sources는 공백으로 구분합니다 (예: "outdoor,ptz", "rtsp://a/1 rtsp://b/1")
+var streamCSVHeader = []string{"id", "name", "source", "source_on_demand", "rtsp_transport", "onvif_address", "onvif_profile", "site_id", "tags", "backup_sources", "failback_after"}
+
+// streamPathEntry는 YAML paths: 형식의 한 경로입니다 (config.yaml의 paths와 같은 키, name/ONVIF는 추가 키)
+type streamPathEntry struct {
+	Name           string   `yaml:"name,omitempty"`
+	Source         string   `yaml:"source"`
+	SourceOnDemand bool     `yaml:"sourceOnDemand"`
+	RTSPTransport  string   `yaml:"rtspTransport,omitempty"`
+	ONVIFAddress   string   `yaml:"onvifAddress,omitempty"`
+	ONVIFProfile   string   `yaml:"onvifProfile,omitempty"`
+	Site           string   `yaml:"site,omitempty"`
+	Tags           []string `yaml:"tags,omitempty"`
+	BackupSources  []string `yaml:"backupSources,omitempty"`
+	FailbackAfter  string   `yaml:"failbackAfter,omitempty"`
+}
+
+// importRow는 파싱한 가져오기 행입니다 (parseErr가 있으면 stream은 nil이거나 ID만 있음)
+type importRow struct {
+	stream   *database.Stream
+	parseErr error
+}
+
+// importRowResult는 행별 검증/반영 결과입니다 (row는 1부터, CSV는 헤더 제외)
+type importRowResult struct {
+	Row    int    `json:"row"`
+	ID     string `json:"id,omitempty"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// streamFormat은 ?format= 또는 Content-Type으로 형식을 정합니다 (기본 JSON)
+func streamFormat(c *gin.Context) (string, error) {
+	if format := c.Query("format"); format != "" {
+		switch format {
+		case streamFormatCSV, streamFormatYAML, streamFormatJSON:
+			return format, nil
+		case "yml":
+			return streamFormatYAML, nil
+		}
+		return "", fmt.Errorf("invalid format (csv, yaml, json): %s", format)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return streamFormatCSV, nil
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return streamFormatYAML, nil
+	}
+	return streamFormatJSON, nil
+}
+
+// parseImportCSV는 헤더가 있는 CSV를 파싱합니다 (열 순서는 자유, 모르는 열은 오류)
+func parseImportCSV(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		known := false
+		for _, column := range streamCSVHeader {
+			if name == column {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown CSV column: %s", name)
+		}
+		if _, dup := columns[name]; dup {
+			return nil, fmt.Errorf("duplicate CSV column: %s", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		if _, ok := columns["name"]; !ok {
+			return nil, fmt.Errorf("CSV header must contain id or name")
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// 따옴표 오류 등은 해당 행만 오류로 보고하고 계속 읽음
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, importRow{parseErr: err})
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if len(record) != len(header) {
+			rows = append(rows, importRow{parseErr: fmt.Errorf("expected %d fields, got %d", len(header), len(record))})
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		stream := &database.Stream{
+			ID:            field("id"),
+			Name:          field("name"),
+			Source:        field("source"),
+			RTSPTransport: field("rtsp_transport"),
+			ONVIFAddress:  field("onvif_address"),
+			ONVIFProfile:  field("onvif_profile"),
+			SiteID:        field("site_id"),
+		}
+		// tags 열이 없으면 nil (수정 시 기존 태그 유지), 빈 값이면 태그 제거
+		if _, ok := columns["tags"]; ok {
+			stream.Tags = []string{}
+			if value := field("tags"); value != "" {
+				stream.Tags = strings.Split(value, ",")
+			}
+		}
+		// backup_sources도 열이 없으면 nil (수정 시 기존 예비 소스 유지)
+		if _, ok := columns["backup_sources"]; ok {
+			stream.BackupSources = strings.Fields(field("backup_sources"))
+			if stream.BackupSources == nil {
+				stream.BackupSources = []string{}
+			}
+		}
+		if value := field("source_on_demand"); value != "" {
+			onDemand, err := strconv.ParseBool(value)
+			if err != nil {
+				rows = append(rows, importRow{parseErr: fmt.Errorf("invalid source_on_demand: %s", value)})
+				continue
+			}
+			stream.SourceOnDemand = onDemand
+		}
+		if value := field("failback_after"); value != "" {
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				rows = append(rows, importRow{parseErr: fmt.Errorf("invalid failback_after: %s", value)})
+				continue
+			}
+			stream.FailbackAfter = seconds
+		}
+
+		rows = append(rows, importRow{stream: stream})
+	}
+
+	return rows, nil
+}
+
+// parseImportYAML은 config.yaml과 같은 paths: 형식을 파싱합니다 (문서 순서 유지)
+func parseImportYAML(data []byte) ([]importRow, error) {
+	var doc struct {
+		Paths yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if doc.Paths.Kind == 0 {
+		return nil, fmt.Errorf("YAML must contain a paths: section")
+	}
+	if doc.Paths.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("paths: must be a mapping of path name to settings")
+	}
+
+	rows := make([]importRow, 0, len(doc.Paths.Content)/2)
+	for i := 0; i+1 < len(doc.Paths.Content); i += 2 {
+		id := doc.Paths.Content[i].Value
+
+		var entry streamPathEntry
+		if err := doc.Paths.Content[i+1].Decode(&entry); err != nil {
+			rows = append(rows, importRow{stream: &database.Stream{ID: id}, parseErr: err})
+			continue
+		}
+
+		var failbackAfter time.Duration
+		if entry.FailbackAfter != "" {
+			d, err := time.ParseDuration(entry.FailbackAfter)
+			if err != nil {
+				rows = append(rows, importRow{stream: &database.Stream{ID: id}, parseErr: fmt.Errorf("invalid failbackAfter: %s", entry.FailbackAfter)})
+				continue
+			}
+			failbackAfter = d
+		}
+
+		rows = append(rows, importRow{stream: &database.Stream{
+			ID:             id,
+			Name:           entry.Name,
+			Source:         entry.Source,
+			SourceOnDemand: entry.SourceOnDemand,
+			RTSPTransport:  entry.RTSPTransport,
+			ONVIFAddress:   entry.ONVIFAddress,
+			ONVIFProfile:   entry.ONVIFProfile,
+			SiteID:         entry.Site,
+			Tags:           entry.Tags,
+			BackupSources:  entry.BackupSources,
+			FailbackAfter:  int(failbackAfter / time.Second),
+		}})
+	}
+
+	return rows, nil
+}
+
+// parseImportJSON은 스트림 생성 요청과 같은 객체의 배열을 파싱합니다
+func parseImportJSON(data []byte) ([]importRow, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid JSON (expected an array of streams): %w", err)
+	}
+
+	rows := make([]importRow, 0, len(items))
+	for _, item := range items {
+		var stream database.Stream
+		if err := json.Unmarshal(item, &stream); err != nil {
+			rows = append(rows, importRow{parseErr: err})
+			continue
+		}
+		rows = append(rows, importRow{stream: &stream})
+	}
+
+	return rows, nil
+}
+
+// validateImportStream은 스트림 생성과 같은 규칙으로 행을 검증하고 기본값을 채웁니다
+func validateImportStream(stream *database.Stream) error {
+	// 출처는 config.yaml 동기화에서만 설정 (내보낸 파일의 값은 무시)
+	stream.Origin = ""
+	if stream.ID == "" {
+		stream.ID = stream.Name
+	}
+	if stream.ID == "" {
+		return fmt.Errorf("id or name is required")
+	}
+	if stream.Name == "" {
+		stream.Name = stream.ID
+	}
+
+	// 구분자는 트랜스코딩 파생 스트림 ID(CAM1~h264_720p)에 예약됨
+	if strings.Contains(stream.ID, core.DerivedStreamSeparator) {
+		return fmt.Errorf("stream ID must not contain '%s' (reserved for transcoded streams)", core.DerivedStreamSeparator)
+	}
+
+	switch stream.RTSPTransport {
+	case "":
+		stream.RTSPTransport = "tcp"
+	case "tcp", "udp":
+	default:
+		return fmt.Errorf("invalid rtsp_transport (tcp, udp): %s", stream.RTSPTransport)
+	}
+
+	if stream.Source != "" {
+		if err := source.Validate(stream.Source); err != nil {
+			return fmt.Errorf("invalid source: %w", err)
+		}
+	}
+
+	return validateBackupSources(stream)
+}
+
+// hasMaskedPassword는 주 소스나 예비 소스에 마스킹된 비밀번호가 있는지 확인합니다
+func hasMaskedPassword(stream *database.Stream) bool {
+	for _, raw := range append([]string{stream.Source}, stream.BackupSources...) {
+		if strings.Contains(raw, ":"+secret.MaskedPassword+"@") {
+			return true
+		}
+	}
+	return false
+}
+
+// handleImportStreams는 CSV, YAML(paths:), JSON 배열로 여러 스트림을 한 번에 추가합니다
+// 모든 행을 먼저 검증하고, 오류가 하나라도 있으면 아무것도 반영하지 않습니다 (DB 반영은 한 트랜잭션)
+// 내보내기 결과의 마스킹된 비밀번호(***)는 on_conflict=update일 때 기존 비밀번호로 복원됩니다
+// POST /api/v1/streams/import?format=csv|yaml|json&dry_run=true&on_conflict=error|skip|update
+func (s *Server) handleImportStreams(c *gin.Context) {
+	format, err := streamFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid dry_run: " + value,
+			})
+			return
+		}
+	}
+
+	onConflict := c.DefaultQuery("on_conflict", importConflictError)
+	switch onConflict {
+	case importConflictError, importConflictSkip, importConflictUpdate:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid on_conflict (error, skip, update): " + onConflict,
+		})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read request body: " + err.Error(),
+		})
+		return
+	}
+
+	var rows []importRow
+	switch format {
+	case streamFormatCSV:
+		rows, err = parseImportCSV(data)
+	case streamFormatYAML:
+		rows, err = parseImportYAML(data)
+	default:
+		rows, err = parseImportJSON(data)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// 1. 행별 검증 (파일 내 중복 ID, 기존 스트림과 충돌 포함)
+	results := make([]importRowResult, len(rows))
+	seen := make(map[string]int, len(rows))
+	var creates, updates []*database.Stream
+	counts := map[string]int{}
+
+	for i, row := range rows {
+		result := &results[i]
+		result.Row = i + 1
+
+		fail := func(err error) {
+			result.Action = importActionError
+			result.Error = err.Error()
+			counts[importActionError]++
+		}
+
+		if row.stream != nil {
+			result.ID = row.stream.ID
+		}
+		if row.parseErr != nil {
+			fail(row.parseErr)
+			continue
+		}
+
+		stream := row.stream
+		if err := validateImportStream(stream); err != nil {
+			fail(err)
+			continue
+		}
+		result.ID = stream.ID
+		if err := s.validateStreamLabels(stream); err != nil {
+			fail(err)
+			continue
+		}
+
+		if first, dup := seen[stream.ID]; dup {
+			fail(fmt.Errorf("duplicate id (same as row %d)", first))
+			continue
+		}
+		seen[stream.ID] = result.Row
+
+		exists, err := s.streamRepo.Exists(stream.ID)
+		if err != nil {
+			s.logger.Error("Failed to check existing stream", zap.String("id", stream.ID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to check existing stream: " + err.Error(),
+			})
+			return
+		}
+
+		if !exists {
+			if hasMaskedPassword(stream) {
+				fail(fmt.Errorf("source has a masked password (export with reveal=true to create new streams)"))
+				continue
+			}
+			result.Action = importActionCreate
+			creates = append(creates, stream)
+			counts[importActionCreate]++
+			continue
+		}
+
+		switch onConflict {
+		case importConflictSkip:
+			result.Action = importActionSkip
+			counts[importActionSkip]++
+		case importConflictUpdate:
+			existing, err := s.streamRepo.Get(stream.ID)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			stream.Source = secret.UnmaskURL(stream.Source, existing.Source)
+			stream.BackupSources = unmaskBackupSources(stream.BackupSources, existing.BackupSources)
+			if stream.ONVIFAddress == "" {
+				stream.ONVIFAddress = existing.ONVIFAddress
+				stream.ONVIFProfile = existing.ONVIFProfile
+			}
+			if stream.SiteID == "" {
+				stream.SiteID = existing.SiteID
+			}
+			result.Action = importActionUpdate
+			updates = append(updates, stream)
+			counts[importActionUpdate]++
+		default:
+			fail(fmt.Errorf("stream already exists"))
+		}
+	}
+
+	report := gin.H{
+		"format":  format,
+		"dry_run": dryRun,
+		"applied": false,
+		"total":   len(rows),
+		"created": counts[importActionCreate],
+		"updated": counts[importActionUpdate],
+		"skipped": counts[importActionSkip],
+		"errors":  counts[importActionError],
+		"rows":    results,
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	if counts[importActionError] > 0 {
+		report["error"] = fmt.Sprintf("Import rejected: %d of %d rows have errors", counts[importActionError], len(rows))
+		c.JSON(http.StatusBadRequest, report)
+		return
+	}
+
+	// 2. DB 반영 (한 트랜잭션, 실패 시 전체 롤백)
+	if err := s.streamRepo.Import(creates, updates); err != nil {
+		s.logger.Error("Failed to import streams", zap.Error(err))
+		report["error"] = "Failed to import streams: " + err.Error()
+		c.JSON(http.StatusInternalServerError, report)
+		return
+	}
+	report["applied"] = true
+	s.reloadSites()
+
+	s.logger.Info("Streams imported",
+		zap.String("format", format),
+		zap.Int("created", len(creates)),
+		zap.Int("updated", len(updates)),
+	)
+	s.recordAudit(c, audit.ActionStreamImport, "",
+		fmt.Sprintf("format=%s created=%d updated=%d skipped=%d", format, len(creates), len(updates), counts[importActionSkip]))
+
+	// 3. 런타임 반영 (DB 반영 이후의 실패는 로그만 남김)
+	for _, stream := range creates {
+		s.activateStream(c, stream)
+	}
+	for _, stream := range updates {
+		s.recordAudit(c, audit.ActionStreamUpdate, stream.ID, "source="+secret.MaskURL(stream.Source))
+		s.restartStream(stream.ID, stream.SourceOnDemand)
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleExportStreams는 스트림 설정을 가져오기와 같은 형식으로 내보냅니다
+// 목록 조회와 같은 필터/정렬을 사용하며, 비밀번호는 ?reveal=true가 아니면 마스킹됩니다
+// GET /api/v1/streams/export?format=csv|yaml|json&reveal=true
+func (s *Server) handleExportStreams(c *gin.Context) {
+	format, err := streamFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	reveal, ok := parseReveal(c)
+	if !ok {
+		return
+	}
+
+	// 기본 정렬은 이름순 (파일 비교가 쉽도록)
+	if c.Query("sort") == "" {
+		query := c.Request.URL.Query()
+		query.Set("sort", database.StreamSortName)
+		c.Request.URL.RawQuery = query.Encode()
+	}
+
+	result, ok := s.queryStreams(c)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("streams_%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	switch format {
+	case streamFormatCSV:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+
+		w := csv.NewWriter(c.Writer)
+		w.Write(streamCSVHeader)
+		for _, stream := range result.streams {
+			w.Write([]string{
+				stream.ID,
+				stream.Name,
+				responseSource(stream.Source, reveal),
+				strconv.FormatBool(stream.SourceOnDemand),
+				stream.RTSPTransport,
+				stream.ONVIFAddress,
+				stream.ONVIFProfile,
+				stream.SiteID,
+				strings.Join(stream.Tags, ","),
+				strings.Join(responseBackupSources(stream.BackupSources, reveal), " "),
+				strconv.Itoa(stream.FailbackAfter),
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			s.logger.Warn("Failed to write stream export", zap.Error(err))
+		}
+
+	case streamFormatYAML:
+		// yaml.Node로 순서를 유지한 paths: 매핑 구성
+		paths := &yaml.Node{Kind: yaml.MappingNode}
+		for _, stream := range result.streams {
+			entry := streamPathEntry{
+				Source:         responseSource(stream.Source, reveal),
+				SourceOnDemand: stream.SourceOnDemand,
+				RTSPTransport:  stream.RTSPTransport,
+				ONVIFAddress:   stream.ONVIFAddress,
+				ONVIFProfile:   stream.ONVIFProfile,
+				Site:           stream.SiteID,
+				Tags:           stream.Tags,
+				BackupSources:  responseBackupSources(stream.BackupSources, reveal),
+			}
+			if stream.FailbackAfter > 0 {
+				entry.FailbackAfter = (time.Duration(stream.FailbackAfter) * time.Second).String()
+			}
+			if stream.Name != stream.ID {
+				entry.Name = stream.Name
+			}
+
+			var value yaml.Node
+			if err := value.Encode(entry); err != nil {
+				s.logger.Error("Failed to encode stream export", zap.String("id", stream.ID), zap.Error(err))
+				continue
+			}
+			paths.Content = append(paths.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: stream.ID}, &value)
+		}
+
+		// config.yaml과 같은 2칸 들여쓰기
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(map[string]*yaml.Node{"paths": paths}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to encode export: " + err.Error(),
+			})
+			return
+		}
+		encoder.Close()
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", buf.Bytes())
+
+	default:
+		streams := make([]gin.H, 0, len(result.streams))
+		for _, stream := range result.streams {
+			item := gin.H{
+				"id":               stream.ID,
+				"name":             stream.Name,
+				"source":           responseSource(stream.Source, reveal),
+				"source_on_demand": stream.SourceOnDemand,
+				"rtsp_transport":   stream.RTSPTransport,
+			}
+			if stream.ONVIFAddress != "" {
+				item["onvif_address"] = stream.ONVIFAddress
+				item["onvif_profile"] = stream.ONVIFProfile
+			}
+			if stream.SiteID != "" {
+				item["site_id"] = stream.SiteID
+			}
+			if len(stream.Tags) > 0 {
+				item["tags"] = stream.Tags
+			}
+			addBackupInfo(item, stream, reveal)
+			streams = append(streams, item)
+		}
+		c.JSON(http.StatusOK, streams)
+	}
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultItemsPerPage는 page만 지정했을 때 페이지 크기 (mediamtx와 동일)
+	defaultItemsPerPage = 100
+
+	// streamSortBitrate는 런타임 비트레이트 정렬 (DB 컬럼이 아니므로 메모리에서 정렬)
+	streamSortBitrate = "bitrate"
+)
+
+// streamPage는 스트림 목록 조회 결과입니다
+type streamPage struct {
+	streams   []*database.Stream
+	itemCount int // 조건에 맞는 전체 개수
+	pageCount int
+}
+
+// parsePagination은 ?page=&itemsPerPage= 를 파싱합니다 (page는 0부터, mediamtx와 같은 이름)
+// 둘 다 없으면 paginated=false이며 전체를 반환합니다 (기존 클라이언트 호환)
+func parsePagination(c *gin.Context) (page, itemsPerPage int, paginated bool, err error) {
+	pageStr, perPageStr := c.Query("page"), c.Query("itemsPerPage")
+	if pageStr == "" && perPageStr == "" {
+		return 0, 0, false, nil
+	}
+
+	itemsPerPage = defaultItemsPerPage
+	if perPageStr != "" {
+		tmp, err := strconv.ParseUint(perPageStr, 10, 31)
+		if err != nil || tmp == 0 {
+			return 0, 0, false, fmt.Errorf("invalid itemsPerPage: %s", perPageStr)
+		}
+		itemsPerPage = int(tmp)
+	}
+
+	if pageStr != "" {
+		tmp, err := strconv.ParseUint(pageStr, 10, 31)
+		if err != nil {
+			return 0, 0, false, fmt.Errorf("invalid page: %s", pageStr)
+		}
+		page = int(tmp)
+	}
+
+	return page, itemsPerPage, true, nil
+}
+
+// pageCount는 전체 개수와 페이지 크기로 페이지 수를 계산합니다
+func pageCount(itemCount, itemsPerPage int) int {
+	return (itemCount + itemsPerPage - 1) / itemsPerPage
+}
+
+// queryStreams는 목록 조회 쿼리 파라미터에 맞는 스트림을 조회합니다
+// 잘못된 파라미터면 400을 응답하고 ok=false를 반환합니다
+//
+//	?page=0&itemsPerPage=50            페이지 (둘 다 없으면 전체)
+//	?status=online|offline             소스 연결 상태
+//	?codec=H265                        감지된 비디오 코덱
+//	?on_demand=true                    요청 시 시작 여부
+//	?name=CCTV-JEJU1                   이름 또는 ID 앞부분
+//	?site=JEJU1                        소속 사이트
+//	?tag=ptz,outdoor                   태그 (쉼표로 여러 개, 모두 가진 스트림만)
+//	?sort=name|created_at|bitrate&order=asc|desc
+//
+// DB 컬럼 조건과 정렬, 페이지는 SQL에서 처리하며 런타임 조건(상태, 코덱)은 해당 스트림 ID 목록으로 바꿔 전달합니다
+// 비트레이트 정렬과 스트림이 제한된 viewer는 조건에 맞는 행을 모두 가져와 메모리에서 정렬/페이지를 나눕니다
+func (s *Server) queryStreams(c *gin.Context) (*streamPage, bool) {
+	badRequest := func(message string) (*streamPage, bool) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return nil, false
+	}
+
+	page, itemsPerPage, paginated, err := parsePagination(c)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+
+	filter := database.StreamFilter{
+		NamePrefix: c.Query("name"),
+		SiteID:     c.Query("site"),
+	}
+
+	if value := c.Query("tag"); value != "" {
+		tags, err := normalizeTags(strings.Split(value, ","))
+		if err != nil {
+			return badRequest("Invalid tag: " + err.Error())
+		}
+		filter.Tags = tags
+	}
+
+	if value := c.Query("on_demand"); value != "" {
+		onDemand, err := strconv.ParseBool(value)
+		if err != nil {
+			return badRequest("Invalid on_demand: " + value)
+		}
+		filter.OnDemand = &onDemand
+	}
+
+	sortBy := c.DefaultQuery("sort", database.StreamSortCreatedAt)
+	switch sortBy {
+	case database.StreamSortName, database.StreamSortCreatedAt, streamSortBitrate:
+	default:
+		return badRequest("Invalid sort (name, created_at, bitrate): " + sortBy)
+	}
+	filter.Sort = sortBy
+
+	// 기본 정렬 방향: 이름은 오름차순, 생성 시각/비트레이트는 내림차순
+	switch order := c.Query("order"); order {
+	case "":
+		filter.Desc = sortBy != database.StreamSortName
+	case "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return badRequest("Invalid order (asc, desc): " + order)
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", "online", "offline":
+	default:
+		return badRequest("Invalid status (online, offline): " + status)
+	}
+	codec := c.Query("codec")
+
+	// 런타임 조건을 스트림 ID 조건으로 변환
+	if status != "" || codec != "" {
+		var matched, online []string
+		if s.streamManager != nil {
+			for id, stream := range s.streamManager.ListStreams() {
+				ready := stream.IsReady()
+				if ready {
+					online = append(online, id)
+				}
+				if codec != "" && !strings.EqualFold(stream.GetVideoCodec(), codec) {
+					continue
+				}
+				if status != "" && ready != (status == "online") {
+					continue
+				}
+				matched = append(matched, id)
+			}
+		}
+
+		switch {
+		case codec != "" || status == "online":
+			// 코덱은 실행 중인 스트림에만 있음
+			filter.IDs = append(make([]string, 0, len(matched)), matched...)
+		case status == "offline":
+			filter.ExcludeIDs = online
+		}
+	}
+
+	identity := identityFromContext(c)
+	inMemory := sortBy == streamSortBitrate || !identity.CanViewAll()
+	if paginated && !inMemory {
+		filter.Limit = itemsPerPage
+		filter.Offset = page * itemsPerPage
+	}
+
+	streams, total, err := s.streamRepo.Query(filter)
+	if err != nil {
+		s.logger.Error("Failed to list streams from database", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list streams: " + err.Error(),
+		})
+		return nil, false
+	}
+
+	if inMemory {
+		// viewer는 허용된 스트림만
+		visible := streams[:0]
+		for _, stream := range streams {
+			if identity.CanView(stream.ID) {
+				visible = append(visible, stream)
+			}
+		}
+		streams = visible
+		total = len(streams)
+
+		if sortBy == streamSortBitrate {
+			bitrates := make(map[string]float64, len(streams))
+			for _, stream := range streams {
+				bitrates[stream.ID] = s.streamBitrate(stream.ID)
+			}
+			sort.SliceStable(streams, func(i, j int) bool {
+				if filter.Desc {
+					return bitrates[streams[i].ID] > bitrates[streams[j].ID]
+				}
+				return bitrates[streams[i].ID] < bitrates[streams[j].ID]
+			})
+		}
+
+		if paginated {
+			start := min(page*itemsPerPage, len(streams))
+			end := min(start+itemsPerPage, len(streams))
+			streams = streams[start:end]
+		}
+	}
+
+	result := &streamPage{
+		streams:   streams,
+		itemCount: total,
+		pageCount: 1,
+	}
+	if paginated {
+		result.pageCount = pageCount(total, itemsPerPage)
+	}
+	return result, true
+}
+
+// streamBitrate는 스트림이 수신 중인 비트레이트(bps)를 반환합니다 (샘플이 없으면 0)
+func (s *Server) streamBitrate(streamID string) float64 {
+	if s.statusManager == nil {
+		return 0
+	}
+	rate, _ := s.statusManager.Bitrate(streamID)
+	return rate.ReceivedBps
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+// handleListTags는 태그 목록과 태그별 스트림 수를 조회합니다
+// GET /api/v1/tags
+func (s *Server) handleListTags(c *gin.Context) {
+	tags, err := s.tagRepo.List()
+	if err != nil {
+		s.logger.Error("Failed to list tags", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list tags: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags":  tags,
+		"count": len(tags),
+	})
+}
+
+// handleCreateTag는 태그를 생성합니다 (스트림에 지정하면 자동으로 생성되므로 설명을 붙일 때 사용)
+// POST /api/v1/tags {"name":"ptz","description":"PTZ 카메라"}
+func (s *Server) handleCreateTag(c *gin.Context) {
+	var tag database.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	names, err := normalizeTags([]string{tag.Name})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid tag: " + err.Error(),
+		})
+		return
+	}
+	tag.Name = names[0]
+
+	exists, err := s.tagRepo.Exists(tag.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create tag: " + err.Error(),
+		})
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("Tag %s already exists", tag.Name),
+		})
+		return
+	}
+
+	if err := s.tagRepo.Create(&tag); err != nil {
+		s.logger.Error("Failed to create tag", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create tag: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionTagCreate, "", "name="+tag.Name)
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// handleUpdateTag는 태그 설명을 수정합니다
+// PUT /api/v1/tags/:name {"description":"..."}
+func (s *Server) handleUpdateTag(c *gin.Context) {
+	var request struct {
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	name := c.Param("name")
+	if err := s.tagRepo.Update(&database.Tag{Name: name, Description: request.Description}); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionTagUpdate, "", "name="+name)
+
+	tag, err := s.tagRepo.Get(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+// handleDeleteTag는 태그를 삭제하고 모든 스트림에서 떼어냅니다
+// DELETE /api/v1/tags/:name
+func (s *Server) handleDeleteTag(c *gin.Context) {
+	name := c.Param("name")
+
+	if err := s.tagRepo.Delete(name); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionTagDelete, "", "name="+name)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Tag deleted successfully",
+		"name":    name,
+	})
+}
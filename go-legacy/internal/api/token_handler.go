@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/hls"
+	"go.uber.org/zap"
+)
+
+// handleIssueStreamToken은 스트림 재생 토큰을 발급합니다
+// POST /api/v1/streams/:id/token {"ttl": 초, "ip": "클라이언트 IP"}
+// 발급된 토큰은 HLS(?token=)와 WebSocket 시그널링(/ws?token=)에서 사용할 수 있습니다
+func (s *Server) handleIssueStreamToken(c *gin.Context) {
+	streamID := c.Param("id")
+
+	var request struct {
+		TTL int    `json:"ttl"` // 초 (0이면 기본값)
+		IP  string `json:"ip"`  // 비어있으면 IP 제한 없음
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if request.TTL < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ttl: " + strconv.Itoa(request.TTL),
+		})
+		return
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ip: " + request.IP,
+		})
+		return
+	}
+
+	if _, err := s.streamRepo.Get(streamID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Stream %s not found", streamID),
+		})
+		return
+	}
+
+	token, expiresAt, err := s.authManager.IssueStreamToken(streamID, time.Duration(request.TTL)*time.Second, request.IP)
+	if err != nil {
+		s.logger.Error("Failed to issue stream token", zap.String("stream_id", streamID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to issue token: " + err.Error(),
+		})
+		return
+	}
+
+	s.logger.Info("Stream token issued",
+		zap.String("stream_id", streamID),
+		zap.Time("expires_at", expiresAt),
+		zap.String("bound_ip", request.IP),
+		zap.String("issued_by", identityFromContext(c).Name),
+	)
+
+	s.recordAudit(c, audit.ActionTokenIssue, streamID, fmt.Sprintf("expires_at=%s bound_ip=%s", expiresAt.Format(time.RFC3339), request.IP))
+
+	scheme, wsScheme := "http", "ws"
+	if c.Request.TLS != nil {
+		scheme, wsScheme = "https", "wss"
+	}
+	query := url.Values{"token": []string{token}}.Encode()
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"stream_id":  streamID,
+		"expires_at": expiresAt,
+		"hls_url":    fmt.Sprintf("%s://%s/hls/%s/index.m3u8?%s", scheme, c.Request.Host, url.PathEscape(streamID), query),
+		"ws_url":     fmt.Sprintf("%s://%s/ws?%s", wsScheme, c.Request.Host, query),
+	})
+}
+
+// playlistRecorder는 HLS 플레이리스트 응답을 버퍼링합니다
+type playlistRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *playlistRecorder) Header() http.Header         { return r.header }
+func (r *playlistRecorder) Write(p []byte) (int, error) { return r.body.Write(p) }
+func (r *playlistRecorder) WriteHeader(status int)      { r.status = status }
+
+// serveHLS는 요청을 HLS muxer로 전달합니다
+// ?token=으로 요청한 플레이리스트는 상대 경로 URI(하위 플레이리스트, init, 세그먼트, part)에도
+// 토큰을 붙여, 플레이어의 후속 요청이 같은 토큰으로 인증되도록 합니다
+func (s *Server) serveHLS(c *gin.Context, muxer *hls.MuxerGoHLS) {
+	defer s.trackHLSView(c)
+
+	token := c.Query("token")
+	if token == "" || !strings.HasSuffix(c.Request.URL.Path, ".m3u8") {
+		muxer.Handle(c.Writer, c.Request)
+		return
+	}
+
+	rec := &playlistRecorder{
+		header: c.Writer.Header(),
+		status: http.StatusOK,
+	}
+	muxer.Handle(rec, c.Request)
+
+	body := rec.body.Bytes()
+	if rec.status == http.StatusOK {
+		body = appendTokenToPlaylist(body, token)
+	}
+
+	c.Writer.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	c.Writer.WriteHeader(rec.status)
+	c.Writer.Write(body)
+}
+
+// appendTokenToPlaylist는 플레이리스트의 모든 URI에 token 쿼리를 추가합니다
+// URI 줄과 태그의 URI="..." 속성(EXT-X-MAP, EXT-X-PART, EXT-X-PRELOAD-HINT 등)을 처리합니다
+func appendTokenToPlaylist(playlist []byte, token string) []byte {
+	lines := strings.Split(string(playlist), "\n")
+
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, "\r")
+
+		switch {
+		case trimmed == "":
+			continue
+
+		case !strings.HasPrefix(trimmed, "#"):
+			lines[i] = addTokenQuery(trimmed, token) + line[len(trimmed):]
+
+		default:
+			lines[i] = addTokenToURIAttributes(line, token)
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// addTokenToURIAttributes는 태그 줄의 URI="..." 속성에 token 쿼리를 추가합니다
+func addTokenToURIAttributes(line string, token string) string {
+	const attr = `URI="`
+
+	var out strings.Builder
+	rest := line
+
+	for {
+		idx := strings.Index(rest, attr)
+		if idx < 0 {
+			out.WriteString(rest)
+			return out.String()
+		}
+
+		start := idx + len(attr)
+		end := strings.IndexByte(rest[start:], '"')
+		if end < 0 {
+			out.WriteString(rest)
+			return out.String()
+		}
+
+		out.WriteString(rest[:start])
+		out.WriteString(addTokenQuery(rest[start:start+end], token))
+		rest = rest[start+end:]
+	}
+}
+
+// addTokenQuery는 URI에 token 쿼리를 추가합니다 (이미 있으면 그대로)
+func addTokenQuery(uri string, token string) string {
+	if strings.Contains(uri, "token=") {
+		return uri
+	}
+
+	sep := "?"
+	if strings.Contains(uri, "?") {
+		sep = "&"
+	}
+	return uri + sep + "token=" + url.QueryEscape(token)
+}
+
+// trackHLSView는 HLS 요청을 시청 세션으로 감사 로그에 기록합니다
+func (s *Server) trackHLSView(c *gin.Context) {
+	if s.auditLogger == nil || c.Writer.Status() >= http.StatusBadRequest {
+		return
+	}
+
+	var bytes uint64
+	if size := c.Writer.Size(); size > 0 {
+		bytes = uint64(size)
+	}
+	s.auditLogger.TouchHLS(s.auditActor(c), c.Param("streamId"), bytes)
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/transcode"
+	"go.uber.org/zap"
+)
+
+// transcodeEnabled는 트랜스코딩이 비활성화면 503을 응답하고 false를 반환합니다
+func (s *Server) transcodeEnabled(c *gin.Context) bool {
+	if s.transcodeManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Transcoding is not enabled",
+		})
+		return false
+	}
+	return true
+}
+
+// transcodeErrorStatus는 트랜스코딩 에러를 HTTP 상태 코드로 변환합니다
+func transcodeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, transcode.ErrInvalidProfile):
+		return http.StatusBadRequest
+	case errors.Is(err, transcode.ErrProfileNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, transcode.ErrProfileExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// profileDetails는 감사 로그에 남길 프로필 설정 요약입니다
+func profileDetails(profile *database.TranscodeProfile) string {
+	return fmt.Sprintf("codec=%s resolution=%s bitrate=%s gop=%d",
+		profile.Codec, profile.Resolution, profile.Bitrate, profile.GOP)
+}
+
+// handleListTranscodeProfiles는 트랜스코딩 프로필 목록을 조회합니다
+// GET /api/v1/transcode/profiles
+func (s *Server) handleListTranscodeProfiles(c *gin.Context) {
+	if !s.transcodeEnabled(c) {
+		return
+	}
+
+	profiles, err := s.transcodeManager.ListProfiles()
+	if err != nil {
+		s.logger.Error("Failed to list transcode profiles", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list transcode profiles: " + err.Error(),
+		})
+		return
+	}
+	if profiles == nil {
+		profiles = []*database.TranscodeProfile{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profiles": profiles,
+		"count":    len(profiles),
+	})
+}
+
+// handleCreateTranscodeProfile은 트랜스코딩 프로필을 추가합니다
+// POST /api/v1/transcode/profiles {"name":"h264_720p","codec":"h264","resolution":"720p","bitrate":"2000k","gop":60}
+func (s *Server) handleCreateTranscodeProfile(c *gin.Context) {
+	if !s.transcodeEnabled(c) {
+		return
+	}
+
+	var profile database.TranscodeProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := s.transcodeManager.CreateProfile(&profile); err != nil {
+		c.JSON(transcodeErrorStatus(err), gin.H{
+			"error": "Failed to create transcode profile: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionTranscodeProfileCreate, "", "name="+profile.Name+" "+profileDetails(&profile))
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// handleUpdateTranscodeProfile은 트랜스코딩 프로필을 수정합니다
+// 실행 중인 트랜스코더는 중지되며 다음 시청부터 새 설정이 적용됩니다
+// PUT /api/v1/transcode/profiles/:name
+func (s *Server) handleUpdateTranscodeProfile(c *gin.Context) {
+	if !s.transcodeEnabled(c) {
+		return
+	}
+
+	var profile database.TranscodeProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	profile.Name = c.Param("name")
+
+	if err := s.transcodeManager.UpdateProfile(&profile); err != nil {
+		c.JSON(transcodeErrorStatus(err), gin.H{
+			"error": "Failed to update transcode profile: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionTranscodeProfileUpdate, "", "name="+profile.Name+" "+profileDetails(&profile))
+
+	updated, err := s.transcodeManager.GetProfile(profile.Name)
+	if err != nil {
+		c.JSON(transcodeErrorStatus(err), gin.H{
+			"error": "Failed to get transcode profile: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// handleDeleteTranscodeProfile은 트랜스코딩 프로필을 삭제하고 해당 트랜스코더를 중지합니다
+// DELETE /api/v1/transcode/profiles/:name
+func (s *Server) handleDeleteTranscodeProfile(c *gin.Context) {
+	if !s.transcodeEnabled(c) {
+		return
+	}
+
+	name := c.Param("name")
+	if err := s.transcodeManager.DeleteProfile(name); err != nil {
+		c.JSON(transcodeErrorStatus(err), gin.H{
+			"error": "Failed to delete transcode profile: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionTranscodeProfileDelete, "", "name="+name)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Transcode profile deleted successfully",
+		"name":    name,
+	})
+}
+
+// handleListDerivedStreams는 스트림의 트랜스코딩 파생 스트림 목록과 실행 상태를 조회합니다
+// 파생 스트림은 WebRTC/RTSP로 ID(CAM1~h264_720p)를 지정해 시청하면 트랜스코더가 시작됩니다
+// GET /api/v1/streams/:id/transcodes
+func (s *Server) handleListDerivedStreams(c *gin.Context) {
+	if !s.transcodeEnabled(c) {
+		return
+	}
+
+	streamID := c.Param("id")
+	if _, err := s.streamManager.GetStream(streamID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Stream not found: " + streamID,
+		})
+		return
+	}
+
+	derived, err := s.transcodeManager.DerivedStreams(streamID)
+	if err != nil {
+		s.logger.Error("Failed to list transcoded streams",
+			zap.String("stream_id", streamID),
+			zap.Error(err),
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list transcoded streams: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stream_id":  streamID,
+		"transcodes": derived,
+		"count":      len(derived),
+	})
+}
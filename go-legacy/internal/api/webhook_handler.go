@@ -0,0 +1,317 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/webhook"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDeliveryLimit는 웹훅 전송 기록 조회 기본 개수
+	defaultDeliveryLimit = 50
+
+	// maxDeliveryLimit는 웹훅 전송 기록 조회 최대 개수
+	maxDeliveryLimit = 500
+)
+
+// webhooksEnabled는 웹훅이 비활성화면 503을 응답하고 false를 반환합니다
+func (s *Server) webhooksEnabled(c *gin.Context) bool {
+	if s.webhookManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Webhooks are not enabled",
+		})
+		return false
+	}
+	return true
+}
+
+// webhookErrorStatus는 웹훅 에러를 HTTP 상태 코드로 변환합니다
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidWebhook):
+		return http.StatusBadRequest
+	case errors.Is(err, webhook.ErrWebhookNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// webhookID는 경로의 웹훅 ID를 파싱합니다 (잘못된 값이면 400 응답 후 false)
+func webhookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID: " + c.Param("id"),
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// redactWebhook은 응답용으로 서명 키를 제거한 사본을 반환합니다 (키는 생성 응답에서만 반환)
+func redactWebhook(w *database.Webhook) *database.Webhook {
+	redacted := *w
+	redacted.Secret = ""
+	return &redacted
+}
+
+// webhookDetails는 감사 로그에 남길 웹훅 설정 요약입니다
+func webhookDetails(w *database.Webhook) string {
+	events := "*"
+	if len(w.Events) > 0 {
+		events = strings.Join(w.Events, ",")
+	}
+	return "id=" + strconv.FormatInt(w.ID, 10) + " url=" + w.URL + " events=" + events +
+		" enabled=" + strconv.FormatBool(w.Enabled)
+}
+
+// handleListWebhooks는 웹훅 목록을 조회합니다
+// GET /api/v1/webhooks
+func (s *Server) handleListWebhooks(c *gin.Context) {
+	if !s.webhooksEnabled(c) {
+		return
+	}
+
+	list, err := s.webhookManager.List()
+	if err != nil {
+		s.logger.Error("Failed to list webhooks", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list webhooks: " + err.Error(),
+		})
+		return
+	}
+
+	webhooks := make([]*database.Webhook, 0, len(list))
+	for _, w := range list {
+		webhooks = append(webhooks, redactWebhook(w))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks,
+		"count":    len(webhooks),
+		"events":   webhook.Events,
+	})
+}
+
+// handleGetWebhook은 웹훅을 조회합니다
+// GET /api/v1/webhooks/:id
+func (s *Server) handleGetWebhook(c *gin.Context) {
+	if !s.webhooksEnabled(c) {
+		return
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	w, err := s.webhookManager.Get(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{
+			"error": "Failed to get webhook: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactWebhook(w))
+}
+
+// handleCreateWebhook은 웹훅을 추가합니다 (secret을 생략하면 생성해 응답에 한 번만 포함)
+// POST /api/v1/webhooks {"name":"nvr","url":"https://example.com/hook","events":["stream.offline"],"enabled":true}
+func (s *Server) handleCreateWebhook(c *gin.Context) {
+	if !s.webhooksEnabled(c) {
+		return
+	}
+
+	w := database.Webhook{Enabled: true}
+	if err := c.ShouldBindJSON(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := s.webhookManager.Create(&w); err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{
+			"error": "Failed to create webhook: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionWebhookCreate, "", webhookDetails(&w))
+
+	c.JSON(http.StatusCreated, w)
+}
+
+// handleUpdateWebhook은 웹훅을 수정합니다 (secret을 생략하면 기존 키 유지)
+// PUT /api/v1/webhooks/:id
+func (s *Server) handleUpdateWebhook(c *gin.Context) {
+	if !s.webhooksEnabled(c) {
+		return
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	w := database.Webhook{Enabled: true}
+	if err := c.ShouldBindJSON(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	w.ID = id
+
+	if err := s.webhookManager.Update(&w); err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{
+			"error": "Failed to update webhook: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionWebhookUpdate, "", webhookDetails(&w))
+
+	updated, err := s.webhookManager.Get(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{
+			"error": "Failed to get webhook: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactWebhook(updated))
+}
+
+// handleDeleteWebhook은 웹훅과 전송 기록을 삭제합니다
+// DELETE /api/v1/webhooks/:id
+func (s *Server) handleDeleteWebhook(c *gin.Context) {
+	if !s.webhooksEnabled(c) {
+		return
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := s.webhookManager.Delete(id); err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{
+			"error": "Failed to delete webhook: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionWebhookDelete, "", "id="+c.Param("id"))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted successfully",
+		"id":      id,
+	})
+}
+
+// handleTestWebhook은 웹훅에 확인용 이벤트(webhook.test)를 보냅니다
+// 전송은 비동기이며 결과는 전송 기록에서 확인합니다
+// POST /api/v1/webhooks/:id/test
+func (s *Server) handleTestWebhook(c *gin.Context) {
+	if !s.webhooksEnabled(c) {
+		return
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := s.webhookManager.Test(id)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{
+			"error": "Failed to send test webhook: " + err.Error(),
+		})
+		return
+	}
+
+	s.recordAudit(c, audit.ActionWebhookTest, "", "id="+c.Param("id"))
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// handleListWebhookDeliveries는 최근 웹훅 전송 기록과 결과를 조회합니다
+// GET /api/v1/webhooks/deliveries?webhook_id=&event=&status=pending|delivered|failed&limit=&offset=
+// GET /api/v1/webhooks/:id/deliveries
+func (s *Server) handleListWebhookDeliveries(c *gin.Context) {
+	if !s.webhooksEnabled(c) {
+		return
+	}
+
+	filter := database.DeliveryFilter{
+		Event:  c.Query("event"),
+		Status: c.Query("status"),
+	}
+
+	if c.Param("id") != "" {
+		id, ok := webhookID(c)
+		if !ok {
+			return
+		}
+		filter.WebhookID = id
+	} else if value := c.Query("webhook_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid webhook_id: " + value,
+			})
+			return
+		}
+		filter.WebhookID = id
+	}
+
+	switch filter.Status {
+	case "", database.DeliveryPending, database.DeliveryDelivered, database.DeliveryFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid status (pending, delivered, failed): " + filter.Status,
+		})
+		return
+	}
+
+	limit, err := queryInt(c, "limit", defaultDeliveryLimit)
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid limit: " + c.Query("limit"),
+		})
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid offset: " + c.Query("offset"),
+		})
+		return
+	}
+	if limit == 0 || limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	deliveries, total, err := s.webhookManager.Deliveries(filter)
+	if err != nil {
+		s.logger.Error("Failed to query webhook deliveries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to query webhook deliveries: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+		"total":      total,
+	})
+}
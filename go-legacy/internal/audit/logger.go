@@ -0,0 +1,410 @@
+// Package audit은 관리 작업과 시청 기록을 감사 로그로 남깁니다
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+// 감사 로그 action
+const (
+	ActionStreamCreate    = "stream.create"
+	ActionStreamUpdate    = "stream.update"
+	ActionStreamDelete    = "stream.delete"
+	ActionStreamImport    = "stream.import"
+	ActionStreamStart     = "stream.start"
+	ActionStreamStop      = "stream.stop"
+	ActionTokenIssue      = "stream.token"
+	ActionPTZ             = "stream.ptz"
+	ActionViewStart       = "view.start"
+	ActionViewEnd         = "view.end"
+	ActionRecordingExport = "recording.export"
+
+	ActionTranscodeProfileCreate = "transcode.profile.create"
+	ActionTranscodeProfileUpdate = "transcode.profile.update"
+	ActionTranscodeProfileDelete = "transcode.profile.delete"
+
+	ActionGroupCreate = "group.create"
+	ActionGroupUpdate = "group.update"
+	ActionGroupDelete = "group.delete"
+
+	ActionSiteCreate = "site.create"
+	ActionSiteUpdate = "site.update"
+	ActionSiteDelete = "site.delete"
+	ActionSiteStart  = "site.start"
+	ActionSiteStop   = "site.stop"
+
+	ActionTagCreate = "tag.create"
+	ActionTagUpdate = "tag.update"
+	ActionTagDelete = "tag.delete"
+
+	ActionWebhookCreate = "webhook.create"
+	ActionWebhookUpdate = "webhook.update"
+	ActionWebhookDelete = "webhook.delete"
+	ActionWebhookTest   = "webhook.test"
+
+	ActionConfigReload = "config.reload"
+)
+
+// 시청 프로토콜
+const (
+	ProtocolWebRTC         = "webrtc"
+	ProtocolWebRTCPlayback = "webrtc-playback"
+	ProtocolHLS            = "hls"
+	ProtocolRTSP           = "rtsp"
+	ProtocolHTTP           = "http"
+)
+
+const (
+	// queueSize는 DB 기록 대기열 크기 (가득 차면 항목을 버리고 경고)
+	queueSize = 1024
+
+	// hlsIdleTimeout은 요청이 없을 때 HLS 시청이 끝난 것으로 보는 시간
+	hlsIdleTimeout = 30 * time.Second
+)
+
+// Actor는 작업을 수행한 주체입니다
+type Actor struct {
+	User       string
+	Role       string
+	RemoteAddr string
+}
+
+// ActorFromIdentity는 인증된 Identity로 Actor를 만듭니다
+func ActorFromIdentity(identity *auth.Identity, remoteAddr string) Actor {
+	if identity == nil {
+		return Actor{User: "unknown", RemoteAddr: remoteAddr}
+	}
+	return Actor{
+		User:       identity.Name,
+		Role:       string(identity.Role),
+		RemoteAddr: remoteAddr,
+	}
+}
+
+type remoteAddrKey struct{}
+
+// WithRemoteAddr는 context에 클라이언트 IP를 저장합니다
+// (신뢰 프록시 설정을 반영한 주소, net/http 핸들러에서 감사 로그 주체를 만들 때 사용)
+func WithRemoteAddr(ctx context.Context, remoteAddr string) context.Context {
+	return context.WithValue(ctx, remoteAddrKey{}, remoteAddr)
+}
+
+// RemoteAddrFromContext는 context에서 클라이언트 IP를 가져옵니다 (없으면 빈 문자열)
+func RemoteAddrFromContext(ctx context.Context) string {
+	remoteAddr, _ := ctx.Value(remoteAddrKey{}).(string)
+	return remoteAddr
+}
+
+// Config는 감사 로그 설정
+type Config struct {
+	Enabled   bool
+	Retention time.Duration // 0이면 무기한 보관
+}
+
+// Logger는 감사 로그를 비동기로 DB에 기록합니다
+// nil Logger의 메서드는 아무 작업도 하지 않습니다
+type Logger struct {
+	config Config
+	repo   *database.AuditRepository
+	logger *zap.Logger
+
+	queue chan *database.AuditEntry
+	done  chan struct{}
+
+	// Close 이후의 기록은 버림
+	closeMutex sync.RWMutex
+	closed     bool
+
+	// HLS 시청 세션 (요청 단위이므로 유휴 시간으로 종료 판단)
+	hlsMutex    sync.Mutex
+	hlsSessions map[string]*hlsSession
+}
+
+// NewLogger는 새로운 감사 로거를 생성합니다
+// 비활성화된 경우 nil을 반환합니다
+func NewLogger(config Config, repo *database.AuditRepository, logger *zap.Logger) *Logger {
+	if !config.Enabled {
+		return nil
+	}
+
+	l := &Logger{
+		config:      config,
+		repo:        repo,
+		logger:      logger,
+		queue:       make(chan *database.AuditEntry, queueSize),
+		done:        make(chan struct{}),
+		hlsSessions: make(map[string]*hlsSession),
+	}
+
+	go l.run()
+
+	return l
+}
+
+// Start는 HLS 세션 정리 및 보관 기간 정리 고루틴을 시작합니다
+func (l *Logger) Start(ctx context.Context) {
+	if l == nil {
+		return
+	}
+
+	go func() {
+		hlsTicker := time.NewTicker(hlsIdleTimeout / 2)
+		defer hlsTicker.Stop()
+		retentionTicker := time.NewTicker(time.Hour)
+		defer retentionTicker.Stop()
+
+		l.deleteExpired()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-hlsTicker.C:
+				l.closeIdleHLSSessions(now)
+			case <-retentionTicker.C:
+				l.deleteExpired()
+			}
+		}
+	}()
+}
+
+// Close는 진행 중인 HLS 세션을 종료 기록하고 대기열을 모두 기록한 후 종료합니다
+func (l *Logger) Close() {
+	if l == nil {
+		return
+	}
+
+	l.closeIdleHLSSessions(time.Now().Add(hlsIdleTimeout))
+
+	l.closeMutex.Lock()
+	if l.closed {
+		l.closeMutex.Unlock()
+		return
+	}
+	l.closed = true
+	close(l.queue)
+	l.closeMutex.Unlock()
+
+	<-l.done
+}
+
+// Record는 감사 로그 항목을 기록합니다 (비동기)
+func (l *Logger) Record(actor Actor, action, streamID, details string) {
+	l.enqueue(&database.AuditEntry{
+		User:       actor.User,
+		Role:       actor.Role,
+		RemoteAddr: actor.RemoteAddr,
+		Action:     action,
+		StreamID:   streamID,
+		Protocol:   ProtocolHTTP,
+		Details:    details,
+	})
+}
+
+// RecordExport는 녹화 내보내기를 기록합니다
+func (l *Logger) RecordExport(actor Actor, streamID string, bytes uint64, details string) {
+	l.enqueue(&database.AuditEntry{
+		User:       actor.User,
+		Role:       actor.Role,
+		RemoteAddr: actor.RemoteAddr,
+		Action:     ActionRecordingExport,
+		StreamID:   streamID,
+		Protocol:   ProtocolHTTP,
+		Bytes:      bytes,
+		Details:    details,
+	})
+}
+
+// Session은 시청 세션입니다 (시작 시 view.start, End 호출 시 view.end 기록)
+type Session struct {
+	l        *Logger
+	id       string
+	actor    Actor
+	streamID string
+	protocol string
+	start    time.Time
+	endOnce  sync.Once
+}
+
+// StartSession은 시청 세션 시작을 기록합니다
+func (l *Logger) StartSession(actor Actor, streamID, protocol string) *Session {
+	if l == nil {
+		return nil
+	}
+
+	s := &Session{
+		l:        l,
+		id:       newSessionID(),
+		actor:    actor,
+		streamID: streamID,
+		protocol: protocol,
+		start:    time.Now(),
+	}
+
+	l.enqueue(&database.AuditEntry{
+		Time:       s.start,
+		User:       actor.User,
+		Role:       actor.Role,
+		RemoteAddr: actor.RemoteAddr,
+		Action:     ActionViewStart,
+		StreamID:   streamID,
+		Protocol:   protocol,
+		SessionID:  s.id,
+	})
+
+	return s
+}
+
+// End는 시청 세션 종료를 기록합니다 (여러 번 호출해도 한 번만 기록)
+func (s *Session) End(bytes uint64) {
+	if s == nil {
+		return
+	}
+
+	s.endOnce.Do(func() {
+		s.endAt(time.Now(), bytes)
+	})
+}
+
+// endAt은 지정 시각에 세션 종료를 기록합니다
+func (s *Session) endAt(t time.Time, bytes uint64) {
+	s.l.enqueue(&database.AuditEntry{
+		Time:       t,
+		User:       s.actor.User,
+		Role:       s.actor.Role,
+		RemoteAddr: s.actor.RemoteAddr,
+		Action:     ActionViewEnd,
+		StreamID:   s.streamID,
+		Protocol:   s.protocol,
+		SessionID:  s.id,
+		Bytes:      bytes,
+		DurationMs: t.Sub(s.start).Milliseconds(),
+	})
+}
+
+// hlsSession은 같은 사용자/IP/스트림의 HLS 요청들을 하나의 시청 세션으로 묶습니다
+type hlsSession struct {
+	session  *Session
+	bytes    uint64
+	lastSeen time.Time
+}
+
+// TouchHLS는 HLS 요청을 기록합니다
+// 첫 요청에서 시청 시작, hlsIdleTimeout 동안 요청이 없으면 시청 종료로 기록됩니다
+func (l *Logger) TouchHLS(actor Actor, streamID string, bytes uint64) {
+	if l == nil {
+		return
+	}
+
+	key := actor.User + "|" + actor.RemoteAddr + "|" + streamID
+	now := time.Now()
+
+	l.hlsMutex.Lock()
+	defer l.hlsMutex.Unlock()
+
+	hs, exists := l.hlsSessions[key]
+	if !exists {
+		hs = &hlsSession{
+			session: l.StartSession(actor, streamID, ProtocolHLS),
+		}
+		l.hlsSessions[key] = hs
+	}
+
+	hs.bytes += bytes
+	hs.lastSeen = now
+}
+
+// closeIdleHLSSessions는 유휴 HLS 세션의 종료를 기록합니다
+func (l *Logger) closeIdleHLSSessions(now time.Time) {
+	l.hlsMutex.Lock()
+	defer l.hlsMutex.Unlock()
+
+	for key, hs := range l.hlsSessions {
+		if now.Sub(hs.lastSeen) >= hlsIdleTimeout {
+			// 종료 시각은 마지막 요청 시각
+			hs.session.endOnce.Do(func() {
+				hs.session.endAt(hs.lastSeen, hs.bytes)
+			})
+			delete(l.hlsSessions, key)
+		}
+	}
+}
+
+// enqueue는 항목을 기록 대기열에 넣습니다
+func (l *Logger) enqueue(entry *database.AuditEntry) {
+	if l == nil {
+		return
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.closeMutex.RLock()
+	defer l.closeMutex.RUnlock()
+
+	if l.closed {
+		l.logger.Debug("Audit logger closed, dropping entry",
+			zap.String("action", entry.Action),
+			zap.String("stream_id", entry.StreamID),
+		)
+		return
+	}
+
+	select {
+	case l.queue <- entry:
+	default:
+		l.logger.Warn("Audit queue full, dropping entry",
+			zap.String("action", entry.Action),
+			zap.String("user", entry.User),
+			zap.String("stream_id", entry.StreamID),
+		)
+	}
+}
+
+// run은 대기열의 항목을 DB에 기록합니다
+func (l *Logger) run() {
+	defer close(l.done)
+
+	for entry := range l.queue {
+		if err := l.repo.Insert(entry); err != nil {
+			l.logger.Error("Failed to write audit entry",
+				zap.String("action", entry.Action),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// deleteExpired는 보관 기간이 지난 항목을 삭제합니다
+func (l *Logger) deleteExpired() {
+	if l.config.Retention <= 0 {
+		return
+	}
+
+	deleted, err := l.repo.DeleteBefore(time.Now().Add(-l.config.Retention))
+	if err != nil {
+		l.logger.Error("Failed to delete expired audit entries", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		l.logger.Info("Expired audit entries deleted", zap.Int64("count", deleted))
+	}
+}
+
+// newSessionID는 시청 세션 ID를 생성합니다
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000")
+	}
+	return hex.EncodeToString(b)
+}
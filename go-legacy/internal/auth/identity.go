@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/cctv3/internal/core"
+)
+
+// Role은 사용자 권한 등급입니다
+type Role string
+
+// 지원하는 역할 (아래로 갈수록 권한이 좁아짐)
+const (
+	RoleAdmin    Role = "admin"    // 모든 작업 (스트림 CRUD, 설정 변경)
+	RoleOperator Role = "operator" // 스트림 시작/정지, 모든 스트림 시청
+	RoleViewer   Role = "viewer"   // 허용된 스트림 시청만 가능
+)
+
+// rank는 역할 비교용 순위를 반환합니다
+func (r Role) rank() int {
+	switch r {
+	case RoleAdmin:
+		return 3
+	case RoleOperator:
+		return 2
+	case RoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseRole은 문자열을 역할로 변환합니다
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if r.rank() == 0 {
+		return "", fmt.Errorf("invalid role: %q (allowed: admin, operator, viewer)", s)
+	}
+	return r, nil
+}
+
+// Identity는 인증된 사용자 정보입니다
+type Identity struct {
+	Name    string   `json:"name"`
+	Role    Role     `json:"role"`
+	Streams []string `json:"streams,omitempty"` // 시청 가능한 스트림 (빈 값=전체, "~"로 시작하면 스트림 ID 전체와 일치하는 정규식)
+	Sites   []string `json:"sites,omitempty"`   // 소속 스트림 전체를 시청 가능한 사이트
+	Method  string   `json:"method"`            // "api_key", "jwt", "token", "internal", "anonymous"
+
+	// patterns는 Streams의 정규식 항목 (인증 관리자가 키를 불러올 때 컴파일)
+	patterns []*regexp.Regexp
+
+	// siteOf는 스트림의 사이트를 찾습니다 (인증 관리자가 설정, nil이면 Sites 무시)
+	siteOf func(streamID string) string
+}
+
+// compileStreamPatterns는 스트림 목록의 "~" 정규식 항목을 컴파일합니다
+// 정규식은 스트림 ID 전체와 일치해야 합니다 ("~cam1"은 cam10, xcam1과 일치하지 않음)
+func compileStreamPatterns(streams []string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, stream := range streams {
+		if !strings.HasPrefix(stream, "~") {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + stream[1:] + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid stream pattern %q: %w", stream, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// Anonymous는 인증이 비활성화된 경우 사용하는 관리자 권한 Identity입니다
+var Anonymous = &Identity{
+	Name:   "anonymous",
+	Role:   RoleAdmin,
+	Method: "anonymous",
+}
+
+// HasRole은 최소 역할 이상의 권한이 있는지 확인합니다
+func (i *Identity) HasRole(min Role) bool {
+	if i == nil {
+		return false
+	}
+	return i.Role.rank() >= min.rank()
+}
+
+// CanViewAll은 모든 스트림을 시청할 수 있는지 확인합니다 (admin/operator 또는 스트림/사이트 제한 없음)
+func (i *Identity) CanViewAll() bool {
+	if i == nil {
+		return false
+	}
+	return i.HasRole(RoleOperator) || (len(i.Streams) == 0 && len(i.Sites) == 0)
+}
+
+// CanViewSite는 사이트 전체를 시청할 수 있는지 확인합니다
+func (i *Identity) CanViewSite(siteID string) bool {
+	if i == nil {
+		return false
+	}
+	if i.CanViewAll() {
+		return true
+	}
+	for _, site := range i.Sites {
+		if site == siteID {
+			return true
+		}
+	}
+	return false
+}
+
+// CanView는 스트림 시청 권한이 있는지 확인합니다
+// admin/operator는 모든 스트림, viewer는 Streams에 포함되거나 Sites에 속한 스트림만 시청할 수 있습니다
+// 파생 스트림(CAM1~h264_720p)은 원본 스트림의 권한을 따릅니다
+func (i *Identity) CanView(streamID string) bool {
+	if i == nil {
+		return false
+	}
+	if i.CanViewAll() {
+		return true
+	}
+	if baseID, _, ok := core.SplitDerivedStreamID(streamID); ok {
+		streamID = baseID
+	}
+
+	for _, pattern := range i.Streams {
+		if pattern == "*" || pattern == streamID {
+			return true
+		}
+	}
+	for _, re := range i.patterns {
+		if re.MatchString(streamID) {
+			return true
+		}
+	}
+
+	if len(i.Sites) > 0 && i.siteOf != nil {
+		if siteID := i.siteOf(streamID); siteID != "" {
+			return i.CanViewSite(siteID)
+		}
+	}
+
+	return false
+}
+
+type identityKey struct{}
+
+// WithIdentity는 context에 Identity를 저장합니다
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext는 context에서 Identity를 가져옵니다 (없으면 nil)
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// IsOriginAllowed는 브라우저 Origin이 허용 목록에 포함되는지 확인합니다 ("*"이면 전체 허용)
+func IsOriginAllowed(origin string, allowedOrigins []string) bool {
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,464 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// PauseAfterError는 인증 실패 후 응답 지연 시간 (무차별 대입 방지)
+	PauseAfterError = 2 * time.Second
+
+	// jwksRefreshPeriod는 JWKS 재조회 주기
+	jwksRefreshPeriod = 60 * 60 * time.Second
+
+	// InternalUser는 서버가 실행한 프로세스(트랜스코더 등)의 사용자 이름
+	InternalUser = "internal"
+)
+
+var (
+	// ErrNoCredentials는 인증 정보가 제공되지 않았을 때 반환됩니다
+	ErrNoCredentials = errors.New("credentials not provided")
+	// ErrInvalidCredentials는 인증 정보가 유효하지 않을 때 반환됩니다
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// APIKey는 정적 API 키 설정입니다
+type APIKey struct {
+	Name    string
+	Key     string
+	Role    Role
+	Streams []string // viewer가 시청 가능한 스트림 (빈 값=전체)
+	Sites   []string // viewer가 시청 가능한 사이트
+}
+
+// Config는 인증 관리자 설정
+type Config struct {
+	Enabled bool
+	APIKeys []APIKey
+
+	// JWT (JWKS URL이 비어있으면 JWT 인증 비활성화)
+	JWTJWKS         string
+	JWTIssuer       string // 비어있으면 검증 생략
+	JWTAudience     string // 비어있으면 검증 생략
+	JWTRoleClaim    string // 역할 claim 이름 (기본 "role")
+	JWTStreamsClaim string // 시청 가능 스트림 claim 이름 (기본 "streams")
+	JWTSitesClaim   string // 시청 가능 사이트 claim 이름 (기본 "sites")
+
+	ReadTimeout time.Duration // JWKS 조회 타임아웃
+
+	// 스트림 재생 토큰 (외부 포털 임베드용)
+	TokenSecret     string        // HMAC 서명 키 (비어있으면 실행 시마다 임의 생성)
+	TokenDefaultTTL time.Duration // 유효 기간 미지정 시 기본값
+	TokenMaxTTL     time.Duration // 최대 유효 기간
+}
+
+// Credentials는 요청에서 추출한 인증 정보입니다
+type Credentials struct {
+	User  string
+	Pass  string
+	Token string // API 키 또는 JWT
+}
+
+// IsEmpty는 인증 정보가 비어있는지 확인합니다
+func (c Credentials) IsEmpty() bool {
+	return c.User == "" && c.Pass == "" && c.Token == ""
+}
+
+// Manager는 API 키 및 JWT 인증을 담당합니다
+type Manager struct {
+	config Config
+	logger *zap.Logger
+
+	mutex           sync.Mutex
+	jwksLastRefresh time.Time
+	jwtKeyFunc      keyfunc.Keyfunc
+
+	// API 키별 스트림 정규식 (config.APIKeys와 같은 순서)
+	keyPatterns [][]*regexp.Regexp
+
+	tokens *tokenSigner
+
+	// 스트림의 사이트 조회 (사이트 단위 시청 권한)
+	siteResolver func(streamID string) string
+
+	// 서버가 실행한 프로세스용 키 (실행 시마다 임의 생성, operator 권한)
+	internalKey string
+}
+
+// NewManager는 새로운 인증 관리자를 생성합니다
+func NewManager(config Config, logger *zap.Logger) (*Manager, error) {
+	if config.JWTRoleClaim == "" {
+		config.JWTRoleClaim = "role"
+	}
+	if config.JWTStreamsClaim == "" {
+		config.JWTStreamsClaim = "streams"
+	}
+	if config.JWTSitesClaim == "" {
+		config.JWTSitesClaim = "sites"
+	}
+	if config.ReadTimeout <= 0 {
+		config.ReadTimeout = 10 * time.Second
+	}
+	if config.TokenDefaultTTL <= 0 {
+		config.TokenDefaultTTL = time.Hour
+	}
+	if config.TokenMaxTTL <= 0 {
+		config.TokenMaxTTL = 24 * time.Hour
+	}
+
+	keyPatterns := make([][]*regexp.Regexp, len(config.APIKeys))
+	for i, key := range config.APIKeys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("api key #%d (%s) is empty", i, key.Name)
+		}
+		if _, err := ParseRole(string(key.Role)); err != nil {
+			return nil, fmt.Errorf("api key #%d (%s): %w", i, key.Name, err)
+		}
+		patterns, err := compileStreamPatterns(key.Streams)
+		if err != nil {
+			return nil, fmt.Errorf("api key #%d (%s): %w", i, key.Name, err)
+		}
+		keyPatterns[i] = patterns
+	}
+
+	if config.Enabled && len(config.APIKeys) == 0 && config.JWTJWKS == "" {
+		return nil, fmt.Errorf("auth is enabled but neither api keys nor jwt jwks are configured")
+	}
+
+	tokens, err := newTokenSigner(config.TokenSecret)
+	if err != nil {
+		return nil, err
+	}
+	if config.Enabled && config.TokenSecret == "" {
+		logger.Warn("Stream token secret is not configured, issued tokens will be invalidated on restart")
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate internal key: %w", err)
+	}
+
+	return &Manager{
+		config:      config,
+		logger:      logger,
+		tokens:      tokens,
+		keyPatterns: keyPatterns,
+		internalKey: hex.EncodeToString(random),
+	}, nil
+}
+
+// SetSiteResolver는 사이트 단위 시청 권한에 사용할 스트림의 사이트 조회 함수를 설정합니다
+func (m *Manager) SetSiteResolver(resolver func(streamID string) string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.siteResolver = resolver
+}
+
+// currentSiteResolver는 설정된 사이트 조회 함수를 반환합니다
+func (m *Manager) currentSiteResolver() func(streamID string) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.siteResolver
+}
+
+// IsEnabled는 인증 활성화 여부를 반환합니다
+func (m *Manager) IsEnabled() bool {
+	return m != nil && m.config.Enabled
+}
+
+// Authenticate는 인증 정보를 검증하고 Identity를 반환합니다
+// 인증이 비활성화된 경우 Anonymous(admin)를 반환합니다
+func (m *Manager) Authenticate(creds Credentials) (*Identity, error) {
+	if !m.IsEnabled() {
+		return Anonymous, nil
+	}
+
+	// 토큰이 없으면 Basic 인증의 비밀번호를 토큰으로 사용 (RTSP, 브라우저 호환)
+	token := creds.Token
+	if token == "" {
+		token = creds.Pass
+	}
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+
+	if identity := m.authenticateAPIKey(token); identity != nil {
+		return identity, nil
+	}
+
+	if m.config.JWTJWKS != "" && strings.Count(token, ".") == 2 {
+		identity, err := m.authenticateJWT(token)
+		if err != nil {
+			m.logger.Debug("JWT authentication failed", zap.Error(err))
+			return nil, ErrInvalidCredentials
+		}
+		return identity, nil
+	}
+
+	return nil, ErrInvalidCredentials
+}
+
+// InternalCredentials는 서버가 실행한 프로세스가 내부 RTSP 서버에 접속할 때 사용할 인증 정보를 반환합니다
+// 인증이 비활성화되어 있으면 빈 값을 반환합니다
+func (m *Manager) InternalCredentials() (user, pass string) {
+	if !m.IsEnabled() {
+		return "", ""
+	}
+	return InternalUser, m.internalKey
+}
+
+// IssueStreamToken은 스트림 재생 토큰을 발급합니다
+// ttl이 0 이하면 기본값, 최대값을 넘으면 최대값으로 제한됩니다
+// clientIP가 비어있지 않으면 해당 IP에서만 사용할 수 있습니다
+// (검증 시에는 신뢰하는 프록시를 거쳐 확인한 클라이언트 IP와 비교하므로 X-Forwarded-For로 위조할 수 없음)
+func (m *Manager) IssueStreamToken(streamID string, ttl time.Duration, clientIP string) (string, time.Time, error) {
+	if m == nil {
+		return "", time.Time{}, fmt.Errorf("auth manager is not initialized")
+	}
+
+	if ip := net.ParseIP(clientIP); ip != nil {
+		clientIP = ip.String()
+	}
+
+	if ttl <= 0 {
+		ttl = m.config.TokenDefaultTTL
+	}
+	if ttl > m.config.TokenMaxTTL {
+		ttl = m.config.TokenMaxTTL
+	}
+
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+
+	token, err := m.tokens.sign(StreamToken{
+		StreamID:  streamID,
+		ExpiresAt: expiresAt.Unix(),
+		ClientIP:  clientIP,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// AuthenticateStreamToken은 스트림 재생 토큰을 검증합니다
+// 토큰의 스트림만 시청할 수 있는 viewer Identity를 반환합니다
+func (m *Manager) AuthenticateStreamToken(raw string, clientIP string) (*Identity, error) {
+	if m == nil {
+		return nil, ErrTokenInvalid
+	}
+
+	token, err := m.tokens.verify(raw, clientIP, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Name:    "token:" + token.StreamID,
+		Role:    RoleViewer,
+		Streams: []string{token.StreamID},
+		Method:  "token",
+	}, nil
+}
+
+// authenticateAPIKey는 정적 API 키를 확인합니다
+func (m *Manager) authenticateAPIKey(token string) *Identity {
+	if subtle.ConstantTimeCompare([]byte(m.internalKey), []byte(token)) == 1 {
+		return &Identity{
+			Name:   InternalUser,
+			Role:   RoleOperator,
+			Method: "internal",
+		}
+	}
+
+	for i, key := range m.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(token)) == 1 {
+			return &Identity{
+				Name:     key.Name,
+				Role:     key.Role,
+				Streams:  key.Streams,
+				Sites:    key.Sites,
+				Method:   "api_key",
+				patterns: m.keyPatterns[i],
+				siteOf:   m.currentSiteResolver(),
+			}
+		}
+	}
+	return nil
+}
+
+// authenticateJWT는 JWKS로 JWT 서명을 검증하고 역할 claim을 읽습니다
+func (m *Manager) authenticateJWT(encoded string) (*Identity, error) {
+	keyFunc, err := m.pullJWTJWKS()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWKS: %w", err)
+	}
+
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if m.config.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.config.JWTIssuer))
+	}
+	if m.config.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(m.config.JWTAudience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(encoded, claims, keyFunc, opts...); err != nil {
+		return nil, err
+	}
+
+	rawRole, _ := claims[m.config.JWTRoleClaim].(string)
+	role, err := ParseRole(rawRole)
+	if err != nil {
+		return nil, fmt.Errorf("claim '%s': %w", m.config.JWTRoleClaim, err)
+	}
+
+	streams, err := parseStreamsClaim(claims[m.config.JWTStreamsClaim])
+	if err != nil {
+		return nil, fmt.Errorf("claim '%s': %w", m.config.JWTStreamsClaim, err)
+	}
+	patterns, err := compileStreamPatterns(streams)
+	if err != nil {
+		return nil, fmt.Errorf("claim '%s': %w", m.config.JWTStreamsClaim, err)
+	}
+
+	sites, err := parseStreamsClaim(claims[m.config.JWTSitesClaim])
+	if err != nil {
+		return nil, fmt.Errorf("claim '%s': %w", m.config.JWTSitesClaim, err)
+	}
+
+	name, _ := claims.GetSubject()
+
+	return &Identity{
+		Name:     name,
+		Role:     role,
+		Streams:  streams,
+		Sites:    sites,
+		Method:   "jwt",
+		patterns: patterns,
+		siteOf:   m.currentSiteResolver(),
+	}, nil
+}
+
+// parseStreamsClaim은 스트림 목록 claim을 파싱합니다 (배열 또는 쉼표 구분 문자열)
+func parseStreamsClaim(v interface{}) ([]string, error) {
+	switch value := v.(type) {
+	case nil:
+		return nil, nil
+
+	case string:
+		var out []string
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out, nil
+
+	case []interface{}:
+		out := make([]string, 0, len(value))
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("stream entries must be strings")
+			}
+			out = append(out, s)
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// pullJWTJWKS는 JWKS를 조회합니다 (jwksRefreshPeriod 동안 캐시)
+func (m *Manager) pullJWTJWKS() (jwt.Keyfunc, error) {
+	now := time.Now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if now.Sub(m.jwksLastRefresh) >= jwksRefreshPeriod {
+		httpClient := &http.Client{Timeout: m.config.ReadTimeout}
+
+		res, err := httpClient.Get(m.config.JWTJWKS)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("JWKS server replied with code %d", res.StatusCode)
+		}
+
+		var raw json.RawMessage
+		if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		tmp, err := keyfunc.NewJWKSetJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		m.jwtKeyFunc = tmp
+		m.jwksLastRefresh = now
+
+		m.logger.Info("JWKS refreshed", zap.String("url", m.config.JWTJWKS))
+	}
+
+	return m.jwtKeyFunc.Keyfunc, nil
+}
+
+// RefreshJWTJWKS는 다음 인증 시 JWKS를 다시 조회하도록 합니다
+func (m *Manager) RefreshJWTJWKS() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.jwksLastRefresh = time.Time{}
+}
+
+// CredentialsFromRequest는 HTTP 요청에서 인증 정보를 추출합니다
+// 지원 형식: "Authorization: Bearer <token>", "Authorization: Basic", "X-API-Key" 헤더,
+// 그리고 헤더를 설정할 수 없는 브라우저(HLS, WebSocket)를 위한 ?api_key= / ?jwt= 쿼리
+func CredentialsFromRequest(r *http.Request) Credentials {
+	var creds Credentials
+
+	if h := r.Header.Get("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			creds.Token = strings.TrimSpace(token)
+		} else if user, pass, ok := r.BasicAuth(); ok {
+			creds.User = user
+			creds.Pass = pass
+		}
+	}
+
+	if creds.Token == "" {
+		creds.Token = r.Header.Get("X-API-Key")
+	}
+
+	if creds.Token == "" {
+		query := r.URL.Query()
+		if v := query.Get("api_key"); v != "" {
+			creds.Token = v
+		} else if v := query.Get("jwt"); v != "" {
+			creds.Token = v
+		}
+	}
+
+	return creds
+}
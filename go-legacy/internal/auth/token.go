@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTokenInvalid는 서명이 맞지 않거나 형식이 잘못된 토큰
+	ErrTokenInvalid = errors.New("invalid stream token")
+	// ErrTokenExpired는 만료된 토큰
+	ErrTokenExpired = errors.New("stream token expired")
+	// ErrTokenIPMismatch는 발급 시 지정한 IP와 다른 클라이언트가 사용한 토큰
+	ErrTokenIPMismatch = errors.New("stream token is bound to another client IP")
+)
+
+// StreamToken은 스트림 재생 토큰의 내용입니다
+type StreamToken struct {
+	StreamID  string `json:"s"`
+	ExpiresAt int64  `json:"e"`            // Unix 초
+	ClientIP  string `json:"ip,omitempty"` // 비어있으면 IP 제한 없음
+}
+
+// tokenSigner는 HMAC-SHA256으로 스트림 토큰을 서명/검증합니다
+// 토큰 형식: base64url(JSON) + "." + base64url(HMAC)
+type tokenSigner struct {
+	secret []byte
+}
+
+// newTokenSigner는 서명 키로 tokenSigner를 생성합니다
+// 키가 비어있으면 임의 키를 생성합니다 (재시작 시 기존 토큰 무효화)
+func newTokenSigner(secret string) (*tokenSigner, error) {
+	if secret != "" {
+		return &tokenSigner{secret: []byte(secret)}, nil
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate token secret: %w", err)
+	}
+	return &tokenSigner{secret: random}, nil
+}
+
+// sign은 토큰을 서명합니다
+func (t *tokenSigner) sign(token StreamToken) (string, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(t.mac(encoded)), nil
+}
+
+// verify는 서명과 만료, IP를 검증하고 토큰 내용을 반환합니다
+func (t *tokenSigner) verify(raw string, clientIP string, now time.Time) (*StreamToken, error) {
+	encoded, sig, ok := strings.Cut(raw, ".")
+	if !ok {
+		return nil, ErrTokenInvalid
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(expected, t.mac(encoded)) {
+		return nil, ErrTokenInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+
+	var token StreamToken
+	if err := json.Unmarshal(payload, &token); err != nil || token.StreamID == "" {
+		return nil, ErrTokenInvalid
+	}
+
+	if now.Unix() >= token.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	if token.ClientIP != "" && !sameIP(token.ClientIP, clientIP) {
+		return nil, ErrTokenIPMismatch
+	}
+
+	return &token, nil
+}
+
+// sameIP는 두 IP 주소가 같은지 확인합니다 (IPv4-mapped IPv6 등 표기 차이 무시)
+func sameIP(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return false
+	}
+	return ipA.Equal(ipB)
+}
+
+// mac은 HMAC-SHA256 서명을 계산합니다
+func (t *tokenSigner) mac(data string) []byte {
+	h := hmac.New(sha256.New, t.secret)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
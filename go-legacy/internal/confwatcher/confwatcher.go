@@ -0,0 +1,114 @@
+// Package confwatcher는 설정 파일 변경을 감지합니다
+// 파일 내용의 해시를 주기적으로 비교하므로 편집기의 임시 파일 교체(rename)나 심볼릭 링크 교체(ConfigMap)도 감지합니다
+package confwatcher
+
+import (
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config는 설정 파일 감시자 설정
+type Config struct {
+	Path     string        // 감시할 설정 파일 경로
+	Interval time.Duration // 변경 확인 주기 (기본 2초)
+
+	// OnChange는 파일 내용이 바뀔 때마다 감시 고루틴에서 호출됩니다
+	OnChange func()
+
+	Logger *zap.Logger
+}
+
+// Watcher는 설정 파일 변경 감시자입니다
+type Watcher struct {
+	config Config
+	logger *zap.Logger
+
+	lastHash [sha256.Size]byte
+
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// New는 새로운 설정 파일 감시자를 생성합니다
+func New(config Config) *Watcher {
+	if config.Interval <= 0 {
+		config.Interval = 2 * time.Second
+	}
+
+	return &Watcher{
+		config: config,
+		logger: config.Logger.With(zap.String("component", "confwatcher")),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start는 현재 파일 내용을 기준으로 감시를 시작합니다
+func (w *Watcher) Start() error {
+	hash, err := w.hash()
+	if err != nil {
+		return err
+	}
+	w.lastHash = hash
+
+	w.wg.Add(1)
+	go w.run()
+
+	w.logger.Info("Config watcher started",
+		zap.String("path", w.config.Path),
+		zap.Duration("interval", w.config.Interval),
+	)
+	return nil
+}
+
+// Stop은 감시를 중지합니다 (진행 중인 OnChange 호출이 끝날 때까지 대기)
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		hash, err := w.hash()
+		if err != nil {
+			// 편집기가 파일을 교체하는 중일 수 있으므로 다음 주기에 다시 확인
+			w.logger.Debug("Failed to read config file", zap.Error(err))
+			continue
+		}
+		if hash == w.lastHash {
+			continue
+		}
+		w.lastHash = hash
+
+		w.logger.Info("Config file changed", zap.String("path", w.config.Path))
+		if w.config.OnChange != nil {
+			w.config.OnChange()
+		}
+	}
+}
+
+// hash는 설정 파일 내용의 SHA-256 해시를 계산합니다
+func (w *Watcher) hash() ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(w.config.Path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
@@ -2,8 +2,14 @@ package core
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/yourusername/cctv3/internal/testsrc"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,6 +23,15 @@ type Config struct {
 	RTSP        RTSPConfig            `yaml:"rtsp"`
 	WebRTC      WebRTCConfig          `yaml:"webrtc"`
 	HLS         HLSConfig             `yaml:"hls"`
+	Record      RecordConfig          `yaml:"record"`
+	Auth        AuthConfig            `yaml:"auth"`
+	Audit       AuditConfig           `yaml:"audit"`
+	Ingest      IngestConfig          `yaml:"ingest"`
+	ONVIF       ONVIFConfig           `yaml:"onvif"`
+	Events      EventsConfig          `yaml:"events"`
+	Webhooks    WebhooksConfig        `yaml:"webhooks"`
+	Snapshot    SnapshotConfig        `yaml:"snapshot"`
+	Transcode   TranscodeConfig       `yaml:"transcode"`
 	Media       MediaConfig           `yaml:"media"`
 	Logging     LoggingConfig         `yaml:"logging"`
 	Metrics     MetricsConfig         `yaml:"metrics"`
@@ -26,6 +41,11 @@ type Config struct {
 // DatabaseConfig는 데이터베이스 설정
 type DatabaseConfig struct {
 	Path string `yaml:"path"` // SQLite 파일 경로
+
+	// 카메라 인증 정보 암호화 키 파일 (CCTV_SECRET_KEY 환경 변수가 우선, 파일이 없으면 생성)
+	SecretKeyFile string `yaml:"secret_key_file"`
+	// 키 교체 시 이전 키 파일 목록 (CCTV_SECRET_KEY_PREVIOUS 환경 변수로도 지정 가능)
+	PreviousSecretKeyFiles []string `yaml:"previous_secret_key_files"`
 }
 
 // APIConfig는 외부 API 설정
@@ -47,17 +67,50 @@ type PathConfig struct {
 	Source         string `yaml:"source" json:"source,omitempty"`
 	SourceOnDemand bool   `yaml:"sourceOnDemand" json:"sourceOnDemand,omitempty"`
 	RTSPTransport  string `yaml:"rtspTransport" json:"rtspTransport,omitempty"`
+	// 예비 소스 (주 소스 장애 시 순서대로 전환) / 주 소스가 이 시간 동안 안정적이면 복귀 (비우면 복귀 안 함)
+	BackupSources []string `yaml:"backupSources,omitempty" json:"backupSources,omitempty"`
+	FailbackAfter string   `yaml:"failbackAfter,omitempty" json:"failbackAfter,omitempty"`
 
 	// RunOnDemand 설정 (외부 프로세스 실행, 예: ffmpeg 트랜스코딩)
 	RunOnDemand           string `yaml:"runOnDemand,omitempty" json:"runOnDemand,omitempty"`
-	RunOnDemandRestart    bool   `yaml:"runOnDemandRestart,omitempty" json:"runOnDemandCloseAfter,omitempty"`
+	RunOnDemandRestart    bool   `yaml:"runOnDemandRestart,omitempty" json:"runOnDemandRestart,omitempty"`
 	RunOnDemandCloseAfter string `yaml:"runOnDemandCloseAfter,omitempty" json:"runOnDemandCloseAfter,omitempty"`
+	// runOnDemand 명령이 송출을 시작할 때까지 기다리는 시간 (기본 10s)
+	RunOnDemandStartTimeout string `yaml:"runOnDemandStartTimeout,omitempty" json:"runOnDemandStartTimeout,omitempty"`
+
+	// 경로 훅 (internal/hooks, MTX_PATH 등 환경 변수로 이벤트 정보 전달)
+	// runOnInit: 경로 로드 시 시작, 삭제 시 종료
+	// runOnReady: 스트림 준비(소스 연결, 송출 시작) 시 시작, 준비 해제 시 종료 / runOnNotReady: 준비 해제 시 1회 실행
+	// runOnRead: 시청자(WebRTC, RTSP)마다 시작, 시청 종료 시 종료 / runOnUnread: 시청 종료 시 1회 실행
+	RunOnInit         string `yaml:"runOnInit,omitempty" json:"runOnInit,omitempty"`
+	RunOnInitRestart  bool   `yaml:"runOnInitRestart,omitempty" json:"runOnInitRestart,omitempty"`
+	RunOnReady        string `yaml:"runOnReady,omitempty" json:"runOnReady,omitempty"`
+	RunOnReadyRestart bool   `yaml:"runOnReadyRestart,omitempty" json:"runOnReadyRestart,omitempty"`
+	RunOnNotReady     string `yaml:"runOnNotReady,omitempty" json:"runOnNotReady,omitempty"`
+	RunOnRead         string `yaml:"runOnRead,omitempty" json:"runOnRead,omitempty"`
+	RunOnReadRestart  bool   `yaml:"runOnReadRestart,omitempty" json:"runOnReadRestart,omitempty"`
+	RunOnUnread       string `yaml:"runOnUnread,omitempty" json:"runOnUnread,omitempty"`
 }
 
 type ServerConfig struct {
-	HTTPPort   int  `yaml:"http_port"`
-	WSPort     int  `yaml:"ws_port"`
-	Production bool `yaml:"production"`
+	HTTPPort       int      `yaml:"http_port"`
+	WSPort         int      `yaml:"ws_port"`
+	Production     bool     `yaml:"production"`
+	AllowedOrigins []string `yaml:"allowed_origins"` // CORS/WebSocket 허용 Origin ("*"=전체)
+
+	// X-Forwarded-For/X-Real-IP를 신뢰할 리버스 프록시 (IP 또는 CIDR, 비어있으면 헤더를 무시하고 접속 주소 사용)
+	// 클라이언트 IP는 스트림 토큰 IP 제한과 감사 로그에 사용됩니다
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// 설정 파일 변경 감지 (변경 시 재시작 없이 다시 로드)
+	ConfigWatch         bool `yaml:"config_watch"`
+	ConfigWatchInterval int  `yaml:"config_watch_interval"` // 변경 확인 주기 (초)
+
+	// 종료 시 진행 중인 API 요청, 녹화/HLS 세그먼트 마무리를 기다리는 최대 시간 (초)
+	ShutdownTimeout int `yaml:"shutdown_timeout"`
+
+	// 실시간 상태(/api/v1/events, WebSocket subscribe) 비트레이트 샘플 주기 (초)
+	StatusInterval int `yaml:"status_interval"`
 }
 
 type RTSPConfig struct {
@@ -75,6 +128,8 @@ type RTSPServerConfig struct {
 	WriteTimeout int      `yaml:"write_timeout"`
 }
 
+// TestStreamConfig는 내장 테스트 패턴 스트림 설정입니다
+// URL(testsrc://bars?size=640x480&fps=25)을 지정하면 Name 경로(기본 "testsrc")에 상시 스트림으로 등록됩니다
 type TestStreamConfig struct {
 	URL  string `yaml:"url"`
 	Name string `yaml:"name"`
@@ -117,6 +172,143 @@ type HLSConfig struct {
 	EnableCompression bool   `yaml:"enable_compression"`
 }
 
+// RecordConfig는 녹화(fMP4 세그먼트) 및 재생 설정
+type RecordConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Path            string `yaml:"path"`             // 녹화 파일 저장 디렉토리 (<path>/<streamID>/*.mp4)
+	SegmentDuration int    `yaml:"segment_duration"` // 세그먼트 길이 (초)
+	PartDuration    int    `yaml:"part_duration"`    // fMP4 part 길이 (밀리초)
+	DeleteAfter     int    `yaml:"delete_after"`     // 보관 기간 (시간, 0=무제한)
+}
+
+// AuditConfig는 감사 로그 설정
+type AuditConfig struct {
+	Enabled       bool `yaml:"enabled"`
+	RetentionDays int  `yaml:"retention_days"` // 보관 기간 (일, 0=무제한)
+}
+
+// IngestConfig는 RTMP/SRT 송출 수신 설정
+type IngestConfig struct {
+	RTMP             IngestRTMPConfig  `yaml:"rtmp"`
+	SRT              IngestSRTConfig   `yaml:"srt"`
+	ReadTimeout      int               `yaml:"read_timeout"`       // 수신 타임아웃 (초)
+	StreamKeys       map[string]string `yaml:"stream_keys"`        // 스트림 키 -> 스트림 ID
+	RequireStreamKey bool              `yaml:"require_stream_key"` // true면 stream_keys의 키로만 송출 가능
+}
+
+// IngestRTMPConfig는 RTMP 리스너 설정
+type IngestRTMPConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+}
+
+// IngestSRTConfig는 SRT 리스너 설정
+type IngestSRTConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Port       int    `yaml:"port"`
+	Passphrase string `yaml:"passphrase"` // 암호화 passphrase (비어있으면 암호화 없음)
+}
+
+// ONVIFConfig는 ONVIF 카메라 검색/가져오기 설정
+type ONVIFConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	DiscoveryTimeout int    `yaml:"discovery_timeout"` // WS-Discovery 응답 대기 시간 (초)
+	RequestTimeout   int    `yaml:"request_timeout"`   // SOAP 요청 타임아웃 (초)
+	Interface        string `yaml:"interface"`         // 멀티캐스트 인터페이스 (비어있으면 기본)
+	PTZRole          string `yaml:"ptz_role"`          // PTZ 제어 최소 역할 (operator, admin)
+}
+
+// EventsConfig는 카메라 이벤트(모션, 라인 크로싱 등) 수집 설정
+type EventsConfig struct {
+	Enabled       bool              `yaml:"enabled"`
+	ONVIF         bool              `yaml:"onvif"`          // ONVIF로 가져온 스트림의 PullPoint 이벤트 구독 (onvif.enabled 필요)
+	RetentionDays int               `yaml:"retention_days"` // 보관 기간 (일, 0=무제한)
+	Record        EventRecordConfig `yaml:"record"`
+}
+
+// EventRecordConfig는 이벤트 녹화 설정 (record.enabled가 false인 경우에만 동작, 저장 위치/세그먼트는 record 설정 사용)
+type EventRecordConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Types    []string `yaml:"types"`     // 녹화를 트리거할 이벤트 타입 (비어있으면 전체)
+	PreRoll  int      `yaml:"pre_roll"`  // 이벤트 이전 녹화 구간 (초)
+	PostRoll int      `yaml:"post_roll"` // 마지막 이벤트 이후 녹화 유지 시간 (초)
+}
+
+// WebhooksConfig는 외부 시스템 알림(웹훅) 설정 (엔드포인트는 API로 관리)
+type WebhooksConfig struct {
+	Enabled       bool `yaml:"enabled"`
+	Timeout       int  `yaml:"timeout"`        // 요청 타임아웃 (초)
+	MaxAttempts   int  `yaml:"max_attempts"`   // 최대 전송 시도 횟수 (초과 시 failed)
+	RetryBackoff  int  `yaml:"retry_backoff"`  // 첫 재시도 대기 시간 (초, 이후 시도마다 2배)
+	MaxBackoff    int  `yaml:"max_backoff"`    // 재시도 대기 시간 상한 (초)
+	RetentionDays int  `yaml:"retention_days"` // 완료된 전송 기록 보관 기간 (일, 0=무제한)
+}
+
+// SnapshotConfig는 스트림 스냅샷(JPEG 썸네일) 설정 (GET /api/v1/streams/:id/snapshot.jpg)
+type SnapshotConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	Interval       int    `yaml:"interval"`        // 실행 중인 전체 스트림의 스냅샷 갱신 주기 (초, 0=요청 시에만 생성)
+	DecoderCommand string `yaml:"decoder_command"` // H.264/H.265 키프레임(stdin) → JPEG(stdout) 변환 명령 ($CODEC=h264|hevc)
+	Timeout        int    `yaml:"timeout"`         // 디코더 명령 제한 시간 (초)
+	MaxConcurrent  int    `yaml:"max_concurrent"`  // 동시에 실행할 디코더 수
+	Quality        int    `yaml:"quality"`         // 크기 조정 시 JPEG 품질 (1-100)
+}
+
+// TranscodeConfig는 서버 측 트랜스코딩 설정 (파생 스트림 CAM1~h264_720p, rtsp.server 필요)
+// 트랜스코더는 내부 RTSP 서버에서 원본을 읽어 파생 스트림 경로로 다시 publish합니다
+type TranscodeConfig struct {
+	Enabled      bool                              `yaml:"enabled"`
+	FFmpegPath   string                            `yaml:"ffmpeg_path"`   // ffmpeg 실행 파일 (기본 "ffmpeg")
+	H264Encoder  string                            `yaml:"h264_encoder"`  // H.264 인코더 (기본 libx264, 예: h264_nvenc, h264_vaapi)
+	H265Encoder  string                            `yaml:"h265_encoder"`  // H.265 인코더 (기본 libx265, 예: hevc_nvenc)
+	CloseAfter   int                               `yaml:"close_after"`   // 시청자가 없으면 트랜스코더 종료 (초)
+	StartTimeout int                               `yaml:"start_timeout"` // 원본/트랜스코더 출력 대기 시간 (초)
+	Fallback     string                            `yaml:"fallback"`      // 브라우저가 원본 코덱을 지원하지 않을 때 우선 사용할 프로필
+	Profiles     map[string]TranscodeProfileConfig `yaml:"profiles"`      // DB에 없으면 시작 시 추가 (DB 데이터 우선)
+}
+
+// TranscodeProfileConfig는 트랜스코딩 프로필 설정
+type TranscodeProfileConfig struct {
+	Codec      string `yaml:"codec"`      // h264, h265
+	Resolution string `yaml:"resolution"` // "1280x720" 또는 "720p" (비어있으면 원본 해상도)
+	Bitrate    string `yaml:"bitrate"`    // "2000k", "2M"
+	GOP        int    `yaml:"gop"`        // 키프레임 간격 (프레임)
+}
+
+// AuthConfig는 API/뷰어 인증 설정
+type AuthConfig struct {
+	Enabled bool               `yaml:"enabled"`
+	APIKeys []AuthAPIKeyConfig `yaml:"api_keys"`
+	JWT     AuthJWTConfig      `yaml:"jwt"`
+	Tokens  AuthTokenConfig    `yaml:"tokens"`
+}
+
+// AuthTokenConfig는 스트림 재생 토큰 설정 (POST /api/v1/streams/:id/token)
+type AuthTokenConfig struct {
+	Secret     string `yaml:"secret"`      // HMAC 서명 키 (비어있으면 실행 시마다 임의 생성)
+	DefaultTTL int    `yaml:"default_ttl"` // 기본 유효 기간 (초)
+	MaxTTL     int    `yaml:"max_ttl"`     // 최대 유효 기간 (초)
+}
+
+// AuthAPIKeyConfig는 정적 API 키 설정
+type AuthAPIKeyConfig struct {
+	Name    string   `yaml:"name"`
+	Key     string   `yaml:"key"`
+	Role    string   `yaml:"role"`    // admin, operator, viewer
+	Streams []string `yaml:"streams"` // viewer가 시청 가능한 스트림 (빈 값=전체, "~"로 시작하면 ID 전체와 일치하는 정규식)
+	Sites   []string `yaml:"sites"`   // viewer가 시청 가능한 사이트 (소속 스트림 전체)
+}
+
+// AuthJWTConfig는 JWT 인증 설정 (JWKS로 서명 검증)
+type AuthJWTConfig struct {
+	JWKS         string `yaml:"jwks"`          // JWKS URL (비어있으면 JWT 비활성화)
+	Issuer       string `yaml:"issuer"`        // iss 검증 (선택)
+	Audience     string `yaml:"audience"`      // aud 검증 (선택)
+	RoleClaim    string `yaml:"role_claim"`    // 역할 claim 이름 (기본 "role")
+	StreamsClaim string `yaml:"streams_claim"` // 시청 가능 스트림 claim 이름 (기본 "streams")
+	SitesClaim   string `yaml:"sites_claim"`   // 시청 가능 사이트 claim 이름 (기본 "sites")
+}
+
 type MediaConfig struct {
 	Buffer BufferConfig `yaml:"buffer"`
 	Codec  CodecConfig  `yaml:"codec"`
@@ -183,6 +375,24 @@ func (c *Config) setDefaults() {
 	if c.Database.Path == "" {
 		c.Database.Path = "data/streams.db"
 	}
+	if c.Database.SecretKeyFile == "" {
+		c.Database.SecretKeyFile = filepath.Join(filepath.Dir(c.Database.Path), "secret.key")
+	}
+
+	// 설정 파일 감시 기본값
+	if c.Server.ConfigWatchInterval == 0 {
+		c.Server.ConfigWatchInterval = 2 // 2초
+	}
+
+	// 종료 대기 시간 기본값
+	if c.Server.ShutdownTimeout == 0 {
+		c.Server.ShutdownTimeout = 10 // 10초
+	}
+
+	// 상태 비트레이트 샘플 주기 기본값
+	if c.Server.StatusInterval == 0 {
+		c.Server.StatusInterval = 5 // 5초
+	}
 
 	// API 설정 기본값 (API가 활성화된 경우에만)
 	if c.API != nil && c.API.Enabled {
@@ -199,6 +409,19 @@ func (c *Config) setDefaults() {
 		c.RTSP.Client.OnDemandWaitSec = 2 // 2초 (온디맨드 스트림 시작 대기)
 	}
 
+	// 테스트 패턴 스트림은 같은 이름의 경로가 없으면 상시 경로로 추가
+	if c.RTSP.TestStream.URL != "" {
+		if c.RTSP.TestStream.Name == "" {
+			c.RTSP.TestStream.Name = "testsrc"
+		}
+		if _, exists := c.Paths[c.RTSP.TestStream.Name]; !exists {
+			if c.Paths == nil {
+				c.Paths = make(map[string]PathConfig)
+			}
+			c.Paths[c.RTSP.TestStream.Name] = PathConfig{Source: c.RTSP.TestStream.URL}
+		}
+	}
+
 	// HLS 설정 기본값
 	if c.HLS.SegmentDuration == 0 {
 		c.HLS.SegmentDuration = 2 // 2초
@@ -212,6 +435,97 @@ func (c *Config) setDefaults() {
 	if c.HLS.CleanupThreshold == 0 {
 		c.HLS.CleanupThreshold = 20 // 최대 20개 세그먼트 유지
 	}
+
+	// 녹화 설정 기본값
+	if c.Record.Path == "" {
+		c.Record.Path = "recordings"
+	}
+	if c.Record.SegmentDuration == 0 {
+		c.Record.SegmentDuration = 60 // 1분
+	}
+	if c.Record.PartDuration == 0 {
+		c.Record.PartDuration = 1000 // 1초
+	}
+
+	// RTMP/SRT 수신 설정 기본값
+	if c.Ingest.RTMP.Port == 0 {
+		c.Ingest.RTMP.Port = 1935
+	}
+	if c.Ingest.SRT.Port == 0 {
+		c.Ingest.SRT.Port = 8890
+	}
+	if c.Ingest.ReadTimeout == 0 {
+		c.Ingest.ReadTimeout = 10 // 10초
+	}
+
+	// ONVIF 설정 기본값
+	if c.ONVIF.DiscoveryTimeout == 0 {
+		c.ONVIF.DiscoveryTimeout = 3 // 3초
+	}
+	if c.ONVIF.RequestTimeout == 0 {
+		c.ONVIF.RequestTimeout = 10 // 10초
+	}
+	if c.ONVIF.PTZRole == "" {
+		c.ONVIF.PTZRole = "operator"
+	}
+
+	// 이벤트 녹화 기본값
+	if c.Events.Record.PreRoll == 0 {
+		c.Events.Record.PreRoll = 5 // 5초
+	}
+	if c.Events.Record.PostRoll == 0 {
+		c.Events.Record.PostRoll = 10 // 10초
+	}
+
+	// 웹훅 설정 기본값
+	if c.Webhooks.Timeout == 0 {
+		c.Webhooks.Timeout = 10 // 10초
+	}
+	if c.Webhooks.MaxAttempts == 0 {
+		c.Webhooks.MaxAttempts = 8
+	}
+	if c.Webhooks.RetryBackoff == 0 {
+		c.Webhooks.RetryBackoff = 5 // 5초
+	}
+	if c.Webhooks.MaxBackoff == 0 {
+		c.Webhooks.MaxBackoff = 3600 // 1시간
+	}
+
+	// 스냅샷 설정 기본값
+	if c.Snapshot.Timeout == 0 {
+		c.Snapshot.Timeout = 5 // 5초
+	}
+	if c.Snapshot.MaxConcurrent == 0 {
+		c.Snapshot.MaxConcurrent = 2
+	}
+	if c.Snapshot.Quality == 0 {
+		c.Snapshot.Quality = 80
+	}
+
+	// 트랜스코딩 설정 기본값
+	if c.Transcode.FFmpegPath == "" {
+		c.Transcode.FFmpegPath = "ffmpeg"
+	}
+	if c.Transcode.H264Encoder == "" {
+		c.Transcode.H264Encoder = "libx264"
+	}
+	if c.Transcode.H265Encoder == "" {
+		c.Transcode.H265Encoder = "libx265"
+	}
+	if c.Transcode.CloseAfter == 0 {
+		c.Transcode.CloseAfter = 15 // 15초
+	}
+	if c.Transcode.StartTimeout == 0 {
+		c.Transcode.StartTimeout = 10 // 10초
+	}
+
+	// 메트릭 설정 기본값
+	if c.Metrics.Port == 0 {
+		c.Metrics.Port = 9090
+	}
+	if c.Metrics.Interval == 0 {
+		c.Metrics.Interval = 10 // 10초
+	}
 }
 
 // Validate는 설정값의 유효성을 검증합니다
@@ -224,6 +538,59 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid ws_port: %d", c.Server.WSPort)
 	}
 
+	if c.Server.ConfigWatchInterval < 0 {
+		return fmt.Errorf("config_watch_interval must not be negative")
+	}
+	if c.Server.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdown_timeout must not be negative")
+	}
+	if c.Server.StatusInterval < 0 {
+		return fmt.Errorf("status_interval must not be negative")
+	}
+
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("invalid trusted_proxies entry %q (expected IP or CIDR)", proxy)
+			}
+		}
+	}
+
+	// 경로 이름 검증 (DerivedStreamSeparator는 트랜스코딩 파생 스트림 ID에만 사용)
+	for name, path := range c.Paths {
+		if name == "" || strings.Contains(name, DerivedStreamSeparator) {
+			return fmt.Errorf("invalid path name %q", name)
+		}
+		if path.Source != "" && path.RunOnDemand != "" {
+			return fmt.Errorf("path %s: source and runOnDemand are mutually exclusive", name)
+		}
+		if len(path.BackupSources) > 0 && path.Source == "" {
+			return fmt.Errorf("path %s: backupSources requires source", name)
+		}
+		for field, value := range map[string]string{
+			"runOnDemandCloseAfter":   path.RunOnDemandCloseAfter,
+			"runOnDemandStartTimeout": path.RunOnDemandStartTimeout,
+			"failbackAfter":           path.FailbackAfter,
+		} {
+			if value == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return fmt.Errorf("path %s: invalid %s %q", name, field, value)
+			}
+		}
+	}
+
+	// 테스트 패턴 스트림 검증
+	if test := c.RTSP.TestStream; test.URL != "" {
+		if _, err := testsrc.ParseURL(test.URL); err != nil {
+			return fmt.Errorf("invalid rtsp test_stream url: %w", err)
+		}
+		if c.Paths[test.Name].Source != test.URL {
+			return fmt.Errorf("rtsp test_stream name %q conflicts with an existing path", test.Name)
+		}
+	}
+
 	if c.RTSP.Pool.MaxStreams <= 0 {
 		return fmt.Errorf("max_streams must be positive")
 	}
@@ -245,5 +612,117 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// 녹화 설정 검증
+	if c.Record.Enabled {
+		if c.Record.SegmentDuration <= 0 {
+			return fmt.Errorf("record segment_duration must be positive")
+		}
+		if c.Record.PartDuration <= 0 {
+			return fmt.Errorf("record part_duration must be positive")
+		}
+		if c.Record.DeleteAfter < 0 {
+			return fmt.Errorf("record delete_after must not be negative")
+		}
+	}
+
+	// RTMP/SRT 수신 설정 검증
+	if c.Ingest.RTMP.Enabled && (c.Ingest.RTMP.Port <= 0 || c.Ingest.RTMP.Port > 65535) {
+		return fmt.Errorf("invalid ingest rtmp port: %d", c.Ingest.RTMP.Port)
+	}
+	if c.Ingest.SRT.Enabled {
+		if c.Ingest.SRT.Port <= 0 || c.Ingest.SRT.Port > 65535 {
+			return fmt.Errorf("invalid ingest srt port: %d", c.Ingest.SRT.Port)
+		}
+		// SRT passphrase는 10~79자
+		if n := len(c.Ingest.SRT.Passphrase); n != 0 && (n < 10 || n > 79) {
+			return fmt.Errorf("ingest srt passphrase must be 10-79 characters")
+		}
+	}
+	if c.Ingest.RequireStreamKey && len(c.Ingest.StreamKeys) == 0 {
+		return fmt.Errorf("ingest require_stream_key requires at least one stream_keys entry")
+	}
+
+	// ONVIF 설정 검증
+	if c.ONVIF.Enabled && (c.ONVIF.DiscoveryTimeout < 0 || c.ONVIF.RequestTimeout < 0) {
+		return fmt.Errorf("onvif timeouts must not be negative")
+	}
+	// viewer는 카메라를 움직일 수 없음
+	if c.ONVIF.PTZRole != "operator" && c.ONVIF.PTZRole != "admin" {
+		return fmt.Errorf("onvif ptz_role must be operator or admin: %q", c.ONVIF.PTZRole)
+	}
+
+	// 이벤트 설정 검증
+	if c.Events.RetentionDays < 0 {
+		return fmt.Errorf("events retention_days must not be negative")
+	}
+	if c.Events.Record.Enabled && (c.Events.Record.PreRoll < 0 || c.Events.Record.PostRoll <= 0) {
+		return fmt.Errorf("events record pre_roll must not be negative and post_roll must be positive")
+	}
+
+	// 웹훅 설정 검증
+	if c.Webhooks.Enabled {
+		if c.Webhooks.Timeout < 0 || c.Webhooks.MaxAttempts < 0 || c.Webhooks.RetryBackoff < 0 || c.Webhooks.MaxBackoff < 0 {
+			return fmt.Errorf("webhooks timeout, max_attempts, retry_backoff and max_backoff must not be negative")
+		}
+		if c.Webhooks.RetentionDays < 0 {
+			return fmt.Errorf("webhooks retention_days must not be negative")
+		}
+	}
+
+	// 스냅샷 설정 검증
+	if c.Snapshot.Enabled {
+		if c.Snapshot.Interval < 0 || c.Snapshot.Timeout < 0 || c.Snapshot.MaxConcurrent < 0 {
+			return fmt.Errorf("snapshot interval, timeout and max_concurrent must not be negative")
+		}
+		if c.Snapshot.Quality < 1 || c.Snapshot.Quality > 100 {
+			return fmt.Errorf("snapshot quality must be between 1 and 100: %d", c.Snapshot.Quality)
+		}
+	}
+
+	// 트랜스코딩 설정 검증 (프로필 값은 시작 시 DB 동기화 과정에서 검증)
+	if c.Transcode.Enabled {
+		if !c.RTSP.Server.Enabled {
+			return fmt.Errorf("transcode requires rtsp.server.enabled")
+		}
+		if c.Transcode.CloseAfter < 0 || c.Transcode.StartTimeout < 0 {
+			return fmt.Errorf("transcode close_after and start_timeout must not be negative")
+		}
+	}
+
+	// 메트릭 설정 검증 (API 서버와 별도 포트에서 서비스)
+	if c.Metrics.Enabled {
+		if c.Metrics.Port < 0 || c.Metrics.Port > 65535 {
+			return fmt.Errorf("invalid metrics port: %d", c.Metrics.Port)
+		}
+		if c.Metrics.Port == c.Server.HTTPPort || c.Metrics.Port == c.Server.WSPort {
+			return fmt.Errorf("metrics port %d conflicts with server ports", c.Metrics.Port)
+		}
+		if c.Metrics.Interval < 0 {
+			return fmt.Errorf("metrics interval must not be negative")
+		}
+	}
+
+	// 인증 설정 검증
+	if c.Auth.Enabled && len(c.Auth.APIKeys) == 0 && c.Auth.JWT.JWKS == "" {
+		return fmt.Errorf("auth requires at least one api_key or jwt.jwks")
+	}
+	for i, key := range c.Auth.APIKeys {
+		if key.Key == "" {
+			return fmt.Errorf("auth api_keys[%d]: key is required", i)
+		}
+		switch key.Role {
+		case "admin", "operator", "viewer":
+		default:
+			return fmt.Errorf("auth api_keys[%d]: invalid role %q", i, key.Role)
+		}
+		for _, stream := range key.Streams {
+			if pattern, ok := strings.CutPrefix(stream, "~"); ok {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("auth api_keys[%d]: invalid stream pattern %q: %w", i, stream, err)
+				}
+			}
+		}
+	}
+
 	return nil
 }
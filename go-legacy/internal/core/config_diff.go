@@ -0,0 +1,132 @@
+package core
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// reloadableFields는 재시작 없이 적용할 수 있는 설정 경로입니다 (yaml 키 기준, 하위 경로 포함)
+var reloadableFields = []string{
+	"paths",
+	"logging.level",
+	"hls",
+	"webrtc.ice_servers",
+	"webrtc.settings.max_peers",
+	"rtsp.client",
+	"rtsp.test_stream",        // paths에 반영되어 함께 다시 로드됨
+	"server.shutdown_timeout", // 종료 시점에 읽음
+}
+
+// RestartRequiredError는 재시작이 필요한 설정이 변경되었을 때 반환됩니다
+type RestartRequiredError struct {
+	Fields []string
+}
+
+func (e *RestartRequiredError) Error() string {
+	return "config changes require server restart: " + strings.Join(e.Fields, ", ")
+}
+
+// DiffConfig는 두 설정에서 변경된 항목의 yaml 경로 목록을 반환합니다 (예: "hls.segment_count")
+// 구조체 값을 가진 맵(paths 등)은 항목별로 비교합니다 (예: "paths.cam1")
+func DiffConfig(oldConfig, newConfig *Config) []string {
+	changed := []string{}
+	diffValue("", reflect.ValueOf(oldConfig).Elem(), reflect.ValueOf(newConfig).Elem(), &changed)
+	sort.Strings(changed)
+	return changed
+}
+
+// RestartRequired는 변경 항목 중 재시작 없이 적용할 수 없는 항목을 반환합니다
+func RestartRequired(changed []string) []string {
+	var fields []string
+	for _, field := range changed {
+		if !isReloadable(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func isReloadable(field string) bool {
+	for _, prefix := range reloadableFields {
+		if field == prefix || strings.HasPrefix(field, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func diffValue(path string, a, b reflect.Value, changed *[]string) {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*changed = append(*changed, path)
+			}
+			return
+		}
+		diffValue(path, a.Elem(), b.Elem(), changed)
+
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(t.Field(i).Name)
+			}
+			diffValue(joinPath(path, name), a.Field(i), b.Field(i), changed)
+		}
+
+	case reflect.Map:
+		// 스칼라 값 맵(stream_keys 등)은 키가 비밀값일 수 있으므로 통째로 비교
+		if a.Type().Key().Kind() != reflect.String || a.Type().Elem().Kind() != reflect.Struct {
+			if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+				*changed = append(*changed, path)
+			}
+			return
+		}
+
+		keys := make(map[string]struct{})
+		for _, key := range a.MapKeys() {
+			keys[key.String()] = struct{}{}
+		}
+		for _, key := range b.MapKeys() {
+			keys[key.String()] = struct{}{}
+		}
+		for key := range keys {
+			k := reflect.ValueOf(key).Convert(a.Type().Key())
+			av, bv := a.MapIndex(k), b.MapIndex(k)
+			if !av.IsValid() || !bv.IsValid() || !reflect.DeepEqual(av.Interface(), bv.Interface()) {
+				*changed = append(*changed, joinPath(path, key))
+			}
+		}
+
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*changed = append(*changed, path)
+		}
+	}
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// ReloadResult는 설정 다시 로드 결과입니다
+type ReloadResult struct {
+	Changed []string `json:"changed"` // 변경된 설정 항목
+
+	// 추가/변경/삭제된 경로 (스트림 ID)
+	StreamsAdded   []string `json:"streams_added"`
+	StreamsUpdated []string `json:"streams_updated"`
+	StreamsRemoved []string `json:"streams_removed"`
+
+	// 적용 중 실패한 항목 (나머지 변경은 적용됨)
+	Errors []string `json:"errors,omitempty"`
+}
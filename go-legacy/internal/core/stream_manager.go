@@ -1,8 +1,10 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -10,6 +12,23 @@ import (
 	"go.uber.org/zap"
 )
 
+// DerivedStreamSeparator는 파생 스트림 ID의 구분자입니다 (예: CAM1~h264_720p = CAM1의 트랜스코딩 스트림)
+const DerivedStreamSeparator = "~"
+
+// DerivedStreamID는 원본 스트림 ID와 프로필 이름으로 파생 스트림 ID를 만듭니다
+func DerivedStreamID(baseID, profile string) string {
+	return baseID + DerivedStreamSeparator + profile
+}
+
+// SplitDerivedStreamID는 파생 스트림 ID를 원본 스트림 ID와 프로필 이름으로 나눕니다
+func SplitDerivedStreamID(id string) (baseID, profile string, ok bool) {
+	baseID, profile, ok = strings.Cut(id, DerivedStreamSeparator)
+	if !ok || baseID == "" || profile == "" {
+		return id, "", false
+	}
+	return baseID, profile, true
+}
+
 // StreamManager는 스트림을 관리합니다
 type StreamManager struct {
 	ctx       context.Context
@@ -21,6 +40,70 @@ type StreamManager struct {
 
 	// Phase 2.3: config 기반 버퍼 크기
 	videoBufferSize int
+
+	// 스트림 상태 변화 콜백 (경로 훅, 웹훅)
+	hooks atomic.Pointer[StreamHooks]
+}
+
+// StreamHooks는 스트림 상태 변화 콜백입니다 (nil인 콜백은 호출하지 않음)
+// 콜백은 상태를 바꾼 고루틴에서 잠금 없이 호출되므로 오래 막히지 않아야 합니다
+type StreamHooks struct {
+	OnReady    func(stream *Stream, sourceType, sourceID string) // 소스 연결 또는 송출 시작
+	OnNotReady func(stream *Stream, sourceType, sourceID string) // 소스 연결 끊김, 송출 종료, 스트림 삭제
+	OnRead     func(stream *Stream, readerType, readerID string) // StreamReader 구독
+	OnUnread   func(stream *Stream, readerType, readerID string) // StreamReader 구독 해제
+
+	OnCodecChange func(stream *Stream, oldCodec, newCodec string) // 비디오 코덱 감지 (처음이면 oldCodec은 "") 또는 재연결 후 변경
+
+	OnSubscribersChange func(stream *Stream, count int) // 구독자(시청, 녹화, HLS 등) 수 변화
+}
+
+// CombineStreamHooks는 여러 콜백 묶음을 순서대로 모두 호출하는 하나의 콜백 묶음으로 합칩니다
+func CombineStreamHooks(list ...StreamHooks) StreamHooks {
+	return StreamHooks{
+		OnReady: func(stream *Stream, sourceType, sourceID string) {
+			for _, hooks := range list {
+				if hooks.OnReady != nil {
+					hooks.OnReady(stream, sourceType, sourceID)
+				}
+			}
+		},
+		OnNotReady: func(stream *Stream, sourceType, sourceID string) {
+			for _, hooks := range list {
+				if hooks.OnNotReady != nil {
+					hooks.OnNotReady(stream, sourceType, sourceID)
+				}
+			}
+		},
+		OnRead: func(stream *Stream, readerType, readerID string) {
+			for _, hooks := range list {
+				if hooks.OnRead != nil {
+					hooks.OnRead(stream, readerType, readerID)
+				}
+			}
+		},
+		OnUnread: func(stream *Stream, readerType, readerID string) {
+			for _, hooks := range list {
+				if hooks.OnUnread != nil {
+					hooks.OnUnread(stream, readerType, readerID)
+				}
+			}
+		},
+		OnCodecChange: func(stream *Stream, oldCodec, newCodec string) {
+			for _, hooks := range list {
+				if hooks.OnCodecChange != nil {
+					hooks.OnCodecChange(stream, oldCodec, newCodec)
+				}
+			}
+		},
+		OnSubscribersChange: func(stream *Stream, count int) {
+			for _, hooks := range list {
+				if hooks.OnSubscribersChange != nil {
+					hooks.OnSubscribersChange(stream, count)
+				}
+			}
+		},
+	}
 }
 
 // subscriberWorker는 구독자와 전용 워커를 관리합니다
@@ -41,6 +124,7 @@ type Stream struct {
 	// 미디어 정보
 	videoCodec string // H264 또는 H265
 	codecMutex sync.RWMutex
+	videoSPS   atomic.Pointer[[]byte] // 마지막으로 받은 SPS (HLS CODECS 속성 등)
 
 	// 구독자 관리 (워커 풀 패턴)
 	subscribers map[string]*subscriberWorker
@@ -52,6 +136,11 @@ type Stream struct {
 	bytesReceived   atomic.Uint64
 	bytesSent       atomic.Uint64
 
+	// 드롭/재연결 통계 (메트릭용)
+	bufferDrops      atomic.Uint64 // 수신 버퍼가 가득 차 버린 패킷 수
+	subscriberDrops  atomic.Uint64 // 구독자 워커 버퍼가 가득 차 버린 패킷 수 (구독자별 합계)
+	sourceReconnects atomic.Uint64 // 소스 연결이 끊겨 재연결한 횟수
+
 	// 버퍼링
 	packetBuffer chan *rtp.Packet
 
@@ -64,6 +153,14 @@ type Stream struct {
 
 	// 워커 슬라이스 풀 (Phase 2.1 최적화 - 메모리 재사용)
 	workerSlicePool sync.Pool
+
+	// 소스 준비 상태 (패킷을 보내는 소스/송출자가 있음)
+	ready      bool
+	sourceType string // 예: rtspSource, rtspSession, rtmpConn
+	sourceID   string
+	readyMutex sync.Mutex
+
+	hooks *atomic.Pointer[StreamHooks]
 }
 
 // StreamSubscriber는 스트림 구독자 인터페이스
@@ -72,6 +169,13 @@ type StreamSubscriber interface {
 	GetID() string
 }
 
+// StreamReader는 외부 시청자 구독자가 구현합니다 (구독/해제 시 OnRead/OnUnread 호출)
+// 녹화, 스냅샷 등 서버 내부 구독자는 구현하지 않습니다
+type StreamReader interface {
+	StreamSubscriber
+	ReaderType() string // 예: webRTCSession, rtspSession
+}
+
 // NewStreamManager는 새로운 스트림 관리자를 생성합니다
 func NewStreamManager(logger *zap.Logger, videoBufferSize int) *StreamManager {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -106,6 +210,7 @@ func (sm *StreamManager) CreateStream(id, name string) (*Stream, error) {
 		subscribers:  make(map[string]*subscriberWorker),
 		packetBuffer: make(chan *rtp.Packet, sm.videoBufferSize), // Phase 2.3: config 기반 버퍼 크기
 		ctx:          sm.ctx,
+		hooks:        &sm.hooks,
 		workerSlicePool: sync.Pool{
 			New: func() interface{} {
 				// 초기 capacity 20 (예상 구독자 수)
@@ -127,6 +232,11 @@ func (sm *StreamManager) CreateStream(id, name string) (*Stream, error) {
 	return stream, nil
 }
 
+// SetHooks는 스트림 상태 변화 콜백을 설정합니다 (이미 생성된 스트림에도 적용)
+func (sm *StreamManager) SetHooks(hooks StreamHooks) {
+	sm.hooks.Store(&hooks)
+}
+
 // GetStream은 스트림을 조회합니다
 func (sm *StreamManager) GetStream(id string) (*Stream, error) {
 	sm.mutex.RLock()
@@ -143,22 +253,16 @@ func (sm *StreamManager) GetStream(id string) (*Stream, error) {
 // RemoveStream은 스트림을 제거합니다
 func (sm *StreamManager) RemoveStream(id string) error {
 	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-
 	stream, exists := sm.streams[id]
 	if !exists {
+		sm.mutex.Unlock()
 		return fmt.Errorf("stream %s not found", id)
 	}
-
-	// 스트림을 닫힌 상태로 표시 (WritePacket 차단)
-	stream.closeMutex.Lock()
-	stream.closed = true
-	stream.closeMutex.Unlock()
-
-	// 패킷 버퍼 닫기
-	close(stream.packetBuffer)
-
 	delete(sm.streams, id)
+	sm.mutex.Unlock()
+
+	// 잠금 밖에서 닫음 (OnNotReady 콜백이 스트림 관리자를 사용할 수 있음)
+	stream.close()
 
 	sm.logger.Info("Stream removed",
 		zap.String("stream_id", id),
@@ -190,20 +294,93 @@ func (sm *StreamManager) Close() {
 	defer sm.mutex.Unlock()
 
 	for id, stream := range sm.streams {
-		close(stream.packetBuffer)
+		stream.close()
 		delete(sm.streams, id)
 	}
 }
 
+// close는 스트림을 닫힌 상태로 표시하고 패킷 버퍼를 닫습니다
+// closeMutex 쓰기 잠금으로 진행 중인 WritePacket이 끝날 때까지 기다리므로 닫힌 채널에 쓰지 않습니다
+func (s *Stream) close() {
+	s.closeMutex.Lock()
+	if s.closed {
+		s.closeMutex.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.packetBuffer)
+	s.closeMutex.Unlock()
+
+	s.SetNotReady()
+}
+
+// SetReady는 소스가 연결되어 스트림이 준비되었음을 표시합니다
+// 준비되지 않은 상태에서 바뀔 때만 OnReady를 호출합니다
+func (s *Stream) SetReady(sourceType, sourceID string) {
+	s.readyMutex.Lock()
+	if s.ready {
+		s.readyMutex.Unlock()
+		return
+	}
+	s.ready = true
+	s.sourceType = sourceType
+	s.sourceID = sourceID
+	s.readyMutex.Unlock()
+
+	s.logger.Info("Stream ready",
+		zap.String("source_type", sourceType),
+		zap.String("source_id", sourceID),
+	)
+
+	if hooks := s.hooks.Load(); hooks != nil && hooks.OnReady != nil {
+		hooks.OnReady(s, sourceType, sourceID)
+	}
+}
+
+// SetNotReady는 소스 연결이 끊겨 스트림이 준비되지 않았음을 표시합니다
+// 준비된 상태에서 바뀔 때만 OnNotReady를 호출합니다
+func (s *Stream) SetNotReady() {
+	s.readyMutex.Lock()
+	if !s.ready {
+		s.readyMutex.Unlock()
+		return
+	}
+	s.ready = false
+	sourceType, sourceID := s.sourceType, s.sourceID
+	s.sourceType, s.sourceID = "", ""
+	s.readyMutex.Unlock()
+
+	s.logger.Info("Stream not ready", zap.String("source_type", sourceType))
+
+	if hooks := s.hooks.Load(); hooks != nil && hooks.OnNotReady != nil {
+		hooks.OnNotReady(s, sourceType, sourceID)
+	}
+}
+
+// IsReady는 스트림이 준비되었는지 반환합니다
+func (s *Stream) IsReady() bool {
+	s.readyMutex.Lock()
+	defer s.readyMutex.Unlock()
+	return s.ready
+}
+
+// GetSource는 준비된 스트림의 소스 종류와 ID를 반환합니다 (준비되지 않았으면 빈 문자열)
+func (s *Stream) GetSource() (sourceType, sourceID string) {
+	s.readyMutex.Lock()
+	defer s.readyMutex.Unlock()
+	return s.sourceType, s.sourceID
+}
+
 // WritePacket은 스트림에 RTP 패킷을 씁니다
 func (s *Stream) WritePacket(pkt *rtp.Packet) error {
-	// 스트림이 닫혔는지 확인
+	// 스트림이 닫혔는지 확인 (쓰는 동안 읽기 잠금을 유지해 close와 경쟁하지 않음)
 	s.closeMutex.RLock()
+	defer s.closeMutex.RUnlock()
 	if s.closed {
-		s.closeMutex.RUnlock()
 		return fmt.Errorf("stream %s is closed", s.id)
 	}
-	s.closeMutex.RUnlock()
+
+	s.captureSPS(pkt.Payload)
 
 	select {
 	case s.packetBuffer <- pkt:
@@ -216,24 +393,37 @@ func (s *Stream) WritePacket(pkt *rtp.Packet) error {
 		s.logger.Warn("Packet buffer full, dropping oldest packet")
 		select {
 		case <-s.packetBuffer:
+			s.bufferDrops.Add(1)
 		default:
 		}
 
-		// 다시 닫혔는지 확인 (경쟁 조건 방지)
-		s.closeMutex.RLock()
-		if s.closed {
-			s.closeMutex.RUnlock()
-			return fmt.Errorf("stream %s is closed", s.id)
+		// 잠금을 쥔 채 막히지 않도록 다른 writer가 먼저 채웠으면 이 패킷을 버림
+		select {
+		case s.packetBuffer <- pkt:
+		default:
+			s.bufferDrops.Add(1)
 		}
-		s.closeMutex.RUnlock()
-
-		s.packetBuffer <- pkt
 		return nil
 	}
 }
 
 // Subscribe는 스트림 구독을 추가합니다 (워커 풀 패턴)
 func (s *Stream) Subscribe(subscriber StreamSubscriber) error {
+	if err := s.subscribe(subscriber); err != nil {
+		return err
+	}
+
+	if reader, ok := subscriber.(StreamReader); ok {
+		if hooks := s.hooks.Load(); hooks != nil && hooks.OnRead != nil {
+			hooks.OnRead(s, reader.ReaderType(), reader.GetID())
+		}
+	}
+	s.notifySubscribersChange()
+
+	return nil
+}
+
+func (s *Stream) subscribe(subscriber StreamSubscriber) error {
 	s.subMutex.Lock()
 	defer s.subMutex.Unlock()
 
@@ -267,12 +457,35 @@ func (s *Stream) Subscribe(subscriber StreamSubscriber) error {
 
 // Unsubscribe는 스트림 구독을 제거합니다
 func (s *Stream) Unsubscribe(subscriberID string) error {
+	subscriber, err := s.unsubscribe(subscriberID)
+	if err != nil {
+		return err
+	}
+
+	if reader, ok := subscriber.(StreamReader); ok {
+		if hooks := s.hooks.Load(); hooks != nil && hooks.OnUnread != nil {
+			hooks.OnUnread(s, reader.ReaderType(), subscriberID)
+		}
+	}
+	s.notifySubscribersChange()
+
+	return nil
+}
+
+// notifySubscribersChange는 현재 구독자 수로 OnSubscribersChange를 호출합니다
+func (s *Stream) notifySubscribersChange() {
+	if hooks := s.hooks.Load(); hooks != nil && hooks.OnSubscribersChange != nil {
+		hooks.OnSubscribersChange(s, s.GetSubscriberCount())
+	}
+}
+
+func (s *Stream) unsubscribe(subscriberID string) (StreamSubscriber, error) {
 	s.subMutex.Lock()
 	defer s.subMutex.Unlock()
 
 	worker, exists := s.subscribers[subscriberID]
 	if !exists {
-		return fmt.Errorf("subscriber %s not found", subscriberID)
+		return nil, fmt.Errorf("subscriber %s not found", subscriberID)
 	}
 
 	// 워커 종료
@@ -286,7 +499,7 @@ func (s *Stream) Unsubscribe(subscriberID string) error {
 		zap.Int("total_subscribers", len(s.subscribers)),
 	)
 
-	return nil
+	return worker.sub, nil
 }
 
 // distributePackets는 패킷을 모든 구독자 워커에게 배포합니다 (워커 풀 패턴 + sync.Pool)
@@ -318,6 +531,7 @@ func (s *Stream) distributePackets() {
 					// 성공적으로 전송
 				default:
 					// 워커 버퍼 가득 참, 패킷 드롭 (블로킹 방지)
+					s.subscriberDrops.Add(1)
 					worker.logger.Debug("Worker buffer full, dropping packet")
 				}
 			}
@@ -365,6 +579,21 @@ func (s *Stream) GetStats() (packetsReceived, packetsSent, bytesReceived, bytesS
 	return s.packetsReceived.Load(), s.packetsSent.Load(), s.bytesReceived.Load(), s.bytesSent.Load()
 }
 
+// GetDropStats는 드롭된 패킷 수를 반환합니다 (수신 버퍼, 구독자 워커 버퍼)
+func (s *Stream) GetDropStats() (bufferDropped, subscriberDropped uint64) {
+	return s.bufferDrops.Load(), s.subscriberDrops.Load()
+}
+
+// AddSourceReconnect는 소스 재연결 횟수를 증가시킵니다 (소스의 연결 끊김 콜백에서 호출)
+func (s *Stream) AddSourceReconnect() {
+	s.sourceReconnects.Add(1)
+}
+
+// GetSourceReconnects는 소스 재연결 횟수를 반환합니다
+func (s *Stream) GetSourceReconnects() uint64 {
+	return s.sourceReconnects.Load()
+}
+
 // GetSubscriberCount는 구독자 수를 반환합니다
 func (s *Stream) GetSubscriberCount() int {
 	s.subMutex.RLock()
@@ -373,11 +602,25 @@ func (s *Stream) GetSubscriberCount() int {
 }
 
 // SetVideoCodec는 스트림의 비디오 코덱을 설정합니다
+// 처음 감지되었거나 이전에 감지된 코덱과 다르면 OnCodecChange를 호출합니다
 func (s *Stream) SetVideoCodec(codec string) {
 	s.codecMutex.Lock()
-	defer s.codecMutex.Unlock()
+	oldCodec := s.videoCodec
 	s.videoCodec = codec
+	s.codecMutex.Unlock()
+
+	if oldCodec != codec {
+		s.videoSPS.Store(nil)
+	}
+
 	s.logger.Info("Video codec set", zap.String("codec", codec))
+
+	if oldCodec == codec {
+		return
+	}
+	if hooks := s.hooks.Load(); hooks != nil && hooks.OnCodecChange != nil {
+		hooks.OnCodecChange(s, oldCodec, codec)
+	}
 }
 
 // GetVideoCodec는 스트림의 비디오 코덱을 반환합니다
@@ -387,6 +630,64 @@ func (s *Stream) GetVideoCodec() string {
 	return s.videoCodec
 }
 
+// GetVideoSPS는 마지막으로 받은 SPS NAL 유닛을 반환합니다 (아직 받지 못했으면 nil)
+func (s *Stream) GetVideoSPS() []byte {
+	if sps := s.videoSPS.Load(); sps != nil {
+		return *sps
+	}
+	return nil
+}
+
+// captureSPS는 RTP 페이로드에 SPS가 있으면 저장합니다 (단일 NAL 유닛, H.264 STAP-A, H.265 AP)
+func (s *Stream) captureSPS(payload []byte) {
+	if len(payload) < 2 {
+		return
+	}
+
+	switch s.GetVideoCodec() {
+	case "H264":
+		switch payload[0] & 0x1F {
+		case 7: // SPS
+			s.storeSPS(payload)
+		case 24: // STAP-A
+			s.captureAggregatedSPS(payload[1:], func(nalu []byte) bool { return nalu[0]&0x1F == 7 })
+		}
+
+	case "H265":
+		switch (payload[0] >> 1) & 0x3F {
+		case 33: // SPS_NUT
+			s.storeSPS(payload)
+		case 48: // AP
+			s.captureAggregatedSPS(payload[2:], func(nalu []byte) bool { return (nalu[0]>>1)&0x3F == 33 })
+		}
+	}
+}
+
+// captureAggregatedSPS는 집합 패킷(2바이트 길이 + NAL 유닛 반복)에서 SPS를 찾아 저장합니다
+func (s *Stream) captureAggregatedSPS(data []byte, isSPS func([]byte) bool) {
+	for len(data) > 2 {
+		size := int(data[0])<<8 | int(data[1])
+		data = data[2:]
+		if size == 0 || size > len(data) {
+			return
+		}
+		if isSPS(data[:size]) {
+			s.storeSPS(data[:size])
+			return
+		}
+		data = data[size:]
+	}
+}
+
+// storeSPS는 바뀐 경우에만 SPS 복사본을 저장합니다
+func (s *Stream) storeSPS(sps []byte) {
+	if old := s.videoSPS.Load(); old != nil && bytes.Equal(*old, sps) {
+		return
+	}
+	stored := append([]byte(nil), sps...)
+	s.videoSPS.Store(&stored)
+}
+
 // GetID는 스트림 ID를 반환합니다
 func (s *Stream) GetID() string {
 	return s.id
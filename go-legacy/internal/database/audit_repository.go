@@ -0,0 +1,184 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AuditEntry는 감사 로그 항목입니다
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	Role       string    `json:"role,omitempty"`
+	Action     string    `json:"action"`
+	StreamID   string    `json:"stream_id,omitempty"`
+	Protocol   string    `json:"protocol,omitempty"` // webrtc, hls, rtsp, http
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	SessionID  string    `json:"session_id,omitempty"` // 시청 시작/종료 항목 연결용
+	Bytes      uint64    `json:"bytes,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"` // 시청 종료 항목의 시청 시간
+	Details    string    `json:"details,omitempty"`
+}
+
+// AuditFilter는 감사 로그 조회 조건입니다 (빈 값은 조건 없음)
+type AuditFilter struct {
+	User     string
+	StreamID string
+	Action   string
+	Start    *time.Time
+	End      *time.Time
+	Limit    int // 0 이하면 제한 없음
+	Offset   int
+}
+
+// AuditRepository는 감사 로그 데이터 액세스 레이어입니다
+type AuditRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewAuditRepository는 새로운 AuditRepository를 생성합니다
+func NewAuditRepository(db *DB, logger *zap.Logger) *AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Insert는 감사 로그 항목을 추가합니다
+func (r *AuditRepository) Insert(entry *AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (time_ms, user, role, action, stream_id, protocol, remote_addr, session_id, bytes, duration_ms, details)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Conn().Exec(
+		query,
+		entry.Time.UnixMilli(),
+		entry.User,
+		entry.Role,
+		entry.Action,
+		entry.StreamID,
+		entry.Protocol,
+		entry.RemoteAddr,
+		entry.SessionID,
+		int64(entry.Bytes),
+		entry.DurationMs,
+		entry.Details,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		entry.ID = id
+	}
+
+	return nil
+}
+
+// Query는 조건에 맞는 감사 로그를 최신순으로 조회합니다
+// 두 번째 반환값은 Limit/Offset 적용 전 전체 개수입니다
+func (r *AuditRepository) Query(filter AuditFilter) ([]*AuditEntry, int, error) {
+	where, args := filter.where()
+
+	var total int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM audit_log`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `
+		SELECT id, time_ms, user, role, action, stream_id, protocol, remote_addr, session_id, bytes, duration_ms, details
+		FROM audit_log` + where + `
+		ORDER BY time_ms DESC, id DESC
+	`
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Conn().Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*AuditEntry, 0)
+	for rows.Next() {
+		entry := &AuditEntry{}
+		var timeMs, bytes int64
+
+		if err := rows.Scan(
+			&entry.ID,
+			&timeMs,
+			&entry.User,
+			&entry.Role,
+			&entry.Action,
+			&entry.StreamID,
+			&entry.Protocol,
+			&entry.RemoteAddr,
+			&entry.SessionID,
+			&bytes,
+			&entry.DurationMs,
+			&entry.Details,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		entry.Time = time.UnixMilli(timeMs).UTC()
+		entry.Bytes = uint64(bytes)
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// DeleteBefore는 지정 시각 이전의 감사 로그를 삭제합니다 (보관 기간 정리용)
+func (r *AuditRepository) DeleteBefore(t time.Time) (int64, error) {
+	result, err := r.db.Conn().Exec(`DELETE FROM audit_log WHERE time_ms < ?`, t.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete audit entries: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// where는 조회 조건을 SQL WHERE 절로 변환합니다
+func (f AuditFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if f.User != "" {
+		conds = append(conds, "user = ?")
+		args = append(args, f.User)
+	}
+	if f.StreamID != "" {
+		conds = append(conds, "stream_id = ?")
+		args = append(args, f.StreamID)
+	}
+	if f.Action != "" {
+		// "view"는 "view.start", "view.end"를 모두 포함
+		conds = append(conds, "(action = ? OR action LIKE ?)")
+		args = append(args, f.Action, f.Action+".%")
+	}
+	if f.Start != nil {
+		conds = append(conds, "time_ms >= ?")
+		args = append(args, f.Start.UnixMilli())
+	}
+	if f.End != nil {
+		conds = append(conds, "time_ms < ?")
+		args = append(args, f.End.UnixMilli())
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
@@ -25,7 +25,8 @@ func New(dbPath string, logger *zap.Logger) (*DB, error) {
 	}
 
 	// SQLite 연결 열기
-	conn, err := sql.Open("sqlite", dbPath)
+	// 감사 로그 등 백그라운드 쓰기와 API 요청이 겹칠 때 SQLITE_BUSY 대신 잠금 해제를 기다림 (연결마다 적용)
+	conn, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -59,24 +60,225 @@ func (db *DB) migrate() error {
 		id TEXT PRIMARY KEY,
 		name TEXT NOT NULL,
 		source TEXT NOT NULL,
+		source_username TEXT NOT NULL DEFAULT '',
+		source_password TEXT NOT NULL DEFAULT '',
 		source_on_demand BOOLEAN NOT NULL DEFAULT 1,
 		rtsp_transport TEXT NOT NULL DEFAULT 'tcp',
+		onvif_address TEXT NOT NULL DEFAULT '',
+		onvif_profile TEXT NOT NULL DEFAULT '',
+		site_id TEXT NOT NULL DEFAULT '',
+		failback_after INTEGER NOT NULL DEFAULT 0,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_streams_name ON streams(name);
 	CREATE INDEX IF NOT EXISTS idx_streams_created_at ON streams(created_at);
+
+	CREATE TABLE IF NOT EXISTS stream_sources (
+		stream_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		source TEXT NOT NULL,
+		source_username TEXT NOT NULL DEFAULT '',
+		source_password TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (stream_id, position)
+	);
+
+	CREATE TABLE IF NOT EXISTS sites (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		max_streams INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS tags (
+		name TEXT PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS stream_tags (
+		stream_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (stream_id, tag)
+	);
+	CREATE INDEX IF NOT EXISTS idx_stream_tags_tag ON stream_tags(tag);
+
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		time_ms INTEGER NOT NULL,
+		user TEXT NOT NULL DEFAULT '',
+		role TEXT NOT NULL DEFAULT '',
+		action TEXT NOT NULL,
+		stream_id TEXT NOT NULL DEFAULT '',
+		protocol TEXT NOT NULL DEFAULT '',
+		remote_addr TEXT NOT NULL DEFAULT '',
+		session_id TEXT NOT NULL DEFAULT '',
+		bytes INTEGER NOT NULL DEFAULT 0,
+		duration_ms INTEGER NOT NULL DEFAULT 0,
+		details TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_audit_log_time ON audit_log(time_ms);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log(user, time_ms);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_stream ON audit_log(stream_id, time_ms);
+
+	CREATE TABLE IF NOT EXISTS events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		time_ms INTEGER NOT NULL,
+		stream_id TEXT NOT NULL,
+		type TEXT NOT NULL,
+		active BOOLEAN NOT NULL DEFAULT 1,
+		source TEXT NOT NULL DEFAULT '',
+		topic TEXT NOT NULL DEFAULT '',
+		details TEXT NOT NULL DEFAULT '',
+		recording BOOLEAN NOT NULL DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_events_time ON events(time_ms);
+	CREATE INDEX IF NOT EXISTS idx_events_stream ON events(stream_id, time_ms);
+
+	CREATE TABLE IF NOT EXISTS transcode_profiles (
+		name TEXT PRIMARY KEY,
+		codec TEXT NOT NULL,
+		resolution TEXT NOT NULL DEFAULT '',
+		bitrate TEXT NOT NULL DEFAULT '',
+		gop INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS stream_groups (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS stream_renditions (
+		group_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		stream_id TEXT NOT NULL,
+		bandwidth INTEGER NOT NULL DEFAULT 0,
+		width INTEGER NOT NULL DEFAULT 0,
+		height INTEGER NOT NULL DEFAULT 0,
+		position INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (group_id, name)
+	);
+	CREATE INDEX IF NOT EXISTS idx_stream_renditions_stream ON stream_renditions(stream_id);
+
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL DEFAULT '',
+		events TEXT NOT NULL DEFAULT '',
+		enabled BOOLEAN NOT NULL DEFAULT 1,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id INTEGER NOT NULL,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_ms INTEGER NOT NULL,
+		last_status_code INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_ms INTEGER NOT NULL,
+		updated_ms INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_ms);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_ms);
 	`
 
 	if _, err := db.conn.Exec(schema); err != nil {
 		return fmt.Errorf("failed to execute schema: %w", err)
 	}
 
+	// 이전 버전 DB 마이그레이션: 카메라 인증 정보 컬럼 (암호화 저장, source에는 인증 정보를 제외한 URL만 저장)
+	if err := db.addColumnIfMissing("streams", "source_username", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("streams", "source_password", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// ONVIF 가져오기 정보 컬럼
+	if err := db.addColumnIfMissing("streams", "onvif_address", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := db.addColumnIfMissing("streams", "onvif_profile", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// 사이트 컬럼 (인덱스는 컬럼 추가 이후 생성)
+	if err := db.addColumnIfMissing("streams", "site_id", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if _, err := db.conn.Exec(`CREATE INDEX IF NOT EXISTS idx_streams_site ON streams(site_id)`); err != nil {
+		return fmt.Errorf("failed to create streams site index: %w", err)
+	}
+
+	// 예비 소스 복귀 대기 시간 컬럼 (예비 소스는 stream_sources 테이블)
+	if err := db.addColumnIfMissing("streams", "failback_after", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// 스트림 출처 컬럼 (config.yaml에서 생성된 스트림만 설정에서 삭제될 때 함께 삭제)
+	if err := db.addColumnIfMissing("streams", "origin", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
 	db.logger.Info("Database schema migrated successfully")
 	return nil
 }
 
+// addColumnIfMissing은 기존 테이블에 컬럼이 없으면 추가합니다
+func (db *DB) addColumnIfMissing(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to read table info: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    bool
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			return fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read table info: %w", err)
+	}
+
+	if _, err := db.conn.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+
+	db.logger.Info("Database column added",
+		zap.String("table", table),
+		zap.String("column", column),
+	)
+	return nil
+}
+
 // Close는 데이터베이스 연결을 닫습니다
 func (db *DB) Close() error {
 	if db.conn != nil {
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Event는 카메라 이벤트(모션, 라인 크로싱 등)입니다
+type Event struct {
+	ID        int64     `json:"id"`
+	Time      time.Time `json:"time"`
+	StreamID  string    `json:"stream_id"`
+	Type      string    `json:"type"`              // motion, line_crossing, intrusion, tamper, input, video_loss, alarm
+	Active    bool      `json:"active"`            // 발생(true) / 해제(false)
+	Source    string    `json:"source"`            // onvif, http 등 수집 경로
+	Topic     string    `json:"topic,omitempty"`   // 원본 이벤트 이름 (ONVIF 토픽, 제조사 이벤트 타입)
+	Details   string    `json:"details,omitempty"` // 원본 속성 (규칙 이름, 입력 포트 등)
+	Recording bool      `json:"recording"`         // 이벤트 녹화를 시작/연장했는지 여부
+}
+
+// EventFilter는 이벤트 조회 조건입니다 (빈 값은 조건 없음)
+type EventFilter struct {
+	StreamID string
+	Type     string
+	Start    *time.Time
+	End      *time.Time
+	Limit    int // 0 이하면 제한 없음
+	Offset   int
+}
+
+// EventRepository는 이벤트 데이터 액세스 레이어입니다
+type EventRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewEventRepository는 새로운 EventRepository를 생성합니다
+func NewEventRepository(db *DB, logger *zap.Logger) *EventRepository {
+	return &EventRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Insert는 이벤트를 추가합니다
+func (r *EventRepository) Insert(event *Event) error {
+	query := `
+		INSERT INTO events (time_ms, stream_id, type, active, source, topic, details, recording)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Conn().Exec(
+		query,
+		event.Time.UnixMilli(),
+		event.StreamID,
+		event.Type,
+		event.Active,
+		event.Source,
+		event.Topic,
+		event.Details,
+		event.Recording,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert event: %w", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		event.ID = id
+	}
+
+	return nil
+}
+
+// Query는 조건에 맞는 이벤트를 최신순으로 조회합니다
+// 두 번째 반환값은 Limit/Offset 적용 전 전체 개수입니다
+func (r *EventRepository) Query(filter EventFilter) ([]*Event, int, error) {
+	where, args := filter.where()
+
+	var total int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM events`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	query := `
+		SELECT id, time_ms, stream_id, type, active, source, topic, details, recording
+		FROM events` + where + `
+		ORDER BY time_ms DESC, id DESC
+	`
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Conn().Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*Event, 0)
+	for rows.Next() {
+		event := &Event{}
+		var timeMs int64
+
+		if err := rows.Scan(
+			&event.ID,
+			&timeMs,
+			&event.StreamID,
+			&event.Type,
+			&event.Active,
+			&event.Source,
+			&event.Topic,
+			&event.Details,
+			&event.Recording,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		event.Time = time.UnixMilli(timeMs).UTC()
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// DeleteBefore는 지정 시각 이전의 이벤트를 삭제합니다 (보관 기간 정리용)
+func (r *EventRepository) DeleteBefore(t time.Time) (int64, error) {
+	result, err := r.db.Conn().Exec(`DELETE FROM events WHERE time_ms < ?`, t.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete events: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// where는 조회 조건을 SQL WHERE 절로 변환합니다
+func (f EventFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if f.StreamID != "" {
+		conds = append(conds, "stream_id = ?")
+		args = append(args, f.StreamID)
+	}
+	if f.Type != "" {
+		conds = append(conds, "type = ?")
+		args = append(args, f.Type)
+	}
+	if f.Start != nil {
+		conds = append(conds, "time_ms >= ?")
+		args = append(args, f.Start.UnixMilli())
+	}
+	if f.End != nil {
+		conds = append(conds, "time_ms < ?")
+		args = append(args, f.End.UnixMilli())
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
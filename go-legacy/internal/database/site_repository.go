@@ -0,0 +1,246 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Site는 스트림을 묶는 설치 장소입니다 (예: JEJU1, Cheonan)
+// 같은 사이트의 카메라는 보통 하나의 업링크를 공유하므로 동시 연결 수를 제한할 수 있습니다
+type Site struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	MaxStreams  int       `json:"max_streams"` // 동시에 연결할 수 있는 소스 수 (0=제한 없음)
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SiteRepository는 사이트 데이터 액세스 레이어입니다
+type SiteRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewSiteRepository는 새로운 SiteRepository를 생성합니다
+func NewSiteRepository(db *DB, logger *zap.Logger) *SiteRepository {
+	return &SiteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create는 새로운 사이트를 생성합니다
+func (r *SiteRepository) Create(site *Site) error {
+	now := time.Now()
+	site.CreatedAt = now
+	site.UpdatedAt = now
+
+	if _, err := r.db.Conn().Exec(
+		`INSERT INTO sites (id, name, description, max_streams, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		site.ID, site.Name, site.Description, site.MaxStreams, site.CreatedAt, site.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create site: %w", err)
+	}
+
+	r.logger.Info("Site created",
+		zap.String("id", site.ID),
+		zap.Int("max_streams", site.MaxStreams),
+	)
+
+	return nil
+}
+
+// Get은 ID로 사이트를 조회합니다
+func (r *SiteRepository) Get(id string) (*Site, error) {
+	site := &Site{}
+	err := r.db.Conn().QueryRow(
+		`SELECT id, name, description, max_streams, created_at, updated_at FROM sites WHERE id = ?`, id,
+	).Scan(&site.ID, &site.Name, &site.Description, &site.MaxStreams, &site.CreatedAt, &site.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("site not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+
+	return site, nil
+}
+
+// List는 모든 사이트를 이름순으로 조회합니다
+func (r *SiteRepository) List() ([]*Site, error) {
+	rows, err := r.db.Conn().Query(
+		`SELECT id, name, description, max_streams, created_at, updated_at FROM sites ORDER BY name COLLATE NOCASE, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sites: %w", err)
+	}
+	defer rows.Close()
+
+	sites := make([]*Site, 0)
+	for rows.Next() {
+		site := &Site{}
+		if err := rows.Scan(&site.ID, &site.Name, &site.Description, &site.MaxStreams, &site.CreatedAt, &site.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan site: %w", err)
+		}
+		sites = append(sites, site)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sites: %w", err)
+	}
+
+	return sites, nil
+}
+
+// Update는 사이트의 이름, 설명, 동시 연결 제한을 수정합니다
+func (r *SiteRepository) Update(site *Site) error {
+	site.UpdatedAt = time.Now()
+
+	result, err := r.db.Conn().Exec(
+		`UPDATE sites SET name = ?, description = ?, max_streams = ?, updated_at = ? WHERE id = ?`,
+		site.Name, site.Description, site.MaxStreams, site.UpdatedAt, site.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update site: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("site not found: %s", site.ID)
+	}
+
+	r.logger.Info("Site updated",
+		zap.String("id", site.ID),
+		zap.Int("max_streams", site.MaxStreams),
+	)
+
+	return nil
+}
+
+// Delete는 사이트를 삭제하고 소속 스트림의 사이트 지정을 해제합니다
+func (r *SiteRepository) Delete(id string) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM sites WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete site: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("site not found: %s", id)
+	}
+
+	if _, err := tx.Exec(`UPDATE streams SET site_id = '' WHERE site_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to unassign site streams: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit site deletion: %w", err)
+	}
+
+	r.logger.Info("Site deleted", zap.String("id", id))
+
+	return nil
+}
+
+// Exists는 사이트가 존재하는지 확인합니다
+func (r *SiteRepository) Exists(id string) (bool, error) {
+	var count int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM sites WHERE id = ?`, id).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check site existence: %w", err)
+	}
+	return count > 0, nil
+}
+
+// AssignStreams는 스트림들을 사이트에 지정합니다 (다른 사이트에 있으면 옮김)
+// 없는 스트림이 하나라도 있으면 전체를 롤백합니다
+func (r *SiteRepository) AssignStreams(siteID string, streamIDs []string) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, streamID := range streamIDs {
+		result, err := tx.Exec(`UPDATE streams SET site_id = ?, updated_at = ? WHERE id = ?`, siteID, now, streamID)
+		if err != nil {
+			return fmt.Errorf("failed to assign stream %s: %w", streamID, err)
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		} else if n == 0 {
+			return fmt.Errorf("stream not found: %s", streamID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit site streams: %w", err)
+	}
+
+	r.logger.Info("Streams assigned to site",
+		zap.String("site_id", siteID),
+		zap.Int("count", len(streamIDs)),
+	)
+
+	return nil
+}
+
+// UnassignStream은 스트림의 사이트 지정을 해제합니다 (해당 사이트 소속이 아니면 오류)
+func (r *SiteRepository) UnassignStream(siteID, streamID string) error {
+	result, err := r.db.Conn().Exec(
+		`UPDATE streams SET site_id = '', updated_at = ? WHERE id = ? AND site_id = ?`, time.Now(), streamID, siteID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to unassign stream: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stream %s is not in site %s", streamID, siteID)
+	}
+
+	return nil
+}
+
+// StreamSites는 사이트에 지정된 스트림별 사이트 ID를 반환합니다
+func (r *SiteRepository) StreamSites() (map[string]string, error) {
+	rows, err := r.db.Conn().Query(`SELECT id, site_id FROM streams WHERE site_id != ''`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stream sites: %w", err)
+	}
+	defer rows.Close()
+
+	sites := make(map[string]string)
+	for rows.Next() {
+		var streamID, siteID string
+		if err := rows.Scan(&streamID, &siteID); err != nil {
+			return nil, fmt.Errorf("failed to scan stream site: %w", err)
+		}
+		sites[streamID] = siteID
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stream sites: %w", err)
+	}
+
+	return sites, nil
+}
@@ -0,0 +1,327 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StreamGroup은 같은 카메라의 여러 화질 스트림(메인/서브)을 묶은 그룹입니다
+type StreamGroup struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Renditions []StreamRendition `json:"renditions"` // 기본 화질이 첫 번째
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// StreamRendition은 그룹에 속한 화질 하나입니다
+type StreamRendition struct {
+	Name      string `json:"name"`             // 그룹 내 이름 (예: main, sub)
+	StreamID  string `json:"stream_id"`        // 실제 스트림 ID
+	Bandwidth int64  `json:"bandwidth"`        // 최대 비트레이트 (bps, HLS BANDWIDTH)
+	Width     int    `json:"width,omitempty"`  // 해상도 (HLS RESOLUTION, 0이면 생략)
+	Height    int    `json:"height,omitempty"` // 해상도
+}
+
+// Rendition은 이름으로 화질을 찾습니다
+func (g *StreamGroup) Rendition(name string) (StreamRendition, bool) {
+	for _, rendition := range g.Renditions {
+		if rendition.Name == name {
+			return rendition, true
+		}
+	}
+	return StreamRendition{}, false
+}
+
+// RenditionForBandwidth는 대역폭 추정치 안에서 가장 높은 화질을 반환합니다
+// 추정치에 맞는 화질이 없으면 가장 낮은 화질을 반환합니다
+func (g *StreamGroup) RenditionForBandwidth(bandwidth int64) (StreamRendition, bool) {
+	var best, lowest *StreamRendition
+	for i := range g.Renditions {
+		rendition := &g.Renditions[i]
+		if lowest == nil || rendition.Bandwidth < lowest.Bandwidth {
+			lowest = rendition
+		}
+		if rendition.Bandwidth <= bandwidth && (best == nil || rendition.Bandwidth > best.Bandwidth) {
+			best = rendition
+		}
+	}
+
+	if best != nil {
+		return *best, true
+	}
+	if lowest != nil {
+		return *lowest, true
+	}
+	return StreamRendition{}, false
+}
+
+// StreamGroupRepository는 스트림 그룹 데이터 액세스 레이어입니다
+type StreamGroupRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewStreamGroupRepository는 새로운 StreamGroupRepository를 생성합니다
+func NewStreamGroupRepository(db *DB, logger *zap.Logger) *StreamGroupRepository {
+	return &StreamGroupRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create는 새로운 그룹과 화질 목록을 생성합니다
+func (r *StreamGroupRepository) Create(group *StreamGroup) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	group.CreatedAt = now
+	group.UpdatedAt = now
+
+	if _, err := tx.Exec(
+		`INSERT INTO stream_groups (id, name, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+		group.ID, group.Name, group.CreatedAt, group.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create stream group: %w", err)
+	}
+
+	if err := insertRenditions(tx, group); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stream group: %w", err)
+	}
+
+	r.logger.Info("Stream group created",
+		zap.String("id", group.ID),
+		zap.Int("renditions", len(group.Renditions)),
+	)
+
+	return nil
+}
+
+// Get은 ID로 그룹을 조회합니다
+func (r *StreamGroupRepository) Get(id string) (*StreamGroup, error) {
+	group := &StreamGroup{}
+	err := r.db.Conn().QueryRow(
+		`SELECT id, name, created_at, updated_at FROM stream_groups WHERE id = ?`, id,
+	).Scan(&group.ID, &group.Name, &group.CreatedAt, &group.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("stream group not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream group: %w", err)
+	}
+
+	renditions, err := r.renditions(`WHERE group_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	group.Renditions = renditions[id]
+
+	return group, nil
+}
+
+// List는 모든 그룹을 ID순으로 조회합니다
+func (r *StreamGroupRepository) List() ([]*StreamGroup, error) {
+	rows, err := r.db.Conn().Query(`SELECT id, name, created_at, updated_at FROM stream_groups ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stream groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*StreamGroup
+	for rows.Next() {
+		group := &StreamGroup{}
+		if err := rows.Scan(&group.ID, &group.Name, &group.CreatedAt, &group.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stream group: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stream groups: %w", err)
+	}
+
+	renditions, err := r.renditions("")
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		group.Renditions = renditions[group.ID]
+	}
+
+	return groups, nil
+}
+
+// Update는 그룹 이름과 화질 목록을 교체합니다
+func (r *StreamGroupRepository) Update(group *StreamGroup) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	group.UpdatedAt = time.Now()
+
+	result, err := tx.Exec(
+		`UPDATE stream_groups SET name = ?, updated_at = ? WHERE id = ?`,
+		group.Name, group.UpdatedAt, group.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update stream group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stream group not found: %s", group.ID)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM stream_renditions WHERE group_id = ?`, group.ID); err != nil {
+		return fmt.Errorf("failed to delete stream renditions: %w", err)
+	}
+	if err := insertRenditions(tx, group); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stream group: %w", err)
+	}
+
+	r.logger.Info("Stream group updated",
+		zap.String("id", group.ID),
+		zap.Int("renditions", len(group.Renditions)),
+	)
+
+	return nil
+}
+
+// Delete는 그룹과 화질 목록을 삭제합니다
+func (r *StreamGroupRepository) Delete(id string) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM stream_groups WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete stream group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stream group not found: %s", id)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM stream_renditions WHERE group_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete stream renditions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stream group deletion: %w", err)
+	}
+
+	r.logger.Info("Stream group deleted", zap.String("id", id))
+
+	return nil
+}
+
+// Exists는 그룹이 존재하는지 확인합니다
+func (r *StreamGroupRepository) Exists(id string) (bool, error) {
+	var count int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM stream_groups WHERE id = ?`, id).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check stream group existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// RemoveStream은 삭제된 스트림을 모든 그룹의 화질 목록에서 제거합니다
+func (r *StreamGroupRepository) RemoveStream(streamID string) error {
+	result, err := r.db.Conn().Exec(`DELETE FROM stream_renditions WHERE stream_id = ?`, streamID)
+	if err != nil {
+		return fmt.Errorf("failed to remove stream from groups: %w", err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected > 0 {
+		r.logger.Info("Stream removed from groups",
+			zap.String("stream_id", streamID),
+			zap.Int64("renditions", rowsAffected),
+		)
+	}
+
+	return nil
+}
+
+// renditions는 화질 목록을 그룹 ID별로 조회합니다 (position 순)
+func (r *StreamGroupRepository) renditions(where string, args ...interface{}) (map[string][]StreamRendition, error) {
+	rows, err := r.db.Conn().Query(`
+		SELECT group_id, name, stream_id, bandwidth, width, height
+		FROM stream_renditions
+		`+where+`
+		ORDER BY group_id, position
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stream renditions: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]StreamRendition)
+	for rows.Next() {
+		var (
+			groupID   string
+			rendition StreamRendition
+		)
+		if err := rows.Scan(
+			&groupID,
+			&rendition.Name,
+			&rendition.StreamID,
+			&rendition.Bandwidth,
+			&rendition.Width,
+			&rendition.Height,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan stream rendition: %w", err)
+		}
+		result[groupID] = append(result[groupID], rendition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stream renditions: %w", err)
+	}
+
+	return result, nil
+}
+
+// insertRenditions는 그룹의 화질 목록을 입력 순서대로 저장합니다
+func insertRenditions(tx *sql.Tx, group *StreamGroup) error {
+	for i, rendition := range group.Renditions {
+		if _, err := tx.Exec(`
+			INSERT INTO stream_renditions (group_id, name, stream_id, bandwidth, width, height, position)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`,
+			group.ID,
+			rendition.Name,
+			rendition.StreamID,
+			rendition.Bandwidth,
+			rendition.Width,
+			rendition.Height,
+			i,
+		); err != nil {
+			return fmt.Errorf("failed to create stream rendition %s: %w", rendition.Name, err)
+		}
+	}
+	return nil
+}
@@ -3,88 +3,385 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/yourusername/cctv3/internal/secret"
 	"go.uber.org/zap"
 )
 
+// StreamOriginConfig는 config.yaml paths에서 생성된 스트림의 출처입니다 (API로 생성된 스트림은 빈 값)
+const StreamOriginConfig = "config"
+
 // Stream은 스트림 정보를 나타냅니다
+// Source는 인증 정보를 포함한 전체 URL이며, DB에는 인증 정보가 분리/암호화되어 저장됩니다
 type Stream struct {
-	ID             string    `json:"id"`
-	Name           string    `json:"name"`
-	Source         string    `json:"source"`
-	SourceOnDemand bool      `json:"source_on_demand"`
-	RTSPTransport  string    `json:"rtsp_transport"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Source         string `json:"source"`
+	SourceOnDemand bool   `json:"source_on_demand"`
+	RTSPTransport  string `json:"rtsp_transport"`
+	// ONVIF로 가져온 스트림의 장치 서비스 URL과 프로필 토큰 (PTZ/이벤트 등에 사용)
+	ONVIFAddress string `json:"onvif_address,omitempty"`
+	ONVIFProfile string `json:"onvif_profile,omitempty"`
+	// 소속 사이트 (빈 값=없음)와 태그 (수정 시 nil이면 기존 태그 유지)
+	SiteID string   `json:"site_id,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	// 예비 소스 (우선순위 순서, 수정 시 nil이면 기존 값 유지)와 주 소스 복귀 대기 시간 (초, 0이면 자동 복귀 안 함)
+	BackupSources []string `json:"backup_sources,omitempty"`
+	FailbackAfter int      `json:"failback_after,omitempty"`
+	// 출처 (StreamOriginConfig 또는 빈 값, Update로는 바뀌지 않음)
+	Origin    string    `json:"origin,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// 저장된 인증 정보를 복호화하지 못함 (Source 또는 해당 예비 소스는 비어 있음, 조회 전용)
+	CredentialsError bool `json:"credentials_error,omitempty"`
 }
 
+// streamColumns는 scanStream이 읽는 컬럼 순서입니다
+const streamColumns = "id, name, source, source_username, source_password, source_on_demand, rtsp_transport, onvif_address, onvif_profile, site_id, failback_after, origin, created_at, updated_at"
+
 // StreamRepository는 스트림 데이터 액세스 레이어입니다
 type StreamRepository struct {
-	db     *DB
-	logger *zap.Logger
+	db      *DB
+	keyring *secret.Keyring // 카메라 인증 정보 암호화
+	logger  *zap.Logger
 }
 
 // NewStreamRepository는 새로운 StreamRepository를 생성합니다
-func NewStreamRepository(db *DB, logger *zap.Logger) *StreamRepository {
+func NewStreamRepository(db *DB, keyring *secret.Keyring, logger *zap.Logger) *StreamRepository {
 	return &StreamRepository{
-		db:     db,
-		logger: logger,
+		db:      db,
+		keyring: keyring,
+		logger:  logger,
+	}
+}
+
+// sealSource는 source URL에서 인증 정보를 분리하고 암호화합니다
+func (r *StreamRepository) sealSource(source string) (clean, username, password string, err error) {
+	clean, user, pass, err := secret.SplitURLCredentials(source)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if username, err = r.keyring.Encrypt(user); err != nil {
+		return "", "", "", fmt.Errorf("failed to encrypt username: %w", err)
+	}
+	if password, err = r.keyring.Encrypt(pass); err != nil {
+		return "", "", "", fmt.Errorf("failed to encrypt password: %w", err)
+	}
+
+	return clean, username, password, nil
+}
+
+// openSource는 암호화된 인증 정보를 복호화해 전체 source URL을 만듭니다
+func (r *StreamRepository) openSource(clean, username, password string) (string, error) {
+	user, err := r.keyring.Decrypt(username)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt username: %w", err)
+	}
+	pass, err := r.keyring.Decrypt(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password: %w", err)
 	}
+
+	return secret.JoinURLCredentials(clean, user, pass)
+}
+
+// scanStream은 조회 결과 한 행을 Stream으로 변환합니다
+func (r *StreamRepository) scanStream(row interface{ Scan(...interface{}) error }) (*Stream, error) {
+	stream := &Stream{}
+	var username, password string
+
+	if err := row.Scan(
+		&stream.ID,
+		&stream.Name,
+		&stream.Source,
+		&username,
+		&password,
+		&stream.SourceOnDemand,
+		&stream.RTSPTransport,
+		&stream.ONVIFAddress,
+		&stream.ONVIFProfile,
+		&stream.SiteID,
+		&stream.FailbackAfter,
+		&stream.Origin,
+		&stream.CreatedAt,
+		&stream.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	// 복호화할 수 없는 행(키 분실, 손상) 하나 때문에 목록 전체가 실패하지 않도록
+	// 소스를 비우고 CredentialsError로 표시합니다
+	source, err := r.openSource(stream.Source, username, password)
+	if err != nil {
+		r.logger.Warn("Failed to decrypt stream credentials",
+			zap.String("id", stream.ID),
+			zap.Error(err),
+		)
+		source = ""
+		stream.CredentialsError = true
+	}
+	stream.Source = source
+
+	return stream, nil
 }
 
 // Create는 새로운 스트림을 생성합니다
 func (r *StreamRepository) Create(stream *Stream) error {
-	query := `
-		INSERT INTO streams (id, name, source, source_on_demand, rtsp_transport, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
 	now := time.Now()
+	if err := r.insertStream(tx, stream, now); err != nil {
+		return fmt.Errorf("failed to create stream: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to create stream: %w", err)
+	}
+
+	r.logger.Info("Stream created",
+		zap.String("id", stream.ID),
+		zap.String("name", stream.Name),
+	)
+
+	return nil
+}
+
+// insertStream은 트랜잭션 안에서 스트림과 태그를 추가합니다
+func (r *StreamRepository) insertStream(tx *sql.Tx, stream *Stream, now time.Time) error {
+	source, username, password, err := r.sealSource(stream.Source)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO streams (id, name, source, source_username, source_password, source_on_demand, rtsp_transport, onvif_address, onvif_profile, site_id, failback_after, origin, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		stream.ID,
+		stream.Name,
+		source,
+		username,
+		password,
+		stream.SourceOnDemand,
+		stream.RTSPTransport,
+		stream.ONVIFAddress,
+		stream.ONVIFProfile,
+		stream.SiteID,
+		stream.FailbackAfter,
+		stream.Origin,
+		now,
+		now,
+	); err != nil {
+		return err
+	}
+
+	if err := setStreamTags(tx, stream.ID, stream.Tags); err != nil {
+		return err
+	}
+	if err := r.setBackupSources(tx, stream.ID, stream.BackupSources); err != nil {
+		return err
+	}
+
 	stream.CreatedAt = now
 	stream.UpdatedAt = now
+	return nil
+}
 
-	_, err := r.db.Conn().Exec(
-		query,
-		stream.ID,
+// updateStream은 트랜잭션 안에서 스트림을 수정합니다 (Tags, BackupSources가 nil이면 기존 값 유지)
+func (r *StreamRepository) updateStream(tx *sql.Tx, stream *Stream, now time.Time) error {
+	source, username, password, err := r.sealSource(stream.Source)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE streams
+		SET name = ?, source = ?, source_username = ?, source_password = ?, source_on_demand = ?, rtsp_transport = ?, onvif_address = ?, onvif_profile = ?, site_id = ?, failback_after = ?, updated_at = ?
+		WHERE id = ?
+	`,
 		stream.Name,
-		stream.Source,
+		source,
+		username,
+		password,
 		stream.SourceOnDemand,
 		stream.RTSPTransport,
-		stream.CreatedAt,
-		stream.UpdatedAt,
+		stream.ONVIFAddress,
+		stream.ONVIFProfile,
+		stream.SiteID,
+		stream.FailbackAfter,
+		now,
+		stream.ID,
 	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stream not found: %s", stream.ID)
+	}
+
+	if stream.Tags != nil {
+		if err := setStreamTags(tx, stream.ID, stream.Tags); err != nil {
+			return err
+		}
+	}
+	if stream.BackupSources != nil {
+		if err := r.setBackupSources(tx, stream.ID, stream.BackupSources); err != nil {
+			return err
+		}
+	}
+
+	stream.UpdatedAt = now
+	return nil
+}
+
+// setStreamTags는 스트림의 태그를 교체합니다 (없는 태그는 tags 테이블에 추가)
+func setStreamTags(tx *sql.Tx, streamID string, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM stream_tags WHERE stream_id = ?`, streamID); err != nil {
+		return fmt.Errorf("failed to clear stream tags: %w", err)
+	}
 
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO tags (name, created_at) VALUES (?, ?)`, tag, time.Now()); err != nil {
+			return fmt.Errorf("failed to create tag %s: %w", tag, err)
+		}
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO stream_tags (stream_id, tag) VALUES (?, ?)`, streamID, tag); err != nil {
+			return fmt.Errorf("failed to add stream tag %s: %w", tag, err)
+		}
+	}
+
+	return nil
+}
+
+// setBackupSources는 스트림의 예비 소스를 교체합니다 (인증 정보는 주 소스와 같이 분리/암호화)
+func (r *StreamRepository) setBackupSources(tx *sql.Tx, streamID string, sources []string) error {
+	if _, err := tx.Exec(`DELETE FROM stream_sources WHERE stream_id = ?`, streamID); err != nil {
+		return fmt.Errorf("failed to clear backup sources: %w", err)
+	}
+
+	for i, raw := range sources {
+		source, username, password, err := r.sealSource(raw)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO stream_sources (stream_id, position, source, source_username, source_password) VALUES (?, ?, ?, ?, ?)`,
+			streamID, i+1, source, username, password,
+		); err != nil {
+			return fmt.Errorf("failed to add backup source: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// attachRelations는 조회한 스트림에 태그와 예비 소스를 채웁니다
+func (r *StreamRepository) attachRelations(streams []*Stream) error {
+	if err := r.attachTags(streams); err != nil {
+		return err
+	}
+	return r.attachBackupSources(streams)
+}
+
+// attachBackupSources는 조회한 스트림에 예비 소스를 우선순위 순서로 채웁니다
+func (r *StreamRepository) attachBackupSources(streams []*Stream) error {
+	if len(streams) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Stream, len(streams))
+	args := make([]interface{}, 0, len(streams))
+	for _, stream := range streams {
+		byID[stream.ID] = stream
+		args = append(args, stream.ID)
+	}
+
+	rows, err := r.db.Conn().Query(
+		`SELECT stream_id, source, source_username, source_password FROM stream_sources WHERE stream_id IN (`+placeholders(len(args))+`) ORDER BY stream_id, position`, args...,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to create stream: %w", err)
+		return fmt.Errorf("failed to query backup sources: %w", err)
 	}
+	defer rows.Close()
 
-	r.logger.Info("Stream created",
-		zap.String("id", stream.ID),
-		zap.String("name", stream.Name),
+	for rows.Next() {
+		var streamID, clean, username, password string
+		if err := rows.Scan(&streamID, &clean, &username, &password); err != nil {
+			return fmt.Errorf("failed to scan backup source: %w", err)
+		}
+		stream, ok := byID[streamID]
+		if !ok {
+			continue
+		}
+		source, err := r.openSource(clean, username, password)
+		if err != nil {
+			r.logger.Warn("Failed to decrypt backup source credentials",
+				zap.String("id", streamID),
+				zap.Error(err),
+			)
+			stream.CredentialsError = true
+			continue
+		}
+		stream.BackupSources = append(stream.BackupSources, source)
+	}
+
+	return rows.Err()
+}
+
+// attachTags는 조회한 스트림에 태그를 채웁니다 (태그 이름순)
+func (r *StreamRepository) attachTags(streams []*Stream) error {
+	if len(streams) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Stream, len(streams))
+	args := make([]interface{}, 0, len(streams))
+	for _, stream := range streams {
+		byID[stream.ID] = stream
+		args = append(args, stream.ID)
+	}
+
+	rows, err := r.db.Conn().Query(
+		`SELECT stream_id, tag FROM stream_tags WHERE stream_id IN (`+placeholders(len(args))+`) ORDER BY tag`, args...,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to query stream tags: %w", err)
+	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		var streamID, tag string
+		if err := rows.Scan(&streamID, &tag); err != nil {
+			return fmt.Errorf("failed to scan stream tag: %w", err)
+		}
+		if stream, ok := byID[streamID]; ok {
+			stream.Tags = append(stream.Tags, tag)
+		}
+	}
+
+	return rows.Err()
 }
 
 // Get은 ID로 스트림을 조회합니다
 func (r *StreamRepository) Get(id string) (*Stream, error) {
 	query := `
-		SELECT id, name, source, source_on_demand, rtsp_transport, created_at, updated_at
+		SELECT ` + streamColumns + `
 		FROM streams
 		WHERE id = ?
 	`
 
-	stream := &Stream{}
-	err := r.db.Conn().QueryRow(query, id).Scan(
-		&stream.ID,
-		&stream.Name,
-		&stream.Source,
-		&stream.SourceOnDemand,
-		&stream.RTSPTransport,
-		&stream.CreatedAt,
-		&stream.UpdatedAt,
-	)
+	stream, err := r.scanStream(r.db.Conn().QueryRow(query, id))
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("stream not found: %s", id)
@@ -93,13 +390,17 @@ func (r *StreamRepository) Get(id string) (*Stream, error) {
 		return nil, fmt.Errorf("failed to get stream: %w", err)
 	}
 
+	if err := r.attachRelations([]*Stream{stream}); err != nil {
+		return nil, err
+	}
+
 	return stream, nil
 }
 
 // List는 모든 스트림을 조회합니다
 func (r *StreamRepository) List() ([]*Stream, error) {
 	query := `
-		SELECT id, name, source, source_on_demand, rtsp_transport, created_at, updated_at
+		SELECT ` + streamColumns + `
 		FROM streams
 		ORDER BY created_at DESC
 	`
@@ -112,16 +413,7 @@ func (r *StreamRepository) List() ([]*Stream, error) {
 
 	var streams []*Stream
 	for rows.Next() {
-		stream := &Stream{}
-		err := rows.Scan(
-			&stream.ID,
-			&stream.Name,
-			&stream.Source,
-			&stream.SourceOnDemand,
-			&stream.RTSPTransport,
-			&stream.CreatedAt,
-			&stream.UpdatedAt,
-		)
+		stream, err := r.scanStream(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan stream: %w", err)
 		}
@@ -132,40 +424,164 @@ func (r *StreamRepository) List() ([]*Stream, error) {
 		return nil, fmt.Errorf("error iterating streams: %w", err)
 	}
 
+	if err := r.attachRelations(streams); err != nil {
+		return nil, err
+	}
+
 	return streams, nil
 }
 
-// Update는 스트림을 업데이트합니다
-func (r *StreamRepository) Update(stream *Stream) error {
-	query := `
-		UPDATE streams
-		SET name = ?, source = ?, source_on_demand = ?, rtsp_transport = ?, updated_at = ?
-		WHERE id = ?
-	`
+// 스트림 목록 정렬 기준 (DB 컬럼)
+const (
+	StreamSortName      = "name"
+	StreamSortCreatedAt = "created_at"
+)
 
-	stream.UpdatedAt = time.Now()
+// StreamFilter는 스트림 목록 조회 조건입니다 (빈 값은 조건 없음)
+// 연결 상태, 코덱처럼 DB에 없는 런타임 조건은 호출하는 쪽에서 해당하는 ID 목록으로 바꿔 전달합니다
+type StreamFilter struct {
+	NamePrefix string   // 이름 또는 ID 앞부분 (대소문자 구분 없음)
+	OnDemand   *bool    // source_on_demand
+	IDs        []string // nil이 아니면 이 ID만 (비어있으면 결과 없음)
+	ExcludeIDs []string // 제외할 ID
+	SiteID     string   // 소속 사이트
+	Tags       []string // 모든 태그를 가진 스트림만
+
+	Sort   string // StreamSortName, StreamSortCreatedAt (기본 created_at)
+	Desc   bool
+	Limit  int // 0 이하면 제한 없음
+	Offset int
+}
 
-	result, err := r.db.Conn().Exec(
-		query,
-		stream.Name,
-		stream.Source,
-		stream.SourceOnDemand,
-		stream.RTSPTransport,
-		stream.UpdatedAt,
-		stream.ID,
-	)
+// where는 조회 조건을 SQL WHERE 절과 인자로 변환합니다
+func (f StreamFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
 
+	if f.NamePrefix != "" {
+		pattern := escapeLike(f.NamePrefix) + "%"
+		conds = append(conds, `(name LIKE ? ESCAPE '\' OR id LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	if f.OnDemand != nil {
+		conds = append(conds, "source_on_demand = ?")
+		args = append(args, *f.OnDemand)
+	}
+	if f.IDs != nil {
+		if len(f.IDs) == 0 {
+			conds = append(conds, "0")
+		} else {
+			conds = append(conds, "id IN ("+placeholders(len(f.IDs))+")")
+			for _, id := range f.IDs {
+				args = append(args, id)
+			}
+		}
+	}
+	if len(f.ExcludeIDs) > 0 {
+		conds = append(conds, "id NOT IN ("+placeholders(len(f.ExcludeIDs))+")")
+		for _, id := range f.ExcludeIDs {
+			args = append(args, id)
+		}
+	}
+	if f.SiteID != "" {
+		conds = append(conds, "site_id = ?")
+		args = append(args, f.SiteID)
+	}
+	if len(f.Tags) > 0 {
+		conds = append(conds, "id IN (SELECT stream_id FROM stream_tags WHERE tag IN ("+placeholders(len(f.Tags))+") GROUP BY stream_id HAVING COUNT(*) = ?)")
+		for _, tag := range f.Tags {
+			args = append(args, tag)
+		}
+		args = append(args, len(f.Tags))
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// orderBy는 정렬 조건을 SQL ORDER BY 절로 변환합니다 (같은 값은 ID 순)
+func (f StreamFilter) orderBy() string {
+	column := "created_at"
+	if f.Sort == StreamSortName {
+		column = "name COLLATE NOCASE"
+	}
+
+	direction := " ASC"
+	if f.Desc {
+		direction = " DESC"
+	}
+	return " ORDER BY " + column + direction + ", id" + direction
+}
+
+// escapeLike는 LIKE 패턴의 특수 문자(%, _, \)를 이스케이프합니다
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// placeholders는 IN 절용 "?, ?, ..."를 만듭니다
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// Query는 조건에 맞는 스트림을 정렬/페이지 단위로 조회하고 전체 개수를 반환합니다
+func (r *StreamRepository) Query(filter StreamFilter) ([]*Stream, int, error) {
+	where, args := filter.where()
+
+	var total int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM streams`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count streams: %w", err)
+	}
+
+	query := `
+		SELECT ` + streamColumns + `
+		FROM streams` + where + filter.orderBy()
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Conn().Query(query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update stream: %w", err)
+		return nil, 0, fmt.Errorf("failed to query streams: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
+	streams := make([]*Stream, 0)
+	for rows.Next() {
+		stream, err := r.scanStream(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan stream: %w", err)
+		}
+		streams = append(streams, stream)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating streams: %w", err)
+	}
+
+	if err := r.attachRelations(streams); err != nil {
+		return nil, 0, err
+	}
+
+	return streams, total, nil
+}
+
+// Update는 스트림을 업데이트합니다
+func (r *StreamRepository) Update(stream *Stream) error {
+	tx, err := r.db.Conn().Begin()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("stream not found: %s", stream.ID)
+	if err := r.updateStream(tx, stream, time.Now()); err != nil {
+		return fmt.Errorf("failed to update stream: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to update stream: %w", err)
 	}
 
 	r.logger.Info("Stream updated",
@@ -176,11 +592,50 @@ func (r *StreamRepository) Update(stream *Stream) error {
 	return nil
 }
 
+// Import는 여러 스트림을 한 트랜잭션으로 추가/갱신합니다
+// 하나라도 실패하면 전체를 롤백하므로 일부만 반영되지 않습니다
+func (r *StreamRepository) Import(creates, updates []*Stream) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	for _, stream := range creates {
+		if err := r.insertStream(tx, stream, now); err != nil {
+			return fmt.Errorf("failed to import stream %s: %w", stream.ID, err)
+		}
+	}
+
+	for _, stream := range updates {
+		if err := r.updateStream(tx, stream, now); err != nil {
+			return fmt.Errorf("failed to import stream %s: %w", stream.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Info("Streams imported",
+		zap.Int("created", len(creates)),
+		zap.Int("updated", len(updates)),
+	)
+
+	return nil
+}
+
 // Delete는 스트림을 삭제합니다
 func (r *StreamRepository) Delete(id string) error {
-	query := `DELETE FROM streams WHERE id = ?`
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.Conn().Exec(query, id)
+	result, err := tx.Exec(`DELETE FROM streams WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete stream: %w", err)
 	}
@@ -194,6 +649,17 @@ func (r *StreamRepository) Delete(id string) error {
 		return fmt.Errorf("stream not found: %s", id)
 	}
 
+	if _, err := tx.Exec(`DELETE FROM stream_tags WHERE stream_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete stream tags: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM stream_sources WHERE stream_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete backup sources: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete stream: %w", err)
+	}
+
 	r.logger.Info("Stream deleted",
 		zap.String("id", id),
 	)
@@ -201,6 +667,24 @@ func (r *StreamRepository) Delete(id string) error {
 	return nil
 }
 
+// SetOrigin은 스트림의 출처를 변경합니다
+func (r *StreamRepository) SetOrigin(id, origin string) error {
+	result, err := r.db.Conn().Exec(`UPDATE streams SET origin = ? WHERE id = ?`, origin, id)
+	if err != nil {
+		return fmt.Errorf("failed to set stream origin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stream not found: %s", id)
+	}
+
+	return nil
+}
+
 // Exists는 스트림이 존재하는지 확인합니다
 func (r *StreamRepository) Exists(id string) (bool, error) {
 	query := `SELECT COUNT(*) FROM streams WHERE id = ?`
@@ -226,3 +710,110 @@ func (r *StreamRepository) Count() (int, error) {
 
 	return count, nil
 }
+
+// EncryptCredentials는 저장된 인증 정보(주 소스와 예비 소스)를 현재 키로 다시 암호화합니다
+// - 이전 버전에서 source URL에 평문으로 저장된 인증 정보를 분리/암호화
+// - 이전 키로 암호화된 값을 현재 키로 재암호화 (키 교체)
+// 어떤 키로도 복호화할 수 없는 행은 경고만 남기고 그대로 둡니다 (조회 시 CredentialsError)
+func (r *StreamRepository) EncryptCredentials() (int, error) {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	streams, err := r.resealCredentials(tx, "streams", "id")
+	if err != nil {
+		return 0, err
+	}
+	backups, err := r.resealCredentials(tx, "stream_sources", "stream_id")
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	count := streams + backups
+	if count > 0 {
+		r.logger.Info("Stream credentials re-encrypted", zap.Int("count", count))
+	}
+
+	return count, nil
+}
+
+// resealCredentials는 table의 source 인증 정보 중 평문이거나 이전 키로 암호화된 행을 다시 암호화합니다
+// streamColumn은 오류 메시지에 표시할 스트림 ID 컬럼입니다
+func (r *StreamRepository) resealCredentials(tx *sql.Tx, table, streamColumn string) (int, error) {
+	rows, err := tx.Query(`SELECT rowid, ` + streamColumn + `, source, source_username, source_password FROM ` + table)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+
+	type sealedRow struct {
+		rowid                          int64
+		id, source, username, password string
+	}
+	var pending []sealedRow
+
+	for rows.Next() {
+		var row sealedRow
+		if err := rows.Scan(&row.rowid, &row.id, &row.source, &row.username, &row.password); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan stream: %w", err)
+		}
+
+		clean, user, pass, err := secret.SplitURLCredentials(row.source)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("stream %s: %w", row.id, err)
+		}
+
+		legacy := user != "" || pass != ""
+		if !legacy && !r.keyring.NeedsRotation(row.username) && !r.keyring.NeedsRotation(row.password) {
+			continue
+		}
+
+		// 평문 URL의 인증 정보보다 이미 분리된 컬럼을 우선
+		if row.username != "" || row.password != "" {
+			user, err = r.keyring.Decrypt(row.username)
+			if err == nil {
+				pass, err = r.keyring.Decrypt(row.password)
+			}
+			if err != nil {
+				r.logger.Warn("Skipping stream credentials that cannot be decrypted",
+					zap.String("id", row.id),
+					zap.Error(err),
+				)
+				continue
+			}
+		}
+
+		row.source = clean
+		if row.username, err = r.keyring.Encrypt(user); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if row.password, err = r.keyring.Encrypt(pass); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating %s: %w", table, err)
+	}
+
+	for _, row := range pending {
+		if _, err := tx.Exec(
+			`UPDATE `+table+` SET source = ?, source_username = ?, source_password = ? WHERE rowid = ?`,
+			row.source, row.username, row.password, row.rowid,
+		); err != nil {
+			return 0, fmt.Errorf("failed to update stream %s: %w", row.id, err)
+		}
+	}
+
+	return len(pending), nil
+}
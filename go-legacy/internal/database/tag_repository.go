@@ -0,0 +1,154 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Tag는 스트림에 붙이는 분류 이름입니다 (예: ptz, outdoor)
+// 스트림에 없는 태그를 지정하면 자동으로 생성됩니다
+type Tag struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	StreamCount int       `json:"stream_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TagRepository는 태그 데이터 액세스 레이어입니다
+type TagRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewTagRepository는 새로운 TagRepository를 생성합니다
+func NewTagRepository(db *DB, logger *zap.Logger) *TagRepository {
+	return &TagRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// tagQuery는 태그와 사용 중인 스트림 수를 조회합니다
+const tagQuery = `
+	SELECT t.name, t.description, t.created_at, COUNT(st.stream_id)
+	FROM tags t LEFT JOIN stream_tags st ON st.tag = t.name
+`
+
+// Create는 새로운 태그를 생성합니다
+func (r *TagRepository) Create(tag *Tag) error {
+	tag.CreatedAt = time.Now()
+
+	if _, err := r.db.Conn().Exec(
+		`INSERT INTO tags (name, description, created_at) VALUES (?, ?, ?)`,
+		tag.Name, tag.Description, tag.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	r.logger.Info("Tag created", zap.String("name", tag.Name))
+
+	return nil
+}
+
+// Get은 이름으로 태그를 조회합니다
+func (r *TagRepository) Get(name string) (*Tag, error) {
+	tag := &Tag{}
+	err := r.db.Conn().QueryRow(tagQuery+` WHERE t.name = ? GROUP BY t.name`, name).
+		Scan(&tag.Name, &tag.Description, &tag.CreatedAt, &tag.StreamCount)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tag not found: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// List는 모든 태그를 이름순으로 조회합니다
+func (r *TagRepository) List() ([]*Tag, error) {
+	rows, err := r.db.Conn().Query(tagQuery + ` GROUP BY t.name ORDER BY t.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]*Tag, 0)
+	for rows.Next() {
+		tag := &Tag{}
+		if err := rows.Scan(&tag.Name, &tag.Description, &tag.CreatedAt, &tag.StreamCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Update는 태그 설명을 수정합니다
+func (r *TagRepository) Update(tag *Tag) error {
+	result, err := r.db.Conn().Exec(`UPDATE tags SET description = ? WHERE name = ?`, tag.Description, tag.Name)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found: %s", tag.Name)
+	}
+
+	return nil
+}
+
+// Delete는 태그를 삭제하고 스트림에서 떼어냅니다
+func (r *TagRepository) Delete(name string) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM tags WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found: %s", name)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM stream_tags WHERE tag = ?`, name); err != nil {
+		return fmt.Errorf("failed to remove tag from streams: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tag deletion: %w", err)
+	}
+
+	r.logger.Info("Tag deleted", zap.String("name", name))
+
+	return nil
+}
+
+// Exists는 태그가 존재하는지 확인합니다
+func (r *TagRepository) Exists(name string) (bool, error) {
+	var count int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM tags WHERE name = ?`, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check tag existence: %w", err)
+	}
+	return count > 0, nil
+}
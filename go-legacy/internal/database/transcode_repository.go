@@ -0,0 +1,198 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TranscodeProfile은 서버 측 트랜스코딩 프로필입니다 (파생 스트림 CAM1~<name>)
+type TranscodeProfile struct {
+	Name       string    `json:"name"`
+	Codec      string    `json:"codec"`                // h264, h265
+	Resolution string    `json:"resolution,omitempty"` // "1280x720" 또는 "720p" (비어있으면 원본 해상도)
+	Bitrate    string    `json:"bitrate,omitempty"`    // "2000k", "2M" (비어있으면 인코더 기본값)
+	GOP        int       `json:"gop,omitempty"`        // 키프레임 간격 (프레임, 0이면 인코더 기본값)
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TranscodeProfileRepository는 트랜스코딩 프로필 데이터 액세스 레이어입니다
+type TranscodeProfileRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewTranscodeProfileRepository는 새로운 TranscodeProfileRepository를 생성합니다
+func NewTranscodeProfileRepository(db *DB, logger *zap.Logger) *TranscodeProfileRepository {
+	return &TranscodeProfileRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// scanProfile은 조회 결과 한 행을 TranscodeProfile로 변환합니다
+func scanProfile(row interface{ Scan(...interface{}) error }) (*TranscodeProfile, error) {
+	profile := &TranscodeProfile{}
+	if err := row.Scan(
+		&profile.Name,
+		&profile.Codec,
+		&profile.Resolution,
+		&profile.Bitrate,
+		&profile.GOP,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Create는 새로운 프로필을 생성합니다
+func (r *TranscodeProfileRepository) Create(profile *TranscodeProfile) error {
+	query := `
+		INSERT INTO transcode_profiles (name, codec, resolution, bitrate, gop, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+
+	if _, err := r.db.Conn().Exec(
+		query,
+		profile.Name,
+		profile.Codec,
+		profile.Resolution,
+		profile.Bitrate,
+		profile.GOP,
+		profile.CreatedAt,
+		profile.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create transcode profile: %w", err)
+	}
+
+	r.logger.Info("Transcode profile created",
+		zap.String("name", profile.Name),
+		zap.String("codec", profile.Codec),
+	)
+
+	return nil
+}
+
+// Get은 이름으로 프로필을 조회합니다
+func (r *TranscodeProfileRepository) Get(name string) (*TranscodeProfile, error) {
+	query := `
+		SELECT name, codec, resolution, bitrate, gop, created_at, updated_at
+		FROM transcode_profiles
+		WHERE name = ?
+	`
+
+	profile, err := scanProfile(r.db.Conn().QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("transcode profile not found: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcode profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// List는 모든 프로필을 이름순으로 조회합니다
+func (r *TranscodeProfileRepository) List() ([]*TranscodeProfile, error) {
+	query := `
+		SELECT name, codec, resolution, bitrate, gop, created_at, updated_at
+		FROM transcode_profiles
+		ORDER BY name
+	`
+
+	rows, err := r.db.Conn().Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transcode profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*TranscodeProfile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transcode profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transcode profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// Update는 프로필을 업데이트합니다
+func (r *TranscodeProfileRepository) Update(profile *TranscodeProfile) error {
+	query := `
+		UPDATE transcode_profiles
+		SET codec = ?, resolution = ?, bitrate = ?, gop = ?, updated_at = ?
+		WHERE name = ?
+	`
+
+	profile.UpdatedAt = time.Now()
+
+	result, err := r.db.Conn().Exec(
+		query,
+		profile.Codec,
+		profile.Resolution,
+		profile.Bitrate,
+		profile.GOP,
+		profile.UpdatedAt,
+		profile.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transcode profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("transcode profile not found: %s", profile.Name)
+	}
+
+	r.logger.Info("Transcode profile updated", zap.String("name", profile.Name))
+
+	return nil
+}
+
+// Delete는 프로필을 삭제합니다
+func (r *TranscodeProfileRepository) Delete(name string) error {
+	result, err := r.db.Conn().Exec(`DELETE FROM transcode_profiles WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete transcode profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("transcode profile not found: %s", name)
+	}
+
+	r.logger.Info("Transcode profile deleted", zap.String("name", name))
+
+	return nil
+}
+
+// Exists는 프로필이 존재하는지 확인합니다
+func (r *TranscodeProfileRepository) Exists(name string) (bool, error) {
+	var count int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM transcode_profiles WHERE name = ?`, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check transcode profile existence: %w", err)
+	}
+
+	return count > 0, nil
+}
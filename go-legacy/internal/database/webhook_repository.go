@@ -0,0 +1,446 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/secret"
+	"go.uber.org/zap"
+)
+
+// 웹훅 전송 상태
+const (
+	DeliveryPending   = "pending"   // 전송 대기 또는 재시도 대기
+	DeliveryDelivered = "delivered" // 2xx 응답
+	DeliveryFailed    = "failed"    // 최대 시도 횟수 초과 또는 웹훅 삭제/비활성화
+)
+
+// Webhook은 이벤트를 전달받을 외부 엔드포인트입니다
+// Secret은 HMAC 서명 키이며 DB에는 암호화되어 저장됩니다
+type Webhook struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"` // 전달할 이벤트 (비어있으면 전체)
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Accepts는 웹훅이 이벤트를 전달받는지 반환합니다
+func (w *Webhook) Accepts(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery는 웹훅 하나로의 이벤트 전송 기록입니다 (재시도 대기열 겸용)
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"` // 전송 본문
+	Status         string          `json:"status"`  // pending, delivered, failed
+	Attempts       int             `json:"attempts"`
+	NextAttempt    time.Time       `json:"next_attempt"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// DeliveryFilter는 전송 기록 조회 조건입니다 (빈 값은 조건 없음)
+type DeliveryFilter struct {
+	WebhookID int64
+	Event     string
+	Status    string
+	Limit     int // 0 이하면 제한 없음
+	Offset    int
+}
+
+// WebhookRepository는 웹훅과 전송 기록 데이터 액세스 레이어입니다
+type WebhookRepository struct {
+	db      *DB
+	keyring *secret.Keyring // 서명 키 암호화
+	logger  *zap.Logger
+}
+
+// NewWebhookRepository는 새로운 WebhookRepository를 생성합니다
+func NewWebhookRepository(db *DB, keyring *secret.Keyring, logger *zap.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:      db,
+		keyring: keyring,
+		logger:  logger,
+	}
+}
+
+// Create는 새로운 웹훅을 생성합니다
+func (r *WebhookRepository) Create(webhook *Webhook) error {
+	encrypted, err := r.keyring.Encrypt(webhook.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	now := time.Now()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	result, err := r.db.Conn().Exec(`
+		INSERT INTO webhooks (name, url, secret, events, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`,
+		webhook.Name,
+		webhook.URL,
+		encrypted,
+		strings.Join(webhook.Events, ","),
+		webhook.Enabled,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		webhook.ID = id
+	}
+
+	r.logger.Info("Webhook created",
+		zap.Int64("id", webhook.ID),
+		zap.String("url", webhook.URL),
+	)
+
+	return nil
+}
+
+// Get은 ID로 웹훅을 조회합니다 (서명 키는 복호화된 값)
+func (r *WebhookRepository) Get(id int64) (*Webhook, error) {
+	webhook, err := r.scanWebhook(r.db.Conn().QueryRow(`
+		SELECT id, name, url, secret, events, enabled, created_at, updated_at
+		FROM webhooks
+		WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook not found: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// List는 모든 웹훅을 ID순으로 조회합니다 (서명 키는 복호화된 값)
+func (r *WebhookRepository) List() ([]*Webhook, error) {
+	rows, err := r.db.Conn().Query(`
+		SELECT id, name, url, secret, events, enabled, created_at, updated_at
+		FROM webhooks
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []*Webhook
+	for rows.Next() {
+		webhook, err := r.scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// Update는 웹훅을 업데이트합니다
+func (r *WebhookRepository) Update(webhook *Webhook) error {
+	encrypted, err := r.keyring.Encrypt(webhook.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	webhook.UpdatedAt = time.Now()
+
+	result, err := r.db.Conn().Exec(`
+		UPDATE webhooks
+		SET name = ?, url = ?, secret = ?, events = ?, enabled = ?, updated_at = ?
+		WHERE id = ?
+	`,
+		webhook.Name,
+		webhook.URL,
+		encrypted,
+		strings.Join(webhook.Events, ","),
+		webhook.Enabled,
+		webhook.UpdatedAt,
+		webhook.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found: %d", webhook.ID)
+	}
+
+	r.logger.Info("Webhook updated", zap.Int64("id", webhook.ID))
+
+	return nil
+}
+
+// Delete는 웹훅과 전송 기록을 삭제합니다
+func (r *WebhookRepository) Delete(id int64) error {
+	tx, err := r.db.Conn().Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found: %d", id)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit webhook deletion: %w", err)
+	}
+
+	r.logger.Info("Webhook deleted", zap.Int64("id", id))
+
+	return nil
+}
+
+// EnqueueDelivery는 전송 대기열에 전송 기록을 추가합니다
+func (r *WebhookRepository) EnqueueDelivery(delivery *WebhookDelivery) error {
+	now := time.Now()
+	delivery.Status = DeliveryPending
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+	if delivery.NextAttempt.IsZero() {
+		delivery.NextAttempt = now
+	}
+
+	result, err := r.db.Conn().Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, attempts, next_attempt_ms, created_ms, updated_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		delivery.WebhookID,
+		delivery.Event,
+		string(delivery.Payload),
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttempt.UnixMilli(),
+		delivery.CreatedAt.UnixMilli(),
+		delivery.UpdatedAt.UnixMilli(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		delivery.ID = id
+	}
+
+	return nil
+}
+
+// DueDeliveries는 전송 시각이 된 대기 중인 전송을 오래된 순으로 조회합니다
+func (r *WebhookRepository) DueDeliveries(now time.Time, limit int) ([]*WebhookDelivery, error) {
+	return r.queryDeliveries(`
+		WHERE status = ? AND next_attempt_ms <= ?
+		ORDER BY next_attempt_ms, id
+		LIMIT ?
+	`, DeliveryPending, now.UnixMilli(), limit)
+}
+
+// UpdateDelivery는 전송 결과(상태, 시도 횟수, 다음 시도 시각, 마지막 응답)를 저장합니다
+func (r *WebhookRepository) UpdateDelivery(delivery *WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+
+	if _, err := r.db.Conn().Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_ms = ?, last_status_code = ?, last_error = ?, updated_ms = ?
+		WHERE id = ?
+	`,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttempt.UnixMilli(),
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.UpdatedAt.UnixMilli(),
+		delivery.ID,
+	); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// QueryDeliveries는 조건에 맞는 전송 기록을 최신순으로 조회합니다
+// 두 번째 반환값은 Limit/Offset 적용 전 전체 개수입니다
+func (r *WebhookRepository) QueryDeliveries(filter DeliveryFilter) ([]*WebhookDelivery, int, error) {
+	where, args := filter.where()
+
+	var total int
+	if err := r.db.Conn().QueryRow(`SELECT COUNT(*) FROM webhook_deliveries`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := where + ` ORDER BY created_ms DESC, id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	deliveries, err := r.queryDeliveries(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// DeleteDeliveriesBefore는 지정 시각 이전에 완료된 전송 기록을 삭제합니다 (보관 기간 정리용, 대기 중인 전송은 유지)
+func (r *WebhookRepository) DeleteDeliveriesBefore(t time.Time) (int64, error) {
+	result, err := r.db.Conn().Exec(
+		`DELETE FROM webhook_deliveries WHERE status != ? AND updated_ms < ?`,
+		DeliveryPending, t.UnixMilli(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanWebhook은 조회 결과 한 행을 Webhook으로 변환하고 서명 키를 복호화합니다
+func (r *WebhookRepository) scanWebhook(row interface{ Scan(...interface{}) error }) (*Webhook, error) {
+	webhook := &Webhook{}
+	var encrypted, events string
+	if err := row.Scan(
+		&webhook.ID,
+		&webhook.Name,
+		&webhook.URL,
+		&encrypted,
+		&events,
+		&webhook.Enabled,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	secret, err := r.keyring.Decrypt(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	webhook.Secret = secret
+
+	webhook.Events = []string{}
+	if events != "" {
+		webhook.Events = strings.Split(events, ",")
+	}
+
+	return webhook, nil
+}
+
+// queryDeliveries는 WHERE/ORDER 절에 맞는 전송 기록을 조회합니다
+func (r *WebhookRepository) queryDeliveries(clause string, args ...interface{}) ([]*WebhookDelivery, error) {
+	rows, err := r.db.Conn().Query(`
+		SELECT id, webhook_id, event, payload, status, attempts, next_attempt_ms,
+			last_status_code, last_error, created_ms, updated_ms
+		FROM webhook_deliveries`+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*WebhookDelivery, 0)
+	for rows.Next() {
+		delivery := &WebhookDelivery{}
+		var payload string
+		var nextAttemptMs, createdMs, updatedMs int64
+
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&nextAttemptMs,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&createdMs,
+			&updatedMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+
+		delivery.Payload = json.RawMessage(payload)
+		delivery.NextAttempt = time.UnixMilli(nextAttemptMs).UTC()
+		delivery.CreatedAt = time.UnixMilli(createdMs).UTC()
+		delivery.UpdatedAt = time.UnixMilli(updatedMs).UTC()
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// where는 조회 조건을 SQL WHERE 절로 변환합니다
+func (f DeliveryFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if f.WebhookID != 0 {
+		conds = append(conds, "webhook_id = ?")
+		args = append(args, f.WebhookID)
+	}
+	if f.Event != "" {
+		conds = append(conds, "event = ?")
+		args = append(args, f.Event)
+	}
+	if f.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, f.Status)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
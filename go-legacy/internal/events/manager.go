@@ -0,0 +1,199 @@
+// Package events는 카메라 이벤트(모션, 라인 크로싱 등)를 수집해 저장하고 전달합니다
+// 수집 경로: ONVIF PullPoint 구독, 카메라/외부 시스템의 HTTP 알람
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+// 같은 상태의 반복 알림을 무시하는 기본 간격
+const defaultDedupeInterval = 5 * time.Second
+
+// Config는 이벤트 관리자 설정
+type Config struct {
+	Retention      time.Duration // 0이면 무기한 보관
+	DedupeInterval time.Duration // 같은 스트림/타입/상태의 반복 알림 무시 간격
+	RecordTypes    []string      // 녹화를 트리거할 이벤트 타입 (비어있으면 전체)
+
+	// ONVIF PullPoint 구독 (ONVIFTargets가 nil이면 비활성화)
+	ONVIFTargets   func() ([]ONVIFTarget, error)
+	RequestTimeout time.Duration // SOAP 요청 타임아웃
+
+	// OnEvent는 저장된 이벤트마다 호출됩니다 (WebSocket 전송 등)
+	OnEvent func(event *database.Event)
+	// OnTrigger는 녹화 대상 이벤트 발생 시 호출되며 녹화 여부를 반환합니다 (nil이면 녹화 안 함)
+	OnTrigger func(streamID string) bool
+}
+
+// eventState는 스트림/타입별 마지막 저장 상태입니다
+type eventState struct {
+	active bool
+	time   time.Time
+}
+
+// Manager는 이벤트를 정규화해 저장하고 구독자에게 전달합니다
+type Manager struct {
+	config      Config
+	repo        *database.EventRepository
+	logger      *zap.Logger
+	recordTypes map[string]bool
+
+	stateMutex sync.Mutex
+	states     map[string]eventState // streamID/type -> 마지막 상태
+
+	// ONVIF 구독 (streamID -> watcher)
+	watcherMutex sync.Mutex
+	watchers     map[string]*watcher
+	closed       bool
+	wg           sync.WaitGroup
+}
+
+// NewManager는 새로운 이벤트 관리자를 생성합니다
+func NewManager(config Config, repo *database.EventRepository, logger *zap.Logger) *Manager {
+	if config.DedupeInterval <= 0 {
+		config.DedupeInterval = defaultDedupeInterval
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = 10 * time.Second
+	}
+
+	var recordTypes map[string]bool
+	if len(config.RecordTypes) > 0 {
+		recordTypes = make(map[string]bool, len(config.RecordTypes))
+		for _, t := range config.RecordTypes {
+			recordTypes[t] = true
+		}
+	}
+
+	return &Manager{
+		config:      config,
+		repo:        repo,
+		logger:      logger,
+		recordTypes: recordTypes,
+		states:      make(map[string]eventState),
+		watchers:    make(map[string]*watcher),
+	}
+}
+
+// Start는 보관 기간 정리와 ONVIF 구독 동기화 고루틴을 시작합니다
+func (m *Manager) Start(ctx context.Context) {
+	go func() {
+		retentionTicker := time.NewTicker(time.Hour)
+		defer retentionTicker.Stop()
+
+		m.deleteExpired()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-retentionTicker.C:
+				m.deleteExpired()
+			}
+		}
+	}()
+
+	if m.config.ONVIFTargets != nil {
+		go m.runONVIFSync(ctx)
+	}
+}
+
+// Close는 모든 ONVIF 구독을 해제하고 종료를 기다립니다
+func (m *Manager) Close() {
+	m.watcherMutex.Lock()
+	m.closed = true
+	for streamID, w := range m.watchers {
+		w.cancel()
+		delete(m.watchers, streamID)
+	}
+	m.watcherMutex.Unlock()
+
+	m.wg.Wait()
+}
+
+// Publish는 이벤트를 저장하고 구독자에게 전달합니다
+// 같은 상태가 반복된 알림은 저장하지 않고 stored=false를 반환합니다 (녹화 연장은 수행)
+func (m *Manager) Publish(event *database.Event) (stored bool, err error) {
+	if event.StreamID == "" {
+		return false, fmt.Errorf("event stream ID is required")
+	}
+	if !IsValidType(event.Type) {
+		return false, fmt.Errorf("invalid event type: %q", event.Type)
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	// 녹화 트리거 (발생 이벤트만, 반복 알림도 post-roll 연장)
+	if event.Active && m.config.OnTrigger != nil && (m.recordTypes == nil || m.recordTypes[event.Type]) {
+		event.Recording = m.config.OnTrigger(event.StreamID)
+	}
+
+	if m.isDuplicate(event) {
+		return false, nil
+	}
+
+	if err := m.repo.Insert(event); err != nil {
+		return false, err
+	}
+
+	m.logger.Info("Camera event",
+		zap.String("stream_id", event.StreamID),
+		zap.String("type", event.Type),
+		zap.Bool("active", event.Active),
+		zap.String("source", event.Source),
+		zap.Bool("recording", event.Recording),
+	)
+
+	if m.config.OnEvent != nil {
+		m.config.OnEvent(event)
+	}
+
+	return true, nil
+}
+
+// isDuplicate는 직전과 같은 상태의 반복 알림인지 확인하고 마지막 상태를 갱신합니다
+// 해제 상태의 반복은 항상, 발생 상태의 반복은 DedupeInterval 이내면 중복으로 봅니다
+func (m *Manager) isDuplicate(event *database.Event) bool {
+	key := event.StreamID + "/" + event.Type
+
+	m.stateMutex.Lock()
+	defer m.stateMutex.Unlock()
+
+	last, exists := m.states[key]
+	if exists && last.active == event.Active {
+		if !event.Active || event.Time.Sub(last.time) < m.config.DedupeInterval {
+			return true
+		}
+	}
+
+	m.states[key] = eventState{active: event.Active, time: event.Time}
+	return false
+}
+
+// Query는 저장된 이벤트를 조회합니다
+func (m *Manager) Query(filter database.EventFilter) ([]*database.Event, int, error) {
+	return m.repo.Query(filter)
+}
+
+// deleteExpired는 보관 기간이 지난 이벤트를 삭제합니다
+func (m *Manager) deleteExpired() {
+	if m.config.Retention <= 0 {
+		return
+	}
+
+	deleted, err := m.repo.DeleteBefore(time.Now().Add(-m.config.Retention))
+	if err != nil {
+		m.logger.Warn("Failed to delete expired events", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		m.logger.Info("Expired events deleted", zap.Int64("count", deleted))
+	}
+}
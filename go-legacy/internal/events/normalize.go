@@ -0,0 +1,308 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/onvif"
+)
+
+// 정규화된 이벤트 타입
+const (
+	TypeMotion       = "motion"
+	TypeLineCrossing = "line_crossing"
+	TypeIntrusion    = "intrusion"
+	TypeTamper       = "tamper"
+	TypeInput        = "input"
+	TypeVideoLoss    = "video_loss"
+	TypeAlarm        = "alarm" // 분류할 수 없는 제조사 알람
+)
+
+// 이벤트 수집 경로
+const (
+	SourceONVIF = "onvif"
+	SourceHTTP  = "http"
+)
+
+// Types는 지원하는 이벤트 타입 목록입니다
+var Types = []string{TypeMotion, TypeLineCrossing, TypeIntrusion, TypeTamper, TypeInput, TypeVideoLoss, TypeAlarm}
+
+// IsValidType은 지원하는 이벤트 타입인지 확인합니다
+func IsValidType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// onvifTopics는 ONVIF 토픽(소문자) 일부와 이벤트 타입의 대응입니다 (앞에서부터 매칭)
+var onvifTopics = []struct {
+	match     string
+	eventType string
+}{
+	{"linedetector", TypeLineCrossing},
+	{"linecross", TypeLineCrossing},
+	{"fielddetector", TypeIntrusion},
+	{"intrusion", TypeIntrusion},
+	{"tamper", TypeTamper},
+	{"globalscenechange", TypeTamper},
+	{"signalloss", TypeVideoLoss},
+	{"videoloss", TypeVideoLoss},
+	{"digitalinput", TypeInput},
+	{"motion", TypeMotion},
+}
+
+// onvifStateItems는 발생/해제 상태를 나타내는 Data 항목 이름입니다
+var onvifStateItems = []string{"IsMotion", "State", "IsInside", "IsTamper", "LogicalState", "Active"}
+
+// FromONVIF는 ONVIF 알림을 이벤트로 변환합니다
+// 알 수 없는 토픽(시스템 상태 등)과 구독 시점의 해제 상태(Initialized)는 ok=false입니다
+func FromONVIF(n onvif.Notification) (database.Event, bool) {
+	topic := strings.ToLower(n.Topic)
+
+	eventType := ""
+	for _, t := range onvifTopics {
+		if strings.Contains(topic, t.match) {
+			eventType = t.eventType
+			break
+		}
+	}
+	if eventType == "" {
+		return database.Event{}, false
+	}
+
+	// 상태 항목이 없으면 순간 이벤트 (라인 크로싱 등)
+	active := true
+	for _, name := range onvifStateItems {
+		if value, ok := n.Data[name]; ok {
+			active = parseBool(value)
+			break
+		}
+	}
+
+	if n.Operation == "Initialized" && !active {
+		return database.Event{}, false
+	}
+
+	return database.Event{
+		Time:    n.Time,
+		Type:    eventType,
+		Active:  active,
+		Source:  SourceONVIF,
+		Topic:   n.Topic,
+		Details: formatItems(n.Source, n.Data),
+	}, true
+}
+
+// vendorTypes는 제조사 HTTP 알람 이벤트 이름(소문자)과 이벤트 타입의 대응입니다
+var vendorTypes = map[string]string{
+	// Hikvision (EventNotificationAlert eventType)
+	"vmd":             TypeMotion,
+	"motiondetection": TypeMotion,
+	"linedetection":   TypeLineCrossing,
+	"fielddetection":  TypeIntrusion,
+	"regionentrance":  TypeIntrusion,
+	"regionexiting":   TypeIntrusion,
+	"tamperdetection": TypeTamper,
+	"shelteralarm":    TypeTamper,
+	"io":              TypeInput,
+	"videoloss":       TypeVideoLoss,
+	// Dahua / 기타
+	"videomotion":          TypeMotion,
+	"crosslinedetection":   TypeLineCrossing,
+	"crossregiondetection": TypeIntrusion,
+	"videoblind":           TypeTamper,
+	"alarmlocal":           TypeInput,
+}
+
+// httpAlarm은 일반 JSON 알람 본문입니다
+type httpAlarm struct {
+	Type    string          `json:"type"`
+	Active  *bool           `json:"active"`
+	State   string          `json:"state"` // active/inactive (active 대신 사용 가능)
+	Time    string          `json:"time"`  // RFC3339 (없으면 수신 시각)
+	Source  string          `json:"source"`
+	Details json.RawMessage `json:"details"` // 문자열 또는 객체
+}
+
+// hikvisionAlert는 Hikvision HTTP 알람 본문입니다
+type hikvisionAlert struct {
+	XMLName          xml.Name `xml:"EventNotificationAlert"`
+	DateTime         string   `xml:"dateTime"`
+	EventType        string   `xml:"eventType"`
+	EventState       string   `xml:"eventState"`
+	EventDescription string   `xml:"eventDescription"`
+	ChannelID        string   `xml:"channelID"`
+	InputIOPortID    string   `xml:"inputIOPortID"`
+}
+
+// ParseHTTPAlarm은 카메라/외부 시스템의 HTTP 알람 본문을 이벤트로 변환합니다
+// 지원 형식: 일반 JSON ({"type","active"|"state","time","details"}),
+// Hikvision EventNotificationAlert XML (단독 또는 multipart/form-data의 XML 파트)
+func ParseHTTPAlarm(contentType string, body []byte) (database.Event, error) {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		part, err := firstAlarmPart(body, params["boundary"])
+		if err != nil {
+			return database.Event{}, err
+		}
+		body = part
+	}
+
+	body = bytes.TrimSpace(body)
+	switch {
+	case len(body) == 0:
+		return database.Event{}, fmt.Errorf("empty alarm body")
+	case body[0] == '{':
+		return parseJSONAlarm(body)
+	case body[0] == '<':
+		return parseHikvisionAlarm(body)
+	}
+	return database.Event{}, fmt.Errorf("unsupported alarm format (JSON or EventNotificationAlert XML required)")
+}
+
+// firstAlarmPart는 multipart 본문에서 첫 번째 XML/JSON 파트를 찾습니다 (스냅샷 이미지 파트는 무시)
+func firstAlarmPart(body []byte, boundary string) ([]byte, error) {
+	if boundary == "" {
+		return nil, fmt.Errorf("multipart boundary is missing")
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no XML or JSON part in multipart alarm")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart alarm: %w", err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart alarm: %w", err)
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '<' || trimmed[0] == '{') {
+			return trimmed, nil
+		}
+	}
+}
+
+// parseJSONAlarm은 일반 JSON 알람을 변환합니다
+func parseJSONAlarm(body []byte) (database.Event, error) {
+	var alarm httpAlarm
+	if err := json.Unmarshal(body, &alarm); err != nil {
+		return database.Event{}, fmt.Errorf("invalid JSON alarm: %w", err)
+	}
+	if alarm.Type == "" {
+		return database.Event{}, fmt.Errorf("alarm type is required")
+	}
+
+	event := database.Event{
+		Type:   normalizeVendorType(alarm.Type),
+		Active: true,
+		Source: SourceHTTP,
+		Topic:  alarm.Type,
+	}
+	if alarm.Source != "" {
+		event.Source = alarm.Source
+	}
+	switch {
+	case alarm.Active != nil:
+		event.Active = *alarm.Active
+	case alarm.State != "":
+		event.Active = parseBool(alarm.State)
+	}
+	if alarm.Time != "" {
+		t, err := time.Parse(time.RFC3339Nano, alarm.Time)
+		if err != nil {
+			return database.Event{}, fmt.Errorf("invalid alarm time (RFC3339 required): %s", alarm.Time)
+		}
+		event.Time = t
+	}
+	if len(alarm.Details) > 0 {
+		var text string
+		if err := json.Unmarshal(alarm.Details, &text); err == nil {
+			event.Details = text
+		} else {
+			event.Details = string(alarm.Details)
+		}
+	}
+
+	return event, nil
+}
+
+// parseHikvisionAlarm은 Hikvision EventNotificationAlert XML을 변환합니다
+func parseHikvisionAlarm(body []byte) (database.Event, error) {
+	var alert hikvisionAlert
+	if err := xml.Unmarshal(body, &alert); err != nil {
+		return database.Event{}, fmt.Errorf("invalid XML alarm: %w", err)
+	}
+	if alert.EventType == "" {
+		return database.Event{}, fmt.Errorf("alarm eventType is required")
+	}
+
+	event := database.Event{
+		Type:   normalizeVendorType(alert.EventType),
+		Active: alert.EventState == "" || parseBool(alert.EventState),
+		Source: SourceHTTP,
+		Topic:  alert.EventType,
+	}
+
+	// 카메라 시계가 틀린 경우가 많으므로 시각은 수신 시각 사용 (카메라 시각은 details에 보관)
+	details := map[string]string{
+		"channel":     alert.ChannelID,
+		"input":       alert.InputIOPortID,
+		"description": alert.EventDescription,
+		"camera_time": strings.TrimSpace(alert.DateTime),
+	}
+	event.Details = formatItems(details)
+
+	return event, nil
+}
+
+// normalizeVendorType은 제조사 이벤트 이름을 이벤트 타입으로 변환합니다
+func normalizeVendorType(name string) string {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if IsValidType(lower) {
+		return lower
+	}
+	if t, ok := vendorTypes[lower]; ok {
+		return t
+	}
+	return TypeAlarm
+}
+
+// parseBool은 카메라가 보내는 다양한 상태 표현을 해석합니다
+func parseBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "active", "on", "start":
+		return true
+	}
+	return false
+}
+
+// formatItems는 속성 맵을 "key=value" 목록으로 만듭니다 (빈 값 제외, 키 순 정렬)
+func formatItems(maps ...map[string]string) string {
+	var items []string
+	for _, m := range maps {
+		for k, v := range m {
+			if v != "" {
+				items = append(items, k+"="+v)
+			}
+		}
+	}
+	sort.Strings(items)
+	return strings.Join(items, " ")
+}
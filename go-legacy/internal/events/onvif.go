@@ -0,0 +1,200 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/onvif"
+	"go.uber.org/zap"
+)
+
+const (
+	// onvifSyncInterval은 구독 대상 스트림 목록을 다시 읽는 주기 (스트림 추가/삭제 반영)
+	onvifSyncInterval = 30 * time.Second
+
+	// subscriptionTermination은 PullPoint 구독 유지 시간 (절반이 지나면 Renew)
+	subscriptionTermination = 60 * time.Second
+
+	// pullTimeout은 PullMessages 대기 시간
+	pullTimeout = 5 * time.Second
+
+	// pullMessageLimit은 PullMessages 한 번에 받을 최대 알림 수
+	pullMessageLimit = 100
+
+	// 재연결 대기 시간 (실패할 때마다 두 배, 최대 maxRetryDelay)
+	minRetryDelay = 2 * time.Second
+	maxRetryDelay = 2 * time.Minute
+)
+
+// ONVIFTarget은 이벤트를 구독할 카메라입니다
+type ONVIFTarget struct {
+	StreamID string
+	Address  string // 장치 서비스 URL
+	Username string
+	Password string
+}
+
+// watcher는 스트림 하나의 PullPoint 구독 고루틴입니다
+type watcher struct {
+	target ONVIFTarget
+	cancel context.CancelFunc
+}
+
+// runONVIFSync는 구독 대상 목록을 주기적으로 읽어 구독을 시작/중지합니다
+func (m *Manager) runONVIFSync(ctx context.Context) {
+	ticker := time.NewTicker(onvifSyncInterval)
+	defer ticker.Stop()
+
+	m.syncONVIF(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.syncONVIF(ctx)
+		}
+	}
+}
+
+// syncONVIF는 대상 목록과 실행 중인 구독을 맞춥니다 (주소/인증 정보가 바뀐 스트림은 다시 구독)
+func (m *Manager) syncONVIF(ctx context.Context) {
+	targets, err := m.config.ONVIFTargets()
+	if err != nil {
+		m.logger.Warn("Failed to load ONVIF event targets", zap.Error(err))
+		return
+	}
+
+	wanted := make(map[string]ONVIFTarget, len(targets))
+	for _, target := range targets {
+		wanted[target.StreamID] = target
+	}
+
+	m.watcherMutex.Lock()
+	defer m.watcherMutex.Unlock()
+
+	if m.closed || ctx.Err() != nil {
+		return
+	}
+
+	for streamID, w := range m.watchers {
+		if target, ok := wanted[streamID]; !ok || target != w.target {
+			w.cancel()
+			delete(m.watchers, streamID)
+		}
+	}
+
+	for streamID, target := range wanted {
+		if _, exists := m.watchers[streamID]; exists {
+			continue
+		}
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		m.watchers[streamID] = &watcher{target: target, cancel: cancel}
+
+		m.wg.Add(1)
+		go func(target ONVIFTarget) {
+			defer m.wg.Done()
+			m.watchONVIF(watchCtx, target)
+		}(target)
+	}
+}
+
+// watchONVIF는 PullPoint 구독을 유지하며 실패 시 대기 후 다시 구독합니다
+func (m *Manager) watchONVIF(ctx context.Context, target ONVIFTarget) {
+	logger := m.logger.With(
+		zap.String("stream_id", target.StreamID),
+		zap.String("address", target.Address),
+	)
+	logger.Info("ONVIF event subscription started")
+	defer logger.Info("ONVIF event subscription stopped")
+
+	delay := minRetryDelay
+	for {
+		subscribed, err := m.pullONVIF(ctx, target)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if subscribed {
+			delay = minRetryDelay
+		}
+		if errors.Is(err, onvif.ErrEventsNotSupported) {
+			delay = maxRetryDelay
+		}
+		logger.Warn("ONVIF event subscription failed, retrying",
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// pullONVIF는 구독을 생성하고 실패하거나 ctx가 끝날 때까지 알림을 가져옵니다
+// subscribed는 구독 생성에 성공했는지 여부입니다
+func (m *Manager) pullONVIF(ctx context.Context, target ONVIFTarget) (subscribed bool, err error) {
+	client, err := onvif.NewClient(onvif.ClientConfig{
+		Address:  target.Address,
+		Username: target.Username,
+		Password: target.Password,
+		// PullMessages는 최대 pullTimeout 동안 응답을 보류하므로 그만큼 여유를 둠
+		Timeout: m.config.RequestTimeout + pullTimeout,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	pullPoint, err := client.CreatePullPoint(ctx, subscriptionTermination)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		unsubscribeCtx, cancel := context.WithTimeout(context.Background(), m.config.RequestTimeout)
+		defer cancel()
+		pullPoint.Unsubscribe(unsubscribeCtx)
+	}()
+
+	renewAt := time.Now().Add(subscriptionTermination / 2)
+	for {
+		if time.Now().After(renewAt) {
+			if err := pullPoint.Renew(ctx, subscriptionTermination); err != nil {
+				return true, err
+			}
+			renewAt = time.Now().Add(subscriptionTermination / 2)
+		}
+
+		notifications, err := pullPoint.Pull(ctx, pullTimeout, pullMessageLimit)
+		if err != nil {
+			return true, err
+		}
+
+		for _, n := range notifications {
+			event, ok := FromONVIF(n)
+			if !ok {
+				continue
+			}
+
+			// 카메라 시계가 틀린 경우가 많으므로 녹화 타임라인과 맞도록 수신 시각 사용
+			event.StreamID = target.StreamID
+			event.Time = time.Now()
+
+			if _, err := m.Publish(&event); err != nil {
+				m.logger.Warn("Failed to store ONVIF event",
+					zap.String("stream_id", target.StreamID),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,365 @@
+// Package hooks는 mediamtx 스타일 경로 훅(runOnInit, runOnDemand, runOnReady/NotReady, runOnRead/Unread)을 실행합니다
+// 훅은 core.Stream의 상태 변화(core.StreamHooks)로 실행되며, 명령은 process.Manager로 실행하고
+// 이벤트 정보는 MTX_PATH, MTX_QUERY 등 환경 변수로 전달합니다
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/process"
+	"go.uber.org/zap"
+)
+
+const (
+	// 경로 설정에 값이 없을 때 사용하는 runOnDemand 기본값
+	defaultDemandCloseAfter   = 10 * time.Second
+	defaultDemandStartTimeout = 10 * time.Second
+
+	// activityInterval은 시청 중인 runOnDemand 프로세스의 활동 시간 갱신 주기
+	activityInterval = 5 * time.Second
+)
+
+// Config는 훅 관리자 설정
+type Config struct {
+	RTSPPort int // RTSP_PORT 환경 변수 (runOnDemand 명령이 내부 RTSP 서버로 송출할 때 사용)
+
+	ProcessManager *process.Manager
+	StreamManager  *core.StreamManager
+
+	// Paths는 현재 경로 설정을 반환합니다 (설정 다시 로드 후에는 새 설정)
+	Paths func() map[string]core.PathConfig
+
+	Logger *zap.Logger
+}
+
+// Manager는 경로 훅 프로세스를 관리합니다
+type Manager struct {
+	config Config
+	logger *zap.Logger
+
+	// 일회성 훅(runOnNotReady, runOnUnread) 실행 컨텍스트 (Close 시 취소)
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex       sync.Mutex
+	demands     map[string]struct{}            // runOnDemand를 시작한 경로
+	readerHooks map[string]map[string]struct{} // 경로 -> 실행 중인 runOnRead 프로세스 ID
+}
+
+// NewManager는 새로운 훅 관리자를 생성합니다
+func NewManager(config Config) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{
+		config:      config,
+		logger:      config.Logger,
+		ctx:         ctx,
+		cancel:      cancel,
+		demands:     make(map[string]struct{}),
+		readerHooks: make(map[string]map[string]struct{}),
+	}
+}
+
+// StreamHooks는 StreamManager.SetHooks에 등록할 콜백을 반환합니다
+func (m *Manager) StreamHooks() core.StreamHooks {
+	return core.StreamHooks{
+		OnReady:    m.onReady,
+		OnNotReady: m.onNotReady,
+		OnRead:     m.onRead,
+		OnUnread:   m.onUnread,
+	}
+}
+
+// HasDemand는 경로에 runOnDemand가 설정되어 있는지 반환합니다
+func (m *Manager) HasDemand(path string) bool {
+	pathConfig, ok := m.pathConfig(path)
+	return ok && pathConfig.RunOnDemand != ""
+}
+
+// Demand는 시청 요청 시 runOnDemand 명령을 시작하고 스트림이 준비될 때까지 기다립니다
+// 이미 준비되었거나 명령이 실행 중이면 새로 시작하지 않습니다 (query는 MTX_QUERY로 전달)
+// runOnDemandStartTimeout 안에 준비되지 않으면 명령을 종료합니다
+func (m *Manager) Demand(path, query string) error {
+	pathConfig, ok := m.pathConfig(path)
+	if !ok || pathConfig.RunOnDemand == "" {
+		return fmt.Errorf("path %s has no runOnDemand", path)
+	}
+
+	stream, err := m.config.StreamManager.GetStream(path)
+	if err != nil {
+		return err
+	}
+
+	if stream.IsReady() {
+		m.config.ProcessManager.UpdateActivity(path)
+		return nil
+	}
+
+	m.mutex.Lock()
+	if !m.config.ProcessManager.IsRunning(path) {
+		closeAfter := parseDuration(pathConfig.RunOnDemandCloseAfter, defaultDemandCloseAfter)
+		err := m.config.ProcessManager.Start(path, pathConfig.RunOnDemand, m.env(path, query),
+			pathConfig.RunOnDemandRestart, closeAfter)
+		if err != nil {
+			m.mutex.Unlock()
+			return fmt.Errorf("failed to start runOnDemand: %w", err)
+		}
+		m.demands[path] = struct{}{}
+
+		m.logger.Info("runOnDemand started",
+			zap.String("path", path),
+			zap.Duration("close_after", closeAfter),
+		)
+	}
+	m.mutex.Unlock()
+
+	timeout := parseDuration(pathConfig.RunOnDemandStartTimeout, defaultDemandStartTimeout)
+	if !waitUntil(timeout, stream.IsReady) {
+		// 송출하지 않는 명령은 종료 (다음 시청 요청 시 다시 시작)
+		m.mutex.Lock()
+		delete(m.demands, path)
+		m.mutex.Unlock()
+		m.stop(path)
+
+		return fmt.Errorf("runOnDemand for %s did not publish within %s", path, timeout)
+	}
+
+	return nil
+}
+
+// InitPath는 경로의 runOnInit 명령을 시작합니다 (경로 로드 시)
+func (m *Manager) InitPath(path string) {
+	pathConfig, ok := m.pathConfig(path)
+	if !ok || pathConfig.RunOnInit == "" {
+		return
+	}
+
+	m.start(processID("init", path), pathConfig.RunOnInit, m.env(path, ""), pathConfig.RunOnInitRestart)
+}
+
+// ClosePath는 경로의 모든 훅 프로세스(runOnInit, runOnDemand, runOnReady, runOnRead)를 종료합니다 (경로 삭제/변경 시)
+func (m *Manager) ClosePath(path string) {
+	m.mutex.Lock()
+	readerHooks := m.readerHooks[path]
+	delete(m.readerHooks, path)
+	delete(m.demands, path)
+	m.mutex.Unlock()
+
+	m.stop(processID("init", path))
+	m.stop(processID("ready", path))
+	m.stop(path)
+	for id := range readerHooks {
+		m.stop(id)
+	}
+}
+
+// Run은 시청자가 있는 runOnDemand 프로세스의 활동 시간을 주기적으로 갱신합니다
+// 시청자가 없으면 ProcessManager의 비활동 모니터가 runOnDemandCloseAfter 후 프로세스를 종료합니다
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(activityInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshActivity()
+		}
+	}
+}
+
+// Close는 실행 중인 일회성 훅을 취소합니다 (장기 실행 훅은 ProcessManager.StopAll로 종료)
+func (m *Manager) Close() {
+	m.cancel()
+}
+
+// refreshActivity는 시청자가 있는 runOnDemand 프로세스의 활동 시간을 갱신합니다
+func (m *Manager) refreshActivity() {
+	m.mutex.Lock()
+	paths := make([]string, 0, len(m.demands))
+	for path := range m.demands {
+		if !m.config.ProcessManager.IsRunning(path) {
+			delete(m.demands, path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	m.mutex.Unlock()
+
+	for _, path := range paths {
+		stream, err := m.config.StreamManager.GetStream(path)
+		if err == nil && stream.GetSubscriberCount() > 0 {
+			m.config.ProcessManager.UpdateActivity(path)
+		}
+	}
+}
+
+// onReady는 스트림이 준비되면 runOnReady 명령을 시작합니다
+func (m *Manager) onReady(stream *core.Stream, sourceType, sourceID string) {
+	path := stream.GetID()
+	pathConfig, ok := m.pathConfig(path)
+	if !ok || pathConfig.RunOnReady == "" {
+		return
+	}
+
+	m.start(processID("ready", path), pathConfig.RunOnReady, m.env(path, "",
+		"MTX_SOURCE_TYPE="+sourceType,
+		"MTX_SOURCE_ID="+sourceID,
+	), pathConfig.RunOnReadyRestart)
+}
+
+// onNotReady는 runOnReady 명령을 종료하고 runOnNotReady 명령을 실행합니다
+func (m *Manager) onNotReady(stream *core.Stream, sourceType, sourceID string) {
+	path := stream.GetID()
+	m.stop(processID("ready", path))
+
+	pathConfig, ok := m.pathConfig(path)
+	if !ok || pathConfig.RunOnNotReady == "" {
+		return
+	}
+
+	m.run(processID("notready", path), pathConfig.RunOnNotReady, m.env(path, "",
+		"MTX_SOURCE_TYPE="+sourceType,
+		"MTX_SOURCE_ID="+sourceID,
+	))
+}
+
+// onRead는 시청자마다 runOnRead 명령을 시작합니다
+func (m *Manager) onRead(stream *core.Stream, readerType, readerID string) {
+	path := stream.GetID()
+	pathConfig, ok := m.pathConfig(path)
+	if !ok || pathConfig.RunOnRead == "" {
+		return
+	}
+
+	id := processID("read", path+"/"+readerID)
+	m.mutex.Lock()
+	if m.readerHooks[path] == nil {
+		m.readerHooks[path] = make(map[string]struct{})
+	}
+	m.readerHooks[path][id] = struct{}{}
+	m.mutex.Unlock()
+
+	m.start(id, pathConfig.RunOnRead, m.env(path, "",
+		"MTX_READER_TYPE="+readerType,
+		"MTX_READER_ID="+readerID,
+	), pathConfig.RunOnReadRestart)
+}
+
+// onUnread는 시청자의 runOnRead 명령을 종료하고 runOnUnread 명령을 실행합니다
+func (m *Manager) onUnread(stream *core.Stream, readerType, readerID string) {
+	path := stream.GetID()
+	id := processID("read", path+"/"+readerID)
+
+	m.mutex.Lock()
+	if readerHooks, ok := m.readerHooks[path]; ok {
+		delete(readerHooks, id)
+		if len(readerHooks) == 0 {
+			delete(m.readerHooks, path)
+		}
+	}
+	m.mutex.Unlock()
+
+	m.stop(id)
+
+	pathConfig, ok := m.pathConfig(path)
+	if !ok || pathConfig.RunOnUnread == "" {
+		return
+	}
+
+	m.run(processID("unread", path+"/"+readerID), pathConfig.RunOnUnread, m.env(path, "",
+		"MTX_READER_TYPE="+readerType,
+		"MTX_READER_ID="+readerID,
+	))
+}
+
+// start는 장기 실행 훅 명령을 시작합니다
+func (m *Manager) start(id, command string, env []string, restart bool) {
+	if err := m.config.ProcessManager.Start(id, command, env, restart, 0); err != nil {
+		m.logger.Error("Failed to start hook",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+	}
+}
+
+// stop은 실행 중인 훅 명령을 종료합니다
+func (m *Manager) stop(id string) {
+	if !m.config.ProcessManager.IsRunning(id) {
+		return
+	}
+	if err := m.config.ProcessManager.Stop(id); err != nil {
+		m.logger.Debug("Failed to stop hook",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+	}
+}
+
+// run은 일회성 훅 명령을 백그라운드에서 실행합니다
+func (m *Manager) run(id, command string, env []string) {
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	go func() {
+		if _, err := m.config.ProcessManager.Run(m.ctx, id, command, env, nil); err != nil && m.ctx.Err() == nil {
+			m.logger.Warn("Hook command failed",
+				zap.String("id", id),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// env는 훅 명령에 전달할 환경 변수를 만듭니다
+func (m *Manager) env(path, query string, extra ...string) []string {
+	env := []string{
+		"MTX_PATH=" + path,
+		"MTX_QUERY=" + query,
+		"RTSP_PORT=" + strconv.Itoa(m.config.RTSPPort),
+	}
+	return append(env, extra...)
+}
+
+func (m *Manager) pathConfig(path string) (core.PathConfig, bool) {
+	pathConfig, ok := m.config.Paths()[path]
+	return pathConfig, ok
+}
+
+// processID는 훅 프로세스 ID를 만듭니다 (runOnDemand는 기존과 같이 경로 이름을 ID로 사용)
+func processID(hook, name string) string {
+	return "hook:" + hook + ":" + name
+}
+
+// parseDuration은 경로 설정의 기간 값을 파싱합니다 (빈 값이면 기본값, 검증은 Config.Validate에서)
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
+// waitUntil은 조건이 참이 될 때까지 최대 timeout 동안 기다립니다
+func waitUntil(timeout time.Duration, condition func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if condition() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
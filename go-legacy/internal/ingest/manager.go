@@ -0,0 +1,298 @@
+// Package ingest는 RTMP/SRT 송출(publish)을 받아 core.Stream으로 전달합니다
+// 모바일 장비나 RTSP를 지원하지 않는 인코더에서 사용합니다
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	srt "github.com/datarhei/gosrt"
+	"github.com/pion/rtp"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUnauthorized는 송출 인증 실패 에러입니다
+	ErrUnauthorized = errors.New("publisher authentication failed")
+
+	// ErrAlreadyPublishing은 같은 스트림에 이미 송출 중인 경우의 에러입니다
+	ErrAlreadyPublishing = errors.New("stream is already being published")
+)
+
+// Config는 RTMP/SRT 수신 설정
+type Config struct {
+	RTMPEnabled bool
+	RTMPAddress string // ":1935"
+
+	SRTEnabled    bool
+	SRTAddress    string // ":8890"
+	SRTPassphrase string // 비어있으면 암호화 없음
+
+	ReadTimeout time.Duration
+
+	// 스트림 키 -> 스트림 ID (키 자체가 인증 정보 역할)
+	StreamKeys map[string]string
+	// true면 StreamKeys에 등록된 키로만 송출 가능
+	RequireStreamKey bool
+
+	StreamManager *core.StreamManager
+	AuthManager   *auth.Manager // nil이거나 비활성화면 인증 없음
+	Logger        *zap.Logger
+
+	// 송출 시작 시 호출 (녹화/HLS 준비 등)
+	OnPublish func(streamID string, stream *core.Stream)
+	// 송출 종료 시 호출
+	OnUnpublish func(streamID string)
+	// core.Stream에 쓴 RTP 패킷마다 호출 (HLS 전달 등)
+	OnPacket func(streamID string, pkt *rtp.Packet)
+}
+
+// Manager는 RTMP/SRT 리스너와 송출 세션을 관리합니다
+type Manager struct {
+	config Config
+	logger *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	rtmpListener net.Listener
+	srtListener  srt.Listener
+	draining     atomic.Bool // 리스너를 닫고 기존 송출만 유지 중
+
+	// 스트림 ID -> 송출 세션
+	publishers map[string]*publisher
+	mutex      sync.Mutex
+}
+
+// publisher는 하나의 송출 세션입니다
+type publisher struct {
+	streamID   string
+	protocol   string // rtmp, srt
+	remoteAddr string
+	user       string
+	published  bool // OnPublish 호출 여부
+	stream     *core.Stream
+}
+
+// NewManager는 새로운 수신 관리자를 생성합니다
+func NewManager(config Config) *Manager {
+	if config.ReadTimeout <= 0 {
+		config.ReadTimeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{
+		config:     config,
+		logger:     config.Logger,
+		ctx:        ctx,
+		cancel:     cancel,
+		publishers: make(map[string]*publisher),
+	}
+}
+
+// Start는 설정된 리스너를 엽니다
+func (m *Manager) Start() error {
+	if m.config.RTMPEnabled {
+		if err := m.startRTMP(); err != nil {
+			return fmt.Errorf("failed to start RTMP listener: %w", err)
+		}
+		m.logger.Info("RTMP ingest listener started", zap.String("address", m.config.RTMPAddress))
+	}
+
+	if m.config.SRTEnabled {
+		if err := m.startSRT(); err != nil {
+			m.Stop()
+			return fmt.Errorf("failed to start SRT listener: %w", err)
+		}
+		m.logger.Info("SRT ingest listener started", zap.String("address", m.config.SRTAddress))
+	}
+
+	return nil
+}
+
+// Drain은 리스너를 닫아 새 송출을 거부합니다 (진행 중인 송출은 Stop까지 유지)
+func (m *Manager) Drain() {
+	if m.draining.Swap(true) {
+		return
+	}
+
+	m.closeListeners()
+	m.logger.Info("Ingest listeners closed, draining publishers")
+}
+
+// Stop은 리스너를 닫고 모든 송출 세션을 종료합니다
+func (m *Manager) Stop() {
+	m.cancel()
+
+	if !m.draining.Swap(true) {
+		m.closeListeners()
+	}
+
+	// 연결은 ctx 취소 시 closeOnDone에 의해 닫힘
+	m.wg.Wait()
+	m.logger.Info("Ingest listeners stopped")
+}
+
+// closeListeners는 RTMP/SRT 리스너를 닫습니다
+func (m *Manager) closeListeners() {
+	if m.rtmpListener != nil {
+		m.rtmpListener.Close()
+	}
+	if m.srtListener != nil {
+		m.srtListener.Close()
+	}
+}
+
+// authorize는 스트림 키(또는 스트림 ID)와 인증 정보로 송출 대상 스트림을 결정합니다
+// 등록된 스트림 키면 키 자체로 인증되고, 아니면 operator 이상 권한이 필요합니다
+func (m *Manager) authorize(key, user, pass string) (streamID string, name string, err error) {
+	if key == "" {
+		return "", "", fmt.Errorf("%w: empty stream key", ErrUnauthorized)
+	}
+
+	if mapped, ok := m.config.StreamKeys[key]; ok {
+		return mapped, "stream-key", nil
+	}
+
+	if m.config.RequireStreamKey {
+		return "", "", fmt.Errorf("%w: unknown stream key", ErrUnauthorized)
+	}
+
+	if !m.config.AuthManager.IsEnabled() {
+		return key, auth.Anonymous.Name, nil
+	}
+
+	identity, err := m.config.AuthManager.Authenticate(auth.Credentials{User: user, Pass: pass})
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	if !identity.HasRole(auth.RoleOperator) || !identity.CanView(key) {
+		return "", "", fmt.Errorf("%w: %s is not allowed to publish to %s", ErrUnauthorized, identity.Name, key)
+	}
+
+	return key, identity.Name, nil
+}
+
+// acquire는 스트림의 송출 권한을 얻고 core.Stream을 반환합니다 (스트림이 없으면 생성)
+func (m *Manager) acquire(p *publisher) (*core.Stream, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.ctx.Err() != nil {
+		return nil, errors.New("ingest manager is stopped")
+	}
+
+	if existing, exists := m.publishers[p.streamID]; exists {
+		return nil, fmt.Errorf("%w (%s from %s)", ErrAlreadyPublishing, existing.protocol, existing.remoteAddr)
+	}
+
+	stream, err := m.config.StreamManager.GetStream(p.streamID)
+	if err != nil {
+		stream, err = m.config.StreamManager.CreateStream(p.streamID, p.streamID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stream: %w", err)
+		}
+	}
+
+	m.publishers[p.streamID] = p
+	return stream, nil
+}
+
+// release는 송출 권한을 반납합니다
+func (m *Manager) release(p *publisher) {
+	m.mutex.Lock()
+	if m.publishers[p.streamID] == p {
+		delete(m.publishers, p.streamID)
+	}
+	m.mutex.Unlock()
+
+	if !p.published {
+		return
+	}
+
+	m.logger.Info("Ingest publisher stopped",
+		zap.String("stream_id", p.streamID),
+		zap.String("protocol", p.protocol),
+		zap.String("remote_addr", p.remoteAddr),
+	)
+
+	p.stream.SetNotReady()
+
+	if m.config.OnUnpublish != nil {
+		m.config.OnUnpublish(p.streamID)
+	}
+}
+
+// newPacketizer는 변환한 RTP 패킷을 스트림과 OnPacket 콜백에 전달하는 Packetizer를 생성합니다
+func (m *Manager) newPacketizer(streamID string, stream *core.Stream) *packetizer.Packetizer {
+	return packetizer.New(stream, func(pkt *rtp.Packet) {
+		if err := stream.WritePacket(pkt); err != nil {
+			m.logger.Error("Failed to write packet to stream",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+			return
+		}
+
+		if m.config.OnPacket != nil {
+			m.config.OnPacket(streamID, pkt)
+		}
+	}, m.logger)
+}
+
+// publishStarted는 첫 트랙 설정 후 송출 시작을 알립니다
+func (m *Manager) publishStarted(p *publisher, stream *core.Stream, w *packetizer.Packetizer) {
+	p.published = true
+	p.stream = stream
+
+	m.logger.Info("Ingest publisher started",
+		zap.String("stream_id", p.streamID),
+		zap.String("protocol", p.protocol),
+		zap.String("remote_addr", p.remoteAddr),
+		zap.String("user", p.user),
+		zap.String("video_codec", w.VideoCodec()),
+		zap.Bool("audio", w.HasAudio()),
+	)
+
+	// 경로 훅 소스 종류는 mediamtx와 같이 rtmpConn, srtConn
+	stream.SetReady(p.protocol+"Conn", p.remoteAddr)
+
+	if m.config.OnPublish != nil {
+		m.config.OnPublish(p.streamID, stream)
+	}
+}
+
+// closeOnDone은 관리자가 종료되거나 done이 닫히면 연결을 닫습니다
+// 핸드셰이크 중인 연결도 Stop 시 즉시 종료되도록 합니다
+func (m *Manager) closeOnDone(c io.Closer, done <-chan struct{}) {
+	go func() {
+		select {
+		case <-m.ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+}
+
+// hostOf는 주소에서 호스트 부분만 반환합니다
+func hostOf(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
@@ -0,0 +1,129 @@
+package ingest
+
+import (
+	"errors"
+	"net"
+	"path"
+	"time"
+
+	"github.com/bluenviron/gortmplib"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"go.uber.org/zap"
+)
+
+// startRTMP는 RTMP 리스너를 엽니다
+func (m *Manager) startRTMP() error {
+	ln, err := net.Listen("tcp", m.config.RTMPAddress)
+	if err != nil {
+		return err
+	}
+	m.rtmpListener = ln
+
+	m.wg.Add(1)
+	go m.acceptRTMP(ln)
+
+	return nil
+}
+
+// acceptRTMP는 RTMP 연결을 받습니다
+func (m *Manager) acceptRTMP(ln net.Listener) {
+	defer m.wg.Done()
+
+	for {
+		nconn, err := ln.Accept()
+		if err != nil {
+			if m.ctx.Err() == nil && !m.draining.Load() {
+				m.logger.Error("RTMP accept failed", zap.Error(err))
+			}
+			return
+		}
+
+		m.wg.Add(1)
+		go m.handleRTMP(nconn)
+	}
+}
+
+// handleRTMP는 하나의 RTMP 연결을 처리합니다
+func (m *Manager) handleRTMP(nconn net.Conn) {
+	defer m.wg.Done()
+
+	done := make(chan struct{})
+	defer close(done)
+	defer nconn.Close()
+	m.closeOnDone(nconn, done)
+
+	remoteAddr := nconn.RemoteAddr().String()
+	m.logger.Info("RTMP client connected", zap.String("remote_addr", remoteAddr))
+
+	err := m.runRTMP(nconn)
+
+	m.logger.Info("RTMP client disconnected",
+		zap.String("remote_addr", remoteAddr),
+		zap.Error(err),
+	)
+}
+
+// runRTMP는 RTMP 핸드셰이크, 인증, 트랙 설정 후 송출 데이터를 읽습니다
+// 스트림 키는 URL의 마지막 경로입니다 (예: rtmp://host:1935/live/<key>?user=..&pass=..)
+func (m *Manager) runRTMP(nconn net.Conn) error {
+	nconn.SetDeadline(time.Now().Add(m.config.ReadTimeout))
+
+	conn := &gortmplib.ServerConn{RW: nconn}
+	if err := conn.Initialize(); err != nil {
+		return err
+	}
+	if err := conn.Accept(); err != nil {
+		return err
+	}
+
+	if !conn.Publish {
+		return errors.New("RTMP playback is not supported, use RTSP, WebRTC or HLS")
+	}
+
+	key := path.Base(conn.URL.Path)
+	query := conn.URL.Query()
+
+	streamID, user, err := m.authorize(key, query.Get("user"), query.Get("pass"))
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			// 무차별 대입 방지
+			time.Sleep(auth.PauseAfterError)
+		}
+		return err
+	}
+
+	p := &publisher{
+		streamID:   streamID,
+		protocol:   "rtmp",
+		remoteAddr: hostOf(nconn.RemoteAddr()),
+		user:       user,
+	}
+
+	stream, err := m.acquire(p)
+	if err != nil {
+		return err
+	}
+	defer m.release(p)
+
+	r := &gortmplib.Reader{Conn: conn}
+	if err := r.Initialize(); err != nil {
+		return err
+	}
+
+	w := m.newPacketizer(streamID, stream)
+	if err := packetizer.SetupRTMP(r, w); err != nil {
+		return err
+	}
+
+	m.publishStarted(p, stream, w)
+
+	nconn.SetWriteDeadline(time.Time{})
+
+	for {
+		nconn.SetReadDeadline(time.Now().Add(m.config.ReadTimeout))
+		if err := r.Read(); err != nil {
+			return err
+		}
+	}
+}
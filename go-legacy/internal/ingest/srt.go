@@ -0,0 +1,233 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/mpegts"
+	srt "github.com/datarhei/gosrt"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"go.uber.org/zap"
+)
+
+// srtStreamID는 SRT streamid에서 추출한 송출 정보입니다
+type srtStreamID struct {
+	publish bool
+	key     string
+	user    string
+	pass    string
+}
+
+// parseSRTStreamID는 SRT streamid를 파싱합니다
+// 지원 형식:
+//   - "<key>" (송출)
+//   - "publish:<key>" 또는 "publish:<key>:<user>:<pass>" (mediamtx 형식)
+//   - "#!::r=<key>,m=publish,u=<user>,s=<pass>" (SRT 표준 access control 형식)
+func parseSRTStreamID(raw string) (*srtStreamID, error) {
+	if raw == "" {
+		return nil, errors.New("empty streamid")
+	}
+
+	if strings.HasPrefix(raw, "#!::") {
+		id := &srtStreamID{publish: true}
+		for _, kv := range strings.Split(raw[len("#!::"):], ",") {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid streamid entry '%s'", kv)
+			}
+			switch key {
+			case "r":
+				id.key = value
+			case "u":
+				id.user = value
+			case "s":
+				id.pass = value
+			case "m":
+				switch value {
+				case "publish":
+					id.publish = true
+				case "request":
+					id.publish = false
+				default:
+					return nil, fmt.Errorf("unsupported mode '%s'", value)
+				}
+			}
+		}
+		return id, nil
+	}
+
+	parts := strings.Split(raw, ":")
+	switch {
+	case len(parts) == 1:
+		return &srtStreamID{publish: true, key: parts[0]}, nil
+
+	case parts[0] == "publish" || parts[0] == "read":
+		id := &srtStreamID{publish: parts[0] == "publish", key: parts[1]}
+		switch len(parts) {
+		case 2:
+		case 4:
+			id.user, id.pass = parts[2], parts[3]
+		default:
+			return nil, errors.New("streamid must be 'publish:<key>' or 'publish:<key>:<user>:<pass>'")
+		}
+		return id, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported streamid action '%s'", parts[0])
+	}
+}
+
+// startSRT는 SRT 리스너를 엽니다
+func (m *Manager) startSRT() error {
+	conf := srt.DefaultConfig()
+	conf.ConnectionTimeout = m.config.ReadTimeout
+	conf.PeerIdleTimeout = m.config.ReadTimeout
+
+	ln, err := srt.Listen("srt", m.config.SRTAddress, conf)
+	if err != nil {
+		return err
+	}
+	m.srtListener = ln
+
+	m.wg.Add(1)
+	go m.acceptSRT(ln)
+
+	return nil
+}
+
+// acceptSRT는 SRT 연결 요청을 받습니다
+func (m *Manager) acceptSRT(ln srt.Listener) {
+	defer m.wg.Done()
+
+	for {
+		req, err := ln.Accept2()
+		if err != nil {
+			if m.ctx.Err() == nil && !m.draining.Load() && !errors.Is(err, srt.ErrListenerClosed) {
+				m.logger.Error("SRT accept failed", zap.Error(err))
+			}
+			return
+		}
+
+		m.wg.Add(1)
+		go m.handleSRT(req)
+	}
+}
+
+// handleSRT는 하나의 SRT 연결 요청을 처리합니다
+func (m *Manager) handleSRT(req srt.ConnRequest) {
+	defer m.wg.Done()
+
+	remoteAddr := req.RemoteAddr().String()
+	m.logger.Info("SRT client connected",
+		zap.String("remote_addr", remoteAddr),
+		zap.String("streamid", maskSRTStreamID(req.StreamId())),
+	)
+
+	err := m.runSRT(req)
+
+	m.logger.Info("SRT client disconnected",
+		zap.String("remote_addr", remoteAddr),
+		zap.Error(err),
+	)
+}
+
+// runSRT는 streamid 인증 후 MPEG-TS 송출 데이터를 읽습니다
+func (m *Manager) runSRT(req srt.ConnRequest) error {
+	id, err := parseSRTStreamID(req.StreamId())
+	if err != nil {
+		req.Reject(srt.REJ_PEER)
+		return fmt.Errorf("invalid streamid: %w", err)
+	}
+	if !id.publish {
+		req.Reject(srt.REJ_PEER)
+		return errors.New("SRT playback is not supported, use RTSP, WebRTC or HLS")
+	}
+
+	streamID, user, err := m.authorize(id.key, id.user, id.pass)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			// 무차별 대입 방지
+			time.Sleep(auth.PauseAfterError)
+		}
+		req.Reject(srt.REJ_PEER)
+		return err
+	}
+
+	if m.config.SRTPassphrase != "" {
+		if !req.IsEncrypted() {
+			req.Reject(srt.REJ_PEER)
+			return errors.New("passphrase is required")
+		}
+		if err := req.SetPassphrase(m.config.SRTPassphrase); err != nil {
+			req.Reject(srt.REJ_PEER)
+			return errors.New("invalid passphrase")
+		}
+	}
+
+	p := &publisher{
+		streamID:   streamID,
+		protocol:   "srt",
+		remoteAddr: hostOf(req.RemoteAddr()),
+		user:       user,
+	}
+
+	stream, err := m.acquire(p)
+	if err != nil {
+		req.Reject(srt.REJ_PEER)
+		return err
+	}
+	defer m.release(p)
+
+	sconn, err := req.Accept()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	defer sconn.Close()
+	m.closeOnDone(sconn, done)
+
+	r := &mpegts.Reader{R: sconn}
+	if err := r.Initialize(); err != nil {
+		return err
+	}
+
+	decodeErrors := 0
+	r.OnDecodeError(func(err error) {
+		decodeErrors++
+		// 첫 오류와 이후 100개마다 기록
+		if decodeErrors%100 == 1 {
+			m.logger.Warn("SRT MPEG-TS decode error",
+				zap.String("stream_id", streamID),
+				zap.Int("count", decodeErrors),
+				zap.Error(err),
+			)
+		}
+	})
+
+	w := m.newPacketizer(streamID, stream)
+	if err := packetizer.SetupMPEGTS(r, w); err != nil {
+		return err
+	}
+
+	m.publishStarted(p, stream, w)
+
+	for {
+		if err := r.Read(); err != nil {
+			return err
+		}
+	}
+}
+
+// maskSRTStreamID는 로그용으로 streamid의 비밀번호를 가립니다
+func maskSRTStreamID(raw string) string {
+	id, err := parseSRTStreamID(raw)
+	if err != nil || id.pass == "" {
+		return raw
+	}
+	return strings.Replace(raw, id.pass, "***", 1)
+}
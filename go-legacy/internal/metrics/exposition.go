@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 메트릭 종류 (Prometheus TYPE)
+const (
+	typeGauge   = "gauge"
+	typeCounter = "counter"
+)
+
+// family는 이름이 같은 메트릭 샘플 묶음입니다 (HELP/TYPE 한 번 + 샘플들)
+type family struct {
+	name    string
+	typ     string
+	help    string
+	samples []string
+}
+
+// exposition은 Prometheus 텍스트 형식(0.0.4) 출력을 만듭니다
+// 샘플은 처음 추가된 메트릭 이름 순서대로 묶어서 출력합니다
+type exposition struct {
+	families []*family
+	index    map[string]*family
+}
+
+func newExposition() *exposition {
+	return &exposition{
+		index: make(map[string]*family),
+	}
+}
+
+// family는 메트릭 이름의 family를 반환합니다 (없으면 생성)
+func (e *exposition) family(name, typ, help string) *family {
+	f, exists := e.index[name]
+	if !exists {
+		f = &family{name: name, typ: typ, help: help}
+		e.index[name] = f
+		e.families = append(e.families, f)
+	}
+	return f
+}
+
+// gauge는 gauge 샘플을 추가합니다 (labels는 key, value 순서의 쌍)
+func (e *exposition) gauge(name, help string, value float64, labels ...string) {
+	f := e.family(name, typeGauge, help)
+	f.samples = append(f.samples, sample(name, value, labels))
+}
+
+// counter는 counter 샘플을 추가합니다 (labels는 key, value 순서의 쌍)
+func (e *exposition) counter(name, help string, value float64, labels ...string) {
+	f := e.family(name, typeCounter, help)
+	f.samples = append(f.samples, sample(name, value, labels))
+}
+
+// declare는 샘플이 없어도 HELP/TYPE을 출력하도록 family를 등록합니다
+func (e *exposition) declare(name, typ, help string) {
+	e.family(name, typ, help)
+}
+
+// WriteTo는 텍스트 형식으로 출력합니다
+func (e *exposition) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, f := range e.families {
+		b.WriteString("# HELP " + f.name + " " + f.help + "\n")
+		b.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+		for _, s := range f.samples {
+			b.WriteString(s)
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// sample은 샘플 한 줄을 만듭니다 (name{k="v",...} value)
+func sample(name string, value float64, labels []string) string {
+	var b strings.Builder
+	b.WriteString(name)
+
+	if len(labels) >= 2 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(escapeLabel(labels[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// labelEscaper는 레이블 값의 역슬래시, 큰따옴표, 줄바꿈을 이스케이프합니다
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
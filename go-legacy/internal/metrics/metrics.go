@@ -0,0 +1,432 @@
+// Package metrics는 Prometheus 텍스트 형식의 메트릭 엔드포인트(/metrics)를 제공합니다
+// 스트림(수신/송신 패킷·바이트, 구독자, 소스 연결 상태, 재연결/드롭 횟수), WebRTC 피어, HLS muxer,
+// 프로세스/런타임 메트릭을 스크레이프 시점에 각 관리자에서 읽어 출력합니다
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/hls"
+	"github.com/yourusername/cctv3/internal/webrtc"
+	"go.uber.org/zap"
+)
+
+// 메트릭 이름 접두사
+const namespace = "cctv_"
+
+// Config는 메트릭 서버 설정
+type Config struct {
+	Address   string        // 리스닝 주소 (예: ":9090")
+	Interval  time.Duration // 비트레이트 계산 간격
+	Version   string
+	StartTime time.Time // 서버 시작 시각 (uptime 계산)
+
+	StreamManager *core.StreamManager
+	WebRTCManager *webrtc.Manager
+	HLSManager    *hls.Manager  // nil이면 HLS 메트릭 생략
+	AuthManager   *auth.Manager // 인증이 활성화되어 있으면 operator 이상만 조회 가능
+
+	// SourceStates는 풀 소스(RTSP/RTMP/SRT/HLS/UDP/MJPEG 클라이언트)가 있는 스트림의 연결 상태를 반환합니다
+	SourceStates func() map[string]bool
+	// ClientCount는 시그널링(WebSocket) 클라이언트 수를 반환합니다
+	ClientCount func() int
+
+	Logger *zap.Logger
+}
+
+// bitrate는 마지막 수집 간격 동안의 스트림 비트레이트 (bps)
+type bitrate struct {
+	received float64
+	sent     float64
+}
+
+// streamSample은 비트레이트 계산용 이전 누적 바이트 수
+type streamSample struct {
+	bytesReceived uint64
+	bytesSent     uint64
+	at            time.Time
+}
+
+// Server는 메트릭 HTTP 서버입니다
+type Server struct {
+	config     Config
+	logger     *zap.Logger
+	httpServer *http.Server
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	// 설정 다시 로드 시 교체될 수 있음 (SetHLSManager)
+	hlsManager atomic.Pointer[hls.Manager]
+
+	// 수집 간격마다 갱신되는 스트림별 비트레이트
+	rates   map[string]bitrate
+	samples map[string]streamSample
+	mutex   sync.RWMutex
+}
+
+// NewServer는 새로운 메트릭 서버를 생성합니다
+func NewServer(config Config) *Server {
+	if config.Interval <= 0 {
+		config.Interval = 10 * time.Second
+	}
+	if config.StartTime.IsZero() {
+		config.StartTime = time.Now()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Server{
+		config:    config,
+		logger:    config.Logger.With(zap.String("component", "metrics")),
+		ctx:       ctx,
+		ctxCancel: cancel,
+		rates:     make(map[string]bitrate),
+		samples:   make(map[string]streamSample),
+	}
+
+	s.hlsManager.Store(config.HLSManager)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	s.httpServer = &http.Server{
+		Addr:         config.Address,
+		Handler:      mux,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	return s
+}
+
+// SetHLSManager는 HLS 관리자를 교체합니다 (nil이면 HLS 메트릭 생략)
+func (s *Server) SetHLSManager(manager *hls.Manager) {
+	s.hlsManager.Store(manager)
+}
+
+// Start는 메트릭 서버와 비트레이트 수집을 시작합니다
+// 포트를 열 수 없으면 에러를 반환합니다
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Address, err)
+	}
+
+	s.logger.Info("Starting metrics server",
+		zap.String("addr", s.config.Address),
+		zap.Duration("interval", s.config.Interval),
+	)
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Metrics server error", zap.Error(err))
+		}
+	}()
+
+	go s.collectLoop()
+
+	return nil
+}
+
+// Stop은 메트릭 서버를 종료합니다
+func (s *Server) Stop() error {
+	s.logger.Info("Stopping metrics server")
+	s.ctxCancel()
+	return s.httpServer.Close()
+}
+
+// collectLoop는 수집 간격마다 스트림 비트레이트를 계산합니다
+func (s *Server) collectLoop() {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.collectRates()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.collectRates()
+		}
+	}
+}
+
+// collectRates는 이전 수집 이후 증가한 바이트 수로 스트림별 비트레이트를 계산합니다
+func (s *Server) collectRates() {
+	now := time.Now()
+	streams := s.config.StreamManager.ListStreams()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, stream := range streams {
+		_, _, bytesReceived, bytesSent := stream.GetStats()
+
+		if prev, exists := s.samples[id]; exists {
+			elapsed := now.Sub(prev.at).Seconds()
+			if elapsed > 0 && bytesReceived >= prev.bytesReceived && bytesSent >= prev.bytesSent {
+				s.rates[id] = bitrate{
+					received: float64(bytesReceived-prev.bytesReceived) * 8 / elapsed,
+					sent:     float64(bytesSent-prev.bytesSent) * 8 / elapsed,
+				}
+			}
+		}
+		s.samples[id] = streamSample{bytesReceived: bytesReceived, bytesSent: bytesSent, at: now}
+	}
+
+	// 삭제된 스트림 정리
+	for id := range s.samples {
+		if _, exists := streams[id]; !exists {
+			delete(s.samples, id)
+			delete(s.rates, id)
+		}
+	}
+}
+
+// handleMetrics는 Prometheus 텍스트 형식으로 메트릭을 출력합니다
+// GET /metrics
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.config.AuthManager.IsEnabled() {
+		identity, err := s.config.AuthManager.Authenticate(auth.CredentialsFromRequest(r))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !identity.HasRole(auth.RoleOperator) {
+			http.Error(w, "forbidden: operator role required", http.StatusForbidden)
+			return
+		}
+	}
+
+	e := newExposition()
+	s.writeServerMetrics(e)
+	s.writeStreamMetrics(e)
+	s.writePeerMetrics(e)
+	s.writeHLSMetrics(e)
+	writeRuntimeMetrics(e)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := e.WriteTo(w); err != nil {
+		s.logger.Debug("Failed to write metrics", zap.Error(err))
+	}
+}
+
+// writeServerMetrics는 서버 정보와 uptime, 시그널링 클라이언트 수를 추가합니다
+func (s *Server) writeServerMetrics(e *exposition) {
+	e.gauge(namespace+"info", "Server version information.", 1, "version", s.config.Version)
+	e.gauge(namespace+"uptime_seconds", "Time since the server started.", time.Since(s.config.StartTime).Seconds())
+
+	if s.config.ClientCount != nil {
+		e.gauge(namespace+"signaling_clients", "Connected signaling (WebSocket) clients.", float64(s.config.ClientCount()))
+	}
+}
+
+// writeStreamMetrics는 스트림별 메트릭을 추가합니다 (stream_id 레이블, ID순)
+func (s *Server) writeStreamMetrics(e *exposition) {
+	streams := s.config.StreamManager.ListStreams()
+
+	var sources map[string]bool
+	if s.config.SourceStates != nil {
+		sources = s.config.SourceStates()
+	}
+
+	s.mutex.RLock()
+	rates := make(map[string]bitrate, len(s.rates))
+	for id, rate := range s.rates {
+		rates[id] = rate
+	}
+	s.mutex.RUnlock()
+
+	e.gauge(namespace+"streams", "Registered streams.", float64(len(streams)))
+
+	ids := make([]string, 0, len(streams))
+	for id := range streams {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	e.declare(namespace+"stream_info", typeGauge, "Stream information (codec is empty until the first media is received).")
+	e.declare(namespace+"stream_packets_received_total", typeCounter, "RTP packets received from the stream source.")
+	e.declare(namespace+"stream_packets_sent_total", typeCounter, "RTP packets delivered to stream subscribers.")
+	e.declare(namespace+"stream_bytes_received_total", typeCounter, "RTP payload bytes received from the stream source.")
+	e.declare(namespace+"stream_bytes_sent_total", typeCounter, "RTP payload bytes delivered to stream subscribers.")
+	e.declare(namespace+"stream_subscribers", typeGauge, "Current stream subscribers (WebRTC peers, RTSP readers, HLS, recorder, ...).")
+	e.declare(namespace+"stream_buffer_drops_total", typeCounter, "Packets dropped because the stream receive buffer was full.")
+	e.declare(namespace+"stream_subscriber_drops_total", typeCounter, "Packets dropped because a subscriber buffer was full (sum over subscribers).")
+	e.declare(namespace+"stream_source_reconnects_total", typeCounter, "Times the stream source disconnected and was retried.")
+
+	for _, id := range ids {
+		stream := streams[id]
+		packetsReceived, packetsSent, bytesReceived, bytesSent := stream.GetStats()
+		bufferDropped, subscriberDropped := stream.GetDropStats()
+
+		e.gauge(namespace+"stream_info", "", 1, "stream_id", id, "codec", stream.GetVideoCodec())
+		e.counter(namespace+"stream_packets_received_total", "", float64(packetsReceived), "stream_id", id)
+		e.counter(namespace+"stream_packets_sent_total", "", float64(packetsSent), "stream_id", id)
+		e.counter(namespace+"stream_bytes_received_total", "", float64(bytesReceived), "stream_id", id)
+		e.counter(namespace+"stream_bytes_sent_total", "", float64(bytesSent), "stream_id", id)
+		e.gauge(namespace+"stream_subscribers", "", float64(stream.GetSubscriberCount()), "stream_id", id)
+		e.counter(namespace+"stream_buffer_drops_total", "", float64(bufferDropped), "stream_id", id)
+		e.counter(namespace+"stream_subscriber_drops_total", "", float64(subscriberDropped), "stream_id", id)
+		e.counter(namespace+"stream_source_reconnects_total", "", float64(stream.GetSourceReconnects()), "stream_id", id)
+
+		if rate, exists := rates[id]; exists {
+			e.gauge(namespace+"stream_receive_bitrate_bps", "Stream receive bitrate over the last collection interval.", rate.received, "stream_id", id)
+			e.gauge(namespace+"stream_send_bitrate_bps", "Stream send bitrate over the last collection interval (all subscribers).", rate.sent, "stream_id", id)
+		}
+
+		if connected, exists := sources[id]; exists {
+			e.gauge(namespace+"stream_source_connected",
+				"Whether the stream's pull source is connected (only streams with a running pull source).",
+				boolValue(connected), "stream_id", id)
+		}
+	}
+}
+
+// writePeerMetrics는 WebRTC 피어별 메트릭을 추가합니다 (peer_id, stream_id 레이블)
+func (s *Server) writePeerMetrics(e *exposition) {
+	if s.config.WebRTCManager == nil {
+		return
+	}
+
+	peers := s.config.WebRTCManager.ListPeers()
+	sort.Slice(peers, func(i, j int) bool { return peers[i].GetID() < peers[j].GetID() })
+
+	e.gauge(namespace+"webrtc_peers", "WebRTC peers.", float64(len(peers)))
+	e.declare(namespace+"webrtc_peer_connected", typeGauge, "Whether the WebRTC peer connection is established.")
+	e.declare(namespace+"webrtc_peer_packets_sent_total", typeCounter, "RTP packets sent to the WebRTC peer.")
+	e.declare(namespace+"webrtc_peer_bytes_sent_total", typeCounter, "RTP payload bytes sent to the WebRTC peer.")
+
+	for _, peer := range peers {
+		id, streamID := peer.GetID(), peer.GetStreamID()
+		packetsSent, bytesSent := peer.GetStats()
+
+		e.gauge(namespace+"webrtc_peer_connected", "", boolValue(peer.IsConnected()), "peer_id", id, "stream_id", streamID)
+		e.counter(namespace+"webrtc_peer_packets_sent_total", "", float64(packetsSent), "peer_id", id, "stream_id", streamID)
+		e.counter(namespace+"webrtc_peer_bytes_sent_total", "", float64(bytesSent), "peer_id", id, "stream_id", streamID)
+	}
+}
+
+// hlsMetrics는 HLS muxer 통계 키와 메트릭의 대응입니다 (hls.Manager.GetStats)
+var hlsMetrics = []struct {
+	key  string
+	name string
+	typ  string
+	help string
+}{
+	{"packets_received", "hls_muxer_packets_received_total", typeCounter, "RTP packets received by the HLS muxer."},
+	{"bytes_written", "hls_muxer_bytes_written_total", typeCounter, "Bytes written to HLS segments."},
+	{"segments_created", "hls_muxer_segments_created_total", typeCounter, "HLS segments created."},
+	{"current_bitrate", "hls_muxer_bitrate_bps", typeGauge, "Current HLS muxer bitrate."},
+	{"errors", "hls_muxer_errors_total", typeCounter, "HLS muxer errors."},
+}
+
+// writeHLSMetrics는 HLS muxer별 메트릭을 추가합니다 (stream_id 레이블)
+func (s *Server) writeHLSMetrics(e *exposition) {
+	hlsManager := s.hlsManager.Load()
+	if hlsManager == nil || !hlsManager.IsEnabled() {
+		return
+	}
+
+	ids := hlsManager.GetAllStreams()
+	sort.Strings(ids)
+
+	e.gauge(namespace+"hls_muxers", "Active HLS muxers.", float64(len(ids)))
+	for _, m := range hlsMetrics {
+		e.declare(namespace+m.name, m.typ, m.help)
+	}
+
+	for _, id := range ids {
+		stats, err := hlsManager.GetStats(id)
+		if err != nil {
+			continue
+		}
+
+		for _, m := range hlsMetrics {
+			value, ok := toFloat(stats[m.key])
+			if !ok {
+				continue
+			}
+			if m.typ == typeCounter {
+				e.counter(namespace+m.name, "", value, "stream_id", id)
+			} else {
+				e.gauge(namespace+m.name, "", value, "stream_id", id)
+			}
+		}
+	}
+}
+
+// writeRuntimeMetrics는 Go 런타임/프로세스 메트릭을 추가합니다 (Prometheus Go 클라이언트와 같은 이름)
+func writeRuntimeMetrics(e *exposition) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	e.gauge("go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()))
+	e.gauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(mem.Alloc))
+	e.gauge("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", float64(mem.HeapInuse))
+	e.gauge("go_memstats_sys_bytes", "Number of bytes obtained from system.", float64(mem.Sys))
+	e.counter("go_memstats_mallocs_total", "Total number of mallocs.", float64(mem.Mallocs))
+	e.counter("go_gc_cycles_total", "Number of completed GC cycles.", float64(mem.NumGC))
+	e.counter("go_gc_pause_seconds_total", "Total GC stop-the-world pause time.", float64(mem.PauseTotalNs)/1e9)
+	e.gauge("go_info", "Information about the Go environment.", 1, "version", runtime.Version())
+
+	e.gauge("process_start_time_seconds", "Start time of the process since unix epoch in seconds.", float64(processStart.UnixNano())/1e9)
+
+	// 열린 파일 디스크립터 수 (/proc가 없는 OS에서는 생략)
+	if fds, err := os.ReadDir("/proc/self/fd"); err == nil {
+		e.gauge("process_open_fds", "Number of open file descriptors.", float64(len(fds)))
+	}
+}
+
+// processStart는 프로세스 시작 시각 (패키지 초기화 시점)
+var processStart = time.Now()
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// toFloat는 통계 맵의 숫자 값을 float64로 변환합니다
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,456 @@
+// Package onvif는 ONVIF 카메라 검색(WS-Discovery)과 장치/미디어 서비스 호출을 제공합니다
+// 카메라를 하나씩 RTSP URL로 등록하는 대신 프로필(메인/서브 스트림)을 조회해 가져올 수 있습니다
+package onvif
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDevicePath는 ONVIF 장치 서비스의 표준 경로입니다
+const DefaultDevicePath = "/onvif/device_service"
+
+// ClientConfig는 ONVIF 장치 클라이언트 설정
+type ClientConfig struct {
+	Address  string // 장치 서비스 URL 또는 host[:port]
+	Username string
+	Password string
+	Timeout  time.Duration // 요청당 타임아웃
+}
+
+// Client는 하나의 ONVIF 장치에 대한 클라이언트입니다
+type Client struct {
+	config     ClientConfig
+	deviceURL  string
+	httpClient *http.Client
+
+	mutex       sync.Mutex
+	clockOffset time.Duration // 카메라 시각 - 로컬 시각 (PasswordDigest 생성 시 보정)
+	services    *Services
+}
+
+// Services는 장치가 알려준 서비스 엔드포인트입니다
+type Services struct {
+	Media  string `json:"media"`
+	PTZ    string `json:"ptz,omitempty"`
+	Events string `json:"events,omitempty"`
+}
+
+// DeviceInfo는 GetDeviceInformation 응답입니다
+type DeviceInfo struct {
+	Manufacturer    string `json:"manufacturer" xml:"Manufacturer"`
+	Model           string `json:"model" xml:"Model"`
+	FirmwareVersion string `json:"firmware_version" xml:"FirmwareVersion"`
+	SerialNumber    string `json:"serial_number" xml:"SerialNumber"`
+	HardwareID      string `json:"hardware_id" xml:"HardwareId"`
+}
+
+// Profile은 GetProfiles 응답의 미디어 프로필입니다
+type Profile struct {
+	Token string `xml:"token,attr"`
+	Name  string `xml:"Name"`
+
+	VideoEncoder *struct {
+		Encoding   string `xml:"Encoding"`
+		Resolution struct {
+			Width  int `xml:"Width"`
+			Height int `xml:"Height"`
+		} `xml:"Resolution"`
+		RateControl struct {
+			FrameRateLimit int `xml:"FrameRateLimit"`
+			BitrateLimit   int `xml:"BitrateLimit"`
+		} `xml:"RateControl"`
+	} `xml:"VideoEncoderConfiguration"`
+
+	PTZ *struct {
+		Token string `xml:"token,attr"`
+	} `xml:"PTZConfiguration"`
+}
+
+// StreamProfile은 가져오기에 사용하는 스트림 정보입니다
+type StreamProfile struct {
+	Kind     string `json:"kind"` // main, sub, profile3...
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+	Encoding string `json:"encoding,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	FPS      int    `json:"fps,omitempty"`
+	Bitrate  int    `json:"bitrate,omitempty"` // kbps
+	PTZ      bool   `json:"ptz"`
+	URI      string `json:"uri"` // 인증 정보는 포함하지 않음
+}
+
+// NormalizeAddress는 host[:port] 또는 URL을 장치 서비스 URL로 변환합니다
+func NormalizeAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", errors.New("empty ONVIF address")
+	}
+
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid ONVIF address: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid ONVIF address scheme: %s", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid ONVIF address: missing host")
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = DefaultDevicePath
+	}
+	u.User = nil
+
+	return u.String(), nil
+}
+
+// AddressFromSource는 스트림 source URL의 호스트로 기본 장치 서비스 URL을 만듭니다
+// ONVIF로 가져오지 않은 스트림에서 PTZ 등을 사용할 때의 기본값입니다
+func AddressFromSource(source string) (string, error) {
+	host := hostOf(source)
+	if host == "" {
+		return "", fmt.Errorf("cannot derive ONVIF address from source")
+	}
+	return NormalizeAddress(net.JoinHostPort(host, "80"))
+}
+
+// NewClient는 새로운 ONVIF 장치 클라이언트를 생성합니다
+func NewClient(config ClientConfig) (*Client, error) {
+	deviceURL, err := NormalizeAddress(config.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return &Client{
+		config:     config,
+		deviceURL:  deviceURL,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Address는 장치 서비스 URL을 반환합니다
+func (c *Client) Address() string {
+	return c.deviceURL
+}
+
+// token은 현재 시각 기준 WS-Security 토큰을 생성합니다 (인증 정보가 없으면 nil)
+func (c *Client) token() (*usernameToken, error) {
+	if c.config.Username == "" {
+		return nil, nil
+	}
+
+	c.mutex.Lock()
+	offset := c.clockOffset
+	c.mutex.Unlock()
+
+	return newUsernameToken(c.config.Username, c.config.Password, time.Now().Add(offset))
+}
+
+// Call은 인증 헤더를 포함해 SOAP 요청을 보냅니다
+func (c *Client) Call(ctx context.Context, endpoint string, request, response interface{}) error {
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+	return call(ctx, c.httpClient, endpoint, request, token, nil, response)
+}
+
+// callAction은 WS-Addressing Action/To 헤더를 포함해 SOAP 요청을 보냅니다
+func (c *Client) callAction(ctx context.Context, endpoint, action string, request, response interface{}) error {
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+	return call(ctx, c.httpClient, endpoint, request, token, &addressing{Action: action, To: endpoint}, response)
+}
+
+// Connect는 카메라 시각을 동기화하고 서비스 엔드포인트를 조회합니다
+// 다른 메서드는 필요할 때 Connect를 자동으로 호출합니다
+func (c *Client) Connect(ctx context.Context) (*Services, error) {
+	c.mutex.Lock()
+	services := c.services
+	c.mutex.Unlock()
+	if services != nil {
+		return services, nil
+	}
+
+	// 시각 동기화 실패는 무시 (GetSystemDateAndTime 미지원 장비도 있음)
+	c.syncClock(ctx)
+
+	var request struct {
+		XMLName  xml.Name `xml:"http://www.onvif.org/ver10/device/wsdl GetCapabilities"`
+		Category string   `xml:"Category"`
+	}
+	request.Category = "All"
+
+	var response struct {
+		Capabilities struct {
+			Media struct {
+				XAddr string `xml:"XAddr"`
+			} `xml:"Media"`
+			PTZ struct {
+				XAddr string `xml:"XAddr"`
+			} `xml:"PTZ"`
+			Events struct {
+				XAddr string `xml:"XAddr"`
+			} `xml:"Events"`
+		} `xml:"Capabilities"`
+	}
+
+	if err := c.Call(ctx, c.deviceURL, &request, &response); err != nil {
+		return nil, fmt.Errorf("GetCapabilities failed: %w", err)
+	}
+
+	services = &Services{
+		Media:  c.fixXAddr(response.Capabilities.Media.XAddr),
+		PTZ:    c.fixXAddr(response.Capabilities.PTZ.XAddr),
+		Events: c.fixXAddr(response.Capabilities.Events.XAddr),
+	}
+	if services.Media == "" {
+		// 미디어 서비스를 알려주지 않는 장비는 장치 서비스 URL로 시도
+		services.Media = c.deviceURL
+	}
+
+	c.mutex.Lock()
+	c.services = services
+	c.mutex.Unlock()
+
+	return services, nil
+}
+
+// syncClock은 카메라 UTC 시각과 로컬 시각의 차이를 기록합니다
+func (c *Client) syncClock(ctx context.Context) {
+	request := struct {
+		XMLName xml.Name `xml:"http://www.onvif.org/ver10/device/wsdl GetSystemDateAndTime"`
+	}{}
+
+	var response struct {
+		SystemDateAndTime struct {
+			UTCDateTime struct {
+				Time struct {
+					Hour   int `xml:"Hour"`
+					Minute int `xml:"Minute"`
+					Second int `xml:"Second"`
+				} `xml:"Time"`
+				Date struct {
+					Year  int `xml:"Year"`
+					Month int `xml:"Month"`
+					Day   int `xml:"Day"`
+				} `xml:"Date"`
+			} `xml:"UTCDateTime"`
+		} `xml:"SystemDateAndTime"`
+	}
+
+	// 시각 조회는 인증 없이 허용됨 (ONVIF Core 규격)
+	if err := call(ctx, c.httpClient, c.deviceURL, &request, nil, nil, &response); err != nil {
+		return
+	}
+
+	utc := response.SystemDateAndTime.UTCDateTime
+	if utc.Date.Year == 0 {
+		return
+	}
+	deviceTime := time.Date(utc.Date.Year, time.Month(utc.Date.Month), utc.Date.Day,
+		utc.Time.Hour, utc.Time.Minute, utc.Time.Second, 0, time.UTC)
+
+	c.mutex.Lock()
+	c.clockOffset = time.Until(deviceTime)
+	c.mutex.Unlock()
+}
+
+// fixXAddr은 서비스 주소의 호스트를 장치 주소의 호스트로 맞춥니다
+// NAT/포트포워딩 환경에서 카메라가 내부 IP를 알려주는 경우 대응
+func (c *Client) fixXAddr(xaddr string) string {
+	xaddr = strings.TrimSpace(xaddr)
+	if xaddr == "" {
+		return ""
+	}
+
+	u, err := url.Parse(xaddr)
+	if err != nil || u.Host == "" {
+		return xaddr
+	}
+	device, err := url.Parse(c.deviceURL)
+	if err != nil {
+		return xaddr
+	}
+
+	u.Scheme = device.Scheme
+	u.Host = device.Host
+	return u.String()
+}
+
+// GetDeviceInformation은 제조사/모델 정보를 조회합니다
+func (c *Client) GetDeviceInformation(ctx context.Context) (*DeviceInfo, error) {
+	request := struct {
+		XMLName xml.Name `xml:"http://www.onvif.org/ver10/device/wsdl GetDeviceInformation"`
+	}{}
+
+	var response struct {
+		DeviceInfo
+	}
+	if _, err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.Call(ctx, c.deviceURL, &request, &response); err != nil {
+		return nil, fmt.Errorf("GetDeviceInformation failed: %w", err)
+	}
+
+	info := response.DeviceInfo
+	return &info, nil
+}
+
+// GetProfiles는 미디어 프로필 목록을 조회합니다
+func (c *Client) GetProfiles(ctx context.Context) ([]Profile, error) {
+	services, err := c.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := struct {
+		XMLName xml.Name `xml:"http://www.onvif.org/ver10/media/wsdl GetProfiles"`
+	}{}
+
+	var response struct {
+		Profiles []Profile `xml:"Profiles"`
+	}
+	if err := c.Call(ctx, services.Media, &request, &response); err != nil {
+		return nil, fmt.Errorf("GetProfiles failed: %w", err)
+	}
+
+	return response.Profiles, nil
+}
+
+// GetStreamURI는 프로필의 RTSP(RTP-Unicast) 스트림 URI를 조회합니다
+func (c *Client) GetStreamURI(ctx context.Context, profileToken string) (string, error) {
+	services, err := c.Connect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	type transport struct {
+		Protocol string `xml:"http://www.onvif.org/ver10/schema Protocol"`
+	}
+	type streamSetup struct {
+		Stream    string    `xml:"http://www.onvif.org/ver10/schema Stream"`
+		Transport transport `xml:"http://www.onvif.org/ver10/schema Transport"`
+	}
+	request := struct {
+		XMLName      xml.Name    `xml:"http://www.onvif.org/ver10/media/wsdl GetStreamUri"`
+		StreamSetup  streamSetup `xml:"StreamSetup"`
+		ProfileToken string      `xml:"ProfileToken"`
+	}{
+		StreamSetup:  streamSetup{Stream: "RTP-Unicast", Transport: transport{Protocol: "RTSP"}},
+		ProfileToken: profileToken,
+	}
+
+	var response struct {
+		MediaURI struct {
+			URI string `xml:"Uri"`
+		} `xml:"MediaUri"`
+	}
+	if err := c.Call(ctx, services.Media, &request, &response); err != nil {
+		return "", fmt.Errorf("GetStreamUri failed: %w", err)
+	}
+
+	uri := strings.TrimSpace(response.MediaURI.URI)
+	if uri == "" {
+		return "", fmt.Errorf("GetStreamUri returned empty URI for profile %s", profileToken)
+	}
+	return uri, nil
+}
+
+// GetStreamProfiles는 영상 프로필과 스트림 URI를 조회해 해상도 순으로 main/sub를 붙입니다
+// 가장 높은 해상도가 main, 그 다음이 sub, 나머지는 profileN
+func (c *Client) GetStreamProfiles(ctx context.Context) ([]StreamProfile, error) {
+	profiles, err := c.GetProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []StreamProfile
+	for _, profile := range profiles {
+		// 영상 인코더가 없는 프로필(오디오/메타데이터 전용)은 제외
+		if profile.VideoEncoder == nil {
+			continue
+		}
+
+		uri, err := c.GetStreamURI(ctx, profile.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		streams = append(streams, StreamProfile{
+			Token:    profile.Token,
+			Name:     profile.Name,
+			Encoding: profile.VideoEncoder.Encoding,
+			Width:    profile.VideoEncoder.Resolution.Width,
+			Height:   profile.VideoEncoder.Resolution.Height,
+			FPS:      profile.VideoEncoder.RateControl.FrameRateLimit,
+			Bitrate:  profile.VideoEncoder.RateControl.BitrateLimit,
+			PTZ:      profile.PTZ != nil,
+			URI:      stripCredentials(uri),
+		})
+	}
+
+	// 해상도 내림차순 (같으면 장치가 알려준 순서 유지)
+	sort.SliceStable(streams, func(i, j int) bool {
+		return streams[i].Width*streams[i].Height > streams[j].Width*streams[j].Height
+	})
+
+	for i := range streams {
+		switch i {
+		case 0:
+			streams[i].Kind = "main"
+		case 1:
+			streams[i].Kind = "sub"
+		default:
+			streams[i].Kind = "profile" + strconv.Itoa(i+1)
+		}
+	}
+
+	return streams, nil
+}
+
+// stripCredentials는 카메라가 URI에 넣어준 인증 정보를 제거합니다
+func stripCredentials(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.User == nil {
+		return uri
+	}
+	u.User = nil
+	return u.String()
+}
+
+// hostOf는 URL의 호스트(포트 제외)를 반환합니다
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return u.Host
+	}
+	return host
+}
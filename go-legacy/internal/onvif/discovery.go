@@ -0,0 +1,213 @@
+package onvif
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+// WS-Discovery 멀티캐스트 주소
+const discoveryAddress = "239.255.255.250:3702"
+
+// 수신 버퍼 크기 (ProbeMatches는 보통 수 KB)
+const discoveryBufferSize = 64 * 1024
+
+// DiscoveredDevice는 WS-Discovery로 찾은 장치입니다
+type DiscoveredDevice struct {
+	Endpoint string   `json:"endpoint"` // 장치 고유 주소 (urn:uuid:...)
+	XAddrs   []string `json:"xaddrs"`   // 장치 서비스 URL 목록
+	Name     string   `json:"name,omitempty"`
+	Hardware string   `json:"hardware,omitempty"`
+	Location string   `json:"location,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// probeMatches는 ProbeMatches 응답입니다
+type probeMatches struct {
+	Header struct {
+		RelatesTo string `xml:"RelatesTo"`
+	} `xml:"Header"`
+	Body struct {
+		ProbeMatches struct {
+			ProbeMatch []struct {
+				EndpointReference struct {
+					Address string `xml:"Address"`
+				} `xml:"EndpointReference"`
+				Types  string `xml:"Types"`
+				Scopes string `xml:"Scopes"`
+				XAddrs string `xml:"XAddrs"`
+			} `xml:"ProbeMatch"`
+		} `xml:"ProbeMatches"`
+	} `xml:"Body"`
+}
+
+// Discover는 WS-Discovery Probe를 멀티캐스트로 보내고 timeout 동안 응답을 수집합니다
+// iface가 비어있으면 시스템 기본 멀티캐스트 인터페이스를 사용합니다
+func Discover(ctx context.Context, timeout time.Duration, iface string) ([]DiscoveredDevice, error) {
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open discovery socket: %w", err)
+	}
+	defer conn.Close()
+
+	if iface != "" {
+		ifi, err := net.InterfaceByName(iface)
+		if err != nil {
+			return nil, fmt.Errorf("invalid discovery interface %q: %w", iface, err)
+		}
+		if err := ipv4.NewPacketConn(conn).SetMulticastInterface(ifi); err != nil {
+			return nil, fmt.Errorf("failed to set multicast interface: %w", err)
+		}
+	}
+
+	messageID, err := newMessageID()
+	if err != nil {
+		return nil, err
+	}
+
+	dst, err := net.ResolveUDPAddr("udp4", discoveryAddress)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(buildProbe(messageID), dst); err != nil {
+		return nil, fmt.Errorf("failed to send probe: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// ctx 취소 시 즉시 읽기 중단
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	var devices []DiscoveredDevice
+	seen := make(map[string]int)
+	buf := make([]byte, discoveryBufferSize)
+
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return devices, fmt.Errorf("failed to read probe response: %w", err)
+		}
+
+		for _, device := range parseProbeMatches(buf[:n], messageID, from) {
+			key := device.Endpoint
+			if key == "" {
+				key = strings.Join(device.XAddrs, " ")
+			}
+			if i, exists := seen[key]; exists {
+				devices[i] = device
+				continue
+			}
+			seen[key] = len(devices)
+			devices = append(devices, device)
+		}
+	}
+
+	return devices, ctx.Err()
+}
+
+// buildProbe는 NetworkVideoTransmitter 타입의 Probe 메시지를 생성합니다
+func buildProbe(messageID string) []byte {
+	return []byte(xml.Header +
+		`<s:Envelope xmlns:s="` + nsSOAP + `"` +
+		` xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing"` +
+		` xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery"` +
+		` xmlns:dn="http://www.onvif.org/ver10/network/wsdl">` +
+		`<s:Header>` +
+		`<a:MessageID>` + messageID + `</a:MessageID>` +
+		`<a:To s:mustUnderstand="1">urn:schemas-xmlsoap-org:ws:2005:04:discovery</a:To>` +
+		`<a:Action s:mustUnderstand="1">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</a:Action>` +
+		`</s:Header>` +
+		`<s:Body><d:Probe><d:Types>dn:NetworkVideoTransmitter</d:Types></d:Probe></s:Body>` +
+		`</s:Envelope>`)
+}
+
+// parseProbeMatches는 응답을 파싱합니다 (다른 Probe에 대한 응답은 무시)
+func parseProbeMatches(data []byte, messageID string, from *net.UDPAddr) []DiscoveredDevice {
+	var response probeMatches
+	if err := xml.Unmarshal(data, &response); err != nil {
+		return nil
+	}
+	if relatesTo := strings.TrimSpace(response.Header.RelatesTo); relatesTo != "" && relatesTo != messageID {
+		return nil
+	}
+
+	var devices []DiscoveredDevice
+	for _, match := range response.Body.ProbeMatches.ProbeMatch {
+		device := DiscoveredDevice{
+			Endpoint: strings.TrimSpace(match.EndpointReference.Address),
+			XAddrs:   strings.Fields(match.XAddrs),
+			Scopes:   strings.Fields(match.Scopes),
+		}
+
+		// 응답한 주소와 같은 호스트의 XAddr을 우선 (여러 NIC를 가진 장비 대응)
+		for i, xaddr := range device.XAddrs {
+			if from != nil && hostOf(xaddr) == from.IP.String() {
+				device.XAddrs[0], device.XAddrs[i] = device.XAddrs[i], device.XAddrs[0]
+				break
+			}
+		}
+		if len(device.XAddrs) == 0 && from != nil {
+			device.XAddrs = []string{"http://" + from.IP.String() + DefaultDevicePath}
+		}
+
+		for _, scope := range device.Scopes {
+			switch {
+			case strings.HasPrefix(scope, "onvif://www.onvif.org/name/"):
+				device.Name = scopeValue(scope, "onvif://www.onvif.org/name/")
+			case strings.HasPrefix(scope, "onvif://www.onvif.org/hardware/"):
+				device.Hardware = scopeValue(scope, "onvif://www.onvif.org/hardware/")
+			case strings.HasPrefix(scope, "onvif://www.onvif.org/location/"):
+				device.Location = scopeValue(scope, "onvif://www.onvif.org/location/")
+			}
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices
+}
+
+// scopeValue는 스코프 URI의 값 부분을 디코딩합니다
+func scopeValue(scope, prefix string) string {
+	value := strings.TrimPrefix(scope, prefix)
+	if decoded, err := url.PathUnescape(value); err == nil {
+		value = decoded
+	}
+	return value
+}
+
+// newMessageID는 WS-Addressing MessageID(urn:uuid)를 생성합니다
+func newMessageID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate message ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
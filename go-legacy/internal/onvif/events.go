@@ -0,0 +1,183 @@
+package onvif
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 이벤트 서비스 WS-Addressing Action
+const (
+	actionCreatePullPoint = "http://www.onvif.org/ver10/events/wsdl/EventPortType/CreatePullPointSubscriptionRequest"
+	actionPullMessages    = "http://www.onvif.org/ver10/events/wsdl/PullPointSubscription/PullMessagesRequest"
+	actionRenew           = "http://docs.oasis-open.org/wsn/bw-2/SubscriptionManager/RenewRequest"
+	actionUnsubscribe     = "http://docs.oasis-open.org/wsn/bw-2/SubscriptionManager/UnsubscribeRequest"
+)
+
+// ErrEventsNotSupported는 카메라가 이벤트 서비스를 제공하지 않는 경우의 에러입니다
+var ErrEventsNotSupported = errors.New("onvif: events are not supported")
+
+// Notification은 PullPoint로 받은 이벤트 알림입니다
+type Notification struct {
+	Topic     string            // 네임스페이스 접두사를 제외한 토픽 (예: RuleEngine/CellMotionDetector/Motion)
+	Time      time.Time         // 카메라 발생 시각 (없으면 수신 시각)
+	Operation string            // Initialized, Changed, Deleted
+	Source    map[string]string // 발생 위치 (VideoSourceConfigurationToken, Rule 등)
+	Data      map[string]string // 상태 값 (IsMotion, State, LogicalState 등)
+}
+
+// PullPoint는 카메라의 PullPoint 이벤트 구독입니다
+type PullPoint struct {
+	client  *Client
+	address string // 구독 관리 주소 (PullMessages/Renew/Unsubscribe 대상)
+}
+
+// simpleItem은 tt:SimpleItem 요소입니다
+type simpleItem struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:"Value,attr"`
+}
+
+// CreatePullPoint는 PullPoint 구독을 생성합니다
+// termination이 지나기 전에 Renew를 호출하지 않으면 카메라가 구독을 해제합니다
+func (c *Client) CreatePullPoint(ctx context.Context, termination time.Duration) (*PullPoint, error) {
+	services, err := c.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if services.Events == "" {
+		return nil, ErrEventsNotSupported
+	}
+
+	request := struct {
+		XMLName                xml.Name `xml:"http://www.onvif.org/ver10/events/wsdl CreatePullPointSubscription"`
+		InitialTerminationTime string   `xml:"InitialTerminationTime"`
+	}{
+		InitialTerminationTime: xsdDuration(termination),
+	}
+
+	var response struct {
+		SubscriptionReference struct {
+			Address string `xml:"Address"`
+		} `xml:"SubscriptionReference"`
+	}
+	if err := c.callAction(ctx, services.Events, actionCreatePullPoint, &request, &response); err != nil {
+		return nil, fmt.Errorf("CreatePullPointSubscription failed: %w", err)
+	}
+
+	address := c.fixXAddr(response.SubscriptionReference.Address)
+	if address == "" {
+		return nil, fmt.Errorf("CreatePullPointSubscription returned no subscription address")
+	}
+
+	return &PullPoint{client: c, address: address}, nil
+}
+
+// Address는 구독 관리 주소를 반환합니다
+func (p *PullPoint) Address() string {
+	return p.address
+}
+
+// Pull은 알림을 최대 timeout 동안 기다려 가져옵니다 (알림이 없으면 빈 슬라이스)
+// 클라이언트 HTTP 타임아웃은 timeout보다 길어야 합니다
+func (p *PullPoint) Pull(ctx context.Context, timeout time.Duration, limit int) ([]Notification, error) {
+	request := struct {
+		XMLName      xml.Name `xml:"http://www.onvif.org/ver10/events/wsdl PullMessages"`
+		Timeout      string   `xml:"Timeout"`
+		MessageLimit int      `xml:"MessageLimit"`
+	}{
+		Timeout:      xsdDuration(timeout),
+		MessageLimit: limit,
+	}
+
+	var response struct {
+		Messages []struct {
+			Topic   string `xml:"Topic"`
+			Message struct {
+				Message struct {
+					UtcTime           string `xml:"UtcTime,attr"`
+					PropertyOperation string `xml:"PropertyOperation,attr"`
+					Source            struct {
+						Items []simpleItem `xml:"SimpleItem"`
+					} `xml:"Source"`
+					Data struct {
+						Items []simpleItem `xml:"SimpleItem"`
+					} `xml:"Data"`
+				} `xml:"Message"`
+			} `xml:"Message"`
+		} `xml:"NotificationMessage"`
+	}
+	if err := p.client.callAction(ctx, p.address, actionPullMessages, &request, &response); err != nil {
+		return nil, fmt.Errorf("PullMessages failed: %w", err)
+	}
+
+	now := time.Now()
+	notifications := make([]Notification, 0, len(response.Messages))
+	for _, m := range response.Messages {
+		msg := m.Message.Message
+
+		n := Notification{
+			Topic:     topicPath(m.Topic),
+			Time:      now,
+			Operation: msg.PropertyOperation,
+			Source:    itemMap(msg.Source.Items),
+			Data:      itemMap(msg.Data.Items),
+		}
+		if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(msg.UtcTime)); err == nil {
+			n.Time = t
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// Renew는 구독 만료 시간을 연장합니다
+func (p *PullPoint) Renew(ctx context.Context, termination time.Duration) error {
+	request := struct {
+		XMLName         xml.Name `xml:"http://docs.oasis-open.org/wsn/b-2 Renew"`
+		TerminationTime string   `xml:"TerminationTime"`
+	}{
+		TerminationTime: xsdDuration(termination),
+	}
+
+	if err := p.client.callAction(ctx, p.address, actionRenew, &request, nil); err != nil {
+		return fmt.Errorf("Renew failed: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe는 구독을 해제합니다
+func (p *PullPoint) Unsubscribe(ctx context.Context) error {
+	request := struct {
+		XMLName xml.Name `xml:"http://docs.oasis-open.org/wsn/b-2 Unsubscribe"`
+	}{}
+
+	if err := p.client.callAction(ctx, p.address, actionUnsubscribe, &request, nil); err != nil {
+		return fmt.Errorf("Unsubscribe failed: %w", err)
+	}
+	return nil
+}
+
+// topicPath는 "tns1:RuleEngine/CellMotionDetector/Motion"에서 각 단계의 접두사를 제거합니다
+func topicPath(topic string) string {
+	parts := strings.Split(strings.TrimSpace(topic), "/")
+	for i, part := range parts {
+		parts[i] = localName(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// itemMap은 SimpleItem 목록을 이름→값 맵으로 변환합니다
+func itemMap(items []simpleItem) map[string]string {
+	if len(items) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(items))
+	for _, item := range items {
+		m[item.Name] = item.Value
+	}
+	return m
+}
@@ -0,0 +1,215 @@
+package onvif
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 장치 조회 동시 실행 수
+const inspectConcurrency = 8
+
+// Config는 ONVIF 관리자 설정
+type Config struct {
+	DiscoveryTimeout time.Duration // WS-Discovery 응답 대기 시간
+	RequestTimeout   time.Duration // SOAP 요청당 타임아웃
+	Interface        string        // 멀티캐스트 인터페이스 (비어있으면 기본)
+	Logger           *zap.Logger
+}
+
+// Manager는 ONVIF 검색과 장치 클라이언트 생성을 담당합니다
+type Manager struct {
+	config Config
+	logger *zap.Logger
+
+	// PTZ 제어용 클라이언트 캐시 (서비스 조회를 매 요청마다 반복하지 않도록)
+	clients map[string]*Client
+	mutex   sync.Mutex
+}
+
+// PTZTarget은 PTZ 제어 대상 카메라입니다
+type PTZTarget struct {
+	Address  string // 장치 서비스 URL
+	Username string
+	Password string
+	Profile  string // 비어있으면 PTZ 설정이 있는 첫 번째 프로필
+}
+
+// DiscoverOptions는 검색 요청 옵션입니다
+type DiscoverOptions struct {
+	Username string
+	Password string
+	// 지정하면 멀티캐스트 검색 대신 해당 주소만 조회 (다른 서브넷의 카메라 등)
+	Addresses []string
+	// 0이면 설정값 사용
+	Timeout time.Duration
+}
+
+// Device는 검색/조회 결과입니다
+type Device struct {
+	Address  string          `json:"address"` // 장치 서비스 URL
+	Endpoint string          `json:"endpoint,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Hardware string          `json:"hardware,omitempty"`
+	Location string          `json:"location,omitempty"`
+	Info     *DeviceInfo     `json:"info,omitempty"`
+	Streams  []StreamProfile `json:"streams,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// NewManager는 새로운 ONVIF 관리자를 생성합니다
+func NewManager(config Config) *Manager {
+	if config.DiscoveryTimeout <= 0 {
+		config.DiscoveryTimeout = 3 * time.Second
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = 10 * time.Second
+	}
+
+	return &Manager{
+		config:  config,
+		logger:  config.Logger,
+		clients: make(map[string]*Client),
+	}
+}
+
+// NewClient는 설정된 타임아웃으로 장치 클라이언트를 생성합니다
+func (m *Manager) NewClient(address, username, password string) (*Client, error) {
+	return NewClient(ClientConfig{
+		Address:  address,
+		Username: username,
+		Password: password,
+		Timeout:  m.config.RequestTimeout,
+	})
+}
+
+// Discover는 장치를 찾고 각 장치의 정보와 스트림 프로필을 조회합니다
+// 장치별 조회 실패는 Device.Error에 기록되고 전체 결과는 계속 반환됩니다
+func (m *Manager) Discover(ctx context.Context, opts DiscoverOptions) ([]Device, error) {
+	var devices []Device
+
+	if len(opts.Addresses) > 0 {
+		for _, address := range opts.Addresses {
+			device := Device{Address: address}
+			if normalized, err := NormalizeAddress(address); err != nil {
+				device.Error = err.Error()
+			} else {
+				device.Address = normalized
+			}
+			devices = append(devices, device)
+		}
+	} else {
+		timeout := opts.Timeout
+		if timeout <= 0 {
+			timeout = m.config.DiscoveryTimeout
+		}
+
+		found, err := Discover(ctx, timeout, m.config.Interface)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range found {
+			devices = append(devices, Device{
+				Address:  d.XAddrs[0],
+				Endpoint: d.Endpoint,
+				Name:     d.Name,
+				Hardware: d.Hardware,
+				Location: d.Location,
+			})
+		}
+
+		m.logger.Info("ONVIF discovery completed",
+			zap.Int("devices", len(devices)),
+			zap.Duration("timeout", timeout),
+		)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, inspectConcurrency)
+
+	for i := range devices {
+		if devices[i].Error != "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(device *Device) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			m.inspect(ctx, device, opts.Username, opts.Password)
+		}(&devices[i])
+	}
+	wg.Wait()
+
+	return devices, nil
+}
+
+// inspect는 장치 정보와 스트림 프로필을 조회해 device에 채웁니다
+func (m *Manager) inspect(ctx context.Context, device *Device, username, password string) {
+	client, err := m.NewClient(device.Address, username, password)
+	if err != nil {
+		device.Error = err.Error()
+		return
+	}
+
+	info, err := client.GetDeviceInformation(ctx)
+	if err != nil {
+		device.Error = err.Error()
+		m.logger.Warn("ONVIF device inspection failed",
+			zap.String("address", device.Address),
+			zap.Error(err),
+		)
+		return
+	}
+	device.Info = info
+
+	streams, err := client.GetStreamProfiles(ctx)
+	if err != nil {
+		device.Error = err.Error()
+		m.logger.Warn("ONVIF profile query failed",
+			zap.String("address", device.Address),
+			zap.Error(err),
+		)
+		return
+	}
+	device.Streams = streams
+}
+
+// PTZ는 카메라에 PTZ 명령을 보냅니다
+func (m *Manager) PTZ(ctx context.Context, target PTZTarget, cmd PTZCommand) (*PTZResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	key := target.Address + "\x00" + target.Username + "\x00" + target.Password
+
+	m.mutex.Lock()
+	client, exists := m.clients[key]
+	m.mutex.Unlock()
+
+	if !exists {
+		var err error
+		if client, err = m.NewClient(target.Address, target.Username, target.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := client.ExecutePTZ(ctx, target.Profile, cmd)
+
+	m.mutex.Lock()
+	if err != nil {
+		// 카메라 재시작/설정 변경 등으로 서비스 주소가 바뀌었을 수 있으므로 다음 요청에서 다시 조회
+		delete(m.clients, key)
+	} else {
+		m.clients[key] = client
+	}
+	m.mutex.Unlock()
+
+	return result, err
+}
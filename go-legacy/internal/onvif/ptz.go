@@ -0,0 +1,379 @@
+package onvif
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PTZ 명령 종류
+const (
+	PTZContinuous = "continuous"  // 속도 지정 연속 이동 (stop 또는 timeout까지)
+	PTZStop       = "stop"        // 이동 정지
+	PTZAbsolute   = "absolute"    // 절대 위치 이동
+	PTZRelative   = "relative"    // 현재 위치 기준 상대 이동
+	PTZGotoPreset = "goto_preset" // 프리셋 위치로 이동
+	PTZSetPreset  = "set_preset"  // 현재 위치를 프리셋으로 저장
+	PTZPresets    = "presets"     // 프리셋 목록 조회
+)
+
+// 연속 이동 최대 시간 (클라이언트가 stop을 보내지 못해도 카메라가 멈추도록)
+const maxContinuousTimeout = 60 * time.Second
+
+var (
+	// ErrPTZNotSupported는 장치가 PTZ 서비스를 제공하지 않는 경우의 에러입니다
+	ErrPTZNotSupported = errors.New("camera does not support PTZ")
+
+	// ErrInvalidPTZCommand는 PTZ 명령 값이 잘못된 경우의 에러입니다
+	ErrInvalidPTZCommand = errors.New("invalid PTZ command")
+)
+
+// PTZCommand는 PTZ 제어 요청입니다 (HTTP API와 시그널링 WebSocket 공용)
+// pan/tilt/zoom은 ONVIF 일반 좌표계 기준: continuous/relative는 -1~1, absolute는 pan/tilt -1~1, zoom 0~1
+type PTZCommand struct {
+	Action  string   `json:"action"`
+	Pan     *float64 `json:"pan,omitempty"`
+	Tilt    *float64 `json:"tilt,omitempty"`
+	Zoom    *float64 `json:"zoom,omitempty"`
+	Speed   *float64 `json:"speed,omitempty"`   // absolute/relative/goto_preset 이동 속도 (0~1)
+	Timeout int      `json:"timeout,omitempty"` // continuous 자동 정지 시간 (ms, 0이면 카메라 기본값)
+	Preset  string   `json:"preset,omitempty"`  // goto_preset/set_preset 프리셋 토큰
+	Name    string   `json:"name,omitempty"`    // set_preset 프리셋 이름
+}
+
+// PTZResult는 PTZ 명령 결과입니다
+type PTZResult struct {
+	Action  string   `json:"action"`
+	Preset  string   `json:"preset,omitempty"`  // set_preset으로 저장된 프리셋 토큰
+	Presets []Preset `json:"presets,omitempty"` // presets 조회 결과
+}
+
+// Preset은 PTZ 프리셋입니다
+type Preset struct {
+	Token string   `json:"token"`
+	Name  string   `json:"name"`
+	Pan   *float64 `json:"pan,omitempty"`
+	Tilt  *float64 `json:"tilt,omitempty"`
+	Zoom  *float64 `json:"zoom,omitempty"`
+}
+
+// Validate는 명령 종류별 필수 값과 범위를 확인합니다
+func (cmd *PTZCommand) Validate() error {
+	if err := cmd.validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPTZCommand, err)
+	}
+	return nil
+}
+
+func (cmd *PTZCommand) validate() error {
+	inRange := func(name string, v *float64, min, max float64) error {
+		if v != nil && (*v < min || *v > max) {
+			return fmt.Errorf("%s must be between %g and %g", name, min, max)
+		}
+		return nil
+	}
+
+	switch cmd.Action {
+	case PTZContinuous, PTZRelative, PTZAbsolute:
+		if cmd.Pan == nil && cmd.Tilt == nil && cmd.Zoom == nil {
+			return fmt.Errorf("%s requires pan, tilt or zoom", cmd.Action)
+		}
+		zoomMin := -1.0
+		if cmd.Action == PTZAbsolute {
+			zoomMin = 0
+		}
+		for _, err := range []error{
+			inRange("pan", cmd.Pan, -1, 1),
+			inRange("tilt", cmd.Tilt, -1, 1),
+			inRange("zoom", cmd.Zoom, zoomMin, 1),
+		} {
+			if err != nil {
+				return err
+			}
+		}
+		if cmd.Timeout < 0 || time.Duration(cmd.Timeout)*time.Millisecond > maxContinuousTimeout {
+			return fmt.Errorf("timeout must be 0-%d ms", maxContinuousTimeout.Milliseconds())
+		}
+	case PTZGotoPreset:
+		if cmd.Preset == "" {
+			return errors.New("goto_preset requires preset")
+		}
+	case PTZSetPreset:
+		if cmd.Preset == "" && cmd.Name == "" {
+			return errors.New("set_preset requires name or preset")
+		}
+	case PTZStop, PTZPresets:
+	case "":
+		return errors.New("action is required")
+	default:
+		return fmt.Errorf("unknown PTZ action: %s", cmd.Action)
+	}
+
+	return inRange("speed", cmd.Speed, 0, 1)
+}
+
+// String은 로그/감사 기록용 요약입니다 (예: "continuous pan=0.5 tilt=0")
+func (cmd PTZCommand) String() string {
+	var b strings.Builder
+	b.WriteString(cmd.Action)
+	for _, v := range []struct {
+		name  string
+		value *float64
+	}{
+		{"pan", cmd.Pan},
+		{"tilt", cmd.Tilt},
+		{"zoom", cmd.Zoom},
+		{"speed", cmd.Speed},
+	} {
+		if v.value != nil {
+			b.WriteString(" " + v.name + "=" + strconv.FormatFloat(*v.value, 'g', -1, 64))
+		}
+	}
+	if cmd.Preset != "" {
+		b.WriteString(" preset=" + cmd.Preset)
+	}
+	if cmd.Name != "" {
+		b.WriteString(" name=" + cmd.Name)
+	}
+	return b.String()
+}
+
+// ptzVector는 tt:PTZVector/PTZSpeed 입니다
+type ptzVector struct {
+	PanTilt *vector2D `xml:"http://www.onvif.org/ver10/schema PanTilt,omitempty"`
+	Zoom    *vector1D `xml:"http://www.onvif.org/ver10/schema Zoom,omitempty"`
+}
+
+type vector2D struct {
+	X float64 `xml:"x,attr"`
+	Y float64 `xml:"y,attr"`
+}
+
+type vector1D struct {
+	X float64 `xml:"x,attr"`
+}
+
+// newPTZVector는 지정된 축만 포함한 벡터를 만듭니다 (pan/tilt 중 하나만 지정되면 나머지는 0)
+func newPTZVector(pan, tilt, zoom *float64) *ptzVector {
+	v := &ptzVector{}
+	if pan != nil || tilt != nil {
+		v.PanTilt = &vector2D{}
+		if pan != nil {
+			v.PanTilt.X = *pan
+		}
+		if tilt != nil {
+			v.PanTilt.Y = *tilt
+		}
+	}
+	if zoom != nil {
+		v.Zoom = &vector1D{X: *zoom}
+	}
+	return v
+}
+
+// newPTZSpeed는 이동 속도를 벡터로 변환합니다 (nil이면 카메라 기본 속도)
+func newPTZSpeed(speed *float64) *ptzVector {
+	if speed == nil {
+		return nil
+	}
+	return &ptzVector{
+		PanTilt: &vector2D{X: *speed, Y: *speed},
+		Zoom:    &vector1D{X: *speed},
+	}
+}
+
+// ptzService는 PTZ 서비스 엔드포인트를 반환합니다
+func (c *Client) ptzService(ctx context.Context) (string, error) {
+	services, err := c.Connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	if services.PTZ == "" {
+		return "", ErrPTZNotSupported
+	}
+	return services.PTZ, nil
+}
+
+// ExecutePTZ는 프로필에 대해 PTZ 명령을 실행합니다
+// profileToken이 비어있으면 PTZ 설정이 있는 첫 번째 프로필을 사용합니다
+func (c *Client) ExecutePTZ(ctx context.Context, profileToken string, cmd PTZCommand) (*PTZResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := c.ptzService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if profileToken == "" {
+		if profileToken, err = c.ptzProfile(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &PTZResult{Action: cmd.Action}
+
+	switch cmd.Action {
+	case PTZContinuous:
+		request := struct {
+			XMLName      xml.Name   `xml:"http://www.onvif.org/ver20/ptz/wsdl ContinuousMove"`
+			ProfileToken string     `xml:"ProfileToken"`
+			Velocity     *ptzVector `xml:"Velocity"`
+			Timeout      string     `xml:"Timeout,omitempty"`
+		}{
+			ProfileToken: profileToken,
+			Velocity:     newPTZVector(cmd.Pan, cmd.Tilt, cmd.Zoom),
+		}
+		if cmd.Timeout > 0 {
+			request.Timeout = xsdDuration(time.Duration(cmd.Timeout) * time.Millisecond)
+		}
+		err = c.Call(ctx, endpoint, &request, nil)
+
+	case PTZStop:
+		request := struct {
+			XMLName      xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl Stop"`
+			ProfileToken string   `xml:"ProfileToken"`
+			PanTilt      bool     `xml:"PanTilt"`
+			Zoom         bool     `xml:"Zoom"`
+		}{
+			ProfileToken: profileToken,
+			PanTilt:      true,
+			Zoom:         true,
+		}
+		err = c.Call(ctx, endpoint, &request, nil)
+
+	case PTZAbsolute:
+		request := struct {
+			XMLName      xml.Name   `xml:"http://www.onvif.org/ver20/ptz/wsdl AbsoluteMove"`
+			ProfileToken string     `xml:"ProfileToken"`
+			Position     *ptzVector `xml:"Position"`
+			Speed        *ptzVector `xml:"Speed,omitempty"`
+		}{
+			ProfileToken: profileToken,
+			Position:     newPTZVector(cmd.Pan, cmd.Tilt, cmd.Zoom),
+			Speed:        newPTZSpeed(cmd.Speed),
+		}
+		err = c.Call(ctx, endpoint, &request, nil)
+
+	case PTZRelative:
+		request := struct {
+			XMLName      xml.Name   `xml:"http://www.onvif.org/ver20/ptz/wsdl RelativeMove"`
+			ProfileToken string     `xml:"ProfileToken"`
+			Translation  *ptzVector `xml:"Translation"`
+			Speed        *ptzVector `xml:"Speed,omitempty"`
+		}{
+			ProfileToken: profileToken,
+			Translation:  newPTZVector(cmd.Pan, cmd.Tilt, cmd.Zoom),
+			Speed:        newPTZSpeed(cmd.Speed),
+		}
+		err = c.Call(ctx, endpoint, &request, nil)
+
+	case PTZGotoPreset:
+		request := struct {
+			XMLName      xml.Name   `xml:"http://www.onvif.org/ver20/ptz/wsdl GotoPreset"`
+			ProfileToken string     `xml:"ProfileToken"`
+			PresetToken  string     `xml:"PresetToken"`
+			Speed        *ptzVector `xml:"Speed,omitempty"`
+		}{
+			ProfileToken: profileToken,
+			PresetToken:  cmd.Preset,
+			Speed:        newPTZSpeed(cmd.Speed),
+		}
+		err = c.Call(ctx, endpoint, &request, nil)
+
+	case PTZSetPreset:
+		request := struct {
+			XMLName      xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl SetPreset"`
+			ProfileToken string   `xml:"ProfileToken"`
+			PresetName   string   `xml:"PresetName,omitempty"`
+			PresetToken  string   `xml:"PresetToken,omitempty"` // 지정하면 기존 프리셋 덮어쓰기
+		}{
+			ProfileToken: profileToken,
+			PresetName:   cmd.Name,
+			PresetToken:  cmd.Preset,
+		}
+		var response struct {
+			PresetToken string `xml:"PresetToken"`
+		}
+		err = c.Call(ctx, endpoint, &request, &response)
+		result.Preset = response.PresetToken
+
+	case PTZPresets:
+		result.Presets, err = c.getPresets(ctx, endpoint, profileToken)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("PTZ %s failed: %w", cmd.Action, err)
+	}
+	return result, nil
+}
+
+// getPresets는 프리셋 목록을 조회합니다
+func (c *Client) getPresets(ctx context.Context, endpoint, profileToken string) ([]Preset, error) {
+	request := struct {
+		XMLName      xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl GetPresets"`
+		ProfileToken string   `xml:"ProfileToken"`
+	}{
+		ProfileToken: profileToken,
+	}
+
+	var response struct {
+		Presets []struct {
+			Token    string `xml:"token,attr"`
+			Name     string `xml:"Name"`
+			Position *struct {
+				PanTilt *struct {
+					X float64 `xml:"x,attr"`
+					Y float64 `xml:"y,attr"`
+				} `xml:"PanTilt"`
+				Zoom *struct {
+					X float64 `xml:"x,attr"`
+				} `xml:"Zoom"`
+			} `xml:"PTZPosition"`
+		} `xml:"Preset"`
+	}
+	if err := c.Call(ctx, endpoint, &request, &response); err != nil {
+		return nil, err
+	}
+
+	presets := make([]Preset, 0, len(response.Presets))
+	for _, p := range response.Presets {
+		preset := Preset{Token: p.Token, Name: p.Name}
+		if p.Position != nil {
+			if pt := p.Position.PanTilt; pt != nil {
+				pan, tilt := pt.X, pt.Y
+				preset.Pan, preset.Tilt = &pan, &tilt
+			}
+			if z := p.Position.Zoom; z != nil {
+				zoom := z.X
+				preset.Zoom = &zoom
+			}
+		}
+		presets = append(presets, preset)
+	}
+	return presets, nil
+}
+
+// ptzProfile은 PTZ 설정이 있는 첫 번째 프로필 토큰을 찾습니다
+func (c *Client) ptzProfile(ctx context.Context) (string, error) {
+	profiles, err := c.GetProfiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, profile := range profiles {
+		if profile.PTZ != nil {
+			return profile.Token, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no profile has a PTZ configuration", ErrPTZNotSupported)
+}
+
+// xsdDuration은 time.Duration을 xs:duration 형식(PT1.5S)으로 변환합니다
+func xsdDuration(d time.Duration) string {
+	return "PT" + strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+}
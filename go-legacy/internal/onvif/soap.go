@@ -0,0 +1,250 @@
+package onvif
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ONVIF/SOAP 네임스페이스
+const (
+	nsSOAP = "http://www.w3.org/2003/05/soap-envelope"
+	nsWSSE = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"
+	nsWSU  = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd"
+	nsWSA  = "http://www.w3.org/2005/08/addressing"
+
+	passwordDigestType = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest"
+	nonceEncodingType  = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary"
+)
+
+// 응답 본문 최대 크기 (프로필이 많은 NVR도 충분)
+const maxResponseSize = 4 << 20
+
+// ErrNotAuthorized는 카메라가 인증 정보를 거부한 경우의 에러입니다
+var ErrNotAuthorized = errors.New("onvif: not authorized")
+
+// Fault는 SOAP Fault 응답입니다
+type Fault struct {
+	Code    string
+	Subcode string
+	Reason  string
+}
+
+func (f *Fault) Error() string {
+	if f.Subcode != "" {
+		return fmt.Sprintf("onvif fault %s/%s: %s", f.Code, f.Subcode, f.Reason)
+	}
+	return fmt.Sprintf("onvif fault %s: %s", f.Code, f.Reason)
+}
+
+// Unwrap은 인증 관련 Fault를 ErrNotAuthorized로 매핑합니다
+func (f *Fault) Unwrap() error {
+	if strings.Contains(f.Subcode, "NotAuthorized") || strings.Contains(f.Subcode, "FailedAuthentication") {
+		return ErrNotAuthorized
+	}
+	return nil
+}
+
+// soapFault는 SOAP 1.2 Fault 본문 (요소는 로컬 이름으로 매칭)
+type soapFault struct {
+	Code struct {
+		Value   string `xml:"Value"`
+		Subcode struct {
+			Value   string `xml:"Value"`
+			Subcode struct {
+				Value string `xml:"Value"`
+			} `xml:"Subcode"`
+		} `xml:"Subcode"`
+	} `xml:"Code"`
+	Reason struct {
+		Text string `xml:"Text"`
+	} `xml:"Reason"`
+	// SOAP 1.1 형식으로 응답하는 장비 대응
+	FaultCode   string `xml:"faultcode"`
+	FaultString string `xml:"faultstring"`
+}
+
+func (f *soapFault) toError() *Fault {
+	fault := &Fault{
+		Code:    localName(f.Code.Value),
+		Subcode: localName(f.Code.Subcode.Value),
+		Reason:  strings.TrimSpace(f.Reason.Text),
+	}
+	if sub := localName(f.Code.Subcode.Subcode.Value); sub != "" {
+		fault.Subcode = sub
+	}
+	if fault.Code == "" {
+		fault.Code = localName(f.FaultCode)
+	}
+	if fault.Reason == "" {
+		fault.Reason = strings.TrimSpace(f.FaultString)
+	}
+	return fault
+}
+
+// responseEnvelope는 SOAP 응답 봉투입니다
+type responseEnvelope struct {
+	Body struct {
+		Fault *soapFault `xml:"Fault"`
+		Inner []byte     `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// usernameToken은 WS-Security UsernameToken 헤더입니다
+type usernameToken struct {
+	XMLName  xml.Name `xml:"UsernameToken"`
+	Username string   `xml:"Username"`
+	Password struct {
+		Type  string `xml:"Type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"Password"`
+	Nonce struct {
+		EncodingType string `xml:"EncodingType,attr"`
+		Value        string `xml:",chardata"`
+	} `xml:"Nonce"`
+	Created struct {
+		XMLNS string `xml:"xmlns,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"Created"`
+}
+
+// newUsernameToken은 PasswordDigest 방식의 토큰을 생성합니다
+// Digest = Base64(SHA1(nonce + created + password))
+func newUsernameToken(username, password string, now time.Time) (*usernameToken, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	created := now.UTC().Format("2006-01-02T15:04:05.000Z")
+
+	h := sha1.New()
+	h.Write(nonce)
+	h.Write([]byte(created))
+	h.Write([]byte(password))
+
+	token := &usernameToken{Username: username}
+	token.Password.Type = passwordDigestType
+	token.Password.Value = base64.StdEncoding.EncodeToString(h.Sum(nil))
+	token.Nonce.EncodingType = nonceEncodingType
+	token.Nonce.Value = base64.StdEncoding.EncodeToString(nonce)
+	token.Created.XMLNS = nsWSU
+	token.Created.Value = created
+	return token, nil
+}
+
+// addressing은 WS-Addressing 헤더입니다 (이벤트 구독 등 일부 장비가 요구)
+type addressing struct {
+	Action string
+	To     string
+}
+
+// buildEnvelope는 요청 본문을 SOAP 봉투로 감쌉니다
+// token이 nil이면 인증 헤더, wsa가 nil이면 WS-Addressing 헤더를 생략합니다
+func buildEnvelope(body interface{}, token *usernameToken, wsa *addressing) ([]byte, error) {
+	payload, err := xml.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<s:Envelope xmlns:s="` + nsSOAP + `">`)
+	if token != nil || wsa != nil {
+		buf.WriteString(`<s:Header>`)
+	}
+	if token != nil {
+		header, err := xml.Marshal(token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal security header: %w", err)
+		}
+		buf.WriteString(`<Security s:mustUnderstand="1" xmlns="` + nsWSSE + `">`)
+		buf.Write(header)
+		buf.WriteString(`</Security>`)
+	}
+	if wsa != nil {
+		buf.WriteString(`<Action s:mustUnderstand="1" xmlns="` + nsWSA + `">`)
+		xml.EscapeText(&buf, []byte(wsa.Action))
+		buf.WriteString(`</Action><To s:mustUnderstand="1" xmlns="` + nsWSA + `">`)
+		xml.EscapeText(&buf, []byte(wsa.To))
+		buf.WriteString(`</To>`)
+	}
+	if token != nil || wsa != nil {
+		buf.WriteString(`</s:Header>`)
+	}
+	buf.WriteString(`<s:Body>`)
+	buf.Write(payload)
+	buf.WriteString(`</s:Body></s:Envelope>`)
+	return buf.Bytes(), nil
+}
+
+// call은 SOAP 요청을 보내고 응답 본문을 resp로 디코딩합니다 (resp가 nil이면 무시)
+func call(ctx context.Context, client *http.Client, endpoint string, body interface{}, token *usernameToken, wsa *addressing, resp interface{}) error {
+	payload, err := buildEnvelope(body, token, wsa)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", `application/soap+xml; charset=utf-8`)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var envelope responseEnvelope
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		if res.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("%w (HTTP 401)", ErrNotAuthorized)
+		}
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected HTTP status %d", res.StatusCode)
+		}
+		return fmt.Errorf("invalid SOAP response: %w", err)
+	}
+
+	if envelope.Body.Fault != nil {
+		return envelope.Body.Fault.toError()
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w (HTTP 401)", ErrNotAuthorized)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected HTTP status %d", res.StatusCode)
+	}
+
+	if resp == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(envelope.Body.Inner, resp); err != nil {
+		return fmt.Errorf("invalid SOAP response body: %w", err)
+	}
+	return nil
+}
+
+// localName은 "prefix:Name"에서 Name 부분을 반환합니다
+func localName(qname string) string {
+	qname = strings.TrimSpace(qname)
+	if i := strings.LastIndexByte(qname, ':'); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
+}
@@ -0,0 +1,61 @@
+package packetizer
+
+import (
+	"errors"
+
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/mpegts"
+)
+
+// SetupMPEGTS는 MPEG-TS 트랙별 콜백을 등록합니다
+// 첫 번째 H.264/H.265 트랙과 첫 번째 AAC 트랙만 사용합니다 (콜백이 없는 트랙은 무시됨)
+func SetupMPEGTS(r *mpegts.Reader, w *Packetizer) error {
+	// PTS wrap-around(33비트) 처리
+	td := &mpegts.TimeDecoder{}
+	td.Initialize()
+
+	for _, track := range r.Tracks() {
+		switch codec := track.Codec.(type) {
+		case *mpegts.CodecH264:
+			if w.HasVideo() {
+				continue
+			}
+			if err := w.InitH264(nil, nil); err != nil {
+				return err
+			}
+			r.OnDataH264(track, func(pts int64, _ int64, au [][]byte) error {
+				w.WriteH264(TimestampToDuration(td.Decode(pts), VideoClockRate), au)
+				return nil
+			})
+
+		case *mpegts.CodecH265:
+			if w.HasVideo() {
+				continue
+			}
+			if err := w.InitH265(nil, nil, nil); err != nil {
+				return err
+			}
+			r.OnDataH265(track, func(pts int64, _ int64, au [][]byte) error {
+				w.WriteH265(TimestampToDuration(td.Decode(pts), VideoClockRate), au)
+				return nil
+			})
+
+		case *mpegts.CodecMPEG4Audio:
+			if w.HasAudio() {
+				continue
+			}
+			if err := w.InitAAC(codec.SampleRate); err != nil {
+				return err
+			}
+			r.OnDataMPEG4Audio(track, func(pts int64, aus [][]byte) error {
+				w.WriteAAC(TimestampToDuration(td.Decode(pts), VideoClockRate), aus)
+				return nil
+			})
+		}
+	}
+
+	if !w.HasVideo() {
+		return errors.New("MPEG-TS stream has no H264/H265 video track")
+	}
+
+	return nil
+}
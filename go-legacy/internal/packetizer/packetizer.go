@@ -0,0 +1,312 @@
+// Package packetizer는 RTMP/SRT/HLS/MPEG-TS/MJPEG에서 받은 프레임을 RTP 패킷으로 변환합니다
+// RTSP 소스와 같은 페이로드 타입을 사용하므로 core.Stream 구독자(WebRTC/HLS/녹화)가 그대로 처리합니다
+package packetizer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph265"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmjpeg"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmpeg4audio"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h265"
+	"github.com/pion/rtp"
+	"go.uber.org/zap"
+)
+
+const (
+	// VideoPayloadType는 비디오 RTP 페이로드 타입 (RTSP 소스와 동일)
+	VideoPayloadType = 96
+
+	// AudioPayloadType는 오디오(AAC) RTP 페이로드 타입
+	AudioPayloadType = 97
+
+	// MJPEGPayloadType는 MJPEG 정적 RTP 페이로드 타입 (RFC 2435, 인코더 고정값)
+	MJPEGPayloadType = 26
+
+	// VideoClockRate는 비디오 RTP 클럭 (90kHz)
+	VideoClockRate = 90000
+)
+
+// CodecSetter는 감지된 비디오 코덱을 받는 대상입니다 (core.Stream)
+type CodecSetter interface {
+	SetVideoCodec(codec string)
+}
+
+// Packetizer는 액세스 유닛을 RTP 패킷으로 변환해 콜백으로 전달합니다
+// 연결마다 새로 생성합니다 (SSRC/시퀀스 번호 초기화)
+type Packetizer struct {
+	stream   CodecSetter // nil 가능
+	onPacket func(pkt *rtp.Packet)
+	logger   *zap.Logger
+
+	// 비디오
+	videoCodec string // H264, H265 또는 MJPEG
+	h264Enc    *rtph264.Encoder
+	h265Enc    *rtph265.Encoder
+	mjpegEnc   *rtpmjpeg.Encoder
+
+	// 파라미터 세트 (키프레임에 없으면 앞에 삽입해 중간 참여 구독자도 디코딩 가능하게 함)
+	vps []byte
+	sps []byte
+	pps []byte
+
+	// 오디오 (AAC)
+	aacEnc        *rtpmpeg4audio.Encoder
+	aacSampleRate int
+}
+
+// New는 새로운 Packetizer를 생성합니다
+func New(stream CodecSetter, onPacket func(*rtp.Packet), logger *zap.Logger) *Packetizer {
+	return &Packetizer{
+		stream:   stream,
+		onPacket: onPacket,
+		logger:   logger,
+	}
+}
+
+// InitH264는 H.264 비디오 트랙을 설정합니다
+func (w *Packetizer) InitH264(sps, pps []byte) error {
+	w.h264Enc = &rtph264.Encoder{
+		PayloadType:       VideoPayloadType,
+		PacketizationMode: 1,
+	}
+	if err := w.h264Enc.Init(); err != nil {
+		return fmt.Errorf("failed to initialize H264 encoder: %w", err)
+	}
+
+	w.sps, w.pps = sps, pps
+	w.setVideoCodec("H264")
+	return nil
+}
+
+// InitH265는 H.265 비디오 트랙을 설정합니다
+func (w *Packetizer) InitH265(vps, sps, pps []byte) error {
+	w.h265Enc = &rtph265.Encoder{
+		PayloadType: VideoPayloadType,
+	}
+	if err := w.h265Enc.Init(); err != nil {
+		return fmt.Errorf("failed to initialize H265 encoder: %w", err)
+	}
+
+	w.vps, w.sps, w.pps = vps, sps, pps
+	w.setVideoCodec("H265")
+	return nil
+}
+
+// InitMJPEG는 MJPEG 비디오 트랙을 설정합니다
+func (w *Packetizer) InitMJPEG() error {
+	w.mjpegEnc = &rtpmjpeg.Encoder{}
+	if err := w.mjpegEnc.Init(); err != nil {
+		return fmt.Errorf("failed to initialize MJPEG encoder: %w", err)
+	}
+
+	w.setVideoCodec("MJPEG")
+	return nil
+}
+
+// setVideoCodec은 비디오 코덱을 기록하고 스트림에 알립니다
+func (w *Packetizer) setVideoCodec(codec string) {
+	w.videoCodec = codec
+	if w.stream != nil {
+		w.stream.SetVideoCodec(codec)
+	}
+}
+
+// InitAAC는 AAC 오디오 트랙을 설정합니다
+func (w *Packetizer) InitAAC(sampleRate int) error {
+	if sampleRate <= 0 {
+		return fmt.Errorf("invalid AAC sample rate: %d", sampleRate)
+	}
+
+	w.aacEnc = &rtpmpeg4audio.Encoder{
+		PayloadType:      AudioPayloadType,
+		SizeLength:       13,
+		IndexLength:      3,
+		IndexDeltaLength: 3,
+	}
+	if err := w.aacEnc.Init(); err != nil {
+		return fmt.Errorf("failed to initialize AAC encoder: %w", err)
+	}
+
+	w.aacSampleRate = sampleRate
+	return nil
+}
+
+// HasVideo는 비디오 트랙이 설정되었는지 반환합니다
+func (w *Packetizer) HasVideo() bool {
+	return w.videoCodec != ""
+}
+
+// HasAudio는 오디오 트랙이 설정되었는지 반환합니다
+func (w *Packetizer) HasAudio() bool {
+	return w.aacEnc != nil
+}
+
+// VideoCodec은 설정된 비디오 코덱을 반환합니다
+func (w *Packetizer) VideoCodec() string {
+	return w.videoCodec
+}
+
+// WriteH264는 H.264 액세스 유닛을 씁니다
+func (w *Packetizer) WriteH264(pts time.Duration, au [][]byte) {
+	if w.h264Enc == nil {
+		return
+	}
+
+	filtered := make([][]byte, 0, len(au)+2)
+	hasParams := false
+	for _, nalu := range au {
+		if len(nalu) == 0 {
+			continue
+		}
+		switch h264.NALUType(nalu[0] & 0x1F) {
+		case h264.NALUTypeSPS:
+			w.sps = nalu
+			hasParams = true
+		case h264.NALUTypePPS:
+			w.pps = nalu
+			hasParams = true
+		case h264.NALUTypeAccessUnitDelimiter:
+			continue
+		}
+		filtered = append(filtered, nalu)
+	}
+
+	// 파라미터 세트만 있는 AU (RTMP 시퀀스 헤더)는 저장만 함
+	if len(filtered) == 0 || (hasParams && !containsH264Slice(filtered)) {
+		return
+	}
+
+	if !hasParams && h264.IsRandomAccess(filtered) && w.sps != nil && w.pps != nil {
+		filtered = append([][]byte{w.sps, w.pps}, filtered...)
+	}
+
+	pkts, err := w.h264Enc.Encode(filtered)
+	if err != nil {
+		w.logger.Debug("Failed to encode H264 access unit", zap.Error(err))
+		return
+	}
+	w.writePackets(pkts, uint32(durationToTimestamp(pts, VideoClockRate)))
+}
+
+// WriteH265는 H.265 액세스 유닛을 씁니다
+func (w *Packetizer) WriteH265(pts time.Duration, au [][]byte) {
+	if w.h265Enc == nil {
+		return
+	}
+
+	filtered := make([][]byte, 0, len(au)+3)
+	hasParams := false
+	for _, nalu := range au {
+		if len(nalu) < 2 {
+			continue
+		}
+		switch h265.NALUType((nalu[0] >> 1) & 0b111111) {
+		case h265.NALUType_VPS_NUT:
+			w.vps = nalu
+			hasParams = true
+		case h265.NALUType_SPS_NUT:
+			w.sps = nalu
+			hasParams = true
+		case h265.NALUType_PPS_NUT:
+			w.pps = nalu
+			hasParams = true
+		case h265.NALUType_AUD_NUT:
+			continue
+		}
+		filtered = append(filtered, nalu)
+	}
+
+	if len(filtered) == 0 || (hasParams && !containsH265Slice(filtered)) {
+		return
+	}
+
+	if !hasParams && h265.IsRandomAccess(filtered) && w.vps != nil && w.sps != nil && w.pps != nil {
+		filtered = append([][]byte{w.vps, w.sps, w.pps}, filtered...)
+	}
+
+	pkts, err := w.h265Enc.Encode(filtered)
+	if err != nil {
+		w.logger.Debug("Failed to encode H265 access unit", zap.Error(err))
+		return
+	}
+	w.writePackets(pkts, uint32(durationToTimestamp(pts, VideoClockRate)))
+}
+
+// WriteAAC는 AAC 액세스 유닛들을 씁니다
+func (w *Packetizer) WriteAAC(pts time.Duration, aus [][]byte) {
+	if w.aacEnc == nil || len(aus) == 0 {
+		return
+	}
+
+	pkts, err := w.aacEnc.Encode(aus)
+	if err != nil {
+		w.logger.Debug("Failed to encode AAC access unit", zap.Error(err))
+		return
+	}
+	w.writePackets(pkts, uint32(durationToTimestamp(pts, w.aacSampleRate)))
+}
+
+// WriteJPEG는 JPEG 이미지 한 장을 씁니다
+func (w *Packetizer) WriteJPEG(pts time.Duration, image []byte) {
+	if w.mjpegEnc == nil {
+		return
+	}
+
+	pkts, err := w.mjpegEnc.Encode(image)
+	if err != nil {
+		w.logger.Debug("Failed to encode JPEG frame", zap.Error(err))
+		return
+	}
+	w.writePackets(pkts, uint32(durationToTimestamp(pts, VideoClockRate)))
+}
+
+// writePackets는 RTP 패킷에 타임스탬프를 설정하고 전달합니다
+func (w *Packetizer) writePackets(pkts []*rtp.Packet, timestamp uint32) {
+	for _, pkt := range pkts {
+		// 인코더는 AU 내 상대 타임스탬프를 설정함
+		pkt.Timestamp += timestamp
+		w.onPacket(pkt)
+	}
+}
+
+// containsH264Slice는 AU에 슬라이스(영상 데이터) NALU가 있는지 확인합니다
+func containsH264Slice(au [][]byte) bool {
+	for _, nalu := range au {
+		typ := h264.NALUType(nalu[0] & 0x1F)
+		if typ >= h264.NALUTypeNonIDR && typ <= h264.NALUTypeIDR {
+			return true
+		}
+	}
+	return false
+}
+
+// containsH265Slice는 AU에 슬라이스(VCL) NALU가 있는지 확인합니다
+func containsH265Slice(au [][]byte) bool {
+	for _, nalu := range au {
+		if h265.NALUType((nalu[0]>>1)&0b111111) < h265.NALUType_VPS_NUT {
+			return true
+		}
+	}
+	return false
+}
+
+// durationToTimestamp는 시간을 지정 클럭의 타임스탬프로 변환합니다 (오버플로 방지)
+func durationToTimestamp(d time.Duration, clockRate int) int64 {
+	return multiplyAndDivide(int64(d), int64(clockRate), int64(time.Second))
+}
+
+// TimestampToDuration은 지정 클럭의 타임스탬프를 시간으로 변환합니다
+func TimestampToDuration(ts int64, clockRate int) time.Duration {
+	return time.Duration(multiplyAndDivide(ts, int64(time.Second), int64(clockRate)))
+}
+
+// multiplyAndDivide는 v*m/d를 오버플로 없이 계산합니다
+func multiplyAndDivide(v, m, d int64) int64 {
+	secs := v / d
+	dec := v % d
+	return secs*m + dec*m/d
+}
@@ -0,0 +1,77 @@
+package packetizer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bluenviron/gortmplib"
+	"github.com/bluenviron/gortsplib/v5/pkg/format"
+)
+
+// SetupRTMP는 RTMP 트랙별 콜백을 등록합니다
+// 첫 번째 H.264/H.265 트랙과 첫 번째 AAC 트랙만 사용하고 나머지 트랙은 무시합니다
+func SetupRTMP(r *gortmplib.Reader, w *Packetizer) error {
+	for _, track := range r.Tracks() {
+		switch t := track.(type) {
+		case *format.H264:
+			if w.HasVideo() {
+				r.OnDataH264(t, func(time.Duration, time.Duration, [][]byte) {})
+				continue
+			}
+			sps, pps := t.SafeParams()
+			if err := w.InitH264(sps, pps); err != nil {
+				return err
+			}
+			r.OnDataH264(t, func(pts time.Duration, _ time.Duration, au [][]byte) {
+				w.WriteH264(pts, au)
+			})
+
+		case *format.H265:
+			if w.HasVideo() {
+				r.OnDataH265(t, func(time.Duration, time.Duration, [][]byte) {})
+				continue
+			}
+			vps, sps, pps := t.SafeParams()
+			if err := w.InitH265(vps, sps, pps); err != nil {
+				return err
+			}
+			r.OnDataH265(t, func(pts time.Duration, _ time.Duration, au [][]byte) {
+				w.WriteH265(pts, au)
+			})
+
+		case *format.MPEG4Audio:
+			if w.HasAudio() || t.Config == nil {
+				r.OnDataMPEG4Audio(t, func(time.Duration, []byte) {})
+				continue
+			}
+			if err := w.InitAAC(t.Config.SampleRate); err != nil {
+				return err
+			}
+			r.OnDataMPEG4Audio(t, func(pts time.Duration, au []byte) {
+				w.WriteAAC(pts, [][]byte{au})
+			})
+
+		// 지원하지 않는 오디오 코덱은 무시
+		case *format.Opus:
+			r.OnDataOpus(t, func(time.Duration, []byte) {})
+		case *format.MPEG1Audio:
+			r.OnDataMPEG1Audio(t, func(time.Duration, []byte) {})
+		case *format.AC3:
+			r.OnDataAC3(t, func(time.Duration, []byte) {})
+		case *format.G711:
+			r.OnDataG711(t, func(time.Duration, []byte) {})
+		case *format.LPCM:
+			r.OnDataLPCM(t, func(time.Duration, []byte) {})
+
+		default:
+			return fmt.Errorf("unsupported RTMP video codec %s (supported: H264, H265)", track.Codec())
+		}
+	}
+
+	if !w.HasVideo() {
+		return errors.New("RTMP stream has no H264/H265 video track")
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package playback
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// clipIdleTimeout는 마지막 요청 이후 MP4 클립 파일을 유지하는 시간입니다
+	// (플레이어의 탐색/분할 Range 요청이 같은 파일을 사용, 녹화 중인 구간이 늦게 반영되는 최대 시간)
+	clipIdleTimeout = time.Minute
+
+	// clipMaxEntries는 동시에 보관하는 MP4 클립 파일 수입니다
+	clipMaxEntries = 16
+)
+
+// clipKey는 MP4 클립을 구분합니다 (같은 스트림, 시작 시각, 길이면 같은 파일)
+type clipKey struct {
+	streamID string
+	start    int64 // UnixNano
+	duration time.Duration
+}
+
+// clip은 한 번 생성한 MP4 클립 파일입니다
+type clip struct {
+	ready    chan struct{} // 생성 완료 시 닫힘
+	path     string
+	err      error
+	lastUsed time.Time
+}
+
+// clipCache는 Range 요청마다 클립을 다시 만들지 않도록 생성한 MP4 파일을 보관합니다
+type clipCache struct {
+	logger *zap.Logger
+
+	mutex sync.Mutex
+	dir   string // 처음 사용할 때 생성
+	clips map[clipKey]*clip
+}
+
+// OpenMP4는 요청 구간의 MP4 클립 파일을 엽니다
+// 같은 구간의 클립은 한 번만 생성하고, 동시에 요청되면 생성이 끝날 때까지 기다립니다
+// 반환된 파일은 캐시에서 제거되어도 닫을 때까지 읽을 수 있으며, 호출자가 닫아야 합니다
+func (m *Manager) OpenMP4(streamID string, start time.Time, duration time.Duration) (*os.File, error) {
+	key := clipKey{streamID: streamID, start: start.UnixNano(), duration: duration}
+
+	for {
+		c, created, err := m.clips.get(key)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			c.path, c.err = m.writeClip(streamID, start, duration)
+			m.clips.finish(key, c)
+		}
+		<-c.ready
+
+		if c.err != nil {
+			return nil, c.err
+		}
+
+		f, ok := m.clips.open(key, c)
+		if ok {
+			return f, nil
+		}
+		// 생성 직후 제거된 경우 다시 생성
+	}
+}
+
+// writeClip은 클립을 캐시 디렉토리의 새 파일로 생성합니다
+func (m *Manager) writeClip(streamID string, start time.Time, duration time.Duration) (string, error) {
+	dir, err := m.clips.directory()
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(dir, "clip-*.mp4")
+	if err != nil {
+		return "", err
+	}
+
+	err = m.Write(f, streamID, start, duration, FormatMP4)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// Close는 보관 중인 클립 파일을 삭제합니다
+func (m *Manager) Close() {
+	m.clips.close()
+}
+
+// directory는 클립 파일 디렉토리를 반환합니다 (없으면 생성)
+func (cc *clipCache) directory() (string, error) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	if cc.dir == "" {
+		dir, err := os.MkdirTemp("", "playback-clips-")
+		if err != nil {
+			return "", err
+		}
+		cc.dir = dir
+	}
+	return cc.dir, nil
+}
+
+// get은 key의 클립을 반환합니다
+// 없으면 새 항목을 등록하고 created=true를 반환하며, 호출자가 생성 후 finish를 호출해야 합니다
+func (cc *clipCache) get(key clipKey) (c *clip, created bool, err error) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.evictLocked(time.Now())
+
+	if c, exists := cc.clips[key]; exists {
+		return c, false, nil
+	}
+
+	c = &clip{ready: make(chan struct{})}
+	cc.clips[key] = c
+	return c, true, nil
+}
+
+// finish는 클립 생성을 완료합니다 (실패한 클립은 보관하지 않음)
+func (cc *clipCache) finish(key clipKey, c *clip) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	c.lastUsed = time.Now()
+	if c.err != nil && cc.clips[key] == c {
+		delete(cc.clips, key)
+	}
+	close(c.ready)
+}
+
+// open은 클립 파일을 엽니다 (이미 제거된 클립이면 false)
+func (cc *clipCache) open(key clipKey, c *clip) (*os.File, bool) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	if cc.clips[key] != c {
+		return nil, false
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		cc.removeLocked(key, c)
+		return nil, false
+	}
+	c.lastUsed = time.Now()
+	return f, true
+}
+
+// evictLocked는 오래 사용하지 않은 클립과 최대 개수를 넘는 클립을 제거합니다
+// 생성 중인 클립은 제거하지 않습니다
+func (cc *clipCache) evictLocked(now time.Time) {
+	var oldestKey clipKey
+	var oldest *clip
+	ready := 0
+
+	for key, c := range cc.clips {
+		select {
+		case <-c.ready:
+		default:
+			continue
+		}
+
+		if now.Sub(c.lastUsed) > clipIdleTimeout {
+			cc.removeLocked(key, c)
+			continue
+		}
+
+		ready++
+		if oldest == nil || c.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = key, c
+		}
+	}
+
+	// 새 클립 하나를 추가할 자리를 남김
+	if ready >= clipMaxEntries && oldest != nil {
+		cc.removeLocked(oldestKey, oldest)
+	}
+}
+
+// removeLocked는 클립을 캐시에서 제거하고 파일을 삭제합니다
+// 이미 열린 파일은 닫을 때까지 읽을 수 있습니다
+func (cc *clipCache) removeLocked(key clipKey, c *clip) {
+	delete(cc.clips, key)
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		cc.logger.Warn("Failed to remove playback clip",
+			zap.String("path", c.path),
+			zap.Error(err),
+		)
+	}
+}
+
+// close는 모든 클립 파일과 디렉토리를 삭제합니다
+func (cc *clipCache) close() {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.clips = make(map[clipKey]*clip)
+	if cc.dir != "" {
+		if err := os.RemoveAll(cc.dir); err != nil {
+			cc.logger.Warn("Failed to remove playback clip directory",
+				zap.String("dir", cc.dir),
+				zap.Error(err),
+			)
+		}
+		cc.dir = ""
+	}
+}
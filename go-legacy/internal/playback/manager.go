@@ -0,0 +1,333 @@
+package playback
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/fmp4"
+	"github.com/yourusername/cctv3/internal/recorder"
+	"go.uber.org/zap"
+)
+
+// ErrNoSegmentsFound는 요청 구간에 녹화 세그먼트가 없을 때 반환됩니다
+var ErrNoSegmentsFound = errors.New("no recording segments found")
+
+// 재생 출력 형식
+const (
+	FormatFMP4 = "fmp4"
+	FormatMP4  = "mp4"
+)
+
+// Entry는 연속된 녹화 구간을 나타냅니다
+type Entry struct {
+	Start    time.Time     `json:"start"`
+	Duration EntryDuration `json:"duration"`
+	URL      string        `json:"url,omitempty"`
+}
+
+// EntryDuration은 초 단위 실수로 직렬화되는 구간 길이입니다
+type EntryDuration time.Duration
+
+// MarshalJSON은 구간 길이를 초 단위로 직렬화합니다
+func (d EntryDuration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(time.Duration(d).Seconds(), 'f', -1, 64)), nil
+}
+
+// Manager는 녹화 파일 조회 및 재생 구간 생성을 담당합니다
+type Manager struct {
+	recordPath string
+	logger     *zap.Logger
+	clips      clipCache // Range 요청용 MP4 클립
+}
+
+// NewManager는 새로운 재생 관리자를 생성합니다
+func NewManager(recordPath string, logger *zap.Logger) *Manager {
+	return &Manager{
+		recordPath: recordPath,
+		logger:     logger,
+		clips: clipCache{
+			logger: logger,
+			clips:  make(map[clipKey]*clip),
+		},
+	}
+}
+
+// List는 스트림의 녹화 구간 목록을 반환합니다
+// 인접한 세그먼트(코덱 동일, 시각 오차 1초 이내)는 하나의 구간으로 합쳐집니다
+func (m *Manager) List(streamID string, start, end *time.Time) ([]*Entry, error) {
+	segments, err := m.findSegments(streamID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseSegments(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := concatenateSegments(parsed)
+
+	if start != nil {
+		first := entries[0]
+
+		// 시작 시각이 첫 구간 이후의 공백에 있으면 첫 구간 제외
+		if first.Start.Add(time.Duration(first.Duration)).Before(*start) {
+			entries = entries[1:]
+			if len(entries) == 0 {
+				return nil, ErrNoSegmentsFound
+			}
+		} else if first.Start.Before(*start) {
+			entries[0].Duration -= EntryDuration(start.Sub(first.Start))
+			entries[0].Start = *start
+		}
+	}
+
+	if end != nil {
+		last := entries[len(entries)-1]
+		if last.Start.Add(time.Duration(last.Duration)).After(*end) {
+			entries[len(entries)-1].Duration = EntryDuration(end.Sub(last.Start))
+		}
+	}
+
+	return entries, nil
+}
+
+// Write는 요청 구간을 지정된 형식으로 w에 기록합니다
+// 출력은 start 이전의 가장 가까운 키프레임부터 시작합니다
+func (m *Manager) Write(w io.Writer, streamID string, start time.Time, duration time.Duration, format string) error {
+	var mux muxer
+
+	switch format {
+	case "", FormatFMP4:
+		mux = &muxerFMP4{w: w}
+	case FormatMP4:
+		mux = &muxerMP4{w: w}
+	default:
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	end := start.Add(duration)
+	segments, err := m.findSegments(streamID, &start, &end)
+	if err != nil {
+		return err
+	}
+
+	return seekAndMux(segments, start, duration, mux)
+}
+
+// findSegments는 요청 구간과 겹칠 수 있는 세그먼트를 찾습니다
+func (m *Manager) findSegments(streamID string, start, end *time.Time) ([]*recorder.Segment, error) {
+	if streamID == "" || streamID == "." || streamID == ".." ||
+		strings.ContainsAny(streamID, `/\`) {
+		return nil, fmt.Errorf("invalid stream ID: %s", streamID)
+	}
+
+	all, err := recorder.ListSegments(m.recordPath, streamID)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoSegmentsFound
+		}
+		return nil, err
+	}
+
+	// 재생 종료 시각 이전에 시작한 세그먼트만 사용
+	segments := make([]*recorder.Segment, 0, len(all))
+	for _, seg := range all {
+		if end == nil || !end.Before(seg.Start) {
+			segments = append(segments, seg)
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, ErrNoSegmentsFound
+	}
+
+	if start == nil || start.Before(segments[0].Start) {
+		return segments, nil
+	}
+
+	// 재생 시작 시각을 포함할 수 있는 세그먼트부터 사용
+	for i := 0; i < len(segments)-1; i++ {
+		if !start.Before(segments[i].Start) && start.Before(segments[i+1].Start) {
+			return segments[i:], nil
+		}
+	}
+
+	return segments[len(segments)-1:], nil
+}
+
+// parsedSegment는 헤더와 길이를 읽은 세그먼트
+type parsedSegment struct {
+	start    time.Time
+	init     *fmp4.Init
+	duration time.Duration
+}
+
+// parseSegment는 세그먼트의 init과 길이를 읽습니다
+func parseSegment(seg *recorder.Segment) (*parsedSegment, error) {
+	f, err := os.Open(seg.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	init, duration, err := segmentFMP4ReadHeader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// 헤더에 길이가 없으면 part를 파싱해 계산
+	if duration == 0 {
+		duration, err = segmentFMP4ReadDurationFromParts(f, init)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &parsedSegment{
+		start:    seg.Start,
+		init:     init,
+		duration: duration,
+	}, nil
+}
+
+// parseSegments는 세그먼트들을 병렬로 파싱합니다
+// 아직 part가 기록되지 않은 세그먼트(녹화 시작 직후)는 건너뜁니다
+func parseSegments(segments []*recorder.Segment) ([]*parsedSegment, error) {
+	parsed := make([]*parsedSegment, len(segments))
+	errs := make([]error, len(segments))
+	done := make(chan struct{})
+
+	for i, seg := range segments {
+		go func(i int, seg *recorder.Segment) {
+			parsed[i], errs[i] = parseSegment(seg)
+			done <- struct{}{}
+		}(i, seg)
+	}
+
+	for range segments {
+		<-done
+	}
+
+	out := make([]*parsedSegment, 0, len(parsed))
+	var lastErr error
+	for i, p := range parsed {
+		if errs[i] != nil {
+			lastErr = errs[i]
+			continue
+		}
+		out = append(out, p)
+	}
+
+	if len(out) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, ErrNoSegmentsFound
+	}
+
+	return out, nil
+}
+
+// concatenateSegments는 이어지는 세그먼트를 하나의 구간으로 합칩니다
+func concatenateSegments(parsed []*parsedSegment) []*Entry {
+	out := []*Entry{}
+	var prevInit *fmp4.Init
+
+	for _, p := range parsed {
+		if len(out) != 0 && segmentFMP4CanBeConcatenated(
+			prevInit,
+			out[len(out)-1].Start.Add(time.Duration(out[len(out)-1].Duration)),
+			p.init,
+			p.start) {
+			prevStart := out[len(out)-1].Start
+			curEnd := p.start.Add(p.duration)
+			out[len(out)-1].Duration = EntryDuration(curEnd.Sub(prevStart))
+		} else {
+			out = append(out, &Entry{
+				Start:    p.start,
+				Duration: EntryDuration(p.duration),
+			})
+		}
+
+		prevInit = p.init
+	}
+
+	return out
+}
+
+// seekAndMux는 세그먼트들을 이어 붙여 요청 구간을 muxer로 출력합니다
+func seekAndMux(
+	segments []*recorder.Segment,
+	start time.Time,
+	duration time.Duration,
+	m muxer,
+) error {
+	f, err := os.Open(segments[0].Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	firstInit, _, err := segmentFMP4ReadHeader(f)
+	if err != nil {
+		return err
+	}
+
+	m.writeInit(&fmp4.Init{
+		Tracks: firstInit.Tracks,
+	})
+
+	dts := segments[0].Start.Sub(start) // 음수
+	prevInit := firstInit
+
+	segmentDuration, err := segmentFMP4MuxParts(f, dts, duration, firstInit.Tracks, m)
+	if err != nil {
+		return err
+	}
+
+	segmentEnd := segments[0].Start.Add(segmentDuration)
+
+	for _, seg := range segments[1:] {
+		f, err = os.Open(seg.Path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var init *fmp4.Init
+		init, _, err = segmentFMP4ReadHeader(f)
+		if err != nil {
+			return err
+		}
+
+		if !segmentFMP4CanBeConcatenated(prevInit, segmentEnd, init, seg.Start) {
+			break
+		}
+
+		dts = seg.Start.Sub(start) // 양수
+
+		segmentDuration, err = segmentFMP4MuxParts(f, dts, duration, firstInit.Tracks, m)
+		if err != nil {
+			return err
+		}
+
+		segmentEnd = seg.Start.Add(segmentDuration)
+		prevInit = init
+	}
+
+	return m.flush()
+}
+
+// ParseDuration은 초 단위 숫자 또는 Go duration 문자열을 파싱합니다
+func ParseDuration(raw string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(raw, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.ParseDuration(raw)
+}
@@ -0,0 +1,18 @@
+package playback
+
+import "github.com/bluenviron/mediacommon/v2/pkg/formats/fmp4"
+
+// muxer는 재생 구간을 특정 컨테이너 형식으로 출력하는 인터페이스입니다
+type muxer interface {
+	writeInit(init *fmp4.Init)
+	setTrack(trackID int)
+	writeSample(
+		dts int64,
+		ptsOffset int32,
+		isNonSyncSample bool,
+		payloadSize uint32,
+		getPayload func() ([]byte, error),
+	) error
+	writeFinalDTS(dts int64)
+	flush() error
+}
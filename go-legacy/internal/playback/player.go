@@ -0,0 +1,617 @@
+package playback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph265"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/fmp4"
+	"github.com/pion/rtp"
+	"go.uber.org/zap"
+)
+
+const (
+	// rtpClockRate는 비디오 RTP 클럭
+	rtpClockRate = 90000
+	// rtpPayloadMaxSize는 WebRTC 전송을 고려한 RTP 페이로드 최대 크기
+	rtpPayloadMaxSize = 1200
+	// maxPlayDuration은 한 번에 읽는 최대 재생 구간 (연속 구간 끝까지 읽기 위함)
+	maxPlayDuration = 24 * time.Hour
+	// 재생 속도 제한
+	minPlaybackRate = 0.1
+	maxPlaybackRate = 16
+)
+
+var (
+	errSeek   = errors.New("seek requested")
+	errClosed = errors.New("player closed")
+)
+
+// PacketSink는 재생 RTP 패킷을 받는 대상입니다 (webrtc.Peer 등)
+type PacketSink interface {
+	OnPacket(pkt *rtp.Packet) error
+}
+
+// PlayerState는 재생 상태를 나타냅니다
+type PlayerState struct {
+	Position time.Time `json:"position"`
+	Paused   bool      `json:"paused"`
+	Rate     float64   `json:"rate"`
+	Ended    bool      `json:"ended"`
+}
+
+// PlayerConfig는 녹화 재생기 설정
+type PlayerConfig struct {
+	StreamID string
+	Start    time.Time
+	Rate     float64
+	Sink     PacketSink
+	Ready    <-chan struct{}         // 닫히면 전송 시작 (nil이면 즉시 시작)
+	OnEnd    func(state PlayerState) // 녹화 끝에 도달했을 때 호출
+	Logger   *zap.Logger
+}
+
+// playerCommand는 재생 제어 명령
+type playerCommand struct {
+	seek   *time.Time
+	pause  *bool
+	rate   float64
+	result chan PlayerState
+}
+
+// Player는 녹화된 fMP4 세그먼트를 읽어 RTP 패킷으로 재전송합니다
+// 패킷은 재생 속도에 맞춰 실시간으로 전송되며 seek/pause/rate 변경을 지원합니다
+type Player struct {
+	manager  *Manager
+	streamID string
+	sink     PacketSink
+	ready    <-chan struct{}
+	onEnd    func(state PlayerState)
+	logger   *zap.Logger
+	codec    string
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	commands  chan playerCommand
+	done      chan struct{}
+	startOnce sync.Once
+
+	// 재생 루프 상태 (루프 고루틴에서만 변경)
+	start    time.Time
+	paused   bool
+	rate     float64
+	ended    bool
+	position time.Time
+
+	// 재생 시계 (wall clock ↔ 샘플 DTS 기준점)
+	needAnchor bool
+	anchorWall time.Time
+	anchorDTS  time.Duration
+	wallOrigin time.Time
+	rtpOrigin  uint32
+
+	// RTP 인코더 (seek 간에도 시퀀스 번호 유지)
+	h264Encoder *rtph264.Encoder
+	h265Encoder *rtph265.Encoder
+
+	stateMutex sync.RWMutex
+	state      PlayerState
+}
+
+// NewPlayer는 새로운 녹화 재생기를 생성합니다
+// 시작 시각에 해당하는 녹화가 없으면 ErrNoSegmentsFound를 반환합니다
+func (m *Manager) NewPlayer(config PlayerConfig) (*Player, error) {
+	rate := config.Rate
+	if rate == 0 {
+		rate = 1
+	}
+	if rate < minPlaybackRate || rate > maxPlaybackRate {
+		return nil, fmt.Errorf("invalid playback rate: %v", rate)
+	}
+
+	segments, err := m.findSegments(config.StreamID, &config.Start, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := readSegmentCodec(segments[0].Path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &Player{
+		manager:    m,
+		streamID:   config.StreamID,
+		sink:       config.Sink,
+		ready:      config.Ready,
+		onEnd:      config.OnEnd,
+		logger:     config.Logger.With(zap.String("stream_id", config.StreamID)),
+		codec:      codec,
+		ctx:        ctx,
+		ctxCancel:  cancel,
+		commands:   make(chan playerCommand),
+		done:       make(chan struct{}),
+		start:      config.Start,
+		rate:       rate,
+		position:   config.Start,
+		needAnchor: true,
+		wallOrigin: time.Now(),
+		rtpOrigin:  rand.Uint32(),
+	}
+	p.publishState()
+
+	switch codec {
+	case "H264":
+		p.h264Encoder = &rtph264.Encoder{
+			PayloadType:       96,
+			PacketizationMode: 1,
+			PayloadMaxSize:    rtpPayloadMaxSize,
+		}
+		err = p.h264Encoder.Init()
+	case "H265":
+		p.h265Encoder = &rtph265.Encoder{
+			PayloadType:    96,
+			PayloadMaxSize: rtpPayloadMaxSize,
+		}
+		err = p.h265Encoder.Init()
+	}
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create RTP encoder: %w", err)
+	}
+
+	return p, nil
+}
+
+// Codec은 녹화된 비디오 코덱을 반환합니다 (H264 또는 H265)
+func (p *Player) Codec() string {
+	return p.codec
+}
+
+// Start는 재생 루프를 시작합니다 (한 번만 실행됨)
+func (p *Player) Start() {
+	p.startOnce.Do(func() {
+		go p.run()
+	})
+}
+
+// Seek은 재생 위치를 변경합니다
+func (p *Player) Seek(t time.Time) PlayerState {
+	return p.sendCommand(playerCommand{seek: &t})
+}
+
+// Pause는 재생을 일시정지합니다
+func (p *Player) Pause() PlayerState {
+	paused := true
+	return p.sendCommand(playerCommand{pause: &paused})
+}
+
+// Resume은 일시정지된 재생을 재개합니다
+func (p *Player) Resume() PlayerState {
+	paused := false
+	return p.sendCommand(playerCommand{pause: &paused})
+}
+
+// SetRate는 재생 속도를 변경합니다
+func (p *Player) SetRate(rate float64) (PlayerState, error) {
+	if rate < minPlaybackRate || rate > maxPlaybackRate {
+		return p.State(), fmt.Errorf("invalid playback rate: %v (allowed %v-%v)",
+			rate, minPlaybackRate, maxPlaybackRate)
+	}
+	return p.sendCommand(playerCommand{rate: rate}), nil
+}
+
+// State는 현재 재생 상태를 반환합니다
+func (p *Player) State() PlayerState {
+	p.stateMutex.RLock()
+	defer p.stateMutex.RUnlock()
+	return p.state
+}
+
+// Close는 재생을 종료하고 재생 루프가 끝날 때까지 기다립니다
+func (p *Player) Close() {
+	p.ctxCancel()
+	p.startOnce.Do(func() { close(p.done) }) // 시작되지 않은 경우
+	<-p.done
+}
+
+// sendCommand는 재생 루프에 명령을 전달하고 변경된 상태를 반환합니다
+func (p *Player) sendCommand(cmd playerCommand) PlayerState {
+	cmd.result = make(chan PlayerState, 1)
+
+	select {
+	case p.commands <- cmd:
+	case <-p.ctx.Done():
+		return p.State()
+	}
+
+	select {
+	case state := <-cmd.result:
+		return state
+	case <-p.ctx.Done():
+		return p.State()
+	}
+}
+
+// run은 재생 루프입니다
+func (p *Player) run() {
+	defer close(p.done)
+
+	if err := p.waitReady(); err != nil {
+		return
+	}
+
+	for {
+		err := p.playFrom(p.start)
+
+		switch {
+		case errors.Is(err, errClosed):
+			return
+
+		case errors.Is(err, errSeek):
+			continue
+
+		case err != nil && !errors.Is(err, ErrNoSegmentsFound):
+			p.logger.Error("Playback failed", zap.Error(err))
+		}
+
+		// 현재 연속 구간이 끝남 → 다음 녹화 구간으로 이동
+		if next, ok := p.nextSpanStart(); ok {
+			p.start = next
+			p.needAnchor = true
+			continue
+		}
+
+		// 녹화 끝 도달: seek 명령 또는 종료까지 대기
+		p.ended = true
+		state := p.publishState()
+		if p.onEnd != nil {
+			p.onEnd(state)
+		}
+
+		if err := p.waitCommand(); errors.Is(err, errClosed) {
+			return
+		}
+	}
+}
+
+// playFrom은 지정된 시각부터 연속된 녹화 구간을 재생합니다
+func (p *Player) playFrom(start time.Time) error {
+	segments, err := p.manager.findSegments(p.streamID, &start, nil)
+	if err != nil {
+		return err
+	}
+
+	return seekAndMux(segments, start, maxPlayDuration, &rtpMuxer{player: p, start: start})
+}
+
+// nextSpanStart는 현재 위치 이후의 다음 녹화 구간 시작 시각을 찾습니다
+func (p *Player) nextSpanStart() (time.Time, bool) {
+	pos := p.position
+	entries, err := p.manager.List(p.streamID, &pos, nil)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	for _, entry := range entries {
+		if entry.Start.After(pos.Add(concatenationTolerance)) {
+			return entry.Start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// waitReady는 싱크가 준비될 때까지 제어 명령을 처리하며 대기합니다
+func (p *Player) waitReady() error {
+	if p.ready == nil {
+		return nil
+	}
+
+	for {
+		select {
+		case <-p.ready:
+			return nil
+		case <-p.ctx.Done():
+			return errClosed
+		case cmd := <-p.commands:
+			// 시작 전 seek은 시작 위치만 변경
+			_ = p.handleCommand(cmd)
+		}
+	}
+}
+
+// waitCommand는 녹화 끝에서 seek 명령을 기다립니다
+func (p *Player) waitCommand() error {
+	for {
+		select {
+		case <-p.ctx.Done():
+			return errClosed
+		case cmd := <-p.commands:
+			if err := p.handleCommand(cmd); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleCommand는 재생 제어 명령을 처리합니다
+// seek인 경우 errSeek를 반환하여 현재 재생을 중단합니다
+func (p *Player) handleCommand(cmd playerCommand) error {
+	var err error
+
+	switch {
+	case cmd.seek != nil:
+		p.start = *cmd.seek
+		p.position = *cmd.seek
+		p.needAnchor = true
+		p.ended = false
+		err = errSeek
+
+	case cmd.pause != nil:
+		if p.paused && !*cmd.pause {
+			// 재개 시 현재 샘플부터 시계를 다시 맞춤
+			p.needAnchor = true
+		}
+		p.paused = *cmd.pause
+
+	case cmd.rate > 0:
+		p.rate = cmd.rate
+		p.needAnchor = true
+	}
+
+	cmd.result <- p.publishState()
+	return err
+}
+
+// waitUntil은 샘플 전송 시각까지 대기하며 제어 명령을 처리합니다
+// 반환값은 샘플의 예정 전송 시각입니다
+func (p *Player) waitUntil(dts time.Duration) (time.Time, error) {
+	for {
+		if p.paused {
+			select {
+			case <-p.ctx.Done():
+				return time.Time{}, errClosed
+			case cmd := <-p.commands:
+				if err := p.handleCommand(cmd); err != nil {
+					return time.Time{}, err
+				}
+			}
+			continue
+		}
+
+		if p.needAnchor {
+			p.needAnchor = false
+			p.anchorWall = time.Now()
+			p.anchorDTS = dts
+		}
+
+		scheduled := p.anchorWall.Add(time.Duration(float64(dts-p.anchorDTS) / p.rate))
+		wait := time.Until(scheduled)
+		if wait <= 0 {
+			return scheduled, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-p.ctx.Done():
+			timer.Stop()
+			return time.Time{}, errClosed
+		case cmd := <-p.commands:
+			timer.Stop()
+			if err := p.handleCommand(cmd); err != nil {
+				return time.Time{}, err
+			}
+		case <-timer.C:
+			return scheduled, nil
+		}
+	}
+}
+
+// rtpTimestamp는 예정 전송 시각을 RTP 타임스탬프로 변환합니다
+// wall clock 기준으로 계산하므로 seek/pause/rate 변경 후에도 단조 증가합니다
+func (p *Player) rtpTimestamp(scheduled time.Time, ptsOffset time.Duration) uint32 {
+	elapsed := scheduled.Sub(p.wallOrigin) + time.Duration(float64(ptsOffset)/p.rate)
+	return p.rtpOrigin + uint32(durationGoToMp4(elapsed, rtpClockRate))
+}
+
+// sendAccessUnit은 AU를 RTP 패킷으로 변환하여 전송합니다
+func (p *Player) sendAccessUnit(au [][]byte, ts uint32) {
+	var pkts []*rtp.Packet
+	var err error
+
+	if p.h265Encoder != nil {
+		pkts, err = p.h265Encoder.Encode(au)
+	} else {
+		pkts, err = p.h264Encoder.Encode(au)
+	}
+	if err != nil {
+		p.logger.Debug("Failed to encode access unit", zap.Error(err))
+		return
+	}
+
+	for _, pkt := range pkts {
+		pkt.Timestamp = ts
+		if err := p.sink.OnPacket(pkt); err != nil {
+			p.logger.Debug("Failed to send playback packet", zap.Error(err))
+			return
+		}
+	}
+}
+
+// publishState는 외부에서 조회할 수 있도록 상태를 갱신합니다
+func (p *Player) publishState() PlayerState {
+	state := PlayerState{
+		Position: p.position,
+		Paused:   p.paused,
+		Rate:     p.rate,
+		Ended:    p.ended,
+	}
+
+	p.stateMutex.Lock()
+	p.state = state
+	p.stateMutex.Unlock()
+
+	return state
+}
+
+// rtpMuxer는 세그먼트 샘플을 실시간 RTP 전송으로 변환하는 muxer 구현입니다
+type rtpMuxer struct {
+	player *Player
+	start  time.Time
+
+	videoTrackID int
+	timeScale    uint32
+	params       [][]byte // 키프레임 앞에 붙일 파라미터 셋
+	isVideo      bool
+
+	started bool
+	gop     []*rtpSample
+}
+
+// rtpSample은 전송 대기 중인 샘플
+type rtpSample struct {
+	dts             time.Duration
+	ptsOffset       time.Duration
+	isNonSyncSample bool
+	getPayload      func() ([]byte, error)
+}
+
+func (m *rtpMuxer) writeInit(init *fmp4.Init) {
+	for _, track := range init.Tracks {
+		switch codec := track.Codec.(type) {
+		case *fmp4.CodecH264:
+			m.params = [][]byte{codec.SPS, codec.PPS}
+		case *fmp4.CodecH265:
+			m.params = [][]byte{codec.VPS, codec.SPS, codec.PPS}
+		default:
+			continue
+		}
+		m.videoTrackID = track.ID
+		m.timeScale = track.TimeScale
+		return
+	}
+}
+
+func (m *rtpMuxer) setTrack(trackID int) {
+	m.isVideo = trackID == m.videoTrackID
+}
+
+func (m *rtpMuxer) writeSample(
+	dts int64,
+	ptsOffset int32,
+	isNonSyncSample bool,
+	_ uint32,
+	getPayload func() ([]byte, error),
+) error {
+	if !m.isVideo {
+		return nil
+	}
+
+	sample := &rtpSample{
+		dts:             durationMp4ToGo(dts, m.timeScale),
+		ptsOffset:       durationMp4ToGo(int64(ptsOffset), m.timeScale),
+		isNonSyncSample: isNonSyncSample,
+		getPayload:      getPayload,
+	}
+
+	if !m.started {
+		// 요청 시각 이전 샘플은 마지막 GOP만 보관 (키프레임부터 재생하기 위함)
+		if !isNonSyncSample {
+			m.gop = m.gop[:0]
+		} else if len(m.gop) == 0 {
+			return nil
+		}
+		m.gop = append(m.gop, sample)
+
+		if dts < 0 {
+			return nil
+		}
+
+		m.started = true
+		gop := m.gop
+		m.gop = nil
+
+		for _, s := range gop {
+			if err := m.sendSample(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return m.sendSample(sample)
+}
+
+// sendSample은 전송 시각까지 대기한 후 샘플을 RTP로 전송합니다
+func (m *rtpMuxer) sendSample(sample *rtpSample) error {
+	p := m.player
+
+	scheduled, err := p.waitUntil(sample.dts)
+	if err != nil {
+		return err
+	}
+
+	payload, err := sample.getPayload()
+	if err != nil {
+		return err
+	}
+
+	var avcc h264.AVCC
+	if err := avcc.Unmarshal(payload); err != nil {
+		p.logger.Debug("Invalid sample payload", zap.Error(err))
+		return nil
+	}
+
+	au := [][]byte(avcc)
+	if !sample.isNonSyncSample {
+		au = append(append([][]byte{}, m.params...), au...)
+	}
+
+	p.sendAccessUnit(au, p.rtpTimestamp(scheduled, sample.ptsOffset))
+
+	p.position = m.start.Add(sample.dts)
+	p.publishState()
+
+	return nil
+}
+
+func (m *rtpMuxer) writeFinalDTS(_ int64) {
+}
+
+func (m *rtpMuxer) flush() error {
+	return nil
+}
+
+// readSegmentCodec은 세그먼트의 비디오 코덱을 확인합니다
+func readSegmentCodec(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	init, _, err := segmentFMP4ReadHeader(f)
+	if err != nil {
+		return "", err
+	}
+
+	for _, track := range init.Tracks {
+		switch track.Codec.(type) {
+		case *fmp4.CodecH264:
+			return "H264", nil
+		case *fmp4.CodecH265:
+			return "H265", nil
+		}
+	}
+
+	return "", fmt.Errorf("no supported video track in recording")
+}
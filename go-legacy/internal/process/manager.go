@@ -1,9 +1,12 @@
 package process
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,6 +18,7 @@ type Process struct {
 	ID           string
 	Cmd          *exec.Cmd
 	Command      string
+	Env          []string // 추가 환경 변수 ("KEY=value", 재시작 시에도 유지)
 	Restart      bool
 	CloseAfter   time.Duration
 	cancelFunc   context.CancelFunc
@@ -22,11 +26,18 @@ type Process struct {
 	mu           sync.RWMutex
 }
 
-// Manager는 runOnDemand 프로세스를 관리합니다
+// maxStderrLength는 일회성 명령 실패 시 에러 메시지에 포함할 표준 에러 최대 길이
+const maxStderrLength = 500
+
+// Manager는 runOnDemand/경로 훅 프로세스와 일회성 명령(스냅샷 디코딩 등)을 관리합니다
 type Manager struct {
 	processes map[string]*Process
 	mu        sync.RWMutex
 	logger    *zap.Logger
+
+	// 실행 중인 일회성 명령 (StopAll 시 취소)
+	runs    map[uint64]context.CancelFunc
+	nextRun uint64
 }
 
 // NewManager는 새로운 프로세스 매니저를 생성합니다
@@ -34,11 +45,70 @@ func NewManager(logger *zap.Logger) *Manager {
 	return &Manager{
 		processes: make(map[string]*Process),
 		logger:    logger,
+		runs:      make(map[uint64]context.CancelFunc),
+	}
+}
+
+// Run은 일회성 명령을 실행하고 표준 출력을 반환합니다
+// stdin은 명령의 표준 입력으로, env는 추가 환경 변수("KEY=value")로 전달됩니다
+// ctx가 끝나거나 StopAll이 호출되면 명령을 종료합니다
+func (m *Manager) Run(ctx context.Context, id, command string, env []string, stdin []byte) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m.mu.Lock()
+	runID := m.nextRun
+	m.nextRun++
+	m.runs[runID] = cancel
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.runs, runID)
+		m.mu.Unlock()
+	}()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	setProcessGroup(cmd)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// 종료된 명령의 자식 프로세스가 파이프를 잡고 있어도 대기하지 않음
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		message := strings.TrimSpace(stderr.String())
+		if len(message) > maxStderrLength {
+			message = message[:maxStderrLength] + "..."
+		}
+		if message != "" {
+			return nil, fmt.Errorf("command %s failed: %w: %s", id, err, message)
+		}
+		return nil, fmt.Errorf("command %s failed: %w", id, err)
 	}
+
+	m.logger.Debug("Command completed",
+		zap.String("id", id),
+		zap.Int("output_bytes", stdout.Len()),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+
+	return stdout.Bytes(), nil
 }
 
-// Start는 새로운 프로세스를 시작합니다
-func (m *Manager) Start(id, command string, restart bool, closeAfter time.Duration) error {
+// Start는 새로운 프로세스를 시작합니다 (env는 추가 환경 변수 "KEY=value")
+func (m *Manager) Start(id, command string, env []string, restart bool, closeAfter time.Duration) error {
+	return m.start(id, command, env, restart, closeAfter, time.Now())
+}
+
+// start는 프로세스를 시작합니다 (재시작 시에는 이전 활동 시간을 유지해 비활동 종료가 미뤄지지 않도록 함)
+func (m *Manager) start(id, command string, env []string, restart bool, closeAfter time.Duration, lastActivity time.Time) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -53,6 +123,8 @@ func (m *Manager) Start(id, command string, restart bool, closeAfter time.Durati
 
 	// 프로세스 생성
 	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	setProcessGroup(cmd)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
@@ -66,10 +138,11 @@ func (m *Manager) Start(id, command string, restart bool, closeAfter time.Durati
 		ID:           id,
 		Cmd:          cmd,
 		Command:      command,
+		Env:          env,
 		Restart:      restart,
 		CloseAfter:   closeAfter,
 		cancelFunc:   cancel,
-		lastActivity: time.Now(),
+		lastActivity: lastActivity,
 	}
 
 	m.processes[id] = proc
@@ -150,25 +223,35 @@ func (m *Manager) monitorProcess(proc *Process) {
 		zap.Error(err),
 	)
 
+	// 프로세스 목록에서 제거 (Stop으로 이미 제거되었으면 재시작하지 않음)
+	m.mu.Lock()
+	current := m.processes[proc.ID] == proc
+	if current {
+		delete(m.processes, proc.ID)
+	}
+	m.mu.Unlock()
+
+	if !current {
+		return
+	}
+
 	// 재시작이 필요한 경우
 	if proc.Restart && err != nil {
 		m.logger.Info("Restarting process", zap.String("id", proc.ID))
 		time.Sleep(2 * time.Second) // 재시작 전 잠시 대기
 
+		proc.mu.RLock()
+		lastActivity := proc.lastActivity
+		proc.mu.RUnlock()
+
 		// 재시작 시도
-		if err := m.Start(proc.ID, proc.Command, proc.Restart, proc.CloseAfter); err != nil {
+		if err := m.start(proc.ID, proc.Command, proc.Env, proc.Restart, proc.CloseAfter, lastActivity); err != nil {
 			m.logger.Error("Failed to restart process",
 				zap.String("id", proc.ID),
 				zap.Error(err),
 			)
 		}
-		return
 	}
-
-	// 프로세스 목록에서 제거
-	m.mu.Lock()
-	delete(m.processes, proc.ID)
-	m.mu.Unlock()
 }
 
 // StartInactivityMonitor는 비활동 시간을 체크하는 고루틴을 시작합니다
@@ -220,13 +303,16 @@ func (m *Manager) checkInactiveProcesses() {
 	}
 }
 
-// StopAll은 모든 프로세스를 중지합니다
+// StopAll은 모든 프로세스와 실행 중인 일회성 명령을 중지합니다
 func (m *Manager) StopAll() {
 	m.mu.Lock()
 	ids := make([]string, 0, len(m.processes))
 	for id := range m.processes {
 		ids = append(ids, id)
 	}
+	for _, cancel := range m.runs {
+		cancel()
+	}
 	m.mu.Unlock()
 
 	for _, id := range ids {
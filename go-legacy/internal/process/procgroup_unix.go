@@ -0,0 +1,17 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup은 명령을 새 프로세스 그룹에서 실행하고, 취소 시 그룹 전체를 종료하도록 설정합니다
+// sh -c로 실행한 명령의 자식 프로세스(ffmpeg 등)가 남지 않도록 합니다
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
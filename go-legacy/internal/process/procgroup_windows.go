@@ -0,0 +1,8 @@
+//go:build windows
+
+package process
+
+import "os/exec"
+
+// setProcessGroup은 Windows에서는 아무것도 하지 않습니다 (취소 시 sh 프로세스만 종료)
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,300 @@
+package recorder
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/yourusername/cctv3/internal/core"
+	"go.uber.org/zap"
+)
+
+// maxPreRollPackets는 pre-roll 버퍼의 최대 패킷 수 (키프레임이 오지 않는 스트림의 메모리 보호)
+const maxPreRollPackets = 20000
+
+// bufferedPacket은 pre-roll 버퍼에 보관된 RTP 패킷입니다
+type bufferedPacket struct {
+	pkt      *rtp.Packet
+	received time.Time
+}
+
+// eventRecorder는 이벤트 녹화용 구독자입니다 (core.StreamSubscriber 구현)
+// 평소에는 최근 pre-roll 구간을 GOP 단위로 보관하고, 트리거되면 보관한 GOP부터 녹화를 시작해
+// 마지막 트리거 후 post-roll이 지나면 녹화를 마치고 다시 보관 상태로 돌아갑니다
+type eventRecorder struct {
+	id       string
+	streamID string
+	stream   *core.Stream
+	preRoll  time.Duration
+	postRoll time.Duration
+	newRec   func() *Recorder
+	logger   *zap.Logger
+
+	mutex  sync.Mutex
+	closed bool
+
+	// pre-roll 버퍼 (첫 패킷은 항상 GOP 시작)
+	packets   []bufferedPacket
+	gopStarts []int // packets 내 GOP 시작 위치
+	prevParam bool  // 직전 패킷이 파라미터 셋(SPS/PPS/VPS)인지
+
+	// 녹화 중 상태
+	recorder *Recorder
+	stopAt   time.Time
+	timer    *time.Timer
+}
+
+// newEventRecorder는 새로운 이벤트 녹화 구독자를 생성합니다
+func newEventRecorder(stream *core.Stream, config Config, logger *zap.Logger) *eventRecorder {
+	streamID := stream.GetID()
+	return &eventRecorder{
+		id:       "event-recorder-" + streamID,
+		streamID: streamID,
+		stream:   stream,
+		preRoll:  config.EventPreRoll,
+		postRoll: config.EventPostRoll,
+		newRec: func() *Recorder {
+			return NewRecorder(RecorderConfig{
+				Dir:             filepath.Join(config.Path, streamID),
+				StreamID:        streamID,
+				Stream:          stream,
+				SegmentDuration: config.SegmentDuration,
+				PartDuration:    config.PartDuration,
+				OnComplete:      config.OnSegmentComplete,
+				Logger:          logger,
+			})
+		},
+		logger: logger.With(zap.String("stream_id", streamID)),
+	}
+}
+
+// GetID는 구독자 ID를 반환합니다 (core.StreamSubscriber 인터페이스)
+func (e *eventRecorder) GetID() string {
+	return e.id
+}
+
+// OnPacket은 녹화 중이면 녹화기로 전달하고, 아니면 pre-roll 버퍼에 보관합니다 (core.StreamSubscriber 인터페이스)
+func (e *eventRecorder) OnPacket(pkt *rtp.Packet) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.closed {
+		return nil
+	}
+	if e.recorder != nil {
+		return e.recorder.OnPacket(pkt)
+	}
+
+	e.buffer(pkt, time.Now())
+	return nil
+}
+
+// buffer는 패킷을 pre-roll 버퍼에 추가하고 오래된 GOP를 버립니다
+func (e *eventRecorder) buffer(pkt *rtp.Packet, now time.Time) {
+	param, keyframe := classifyPacket(e.stream.GetVideoCodec(), pkt.Payload)
+
+	// 파라미터 셋이 앞에 있으면 파라미터 셋부터 GOP로 취급 (새 녹화기가 SPS/PPS를 받을 수 있도록)
+	gopStart := (param || keyframe) && !e.prevParam
+	e.prevParam = param
+
+	if gopStart {
+		e.trim(now)
+		e.gopStarts = append(e.gopStarts, len(e.packets))
+	} else if len(e.packets) == 0 {
+		// 첫 GOP 시작 전 패킷은 녹화할 수 없으므로 보관하지 않음
+		return
+	}
+
+	e.packets = append(e.packets, bufferedPacket{pkt: pkt, received: now})
+
+	if len(e.packets) > maxPreRollPackets {
+		e.dropGOPs(1)
+	}
+}
+
+// trim은 pre-roll 구간을 채우는 데 필요 없는 오래된 GOP를 버립니다
+func (e *eventRecorder) trim(now time.Time) {
+	cutoff := now.Add(-e.preRoll)
+
+	// cutoff 이전에 시작한 GOP 중 마지막 GOP부터 보관
+	keep := 0
+	for i, start := range e.gopStarts {
+		if e.packets[start].received.After(cutoff) {
+			break
+		}
+		keep = i
+	}
+	e.dropGOPs(keep)
+}
+
+// dropGOPs는 앞쪽 n개의 GOP를 버립니다 (GOP가 n개 이하이면 버퍼 전체)
+func (e *eventRecorder) dropGOPs(n int) {
+	if n <= 0 {
+		return
+	}
+	if n >= len(e.gopStarts) {
+		e.packets = nil
+		e.gopStarts = nil
+		return
+	}
+
+	offset := e.gopStarts[n]
+	e.packets = append([]bufferedPacket(nil), e.packets[offset:]...)
+
+	starts := e.gopStarts[n:]
+	e.gopStarts = make([]int, len(starts))
+	for i, start := range starts {
+		e.gopStarts[i] = start - offset
+	}
+}
+
+// trigger는 이벤트 녹화를 시작하거나 연장합니다
+func (e *eventRecorder) trigger() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.closed {
+		return
+	}
+
+	if e.recorder != nil {
+		e.stopAt = time.Now().Add(e.postRoll)
+		return
+	}
+
+	// 마지막 GOP 이후 trim되지 않은 오래된 GOP 제거 (pre-roll 직전 GOP부터 녹화)
+	now := time.Now()
+	e.stopAt = now.Add(e.postRoll)
+	e.trim(now)
+
+	rec := e.newRec()
+	for _, bp := range e.packets {
+		if err := rec.writePacket(bp.pkt, bp.received); err != nil {
+			e.logger.Debug("Failed to write pre-roll packet", zap.Error(err))
+		}
+	}
+
+	e.logger.Info("Event recording started",
+		zap.Int("pre_roll_packets", len(e.packets)),
+		zap.Duration("post_roll", e.postRoll),
+	)
+
+	e.recorder = rec
+	e.packets = nil
+	e.gopStarts = nil
+	e.prevParam = false
+	e.timer = time.AfterFunc(e.postRoll, e.checkStop)
+}
+
+// checkStop은 post-roll이 지났으면 녹화를 마치고, 연장되었으면 타이머를 다시 설정합니다
+func (e *eventRecorder) checkStop() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.recorder == nil {
+		return
+	}
+
+	if remaining := time.Until(e.stopAt); remaining > 0 {
+		e.timer.Reset(remaining)
+		return
+	}
+
+	e.recorder.Close()
+	e.recorder = nil
+	e.timer = nil
+
+	e.logger.Info("Event recording stopped")
+}
+
+// isRecording은 이벤트 녹화 중인지 확인합니다
+func (e *eventRecorder) isRecording() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.recorder != nil
+}
+
+// close는 진행 중인 녹화를 마무리하고 버퍼를 비웁니다
+func (e *eventRecorder) close() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.closed {
+		return
+	}
+	e.closed = true
+
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	if e.recorder != nil {
+		e.recorder.Close()
+		e.recorder = nil
+	}
+	e.packets = nil
+	e.gopStarts = nil
+}
+
+// classifyPacket은 RTP 페이로드가 파라미터 셋(SPS/PPS/VPS)이나 키프레임의 시작인지 확인합니다
+func classifyPacket(codec string, payload []byte) (param bool, keyframe bool) {
+	if len(payload) < 2 {
+		return false, false
+	}
+
+	if codec == "H265" {
+		return classifyH265(payload)
+	}
+	return classifyH264(payload)
+}
+
+// classifyH264는 H.264 RTP 페이로드(RFC 6184)를 분류합니다
+func classifyH264(payload []byte) (param bool, keyframe bool) {
+	nalType := payload[0] & 0x1f
+	switch nalType {
+	case 24: // STAP-A: 첫 번째 NAL 기준
+		if len(payload) < 4 {
+			return false, false
+		}
+		nalType = payload[3] & 0x1f
+	case 28: // FU-A: 시작 조각만
+		if payload[1]&0x80 == 0 {
+			return false, false
+		}
+		nalType = payload[1] & 0x1f
+	}
+
+	switch nalType {
+	case 7, 8: // SPS, PPS
+		return true, false
+	case 5: // IDR
+		return false, true
+	}
+	return false, false
+}
+
+// classifyH265는 H.265 RTP 페이로드(RFC 7798)를 분류합니다
+func classifyH265(payload []byte) (param bool, keyframe bool) {
+	nalType := (payload[0] >> 1) & 0x3f
+	switch nalType {
+	case 48: // AP: 첫 번째 NAL 기준
+		if len(payload) < 5 {
+			return false, false
+		}
+		nalType = (payload[4] >> 1) & 0x3f
+	case 49: // FU: 시작 조각만
+		if len(payload) < 3 || payload[2]&0x80 == 0 {
+			return false, false
+		}
+		nalType = payload[2] & 0x3f
+	}
+
+	switch {
+	case nalType >= 32 && nalType <= 34: // VPS, SPS, PPS
+		return true, false
+	case nalType >= 16 && nalType <= 21: // IRAP (BLA, IDR, CRA)
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,364 @@
+package recorder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"go.uber.org/zap"
+)
+
+const (
+	// segmentTimeFormat은 세그먼트 파일명에 사용되는 시작 시각 형식입니다
+	// 예: 2025-11-17_14-02-05.123456.mp4
+	segmentTimeFormat = "2006-01-02_15-04-05.000000"
+	segmentExt        = ".mp4"
+)
+
+// Config는 녹화 관리자 설정
+type Config struct {
+	Enabled         bool
+	Path            string        // 녹화 파일 저장 디렉토리
+	SegmentDuration time.Duration // 세그먼트 길이
+	PartDuration    time.Duration // fMP4 part 길이
+	DeleteAfter     time.Duration // 보관 기간 (0=무제한)
+
+	// 이벤트 녹화 (상시 녹화가 꺼진 스트림에서 이벤트 전후 구간만 녹화)
+	EventPreRoll  time.Duration // 이벤트 이전 보관 구간
+	EventPostRoll time.Duration // 마지막 이벤트 이후 녹화 유지 시간
+
+	// OnSegmentComplete는 세그먼트 파일 기록이 끝날 때마다 호출됩니다 (웹훅 알림 등)
+	OnSegmentComplete func(segment CompletedSegment)
+}
+
+// Segment는 디스크에 저장된 녹화 세그먼트 파일을 나타냅니다
+type Segment struct {
+	Path  string
+	Start time.Time
+}
+
+// CompletedSegment는 기록이 끝난 세그먼트 파일 정보입니다
+type CompletedSegment struct {
+	StreamID string
+	Path     string
+	Start    time.Time
+	Duration time.Duration
+	Size     int64
+}
+
+// Manager는 스트림별 녹화기를 관리합니다
+type Manager struct {
+	config Config
+	logger *zap.Logger
+
+	recorders      map[string]*Recorder      // streamID -> Recorder
+	eventRecorders map[string]*eventRecorder // streamID -> 이벤트 녹화 (pre-roll 버퍼)
+	mutex          sync.RWMutex
+}
+
+// NewManager는 새로운 녹화 관리자를 생성합니다
+func NewManager(config Config, logger *zap.Logger) *Manager {
+	if config.SegmentDuration <= 0 {
+		config.SegmentDuration = 60 * time.Second
+	}
+	if config.PartDuration <= 0 {
+		config.PartDuration = time.Second
+	}
+	if config.EventPostRoll <= 0 {
+		config.EventPostRoll = 10 * time.Second
+	}
+
+	return &Manager{
+		config:         config,
+		logger:         logger,
+		recorders:      make(map[string]*Recorder),
+		eventRecorders: make(map[string]*eventRecorder),
+	}
+}
+
+// IsEnabled는 녹화 활성화 여부를 반환합니다
+func (m *Manager) IsEnabled() bool {
+	return m.config.Enabled
+}
+
+// Path는 녹화 파일 저장 디렉토리를 반환합니다
+func (m *Manager) Path() string {
+	return m.config.Path
+}
+
+// Start는 스트림 녹화를 시작합니다 (이미 녹화 중이면 무시)
+func (m *Manager) Start(stream *core.Stream) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	streamID := stream.GetID()
+	if _, exists := m.recorders[streamID]; exists {
+		return nil
+	}
+
+	rec := NewRecorder(RecorderConfig{
+		Dir:             filepath.Join(m.config.Path, streamID),
+		StreamID:        streamID,
+		Stream:          stream,
+		SegmentDuration: m.config.SegmentDuration,
+		PartDuration:    m.config.PartDuration,
+		OnComplete:      m.config.OnSegmentComplete,
+		Logger:          m.logger,
+	})
+
+	if err := stream.Subscribe(rec); err != nil {
+		return fmt.Errorf("failed to subscribe recorder: %w", err)
+	}
+
+	m.recorders[streamID] = rec
+
+	m.logger.Info("Recording started",
+		zap.String("stream_id", streamID),
+		zap.String("dir", rec.dir),
+	)
+
+	return nil
+}
+
+// Stop은 스트림 녹화를 중지하고 현재 세그먼트를 마무리합니다
+func (m *Manager) Stop(stream *core.Stream) error {
+	m.mutex.Lock()
+	rec, exists := m.recorders[stream.GetID()]
+	if exists {
+		delete(m.recorders, stream.GetID())
+	}
+	m.mutex.Unlock()
+
+	if !exists {
+		return fmt.Errorf("stream %s is not being recorded", stream.GetID())
+	}
+
+	if err := stream.Unsubscribe(rec.GetID()); err != nil {
+		m.logger.Debug("Failed to unsubscribe recorder",
+			zap.String("stream_id", stream.GetID()),
+			zap.Error(err),
+		)
+	}
+	rec.Close()
+
+	m.logger.Info("Recording stopped", zap.String("stream_id", stream.GetID()))
+	return nil
+}
+
+// IsRecording은 스트림이 녹화 중인지 확인합니다
+func (m *Manager) IsRecording(streamID string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	_, exists := m.recorders[streamID]
+	return exists
+}
+
+// StopAll은 모든 녹화를 중지합니다
+func (m *Manager) StopAll() {
+	m.mutex.Lock()
+	recorders := m.recorders
+	m.recorders = make(map[string]*Recorder)
+	m.mutex.Unlock()
+
+	for _, rec := range recorders {
+		if err := rec.stream.Unsubscribe(rec.GetID()); err != nil {
+			m.logger.Debug("Failed to unsubscribe recorder",
+				zap.String("stream_id", rec.streamID),
+				zap.Error(err),
+			)
+		}
+		rec.Close()
+	}
+
+	m.mutex.Lock()
+	eventRecorders := m.eventRecorders
+	m.eventRecorders = make(map[string]*eventRecorder)
+	m.mutex.Unlock()
+
+	for _, er := range eventRecorders {
+		er.stream.Unsubscribe(er.GetID())
+		er.close()
+	}
+}
+
+// StartEventBuffer는 이벤트 녹화를 위해 스트림의 pre-roll 보관을 시작합니다 (이미 보관 중이면 무시)
+func (m *Manager) StartEventBuffer(stream *core.Stream) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	streamID := stream.GetID()
+	if _, exists := m.eventRecorders[streamID]; exists {
+		return nil
+	}
+
+	er := newEventRecorder(stream, m.config, m.logger)
+	if err := stream.Subscribe(er); err != nil {
+		return fmt.Errorf("failed to subscribe event recorder: %w", err)
+	}
+
+	m.eventRecorders[streamID] = er
+
+	m.logger.Info("Event recording buffer started",
+		zap.String("stream_id", streamID),
+		zap.Duration("pre_roll", m.config.EventPreRoll),
+	)
+
+	return nil
+}
+
+// StopEventBuffer는 pre-roll 보관을 중지하고 진행 중인 이벤트 녹화를 마무리합니다
+func (m *Manager) StopEventBuffer(stream *core.Stream) {
+	m.mutex.Lock()
+	er, exists := m.eventRecorders[stream.GetID()]
+	if exists {
+		delete(m.eventRecorders, stream.GetID())
+	}
+	m.mutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	if err := stream.Unsubscribe(er.GetID()); err != nil {
+		m.logger.Debug("Failed to unsubscribe event recorder",
+			zap.String("stream_id", stream.GetID()),
+			zap.Error(err),
+		)
+	}
+	er.close()
+}
+
+// TriggerEvent는 이벤트 녹화를 시작하거나 post-roll을 연장합니다
+// 이벤트 구간이 녹화되면(상시 녹화 포함) true, pre-roll 보관 중이 아닌 스트림(정지 상태 등)이면 false를 반환합니다
+func (m *Manager) TriggerEvent(streamID string) bool {
+	m.mutex.RLock()
+	_, recording := m.recorders[streamID]
+	er, buffering := m.eventRecorders[streamID]
+	m.mutex.RUnlock()
+
+	if recording {
+		return true
+	}
+	if !buffering {
+		return false
+	}
+
+	er.trigger()
+	return true
+}
+
+// IsEventRecording은 스트림이 이벤트 녹화 중인지 확인합니다
+func (m *Manager) IsEventRecording(streamID string) bool {
+	m.mutex.RLock()
+	er, exists := m.eventRecorders[streamID]
+	m.mutex.RUnlock()
+
+	return exists && er.isRecording()
+}
+
+// StartCleaner는 보관 기간이 지난 세그먼트를 주기적으로 삭제합니다
+func (m *Manager) StartCleaner(ctx context.Context) {
+	if m.config.DeleteAfter <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	m.deleteExpiredSegments()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.deleteExpiredSegments()
+		}
+	}
+}
+
+// deleteExpiredSegments는 보관 기간이 지난 세그먼트를 삭제합니다
+func (m *Manager) deleteExpiredSegments() {
+	entries, err := os.ReadDir(m.config.Path)
+	if err != nil {
+		return
+	}
+
+	threshold := time.Now().Add(-m.config.DeleteAfter)
+	deleted := 0
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		segments, err := ListSegments(m.config.Path, entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// 마지막 세그먼트는 현재 기록 중일 수 있으므로 제외
+		for i, seg := range segments {
+			if i == len(segments)-1 || !seg.Start.Before(threshold) {
+				break
+			}
+			if err := os.Remove(seg.Path); err != nil {
+				m.logger.Warn("Failed to delete expired segment",
+					zap.String("path", seg.Path),
+					zap.Error(err),
+				)
+				continue
+			}
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		m.logger.Info("Expired recording segments deleted", zap.Int("count", deleted))
+	}
+}
+
+// ListSegments는 스트림의 세그먼트 파일을 시작 시각 순으로 반환합니다
+func ListSegments(recordPath, streamID string) ([]*Segment, error) {
+	dir := filepath.Join(recordPath, streamID)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]*Segment, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), segmentExt) {
+			continue
+		}
+
+		start, err := time.ParseInLocation(segmentTimeFormat,
+			strings.TrimSuffix(entry.Name(), segmentExt), time.Local)
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, &Segment{
+			Path:  filepath.Join(dir, entry.Name()),
+			Start: start,
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Start.Before(segments[j].Start)
+	})
+
+	return segments, nil
+}
+
+// segmentFileName은 세그먼트 시작 시각으로 파일명을 생성합니다
+func segmentFileName(start time.Time) string {
+	return start.Local().Format(segmentTimeFormat) + segmentExt
+}
@@ -0,0 +1,532 @@
+package recorder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph265"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h265"
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/fmp4"
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/fmp4/seekablebuffer"
+	"github.com/pion/rtp"
+	"github.com/yourusername/cctv3/internal/core"
+	"go.uber.org/zap"
+)
+
+const (
+	// videoTimeScale는 비디오 트랙의 타임스케일 (RTP 클럭과 동일)
+	videoTimeScale = 90000
+	// videoTrackID는 fMP4 비디오 트랙 ID
+	videoTrackID = 1
+	// maxTimestampJump를 넘는 RTP 타임스탬프 변화는 불연속(재연결)으로 간주
+	maxTimestampJump = 10 * videoTimeScale
+)
+
+// rtpDecoder는 RTP 패킷을 Access Unit으로 조립하는 디코더 인터페이스
+type rtpDecoder interface {
+	Decode(pkt *rtp.Packet) ([][]byte, error)
+}
+
+// dtsExtractor는 PTS로부터 DTS를 계산하는 인터페이스
+type dtsExtractor interface {
+	Extract(au [][]byte, pts int64) (int64, error)
+}
+
+// RecorderConfig는 스트림 녹화기 설정
+type RecorderConfig struct {
+	Dir             string
+	StreamID        string
+	Stream          *core.Stream
+	SegmentDuration time.Duration
+	PartDuration    time.Duration
+	OnComplete      func(segment CompletedSegment) // 세그먼트 파일을 닫을 때 호출 (nil 허용)
+	Logger          *zap.Logger
+}
+
+// Recorder는 단일 스트림을 fMP4 세그먼트 파일로 녹화합니다 (core.StreamSubscriber 구현)
+type Recorder struct {
+	id              string
+	dir             string
+	streamID        string
+	stream          *core.Stream
+	segmentDuration int64 // 90kHz 단위
+	partDuration    int64 // 90kHz 단위
+	onComplete      func(segment CompletedSegment)
+	logger          *zap.Logger
+
+	mutex  sync.Mutex
+	closed bool
+
+	// 디코딩 상태
+	codec     string
+	decoder   rtpDecoder
+	extractor dtsExtractor
+	videoPT   uint8
+	ptKnown   bool
+	lastTS    uint32
+	pts       int64
+	tsStarted bool
+
+	// 현재 패킷 수신 시각 (세그먼트 시작 시각 계산용, pre-roll 재생 시 과거 시각)
+	received time.Time
+
+	// 파라미터 셋 (fMP4 init 생성용)
+	vps []byte
+	sps []byte
+	pps []byte
+
+	// 현재 세그먼트
+	segment *segment
+}
+
+// segment는 기록 중인 fMP4 세그먼트 파일
+type segment struct {
+	file     *os.File
+	path     string
+	start    time.Time
+	startDTS int64
+
+	nextSequenceNumber uint32
+	partStartDTS       int64
+	partSamples        []*fmp4.Sample
+
+	// 마지막 샘플은 다음 샘플의 DTS가 도착해야 길이를 알 수 있음
+	pending        *fmp4.Sample
+	pendingDTS     int64
+	lastSampleDiff int64
+
+	buf seekablebuffer.Buffer
+}
+
+// NewRecorder는 새로운 스트림 녹화기를 생성합니다
+func NewRecorder(config RecorderConfig) *Recorder {
+	return &Recorder{
+		id:              "recorder-" + config.StreamID,
+		dir:             config.Dir,
+		streamID:        config.StreamID,
+		stream:          config.Stream,
+		segmentDuration: durationToTimeScale(config.SegmentDuration),
+		partDuration:    durationToTimeScale(config.PartDuration),
+		onComplete:      config.OnComplete,
+		logger:          config.Logger.With(zap.String("stream_id", config.StreamID)),
+	}
+}
+
+// GetID는 구독자 ID를 반환합니다 (core.StreamSubscriber 인터페이스)
+func (r *Recorder) GetID() string {
+	return r.id
+}
+
+// OnPacket은 Stream으로부터 RTP 패킷을 받습니다 (core.StreamSubscriber 인터페이스)
+func (r *Recorder) OnPacket(pkt *rtp.Packet) error {
+	return r.writePacket(pkt, time.Now())
+}
+
+// writePacket은 received 시각에 수신한 RTP 패킷을 기록합니다
+func (r *Recorder) writePacket(pkt *rtp.Packet, received time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.received = received
+
+	// 코덱 확인 (코덱이 바뀌면 디코더와 세그먼트 초기화)
+	codec := r.stream.GetVideoCodec()
+	if codec == "" {
+		return nil
+	}
+	if codec != r.codec || r.decoder == nil {
+		r.resetDecoding()
+		if err := r.initDecoder(codec); err != nil {
+			return err
+		}
+	}
+
+	// 비디오 페이로드 타입이 확정된 후에는 다른 트랙(오디오) 패킷 무시
+	if r.ptKnown && pkt.PayloadType != r.videoPT {
+		return nil
+	}
+
+	// RTP 타임스탬프 → 누적 PTS (wrap-around 처리)
+	if !r.tsStarted {
+		r.tsStarted = true
+		r.lastTS = pkt.Timestamp
+	} else {
+		diff := int64(int32(pkt.Timestamp - r.lastTS))
+		if diff > maxTimestampJump || diff < -maxTimestampJump {
+			r.logger.Info("RTP timestamp discontinuity detected, starting new segment",
+				zap.Int64("diff", diff),
+			)
+			r.closeSegment()
+			r.extractor = nil
+		}
+		r.pts += diff
+		r.lastTS = pkt.Timestamp
+	}
+
+	au, err := r.decoder.Decode(pkt)
+	if err != nil {
+		if errors.Is(err, rtph264.ErrMorePacketsNeeded) ||
+			errors.Is(err, rtph265.ErrMorePacketsNeeded) ||
+			errors.Is(err, rtph264.ErrNonStartingPacketAndNoPrevious) ||
+			errors.Is(err, rtph265.ErrNonStartingPacketAndNoPrevious) {
+			return nil
+		}
+		r.logger.Debug("Failed to decode RTP packet for recording", zap.Error(err))
+		return nil
+	}
+
+	if r.updateParams(au) && !r.ptKnown {
+		r.videoPT = pkt.PayloadType
+		r.ptKnown = true
+	}
+
+	return r.processAccessUnit(au, r.pts)
+}
+
+// Close는 녹화기를 종료하고 현재 세그먼트를 마무리합니다
+func (r *Recorder) Close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.closeSegment()
+}
+
+// initDecoder는 코덱에 맞는 RTP 디코더를 생성합니다
+func (r *Recorder) initDecoder(codec string) error {
+	switch codec {
+	case "H264":
+		forma := &format.H264{PayloadTyp: 96, PacketizationMode: 1}
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return fmt.Errorf("failed to create H264 decoder: %w", err)
+		}
+		r.decoder = dec
+
+	case "H265":
+		forma := &format.H265{PayloadTyp: 96}
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return fmt.Errorf("failed to create H265 decoder: %w", err)
+		}
+		r.decoder = dec
+
+	default:
+		return fmt.Errorf("unsupported codec for recording: %s", codec)
+	}
+
+	r.codec = codec
+	return nil
+}
+
+// resetDecoding은 디코딩 상태를 초기화합니다
+func (r *Recorder) resetDecoding() {
+	r.closeSegment()
+	r.decoder = nil
+	r.extractor = nil
+	r.ptKnown = false
+	r.tsStarted = false
+	r.pts = 0
+	r.vps, r.sps, r.pps = nil, nil, nil
+}
+
+// updateParams는 AU에서 파라미터 셋을 추출합니다
+// 파라미터 셋이 포함되어 있으면 true, 변경되었으면 현재 세그먼트를 닫습니다
+func (r *Recorder) updateParams(au [][]byte) bool {
+	found := false
+	changed := false
+
+	for _, nalu := range au {
+		if len(nalu) == 0 {
+			continue
+		}
+
+		var target *[]byte
+		if r.codec == "H264" {
+			switch h264.NALUType(nalu[0] & 0x1F) {
+			case h264.NALUTypeSPS:
+				target = &r.sps
+			case h264.NALUTypePPS:
+				target = &r.pps
+			}
+		} else {
+			switch h265.NALUType((nalu[0] >> 1) & 0x3F) {
+			case h265.NALUType_VPS_NUT:
+				target = &r.vps
+			case h265.NALUType_SPS_NUT:
+				target = &r.sps
+			case h265.NALUType_PPS_NUT:
+				target = &r.pps
+			}
+		}
+
+		if target == nil {
+			continue
+		}
+		found = true
+
+		if !bytes.Equal(*target, nalu) {
+			if *target != nil {
+				changed = true
+			}
+			*target = append([]byte(nil), nalu...)
+		}
+	}
+
+	if changed {
+		r.logger.Info("Parameter sets changed, starting new segment")
+		r.closeSegment()
+	}
+
+	return found
+}
+
+// hasParams는 init 생성에 필요한 파라미터 셋이 모두 있는지 확인합니다
+func (r *Recorder) hasParams() bool {
+	if r.codec == "H265" {
+		return r.vps != nil && r.sps != nil && r.pps != nil
+	}
+	return r.sps != nil && r.pps != nil
+}
+
+// isRandomAccess는 AU가 키프레임인지 확인합니다
+func (r *Recorder) isRandomAccess(au [][]byte) bool {
+	if r.codec == "H265" {
+		return h265.IsRandomAccess(au)
+	}
+	return h264.IsRandomAccess(au)
+}
+
+// processAccessUnit은 AU를 현재 세그먼트에 기록합니다
+func (r *Recorder) processAccessUnit(au [][]byte, pts int64) error {
+	randomAccess := r.isRandomAccess(au)
+
+	// 첫 키프레임 전에는 기록하지 않음
+	if r.extractor == nil {
+		if !randomAccess || !r.hasParams() {
+			return nil
+		}
+		if r.codec == "H265" {
+			ex := &h265.DTSExtractor{}
+			ex.Initialize()
+			r.extractor = ex
+		} else {
+			ex := &h264.DTSExtractor{}
+			ex.Initialize()
+			r.extractor = ex
+		}
+	}
+
+	dts, err := r.extractor.Extract(au, pts)
+	if err != nil {
+		r.logger.Debug("Failed to extract DTS, waiting for next keyframe", zap.Error(err))
+		r.extractor = nil
+		return nil
+	}
+
+	if r.segment == nil {
+		if !randomAccess {
+			return nil
+		}
+		if err := r.openSegment(r.received, dts); err != nil {
+			return err
+		}
+	} else if randomAccess && dts-r.segment.startDTS >= r.segmentDuration {
+		// 키프레임 경계에서 세그먼트 교체 (시작 시각은 이전 세그먼트에 이어서 계산)
+		nextStart := r.segment.start.Add(timeScaleToDuration(dts - r.segment.startDTS))
+		r.closeSegment()
+		if err := r.openSegment(nextStart, dts); err != nil {
+			return err
+		}
+	}
+
+	sample := &fmp4.Sample{}
+	if r.codec == "H265" {
+		err = sample.FillH265(int32(pts-dts), au)
+	} else {
+		err = sample.FillH264(int32(pts-dts), au)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create sample: %w", err)
+	}
+
+	return r.writeSample(sample, dts)
+}
+
+// openSegment는 새로운 세그먼트 파일을 생성하고 init을 기록합니다
+func (r *Recorder) openSegment(start time.Time, dts int64) error {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	path := filepath.Join(r.dir, segmentFileName(start))
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create segment file: %w", err)
+	}
+
+	var codec fmp4.Codec
+	if r.codec == "H265" {
+		codec = &fmp4.CodecH265{VPS: r.vps, SPS: r.sps, PPS: r.pps}
+	} else {
+		codec = &fmp4.CodecH264{SPS: r.sps, PPS: r.pps}
+	}
+
+	seg := &segment{
+		file:         file,
+		path:         path,
+		start:        start,
+		startDTS:     dts,
+		partStartDTS: dts,
+	}
+
+	init := fmp4.Init{
+		Tracks: []*fmp4.InitTrack{{
+			ID:        videoTrackID,
+			TimeScale: videoTimeScale,
+			Codec:     codec,
+		}},
+	}
+	if err := init.Marshal(&seg.buf); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to marshal init: %w", err)
+	}
+	if _, err := file.Write(seg.buf.Bytes()); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to write init: %w", err)
+	}
+	seg.buf.Reset()
+
+	r.segment = seg
+
+	r.logger.Debug("Recording segment opened", zap.String("path", path))
+	return nil
+}
+
+// writeSample은 샘플을 현재 part에 추가하고 part 길이를 넘으면 디스크에 기록합니다
+func (r *Recorder) writeSample(sample *fmp4.Sample, dts int64) error {
+	seg := r.segment
+
+	if seg.pending != nil {
+		diff := dts - seg.pendingDTS
+		if diff < 0 {
+			diff = 0
+		}
+		seg.pending.Duration = uint32(diff)
+		seg.lastSampleDiff = diff
+		seg.partSamples = append(seg.partSamples, seg.pending)
+
+		if dts-seg.partStartDTS >= r.partDuration {
+			if err := seg.flushPart(dts); err != nil {
+				r.logger.Error("Failed to write recording part", zap.Error(err))
+				r.closeSegment()
+				return err
+			}
+		}
+	}
+
+	seg.pending = sample
+	seg.pendingDTS = dts
+	return nil
+}
+
+// flushPart는 모인 샘플을 moof+mdat으로 기록합니다
+func (seg *segment) flushPart(nextPartDTS int64) error {
+	if len(seg.partSamples) == 0 {
+		return nil
+	}
+
+	part := fmp4.Part{
+		SequenceNumber: seg.nextSequenceNumber,
+		Tracks: []*fmp4.PartTrack{{
+			ID:       videoTrackID,
+			BaseTime: uint64(seg.partStartDTS - seg.startDTS),
+			Samples:  seg.partSamples,
+		}},
+	}
+	seg.nextSequenceNumber++
+
+	if err := part.Marshal(&seg.buf); err != nil {
+		return err
+	}
+	if _, err := seg.file.Write(seg.buf.Bytes()); err != nil {
+		return err
+	}
+	seg.buf.Reset()
+
+	seg.partSamples = nil
+	seg.partStartDTS = nextPartDTS
+	return nil
+}
+
+// closeSegment는 남은 샘플을 기록하고 세그먼트 파일을 닫습니다
+func (r *Recorder) closeSegment() {
+	seg := r.segment
+	if seg == nil {
+		return
+	}
+	r.segment = nil
+
+	// 마지막 샘플의 길이는 직전 샘플 길이로 추정
+	if seg.pending != nil {
+		seg.pending.Duration = uint32(seg.lastSampleDiff)
+		seg.partSamples = append(seg.partSamples, seg.pending)
+		seg.pending = nil
+	}
+
+	if err := seg.flushPart(seg.pendingDTS); err != nil {
+		r.logger.Error("Failed to write final recording part", zap.Error(err))
+	}
+
+	if err := seg.file.Close(); err != nil {
+		r.logger.Error("Failed to close segment file", zap.Error(err))
+	}
+
+	// 샘플이 하나도 기록되지 않은 세그먼트는 삭제
+	if seg.nextSequenceNumber == 0 {
+		os.Remove(seg.path)
+		return
+	}
+
+	r.logger.Debug("Recording segment closed", zap.String("path", seg.path))
+
+	if r.onComplete != nil {
+		completed := CompletedSegment{
+			StreamID: r.streamID,
+			Path:     seg.path,
+			Start:    seg.start,
+			Duration: timeScaleToDuration(seg.pendingDTS + seg.lastSampleDiff - seg.startDTS),
+		}
+		if info, err := os.Stat(seg.path); err == nil {
+			completed.Size = info.Size()
+		}
+		r.onComplete(completed)
+	}
+}
+
+// durationToTimeScale은 time.Duration을 90kHz 단위로 변환합니다
+func durationToTimeScale(d time.Duration) int64 {
+	return int64(d/time.Second)*videoTimeScale + int64(d%time.Second)*videoTimeScale/int64(time.Second)
+}
+
+// timeScaleToDuration은 90kHz 단위를 time.Duration으로 변환합니다
+func timeScaleToDuration(v int64) time.Duration {
+	return time.Duration(v/videoTimeScale)*time.Second +
+		time.Duration(v%videoTimeScale)*time.Second/videoTimeScale
+}
@@ -16,6 +16,8 @@ type PathManager struct {
 	streamManager *core.StreamManager
 	logger        *zap.Logger
 	mu            sync.RWMutex
+
+	server *gortsplib.Server // ServerStream 생성용 (ServerRTSP가 설정)
 }
 
 // Path는 단일 RTSP 경로를 나타냅니다
@@ -26,6 +28,7 @@ type Path struct {
 	subscribers   map[string]*Subscriber // session ID -> Subscriber
 	stream        *core.Stream           // Stream Manager의 스트림
 	serverStream  *gortsplib.ServerStream
+	server        *gortsplib.Server
 	medias        []*description.Media   // SDP medias
 	logger        *zap.Logger
 	mu            sync.RWMutex
@@ -78,6 +81,7 @@ func (pm *PathManager) GetOrCreatePath(pathName string) (*Path, error) {
 
 	path := &Path{
 		name:        streamID,
+		server:      pm.server,
 		stream:      stream,
 		subscribers: make(map[string]*Subscriber),
 		logger:      pm.logger,
@@ -275,10 +279,8 @@ func (p *Path) GetSDP() (*gortsplib.ServerStream, []byte, error) {
 		Medias: medias,
 	}
 
-	// gortsplib.ServerStream 생성
-	// v4에서는 Server 인스턴스와 description.Session이 필요
-	// 임시로 nil server 사용 (나중에 수정 필요)
-	p.serverStream = gortsplib.NewServerStream(nil, desc)
+	// gortsplib.ServerStream 생성 (v4에서는 시작된 Server 인스턴스가 필요)
+	p.serverStream = gortsplib.NewServerStream(p.server, desc)
 
 	// Medias 저장 (Subscriber에서 사용)
 	p.medias = medias
@@ -319,6 +321,44 @@ func (p *Path) RemoveSubscriber(sessionID string) {
 	}
 }
 
+// CloseSession은 종료된 RTSP 세션의 publisher/subscriber를 모든 경로에서 제거합니다
+// publisher가 제거되어야 같은 경로로 다시 publish할 수 있습니다 (트랜스코더 재시작 등)
+func (pm *PathManager) CloseSession(session *gortsplib.ServerSession) {
+	pm.mu.RLock()
+	paths := make([]*Path, 0, len(pm.paths))
+	for _, path := range pm.paths {
+		paths = append(paths, path)
+	}
+	pm.mu.RUnlock()
+
+	sessionID := fmt.Sprintf("%p", session)
+	for _, path := range paths {
+		path.closeSession(session, sessionID)
+	}
+}
+
+// closeSession은 세션이 이 경로의 publisher/subscriber이면 제거합니다
+func (p *Path) closeSession(session *gortsplib.ServerSession, sessionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.publisher != nil && p.publisher.session == session {
+		p.publisher.Close()
+		p.publisher = nil
+		p.logger.Info("Publisher removed", zap.String("path", p.name))
+	}
+
+	if subscriber, exists := p.subscribers[sessionID]; exists {
+		subscriber.Close()
+		delete(p.subscribers, sessionID)
+		p.logger.Info("Subscriber removed",
+			zap.String("path", p.name),
+			zap.String("session_id", sessionID),
+			zap.Int("remaining_subscribers", len(p.subscribers)),
+		)
+	}
+}
+
 // RemovePublisher는 publisher를 제거합니다
 func (p *Path) RemovePublisher() {
 	p.mu.Lock()
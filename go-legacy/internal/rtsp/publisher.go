@@ -11,6 +11,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// SessionType은 RTSP 세션 송출자/시청자 종류입니다 (경로 훅 MTX_SOURCE_TYPE, MTX_READER_TYPE)
+const SessionType = "rtspSession"
+
 // Publisher는 RTSP를 통해 스트림을 publish하는 클라이언트입니다
 // 예: ffmpeg가 트랜스코딩 결과를 publish
 type Publisher struct {
@@ -107,6 +110,8 @@ func (p *Publisher) Activate(ctx *gortsplib.ServerHandlerOnRecordCtx) error {
 		zap.String("path", p.path),
 	)
 
+	p.stream.SetReady(SessionType, fmt.Sprintf("%p", p.session))
+
 	return nil
 }
 
@@ -124,5 +129,8 @@ func (p *Publisher) handlePacket(mediaIndex int, pkt *rtp.Packet) {
 // Close는 publisher를 닫습니다
 func (p *Publisher) Close() {
 	p.logger.Info("Closing publisher", zap.String("path", p.path))
+	if p.active {
+		p.stream.SetNotReady()
+	}
 	p.active = false
 }
@@ -28,6 +28,8 @@ func GenerateSDPFromStream(stream *core.Stream, logger *zap.Logger) ([]byte, []*
 		medias = generateH264Media()
 	case "H265":
 		medias = generateH265Media()
+	case "MJPEG":
+		medias = generateMJPEGMedia()
 	default:
 		return nil, nil, fmt.Errorf("unsupported codec: %s", codec)
 	}
@@ -72,6 +74,16 @@ func generateH265Media() []*description.Media {
 	}
 }
 
+// generateMJPEGMedia는 MJPEG(HTTP MJPEG 소스)용 Media description을 생성합니다
+func generateMJPEGMedia() []*description.Media {
+	return []*description.Media{
+		{
+			Type:    description.MediaTypeVideo,
+			Formats: []format.Format{&format.MJPEG{}},
+		},
+	}
+}
+
 // generateSDPBytes는 Media description으로부터 SDP 바이트를 생성합니다
 func generateSDPBytes(medias []*description.Media) []byte {
 	// description.Session을 사용하여 SDP 생성
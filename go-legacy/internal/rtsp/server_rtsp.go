@@ -2,10 +2,19 @@ package rtsp
 
 import (
 	"fmt"
+	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bluenviron/gortsplib/v4"
+	rtspauth "github.com/bluenviron/gortsplib/v4/pkg/auth"
 	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/headers"
+	"github.com/bluenviron/gortsplib/v4/pkg/liberrors"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/auth"
 	"github.com/yourusername/cctv3/internal/core"
 	"go.uber.org/zap"
 )
@@ -20,6 +29,17 @@ type ServerRTSP struct {
 	logger        *zap.Logger
 	wg            sync.WaitGroup
 	ctx           *ServerContext
+	authManager   *auth.Manager
+	auditLogger   *audit.Logger
+	ensureStream  func(streamID, query string) error
+
+	// 종료 대기 중 (새 DESCRIBE/ANNOUNCE 거부)
+	draining atomic.Bool
+
+	// 재생 세션별 인증 정보와 시청 감사 세션
+	sessionMutex      sync.Mutex
+	sessionIdentities map[*gortsplib.ServerSession]*auth.Identity
+	sessionAudits     map[*gortsplib.ServerSession]*audit.Session
 }
 
 // ServerContext는 서버 컨텍스트를 저장합니다
@@ -32,6 +52,11 @@ type ServerRTSPConfig struct {
 	Address       string              // ":8554"
 	StreamManager *core.StreamManager
 	Logger        *zap.Logger
+	AuthManager   *auth.Manager // nil이면 인증 없음
+	AuditLogger   *audit.Logger // nil이면 시청 기록 없음
+
+	// EnsureStream은 DESCRIBE 응답 전에 호출됩니다 (runOnDemand, 파생 스트림의 트랜스코더 시작 등, nil이면 무시)
+	EnsureStream func(streamID, query string) error
 }
 
 // NewServerRTSP는 새로운 RTSP 서버를 생성합니다
@@ -41,6 +66,12 @@ func NewServerRTSP(config ServerRTSPConfig) *ServerRTSP {
 		streamManager: config.StreamManager,
 		logger:        config.Logger,
 		pathManager:   NewPathManager(config.StreamManager, config.Logger),
+		authManager:   config.AuthManager,
+		auditLogger:   config.AuditLogger,
+		ensureStream:  config.EnsureStream,
+
+		sessionIdentities: make(map[*gortsplib.ServerSession]*auth.Identity),
+		sessionAudits:     make(map[*gortsplib.ServerSession]*audit.Session),
 	}
 
 	s.ctx = &ServerContext{
@@ -51,7 +82,10 @@ func NewServerRTSP(config ServerRTSPConfig) *ServerRTSP {
 	s.server = &gortsplib.Server{
 		Handler:     s,
 		RTSPAddress: s.address,
+		// 비밀번호 자리에 API 키/JWT를 받으므로 Basic만 사용 (Digest는 평문 비교 불가)
+		AuthMethods: []rtspauth.VerifyMethod{rtspauth.VerifyMethodBasic},
 	}
+	s.pathManager.server = s.server
 
 	return s
 }
@@ -68,6 +102,12 @@ func (s *ServerRTSP) Start() error {
 	return nil
 }
 
+// Drain은 새 재생(DESCRIBE)과 송출(ANNOUNCE)을 거부합니다 (기존 세션은 Stop까지 유지)
+func (s *ServerRTSP) Drain() {
+	s.draining.Store(true)
+	s.logger.Info("RTSP server draining")
+}
+
 // Stop은 RTSP 서버를 중지합니다
 func (s *ServerRTSP) Stop() {
 	s.logger.Info("Stopping RTSP server")
@@ -102,6 +142,18 @@ func (s *ServerRTSP) OnSessionOpen(ctx *gortsplib.ServerHandlerOnSessionOpenCtx)
 // OnSessionClose는 세션 종료 시 호출됩니다 (gortsplib.ServerHandlerOnSessionClose)
 func (s *ServerRTSP) OnSessionClose(ctx *gortsplib.ServerHandlerOnSessionCloseCtx) {
 	s.logger.Info("RTSP session closed")
+
+	s.sessionMutex.Lock()
+	auditSession := s.sessionAudits[ctx.Session]
+	delete(s.sessionAudits, ctx.Session)
+	delete(s.sessionIdentities, ctx.Session)
+	s.sessionMutex.Unlock()
+
+	if auditSession != nil {
+		auditSession.End(ctx.Session.Stats().BytesSent)
+	}
+
+	s.pathManager.CloseSession(ctx.Session)
 }
 
 // OnDescribe는 DESCRIBE 요청 시 호출됩니다 (gortsplib.ServerHandlerOnDescribe)
@@ -113,6 +165,28 @@ func (s *ServerRTSP) OnDescribe(ctx *gortsplib.ServerHandlerOnDescribeCtx) (*bas
 		zap.String("remote_addr", ctx.Conn.NetConn().RemoteAddr().String()),
 	)
 
+	if s.draining.Load() {
+		return &base.Response{
+			StatusCode: base.StatusServiceUnavailable,
+		}, nil, nil
+	}
+
+	if _, res, err := s.authenticate(ctx.Request, pathName, auth.RoleViewer); err != nil {
+		return res, nil, err
+	}
+
+	if s.ensureStream != nil {
+		if err := s.ensureStream(strings.TrimPrefix(pathName, "/"), ctx.Query); err != nil {
+			s.logger.Warn("Failed to prepare stream for DESCRIBE",
+				zap.String("path", pathName),
+				zap.Error(err),
+			)
+			return &base.Response{
+				StatusCode: base.StatusNotFound,
+			}, nil, nil
+		}
+	}
+
 	// PathManager를 통해 SDP 생성
 	stream, sdp, err := s.pathManager.GetStreamSDP(pathName)
 	if err != nil {
@@ -144,6 +218,16 @@ func (s *ServerRTSP) OnAnnounce(ctx *gortsplib.ServerHandlerOnAnnounceCtx) (*bas
 		zap.String("remote_addr", ctx.Conn.NetConn().RemoteAddr().String()),
 	)
 
+	if s.draining.Load() {
+		return &base.Response{
+			StatusCode: base.StatusServiceUnavailable,
+		}, nil
+	}
+
+	if _, res, err := s.authenticate(ctx.Request, pathName, auth.RoleOperator); err != nil {
+		return res, err
+	}
+
 	// PathManager를 통해 Publisher 등록
 	if err := s.pathManager.RegisterPublisher(pathName, ctx); err != nil {
 		s.logger.Error("Failed to register publisher",
@@ -172,6 +256,25 @@ func (s *ServerRTSP) OnSetup(ctx *gortsplib.ServerHandlerOnSetupCtx) (*base.Resp
 		
 	)
 
+	// publish 세션의 SETUP은 ANNOUNCE에서 이미 인증됨
+	if ctx.Session.State() == gortsplib.ServerSessionStateInitial {
+		identity, res, err := s.authenticate(ctx.Request, pathName, auth.RoleViewer)
+		if err != nil {
+			return res, nil, err
+		}
+
+		s.sessionMutex.Lock()
+		s.sessionIdentities[ctx.Session] = identity
+		s.sessionMutex.Unlock()
+	}
+
+	// publish 세션은 ANNOUNCE한 미디어를 SETUP하므로 ServerStream 없이 응답 (gortsplib 요구사항)
+	if ctx.Session.State() == gortsplib.ServerSessionStatePreRecord {
+		return &base.Response{
+			StatusCode: base.StatusOK,
+		}, nil, nil
+	}
+
 	// PathManager에서 stream 가져오기
 	stream, err := s.pathManager.GetServerStream(pathName)
 	if err != nil {
@@ -213,6 +316,8 @@ func (s *ServerRTSP) OnPlay(ctx *gortsplib.ServerHandlerOnPlayCtx) (*base.Respon
 		zap.String("path", pathName),
 	)
 
+	s.startAudit(ctx)
+
 	return &base.Response{
 		StatusCode: base.StatusOK,
 	}, nil
@@ -246,3 +351,69 @@ func (s *ServerRTSP) OnRecord(ctx *gortsplib.ServerHandlerOnRecordCtx) (*base.Re
 		StatusCode: base.StatusOK,
 	}, nil
 }
+
+// startAudit는 재생 세션의 시청 시작을 감사 로그에 기록합니다 (PAUSE 후 재개는 제외)
+func (s *ServerRTSP) startAudit(ctx *gortsplib.ServerHandlerOnPlayCtx) {
+	if s.auditLogger == nil {
+		return
+	}
+
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+
+	if _, exists := s.sessionAudits[ctx.Session]; exists {
+		return
+	}
+
+	remoteAddr := ctx.Conn.NetConn().RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		remoteAddr = host
+	}
+
+	actor := audit.ActorFromIdentity(s.sessionIdentities[ctx.Session], remoteAddr)
+	s.sessionAudits[ctx.Session] = s.auditLogger.StartSession(actor, strings.TrimPrefix(ctx.Path, "/"), audit.ProtocolRTSP)
+}
+
+// authenticate는 RTSP 요청의 Basic 인증 정보를 확인하고 인증된 사용자를 반환합니다
+// 비밀번호 자리에 API 키 또는 JWT를 사용합니다 (예: rtsp://user:<api-key>@host:8554/CAM1)
+// 인증 정보가 없으면 WWW-Authenticate로 재요청하고, 틀리면 연결을 종료합니다
+func (s *ServerRTSP) authenticate(req *base.Request, pathName string, min auth.Role) (*auth.Identity, *base.Response, error) {
+	if !s.authManager.IsEnabled() {
+		return auth.Anonymous, nil, nil
+	}
+
+	var creds auth.Credentials
+	var header headers.Authorization
+	if err := header.Unmarshal(req.Header["Authorization"]); err == nil {
+		creds.User = header.Username
+		creds.Pass = header.BasicPass
+	}
+
+	identity, err := s.authManager.Authenticate(creds)
+	if err != nil {
+		if !creds.IsEmpty() {
+			s.logger.Warn("RTSP authentication failed",
+				zap.String("path", pathName),
+				zap.Error(err),
+			)
+			// 무차별 대입 방지
+			time.Sleep(auth.PauseAfterError)
+		}
+		return nil, &base.Response{
+			StatusCode: base.StatusUnauthorized,
+		}, liberrors.ErrServerAuth{}
+	}
+
+	if !identity.HasRole(min) || !identity.CanView(strings.TrimPrefix(pathName, "/")) {
+		s.logger.Warn("RTSP access denied",
+			zap.String("path", pathName),
+			zap.String("identity", identity.Name),
+			zap.String("role", string(identity.Role)),
+		)
+		return nil, &base.Response{
+			StatusCode: base.StatusForbidden,
+		}, fmt.Errorf("access to path '%s' is not allowed", pathName)
+	}
+
+	return identity, nil, nil
+}
@@ -78,6 +78,11 @@ func (s *Subscriber) GetID() string {
 	return s.subscriberID
 }
 
+// ReaderType은 시청자 종류를 반환합니다 (core.StreamReader 인터페이스, runOnRead 훅)
+func (s *Subscriber) ReaderType() string {
+	return SessionType
+}
+
 // OnPacket은 Stream으로부터 RTP 패킷을 받습니다 (core.StreamSubscriber 인터페이스)
 func (s *Subscriber) OnPacket(pkt *rtp.Packet) error {
 	return s.WritePacket(pkt)
@@ -0,0 +1,259 @@
+// Package secret은 카메라 인증 정보 등 민감한 값을 암호화합니다
+package secret
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const (
+	// KeyEnv는 현재 암호화 키(base64, 32바이트)를 지정하는 환경 변수
+	KeyEnv = "CCTV_SECRET_KEY"
+	// PreviousKeysEnv는 이전 암호화 키 목록(쉼표 구분)을 지정하는 환경 변수 (키 교체용)
+	PreviousKeysEnv = "CCTV_SECRET_KEY_PREVIOUS"
+
+	keySize   = 32
+	nonceSize = 24
+
+	// 암호문 형식: "enc:v1:<keyID>:<base64(nonce|box)>"
+	cipherPrefix = "enc:v1:"
+)
+
+// ErrDecrypt는 복호화에 실패했을 때 반환됩니다 (키 불일치 또는 손상)
+var ErrDecrypt = errors.New("decryption error")
+
+// key는 암호화 키와 식별자
+type key struct {
+	id    string
+	value [keySize]byte
+}
+
+// Keyring은 현재 키로 암호화하고, 현재 키 또는 이전 키로 복호화합니다
+type Keyring struct {
+	current  key
+	previous map[string]key
+}
+
+// Config는 키 로딩 설정
+// 환경 변수가 설정되어 있으면 파일보다 우선합니다
+type Config struct {
+	KeyFile          string   // 현재 키 파일 (없으면 새 키를 생성해 저장)
+	PreviousKeyFiles []string // 이전 키 파일 (키 교체 시 기존 데이터 복호화용)
+}
+
+// Load는 환경 변수 또는 키 파일에서 Keyring을 생성합니다
+// 키가 어디에도 없으면 KeyFile에 새 키를 생성합니다 (generated=true)
+func Load(config Config) (kr *Keyring, generated bool, err error) {
+	var current string
+
+	if v, ok := os.LookupEnv(KeyEnv); ok && strings.TrimSpace(v) != "" {
+		current = v
+	} else {
+		if config.KeyFile == "" {
+			return nil, false, fmt.Errorf("secret key is not configured (set %s or a key file)", KeyEnv)
+		}
+
+		data, err := os.ReadFile(config.KeyFile)
+		switch {
+		case err == nil:
+			current = string(data)
+
+		case os.IsNotExist(err):
+			current, err = generateKeyFile(config.KeyFile)
+			if err != nil {
+				return nil, false, err
+			}
+			generated = true
+
+		default:
+			return nil, false, fmt.Errorf("failed to read secret key file: %w", err)
+		}
+	}
+
+	var previous []string
+	if v := os.Getenv(PreviousKeysEnv); v != "" {
+		previous = append(previous, strings.Split(v, ",")...)
+	}
+	for _, path := range config.PreviousKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read previous secret key file: %w", err)
+		}
+		previous = append(previous, string(data))
+	}
+
+	kr, err = NewKeyring(current, previous...)
+	if err != nil {
+		return nil, false, err
+	}
+	return kr, generated, nil
+}
+
+// NewKeyring은 base64 인코딩된 키로 Keyring을 생성합니다
+func NewKeyring(current string, previous ...string) (*Keyring, error) {
+	cur, err := parseKey(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+
+	kr := &Keyring{
+		current:  cur,
+		previous: make(map[string]key),
+	}
+
+	for i, raw := range previous {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		k, err := parseKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous secret key #%d: %w", i, err)
+		}
+		if k.id != cur.id {
+			kr.previous[k.id] = k
+		}
+	}
+
+	return kr, nil
+}
+
+// GenerateKey는 새로운 base64 인코딩 키를 생성합니다
+func GenerateKey() (string, error) {
+	var raw [keySize]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw[:]), nil
+}
+
+// Encrypt는 평문을 현재 키로 암호화합니다 (빈 문자열은 그대로 반환)
+func (kr *Keyring) Encrypt(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", err
+	}
+
+	box := secretbox.Seal(nonce[:], []byte(plain), &nonce, &kr.current.value)
+
+	return cipherPrefix + kr.current.id + ":" + base64.StdEncoding.EncodeToString(box), nil
+}
+
+// Decrypt는 암호문을 복호화합니다 (빈 문자열은 그대로 반환)
+func (kr *Keyring) Decrypt(encrypted string) (string, error) {
+	if encrypted == "" {
+		return "", nil
+	}
+
+	keyID, payload, err := splitCiphertext(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	k, ok := kr.lookup(keyID)
+	if !ok {
+		return "", fmt.Errorf("%w: unknown key %s", ErrDecrypt, keyID)
+	}
+
+	box, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(box) < nonceSize {
+		return "", ErrDecrypt
+	}
+
+	var nonce [nonceSize]byte
+	copy(nonce[:], box[:nonceSize])
+
+	plain, ok := secretbox.Open(nil, box[nonceSize:], &nonce, &k.value)
+	if !ok {
+		return "", ErrDecrypt
+	}
+
+	return string(plain), nil
+}
+
+// NeedsRotation은 값이 현재 키로 암호화되지 않았는지 확인합니다
+// (이전 키로 암호화된 값 또는 암호화되지 않은 평문)
+func (kr *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	keyID, _, err := splitCiphertext(value)
+	return err != nil || keyID != kr.current.id
+}
+
+// lookup은 키 식별자로 키를 찾습니다
+func (kr *Keyring) lookup(id string) (key, bool) {
+	if id == kr.current.id {
+		return kr.current, true
+	}
+	k, ok := kr.previous[id]
+	return k, ok
+}
+
+// splitCiphertext는 암호문을 키 식별자와 본문으로 분리합니다
+func splitCiphertext(value string) (keyID string, payload string, err error) {
+	rest, ok := strings.CutPrefix(value, cipherPrefix)
+	if !ok {
+		return "", "", fmt.Errorf("%w: value is not encrypted", ErrDecrypt)
+	}
+	keyID, payload, ok = strings.Cut(rest, ":")
+	if !ok {
+		return "", "", fmt.Errorf("%w: malformed value", ErrDecrypt)
+	}
+	return keyID, payload, nil
+}
+
+// parseKey는 base64 인코딩된 32바이트 키를 파싱합니다
+func parseKey(raw string) (key, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(raw))
+	if err != nil {
+		return key{}, fmt.Errorf("key must be base64 encoded: %w", err)
+	}
+	if len(decoded) != keySize {
+		return key{}, fmt.Errorf("key must be %d bytes, got %d", keySize, len(decoded))
+	}
+
+	sum := sha256.Sum256(decoded)
+
+	var k key
+	k.id = hex.EncodeToString(sum[:4])
+	copy(k.value[:], decoded)
+	return k, nil
+}
+
+// generateKeyFile은 새 키를 생성해 파일에 저장합니다 (권한 0600)
+func generateKeyFile(path string) (string, error) {
+	encoded, err := GenerateKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate secret key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create secret key directory: %w", err)
+	}
+
+	// 이미 존재하면 덮어쓰지 않음 (동시 실행 방지)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create secret key file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(encoded + "\n"); err != nil {
+		return "", fmt.Errorf("failed to write secret key file: %w", err)
+	}
+
+	return encoded, nil
+}
@@ -0,0 +1,104 @@
+package secret
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaskedPassword는 응답/로그에서 비밀번호 대신 표시하는 값
+const MaskedPassword = "***"
+
+// SplitURLCredentials는 URL에서 사용자 이름과 비밀번호를 분리합니다
+// 반환되는 URL에는 인증 정보가 포함되지 않습니다
+func SplitURLCredentials(raw string) (clean string, username string, password string, err error) {
+	if raw == "" {
+		return "", "", "", nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid source URL: %w", err)
+	}
+
+	if u.User == nil {
+		return raw, "", "", nil
+	}
+
+	username = u.User.Username()
+	password, _ = u.User.Password()
+	u.User = nil
+
+	return u.String(), username, password, nil
+}
+
+// JoinURLCredentials는 인증 정보가 없는 URL에 사용자 이름과 비밀번호를 추가합니다
+func JoinURLCredentials(clean string, username string, password string) (string, error) {
+	if username == "" && password == "" {
+		return clean, nil
+	}
+
+	u, err := url.Parse(clean)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL: %w", err)
+	}
+
+	if password != "" {
+		u.User = url.UserPassword(username, password)
+	} else {
+		u.User = url.User(username)
+	}
+
+	return u.String(), nil
+}
+
+// MaskURL은 URL의 비밀번호를 마스킹합니다 (사용자 이름은 유지)
+// 파싱할 수 없는 URL은 전체를 마스킹합니다
+func MaskURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return MaskedPassword
+	}
+
+	if u.User == nil {
+		return raw
+	}
+
+	if _, ok := u.User.Password(); !ok {
+		return raw
+	}
+
+	// url.UserPassword는 '*'를 이스케이프하므로 직접 조합
+	username := url.User(u.User.Username()).String()
+	u.User = nil
+	prefix := u.Scheme + "://"
+	rest, ok := strings.CutPrefix(u.String(), prefix)
+	if !ok {
+		return MaskedPassword
+	}
+
+	return prefix + username + ":" + MaskedPassword + "@" + rest
+}
+
+// UnmaskURL은 마스킹된 비밀번호를 기존 URL의 비밀번호로 복원합니다
+// (조회 응답의 마스킹된 source를 그대로 수정 요청에 사용하는 경우)
+func UnmaskURL(raw string, previous string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+
+	if pass, ok := u.User.Password(); !ok || pass != MaskedPassword {
+		return raw
+	}
+
+	prev, err := url.Parse(previous)
+	if err != nil || prev.User == nil {
+		return raw
+	}
+
+	prevPass, _ := prev.User.Password()
+	u.User = url.UserPassword(u.User.Username(), prevPass)
+
+	return u.String()
+}
@@ -3,11 +3,18 @@ package signaling
 import (
 	"encoding/json"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/yourusername/cctv3/internal/audit"
+	"github.com/yourusername/cctv3/internal/auth"
+	"github.com/yourusername/cctv3/internal/status"
 	"go.uber.org/zap"
 )
 
@@ -19,9 +26,19 @@ type Server struct {
 	clients map[*Client]bool
 	mutex   sync.RWMutex
 
+	// 종료 대기 중 (새 연결/시청 요청 거부)
+	draining atomic.Bool
+
 	// 콜백
-	onOffer func(offer string, streamID string, client *Client) (answer string, err error)
-	onClose func(clientID string)
+	onOffer    func(offer string, streamID string, client *Client) (answer string, err error)
+	onPlayback func(req PlaybackRequest, client *Client) (answer string, err error)
+	onPTZ      func(streamID string, payload json.RawMessage, client *Client) (result interface{}, err error)
+	onClose    func(clientID string)
+
+	onRendition func(req RenditionRequest, client *Client) (answer string, state interface{}, err error)
+
+	// 실시간 스트림 상태 (nil이면 subscribe 메시지 거부)
+	statusManager *status.Manager
 }
 
 // Client는 WebSocket 클라이언트를 나타냅니다
@@ -31,11 +48,21 @@ type Client struct {
 	send   chan []byte
 	server *Server
 	logger *zap.Logger
+
+	// 인증된 사용자 (인증 미들웨어가 request context에 저장한 값)
+	identity *auth.Identity
+
+	// 클라이언트 IP (감사 로그용)
+	remoteAddr string
+
+	// 스트림 상태 구독 (subscribe 메시지, 없으면 nil)
+	statusSub   *status.Subscription
+	statusMutex sync.Mutex
 }
 
 // Message는 시그널링 메시지를 나타냅니다
 type Message struct {
-	Type     string          `json:"type"`     // "offer", "answer", "ice"
+	Type     string          `json:"type"`     // "offer", "answer", "ice", "play", "seek", "pause", "rate", "playback", "ptz", "event", "rendition", "subscribe", "unsubscribe", "status", "shutdown"
 	StreamID string          `json:"streamId"` // 스트림 ID (모든 메시지에 포함)
 	Payload  json.RawMessage `json:"payload"`  // SDP (string) or ICE candidate (object)
 }
@@ -46,11 +73,69 @@ type OfferPayload struct {
 	StreamID string `json:"streamId"`
 }
 
+// PlaybackPayload는 녹화 재생 메시지(play/seek/pause/rate) 페이로드를 나타냅니다
+type PlaybackPayload struct {
+	SDP   string  `json:"sdp,omitempty"`   // play: 최초 요청 시 WebRTC Offer
+	Start string  `json:"start,omitempty"` // play: 재생 시작 시각 (RFC3339)
+	Time  string  `json:"time,omitempty"`  // seek: 이동할 시각 (RFC3339)
+	Rate  float64 `json:"rate,omitempty"`  // play/rate: 재생 속도 (1.0=정상)
+}
+
+// PlaybackRequest는 파싱된 녹화 재생 요청
+type PlaybackRequest struct {
+	Type     string // "play", "seek", "pause", "rate"
+	StreamID string
+	SDP      string
+	Time     time.Time // play: 시작 시각, seek: 이동 시각 (play 재개 시 zero)
+	Rate     float64
+}
+
+// RenditionPayload는 스트림 그룹 화질 메시지(rendition) 페이로드를 나타냅니다
+type RenditionPayload struct {
+	SDP       string `json:"sdp,omitempty"`       // 최초 요청 시 WebRTC Offer
+	Rendition string `json:"rendition,omitempty"` // 화질 이름 (예: main, sub)
+	Bandwidth int64  `json:"bandwidth,omitempty"` // 클라이언트 대역폭 추정치 (bps, 화질 이름이 없을 때 사용)
+}
+
+// SubscribePayload는 스트림 상태 구독(subscribe) 페이로드를 나타냅니다
+// 비어있으면 시청 권한이 있는 모든 스트림의 모든 상태 이벤트를 받습니다
+type SubscribePayload struct {
+	Streams []string `json:"streams,omitempty"` // 스트림 ID 목록
+	Types   []string `json:"types,omitempty"`   // 상태 이벤트 종류 (stream.state, stream.codec, stream.subscribers, stream.bitrate)
+}
+
+// ShutdownPayload는 서버 종료 알림(shutdown) 페이로드를 나타냅니다
+type ShutdownPayload struct {
+	Reason  string `json:"reason"`
+	Timeout int    `json:"timeout"` // 연결을 끊기까지 남은 최대 시간 (초)
+}
+
+// RenditionRequest는 파싱된 스트림 그룹 화질 요청
+// SDP가 있으면 새 피어를 만들고, 없으면 기존 피어의 화질을 전환합니다
+type RenditionRequest struct {
+	GroupID   string
+	SDP       string
+	Rendition string
+	Bandwidth int64
+}
+
 // ServerConfig는 시그널링 서버 설정
 type ServerConfig struct {
-	Logger  *zap.Logger
-	OnOffer func(offer string, streamID string, client *Client) (answer string, err error)
+	Logger     *zap.Logger
+	OnOffer    func(offer string, streamID string, client *Client) (answer string, err error)
+	OnPlayback func(req PlaybackRequest, client *Client) (answer string, err error)
+	// PTZ 명령 (payload는 PTZ 명령 JSON, 결과는 "ptz" 메시지로 응답)
+	OnPTZ   func(streamID string, payload json.RawMessage, client *Client) (result interface{}, err error)
 	OnClose func(clientID string)
+	// 스트림 그룹 시청/화질 전환 (상태는 "rendition" 메시지로 응답)
+	OnRendition func(req RenditionRequest, client *Client) (answer string, state interface{}, err error)
+
+	// 실시간 스트림 상태 (subscribe 메시지로 구독, 상태는 "status" 메시지로 전송)
+	StatusManager *status.Manager
+
+	// 허용 Origin 목록 ("*"이면 전체 허용)
+	// 같은 Origin과 Origin 헤더가 없는 비브라우저 클라이언트는 항상 허용
+	AllowedOrigins []string
 }
 
 // NewServer는 새로운 시그널링 서버를 생성합니다
@@ -59,17 +144,36 @@ func NewServer(config ServerConfig) *Server {
 		logger: config.Logger,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
-				return true // 개발 모드: 모든 origin 허용
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true
+				}
+				// 같은 Origin (내장 웹 UI)
+				if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+					return true
+				}
+				return auth.IsOriginAllowed(origin, config.AllowedOrigins)
 			},
 		},
-		clients: make(map[*Client]bool),
-		onOffer: config.OnOffer,
-		onClose: config.OnClose,
+		clients:    make(map[*Client]bool),
+		onOffer:    config.OnOffer,
+		onPlayback: config.OnPlayback,
+		onPTZ:      config.OnPTZ,
+		onClose:    config.OnClose,
+
+		onRendition: config.OnRendition,
+
+		statusManager: config.StatusManager,
 	}
 }
 
 // HandleWebSocket은 WebSocket 연결을 처리합니다
 func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		s.logger.Error("Failed to upgrade connection",
@@ -87,6 +191,21 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		logger: s.logger.With(zap.String("client_id", clientID)),
 	}
 
+	// API 라우터가 저장한 클라이언트 IP (신뢰 프록시의 X-Forwarded-For 반영), 없으면 접속 주소
+	client.remoteAddr = audit.RemoteAddrFromContext(r.Context())
+	if client.remoteAddr == "" {
+		client.remoteAddr = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			client.remoteAddr = host
+		}
+	}
+
+	// 인증 미들웨어를 거치지 않은 경우(인증 비활성화) 관리자 권한
+	client.identity = auth.IdentityFromContext(r.Context())
+	if client.identity == nil {
+		client.identity = auth.Anonymous
+	}
+
 	s.registerClient(client)
 
 	// 읽기/쓰기 고루틴 시작
@@ -94,7 +213,7 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	go client.readPump()
 
 	client.logger.Info("WebSocket client connected",
-		zap.String("remote_addr", r.RemoteAddr),
+		zap.String("remote_addr", client.remoteAddr),
 	)
 }
 
@@ -118,6 +237,7 @@ func (s *Server) unregisterClient(client *Client) {
 	if _, exists := s.clients[client]; exists {
 		delete(s.clients, client)
 		close(client.send)
+		client.closeStatus()
 
 		s.logger.Info("Client unregistered",
 			zap.String("client_id", client.id),
@@ -175,6 +295,15 @@ func (c *Client) handleMessage(data []byte) {
 		zap.String("stream_id", msg.StreamID),
 	)
 
+	// 종료 대기 중에는 새 시청(WebRTC/녹화 재생/스트림 그룹) 시작 거부 (기존 재생 제어는 허용)
+	if c.server.draining.Load() {
+		switch msg.Type {
+		case "offer", "play", "rendition":
+			c.SendError("server is shutting down", msg.StreamID)
+			return
+		}
+	}
+
 	switch msg.Type {
 	case "offer":
 		// Offer는 OfferPayload로 전달됨 (sdp + streamId)
@@ -193,6 +322,23 @@ func (c *Client) handleMessage(data []byte) {
 	case "ice":
 		// ICE candidate는 object로 전달됨
 		c.handleICE(msg.Payload, msg.StreamID)
+	case "play":
+		// 녹화 재생 시작/재개 (Offer 처리로 시간이 걸릴 수 있으므로 비동기)
+		go c.handlePlayback(msg)
+	case "seek", "pause", "rate":
+		// 녹화 재생 제어 (순서 보장을 위해 동기 처리)
+		c.handlePlayback(msg)
+	case "ptz":
+		// PTZ 제어 (continuous → stop 순서 보장을 위해 동기 처리)
+		c.handlePTZ(msg)
+	case "rendition":
+		// 스트림 그룹 시청 시작(Offer 포함) 또는 화질 전환
+		go c.handleRendition(msg)
+	case "subscribe":
+		// 스트림 상태 구독 (기존 구독은 교체)
+		c.handleSubscribe(msg)
+	case "unsubscribe":
+		c.closeStatus()
 	default:
 		c.logger.Warn("Unknown message type", zap.String("type", msg.Type))
 	}
@@ -219,6 +365,245 @@ func (c *Client) handleOffer(offer string, streamID string, messageStreamID stri
 	c.SendAnswer(answer, messageStreamID)
 }
 
+// handlePlayback은 녹화 재생 메시지를 처리합니다
+func (c *Client) handlePlayback(msg Message) {
+	if c.server.onPlayback == nil {
+		c.SendError("playback is not supported", msg.StreamID)
+		return
+	}
+
+	var payload PlaybackPayload
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.SendError("invalid playback payload: "+err.Error(), msg.StreamID)
+			return
+		}
+	}
+
+	req := PlaybackRequest{
+		Type:     msg.Type,
+		StreamID: msg.StreamID,
+		SDP:      payload.SDP,
+		Rate:     payload.Rate,
+	}
+
+	raw := payload.Start
+	if msg.Type == "seek" {
+		raw = payload.Time
+	}
+	if raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.SendError("invalid playback time: "+err.Error(), msg.StreamID)
+			return
+		}
+		req.Time = t
+	}
+
+	if msg.Type == "seek" && req.Time.IsZero() {
+		c.SendError("seek requires time", msg.StreamID)
+		return
+	}
+
+	c.logger.Info("Processing playback request",
+		zap.String("type", req.Type),
+		zap.String("stream_id", req.StreamID),
+	)
+
+	answer, err := c.server.onPlayback(req, c)
+	if err != nil {
+		c.logger.Error("Failed to handle playback request", zap.Error(err))
+		c.SendError(err.Error(), msg.StreamID)
+		return
+	}
+
+	if answer != "" {
+		c.SendAnswer(answer, msg.StreamID)
+	}
+}
+
+// handlePTZ는 PTZ 제어 메시지를 처리합니다
+func (c *Client) handlePTZ(msg Message) {
+	if c.server.onPTZ == nil {
+		c.SendError("PTZ is not supported", msg.StreamID)
+		return
+	}
+
+	result, err := c.server.onPTZ(msg.StreamID, msg.Payload, c)
+	if err != nil {
+		c.logger.Warn("Failed to handle PTZ command",
+			zap.String("stream_id", msg.StreamID),
+			zap.Error(err),
+		)
+		c.SendError(err.Error(), msg.StreamID)
+		return
+	}
+
+	c.SendPTZResult(result, msg.StreamID)
+}
+
+// handleRendition은 스트림 그룹 화질 메시지를 처리합니다
+func (c *Client) handleRendition(msg Message) {
+	if c.server.onRendition == nil {
+		c.SendError("stream groups are not supported", msg.StreamID)
+		return
+	}
+
+	var payload RenditionPayload
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.SendError("invalid rendition payload: "+err.Error(), msg.StreamID)
+			return
+		}
+	}
+
+	if payload.Bandwidth < 0 {
+		c.SendError("bandwidth must not be negative", msg.StreamID)
+		return
+	}
+
+	req := RenditionRequest{
+		GroupID:   msg.StreamID,
+		SDP:       payload.SDP,
+		Rendition: payload.Rendition,
+		Bandwidth: payload.Bandwidth,
+	}
+
+	c.logger.Info("Processing rendition request",
+		zap.String("group_id", req.GroupID),
+		zap.String("rendition", req.Rendition),
+		zap.Int64("bandwidth", req.Bandwidth),
+		zap.Bool("offer", req.SDP != ""),
+	)
+
+	answer, state, err := c.server.onRendition(req, c)
+	if err != nil {
+		c.logger.Warn("Failed to handle rendition request",
+			zap.String("group_id", req.GroupID),
+			zap.Error(err),
+		)
+		c.SendError(err.Error(), msg.StreamID)
+		return
+	}
+
+	if answer != "" {
+		c.SendAnswer(answer, msg.StreamID)
+	}
+	if state != nil {
+		c.SendRenditionState(state, msg.StreamID)
+	}
+}
+
+// handleSubscribe는 스트림 상태 구독을 시작합니다
+// 현재 상태를 먼저 보낸 뒤 변화가 있을 때마다 "status" 메시지를 보냅니다
+func (c *Client) handleSubscribe(msg Message) {
+	if c.server.statusManager == nil {
+		c.SendError("status events are not enabled", msg.StreamID)
+		return
+	}
+
+	var payload SubscribePayload
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.SendError("invalid subscribe payload", msg.StreamID)
+			return
+		}
+	}
+	if msg.StreamID != "" {
+		payload.Streams = append(payload.Streams, msg.StreamID)
+	}
+
+	streams := make(map[string]struct{}, len(payload.Streams))
+	for _, id := range payload.Streams {
+		streams[id] = struct{}{}
+	}
+	types := make(map[string]struct{}, len(payload.Types))
+	for _, t := range payload.Types {
+		types[t] = struct{}{}
+	}
+
+	c.closeStatus()
+
+	sub := c.server.statusManager.Subscribe(func(streamID string) bool {
+		if len(streams) > 0 {
+			if _, ok := streams[streamID]; !ok {
+				return false
+			}
+		}
+		return c.identity.CanView(streamID)
+	})
+
+	c.statusMutex.Lock()
+	c.statusSub = sub
+	c.statusMutex.Unlock()
+
+	go c.forwardStatus(sub, types)
+
+	c.logger.Debug("Status subscription started",
+		zap.Strings("streams", payload.Streams),
+		zap.Strings("types", payload.Types),
+	)
+}
+
+// forwardStatus는 구독이 끝날 때까지 상태 이벤트를 "status" 메시지로 보냅니다
+func (c *Client) forwardStatus(sub *status.Subscription, types map[string]struct{}) {
+	for event := range sub.Events() {
+		if len(types) > 0 {
+			if _, ok := types[event.Type]; !ok {
+				continue
+			}
+		}
+
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			c.logger.Error("Failed to marshal status event", zap.Error(err))
+			continue
+		}
+		data, err := json.Marshal(Message{
+			Type:     "status",
+			StreamID: event.StreamID,
+			Payload:  eventJSON,
+		})
+		if err != nil {
+			c.logger.Error("Failed to marshal status message", zap.Error(err))
+			continue
+		}
+
+		if !c.trySend(data) {
+			c.logger.Warn("Send channel full, dropping status event")
+		}
+	}
+}
+
+// closeStatus는 스트림 상태 구독을 종료합니다 (구독 중이 아니면 무시)
+func (c *Client) closeStatus() {
+	c.statusMutex.Lock()
+	sub := c.statusSub
+	c.statusSub = nil
+	c.statusMutex.Unlock()
+
+	if sub != nil {
+		sub.Close()
+	}
+}
+
+// trySend는 등록된 클라이언트의 전송 채널에 메시지를 넣습니다 (연결이 끊겼거나 채널이 가득 차면 false)
+// send 채널은 등록 해제 시 서버 잠금 안에서 닫히므로 같은 잠금을 쥐고 보냅니다
+func (c *Client) trySend(data []byte) bool {
+	c.server.mutex.RLock()
+	defer c.server.mutex.RUnlock()
+
+	if !c.server.clients[c] {
+		return true
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
 // handleICE는 ICE candidate를 처리합니다
 func (c *Client) handleICE(candidateData json.RawMessage, streamID string) {
 	// ICE candidate는 브라우저에서 object 형태로 전달됨
@@ -289,11 +674,102 @@ func (c *Client) SendError(errorMsg string, streamID string) {
 	}
 }
 
+// SendPlaybackState는 녹화 재생 상태를 전송합니다
+func (c *Client) SendPlaybackState(state interface{}, streamID string) {
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		c.logger.Error("Failed to marshal playback state", zap.Error(err))
+		return
+	}
+
+	msg := Message{
+		Type:     "playback",
+		StreamID: streamID,
+		Payload:  stateJSON,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.logger.Error("Failed to marshal playback message", zap.Error(err))
+		return
+	}
+
+	select {
+	case c.send <- data:
+	default:
+		c.logger.Error("Send channel full, dropping playback state")
+	}
+}
+
+// SendPTZResult는 PTZ 명령 결과를 전송합니다
+func (c *Client) SendPTZResult(result interface{}, streamID string) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		c.logger.Error("Failed to marshal PTZ result", zap.Error(err))
+		return
+	}
+
+	msg := Message{
+		Type:     "ptz",
+		StreamID: streamID,
+		Payload:  resultJSON,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.logger.Error("Failed to marshal PTZ message", zap.Error(err))
+		return
+	}
+
+	select {
+	case c.send <- data:
+	default:
+		c.logger.Error("Send channel full, dropping PTZ result")
+	}
+}
+
+// SendRenditionState는 스트림 그룹의 현재 화질 상태를 전송합니다
+func (c *Client) SendRenditionState(state interface{}, groupID string) {
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		c.logger.Error("Failed to marshal rendition state", zap.Error(err))
+		return
+	}
+
+	msg := Message{
+		Type:     "rendition",
+		StreamID: groupID,
+		Payload:  stateJSON,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.logger.Error("Failed to marshal rendition message", zap.Error(err))
+		return
+	}
+
+	select {
+	case c.send <- data:
+	default:
+		c.logger.Error("Send channel full, dropping rendition state")
+	}
+}
+
 // GetID는 클라이언트 ID를 반환합니다
 func (c *Client) GetID() string {
 	return c.id
 }
 
+// GetIdentity는 클라이언트의 인증된 사용자 정보를 반환합니다
+func (c *Client) GetIdentity() *auth.Identity {
+	return c.identity
+}
+
+// GetRemoteAddr는 클라이언트 IP를 반환합니다
+func (c *Client) GetRemoteAddr() string {
+	return c.remoteAddr
+}
+
 // generateClientID는 고유한 클라이언트 ID를 생성합니다
 func generateClientID() string {
 	// 간단한 구현: UUID 사용 권장
@@ -319,14 +795,92 @@ func (s *Server) GetClientCount() int {
 	return len(s.clients)
 }
 
-// Close는 모든 클라이언트 연결을 종료합니다
+// BroadcastEvent는 스트림 시청 권한이 있는 모든 클라이언트에게 카메라 이벤트를 전송합니다
+func (s *Server) BroadcastEvent(streamID string, event interface{}) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal event", zap.Error(err))
+		return
+	}
+
+	data, err := json.Marshal(Message{
+		Type:     "event",
+		StreamID: streamID,
+		Payload:  eventJSON,
+	})
+	if err != nil {
+		s.logger.Error("Failed to marshal event message", zap.Error(err))
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for client := range s.clients {
+		if !client.identity.CanView(streamID) {
+			continue
+		}
+		select {
+		case client.send <- data:
+		default:
+			client.logger.Warn("Send channel full, dropping event")
+		}
+	}
+}
+
+// Shutdown은 새 연결과 시청 요청을 거부하고 모든 클라이언트에게 shutdown 메시지를 보냅니다
+// 연결은 유지되며 Close에서 종료됩니다 (클라이언트는 메시지를 받고 다른 서버로 재연결 가능)
+func (s *Server) Shutdown(reason string, timeout time.Duration) {
+	s.draining.Store(true)
+
+	payload, err := json.Marshal(ShutdownPayload{
+		Reason:  reason,
+		Timeout: int(timeout.Seconds()),
+	})
+	if err != nil {
+		s.logger.Error("Failed to marshal shutdown payload", zap.Error(err))
+		return
+	}
+
+	data, err := json.Marshal(Message{
+		Type:    "shutdown",
+		Payload: payload,
+	})
+	if err != nil {
+		s.logger.Error("Failed to marshal shutdown message", zap.Error(err))
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for client := range s.clients {
+		select {
+		case client.send <- data:
+		default:
+			client.logger.Warn("Send channel full, dropping shutdown message")
+		}
+	}
+
+	s.logger.Info("Signaling server draining",
+		zap.Int("clients", len(s.clients)),
+	)
+}
+
+// Close는 모든 클라이언트 연결을 종료합니다 (close 프레임 전송 후 연결 해제)
 func (s *Server) Close() {
 	s.logger.Info("Closing signaling server")
 
+	s.draining.Store(true)
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutdown")
+	deadline := time.Now().Add(time.Second)
 	for client := range s.clients {
+		// WriteControl은 writePump의 쓰기와 동시에 호출해도 안전
+		_ = client.conn.WriteControl(websocket.CloseMessage, closeMessage, deadline)
 		client.conn.Close()
 		delete(s.clients, client)
 	}
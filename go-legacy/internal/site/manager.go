@@ -0,0 +1,152 @@
+// Package site는 스트림이 속한 사이트(설치 장소)를 메모리에 보관해
+// 시청 권한 확인과 사이트별 동시 연결 제한에 사용합니다
+// DB(sites, streams.site_id)가 원본이며, 사이트나 스트림 소속이 바뀌면 Reload로 다시 읽습니다
+package site
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+// ErrLimitReached는 사이트의 동시 연결 수 제한에 도달했을 때 반환됩니다
+var ErrLimitReached = errors.New("site concurrent stream limit reached")
+
+// Config는 사이트 관리자 설정
+type Config struct {
+	Repository *database.SiteRepository
+	Logger     *zap.Logger
+}
+
+// Manager는 사이트 정보와 스트림별 소속 사이트를 보관합니다
+type Manager struct {
+	repo   *database.SiteRepository
+	logger *zap.Logger
+
+	mutex       sync.RWMutex
+	sites       map[string]database.Site
+	streamSites map[string]string // streamID -> siteID
+}
+
+// NewManager는 새로운 사이트 관리자를 생성하고 DB에서 사이트 정보를 읽습니다
+func NewManager(config Config) (*Manager, error) {
+	if config.Repository == nil {
+		return nil, fmt.Errorf("site repository is required")
+	}
+	if config.Logger == nil {
+		config.Logger = zap.NewNop()
+	}
+
+	m := &Manager{
+		repo:   config.Repository,
+		logger: config.Logger,
+	}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Reload는 사이트 목록과 스트림별 소속 사이트를 DB에서 다시 읽습니다
+func (m *Manager) Reload() error {
+	list, err := m.repo.List()
+	if err != nil {
+		return err
+	}
+	streamSites, err := m.repo.StreamSites()
+	if err != nil {
+		return err
+	}
+
+	sites := make(map[string]database.Site, len(list))
+	for _, site := range list {
+		sites[site.ID] = *site
+	}
+
+	m.mutex.Lock()
+	m.sites = sites
+	m.streamSites = streamSites
+	m.mutex.Unlock()
+
+	m.logger.Debug("Sites reloaded",
+		zap.Int("sites", len(sites)),
+		zap.Int("assigned_streams", len(streamSites)),
+	)
+	return nil
+}
+
+// SiteOf는 스트림이 속한 사이트 ID를 반환합니다 (없으면 빈 값, 파생 스트림은 원본 스트림의 사이트)
+func (m *Manager) SiteOf(streamID string) string {
+	if m == nil {
+		return ""
+	}
+	if baseID, _, ok := core.SplitDerivedStreamID(streamID); ok {
+		streamID = baseID
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.streamSites[streamID]
+}
+
+// Site는 사이트 정보를 반환합니다
+func (m *Manager) Site(siteID string) (database.Site, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	site, ok := m.sites[siteID]
+	return site, ok
+}
+
+// Streams는 사이트에 속한 스트림 ID를 정렬해 반환합니다
+func (m *Manager) Streams(siteID string) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var streams []string
+	for streamID, id := range m.streamSites {
+		if id == siteID {
+			streams = append(streams, streamID)
+		}
+	}
+	sort.Strings(streams)
+	return streams
+}
+
+// CheckLimit은 스트림 소스를 새로 시작해도 사이트 동시 연결 제한을 넘지 않는지 확인합니다
+// running은 현재 실행 중인 소스의 스트림 ID 목록입니다 (streamID 자신은 세지 않음)
+func (m *Manager) CheckLimit(streamID string, running []string) error {
+	if m == nil {
+		return nil
+	}
+
+	siteID := m.SiteOf(streamID)
+	if siteID == "" {
+		return nil
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	site, ok := m.sites[siteID]
+	if !ok || site.MaxStreams <= 0 {
+		return nil
+	}
+
+	count := 0
+	for _, id := range running {
+		if id != streamID && m.streamSites[id] == siteID {
+			count++
+		}
+	}
+	if count >= site.MaxStreams {
+		return fmt.Errorf("%w: site %s allows %d concurrent streams", ErrLimitReached, siteID, site.MaxStreams)
+	}
+
+	return nil
+}
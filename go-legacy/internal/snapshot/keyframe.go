@@ -0,0 +1,279 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph265"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtpmjpeg"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h265"
+	"github.com/pion/rtp"
+	"github.com/yourusername/cctv3/internal/core"
+	"go.uber.org/zap"
+)
+
+// mjpegPayloadType는 MJPEG 정적 RTP 페이로드 타입 (RFC 2435)
+const mjpegPayloadType = 26
+
+// rtpDecoder는 RTP 패킷을 Access Unit으로 조립하는 디코더 인터페이스
+type rtpDecoder interface {
+	Decode(pkt *rtp.Packet) ([][]byte, error)
+}
+
+// keyframe은 스냅샷 생성에 사용할 마지막 키프레임입니다
+type keyframe struct {
+	data     []byte // H.264/H.265: Annex-B (파라미터 셋 + 키프레임 AU), MJPEG: JPEG
+	codec    string
+	received time.Time
+	version  uint64 // 키프레임이 바뀔 때마다 증가 (0이면 키프레임 없음)
+}
+
+// keyframeCache는 스트림의 마지막 키프레임을 보관하는 구독자입니다 (core.StreamSubscriber 구현)
+type keyframeCache struct {
+	id     string
+	stream *core.Stream
+	logger *zap.Logger
+
+	mutex sync.Mutex
+
+	// 디코딩 상태
+	codec    string
+	decoder  rtpDecoder
+	mjpegDec *rtpmjpeg.Decoder
+	videoPT  uint8
+	ptKnown  bool
+
+	// 파라미터 셋 (IDR 앞에 붙여 단독으로 디코딩 가능한 키프레임 구성)
+	vps []byte
+	sps []byte
+	pps []byte
+
+	latest keyframe
+}
+
+// newKeyframeCache는 새로운 키프레임 캐시를 생성합니다
+func newKeyframeCache(stream *core.Stream, logger *zap.Logger) *keyframeCache {
+	return &keyframeCache{
+		id:     "snapshot-" + stream.GetID(),
+		stream: stream,
+		logger: logger.With(zap.String("stream_id", stream.GetID())),
+	}
+}
+
+// GetID는 구독자 ID를 반환합니다 (core.StreamSubscriber 인터페이스)
+func (k *keyframeCache) GetID() string {
+	return k.id
+}
+
+// OnPacket은 RTP 패킷을 조립해 키프레임이면 보관합니다 (core.StreamSubscriber 인터페이스)
+func (k *keyframeCache) OnPacket(pkt *rtp.Packet) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	codec := k.stream.GetVideoCodec()
+	if codec == "" {
+		return nil
+	}
+	if codec != k.codec {
+		if err := k.initDecoder(codec); err != nil {
+			return err
+		}
+	}
+
+	if codec == "MJPEG" {
+		return k.decodeMJPEG(pkt)
+	}
+
+	// 비디오 페이로드 타입이 확정된 후에는 다른 트랙(오디오) 패킷 무시
+	if k.ptKnown && pkt.PayloadType != k.videoPT {
+		return nil
+	}
+
+	au, err := k.decoder.Decode(pkt)
+	if err != nil {
+		if errors.Is(err, rtph264.ErrMorePacketsNeeded) ||
+			errors.Is(err, rtph265.ErrMorePacketsNeeded) ||
+			errors.Is(err, rtph264.ErrNonStartingPacketAndNoPrevious) ||
+			errors.Is(err, rtph265.ErrNonStartingPacketAndNoPrevious) {
+			return nil
+		}
+		k.logger.Debug("Failed to decode RTP packet for snapshot", zap.Error(err))
+		return nil
+	}
+
+	if k.updateParams(au) && !k.ptKnown {
+		k.videoPT = pkt.PayloadType
+		k.ptKnown = true
+	}
+
+	if !k.isRandomAccess(au) || !k.hasParams() {
+		return nil
+	}
+
+	data, err := k.marshalKeyframe(au)
+	if err != nil {
+		k.logger.Debug("Failed to marshal keyframe for snapshot", zap.Error(err))
+		return nil
+	}
+	k.store(data)
+	return nil
+}
+
+// decodeMJPEG는 MJPEG 프레임을 조립해 보관합니다 (모든 프레임이 키프레임)
+func (k *keyframeCache) decodeMJPEG(pkt *rtp.Packet) error {
+	if pkt.PayloadType != mjpegPayloadType {
+		return nil
+	}
+
+	frame, err := k.mjpegDec.Decode(pkt)
+	if err != nil {
+		if errors.Is(err, rtpmjpeg.ErrMorePacketsNeeded) ||
+			errors.Is(err, rtpmjpeg.ErrNonStartingPacketAndNoPrevious) {
+			return nil
+		}
+		k.logger.Debug("Failed to decode MJPEG packet for snapshot", zap.Error(err))
+		return nil
+	}
+
+	k.store(frame)
+	return nil
+}
+
+// store는 새 키프레임을 보관합니다
+func (k *keyframeCache) store(data []byte) {
+	k.latest = keyframe{
+		data:     data,
+		codec:    k.codec,
+		received: time.Now(),
+		version:  k.latest.version + 1,
+	}
+}
+
+// get은 마지막 키프레임을 반환합니다
+func (k *keyframeCache) get() keyframe {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return k.latest
+}
+
+// initDecoder는 코덱에 맞는 RTP 디코더를 생성합니다 (코덱이 바뀌면 상태 초기화)
+func (k *keyframeCache) initDecoder(codec string) error {
+	k.decoder = nil
+	k.mjpegDec = nil
+	k.ptKnown = false
+	k.vps, k.sps, k.pps = nil, nil, nil
+
+	switch codec {
+	case "H264":
+		forma := &format.H264{PayloadTyp: 96, PacketizationMode: 1}
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return fmt.Errorf("failed to create H264 decoder: %w", err)
+		}
+		k.decoder = dec
+
+	case "H265":
+		forma := &format.H265{PayloadTyp: 96}
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return fmt.Errorf("failed to create H265 decoder: %w", err)
+		}
+		k.decoder = dec
+
+	case "MJPEG":
+		forma := &format.MJPEG{}
+		dec, err := forma.CreateDecoder()
+		if err != nil {
+			return fmt.Errorf("failed to create MJPEG decoder: %w", err)
+		}
+		k.mjpegDec = dec
+
+	default:
+		return fmt.Errorf("unsupported codec for snapshot: %s", codec)
+	}
+
+	k.codec = codec
+	return nil
+}
+
+// updateParams는 AU에서 파라미터 셋을 추출하고, 포함되어 있으면 true를 반환합니다
+func (k *keyframeCache) updateParams(au [][]byte) bool {
+	found := false
+
+	for _, nalu := range au {
+		if target := k.paramTarget(nalu); target != nil {
+			found = true
+			if !bytes.Equal(*target, nalu) {
+				*target = append([]byte(nil), nalu...)
+			}
+		}
+	}
+
+	return found
+}
+
+// paramTarget은 NALU가 파라미터 셋이면 보관 위치를, 아니면 nil을 반환합니다
+func (k *keyframeCache) paramTarget(nalu []byte) *[]byte {
+	if len(nalu) == 0 {
+		return nil
+	}
+
+	if k.codec == "H264" {
+		switch h264.NALUType(nalu[0] & 0x1F) {
+		case h264.NALUTypeSPS:
+			return &k.sps
+		case h264.NALUTypePPS:
+			return &k.pps
+		}
+		return nil
+	}
+
+	switch h265.NALUType((nalu[0] >> 1) & 0x3F) {
+	case h265.NALUType_VPS_NUT:
+		return &k.vps
+	case h265.NALUType_SPS_NUT:
+		return &k.sps
+	case h265.NALUType_PPS_NUT:
+		return &k.pps
+	}
+	return nil
+}
+
+// hasParams는 디코딩에 필요한 파라미터 셋이 모두 있는지 확인합니다
+func (k *keyframeCache) hasParams() bool {
+	if k.codec == "H265" {
+		return k.vps != nil && k.sps != nil && k.pps != nil
+	}
+	return k.sps != nil && k.pps != nil
+}
+
+// isRandomAccess는 AU가 키프레임인지 확인합니다
+func (k *keyframeCache) isRandomAccess(au [][]byte) bool {
+	if k.codec == "H265" {
+		return h265.IsRandomAccess(au)
+	}
+	return h264.IsRandomAccess(au)
+}
+
+// marshalKeyframe은 파라미터 셋과 키프레임 AU를 Annex-B 바이트 스트림으로 만듭니다
+func (k *keyframeCache) marshalKeyframe(au [][]byte) ([]byte, error) {
+	nalus := make([][]byte, 0, len(au)+3)
+	if k.codec == "H265" {
+		nalus = append(nalus, k.vps)
+	}
+	nalus = append(nalus, k.sps, k.pps)
+
+	for _, nalu := range au {
+		if len(nalu) > 0 && k.paramTarget(nalu) == nil {
+			nalus = append(nalus, nalu)
+		}
+	}
+
+	return h264.AnnexB(nalus).Marshal()
+}
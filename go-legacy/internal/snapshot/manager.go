@@ -0,0 +1,347 @@
+// Package snapshot은 스트림의 마지막 키프레임으로 JPEG 스냅샷(썸네일)을 생성합니다
+// MJPEG 스트림은 프레임을 그대로 사용하고(순수 Go), H.264/H.265는 외부 디코더 명령으로 변환합니다
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/process"
+	"go.uber.org/zap"
+)
+
+// maxVariants는 스트림별로 보관하는 크기 조정 결과 최대 개수
+const maxVariants = 8
+
+var (
+	// ErrNoSnapshot은 스트림이 실행된 적이 없거나 아직 키프레임을 받지 못한 경우입니다
+	ErrNoSnapshot = errors.New("no snapshot available")
+
+	// ErrDecoderNotConfigured는 H.264/H.265 스트림에 사용할 디코더 명령이 없는 경우입니다
+	ErrDecoderNotConfigured = errors.New("snapshot decoder command is not configured")
+)
+
+// Config는 스냅샷 관리자 설정
+type Config struct {
+	Interval       time.Duration // 스냅샷 갱신 주기 (전체 스트림 백그라운드 갱신, 요청 시 이보다 오래되면 다시 생성)
+	DecoderCommand string        // H.264/H.265 키프레임(stdin, Annex-B) → JPEG(stdout) 변환 명령
+	Timeout        time.Duration // 디코더 명령 제한 시간
+	MaxConcurrent  int           // 동시에 실행할 디코더 수
+	Quality        int           // 크기 조정 시 JPEG 품질 (1-100)
+	ProcessManager *process.Manager
+}
+
+// Snapshot은 생성된 JPEG 스냅샷입니다
+type Snapshot struct {
+	Data []byte
+	Time time.Time // 키프레임 수신 시각
+}
+
+// size는 크기 조정 요청 (0이면 원본 비율에 맞춤)
+type size struct {
+	width  int
+	height int
+}
+
+// entry는 스트림 하나의 키프레임 캐시와 마지막 스냅샷입니다
+// 스트림이 정지되어도 마지막 스냅샷은 유지됩니다
+type entry struct {
+	streamID string
+	cache    *keyframeCache // 스트림 실행 중에만 설정
+
+	mutex     sync.Mutex // 스냅샷 생성 직렬화 (같은 스트림 동시 요청 시 디코더 한 번만 실행)
+	jpeg      []byte
+	taken     time.Time // 키프레임 수신 시각
+	generated time.Time // 스냅샷 생성 시각
+	version   uint64    // 스냅샷을 만든 키프레임 버전
+	variants  map[size][]byte
+
+	// 마지막 변환 실패 (같은 키프레임으로 디코더를 반복 실행하지 않음)
+	failedVersion uint64
+	failedErr     error
+}
+
+// Manager는 스트림별 키프레임 캐시와 스냅샷을 관리합니다
+type Manager struct {
+	config Config
+	logger *zap.Logger
+	slots  chan struct{} // 동시 디코더 실행 제한
+
+	mutex   sync.RWMutex
+	entries map[string]*entry // streamID -> entry
+}
+
+// NewManager는 새로운 스냅샷 관리자를 생성합니다
+func NewManager(config Config, logger *zap.Logger) *Manager {
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 2
+	}
+	if config.Quality <= 0 || config.Quality > 100 {
+		config.Quality = 80
+	}
+
+	return &Manager{
+		config:  config,
+		logger:  logger,
+		slots:   make(chan struct{}, config.MaxConcurrent),
+		entries: make(map[string]*entry),
+	}
+}
+
+// Attach는 스트림의 키프레임 보관을 시작합니다 (이미 보관 중이면 무시)
+func (m *Manager) Attach(stream *core.Stream) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	streamID := stream.GetID()
+	e, exists := m.entries[streamID]
+	if !exists {
+		e = &entry{streamID: streamID}
+		m.entries[streamID] = e
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.cache != nil {
+		return nil
+	}
+
+	cache := newKeyframeCache(stream, m.logger)
+	if err := stream.Subscribe(cache); err != nil {
+		return fmt.Errorf("failed to subscribe snapshot cache: %w", err)
+	}
+	e.cache = cache
+	// 재시작된 스트림의 키프레임 버전은 1부터 다시 시작
+	e.version = 0
+	e.failedVersion = 0
+
+	return nil
+}
+
+// Detach는 키프레임 보관을 중지합니다 (마지막 스냅샷은 유지)
+func (m *Manager) Detach(stream *core.Stream) {
+	m.mutex.RLock()
+	e, exists := m.entries[stream.GetID()]
+	m.mutex.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	e.mutex.Lock()
+	cache := e.cache
+	e.cache = nil
+	e.mutex.Unlock()
+
+	if cache == nil {
+		return
+	}
+	if err := stream.Unsubscribe(cache.GetID()); err != nil {
+		m.logger.Debug("Failed to unsubscribe snapshot cache",
+			zap.String("stream_id", stream.GetID()),
+			zap.Error(err),
+		)
+	}
+}
+
+// Get은 스트림의 JPEG 스냅샷을 반환합니다
+// 마지막 스냅샷이 갱신 주기보다 오래되었고 새 키프레임이 있으면 다시 생성합니다
+// width/height가 0보다 크면 원본 비율을 유지하며 그 안에 들어가도록 줄입니다
+func (m *Manager) Get(ctx context.Context, streamID string, width, height int) (*Snapshot, error) {
+	m.mutex.RLock()
+	e, exists := m.entries[streamID]
+	m.mutex.RUnlock()
+
+	if !exists {
+		return nil, ErrNoSnapshot
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.jpeg == nil || time.Since(e.generated) >= m.config.Interval {
+		err := m.refresh(ctx, e)
+		switch {
+		case err == nil:
+		case e.jpeg == nil:
+			return nil, err
+		case !errors.Is(err, ErrNoSnapshot):
+			// 이전 스냅샷으로 응답
+			m.logger.Warn("Failed to refresh snapshot, serving previous one",
+				zap.String("stream_id", streamID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if e.jpeg == nil {
+		return nil, ErrNoSnapshot
+	}
+
+	data := e.jpeg
+	if width > 0 || height > 0 {
+		key := size{width: width, height: height}
+		if cached, ok := e.variants[key]; ok {
+			data = cached
+		} else {
+			resized, err := resizeJPEG(e.jpeg, width, height, m.config.Quality)
+			if err != nil {
+				return nil, err
+			}
+			if len(e.variants) < maxVariants {
+				e.variants[key] = resized
+			}
+			data = resized
+		}
+	}
+
+	return &Snapshot{Data: data, Time: e.taken}, nil
+}
+
+// Start는 갱신 주기마다 실행 중인 모든 스트림의 스냅샷을 생성합니다
+func (m *Manager) Start(ctx context.Context) {
+	if m.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshAll(ctx)
+		}
+	}
+}
+
+// refreshAll은 새 키프레임이 있는 스트림의 스냅샷을 동시 실행 제한 안에서 생성합니다
+func (m *Manager) refreshAll(ctx context.Context) {
+	m.mutex.RLock()
+	entries := make([]*entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	m.mutex.RUnlock()
+
+	work := make(chan *entry)
+	var wg sync.WaitGroup
+	for i := 0; i < m.config.MaxConcurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range work {
+				m.refreshEntry(ctx, e)
+			}
+		}()
+	}
+
+	for _, e := range entries {
+		select {
+		case work <- e:
+		case <-ctx.Done():
+		}
+	}
+	close(work)
+	wg.Wait()
+}
+
+// refreshEntry는 백그라운드 갱신에서 스트림 하나의 스냅샷을 생성합니다 (요청 처리 중이면 건너뜀)
+func (m *Manager) refreshEntry(ctx context.Context, e *entry) {
+	if ctx.Err() != nil || !e.mutex.TryLock() {
+		return
+	}
+	defer e.mutex.Unlock()
+
+	if err := m.refresh(ctx, e); err != nil && !errors.Is(err, ErrNoSnapshot) && ctx.Err() == nil {
+		m.logger.Debug("Failed to refresh snapshot",
+			zap.String("stream_id", e.streamID),
+			zap.Error(err),
+		)
+	}
+}
+
+// refresh는 새 키프레임이 있으면 스냅샷을 생성합니다 (e.mutex를 잡은 상태로 호출)
+func (m *Manager) refresh(ctx context.Context, e *entry) error {
+	if e.cache == nil {
+		return ErrNoSnapshot
+	}
+
+	kf := e.cache.get()
+	if kf.version == 0 {
+		return ErrNoSnapshot
+	}
+	if kf.version == e.version {
+		return nil
+	}
+	if kf.version == e.failedVersion {
+		return e.failedErr
+	}
+
+	data, err := m.decode(ctx, e.streamID, kf)
+	if err != nil {
+		// 요청 취소는 다음 요청에서 다시 시도
+		if ctx.Err() == nil {
+			e.failedVersion = kf.version
+			e.failedErr = err
+		}
+		return err
+	}
+
+	e.jpeg = data
+	e.taken = kf.received
+	e.generated = time.Now()
+	e.version = kf.version
+	e.variants = make(map[size][]byte)
+	return nil
+}
+
+// decode는 키프레임을 JPEG로 변환합니다
+func (m *Manager) decode(ctx context.Context, streamID string, kf keyframe) ([]byte, error) {
+	// MJPEG 프레임은 이미 JPEG
+	if kf.codec == "MJPEG" {
+		return kf.data, nil
+	}
+
+	if m.config.DecoderCommand == "" || m.config.ProcessManager == nil {
+		return nil, fmt.Errorf("%w for %s", ErrDecoderNotConfigured, kf.codec)
+	}
+
+	// 디코더 명령의 $CODEC은 ffmpeg 입력 형식 이름
+	codec := "h264"
+	if kf.codec == "H265" {
+		codec = "hevc"
+	}
+
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+
+	data, err := m.config.ProcessManager.Run(ctx, "snapshot-"+streamID, m.config.DecoderCommand,
+		[]string{"CODEC=" + codec, "STREAM_ID=" + streamID}, kf.data)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		return nil, fmt.Errorf("snapshot decoder output is not a JPEG image (%d bytes)", len(data))
+	}
+
+	return data, nil
+}
@@ -0,0 +1,88 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+)
+
+// fitSize는 원본 비율을 유지하며 width x height 안에 들어가는 크기를 계산합니다
+// 한쪽이 0이면 다른 쪽에 맞추고, 원본보다 크게 확대하지 않습니다
+func fitSize(srcWidth, srcHeight, width, height int) (int, int) {
+	if width <= 0 || width > srcWidth {
+		width = srcWidth
+	}
+	if height <= 0 || height > srcHeight {
+		height = srcHeight
+	}
+
+	// 비율을 유지하도록 더 많이 줄어드는 쪽에 맞춤
+	if width*srcHeight < height*srcWidth {
+		height = max(1, (srcHeight*width+srcWidth/2)/srcWidth)
+	} else {
+		width = max(1, (srcWidth*height+srcHeight/2)/srcHeight)
+	}
+	return width, height
+}
+
+// resizeJPEG는 JPEG 이미지를 width x height 안에 들어가도록 줄입니다 (순수 Go, 영역 평균)
+func resizeJPEG(data []byte, width, height, quality int) ([]byte, error) {
+	src, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	bounds := src.Bounds()
+	dstWidth, dstHeight := fitSize(bounds.Dx(), bounds.Dy(), width, height)
+	if dstWidth == bounds.Dx() && dstHeight == bounds.Dy() {
+		return data, nil
+	}
+
+	// YCbCr → RGBA 변환은 표준 라이브러리 고속 경로 사용
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleArea(rgba, dstWidth, dstHeight), &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleArea는 대상 픽셀이 덮는 원본 영역의 평균으로 이미지를 축소합니다
+func scaleArea(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for dy := 0; dy < height; dy++ {
+		sy0 := dy * srcHeight / height
+		sy1 := max(sy0+1, (dy+1)*srcHeight/height)
+
+		for dx := 0; dx < width; dx++ {
+			sx0 := dx * srcWidth / width
+			sx1 := max(sx0+1, (dx+1)*srcWidth/width)
+
+			var r, g, b, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				row := src.Pix[sy*src.Stride+sx0*4 : sy*src.Stride+sx1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint32(row[i])
+					g += uint32(row[i+1])
+					b += uint32(row[i+2])
+					a += uint32(row[i+3])
+					n++
+				}
+			}
+
+			offset := dy*dst.Stride + dx*4
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,219 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"github.com/yourusername/cctv3/internal/secret"
+	"go.uber.org/zap"
+)
+
+// Client는 RTSP 이외의 소스(RTMP, SRT, HLS, UDP MPEG-TS, HTTP MJPEG) 클라이언트
+// 수신한 프레임을 packetizer로 RTP 패킷으로 변환해 OnPacket으로 전달하며,
+// 재연결 동작은 rtsp.Client와 같습니다
+type Client struct {
+	sourceType string
+	config     Config
+
+	// 상태
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	logger    *zap.Logger
+	connected bool
+	mutex     sync.RWMutex
+
+	// 통계
+	packetsReceived uint64
+	bytesReceived   uint64
+}
+
+// newClient는 새로운 소스 클라이언트를 생성합니다
+func newClient(sourceType string, config Config) *Client {
+	// 기본값 설정 (rtsp.NewClient와 동일)
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.RetryCount == 0 {
+		config.RetryCount = 3
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Client{
+		sourceType: sourceType,
+		config:     config,
+		ctx:        ctx,
+		ctxCancel:  cancel,
+		logger:     config.Logger.With(zap.String("source_type", sourceType)),
+	}
+}
+
+// Start는 스트림 수신을 시작합니다
+func (c *Client) Start() error {
+	c.logger.Info("Starting source client",
+		zap.String("url", secret.MaskURL(c.config.URL)),
+	)
+
+	go c.runWithRetry()
+
+	return nil
+}
+
+// Stop은 클라이언트를 종료합니다
+func (c *Client) Stop() {
+	c.logger.Info("Stopping source client")
+	c.ctxCancel()
+}
+
+// IsConnected는 연결 상태를 반환합니다
+func (c *Client) IsConnected() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.connected
+}
+
+// GetStats는 통계 정보를 반환합니다
+func (c *Client) GetStats() (packetsReceived, bytesReceived uint64) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.packetsReceived, c.bytesReceived
+}
+
+// runWithRetry는 재연결 로직과 함께 실행합니다
+func (c *Client) runWithRetry() {
+	attempt := 0
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.logger.Info("Source client stopped")
+			return
+		default:
+		}
+
+		attempt++
+		c.logger.Info("Connecting to source",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", c.config.RetryCount),
+		)
+
+		err := c.run()
+
+		// 정상 종료
+		if c.ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("%s source closed", c.sourceType)
+		}
+
+		// 에러 발생
+		c.logger.Error("Source connection failed",
+			zap.Error(err),
+			zap.Int("attempt", attempt),
+		)
+
+		c.setConnected(false)
+
+		if c.config.OnDisconnect != nil {
+			c.config.OnDisconnect(err)
+		}
+
+		// 최대 재시도 횟수 체크 (음수면 무한 재시도)
+		if c.config.RetryCount > 0 && attempt >= c.config.RetryCount {
+			c.logger.Error("Max retry attempts reached, giving up",
+				zap.Int("attempts", attempt),
+			)
+			return
+		}
+
+		// 재연결 대기
+		c.logger.Info("Retrying connection",
+			zap.Duration("delay", c.config.RetryDelay),
+		)
+
+		select {
+		case <-time.After(c.config.RetryDelay):
+			continue
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// run은 소스 종류별로 연결 및 수신을 처리합니다
+// 연결마다 새 Packetizer를 사용합니다
+func (c *Client) run() error {
+	p := packetizer.New(c.config.Stream, c.handleRTPPacket, c.logger)
+
+	switch c.sourceType {
+	case TypeRTMP:
+		return c.runRTMP(p)
+	case TypeSRT:
+		return c.runSRT(p)
+	case TypeHLS:
+		return c.runHLS(p)
+	case TypeUDP:
+		return c.runUDP(p)
+	case TypeMJPEG:
+		return c.runMJPEG(p)
+	case TypeTest:
+		return c.runTestSrc(p)
+	default:
+		return fmt.Errorf("unsupported source type: %s", c.sourceType)
+	}
+}
+
+// ready는 트랙 설정이 끝나 수신이 시작되었음을 알립니다
+func (c *Client) ready(p *packetizer.Packetizer) {
+	c.logger.Info("Source playback started",
+		zap.String("video_codec", p.VideoCodec()),
+		zap.Bool("audio", p.HasAudio()),
+	)
+
+	c.setConnected(true)
+
+	if c.config.OnConnect != nil {
+		c.config.OnConnect()
+	}
+}
+
+// closeOnStop은 수신 중 Stop이 호출되면 closeFn으로 연결을 닫아 블로킹된 읽기를 깨웁니다
+// 반환된 함수는 수신이 끝났을 때 호출해야 합니다
+func (c *Client) closeOnStop(closeFn func()) (done func()) {
+	doneCh := make(chan struct{})
+	go func() {
+		select {
+		case <-c.ctx.Done():
+			closeFn()
+		case <-doneCh:
+		}
+	}()
+	return func() { close(doneCh) }
+}
+
+// setConnected는 연결 상태를 설정합니다
+func (c *Client) setConnected(connected bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.connected = connected
+}
+
+// handleRTPPacket은 변환된 RTP 패킷을 처리합니다
+func (c *Client) handleRTPPacket(pkt *rtp.Packet) {
+	c.mutex.Lock()
+	c.packetsReceived++
+	c.bytesReceived += uint64(len(pkt.Payload))
+	c.mutex.Unlock()
+
+	if c.config.OnPacket != nil {
+		c.config.OnPacket(pkt)
+	}
+}
@@ -0,0 +1,585 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/yourusername/cctv3/internal/secret"
+	"go.uber.org/zap"
+)
+
+// failoverTick은 패킷 감시와 주 소스 복귀 확인 주기
+const failoverTick = time.Second
+
+// FailoverStatus는 예비 소스가 있는 스트림의 현재 소스 상태입니다
+type FailoverStatus struct {
+	Active    int       // 활성 소스 순번 (0=주 소스, 1부터 예비 소스, 연결 중이 아니면 마지막으로 시도한 소스)
+	URL       string    // 활성 소스 URL (인증 정보 포함)
+	Connected bool      // 활성 소스 연결 여부
+	Since     time.Time // 활성 소스로 전환된 시각
+	Failovers uint64    // 다른 소스로 전환한 횟수 (장애)
+	Failbacks uint64    // 주 소스로 복귀한 횟수
+	Probing   bool      // 주 소스 복귀를 위해 시험 연결 중
+}
+
+// failoverChild는 Failover가 만든 단일 소스 하나입니다 (소스를 시작할 때마다 새 세대)
+type failoverChild struct {
+	gen   uint64
+	index int
+	src   Source
+
+	// 아래 필드는 Failover.mutex로 보호
+	codec       string // 소스가 감지한 비디오 코덱 (활성일 때만 스트림에 반영)
+	connected   bool
+	connectedAt time.Time
+	lastPacket  time.Time
+}
+
+// failoverEvent는 소스 연결/끊김 알림입니다
+type failoverEvent struct {
+	gen       uint64
+	connected bool
+	err       error
+}
+
+// childCodec은 소스의 코덱 감지를 Failover로 전달합니다 (StreamCodecSetter)
+type childCodec struct {
+	f     *Failover
+	child *failoverChild
+}
+
+// SetVideoCodec은 StreamCodecSetter 인터페이스 구현
+func (c childCodec) SetVideoCodec(codec string) {
+	c.f.setChildCodec(c.child, codec)
+}
+
+// Failover는 우선순위 순서의 여러 소스(주 소스, 예비 소스) 중 하나로 스트림을 수신하는 Source입니다
+//   - 활성 소스가 끊기거나 Timeout 동안 패킷이 없으면 다음 순서의 소스로 전환합니다
+//   - FailbackAfter가 있으면 예비 소스 사용 중 주 소스를 시험 연결하고,
+//     그 시간 동안 끊김 없이 수신되면 다음 키프레임에서 주 소스로 복귀합니다
+//   - 전환 시 SSRC/시퀀스 번호/타임스탬프를 이어 붙여 구독자는 재연결 없이 계속 받습니다
+//
+// 모든 소스를 한 번씩 시도해도 연결되지 않으면 RetryDelay 후 주 소스부터 다시 시도하며,
+// RetryCount번 반복해도 실패하면 포기합니다 (음수면 무한 재시도)
+type Failover struct {
+	config Config
+	urls   []string
+	logger *zap.Logger
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	events    chan failoverEvent
+
+	mutex     sync.Mutex
+	nextGen   uint64
+	active    *failoverChild // 활성 소스 (패킷을 스트림으로 전달)
+	probe     *failoverChild // 주 소스 복귀 시험 연결 (패킷은 버림)
+	pending   *failoverChild // 키프레임을 기다리는 복귀 대상 (probe와 같음)
+	lastIndex int            // 마지막으로 시작한 활성 소스 순번
+	waitKey   bool           // 활성 소스의 첫 키프레임 대기 중
+	splice    *splicer
+	since     time.Time
+	failovers uint64
+	failbacks uint64
+
+	// 통계 (스트림으로 전달한 패킷)
+	packetsReceived uint64
+	bytesReceived   uint64
+}
+
+var _ Source = (*Failover)(nil)
+
+// newFailover는 주 소스와 예비 소스로 Failover를 생성합니다
+func newFailover(config Config) (*Failover, error) {
+	urls := append([]string{config.URL}, config.BackupURLs...)
+	for _, u := range urls {
+		if _, err := TypeOf(u); err != nil {
+			return nil, err
+		}
+	}
+
+	// 기본값 설정 (rtsp.NewClient와 동일)
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.RetryCount == 0 {
+		config.RetryCount = 3
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Failover{
+		config:    config,
+		urls:      urls,
+		logger:    config.Logger.With(zap.Int("sources", len(urls))),
+		ctx:       ctx,
+		ctxCancel: cancel,
+		events:    make(chan failoverEvent, 16),
+		splice:    newSplicer(),
+	}, nil
+}
+
+// Start는 주 소스부터 수신을 시작합니다
+func (f *Failover) Start() error {
+	f.logger.Info("Starting failover source",
+		zap.String("primary", secret.MaskURL(f.urls[0])),
+		zap.Duration("failback_after", f.config.FailbackAfter),
+	)
+
+	go f.run()
+
+	return nil
+}
+
+// Stop은 모든 소스를 종료합니다
+func (f *Failover) Stop() {
+	f.logger.Info("Stopping failover source")
+	f.ctxCancel()
+}
+
+// IsConnected는 활성 소스의 연결 상태를 반환합니다
+func (f *Failover) IsConnected() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.active != nil && f.active.connected
+}
+
+// GetStats는 스트림으로 전달한 패킷 통계를 반환합니다
+func (f *Failover) GetStats() (packetsReceived, bytesReceived uint64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.packetsReceived, f.bytesReceived
+}
+
+// Status는 활성 소스와 전환 횟수를 반환합니다
+func (f *Failover) Status() FailoverStatus {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	status := FailoverStatus{
+		Active:    f.lastIndex,
+		URL:       f.urls[f.lastIndex],
+		Since:     f.since,
+		Failovers: f.failovers,
+		Failbacks: f.failbacks,
+		Probing:   f.probe != nil,
+	}
+	if f.active != nil {
+		status.Connected = f.active.connected
+	}
+	return status
+}
+
+// run은 소스 연결/끊김 알림과 패킷 감시로 활성 소스를 관리합니다
+func (f *Failover) run() {
+	ticker := time.NewTicker(failoverTick)
+	defer ticker.Stop()
+
+	var (
+		failed    int       // 연결 없이 연속으로 실패한 소스 수
+		rounds    int       // 모든 소스가 실패한 횟수
+		nextProbe time.Time // 다음 주 소스 시험 연결 시각
+	)
+
+	f.startActive(0)
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			f.stopAll()
+			f.logger.Info("Failover source stopped")
+			return
+
+		case ev := <-f.events:
+			f.mutex.Lock()
+			active, probe := f.active, f.probe
+			f.mutex.Unlock()
+
+			switch {
+			case active != nil && ev.gen == active.gen:
+				if ev.connected {
+					failed, rounds = 0, 0
+					f.onActiveConnected(active)
+					continue
+				}
+				wasConnected := f.markDisconnected(active)
+				if wasConnected {
+					failed = 0
+				}
+				failed++
+				if !f.failActive(active, ev.err, wasConnected, &failed, &rounds) {
+					return
+				}
+
+			case probe != nil && ev.gen == probe.gen:
+				if ev.connected {
+					f.mutex.Lock()
+					probe.connected = true
+					probe.connectedAt = time.Now()
+					probe.lastPacket = probe.connectedAt
+					f.mutex.Unlock()
+					f.logger.Info("Primary source reachable, checking stability",
+						zap.Duration("failback_after", f.config.FailbackAfter),
+					)
+					continue
+				}
+				f.stopProbe(probe)
+				nextProbe = time.Now().Add(f.config.RetryDelay)
+			}
+
+		case now := <-ticker.C:
+			f.mutex.Lock()
+			active, probe, pending := f.active, f.probe, f.pending
+			var stalled bool
+			if active != nil && active.connected && now.Sub(active.lastPacket) > f.config.Timeout {
+				stalled = true
+			}
+			probeStable := probe != nil && pending == nil && probe.connected &&
+				now.Sub(probe.connectedAt) >= f.config.FailbackAfter &&
+				now.Sub(probe.lastPacket) <= f.config.Timeout
+			probeStalled := probe != nil && probe.connected && now.Sub(probe.lastPacket) > f.config.Timeout
+			f.mutex.Unlock()
+
+			if stalled {
+				f.markDisconnected(active)
+				failed = 1
+				if !f.failActive(active, fmt.Errorf("no packets received for %s", f.config.Timeout), true, &failed, &rounds) {
+					return
+				}
+				continue
+			}
+
+			// 주 소스 복귀: 시험 연결이 안정적이면 다음 키프레임에서 전환
+			if probeStalled {
+				f.stopProbe(probe)
+				nextProbe = now.Add(f.config.RetryDelay)
+			} else if probeStable {
+				f.mutex.Lock()
+				f.pending = probe
+				f.mutex.Unlock()
+				f.logger.Info("Primary source stable, switching back at next keyframe")
+			} else if probe == nil && f.config.FailbackAfter > 0 && active != nil && active.index > 0 &&
+				active.connected && now.After(nextProbe) {
+				f.startProbe()
+			}
+		}
+	}
+}
+
+// onActiveConnected는 활성 소스 연결을 알립니다
+func (f *Failover) onActiveConnected(active *failoverChild) {
+	f.mutex.Lock()
+	active.connected = true
+	active.connectedAt = time.Now()
+	active.lastPacket = active.connectedAt
+	f.mutex.Unlock()
+
+	f.logger.Info("Failover source connected",
+		zap.Int("index", active.index),
+		zap.String("url", secret.MaskURL(f.urls[active.index])),
+	)
+
+	if f.config.OnConnect != nil {
+		f.config.OnConnect()
+	}
+}
+
+// markDisconnected는 소스를 끊긴 상태로 표시하고 이전 연결 여부를 반환합니다
+func (f *Failover) markDisconnected(child *failoverChild) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	wasConnected := child.connected
+	child.connected = false
+	return wasConnected
+}
+
+// failActive는 활성 소스를 정리하고 다음 소스를 시작합니다 (포기하면 false)
+func (f *Failover) failActive(active *failoverChild, err error, wasConnected bool, failed, rounds *int) bool {
+	active.src.Stop()
+
+	f.logger.Warn("Failover source failed",
+		zap.Int("index", active.index),
+		zap.String("url", secret.MaskURL(f.urls[active.index])),
+		zap.Error(err),
+	)
+
+	// 연결되어 있던 소스가 끊기면 스트림 준비 해제 (다음 소스가 연결되면 다시 준비)
+	if wasConnected && f.config.OnDisconnect != nil {
+		f.config.OnDisconnect(err)
+	}
+
+	// 복귀 대기 중이던 주 소스가 있으면 바로 활성 소스로
+	f.mutex.Lock()
+	probe := f.probe
+	if probe != nil && probe.connected {
+		f.active = nil // 이미 종료함
+		f.mutex.Unlock()
+		f.promote(probe)
+		return true
+	}
+	f.mutex.Unlock()
+	if probe != nil {
+		f.stopProbe(probe)
+	}
+
+	next := (active.index + 1) % len(f.urls)
+
+	// 모든 소스가 연결 없이 실패하면 대기 후 주 소스부터 다시
+	if *failed >= len(f.urls) {
+		*failed = 0
+		*rounds++
+		if f.config.RetryCount > 0 && *rounds >= f.config.RetryCount {
+			f.logger.Error("All sources failed, giving up", zap.Int("rounds", *rounds))
+			f.stopAll()
+			return false
+		}
+
+		f.logger.Info("All sources failed, retrying",
+			zap.Duration("delay", f.config.RetryDelay),
+			zap.Int("round", *rounds),
+		)
+		select {
+		case <-time.After(f.config.RetryDelay):
+		case <-f.ctx.Done():
+			return true
+		}
+		next = 0
+	}
+
+	f.startActive(next)
+	return true
+}
+
+// newChild는 소스 하나를 생성합니다 (재시도는 Failover가 처리하므로 1회만 시도)
+func (f *Failover) newChild(index int) (*failoverChild, error) {
+	f.mutex.Lock()
+	f.nextGen++
+	child := &failoverChild{gen: f.nextGen, index: index}
+	f.mutex.Unlock()
+
+	notify := func(ev failoverEvent) {
+		select {
+		case f.events <- ev:
+		case <-f.ctx.Done():
+		}
+	}
+
+	config := f.config
+	config.URL = f.urls[index]
+	config.BackupURLs = nil
+	config.RetryCount = 1
+	config.Logger = f.config.Logger.With(zap.Int("source_index", index))
+	config.Stream = childCodec{f: f, child: child}
+	config.OnPacket = func(pkt *rtp.Packet) {
+		f.handlePacket(child, pkt)
+	}
+	config.OnConnect = func() {
+		notify(failoverEvent{gen: child.gen, connected: true})
+	}
+	config.OnDisconnect = func(err error) {
+		notify(failoverEvent{gen: child.gen, err: err})
+	}
+
+	src, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+	child.src = src
+	return child, nil
+}
+
+// startActive는 index 순번의 소스를 활성 소스로 시작합니다
+func (f *Failover) startActive(index int) {
+	child, err := f.newChild(index)
+	if err == nil {
+		err = child.src.Start()
+	}
+	if err != nil {
+		// 생성 실패는 연결 실패와 같이 처리 (다음 소스로)
+		f.logger.Error("Failed to create failover source", zap.Int("index", index), zap.Error(err))
+		child = &failoverChild{index: index, src: nopSource{}}
+		f.mutex.Lock()
+		f.nextGen++
+		child.gen = f.nextGen
+		f.mutex.Unlock()
+		go func() {
+			select {
+			case f.events <- failoverEvent{gen: child.gen, err: err}:
+			case <-f.ctx.Done():
+			}
+		}()
+	}
+
+	f.mutex.Lock()
+	if f.active != nil && f.lastIndex != index {
+		f.failovers++
+	}
+	f.active = child
+	f.lastIndex = index
+	f.waitKey = true
+	f.since = time.Now()
+	f.mutex.Unlock()
+
+	if index > 0 {
+		f.logger.Warn("Switching to backup source",
+			zap.Int("index", index),
+			zap.String("url", secret.MaskURL(f.urls[index])),
+		)
+	}
+}
+
+// startProbe는 주 소스 복귀를 위한 시험 연결을 시작합니다
+func (f *Failover) startProbe() {
+	child, err := f.newChild(0)
+	if err == nil {
+		err = child.src.Start()
+	}
+	if err != nil {
+		f.logger.Warn("Failed to start primary source probe", zap.Error(err))
+		return
+	}
+
+	f.mutex.Lock()
+	f.probe = child
+	f.mutex.Unlock()
+
+	f.logger.Info("Probing primary source for failback")
+}
+
+// stopProbe는 주 소스 시험 연결을 종료합니다
+func (f *Failover) stopProbe(probe *failoverChild) {
+	f.mutex.Lock()
+	if f.probe == probe {
+		f.probe, f.pending = nil, nil
+	}
+	f.mutex.Unlock()
+	probe.src.Stop()
+}
+
+// switchBack은 주 소스 시험 연결을 활성 소스로 바꾸고 이전 활성 소스를 반환합니다 (f.mutex를 쥔 상태로 호출)
+func (f *Failover) switchBack(child *failoverChild) *failoverChild {
+	previous := f.active
+	f.active = child
+	f.probe, f.pending = nil, nil
+	f.lastIndex = child.index
+	f.since = time.Now()
+	f.failbacks++
+	return previous
+}
+
+// finishSwitchBack은 주 소스로 복귀한 뒤 코덱을 반영하고 이전 활성 소스를 종료합니다
+func (f *Failover) finishSwitchBack(child, previous *failoverChild) {
+	f.mutex.Lock()
+	codec := child.codec
+	f.mutex.Unlock()
+
+	if codec != "" && f.config.Stream != nil {
+		f.config.Stream.SetVideoCodec(codec)
+	}
+	if previous != nil && previous != child {
+		previous.src.Stop()
+	}
+
+	f.logger.Info("Switched back to primary source",
+		zap.String("url", secret.MaskURL(f.urls[child.index])),
+	)
+}
+
+// promote는 활성 소스가 끊겼을 때 연결되어 있던 주 소스 시험 연결을 바로 활성 소스로 사용합니다
+func (f *Failover) promote(child *failoverChild) {
+	f.mutex.Lock()
+	previous := f.switchBack(child)
+	f.waitKey = true
+	f.mutex.Unlock()
+
+	f.finishSwitchBack(child, previous)
+	if f.config.OnConnect != nil {
+		f.config.OnConnect()
+	}
+}
+
+// stopAll은 활성 소스와 시험 연결을 종료합니다
+func (f *Failover) stopAll() {
+	f.mutex.Lock()
+	active, probe := f.active, f.probe
+	f.active, f.probe, f.pending = nil, nil, nil
+	f.mutex.Unlock()
+
+	if active != nil {
+		active.src.Stop()
+	}
+	if probe != nil {
+		probe.src.Stop()
+	}
+}
+
+// setChildCodec은 소스가 감지한 코덱을 기록하고 활성 소스면 스트림에 반영합니다
+func (f *Failover) setChildCodec(child *failoverChild, codec string) {
+	f.mutex.Lock()
+	child.codec = codec
+	isActive := f.active == child
+	f.mutex.Unlock()
+
+	if isActive && f.config.Stream != nil {
+		f.config.Stream.SetVideoCodec(codec)
+	}
+}
+
+// handlePacket은 소스의 패킷을 이어 붙여 스트림으로 전달합니다
+// 전환 직후에는 새 소스의 키프레임부터 전달하고, 복귀 대상 주 소스는 키프레임에서 활성 소스가 됩니다
+func (f *Failover) handlePacket(child *failoverChild, pkt *rtp.Packet) {
+	now := time.Now()
+
+	f.mutex.Lock()
+	child.lastPacket = now
+
+	var switched bool
+	var previous *failoverChild
+	if child == f.pending && isRandomAccess(child.codec, pkt.Payload) {
+		// 주 소스 복귀: 이전 활성 소스의 패킷은 여기서부터 버려짐
+		previous = f.switchBack(child)
+		f.waitKey = false
+		switched = true
+	}
+
+	if child != f.active {
+		f.mutex.Unlock()
+		return
+	}
+	if f.waitKey {
+		if !isRandomAccess(child.codec, pkt.Payload) {
+			f.mutex.Unlock()
+			return
+		}
+		f.waitKey = false
+	}
+
+	out := f.splice.rewrite(child.gen, pkt, now)
+	f.packetsReceived++
+	f.bytesReceived += uint64(len(pkt.Payload))
+	f.mutex.Unlock()
+
+	if switched {
+		// 이전 소스 종료는 패킷 콜백 밖에서
+		go f.finishSwitchBack(child, previous)
+	}
+
+	if f.config.OnPacket != nil {
+		f.config.OnPacket(out)
+	}
+}
+
+// nopSource는 생성에 실패한 소스 자리를 채웁니다
+type nopSource struct{}
+
+func (nopSource) Start() error               { return errors.New("source not created") }
+func (nopSource) Stop()                      {}
+func (nopSource) IsConnected() bool          { return false }
+func (nopSource) GetStats() (uint64, uint64) { return 0, 0 }
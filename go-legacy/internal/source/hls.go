@@ -0,0 +1,105 @@
+package source
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bluenviron/gohlslib/v2"
+	"github.com/bluenviron/gohlslib/v2/pkg/codecs"
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"go.uber.org/zap"
+)
+
+// runHLS는 HLS(.m3u8) 스트림을 가져옵니다 (MPEG-TS 및 fMP4 세그먼트)
+func (c *Client) runHLS(p *packetizer.Packetizer) error {
+	var hc *gohlslib.Client
+	hc = &gohlslib.Client{
+		URI:        c.config.URL,
+		HTTPClient: &http.Client{Timeout: c.config.Timeout},
+		OnDownloadPrimaryPlaylist: func(u string) {
+			c.logger.Debug("Downloading HLS primary playlist", zap.String("url", u))
+		},
+		OnDownloadStreamPlaylist: func(u string) {
+			c.logger.Debug("Downloading HLS stream playlist", zap.String("url", u))
+		},
+		OnDownloadSegment: func(u string) {
+			c.logger.Debug("Downloading HLS segment", zap.String("url", u))
+		},
+		OnDownloadPart: func(u string) {
+			c.logger.Debug("Downloading HLS part", zap.String("url", u))
+		},
+		OnDecodeError: func(err error) {
+			c.logger.Warn("HLS decode error", zap.Error(err))
+		},
+		OnTracks: func(tracks []*gohlslib.Track) error {
+			if err := setupHLSTracks(hc, tracks, p); err != nil {
+				return err
+			}
+			c.ready(p)
+			return nil
+		},
+	}
+
+	if err := hc.Start(); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-hc.Wait():
+		hc.Close()
+		return err
+
+	case <-c.ctx.Done():
+		hc.Close()
+		return nil
+	}
+}
+
+// setupHLSTracks는 HLS 트랙별 콜백을 등록합니다
+// 첫 번째 H.264/H.265 트랙과 첫 번째 AAC 트랙만 사용합니다
+func setupHLSTracks(hc *gohlslib.Client, tracks []*gohlslib.Track, p *packetizer.Packetizer) error {
+	for _, track := range tracks {
+		clockRate := track.ClockRate
+
+		switch codec := track.Codec.(type) {
+		case *codecs.H264:
+			if p.HasVideo() {
+				continue
+			}
+			if err := p.InitH264(codec.SPS, codec.PPS); err != nil {
+				return err
+			}
+			hc.OnDataH26x(track, func(pts int64, _ int64, au [][]byte) {
+				p.WriteH264(packetizer.TimestampToDuration(pts, clockRate), au)
+			})
+
+		case *codecs.H265:
+			if p.HasVideo() {
+				continue
+			}
+			if err := p.InitH265(codec.VPS, codec.SPS, codec.PPS); err != nil {
+				return err
+			}
+			hc.OnDataH26x(track, func(pts int64, _ int64, au [][]byte) {
+				p.WriteH265(packetizer.TimestampToDuration(pts, clockRate), au)
+			})
+
+		case *codecs.MPEG4Audio:
+			if p.HasAudio() {
+				continue
+			}
+			if err := p.InitAAC(codec.Config.SampleRate); err != nil {
+				return err
+			}
+			hc.OnDataMPEG4Audio(track, func(pts int64, aus [][]byte) {
+				p.WriteAAC(packetizer.TimestampToDuration(pts, clockRate), aus)
+			})
+		}
+	}
+
+	if !p.HasVideo() {
+		return errors.New("HLS stream has no H264/H265 video track")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/packetizer"
+)
+
+// maxJPEGSize는 JPEG 한 장의 최대 크기 (잘못된 스트림으로 인한 메모리 폭증 방지)
+const maxJPEGSize = 10 * 1024 * 1024
+
+// runMJPEG는 HTTP MJPEG(multipart/x-mixed-replace) 스트림을 가져옵니다
+// 구형 IP 카메라의 /video.cgi, /mjpg/video.mjpg 등 (URL의 사용자 정보는 Basic 인증으로 전송)
+func (c *Client) runMJPEG(p *packetizer.Packetizer) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.config.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = c.config.Timeout
+	client := &http.Client{Transport: transport}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad status code: %d", res.StatusCode)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return fmt.Errorf("not an MJPEG stream (Content-Type: %s)", res.Header.Get("Content-Type"))
+	}
+
+	// 프레임이 timeout 동안 오지 않으면 연결을 닫아 읽기를 중단
+	watchdog := time.AfterFunc(c.config.Timeout, func() { res.Body.Close() })
+	defer watchdog.Stop()
+
+	mr := multipart.NewReader(res.Body, params["boundary"])
+	start := time.Now()
+
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			return err
+		}
+
+		image, err := io.ReadAll(io.LimitReader(part, maxJPEGSize+1))
+		part.Close()
+		if err != nil {
+			return err
+		}
+		if len(image) > maxJPEGSize {
+			return errors.New("JPEG frame too large")
+		}
+
+		// JPEG 이외의 파트는 무시 (SOI 마커로 확인)
+		if len(image) < 2 || image[0] != 0xFF || image[1] != 0xD8 {
+			continue
+		}
+
+		watchdog.Reset(c.config.Timeout)
+
+		if !p.HasVideo() {
+			if err := p.InitMJPEG(); err != nil {
+				return err
+			}
+			c.ready(p)
+		}
+
+		p.WriteJPEG(time.Since(start), image)
+	}
+}
@@ -0,0 +1,63 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/bluenviron/gortmplib"
+	"github.com/yourusername/cctv3/internal/packetizer"
+)
+
+// runRTMP는 RTMP 서버에서 스트림을 재생(play)합니다
+// rtmp://host[:1935]/app/stream, rtmps://host[:443]/app/stream
+func (c *Client) runRTMP(p *packetizer.Packetizer) error {
+	u, err := url.Parse(c.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	// 포트 기본값
+	if u.Port() == "" {
+		port := "1935"
+		if u.Scheme == "rtmps" {
+			port = "443"
+		}
+		u.Host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.Timeout)
+	conn := &gortmplib.Client{URL: u, Publish: false}
+	err = conn.Initialize(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	done := c.closeOnStop(conn.Close)
+	defer done()
+
+	nconn := conn.NetConn()
+	nconn.SetReadDeadline(time.Now().Add(c.config.Timeout))
+
+	r := &gortmplib.Reader{Conn: conn}
+	if err := r.Initialize(); err != nil {
+		return fmt.Errorf("failed to read tracks: %w", err)
+	}
+
+	if err := packetizer.SetupRTMP(r, p); err != nil {
+		return err
+	}
+
+	c.ready(p)
+
+	for {
+		nconn.SetReadDeadline(time.Now().Add(c.config.Timeout))
+		if err := r.Read(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package source는 스트림 입력 소스를 URL 스킴에 따라 생성합니다
+// RTSP는 rtsp.Client를 사용하고, 그 외(RTMP, SRT, HLS, UDP MPEG-TS, HTTP MJPEG, 내장 테스트 패턴)는 Client가 처리합니다
+// 예비 소스가 있는 스트림은 Failover가 소스 사이를 전환합니다
+package source
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/yourusername/cctv3/internal/rtsp"
+	"github.com/yourusername/cctv3/internal/testsrc"
+	"go.uber.org/zap"
+)
+
+// 소스 종류
+const (
+	TypeRTSP  = "rtsp"
+	TypeRTMP  = "rtmp"
+	TypeSRT   = "srt"
+	TypeHLS   = "hls"
+	TypeUDP   = "udp"
+	TypeMJPEG = "mjpeg"
+	TypeTest  = "testsrc"
+)
+
+// Source는 외부에서 스트림을 가져와 RTP 패킷으로 전달하는 입력입니다
+// 연결이 끊어지면 RetryCount/RetryDelay에 따라 재연결합니다
+type Source interface {
+	Start() error
+	Stop()
+	IsConnected() bool
+	GetStats() (packetsReceived, bytesReceived uint64)
+}
+
+var _ Source = (*rtsp.Client)(nil)
+
+// Config는 소스 설정 (rtsp.ClientConfig와 동일한 의미)
+type Config struct {
+	URL           string
+	BackupURLs    []string      // 예비 소스 (우선순위 순서, 있으면 Failover로 수신)
+	FailbackAfter time.Duration // 주 소스가 이 시간 동안 안정적이면 복귀 (0이면 자동 복귀 안 함)
+	Transport     string        // RTSP 전용: "tcp" or "udp"
+	Timeout       time.Duration
+	RetryCount    int
+	RetryDelay    time.Duration
+	Logger        *zap.Logger
+	Stream        rtsp.StreamCodecSetter // 스트림 객체 (코덱 설정용)
+	OnPacket      func(*rtp.Packet)
+	OnConnect     func()
+	OnDisconnect  func(error)
+}
+
+// TypeOf는 URL 스킴으로 소스 종류를 결정합니다
+//   - rtsp://, rtsps://          → rtsp
+//   - rtmp://, rtmps://          → rtmp
+//   - srt://                     → srt
+//   - http(s)://....m3u8         → hls
+//   - udp://                     → udp (MPEG-TS, 멀티캐스트 가능)
+//   - 그 외 http(s)://           → mjpeg (multipart/x-mixed-replace)
+//   - testsrc://                 → testsrc (내장 테스트 패턴, 외부 연결 없음)
+func TypeOf(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL: %w", err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "rtsp", "rtsps":
+		return TypeRTSP, nil
+	case "rtmp", "rtmps":
+		return TypeRTMP, nil
+	case "srt":
+		return TypeSRT, nil
+	case "udp":
+		return TypeUDP, nil
+	case "http", "https":
+		if strings.HasSuffix(strings.ToLower(u.Path), ".m3u8") {
+			return TypeHLS, nil
+		}
+		return TypeMJPEG, nil
+	case testsrc.Scheme:
+		return TypeTest, nil
+	case "":
+		return "", fmt.Errorf("source URL has no scheme: %s", rawURL)
+	default:
+		return "", fmt.Errorf("unsupported source scheme '%s' (supported: rtsp, rtsps, rtmp, rtmps, srt, http, https, udp, testsrc)", u.Scheme)
+	}
+}
+
+// Validate는 소스 URL이 지원되는 형식인지 확인합니다
+func Validate(rawURL string) error {
+	typ, err := TypeOf(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if typ == TypeTest {
+		_, err := testsrc.ParseURL(rawURL)
+		return err
+	}
+
+	u, _ := url.Parse(rawURL)
+	if u.Host == "" {
+		return fmt.Errorf("%s source URL requires a host: %s", typ, rawURL)
+	}
+
+	return nil
+}
+
+// New는 URL 스킴에 맞는 소스를 생성합니다 (Start 호출 전까지 연결하지 않음)
+// 예비 소스가 있으면 소스 사이를 전환하는 Failover를 반환합니다
+func New(config Config) (Source, error) {
+	if len(config.BackupURLs) > 0 {
+		return newFailover(config)
+	}
+
+	typ, err := TypeOf(config.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ == TypeRTSP {
+		client, err := rtsp.NewClient(rtsp.ClientConfig{
+			URL:          config.URL,
+			Transport:    config.Transport,
+			Timeout:      config.Timeout,
+			RetryCount:   config.RetryCount,
+			RetryDelay:   config.RetryDelay,
+			Logger:       config.Logger,
+			Stream:       config.Stream,
+			OnPacket:     config.OnPacket,
+			OnConnect:    config.OnConnect,
+			OnDisconnect: config.OnDisconnect,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+
+	if err := Validate(config.URL); err != nil {
+		return nil, err
+	}
+
+	return newClient(typ, config), nil
+}
@@ -0,0 +1,156 @@
+package source
+
+import (
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+// 타임스탬프 간격 추정에 쓰는 기본값
+const (
+	// defaultSpliceClockRate는 클럭을 아직 추정하지 못한 트랙의 기본 RTP 클럭 (비디오 90kHz)
+	defaultSpliceClockRate = 90000
+
+	// minClockEstimateSpan은 클럭 추정에 필요한 최소 수신 시간
+	minClockEstimateSpan = time.Second
+)
+
+// knownClockRates는 추정한 클럭을 맞출 표준 RTP 클럭 목록
+var knownClockRates = []float64{8000, 16000, 22050, 24000, 32000, 44100, 48000, 90000}
+
+// spliceTrack은 페이로드 타입 하나(비디오 또는 오디오)의 이어 붙이기 상태입니다
+type spliceTrack struct {
+	ssrc      uint32 // 출력 SSRC (처음 전송한 소스의 값으로 고정)
+	seqOffset uint16
+	tsOffset  uint32
+
+	// 마지막으로 출력한 패킷
+	lastSeq  uint16
+	lastTS   uint32
+	lastWall time.Time
+
+	// 현재 소스에서 받은 첫 패킷 (클럭 추정용, 원본 타임스탬프)
+	gen       uint64
+	startTS   uint32
+	startWall time.Time
+	clockRate float64
+}
+
+// estimateClockRate는 현재 소스의 타임스탬프 증가량과 경과 시간으로 RTP 클럭을 추정합니다
+func (t *spliceTrack) estimateClockRate(ts uint32, now time.Time) {
+	span := now.Sub(t.startWall)
+	if span < minClockEstimateSpan {
+		return
+	}
+
+	rate := float64(int32(ts-t.startTS)) / span.Seconds()
+	best := knownClockRates[0]
+	for _, known := range knownClockRates {
+		if abs(rate-known) < abs(rate-best) {
+			best = known
+		}
+	}
+	t.clockRate = best
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// splicer는 소스가 바뀌어도 구독자가 하나의 RTP 스트림으로 받도록 SSRC, 시퀀스 번호, 타임스탬프를 이어 붙입니다
+// 새 소스의 첫 패킷은 이전 소스의 마지막 패킷 다음 번호, 경과 시간만큼 진행한 타임스탬프를 받습니다
+// (WebRTC 트랙, HLS Muxer, 녹화가 재협상이나 새 세그먼트 없이 계속 이어짐)
+type splicer struct {
+	tracks map[uint8]*spliceTrack
+}
+
+func newSplicer() *splicer {
+	return &splicer{tracks: make(map[uint8]*spliceTrack)}
+}
+
+// rewrite는 gen 소스의 패킷을 이어 붙인 출력 패킷으로 변환합니다 (원본 패킷은 바꾸지 않음)
+func (s *splicer) rewrite(gen uint64, pkt *rtp.Packet, now time.Time) *rtp.Packet {
+	t, ok := s.tracks[pkt.PayloadType]
+	if !ok {
+		t = &spliceTrack{
+			ssrc:      pkt.SSRC,
+			gen:       gen,
+			startTS:   pkt.Timestamp,
+			startWall: now,
+			clockRate: defaultSpliceClockRate,
+		}
+		s.tracks[pkt.PayloadType] = t
+	} else if t.gen != gen {
+		// 새 소스의 첫 패킷: 이전 출력에 이어지도록 오프셋 계산
+		step := uint32(now.Sub(t.lastWall).Seconds() * t.clockRate)
+		if minStep := uint32(t.clockRate / 30); step < minStep {
+			step = minStep
+		}
+		t.seqOffset = t.lastSeq + 1 - pkt.SequenceNumber
+		t.tsOffset = t.lastTS + step - pkt.Timestamp
+		t.gen = gen
+		t.startTS = pkt.Timestamp
+		t.startWall = now
+	} else {
+		t.estimateClockRate(pkt.Timestamp, now)
+	}
+
+	out := *pkt
+	out.SSRC = t.ssrc
+	out.SequenceNumber += t.seqOffset
+	out.Timestamp += t.tsOffset
+
+	t.lastSeq = out.SequenceNumber
+	t.lastTS = out.Timestamp
+	t.lastWall = now
+	return &out
+}
+
+// isRandomAccess는 RTP 페이로드가 디코딩을 시작할 수 있는 지점(파라미터 셋 또는 키프레임 시작)인지 확인합니다
+// H.264/H.265가 아닌 코덱(MJPEG 등)은 모든 프레임이 독립적이므로 항상 true입니다
+func isRandomAccess(codec string, payload []byte) bool {
+	switch codec {
+	case "H264":
+	case "H265":
+		if len(payload) < 3 {
+			return false
+		}
+		nalType := (payload[0] >> 1) & 0x3f
+		switch nalType {
+		case 48: // AP: 첫 번째 NAL 기준
+			if len(payload) < 5 {
+				return false
+			}
+			nalType = (payload[4] >> 1) & 0x3f
+		case 49: // FU: 시작 조각만
+			if payload[2]&0x80 == 0 {
+				return false
+			}
+			nalType = payload[2] & 0x3f
+		}
+		return nalType >= 16 && nalType <= 21 || nalType >= 32 && nalType <= 34 // IRAP, VPS/SPS/PPS
+	default:
+		return true
+	}
+
+	if len(payload) < 2 {
+		return false
+	}
+	nalType := payload[0] & 0x1f
+	switch nalType {
+	case 24: // STAP-A: 첫 번째 NAL 기준
+		if len(payload) < 4 {
+			return false
+		}
+		nalType = payload[3] & 0x1f
+	case 28: // FU-A: 시작 조각만
+		if payload[1]&0x80 == 0 {
+			return false
+		}
+		nalType = payload[1] & 0x1f
+	}
+	return nalType == 5 || nalType == 7 || nalType == 8 // IDR, SPS, PPS
+}
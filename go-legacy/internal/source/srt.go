@@ -0,0 +1,70 @@
+package source
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/mpegts"
+	srt "github.com/datarhei/gosrt"
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"go.uber.org/zap"
+)
+
+// runSRT는 SRT 서버(caller 모드)에서 MPEG-TS 스트림을 읽습니다
+// srt://host:port?streamid=read:path&passphrase=... (쿼리는 gosrt 설정 이름)
+func (c *Client) runSRT(p *packetizer.Packetizer) error {
+	conf := srt.DefaultConfig()
+	address, err := conf.UnmarshalURL(c.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	conf.ConnectionTimeout = c.config.Timeout
+	conf.PeerIdleTimeout = c.config.Timeout
+
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("invalid SRT options: %w", err)
+	}
+
+	conn, err := srt.Dial("srt", address, conf)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	done := c.closeOnStop(func() { conn.Close() })
+	defer done()
+
+	return c.readMPEGTS(conn, p)
+}
+
+// readMPEGTS는 MPEG-TS 스트림을 읽어 RTP로 변환합니다 (SRT, UDP 공통)
+func (c *Client) readMPEGTS(r io.Reader, p *packetizer.Packetizer) error {
+	mr := &mpegts.Reader{R: r}
+	if err := mr.Initialize(); err != nil {
+		return fmt.Errorf("failed to read tracks: %w", err)
+	}
+
+	decodeErrors := 0
+	mr.OnDecodeError(func(err error) {
+		decodeErrors++
+		// 첫 오류와 이후 100개마다 기록
+		if decodeErrors%100 == 1 {
+			c.logger.Warn("MPEG-TS decode error",
+				zap.Int("count", decodeErrors),
+				zap.Error(err),
+			)
+		}
+	})
+
+	if err := packetizer.SetupMPEGTS(mr, p); err != nil {
+		return err
+	}
+
+	c.ready(p)
+
+	for {
+		if err := mr.Read(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package source
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"github.com/yourusername/cctv3/internal/testsrc"
+	"go.uber.org/zap"
+)
+
+// runTestSrc는 내장 테스트 패턴(H.264)을 실시간 속도로 생성합니다
+// testsrc://bars?size=640x480&fps=25&bitrate=1M&gop=25
+func (c *Client) runTestSrc(p *packetizer.Packetizer) error {
+	config, err := testsrc.ParseURL(c.config.URL)
+	if err != nil {
+		return err
+	}
+
+	gen := testsrc.NewGenerator(config)
+	if err := p.InitH264(gen.SPS(), gen.PPS()); err != nil {
+		return fmt.Errorf("failed to set up test pattern: %w", err)
+	}
+
+	c.logger.Debug("Generating test pattern",
+		zap.Int("width", config.Width),
+		zap.Int("height", config.Height),
+		zap.Int("fps", config.FPS),
+		zap.Int("bitrate", config.Bitrate),
+	)
+	c.ready(p)
+
+	// 시작 시각 기준으로 프레임 시각을 정해 타이머 지연이 누적되지 않도록 함
+	start := time.Now()
+	interval := gen.FrameDuration()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for frame := int64(0); ; frame++ {
+		select {
+		case <-c.ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		pts := time.Duration(frame) * interval
+		p.WriteH264(pts, gen.Next(time.Now()))
+
+		timer.Reset(time.Until(start.Add(pts + interval)))
+	}
+}
@@ -0,0 +1,69 @@
+package source
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/packetizer"
+	"go.uber.org/zap"
+)
+
+// udpReadBufferSize는 UDP 수신 버퍼 크기 (고비트레이트 MPEG-TS 패킷 손실 방지)
+const udpReadBufferSize = 1024 * 1024
+
+// runUDP는 UDP로 전송되는 MPEG-TS 스트림을 수신합니다
+// udp://239.0.0.1:1234 (멀티캐스트), udp://0.0.0.0:1234 (유니캐스트)
+// 멀티캐스트 수신 인터페이스는 ?interface=eth0 으로 지정
+func (c *Client) runUDP(p *packetizer.Packetizer) error {
+	u, err := url.Parse(c.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", u.Host)
+	if err != nil {
+		return fmt.Errorf("invalid UDP address: %w", err)
+	}
+
+	var conn *net.UDPConn
+	if addr.IP.IsMulticast() {
+		var ifi *net.Interface
+		if name := u.Query().Get("interface"); name != "" {
+			ifi, err = net.InterfaceByName(name)
+			if err != nil {
+				return fmt.Errorf("invalid multicast interface: %w", err)
+			}
+		}
+		conn, err = net.ListenMulticastUDP("udp", ifi, addr)
+	} else {
+		conn, err = net.ListenUDP("udp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetReadBuffer(udpReadBufferSize); err != nil {
+		c.logger.Debug("Failed to set UDP read buffer size", zap.Error(err))
+	}
+
+	done := c.closeOnStop(func() { conn.Close() })
+	defer done()
+
+	return c.readMPEGTS(&udpReader{conn: conn, timeout: c.config.Timeout}, p)
+}
+
+// udpReader는 데이터그램마다 읽기 타임아웃을 적용합니다
+type udpReader struct {
+	conn    *net.UDPConn
+	timeout time.Duration
+}
+
+// Read는 데이터그램 하나를 읽습니다
+func (r *udpReader) Read(p []byte) (int, error) {
+	r.conn.SetReadDeadline(time.Now().Add(r.timeout))
+	n, _, err := r.conn.ReadFromUDP(p)
+	return n, err
+}
@@ -0,0 +1,392 @@
+// Package status는 대시보드용 실시간 스트림 상태(소스 연결/끊김, 코덱 감지, 구독자 수, 비트레이트)를
+// 구독자(SSE, WebSocket)에게 전달합니다
+// 이벤트는 저장하지 않으며, 구독을 시작하면 현재 상태를 먼저 보낸 뒤 변화만 보냅니다
+package status
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"go.uber.org/zap"
+)
+
+// 상태 이벤트 종류
+const (
+	EventStreamState       = "stream.state"       // 소스 연결/끊김 (StateData)
+	EventStreamCodec       = "stream.codec"       // 비디오 코덱 감지/변경 (CodecData)
+	EventStreamSubscribers = "stream.subscribers" // 구독자 수 변화 (SubscribersData)
+	EventStreamBitrate     = "stream.bitrate"     // 주기적 비트레이트 샘플 (BitrateData)
+)
+
+// 스트림 상태
+const (
+	StateOnline  = "online"
+	StateOffline = "offline"
+)
+
+const (
+	// defaultInterval은 비트레이트 샘플 기본 주기
+	defaultInterval = 5 * time.Second
+
+	// subscriptionBuffer는 구독자별 이벤트 버퍼 크기 (가득 차면 이벤트를 버림)
+	subscriptionBuffer = 64
+)
+
+// Event는 구독자에게 보내는 상태 이벤트입니다
+type Event struct {
+	Type     string      `json:"type"`
+	StreamID string      `json:"stream_id"`
+	Time     time.Time   `json:"time"`
+	Data     interface{} `json:"data"`
+}
+
+// StateData는 stream.state 이벤트 데이터입니다
+type StateData struct {
+	State      string `json:"state"`                 // online, offline
+	SourceType string `json:"source_type,omitempty"` // rtspSource, rtspSession, rtmpConn 등
+	SourceID   string `json:"source_id,omitempty"`
+	Error      string `json:"error,omitempty"` // 소스 연결이 끊긴 원인
+}
+
+// CodecData는 stream.codec 이벤트 데이터입니다
+type CodecData struct {
+	Codec    string `json:"codec"`
+	Previous string `json:"previous,omitempty"`
+}
+
+// SubscribersData는 stream.subscribers 이벤트 데이터입니다
+type SubscribersData struct {
+	Subscribers int `json:"subscribers"`
+}
+
+// BitrateData는 stream.bitrate 이벤트 데이터입니다 (마지막 샘플 주기 동안의 평균)
+type BitrateData struct {
+	ReceivedBps float64 `json:"received_bps"`
+	SentBps     float64 `json:"sent_bps"`
+}
+
+// Config는 상태 관리자 설정
+type Config struct {
+	StreamManager *core.StreamManager
+	Interval      time.Duration // 비트레이트 샘플 주기
+	Logger        *zap.Logger
+}
+
+// streamSample은 비트레이트 계산용 이전 누적 바이트 수
+type streamSample struct {
+	bytesReceived uint64
+	bytesSent     uint64
+	at            time.Time
+}
+
+// Manager는 스트림 상태 변화를 구독자에게 전달합니다
+type Manager struct {
+	config Config
+	logger *zap.Logger
+
+	subsMutex sync.RWMutex
+	subs      map[*Subscription]struct{}
+	closed    bool
+
+	// 소스 연결이 끊긴 원인 (다음 offline 이벤트에 포함)
+	errorsMutex sync.Mutex
+	errors      map[string]string
+
+	// 비트레이트 샘플 (샘플 고루틴에서만 samples 갱신, rates는 초기 상태 전송에도 사용)
+	samples    map[string]streamSample
+	ratesMutex sync.RWMutex
+	rates      map[string]BitrateData
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Subscription은 상태 이벤트 구독입니다
+type Subscription struct {
+	manager *Manager
+	filter  func(streamID string) bool
+	events  chan Event
+
+	closeOnce sync.Once
+}
+
+// NewManager는 새로운 상태 관리자를 생성합니다
+func NewManager(config Config) *Manager {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+
+	return &Manager{
+		config:  config,
+		logger:  config.Logger.With(zap.String("component", "status")),
+		subs:    make(map[*Subscription]struct{}),
+		errors:  make(map[string]string),
+		samples: make(map[string]streamSample),
+		rates:   make(map[string]BitrateData),
+	}
+}
+
+// Start는 비트레이트 샘플을 시작합니다
+func (m *Manager) Start(ctx context.Context) {
+	m.ctx, m.cancel = context.WithCancel(ctx)
+
+	m.wg.Add(1)
+	go m.sampleLoop()
+
+	m.logger.Info("Status manager started", zap.Duration("interval", m.config.Interval))
+}
+
+// Close는 샘플을 중지하고 모든 구독을 종료합니다 (구독 채널이 닫혀 SSE/WebSocket 전송이 끝남)
+func (m *Manager) Close() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+
+	m.subsMutex.Lock()
+	defer m.subsMutex.Unlock()
+
+	if m.closed {
+		return
+	}
+	m.closed = true
+	for sub := range m.subs {
+		close(sub.events)
+		delete(m.subs, sub)
+	}
+}
+
+// Subscribe는 상태 이벤트 구독을 시작합니다
+// filter가 true를 반환하는 스트림의 이벤트만 받으며, 현재 상태가 먼저 채널에 들어갑니다
+// 관리자가 이미 종료되었으면 닫힌 채널을 가진 구독을 반환합니다
+func (m *Manager) Subscribe(filter func(streamID string) bool) *Subscription {
+	m.subsMutex.Lock()
+	defer m.subsMutex.Unlock()
+
+	// 현재 상태가 모두 들어가도록 버퍼 크기를 늘림
+	snapshot := m.snapshot(filter)
+	sub := &Subscription{
+		manager: m,
+		filter:  filter,
+		events:  make(chan Event, len(snapshot)+subscriptionBuffer),
+	}
+
+	if m.closed {
+		close(sub.events)
+		return sub
+	}
+
+	for _, event := range snapshot {
+		sub.events <- event
+	}
+
+	m.subs[sub] = struct{}{}
+	return sub
+}
+
+// Events는 상태 이벤트 채널을 반환합니다 (구독 종료 시 닫힘)
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close는 구독을 종료합니다
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		m := s.manager
+		m.subsMutex.Lock()
+		defer m.subsMutex.Unlock()
+
+		if _, exists := m.subs[s]; exists {
+			delete(m.subs, s)
+			close(s.events)
+		}
+	})
+}
+
+// Publish는 스트림을 볼 수 있는 구독자에게 이벤트를 보냅니다
+// 구독자 버퍼가 가득 차면 해당 구독자에게는 이벤트를 버립니다 (느린 클라이언트가 다른 구독자를 막지 않도록)
+func (m *Manager) Publish(eventType, streamID string, data interface{}) {
+	event := Event{
+		Type:     eventType,
+		StreamID: streamID,
+		Time:     time.Now().UTC(),
+		Data:     data,
+	}
+
+	m.subsMutex.RLock()
+	defer m.subsMutex.RUnlock()
+
+	for sub := range m.subs {
+		if sub.filter != nil && !sub.filter(streamID) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			m.logger.Debug("Status subscriber buffer full, dropping event",
+				zap.String("type", eventType),
+				zap.String("stream_id", streamID),
+			)
+		}
+	}
+}
+
+// Bitrate는 마지막 샘플 주기 동안의 스트림 비트레이트를 반환합니다 (아직 샘플이 없으면 false)
+func (m *Manager) Bitrate(streamID string) (BitrateData, bool) {
+	m.ratesMutex.RLock()
+	defer m.ratesMutex.RUnlock()
+
+	rate, exists := m.rates[streamID]
+	return rate, exists
+}
+
+// SourceError는 소스 연결이 끊긴 원인을 기록합니다 (다음 offline 이벤트에 포함)
+// 소스의 연결 끊김 콜백에서 SetNotReady 전에 호출합니다
+func (m *Manager) SourceError(streamID string, err error) {
+	if err == nil {
+		return
+	}
+
+	m.errorsMutex.Lock()
+	m.errors[streamID] = err.Error()
+	m.errorsMutex.Unlock()
+}
+
+// StreamHooks는 스트림 상태 변화를 상태 이벤트로 보내는 콜백을 반환합니다
+func (m *Manager) StreamHooks() core.StreamHooks {
+	return core.StreamHooks{
+		OnReady: func(stream *core.Stream, sourceType, sourceID string) {
+			m.takeError(stream.GetID())
+			m.Publish(EventStreamState, stream.GetID(), StateData{
+				State:      StateOnline,
+				SourceType: sourceType,
+				SourceID:   sourceID,
+			})
+		},
+		OnNotReady: func(stream *core.Stream, sourceType, sourceID string) {
+			m.Publish(EventStreamState, stream.GetID(), StateData{
+				State:      StateOffline,
+				SourceType: sourceType,
+				SourceID:   sourceID,
+				Error:      m.takeError(stream.GetID()),
+			})
+		},
+		OnCodecChange: func(stream *core.Stream, oldCodec, newCodec string) {
+			m.Publish(EventStreamCodec, stream.GetID(), CodecData{
+				Codec:    newCodec,
+				Previous: oldCodec,
+			})
+		},
+		OnSubscribersChange: func(stream *core.Stream, count int) {
+			m.Publish(EventStreamSubscribers, stream.GetID(), SubscribersData{
+				Subscribers: count,
+			})
+		},
+	}
+}
+
+// takeError는 기록된 소스 연결 끊김 원인을 반환하고 지웁니다
+func (m *Manager) takeError(streamID string) string {
+	m.errorsMutex.Lock()
+	defer m.errorsMutex.Unlock()
+
+	message := m.errors[streamID]
+	delete(m.errors, streamID)
+	return message
+}
+
+// snapshot은 filter를 통과하는 스트림의 현재 상태 이벤트를 만듭니다 (스트림별 상태, 코덱, 구독자 수, 비트레이트)
+func (m *Manager) snapshot(filter func(streamID string) bool) []Event {
+	now := time.Now().UTC()
+	var events []Event
+
+	m.ratesMutex.RLock()
+	defer m.ratesMutex.RUnlock()
+
+	for id, stream := range m.config.StreamManager.ListStreams() {
+		if filter != nil && !filter(id) {
+			continue
+		}
+
+		state := StateData{State: StateOffline}
+		if stream.IsReady() {
+			state.State = StateOnline
+			state.SourceType, state.SourceID = stream.GetSource()
+		}
+		events = append(events,
+			Event{Type: EventStreamState, StreamID: id, Time: now, Data: state},
+			Event{Type: EventStreamSubscribers, StreamID: id, Time: now, Data: SubscribersData{Subscribers: stream.GetSubscriberCount()}},
+		)
+		if codec := stream.GetVideoCodec(); codec != "" {
+			events = append(events, Event{Type: EventStreamCodec, StreamID: id, Time: now, Data: CodecData{Codec: codec}})
+		}
+		if rate, exists := m.rates[id]; exists {
+			events = append(events, Event{Type: EventStreamBitrate, StreamID: id, Time: now, Data: rate})
+		}
+	}
+
+	return events
+}
+
+// sampleLoop는 샘플 주기마다 스트림 비트레이트를 계산해 보냅니다
+func (m *Manager) sampleLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	m.sample()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.sample()
+		}
+	}
+}
+
+// sample은 이전 샘플 이후 증가한 바이트 수로 스트림별 비트레이트를 계산합니다
+// 준비된 스트림과 방금 끊긴 스트림(0 bps 한 번)만 보냅니다
+func (m *Manager) sample() {
+	now := time.Now()
+	streams := m.config.StreamManager.ListStreams()
+	updates := make(map[string]BitrateData)
+
+	m.ratesMutex.Lock()
+	for id, stream := range streams {
+		_, _, bytesReceived, bytesSent := stream.GetStats()
+
+		if prev, exists := m.samples[id]; exists {
+			elapsed := now.Sub(prev.at).Seconds()
+			if elapsed > 0 && bytesReceived >= prev.bytesReceived && bytesSent >= prev.bytesSent {
+				rate := BitrateData{
+					ReceivedBps: float64(bytesReceived-prev.bytesReceived) * 8 / elapsed,
+					SentBps:     float64(bytesSent-prev.bytesSent) * 8 / elapsed,
+				}
+				last, hadRate := m.rates[id]
+				m.rates[id] = rate
+				if stream.IsReady() || (hadRate && last != rate) {
+					updates[id] = rate
+				}
+			}
+		}
+		m.samples[id] = streamSample{bytesReceived: bytesReceived, bytesSent: bytesSent, at: now}
+	}
+
+	// 삭제된 스트림 정리
+	for id := range m.samples {
+		if _, exists := streams[id]; !exists {
+			delete(m.samples, id)
+			delete(m.rates, id)
+		}
+	}
+	m.ratesMutex.Unlock()
+
+	for id, rate := range updates {
+		m.Publish(EventStreamBitrate, id, rate)
+	}
+}
@@ -0,0 +1,447 @@
+package testsrc
+
+import "math/bits"
+
+// 인코더 고정값 (Baseline 프로파일, CAVLC, QP 26)
+const (
+	h264ProfileBaseline = 66
+	h264Constraints     = 0xE0 // constraint_set0/1/2 (WebRTC 기본 profile-level-id 42e0xx와 동일)
+
+	// log2MaxFrameNum은 frame_num 비트 수 (MaxFrameNum 16)
+	log2MaxFrameNum = 4
+
+	// sliceQP는 모든 매크로블록의 양자화 파라미터 (pic_init_qp 26, slice_qp_delta 0)
+	sliceQP = 26
+)
+
+// NAL 유닛 헤더 (nal_ref_idc 3, 필러는 0)
+const (
+	nalNonIDR = 0x61
+	nalIDR    = 0x65
+	nalSPS    = 0x67
+	nalPPS    = 0x68
+	nalFiller = 0x0C
+)
+
+// Intra 16x16 휘도/색차 예측 모드
+const (
+	lumaVertical   = 0
+	lumaHorizontal = 1
+	lumaDC         = 2
+
+	chromaDC         = 0
+	chromaHorizontal = 1
+	chromaVertical   = 2
+)
+
+// h264Levels는 프레임 크기(매크로블록 수)와 초당 매크로블록 수로 고르는 레벨 (Table A-1)
+var h264Levels = []struct {
+	idc         uint8
+	maxFS       int
+	maxMBPerSec int
+}{
+	{30, 1620, 40500},
+	{31, 3600, 108000},
+	{32, 5120, 216000},
+	{40, 8192, 245760},
+	{42, 8704, 522240},
+	{50, 22080, 589824},
+	{51, 36864, 983040},
+	{52, 36864, 2073600},
+}
+
+// h264Encoder는 매크로블록마다 단색인 화면을 H.264 Baseline I 슬라이스로 인코딩합니다
+// 모든 매크로블록을 Intra 16x16 (평탄한 예측 + DC 계수 하나)로 부호화하므로 DCT/움직임 추정이 필요 없고,
+// 복원 값을 인코더가 정확히 추적해 다음 매크로블록의 예측에 사용합니다
+type h264Encoder struct {
+	width, height int
+	mbWidth       int
+	mbHeight      int
+	fps           int
+
+	sps []byte
+	pps []byte
+
+	// 현재 화면의 매크로블록별 복원 값 (Y, Cb, Cr)
+	recon []mbColor
+
+	frameNum uint32
+	idrPicID uint32
+}
+
+func newH264Encoder(width, height, fps int) *h264Encoder {
+	e := &h264Encoder{
+		width:    width,
+		height:   height,
+		mbWidth:  (width + 15) / 16,
+		mbHeight: (height + 15) / 16,
+		fps:      fps,
+	}
+	e.recon = make([]mbColor, e.mbWidth*e.mbHeight)
+	e.sps = e.buildSPS()
+	e.pps = e.buildPPS()
+	return e
+}
+
+// level은 해상도와 프레임 레이트에 맞는 level_idc를 반환합니다
+func (e *h264Encoder) level() uint8 {
+	fs := e.mbWidth * e.mbHeight
+	for _, level := range h264Levels {
+		if fs <= level.maxFS && fs*e.fps <= level.maxMBPerSec {
+			return level.idc
+		}
+	}
+	return h264Levels[len(h264Levels)-1].idc
+}
+
+// buildSPS는 시퀀스 파라미터 셋을 만듭니다 (POC 타입 2, 참조 프레임 1개, 재정렬 없음)
+func (e *h264Encoder) buildSPS() []byte {
+	w := &bitWriter{}
+	w.writeBits(h264ProfileBaseline, 8)
+	w.writeBits(h264Constraints, 8)
+	w.writeBits(uint64(e.level()), 8)
+	w.writeUE(0)                   // seq_parameter_set_id
+	w.writeUE(log2MaxFrameNum - 4) // log2_max_frame_num_minus4
+	w.writeUE(2)                   // pic_order_cnt_type
+	w.writeUE(1)                   // max_num_ref_frames
+	w.writeBit(0)                  // gaps_in_frame_num_value_allowed_flag
+	w.writeUE(uint32(e.mbWidth - 1))
+	w.writeUE(uint32(e.mbHeight - 1))
+	w.writeBit(1) // frame_mbs_only_flag
+	w.writeBit(1) // direct_8x8_inference_flag
+
+	// 16의 배수가 아닌 크기는 오른쪽/아래를 잘라냄 (4:2:0 프레임은 2픽셀 단위)
+	cropRight := (e.mbWidth*16 - e.width) / 2
+	cropBottom := (e.mbHeight*16 - e.height) / 2
+	if cropRight > 0 || cropBottom > 0 {
+		w.writeBit(1)
+		w.writeUE(0)
+		w.writeUE(uint32(cropRight))
+		w.writeUE(0)
+		w.writeUE(uint32(cropBottom))
+	} else {
+		w.writeBit(0)
+	}
+
+	// VUI: 프레임 레이트와 재정렬 없음 (디코더가 프레임을 버퍼링하지 않도록)
+	w.writeBit(1) // vui_parameters_present_flag
+	w.writeBit(0) // aspect_ratio_info_present_flag
+	w.writeBit(0) // overscan_info_present_flag
+	w.writeBit(0) // video_signal_type_present_flag
+	w.writeBit(0) // chroma_loc_info_present_flag
+	w.writeBit(1) // timing_info_present_flag
+	w.writeBits(1, 32)
+	w.writeBits(uint64(2*e.fps), 32)
+	w.writeBit(1) // fixed_frame_rate_flag
+	w.writeBit(0) // nal_hrd_parameters_present_flag
+	w.writeBit(0) // vcl_hrd_parameters_present_flag
+	w.writeBit(0) // pic_struct_present_flag
+	w.writeBit(1) // bitstream_restriction_flag
+	w.writeBit(1) // motion_vectors_over_pic_boundaries_flag
+	w.writeUE(0)  // max_bytes_per_pic_denom
+	w.writeUE(0)  // max_bits_per_mb_denom
+	w.writeUE(16) // log2_max_mv_length_horizontal
+	w.writeUE(16) // log2_max_mv_length_vertical
+	w.writeUE(0)  // max_num_reorder_frames
+	w.writeUE(1)  // max_dec_frame_buffering
+
+	w.writeTrailingBits()
+	return nalUnit(nalSPS, w.bytes())
+}
+
+// buildPPS는 픽처 파라미터 셋을 만듭니다 (CAVLC, 디블로킹 제어 사용)
+func (e *h264Encoder) buildPPS() []byte {
+	w := &bitWriter{}
+	w.writeUE(0)            // pic_parameter_set_id
+	w.writeUE(0)            // seq_parameter_set_id
+	w.writeBit(0)           // entropy_coding_mode_flag
+	w.writeBit(0)           // bottom_field_pic_order_in_frame_present_flag
+	w.writeUE(0)            // num_slice_groups_minus1
+	w.writeUE(0)            // num_ref_idx_l0_default_active_minus1
+	w.writeUE(0)            // num_ref_idx_l1_default_active_minus1
+	w.writeBit(0)           // weighted_pred_flag
+	w.writeBits(0, 2)       // weighted_bipred_idc
+	w.writeSE(sliceQP - 26) // pic_init_qp_minus26
+	w.writeSE(0)            // pic_init_qs_minus26
+	w.writeSE(0)            // chroma_qp_index_offset
+	w.writeBit(1)           // deblocking_filter_control_present_flag
+	w.writeBit(0)           // constrained_intra_pred_flag
+	w.writeBit(0)           // redundant_pic_cnt_present_flag
+	w.writeTrailingBits()
+	return nalUnit(nalPPS, w.bytes())
+}
+
+// encode는 화면 하나를 슬라이스 NAL 유닛으로 인코딩합니다 (idr이면 IDR 슬라이스)
+func (e *h264Encoder) encode(pic *picture, idr bool) []byte {
+	if idr {
+		e.frameNum = 0
+	}
+
+	w := &bitWriter{}
+	w.writeUE(0) // first_mb_in_slice
+	w.writeUE(7) // slice_type: I (화면의 모든 슬라이스가 I)
+	w.writeUE(0) // pic_parameter_set_id
+	w.writeBits(uint64(e.frameNum), log2MaxFrameNum)
+	if idr {
+		w.writeUE(e.idrPicID)
+		w.writeBit(0) // no_output_of_prior_pics_flag
+		w.writeBit(0) // long_term_reference_flag
+		e.idrPicID = (e.idrPicID + 1) % 2
+	} else {
+		w.writeBit(0) // adaptive_ref_pic_marking_mode_flag
+	}
+	w.writeSE(0) // slice_qp_delta
+	w.writeUE(1) // disable_deblocking_filter_idc: 평탄한 블록이라 필터 불필요
+
+	for mby := 0; mby < e.mbHeight; mby++ {
+		for mbx := 0; mbx < e.mbWidth; mbx++ {
+			e.encodeMacroblock(w, mbx, mby, pic.at(mbx, mby))
+		}
+	}
+
+	w.writeTrailingBits()
+	e.frameNum = (e.frameNum + 1) % (1 << log2MaxFrameNum)
+
+	header := byte(nalNonIDR)
+	if idr {
+		header = nalIDR
+	}
+	return nalUnit(header, w.bytes())
+}
+
+// encodeMacroblock은 매크로블록 하나를 I_16x16으로 부호화합니다
+// 위/왼쪽 매크로블록의 복원 값(단색)을 그대로 예측으로 쓰고, 목표 색과의 차이를 DC 계수 하나로 보냅니다
+func (e *h264Encoder) encodeMacroblock(w *bitWriter, mbx, mby int, target mbColor) {
+	lumaMode, chromaMode := lumaDC, chromaDC
+	pred := mbColor{128, 128, 128}
+
+	switch {
+	case mbx > 0 && (mby == 0 || e.recon[mby*e.mbWidth+mbx-1] == target):
+		lumaMode, chromaMode = lumaHorizontal, chromaHorizontal
+		pred = e.recon[mby*e.mbWidth+mbx-1]
+	case mby > 0:
+		lumaMode, chromaMode = lumaVertical, chromaVertical
+		pred = e.recon[(mby-1)*e.mbWidth+mbx]
+	}
+
+	levelY, y := bestLevel(pred.y, target.y, lumaDCResidual)
+	levelCb, cb := bestLevel(pred.cb, target.cb, chromaDCResidual)
+	levelCr, cr := bestLevel(pred.cr, target.cr, chromaDCResidual)
+	e.recon[mby*e.mbWidth+mbx] = mbColor{y, cb, cr}
+
+	cbpChroma := 0
+	if levelCb != 0 || levelCr != 0 {
+		cbpChroma = 1 // 색차 DC만 부호화
+	}
+
+	w.writeUE(uint32(1 + lumaMode + 4*cbpChroma)) // mb_type: I_16x16_<mode>_<cbpChroma>_0
+	w.writeUE(uint32(chromaMode))                 // intra_chroma_pred_mode
+	w.writeSE(0)                                  // mb_qp_delta
+
+	// 이웃 블록의 AC 계수가 모두 0이므로 휘도 DC의 nC는 항상 0, 색차 DC(4:2:0)는 -1
+	writeDCBlock(w, levelY, false)
+	if cbpChroma != 0 {
+		writeDCBlock(w, levelCb, true)
+		writeDCBlock(w, levelCr, true)
+	}
+}
+
+// lumaDCResidual은 Intra16x16DCLevel 첫 계수 하나가 level일 때 모든 휘도 픽셀에 더해지는 값입니다
+// 역 Hadamard 후 모든 4x4 블록의 DC가 같아지고, DC만 있는 4x4 블록은 평탄하게 복원됨 (8.5.10, 8.5.12)
+func lumaDCResidual(level int) int {
+	// sliceQP < 36이므로 반올림 후 오른쪽 시프트
+	dc := (level*16*levelScale(sliceQP%6) + 1<<(5-sliceQP/6)) >> (6 - sliceQP/6)
+	return (dc + 32) >> 6
+}
+
+// chromaDCResidual은 색차 DC 첫 계수 하나가 level일 때 모든 색차 픽셀에 더해지는 값입니다 (8.5.11)
+func chromaDCResidual(level int) int {
+	dc := ((level * 16 * levelScale(sliceQP%6)) << (sliceQP / 6)) >> 5
+	return (dc + 32) >> 6
+}
+
+// levelScale은 평탄한 스케일링 행렬의 (0,0) 위치 normAdjust4x4 값입니다
+func levelScale(m int) int {
+	return [6]int{10, 11, 13, 14, 16, 18}[m]
+}
+
+// bestLevel은 pred에 더했을 때 target에 가장 가까워지는 DC 계수와 복원 값을 찾습니다
+func bestLevel(pred, target uint8, residual func(int) int) (int, uint8) {
+	// 계수 1당 변화량으로 근삿값을 구한 뒤 주변에서 정확히 검색
+	step := residual(64)
+	guess := (int(target) - int(pred)) * 64 / step
+
+	bestLevel, bestValue := 0, pred
+	bestErr := absInt(int(target) - int(pred))
+	for level := guess - 3; level <= guess+3; level++ {
+		value := clip1(int(pred) + residual(level))
+		err := absInt(int(target) - int(value))
+		if err < bestErr || (err == bestErr && absInt(level) < absInt(bestLevel)) {
+			bestLevel, bestValue, bestErr = level, value, err
+		}
+	}
+	return bestLevel, bestValue
+}
+
+// writeDCBlock은 첫 계수만 있는 DC 블록을 CAVLC로 씁니다 (residual_block_cavlc)
+// chroma면 nC=-1(색차 DC 2x2), 아니면 nC=0 표를 사용합니다
+func writeDCBlock(w *bitWriter, level int, chroma bool) {
+	switch {
+	case level == 0:
+		// coeff_token: TotalCoeff 0
+		if chroma {
+			w.writeBits(0b01, 2)
+		} else {
+			w.writeBits(0b1, 1)
+		}
+		return
+
+	case level == 1 || level == -1:
+		// coeff_token: TrailingOnes 1, TotalCoeff 1 + trailing_ones_sign_flag
+		if chroma {
+			w.writeBits(0b1, 1)
+		} else {
+			w.writeBits(0b01, 2)
+		}
+		if level < 0 {
+			w.writeBit(1)
+		} else {
+			w.writeBit(0)
+		}
+
+	default:
+		// coeff_token: TrailingOnes 0, TotalCoeff 1
+		if chroma {
+			w.writeBits(0b000111, 6)
+		} else {
+			w.writeBits(0b000101, 6)
+		}
+		writeLevel(w, level)
+	}
+
+	w.writeBit(1) // total_zeros 0 (TotalCoeff 1)
+}
+
+// writeLevel은 trailing one이 아닌 첫 계수를 suffixLength 0으로 씁니다 (level_prefix, level_suffix)
+func writeLevel(w *bitWriter, level int) {
+	levelCode := 2*level - 2
+	if level < 0 {
+		levelCode = -2*level - 1
+	}
+	// TrailingOnes < 3인 첫 계수는 디코더가 levelCode에 2를 더함
+	levelCode -= 2
+
+	switch {
+	case levelCode < 14:
+		w.writeBits(1, levelCode+1)
+	case levelCode < 30:
+		w.writeBits(1, 15)
+		w.writeBits(uint64(levelCode-14), 4)
+	default:
+		w.writeBits(1, 16)
+		w.writeBits(uint64(levelCode-30), 12)
+	}
+}
+
+// filler는 size바이트 크기의 필러 데이터 NAL 유닛을 만듭니다 (비트레이트 맞춤용, 디코더는 무시)
+func filler(size int) []byte {
+	if size < 2 {
+		size = 2
+	}
+	nalu := make([]byte, size)
+	nalu[0] = nalFiller
+	for i := 1; i < size-1; i++ {
+		nalu[i] = 0xFF
+	}
+	nalu[size-1] = 0x80 // rbsp_trailing_bits
+	return nalu
+}
+
+// nalUnit은 RBSP에 시작 코드 에뮬레이션 방지 바이트를 넣고 NAL 헤더를 붙입니다
+func nalUnit(header byte, rbsp []byte) []byte {
+	nalu := make([]byte, 1, len(rbsp)+len(rbsp)/64+1)
+	nalu[0] = header
+
+	zeros := 0
+	for _, b := range rbsp {
+		if zeros >= 2 && b <= 3 {
+			nalu = append(nalu, 3)
+			zeros = 0
+		}
+		nalu = append(nalu, b)
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+	return nalu
+}
+
+func clip1(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// bitWriter는 RBSP 비트 단위 기록기입니다
+type bitWriter struct {
+	buf   []byte
+	cur   byte
+	nbits uint
+}
+
+func (w *bitWriter) writeBit(b uint8) {
+	w.cur = w.cur<<1 | b&1
+	w.nbits++
+	if w.nbits == 8 {
+		w.buf = append(w.buf, w.cur)
+		w.cur, w.nbits = 0, 0
+	}
+}
+
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.writeBit(uint8(v >> uint(i)))
+	}
+}
+
+// writeUE는 ue(v) 지수 골롬 부호를 씁니다
+func (w *bitWriter) writeUE(v uint32) {
+	x := uint64(v) + 1
+	n := bits.Len64(x)
+	w.writeBits(0, n-1)
+	w.writeBits(x, n)
+}
+
+// writeSE는 se(v) 부호 있는 지수 골롬 부호를 씁니다
+func (w *bitWriter) writeSE(v int) {
+	if v > 0 {
+		w.writeUE(uint32(2*v - 1))
+	} else {
+		w.writeUE(uint32(-2 * v))
+	}
+}
+
+// writeTrailingBits는 rbsp_trailing_bits를 씁니다 (stop bit + 바이트 정렬)
+func (w *bitWriter) writeTrailingBits() {
+	w.writeBit(1)
+	for w.nbits != 0 {
+		w.writeBit(0)
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	return w.buf
+}
@@ -0,0 +1,136 @@
+package testsrc
+
+import "time"
+
+// mbColor는 매크로블록 하나의 색 (BT.601 제한 범위 YCbCr)
+type mbColor struct {
+	y, cb, cr uint8
+}
+
+// rgb는 8비트 RGB를 BT.601 제한 범위 YCbCr로 변환합니다
+func rgb(r, g, b int) mbColor {
+	return mbColor{
+		y:  uint8(16 + (65738*r+129057*g+25064*b+(1<<23))>>24),
+		cb: uint8(128 + (-37945*r-74494*g+112439*b+(1<<23))>>24),
+		cr: uint8(128 + (112439*r-94154*g-18285*b+(1<<23))>>24),
+	}
+}
+
+// 패턴 색 (SMPTE 75% 컬러 바 순서)
+var (
+	barColors = []mbColor{
+		rgb(191, 191, 191), // 흰색
+		rgb(191, 191, 0),   // 노랑
+		rgb(0, 191, 191),   // 청록
+		rgb(0, 191, 0),     // 초록
+		rgb(191, 0, 191),   // 자홍
+		rgb(191, 0, 0),     // 빨강
+		rgb(0, 0, 191),     // 파랑
+		rgb(16, 16, 16),    // 검정
+	}
+	backgroundColor = rgb(32, 32, 32)
+	textColor       = rgb(235, 235, 235)
+	tickColor       = rgb(255, 128, 0)
+)
+
+// 시각 표시 글꼴 (매크로블록 단위 3x5 글자, 콜론은 1x5)
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// digitGlyphs는 숫자 0~9의 3x5 비트맵입니다 (행마다 3비트, 왼쪽이 최상위 비트)
+var digitGlyphs = [10][glyphHeight]uint8{
+	{7, 5, 5, 5, 7}, // 0
+	{2, 6, 2, 2, 7}, // 1
+	{7, 1, 7, 4, 7}, // 2
+	{7, 1, 7, 1, 7}, // 3
+	{5, 5, 7, 1, 1}, // 4
+	{7, 4, 7, 1, 7}, // 5
+	{7, 4, 7, 5, 7}, // 6
+	{7, 1, 2, 2, 2}, // 7
+	{7, 5, 7, 5, 7}, // 8
+	{7, 5, 7, 1, 7}, // 9
+}
+
+// picture는 매크로블록 단위 화면입니다
+type picture struct {
+	mbWidth  int
+	mbHeight int
+	mbs      []mbColor
+}
+
+func newPicture(mbWidth, mbHeight int) *picture {
+	return &picture{
+		mbWidth:  mbWidth,
+		mbHeight: mbHeight,
+		mbs:      make([]mbColor, mbWidth*mbHeight),
+	}
+}
+
+func (p *picture) at(mbx, mby int) mbColor {
+	return p.mbs[mby*p.mbWidth+mbx]
+}
+
+func (p *picture) set(mbx, mby int, c mbColor) {
+	if mbx >= 0 && mbx < p.mbWidth && mby >= 0 && mby < p.mbHeight {
+		p.mbs[mby*p.mbWidth+mbx] = c
+	}
+}
+
+// textWidth는 "15:04:05" 표시에 필요한 매크로블록 수 (숫자 6개, 콜론 2개, 글자 사이 1칸)
+const textWidth = 6*glyphWidth + 2 + 7
+
+// drawBars는 frame번째 화면을 그립니다
+//   - 위쪽: 4초에 한 바퀴 왼쪽으로 흐르는 컬러 바
+//   - 아래쪽: 벽시계 시각 (지연 측정용)과 프레임마다 한 칸씩 움직이는 표시
+//
+// 화면이 작아 시각이 들어가지 않으면 전체를 컬러 바로 채웁니다
+func drawBars(p *picture, frame int64, fps int, now time.Time) {
+	barRows := p.mbHeight
+	showText := p.mbWidth >= textWidth+2 && p.mbHeight >= 2*(glyphHeight+2)
+	if showText {
+		barRows = p.mbHeight - (glyphHeight + 2)
+	}
+
+	offset := int(frame * int64(p.mbWidth) / int64(4*fps))
+	for mby := 0; mby < barRows; mby++ {
+		for mbx := 0; mbx < p.mbWidth; mbx++ {
+			bar := ((mbx + offset) % p.mbWidth) * len(barColors) / p.mbWidth
+			p.set(mbx, mby, barColors[bar])
+		}
+	}
+
+	if !showText {
+		return
+	}
+
+	for mby := barRows; mby < p.mbHeight; mby++ {
+		for mbx := 0; mbx < p.mbWidth; mbx++ {
+			p.set(mbx, mby, backgroundColor)
+		}
+	}
+
+	x := (p.mbWidth - textWidth) / 2
+	y := barRows + 1
+	for _, ch := range now.Format("15:04:05") {
+		if ch == ':' {
+			p.set(x, y+1, textColor)
+			p.set(x, y+3, textColor)
+			x += 2
+			continue
+		}
+		glyph := digitGlyphs[ch-'0']
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row]&(1<<(glyphWidth-1-col)) != 0 {
+					p.set(x+col, y+row, textColor)
+				}
+			}
+		}
+		x += glyphWidth + 1
+	}
+
+	// 맨 아래 줄: 프레임마다 한 칸 이동 (끊김/프레임 누락 확인용)
+	p.set(int(frame%int64(p.mbWidth)), p.mbHeight-1, tickColor)
+}
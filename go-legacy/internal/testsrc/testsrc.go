@@ -0,0 +1,184 @@
+// Package testsrc는 외부 카메라 없이 사용할 수 있는 합성 테스트 패턴 영상을 생성합니다
+// 흐르는 컬러 바와 벽시계 시각을 순수 Go H.264 Baseline 인코더로 부호화하므로
+// ffmpeg 등 외부 도구나 네트워크 없이 데모/CI에서 스트림을 만들 수 있습니다
+//
+// URL 형식: testsrc://bars?size=640x480&fps=25&bitrate=1M&gop=50
+package testsrc
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scheme은 테스트 패턴 소스의 URL 스킴
+const Scheme = "testsrc"
+
+// 기본값과 허용 범위
+const (
+	DefaultWidth  = 640
+	DefaultHeight = 480
+	DefaultFPS    = 25
+
+	minWidth   = 64
+	maxWidth   = 4096
+	minHeight  = 64
+	maxHeight  = 2304
+	maxFPS     = 60
+	maxBitrate = 100_000_000
+	maxGOP     = 600
+)
+
+// Config는 테스트 패턴 설정
+type Config struct {
+	Width   int
+	Height  int
+	FPS     int
+	Bitrate int // bits/s, 필러 NAL 유닛으로 채움 (0이면 채우지 않음)
+	GOP     int // IDR 프레임 간격 (프레임 수)
+}
+
+// ParseURL은 testsrc:// URL을 파싱합니다
+//   - size:    WxH (기본 640x480, 짝수)
+//   - fps:     1~60 (기본 25)
+//   - bitrate: 비트레이트 (bits/s, k/M 단위 가능, 기본 0 = 패턴 크기 그대로)
+//   - gop:     IDR 간격 프레임 수 (기본 fps, 즉 1초)
+func ParseURL(rawURL string) (Config, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid testsrc URL: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, Scheme) {
+		return Config{}, fmt.Errorf("not a testsrc URL: %s", rawURL)
+	}
+	if u.Host != "" && u.Host != "bars" {
+		return Config{}, fmt.Errorf("unknown test pattern '%s' (supported: bars)", u.Host)
+	}
+
+	config := Config{
+		Width:  DefaultWidth,
+		Height: DefaultHeight,
+		FPS:    DefaultFPS,
+	}
+	query := u.Query()
+
+	if v := query.Get("size"); v != "" {
+		w, h, ok := strings.Cut(strings.ToLower(v), "x")
+		if !ok {
+			return Config{}, fmt.Errorf("invalid size '%s' (expected WxH)", v)
+		}
+		if config.Width, err = strconv.Atoi(w); err != nil {
+			return Config{}, fmt.Errorf("invalid size '%s' (expected WxH)", v)
+		}
+		if config.Height, err = strconv.Atoi(h); err != nil {
+			return Config{}, fmt.Errorf("invalid size '%s' (expected WxH)", v)
+		}
+		if config.Width < minWidth || config.Width > maxWidth || config.Height < minHeight || config.Height > maxHeight {
+			return Config{}, fmt.Errorf("size must be between %dx%d and %dx%d", minWidth, minHeight, maxWidth, maxHeight)
+		}
+		if config.Width%2 != 0 || config.Height%2 != 0 {
+			return Config{}, fmt.Errorf("size must be even: %s", v)
+		}
+	}
+
+	if v := query.Get("fps"); v != "" {
+		if config.FPS, err = strconv.Atoi(v); err != nil || config.FPS < 1 || config.FPS > maxFPS {
+			return Config{}, fmt.Errorf("fps must be between 1 and %d", maxFPS)
+		}
+	}
+
+	if v := query.Get("bitrate"); v != "" {
+		if config.Bitrate, err = parseBitrate(v); err != nil {
+			return Config{}, err
+		}
+	}
+
+	config.GOP = config.FPS
+	if v := query.Get("gop"); v != "" {
+		if config.GOP, err = strconv.Atoi(v); err != nil || config.GOP < 1 || config.GOP > maxGOP {
+			return Config{}, fmt.Errorf("gop must be between 1 and %d", maxGOP)
+		}
+	}
+
+	return config, nil
+}
+
+// parseBitrate는 "500k", "2M", "800000" 형식의 비트레이트를 파싱합니다
+func parseBitrate(v string) (int, error) {
+	multiplier := 1
+	number := v
+	switch {
+	case strings.HasSuffix(v, "k"), strings.HasSuffix(v, "K"):
+		multiplier, number = 1000, v[:len(v)-1]
+	case strings.HasSuffix(v, "m"), strings.HasSuffix(v, "M"):
+		multiplier, number = 1000_000, v[:len(v)-1]
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 || n > maxBitrate/multiplier {
+		return 0, fmt.Errorf("bitrate must be between 0 and %dM", maxBitrate/1000_000)
+	}
+	return n * multiplier, nil
+}
+
+// Generator는 테스트 패턴 프레임을 H.264 액세스 유닛으로 생성합니다 (동시 사용 불가)
+type Generator struct {
+	config  Config
+	encoder *h264Encoder
+	picture *picture
+	frame   int64
+}
+
+// NewGenerator는 새로운 Generator를 생성합니다 (config는 ParseURL로 검증된 값)
+func NewGenerator(config Config) *Generator {
+	encoder := newH264Encoder(config.Width, config.Height, config.FPS)
+	return &Generator{
+		config:  config,
+		encoder: encoder,
+		picture: newPicture(encoder.mbWidth, encoder.mbHeight),
+	}
+}
+
+// SPS는 시퀀스 파라미터 셋 NAL 유닛을 반환합니다
+func (g *Generator) SPS() []byte {
+	return g.encoder.sps
+}
+
+// PPS는 픽처 파라미터 셋 NAL 유닛을 반환합니다
+func (g *Generator) PPS() []byte {
+	return g.encoder.pps
+}
+
+// FrameDuration은 프레임 간격을 반환합니다
+func (g *Generator) FrameDuration() time.Duration {
+	return time.Second / time.Duration(g.config.FPS)
+}
+
+// Next는 now 시각을 표시한 다음 프레임을 액세스 유닛(NAL 유닛 목록)으로 반환합니다
+// GOP마다 SPS/PPS가 포함된 IDR 프레임을 만들고, Bitrate가 있으면 필러로 프레임 크기를 맞춥니다
+func (g *Generator) Next(now time.Time) [][]byte {
+	drawBars(g.picture, g.frame, g.config.FPS, now)
+
+	idr := g.frame%int64(g.config.GOP) == 0
+	g.frame++
+
+	var au [][]byte
+	if idr {
+		au = append(au, g.encoder.sps, g.encoder.pps)
+	}
+	au = append(au, g.encoder.encode(g.picture, idr))
+
+	if g.config.Bitrate > 0 {
+		size := 0
+		for _, nalu := range au {
+			size += len(nalu)
+		}
+		if target := g.config.Bitrate / 8 / g.config.FPS; size < target {
+			au = append(au, filler(target-size))
+		}
+	}
+
+	return au
+}
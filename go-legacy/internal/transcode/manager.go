@@ -0,0 +1,419 @@
+// Package transcode는 서버 측 트랜스코딩 프로필로 파생 스트림(CAM1~h264_720p)을 제공합니다
+// 트랜스코더(ffmpeg)는 ProcessManager로 실행되며, 내부 RTSP 서버에서 원본을 읽어 파생 스트림 경로로 publish합니다
+package transcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/process"
+	"go.uber.org/zap"
+)
+
+// activityInterval은 시청 중인 트랜스코더의 활동 시간 갱신 주기
+const activityInterval = 5 * time.Second
+
+var (
+	// ErrInvalidProfile은 프로필 값이 잘못된 경우입니다
+	ErrInvalidProfile = errors.New("invalid transcode profile")
+
+	// ErrProfileNotFound는 프로필이 없는 경우입니다
+	ErrProfileNotFound = errors.New("transcode profile not found")
+
+	// ErrProfileExists는 같은 이름의 프로필이 이미 있는 경우입니다
+	ErrProfileExists = errors.New("transcode profile already exists")
+)
+
+// Config는 트랜스코딩 관리자 설정
+type Config struct {
+	FFmpegPath  string
+	H264Encoder string
+	H265Encoder string
+
+	RTSPAddress string // 내부 RTSP 서버 주소 (예: 127.0.0.1:8554)
+	RTSPUser    string // 내부 RTSP 인증 정보 (인증 비활성화면 빈 값)
+	RTSPPass    string
+
+	CloseAfter   time.Duration // 시청자가 없으면 트랜스코더 종료
+	StartTimeout time.Duration // 원본 연결과 트랜스코더 첫 출력 대기 시간
+	Fallback     string        // 브라우저가 원본 코덱을 지원하지 않을 때 우선 사용할 프로필
+
+	ProcessManager *process.Manager
+	StreamManager  *core.StreamManager
+
+	// StartSource는 원본 스트림 소스를 시작합니다 (이미 실행 중이면 nil 반환)
+	StartSource func(streamID string) error
+}
+
+// DerivedStream은 원본 스트림의 파생 스트림 정보입니다
+type DerivedStream struct {
+	ID          string                     `json:"id"`
+	Profile     *database.TranscodeProfile `json:"profile"`
+	Running     bool                       `json:"running"`
+	Subscribers int                        `json:"subscribers"`
+}
+
+// transcoder는 파생 스트림 하나의 트랜스코더입니다
+type transcoder struct {
+	id        string // 파생 스트림 ID
+	baseID    string
+	profile   string
+	processID string
+
+	mutex        sync.Mutex // 시작 직렬화
+	stream       *core.Stream
+	startPackets uint64 // 시작 시점의 수신 패킷 수 (이후 패킷이 들어오면 준비 완료)
+}
+
+// Manager는 트랜스코딩 프로필과 트랜스코더 프로세스를 관리합니다
+type Manager struct {
+	config Config
+	repo   *database.TranscodeProfileRepository
+	logger *zap.Logger
+
+	mutex       sync.Mutex
+	transcoders map[string]*transcoder // 파생 스트림 ID -> transcoder
+}
+
+// NewManager는 새로운 트랜스코딩 관리자를 생성합니다
+func NewManager(config Config, repo *database.TranscodeProfileRepository, logger *zap.Logger) *Manager {
+	if config.FFmpegPath == "" {
+		config.FFmpegPath = "ffmpeg"
+	}
+	if config.H264Encoder == "" {
+		config.H264Encoder = "libx264"
+	}
+	if config.H265Encoder == "" {
+		config.H265Encoder = "libx265"
+	}
+	if config.StartTimeout <= 0 {
+		config.StartTimeout = 10 * time.Second
+	}
+
+	return &Manager{
+		config:      config,
+		repo:        repo,
+		logger:      logger,
+		transcoders: make(map[string]*transcoder),
+	}
+}
+
+// ListProfiles는 모든 프로필을 이름순으로 반환합니다
+func (m *Manager) ListProfiles() ([]*database.TranscodeProfile, error) {
+	return m.repo.List()
+}
+
+// GetProfile은 이름으로 프로필을 조회합니다
+func (m *Manager) GetProfile(name string) (*database.TranscodeProfile, error) {
+	exists, err := m.repo.Exists(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	return m.repo.Get(name)
+}
+
+// CreateProfile은 프로필을 검증한 후 추가합니다
+func (m *Manager) CreateProfile(profile *database.TranscodeProfile) error {
+	if err := ValidateProfile(profile); err != nil {
+		return err
+	}
+
+	exists, err := m.repo.Exists(profile.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrProfileExists, profile.Name)
+	}
+
+	return m.repo.Create(profile)
+}
+
+// UpdateProfile은 프로필을 수정하고, 실행 중인 트랜스코더는 중지합니다 (다음 요청부터 새 설정 적용)
+func (m *Manager) UpdateProfile(profile *database.TranscodeProfile) error {
+	if err := ValidateProfile(profile); err != nil {
+		return err
+	}
+	if _, err := m.GetProfile(profile.Name); err != nil {
+		return err
+	}
+
+	if err := m.repo.Update(profile); err != nil {
+		return err
+	}
+
+	m.stopWhere(func(t *transcoder) bool { return t.profile == profile.Name })
+	return nil
+}
+
+// DeleteProfile은 프로필을 삭제하고 해당 프로필의 트랜스코더를 중지합니다
+func (m *Manager) DeleteProfile(name string) error {
+	if _, err := m.GetProfile(name); err != nil {
+		return err
+	}
+
+	if err := m.repo.Delete(name); err != nil {
+		return err
+	}
+
+	m.stopWhere(func(t *transcoder) bool { return t.profile == name })
+	return nil
+}
+
+// DerivedStreams는 원본 스트림에서 만들 수 있는 파생 스트림 목록을 반환합니다
+func (m *Manager) DerivedStreams(baseID string) ([]DerivedStream, error) {
+	profiles, err := m.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DerivedStream, 0, len(profiles))
+	for _, profile := range profiles {
+		derived := DerivedStream{
+			ID:      core.DerivedStreamID(baseID, profile.Name),
+			Profile: profile,
+		}
+
+		m.mutex.Lock()
+		t, exists := m.transcoders[derived.ID]
+		m.mutex.Unlock()
+
+		if exists {
+			derived.Running = m.config.ProcessManager.IsRunning(t.processID)
+			t.mutex.Lock()
+			if t.stream != nil {
+				derived.Subscribers = t.stream.GetSubscriberCount()
+			}
+			t.mutex.Unlock()
+		}
+
+		result = append(result, derived)
+	}
+
+	return result, nil
+}
+
+// Fallback은 클라이언트가 원본 코덱을 재생할 수 없을 때 사용할 파생 스트림 ID를 반환합니다
+// 설정의 fallback 프로필을 우선하고, 없으면 지원 코덱의 첫 번째 프로필(이름순)을 사용합니다
+func (m *Manager) Fallback(baseID string, supports func(codec string) bool) (string, bool) {
+	profiles, err := m.repo.List()
+	if err != nil {
+		m.logger.Error("Failed to list transcode profiles", zap.Error(err))
+		return "", false
+	}
+
+	var selected *database.TranscodeProfile
+	for _, profile := range profiles {
+		if !supports(StreamCodec(profile.Codec)) {
+			continue
+		}
+		if profile.Name == m.config.Fallback {
+			selected = profile
+			break
+		}
+		if selected == nil {
+			selected = profile
+		}
+	}
+
+	if selected == nil {
+		return "", false
+	}
+	return core.DerivedStreamID(baseID, selected.Name), true
+}
+
+// Start는 파생 스트림의 트랜스코더를 시작하고(실행 중이면 활동 시간만 갱신) 첫 출력을 기다립니다
+func (m *Manager) Start(streamID string) (*core.Stream, error) {
+	baseID, profileName, ok := core.SplitDerivedStreamID(streamID)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a transcoded stream", streamID)
+	}
+
+	profile, err := m.GetProfile(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := m.config.StreamManager.GetStream(baseID)
+	if err != nil {
+		return nil, fmt.Errorf("stream not found: %s", baseID)
+	}
+
+	m.mutex.Lock()
+	t, exists := m.transcoders[streamID]
+	if !exists {
+		t = &transcoder{
+			id:        streamID,
+			baseID:    baseID,
+			profile:   profile.Name,
+			processID: "transcode-" + streamID,
+		}
+		m.transcoders[streamID] = t
+	}
+	m.mutex.Unlock()
+
+	t.mutex.Lock()
+	if m.config.ProcessManager.IsRunning(t.processID) {
+		m.config.ProcessManager.UpdateActivity(t.processID)
+	} else if err := m.launch(t, base, profile); err != nil {
+		t.mutex.Unlock()
+		return nil, err
+	}
+	stream, since := t.stream, t.startPackets
+	t.mutex.Unlock()
+
+	// 트랜스코더가 publish를 시작할 때까지 대기
+	ready := waitUntil(m.config.StartTimeout, func() bool {
+		received, _, _, _ := stream.GetStats()
+		return received > since && stream.GetVideoCodec() != ""
+	})
+	if !ready {
+		return nil, fmt.Errorf("transcoder for %s produced no output within %s", streamID, m.config.StartTimeout)
+	}
+
+	return stream, nil
+}
+
+// launch는 원본 소스를 시작하고 트랜스코더 프로세스를 실행합니다 (t.mutex를 잡은 상태로 호출)
+func (m *Manager) launch(t *transcoder, base *core.Stream, profile *database.TranscodeProfile) error {
+	if m.config.StartSource != nil {
+		if err := m.config.StartSource(t.baseID); err != nil {
+			return fmt.Errorf("failed to start source stream %s: %w", t.baseID, err)
+		}
+	}
+
+	// 내부 RTSP 서버가 원본 SDP를 만들려면 코덱이 필요
+	if !waitUntil(m.config.StartTimeout, func() bool { return base.GetVideoCodec() != "" }) {
+		return fmt.Errorf("source stream %s is not ready", t.baseID)
+	}
+
+	if t.stream == nil {
+		stream, err := m.config.StreamManager.GetStream(t.id)
+		if err != nil {
+			stream, err = m.config.StreamManager.CreateStream(t.id, base.GetName()+" ("+profile.Name+")")
+			if err != nil {
+				return fmt.Errorf("failed to create transcoded stream: %w", err)
+			}
+		}
+		t.stream = stream
+	}
+	t.startPackets, _, _, _ = t.stream.GetStats()
+
+	command, err := m.command(t.baseID, t.id, profile)
+	if err != nil {
+		return err
+	}
+
+	// 비정상 종료 시 재시작, 시청자가 없으면 CloseAfter 후 종료
+	if err := m.config.ProcessManager.Start(t.processID, command, nil, true, m.config.CloseAfter); err != nil {
+		return fmt.Errorf("failed to start transcoder: %w", err)
+	}
+
+	m.logger.Info("Transcoder started",
+		zap.String("stream_id", t.id),
+		zap.String("source_codec", base.GetVideoCodec()),
+		zap.String("codec", profile.Codec),
+		zap.String("resolution", profile.Resolution),
+		zap.String("bitrate", profile.Bitrate),
+	)
+
+	return nil
+}
+
+// StopSource는 원본 스트림의 모든 트랜스코더를 중지합니다
+func (m *Manager) StopSource(baseID string) {
+	m.stopWhere(func(t *transcoder) bool { return t.baseID == baseID })
+}
+
+// Close는 모든 트랜스코더를 중지합니다
+func (m *Manager) Close() {
+	m.stopWhere(func(t *transcoder) bool { return true })
+}
+
+// stopWhere는 조건에 맞는 실행 중인 트랜스코더를 중지합니다
+func (m *Manager) stopWhere(match func(t *transcoder) bool) {
+	m.mutex.Lock()
+	var targets []*transcoder
+	for _, t := range m.transcoders {
+		if match(t) {
+			targets = append(targets, t)
+		}
+	}
+	m.mutex.Unlock()
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].id < targets[j].id })
+
+	for _, t := range targets {
+		t.mutex.Lock()
+		if m.config.ProcessManager.IsRunning(t.processID) {
+			if err := m.config.ProcessManager.Stop(t.processID); err != nil {
+				m.logger.Warn("Failed to stop transcoder",
+					zap.String("stream_id", t.id),
+					zap.Error(err),
+				)
+			} else {
+				m.logger.Info("Transcoder stopped", zap.String("stream_id", t.id))
+			}
+		}
+		t.mutex.Unlock()
+	}
+}
+
+// Run은 시청자가 있는 트랜스코더의 활동 시간을 주기적으로 갱신합니다
+// 시청자가 없으면 ProcessManager의 비활동 모니터가 CloseAfter 후 트랜스코더를 종료합니다
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(activityInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshActivity()
+		}
+	}
+}
+
+// refreshActivity는 시청자가 있는 트랜스코더의 활동 시간을 갱신합니다
+func (m *Manager) refreshActivity() {
+	m.mutex.Lock()
+	transcoders := make([]*transcoder, 0, len(m.transcoders))
+	for _, t := range m.transcoders {
+		transcoders = append(transcoders, t)
+	}
+	m.mutex.Unlock()
+
+	for _, t := range transcoders {
+		t.mutex.Lock()
+		stream := t.stream
+		t.mutex.Unlock()
+
+		if stream != nil && stream.GetSubscriberCount() > 0 {
+			m.config.ProcessManager.UpdateActivity(t.processID)
+		}
+	}
+}
+
+// waitUntil은 조건이 참이 될 때까지 최대 timeout 동안 기다립니다
+func waitUntil(timeout time.Duration, condition func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if condition() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,172 @@
+package transcode
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+var (
+	// profileNamePattern은 프로필 이름 형식 (파생 스트림 ID와 RTSP 경로에 사용)
+	profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+	// sizePattern은 "1280x720" 형식의 해상도
+	sizePattern = regexp.MustCompile(`^(\d{2,5})x(\d{2,5})$`)
+
+	// heightPattern은 "720p" 형식의 해상도 (가로는 원본 비율에 맞춤)
+	heightPattern = regexp.MustCompile(`^(\d{2,5})p$`)
+
+	// bitratePattern은 ffmpeg 비트레이트 형식 ("2000k", "2M", "800000")
+	bitratePattern = regexp.MustCompile(`^[1-9]\d*[kKmM]?$`)
+)
+
+// ValidateProfile은 프로필 값을 정규화하고 검증합니다
+// codec은 소문자로 바꾸고 "hevc"는 "h265"로 취급합니다
+func ValidateProfile(profile *database.TranscodeProfile) error {
+	if !profileNamePattern.MatchString(profile.Name) {
+		return fmt.Errorf("%w: name must be 1-64 characters of letters, digits, '_' or '-': %q", ErrInvalidProfile, profile.Name)
+	}
+
+	profile.Codec = strings.ToLower(strings.TrimSpace(profile.Codec))
+	if profile.Codec == "hevc" {
+		profile.Codec = "h265"
+	}
+	if profile.Codec != "h264" && profile.Codec != "h265" {
+		return fmt.Errorf("%w: codec must be h264 or h265: %q", ErrInvalidProfile, profile.Codec)
+	}
+
+	profile.Resolution = strings.ToLower(strings.TrimSpace(profile.Resolution))
+	if profile.Resolution != "" {
+		if _, err := scaleFilter(profile.Resolution); err != nil {
+			return err
+		}
+	}
+
+	profile.Bitrate = strings.TrimSpace(profile.Bitrate)
+	if profile.Bitrate != "" && !bitratePattern.MatchString(profile.Bitrate) {
+		return fmt.Errorf("%w: bitrate must look like 2000k, 2M or 800000: %q", ErrInvalidProfile, profile.Bitrate)
+	}
+
+	if profile.GOP < 0 {
+		return fmt.Errorf("%w: gop must not be negative: %d", ErrInvalidProfile, profile.GOP)
+	}
+
+	return nil
+}
+
+// StreamCodec은 프로필 코덱 이름을 스트림 코덱 이름(H264, H265)으로 변환합니다
+func StreamCodec(codec string) string {
+	if codec == "h265" {
+		return "H265"
+	}
+	return "H264"
+}
+
+// scaleFilter는 해상도를 ffmpeg scale 필터로 변환합니다
+// "1280x720"은 원본 비율을 유지하며 그 안에 맞추고, "720p"는 높이만 지정합니다
+func scaleFilter(resolution string) (string, error) {
+	if m := sizePattern.FindStringSubmatch(resolution); m != nil {
+		width, _ := strconv.Atoi(m[1])
+		height, _ := strconv.Atoi(m[2])
+		if width%2 != 0 || height%2 != 0 {
+			return "", fmt.Errorf("%w: resolution width and height must be even: %q", ErrInvalidProfile, resolution)
+		}
+		return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease:force_divisible_by=2", width, height), nil
+	}
+
+	if m := heightPattern.FindStringSubmatch(resolution); m != nil {
+		height, _ := strconv.Atoi(m[1])
+		if height%2 != 0 {
+			return "", fmt.Errorf("%w: resolution height must be even: %q", ErrInvalidProfile, resolution)
+		}
+		return fmt.Sprintf("scale=-2:%d", height), nil
+	}
+
+	return "", fmt.Errorf("%w: resolution must look like 1280x720 or 720p: %q", ErrInvalidProfile, resolution)
+}
+
+// rtspURL은 내부 RTSP 서버의 스트림 URL을 만듭니다
+func (m *Manager) rtspURL(streamID string) string {
+	u := url.URL{
+		Scheme: "rtsp",
+		Host:   m.config.RTSPAddress,
+		Path:   "/" + streamID,
+	}
+	if m.config.RTSPUser != "" {
+		u.User = url.UserPassword(m.config.RTSPUser, m.config.RTSPPass)
+	}
+	return u.String()
+}
+
+// command는 원본 스트림을 읽어 프로필대로 인코딩한 후 파생 스트림으로 publish하는 ffmpeg 명령을 만듭니다
+// 오디오는 제외합니다 (브라우저 코덱 호환과 화질 변환 목적)
+func (m *Manager) command(baseID, derivedID string, profile *database.TranscodeProfile) (string, error) {
+	encoder := m.config.H264Encoder
+	if profile.Codec == "h265" {
+		encoder = m.config.H265Encoder
+	}
+
+	args := []string{
+		m.config.FFmpegPath,
+		"-nostdin", "-hide_banner", "-loglevel", "error",
+		"-rtsp_transport", "tcp",
+		"-i", m.rtspURL(baseID),
+		"-an",
+		"-c:v", encoder,
+	}
+
+	// 소프트웨어 인코더는 저지연 설정 (하드웨어 인코더는 기본값 사용)
+	switch encoder {
+	case "libx264":
+		args = append(args, "-preset", "veryfast", "-tune", "zerolatency", "-profile:v", "baseline")
+	case "libx265":
+		args = append(args, "-preset", "veryfast", "-tune", "zerolatency")
+	}
+
+	if profile.Resolution != "" {
+		filter, err := scaleFilter(profile.Resolution)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "-vf", filter)
+	}
+	if profile.Bitrate != "" {
+		args = append(args, "-b:v", profile.Bitrate, "-maxrate", profile.Bitrate)
+	}
+	if profile.GOP > 0 {
+		gop := strconv.Itoa(profile.GOP)
+		args = append(args, "-g", gop, "-keyint_min", gop)
+	}
+
+	args = append(args,
+		"-bf", "0",
+		"-pix_fmt", "yuv420p",
+		"-f", "rtsp", "-rtsp_transport", "tcp",
+		m.rtspURL(derivedID),
+	)
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " "), nil
+}
+
+// shellQuote는 sh -c 명령 인자를 작은따옴표로 감쌉니다 (안전한 문자만 있으면 그대로)
+func shellQuote(s string) string {
+	safe := s != ""
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,597 @@
+// Package webhook은 스트림/시청/녹화 이벤트를 외부 HTTP 엔드포인트로 전달합니다
+// 전송은 SQLite 대기열(webhook_deliveries)에 먼저 저장한 뒤 백그라운드에서 보내므로
+// 실패한 전송은 지수 백오프로 재시도되고 서버를 다시 시작해도 이어서 전송됩니다
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/cctv3/internal/core"
+	"github.com/yourusername/cctv3/internal/database"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidWebhook은 웹훅 값이 잘못된 경우입니다
+	ErrInvalidWebhook = errors.New("invalid webhook")
+
+	// ErrWebhookNotFound는 웹훅이 없는 경우입니다
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+const (
+	// pollInterval은 재시도 시각이 된 전송을 확인하는 주기 (새 이벤트는 즉시 전송)
+	pollInterval = time.Second
+
+	// batchSize는 한 번에 가져오는 대기 중인 전송 수
+	batchSize = 50
+
+	// maxConcurrent는 동시에 보내는 요청 수
+	maxConcurrent = 4
+
+	// maxErrorLength는 전송 기록에 남길 응답 본문/에러 최대 길이
+	maxErrorLength = 500
+
+	// secretBytes는 서명 키를 지정하지 않았을 때 생성하는 키 길이
+	secretBytes = 32
+)
+
+// Config는 웹훅 관리자 설정
+type Config struct {
+	Timeout      time.Duration // 요청 타임아웃
+	MaxAttempts  int           // 최대 전송 시도 횟수
+	RetryBackoff time.Duration // 첫 재시도 대기 시간 (시도마다 2배)
+	MaxBackoff   time.Duration // 재시도 대기 시간 상한
+	Retention    time.Duration // 완료된 전송 기록 보관 기간 (0이면 무기한)
+}
+
+// Manager는 웹훅 엔드포인트를 관리하고 이벤트를 전송합니다
+type Manager struct {
+	config Config
+	repo   *database.WebhookRepository
+	logger *zap.Logger
+	client *http.Client
+
+	// 웹훅 목록 캐시 (이벤트마다 DB를 조회하지 않도록, 변경 시 다시 로드)
+	webhookMutex sync.RWMutex
+	webhooks     map[int64]*database.Webhook
+
+	// 새 전송 알림 (폴링 주기를 기다리지 않고 바로 전송)
+	wake chan struct{}
+
+	// 전송 중인 항목 (같은 전송을 중복으로 보내지 않도록)
+	inflightMutex sync.Mutex
+	inflight      map[int64]struct{}
+	slots         chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager는 새로운 웹훅 관리자를 생성합니다
+func NewManager(config Config, repo *database.WebhookRepository, logger *zap.Logger) *Manager {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 8
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 5 * time.Second
+	}
+	if config.MaxBackoff < config.RetryBackoff {
+		config.MaxBackoff = config.RetryBackoff
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{
+		config:   config,
+		repo:     repo,
+		logger:   logger,
+		client:   &http.Client{Timeout: config.Timeout},
+		webhooks: make(map[int64]*database.Webhook),
+		wake:     make(chan struct{}, 1),
+		inflight: make(map[int64]struct{}),
+		slots:    make(chan struct{}, maxConcurrent),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start는 웹훅 목록을 불러오고 전송/보관 기간 정리 고루틴을 시작합니다
+// 이전 실행에서 보내지 못한 전송은 바로 이어서 보냅니다
+func (m *Manager) Start(ctx context.Context) error {
+	if err := m.reload(); err != nil {
+		return err
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(ctx)
+	}()
+
+	go func() {
+		retentionTicker := time.NewTicker(time.Hour)
+		defer retentionTicker.Stop()
+
+		m.deleteExpired()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.ctx.Done():
+				return
+			case <-retentionTicker.C:
+				m.deleteExpired()
+			}
+		}
+	}()
+
+	m.notify()
+	return nil
+}
+
+// Close는 전송을 중지하고 진행 중인 요청이 끝나기를 기다립니다
+// 완료되지 않은 전송은 대기열에 남아 다음 실행 때 다시 보냅니다
+func (m *Manager) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// List는 모든 웹훅을 반환합니다
+func (m *Manager) List() ([]*database.Webhook, error) {
+	return m.repo.List()
+}
+
+// Get은 ID로 웹훅을 조회합니다
+func (m *Manager) Get(id int64) (*database.Webhook, error) {
+	m.webhookMutex.RLock()
+	_, exists := m.webhooks[id]
+	m.webhookMutex.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %d", ErrWebhookNotFound, id)
+	}
+	return m.repo.Get(id)
+}
+
+// Create는 웹훅을 검증한 후 추가합니다 (서명 키가 비어있으면 생성)
+func (m *Manager) Create(webhook *database.Webhook) error {
+	if err := validate(webhook); err != nil {
+		return err
+	}
+	if webhook.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+
+	if err := m.repo.Create(webhook); err != nil {
+		return err
+	}
+	return m.reload()
+}
+
+// Update는 웹훅을 수정합니다 (서명 키가 비어있으면 기존 키 유지)
+func (m *Manager) Update(webhook *database.Webhook) error {
+	if err := validate(webhook); err != nil {
+		return err
+	}
+
+	current, err := m.Get(webhook.ID)
+	if err != nil {
+		return err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = current.Secret
+	}
+
+	if err := m.repo.Update(webhook); err != nil {
+		return err
+	}
+	return m.reload()
+}
+
+// Delete는 웹훅과 전송 기록을 삭제합니다
+func (m *Manager) Delete(id int64) error {
+	if _, err := m.Get(id); err != nil {
+		return err
+	}
+
+	if err := m.repo.Delete(id); err != nil {
+		return err
+	}
+	return m.reload()
+}
+
+// Deliveries는 조건에 맞는 전송 기록을 최신순으로 조회합니다
+func (m *Manager) Deliveries(filter database.DeliveryFilter) ([]*database.WebhookDelivery, int, error) {
+	return m.repo.QueryDeliveries(filter)
+}
+
+// Test는 웹훅에 확인용 이벤트(webhook.test)를 보냅니다 (이벤트 필터와 무관)
+func (m *Manager) Test(id int64) (*database.WebhookDelivery, error) {
+	if _, err := m.Get(id); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(Payload{
+		Event: EventTest,
+		Time:  time.Now().UTC(),
+		Data:  map[string]int64{"webhook_id": id},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delivery := &database.WebhookDelivery{
+		WebhookID: id,
+		Event:     EventTest,
+		Payload:   body,
+	}
+	if err := m.repo.EnqueueDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	m.notify()
+	return delivery, nil
+}
+
+// Publish는 이벤트를 구독하는 활성화된 웹훅마다 전송을 대기열에 추가합니다
+func (m *Manager) Publish(event string, data interface{}) {
+	m.webhookMutex.RLock()
+	var targets []int64
+	for id, webhook := range m.webhooks {
+		if webhook.Enabled && webhook.Accepts(event) {
+			targets = append(targets, id)
+		}
+	}
+	m.webhookMutex.RUnlock()
+
+	if len(targets) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(Payload{
+		Event: event,
+		Time:  time.Now().UTC(),
+		Data:  data,
+	})
+	if err != nil {
+		m.logger.Error("Failed to encode webhook payload",
+			zap.String("event", event),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, id := range targets {
+		if err := m.repo.EnqueueDelivery(&database.WebhookDelivery{
+			WebhookID: id,
+			Event:     event,
+			Payload:   body,
+		}); err != nil {
+			m.logger.Error("Failed to enqueue webhook delivery",
+				zap.Int64("webhook_id", id),
+				zap.String("event", event),
+				zap.Error(err),
+			)
+		}
+	}
+
+	m.notify()
+}
+
+// StreamHooks는 스트림 상태 변화를 웹훅 이벤트로 보내는 콜백을 반환합니다
+func (m *Manager) StreamHooks() core.StreamHooks {
+	return core.StreamHooks{
+		OnReady: func(stream *core.Stream, sourceType, sourceID string) {
+			m.Publish(EventStreamOnline, StreamData{
+				StreamID:   stream.GetID(),
+				SourceType: sourceType,
+				SourceID:   sourceID,
+				Codec:      stream.GetVideoCodec(),
+			})
+		},
+		OnNotReady: func(stream *core.Stream, sourceType, sourceID string) {
+			m.Publish(EventStreamOffline, StreamData{
+				StreamID:   stream.GetID(),
+				SourceType: sourceType,
+				SourceID:   sourceID,
+			})
+		},
+		OnRead: func(stream *core.Stream, readerType, readerID string) {
+			m.Publish(EventViewerJoined, ViewerData{
+				StreamID:   stream.GetID(),
+				ReaderType: readerType,
+				ReaderID:   readerID,
+			})
+		},
+		OnUnread: func(stream *core.Stream, readerType, readerID string) {
+			m.Publish(EventViewerLeft, ViewerData{
+				StreamID:   stream.GetID(),
+				ReaderType: readerType,
+				ReaderID:   readerID,
+			})
+		},
+		OnCodecChange: func(stream *core.Stream, oldCodec, newCodec string) {
+			// 처음 감지된 코덱은 변경이 아님
+			if oldCodec == "" {
+				return
+			}
+			m.Publish(EventStreamCodecChanged, CodecData{
+				StreamID: stream.GetID(),
+				OldCodec: oldCodec,
+				NewCodec: newCodec,
+			})
+		},
+	}
+}
+
+// reload는 웹훅 목록 캐시를 DB에서 다시 불러옵니다
+func (m *Manager) reload() error {
+	list, err := m.repo.List()
+	if err != nil {
+		return err
+	}
+
+	webhooks := make(map[int64]*database.Webhook, len(list))
+	for _, webhook := range list {
+		webhooks[webhook.ID] = webhook
+	}
+
+	m.webhookMutex.Lock()
+	m.webhooks = webhooks
+	m.webhookMutex.Unlock()
+	return nil
+}
+
+// notify는 전송 고루틴을 깨웁니다
+func (m *Manager) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run은 새 전송 알림이나 폴링 주기마다 보낼 시각이 된 전송을 보냅니다
+func (m *Manager) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.wake:
+		}
+		m.dispatch()
+	}
+}
+
+// dispatch는 보낼 시각이 된 전송을 가져와 동시 요청 수 안에서 보냅니다
+func (m *Manager) dispatch() {
+	due, err := m.repo.DueDeliveries(time.Now(), batchSize)
+	if err != nil {
+		m.logger.Error("Failed to load webhook deliveries", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range due {
+		m.inflightMutex.Lock()
+		_, busy := m.inflight[delivery.ID]
+		if !busy {
+			m.inflight[delivery.ID] = struct{}{}
+		}
+		m.inflightMutex.Unlock()
+		if busy {
+			continue
+		}
+
+		select {
+		case m.slots <- struct{}{}:
+		case <-m.ctx.Done():
+			return
+		}
+
+		m.wg.Add(1)
+		go func(delivery *database.WebhookDelivery) {
+			defer func() {
+				<-m.slots
+				m.inflightMutex.Lock()
+				delete(m.inflight, delivery.ID)
+				m.inflightMutex.Unlock()
+				m.wg.Done()
+			}()
+			m.deliver(delivery)
+		}(delivery)
+	}
+}
+
+// deliver는 전송 하나를 보내고 결과에 따라 완료, 재시도 예약, 실패로 기록합니다
+func (m *Manager) deliver(delivery *database.WebhookDelivery) {
+	m.webhookMutex.RLock()
+	webhook, exists := m.webhooks[delivery.WebhookID]
+	m.webhookMutex.RUnlock()
+
+	var statusCode int
+	var err error
+	retry := true
+	switch {
+	case !exists:
+		err = fmt.Errorf("webhook %d was deleted", delivery.WebhookID)
+		retry = false
+	case !webhook.Enabled && delivery.Event != EventTest:
+		err = fmt.Errorf("webhook %d is disabled", delivery.WebhookID)
+		retry = false
+	default:
+		statusCode, err = m.send(webhook, delivery)
+		// 종료 중 취소된 요청은 시도로 세지 않고 다음 실행 때 다시 보냄
+		if err != nil && m.ctx.Err() != nil {
+			return
+		}
+		delivery.Attempts++
+	}
+
+	delivery.LastStatusCode = statusCode
+
+	switch {
+	case err == nil:
+		delivery.Status = database.DeliveryDelivered
+		delivery.LastError = ""
+	case !retry || delivery.Attempts >= m.config.MaxAttempts:
+		delivery.Status = database.DeliveryFailed
+		delivery.LastError = truncate(err.Error())
+	default:
+		delivery.NextAttempt = time.Now().Add(m.backoff(delivery.Attempts))
+		delivery.LastError = truncate(err.Error())
+	}
+
+	if err := m.repo.UpdateDelivery(delivery); err != nil {
+		m.logger.Error("Failed to update webhook delivery",
+			zap.Int64("delivery_id", delivery.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if delivery.Status == database.DeliveryDelivered {
+		m.logger.Debug("Webhook delivered",
+			zap.Int64("webhook_id", delivery.WebhookID),
+			zap.Int64("delivery_id", delivery.ID),
+			zap.String("event", delivery.Event),
+		)
+		return
+	}
+
+	m.logger.Warn("Webhook delivery failed",
+		zap.Int64("webhook_id", delivery.WebhookID),
+		zap.Int64("delivery_id", delivery.ID),
+		zap.String("event", delivery.Event),
+		zap.Int("attempts", delivery.Attempts),
+		zap.String("status", delivery.Status),
+		zap.Error(err),
+	)
+}
+
+// send는 서명한 요청을 보내고 응답 상태 코드를 반환합니다 (2xx가 아니면 에러)
+func (m *Manager) send(webhook *database.Webhook, delivery *database.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(m.ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(body))
+		if message != "" {
+			return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, message)
+		}
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff는 attempts번 실패한 뒤의 재시도 대기 시간을 반환합니다 (RetryBackoff * 2^(attempts-1), MaxBackoff 상한)
+func (m *Manager) backoff(attempts int) time.Duration {
+	delay := m.config.RetryBackoff
+	for i := 1; i < attempts && delay < m.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > m.config.MaxBackoff {
+		delay = m.config.MaxBackoff
+	}
+	return delay
+}
+
+// deleteExpired는 보관 기간이 지난 완료/실패 전송 기록을 삭제합니다
+func (m *Manager) deleteExpired() {
+	if m.config.Retention <= 0 {
+		return
+	}
+
+	deleted, err := m.repo.DeleteDeliveriesBefore(time.Now().Add(-m.config.Retention))
+	if err != nil {
+		m.logger.Error("Failed to delete expired webhook deliveries", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		m.logger.Info("Expired webhook deliveries deleted", zap.Int64("count", deleted))
+	}
+}
+
+// validate는 웹훅 값을 검증합니다
+func validate(webhook *database.Webhook) error {
+	if webhook.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWebhook)
+	}
+
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL: %q", ErrInvalidWebhook, webhook.URL)
+	}
+
+	for _, event := range webhook.Events {
+		if !IsValidEvent(event) {
+			return fmt.Errorf("%w: unknown event %q (supported: %s)", ErrInvalidWebhook, event, strings.Join(Events, ", "))
+		}
+	}
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	return nil
+}
+
+// generateSecret은 임의의 서명 키를 생성합니다
+func generateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// truncate는 전송 기록에 남길 에러 메시지를 자릅니다
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength] + "..."
+	}
+	return message
+}
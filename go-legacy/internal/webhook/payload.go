@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// 웹훅 이벤트
+const (
+	EventStreamOnline             = "stream.online"              // 스트림 소스 연결/송출 시작
+	EventStreamOffline            = "stream.offline"             // 스트림 소스 연결 끊김/송출 종료
+	EventStreamCodecChanged       = "stream.codec_changed"       // 소스 재연결 후 비디오 코덱 변경
+	EventViewerJoined             = "viewer.joined"              // 시청 시작 (WebRTC, RTSP)
+	EventViewerLeft               = "viewer.left"                // 시청 종료
+	EventRecordingSegmentComplete = "recording.segment_complete" // 녹화 세그먼트 파일 완료
+
+	// EventTest는 POST /api/v1/webhooks/:id/test로 보내는 확인용 이벤트입니다 (이벤트 필터와 무관)
+	EventTest = "webhook.test"
+)
+
+// Events는 웹훅으로 구독할 수 있는 이벤트 목록입니다
+var Events = []string{
+	EventStreamOnline,
+	EventStreamOffline,
+	EventStreamCodecChanged,
+	EventViewerJoined,
+	EventViewerLeft,
+	EventRecordingSegmentComplete,
+}
+
+// IsValidEvent는 구독할 수 있는 이벤트인지 확인합니다
+func IsValidEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// 웹훅 요청 헤더
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"  // 전송 ID (재시도 시 동일, 수신 측 중복 제거용)
+	HeaderTimestamp = "X-Webhook-Timestamp" // 서명 시각 (Unix 초)
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + HMAC-SHA256(secret, timestamp + "." + body) hex
+)
+
+// Payload는 웹훅 요청 본문입니다
+type Payload struct {
+	Event string      `json:"event"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// StreamData는 stream.online/offline 이벤트 데이터입니다
+type StreamData struct {
+	StreamID   string `json:"stream_id"`
+	SourceType string `json:"source_type,omitempty"` // rtspSource, rtspSession, rtmpConn 등
+	SourceID   string `json:"source_id,omitempty"`
+	Codec      string `json:"codec,omitempty"`
+}
+
+// CodecData는 stream.codec_changed 이벤트 데이터입니다
+type CodecData struct {
+	StreamID string `json:"stream_id"`
+	OldCodec string `json:"old_codec"`
+	NewCodec string `json:"new_codec"`
+}
+
+// ViewerData는 viewer.joined/left 이벤트 데이터입니다
+type ViewerData struct {
+	StreamID   string `json:"stream_id"`
+	ReaderType string `json:"reader_type"` // webRTCSession, rtspSession
+	ReaderID   string `json:"reader_id"`
+}
+
+// SegmentData는 recording.segment_complete 이벤트 데이터입니다
+type SegmentData struct {
+	StreamID string    `json:"stream_id"`
+	Path     string    `json:"path"`
+	Start    time.Time `json:"start"`
+	Duration float64   `json:"duration"` // 초
+	Size     int64     `json:"size"`     // 바이트
+}
+
+// Sign은 요청 본문의 서명 헤더 값을 만듭니다
+// 수신 측은 같은 방식으로 계산한 값과 X-Webhook-Signature를 비교하고, 오래된 타임스탬프는 거부해 재전송을 막습니다
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -17,6 +17,7 @@ type Manager struct {
 	mutex sync.RWMutex
 
 	maxPeers     int
+	iceServers   []webrtc.ICEServer
 	onPeerClosed func(peerID string)
 
 	// 공유 WebRTC API (싱글톤 패턴 - 메모리 절약)
@@ -28,9 +29,20 @@ type Manager struct {
 type ManagerConfig struct {
 	Logger       *zap.Logger
 	MaxPeers     int
+	ICEServers   []ICEServer         // 비어있으면 defaultICEServers 사용
 	OnPeerClosed func(peerID string) // 피어가 닫힐 때 호출되는 콜백
 }
 
+// ICEServer는 STUN/TURN 서버 설정
+type ICEServer struct {
+	URLs []string
+}
+
+// defaultICEServers는 ICE 서버가 설정되지 않았을 때 사용하는 공개 STUN 서버입니다
+var defaultICEServers = []ICEServer{
+	{URLs: []string{"stun:stun.l.google.com:19302", "stun:stun1.l.google.com:19302"}},
+}
+
 // NewManager는 새로운 WebRTC 관리자를 생성합니다
 func NewManager(config ManagerConfig) *Manager {
 	if config.MaxPeers == 0 {
@@ -41,6 +53,7 @@ func NewManager(config ManagerConfig) *Manager {
 		logger:       config.Logger,
 		peers:        make(map[string]*Peer),
 		maxPeers:     config.MaxPeers,
+		iceServers:   toICEServers(config.ICEServers),
 		onPeerClosed: config.OnPeerClosed,
 	}
 
@@ -123,9 +136,10 @@ func (m *Manager) CreatePeer(streamID string) (*Peer, error) {
 	}
 
 	peer := NewPeer(PeerConfig{
-		StreamID:  streamID,
-		Logger:    m.logger,
-		SharedAPI: m.sharedAPI, // 공유 API 전달
+		StreamID:   streamID,
+		Logger:     m.logger,
+		SharedAPI:  m.sharedAPI, // 공유 API 전달
+		ICEServers: m.iceServers,
 		OnClose: func(peerID string) {
 			// 고루틴으로 실행하여 데드락 방지
 			go func() {
@@ -149,6 +163,45 @@ func (m *Manager) CreatePeer(streamID string) (*Peer, error) {
 	return peer, nil
 }
 
+// SetMaxPeers는 최대 피어 수를 변경합니다 (이미 연결된 피어는 유지)
+func (m *Manager) SetMaxPeers(maxPeers int) {
+	if maxPeers == 0 {
+		maxPeers = 1000
+	}
+
+	m.mutex.Lock()
+	m.maxPeers = maxPeers
+	m.mutex.Unlock()
+
+	m.logger.Info("WebRTC max peers updated", zap.Int("max_peers", maxPeers))
+}
+
+// SetICEServers는 ICE 서버를 변경합니다 (이후 생성되는 피어부터 적용)
+func (m *Manager) SetICEServers(servers []ICEServer) {
+	iceServers := toICEServers(servers)
+
+	m.mutex.Lock()
+	m.iceServers = iceServers
+	m.mutex.Unlock()
+
+	m.logger.Info("WebRTC ICE servers updated", zap.Int("count", len(iceServers)))
+}
+
+// toICEServers는 설정을 pion ICE 서버 목록으로 변환합니다
+func toICEServers(servers []ICEServer) []webrtc.ICEServer {
+	if len(servers) == 0 {
+		servers = defaultICEServers
+	}
+
+	iceServers := make([]webrtc.ICEServer, 0, len(servers))
+	for _, server := range servers {
+		iceServers = append(iceServers, webrtc.ICEServer{
+			URLs: append([]string(nil), server.URLs...),
+		})
+	}
+	return iceServers
+}
+
 // GetPeer는 피어를 조회합니다
 func (m *Manager) GetPeer(peerID string) (*Peer, error) {
 	m.mutex.RLock()
@@ -190,6 +243,18 @@ func (m *Manager) GetPeerCount() int {
 	return len(m.peers)
 }
 
+// ListPeers는 현재 피어 목록을 반환합니다
+func (m *Manager) ListPeers() []*Peer {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	peers := make([]*Peer, 0, len(m.peers))
+	for _, peer := range m.peers {
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
 // Close는 모든 피어를 종료합니다
 func (m *Manager) Close() {
 	m.logger.Info("Closing WebRTC manager")
@@ -13,12 +13,16 @@ import (
 	"go.uber.org/zap"
 )
 
+// ReaderType은 WebRTC 시청자 종류입니다 (경로 훅 MTX_READER_TYPE)
+const ReaderType = "webRTCSession"
+
 // Peer는 WebRTC 피어 연결을 나타냅니다
 type Peer struct {
-	id        string
-	streamID  string
-	logger    *zap.Logger
-	sharedAPI *webrtc.API // 공유 API (싱글톤)
+	id         string
+	streamID   string
+	logger     *zap.Logger
+	sharedAPI  *webrtc.API // 공유 API (싱글톤)
+	iceServers []webrtc.ICEServer
 
 	// 상태
 	ctx       context.Context
@@ -27,6 +31,10 @@ type Peer struct {
 	mutex     sync.RWMutex
 	closeOnce sync.Once // Close가 여러 번 호출되는 것을 방지
 
+	// 최초 연결 시 닫히는 채널 (WaitConnected용)
+	connectedCh   chan struct{}
+	connectedOnce sync.Once
+
 	// pion/webrtc
 	pc         *webrtc.PeerConnection
 	videoTrack *webrtc.TrackLocalStaticRTP
@@ -35,6 +43,9 @@ type Peer struct {
 	// 콜백
 	onClose func(peerID string)
 
+	// 화질 전환 (스트림 그룹 피어만 사용, rendition.go)
+	sources sourceSwitch
+
 	// 통계
 	packetsSent uint64
 	bytesSent   uint64
@@ -43,10 +54,11 @@ type Peer struct {
 
 // PeerConfig는 WebRTC 피어 설정
 type PeerConfig struct {
-	StreamID  string
-	Logger    *zap.Logger
-	SharedAPI *webrtc.API // 공유 API (싱글톤)
-	OnClose   func(peerID string)
+	StreamID   string
+	Logger     *zap.Logger
+	SharedAPI  *webrtc.API // 공유 API (싱글톤)
+	ICEServers []webrtc.ICEServer
+	OnClose    func(peerID string)
 }
 
 // NewPeer는 새로운 WebRTC 피어를 생성합니다
@@ -55,13 +67,16 @@ func NewPeer(config PeerConfig) *Peer {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	peer := &Peer{
-		id:        id,
-		streamID:  config.StreamID,
-		logger:    config.Logger.With(zap.String("peer_id", id)),
-		sharedAPI: config.SharedAPI, // 공유 API 저장
-		ctx:       ctx,
-		ctxCancel: cancel,
-		onClose:   config.OnClose,
+		id:         id,
+		streamID:   config.StreamID,
+		logger:     config.Logger.With(zap.String("peer_id", id)),
+		sharedAPI:  config.SharedAPI, // 공유 API 저장
+		iceServers: config.ICEServers,
+		ctx:        ctx,
+		ctxCancel:  cancel,
+		onClose:    config.OnClose,
+
+		connectedCh: make(chan struct{}),
 	}
 
 	peer.logger.Info("WebRTC peer created",
@@ -133,18 +148,25 @@ func (p *Peer) CreateOffer(offer string, streamCodec string) (answer string, err
 	return finalAnswer.SDP, nil
 }
 
-// selectVideoCodec는 RTSP 스트림 코덱과 클라이언트가 지원하는 코덱을 비교하여 선택합니다
-func (p *Peer) selectVideoCodec(offerSDP string, streamCodec string) string {
+// SupportsCodec은 클라이언트 Offer SDP가 비디오 코덱(H264, H265)을 지원하는지 확인합니다
+func SupportsCodec(offerSDP string, codec string) bool {
 	// SDP 내용을 대소문자 구분 없이 검색
 	offerUpper := strings.ToUpper(offerSDP)
 
-	// H.265/HEVC 지원 여부 확인
-	supportsH265 := strings.Contains(offerUpper, "H265") ||
-		strings.Contains(offerUpper, "HEVC")
+	switch codec {
+	case "H265":
+		return strings.Contains(offerUpper, "H265") || strings.Contains(offerUpper, "HEVC")
+	case "H264":
+		return strings.Contains(offerUpper, "H264") || strings.Contains(offerUpper, "AVC")
+	}
+	return false
+}
 
-	// H.264/AVC 지원 여부 확인
-	supportsH264 := strings.Contains(offerUpper, "H264") ||
-		strings.Contains(offerUpper, "AVC")
+// selectVideoCodec는 RTSP 스트림 코덱과 클라이언트가 지원하는 코덱을 비교하여 선택합니다
+// 클라이언트가 스트림 코덱을 지원하지 않는 경우 서버 측 트랜스코딩 스트림을 사용해야 합니다
+func (p *Peer) selectVideoCodec(offerSDP string, streamCodec string) string {
+	supportsH265 := SupportsCodec(offerSDP, "H265")
+	supportsH264 := SupportsCodec(offerSDP, "H264")
 
 	// 스트림 코덱이 지정된 경우, 클라이언트가 지원하는지 확인
 	if streamCodec != "" {
@@ -181,14 +203,7 @@ func (p *Peer) selectVideoCodec(offerSDP string, streamCodec string) string {
 func (p *Peer) createPeerConnection(selectedCodec string) error {
 	// PeerConnection 설정
 	config := webrtc.Configuration{
-		ICEServers: []webrtc.ICEServer{
-			{
-				URLs: []string{
-					"stun:stun.l.google.com:19302",
-					"stun:stun1.l.google.com:19302",
-				},
-			},
-		},
+		ICEServers: p.iceServers,
 	}
 
 	// 공유 API를 사용하여 PeerConnection 생성 (싱글톤 패턴)
@@ -309,6 +324,19 @@ func (p *Peer) GetID() string {
 	return p.id
 }
 
+// ReaderType은 시청자 종류를 반환합니다 (core.StreamReader 인터페이스, runOnRead 훅)
+func (p *Peer) ReaderType() string {
+	return ReaderType
+}
+
+// GetStreamID는 피어가 시청 중인 스트림 ID를 반환합니다 (스트림 그룹 피어는 현재 화질의 스트림)
+func (p *Peer) GetStreamID() string {
+	if active, _ := p.ActiveSource(); active != "" {
+		return active
+	}
+	return p.streamID
+}
+
 // OnPacket은 StreamSubscriber 인터페이스 구현
 func (p *Peer) OnPacket(packet *rtp.Packet) error {
 	p.mutex.RLock()
@@ -340,6 +368,7 @@ func (p *Peer) SetConnected(connected bool) {
 	p.connected = connected
 
 	if connected {
+		p.connectedOnce.Do(func() { close(p.connectedCh) })
 		p.logger.Info("Peer connected")
 	} else {
 		p.logger.Info("Peer disconnected")
@@ -353,6 +382,23 @@ func (p *Peer) IsConnected() bool {
 	return p.connected
 }
 
+// Connected는 피어가 처음 연결되면 닫히는 채널을 반환합니다
+func (p *Peer) Connected() <-chan struct{} {
+	return p.connectedCh
+}
+
+// WaitConnected는 피어가 연결될 때까지 대기합니다
+func (p *Peer) WaitConnected(timeout time.Duration) error {
+	select {
+	case <-p.connectedCh:
+		return nil
+	case <-p.ctx.Done():
+		return fmt.Errorf("peer closed before connecting")
+	case <-time.After(timeout):
+		return fmt.Errorf("peer connection timeout (%v)", timeout)
+	}
+}
+
 // Close는 피어 연결을 종료합니다 (여러 번 호출되어도 한 번만 실행됨)
 func (p *Peer) Close() {
 	p.closeOnce.Do(func() {
@@ -0,0 +1,175 @@
+package webrtc
+
+import (
+	"sync"
+
+	"github.com/pion/rtp"
+	"go.uber.org/zap"
+)
+
+// frameGap은 화질 전환 시 이전 소스의 마지막 프레임과 새 소스 첫 프레임 사이의 타임스탬프 간격 (90kHz, 약 30fps)
+const frameGap = 3000
+
+// sourceSwitch는 여러 스트림(화질)을 하나의 비디오 트랙으로 보내기 위한 상태입니다
+// 새 소스는 키프레임(또는 파라미터 셋)부터 전송하고, 시퀀스 번호와 타임스탬프를 이어 붙여
+// 브라우저가 재협상 없이 같은 트랙에서 계속 재생하도록 합니다
+type sourceSwitch struct {
+	mutex sync.Mutex
+
+	active  string // 현재 전송 중인 스트림 ID
+	pending string // 키프레임을 기다리는 전환 대상 스트림 ID
+	codec   string // 전환 대상 코덱 (키프레임 판별용)
+
+	seqOffset uint16
+	tsOffset  uint32
+	lastSeq   uint16
+	lastTS    uint32
+	written   bool
+
+	onSwitch func(from, to string)
+}
+
+// SourceSubscriber는 스트림 그룹 피어가 화질 스트림 하나를 구독할 때 사용하는 구독자입니다
+// 구독자 ID는 피어 ID와 같으며, 활성 소스가 아닌 스트림의 패킷은 버립니다
+type SourceSubscriber struct {
+	peer     *Peer
+	streamID string
+}
+
+// GetID는 피어 ID를 반환합니다
+func (s *SourceSubscriber) GetID() string {
+	return s.peer.id
+}
+
+// ReaderType은 시청자 종류를 반환합니다 (core.StreamReader 인터페이스)
+func (s *SourceSubscriber) ReaderType() string {
+	return ReaderType
+}
+
+// OnPacket은 StreamSubscriber 인터페이스 구현
+func (s *SourceSubscriber) OnPacket(pkt *rtp.Packet) error {
+	return s.peer.writeFromSource(s.streamID, pkt)
+}
+
+// Source는 스트림을 구독할 구독자를 반환합니다
+// 처음 호출한 스트림이 활성 소스가 되며, 이후 전환은 SwitchSource로 요청합니다
+func (p *Peer) Source(streamID string) *SourceSubscriber {
+	p.sources.mutex.Lock()
+	if p.sources.active == "" {
+		p.sources.active = streamID
+	}
+	p.sources.mutex.Unlock()
+
+	return &SourceSubscriber{peer: p, streamID: streamID}
+}
+
+// SwitchSource는 다음 키프레임부터 다른 스트림을 보내도록 전환을 예약합니다
+// 두 스트림은 같은 코덱이어야 하며, 전환이 완료되면 onSwitch(이전, 새 스트림)가 호출됩니다
+func (p *Peer) SwitchSource(streamID, codec string, onSwitch func(from, to string)) {
+	p.sources.mutex.Lock()
+	defer p.sources.mutex.Unlock()
+
+	if streamID == p.sources.active {
+		p.sources.pending = ""
+		return
+	}
+
+	p.sources.pending = streamID
+	p.sources.codec = codec
+	p.sources.onSwitch = onSwitch
+
+	p.logger.Info("Source switch scheduled",
+		zap.String("from", p.sources.active),
+		zap.String("to", streamID),
+	)
+}
+
+// ActiveSource는 현재 전송 중인 스트림 ID와 전환 대기 중인 스트림 ID를 반환합니다
+func (p *Peer) ActiveSource() (active, pending string) {
+	p.sources.mutex.Lock()
+	defer p.sources.mutex.Unlock()
+	return p.sources.active, p.sources.pending
+}
+
+// writeFromSource는 활성 소스의 패킷을 시퀀스 번호/타임스탬프를 이어 붙여 전송합니다
+func (p *Peer) writeFromSource(streamID string, pkt *rtp.Packet) error {
+	s := &p.sources
+
+	s.mutex.Lock()
+	var switched func(from, to string)
+	from := s.active
+
+	if streamID == s.pending && isRandomAccess(s.codec, pkt.Payload) {
+		// 새 소스 시작: 이전 소스의 마지막 패킷 다음으로 이어 붙임
+		s.active = streamID
+		s.pending = ""
+		if s.written {
+			s.seqOffset = s.lastSeq + 1 - pkt.SequenceNumber
+			s.tsOffset = s.lastTS + frameGap - pkt.Timestamp
+		}
+		switched = s.onSwitch
+		s.onSwitch = nil
+	}
+
+	if streamID != s.active {
+		s.mutex.Unlock()
+		return nil
+	}
+
+	out := *pkt
+	out.SequenceNumber += s.seqOffset
+	out.Timestamp += s.tsOffset
+	s.lastSeq = out.SequenceNumber
+	s.lastTS = out.Timestamp
+	s.written = true
+	s.mutex.Unlock()
+
+	if switched != nil {
+		p.logger.Info("Source switched",
+			zap.String("from", from),
+			zap.String("to", streamID),
+		)
+		go switched(from, streamID)
+	}
+
+	return p.OnPacket(&out)
+}
+
+// isRandomAccess는 RTP 페이로드가 디코딩을 시작할 수 있는 지점(파라미터 셋 또는 키프레임 시작)인지 확인합니다
+func isRandomAccess(codec string, payload []byte) bool {
+	if len(payload) < 2 {
+		return false
+	}
+
+	if codec == "H265" {
+		nalType := (payload[0] >> 1) & 0x3f
+		switch nalType {
+		case 48: // AP: 첫 번째 NAL 기준
+			if len(payload) < 5 {
+				return false
+			}
+			nalType = (payload[4] >> 1) & 0x3f
+		case 49: // FU: 시작 조각만
+			if len(payload) < 3 || payload[2]&0x80 == 0 {
+				return false
+			}
+			nalType = payload[2] & 0x3f
+		}
+		return nalType >= 16 && nalType <= 21 || nalType >= 32 && nalType <= 34 // IRAP, VPS/SPS/PPS
+	}
+
+	nalType := payload[0] & 0x1f
+	switch nalType {
+	case 24: // STAP-A: 첫 번째 NAL 기준
+		if len(payload) < 4 {
+			return false
+		}
+		nalType = payload[3] & 0x1f
+	case 28: // FU-A: 시작 조각만
+		if payload[1]&0x80 == 0 {
+			return false
+		}
+		nalType = payload[1] & 0x1f
+	}
+	return nalType == 5 || nalType == 7 || nalType == 8 // IDR, SPS, PPS
+}
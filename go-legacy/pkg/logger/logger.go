@@ -24,6 +24,8 @@ var (
 	ctx context.Context
 	// cancel은 로거 취소 함수
 	cancel context.CancelFunc
+	// level은 모든 코어가 공유하는 로그 레벨 (SetLevel로 실행 중 변경)
+	level = zap.NewAtomicLevel()
 )
 
 // LogConfig는 로거 설정
@@ -60,10 +62,11 @@ func InitLogger(cfg LogConfig) error {
 // initLoggerCore는 로거 코어를 초기화합니다
 func initLoggerCore(cfg LogConfig) error {
 	// 로그 레벨 파싱
-	level, err := zapcore.ParseLevel(cfg.Level)
+	parsed, err := zapcore.ParseLevel(cfg.Level)
 	if err != nil {
-		level = zapcore.InfoLevel
+		parsed = zapcore.InfoLevel
 	}
+	level.SetLevel(parsed)
 
 	// 인코더 설정
 	encoderConfig := zap.NewProductionEncoderConfig()
@@ -196,6 +199,20 @@ func dailyRotation() {
 	}
 }
 
+// SetLevel은 로그 레벨을 변경합니다 (이미 생성된 하위 로거에도 즉시 적용)
+func SetLevel(levelName string) error {
+	parsed, err := zapcore.ParseLevel(levelName)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", levelName, err)
+	}
+
+	level.SetLevel(parsed)
+	if logConfig != nil {
+		logConfig.Level = levelName
+	}
+	return nil
+}
+
 // Close는 로거를 종료하고 리소스를 정리합니다
 func Close() {
 	if cancel != nil {
@@ -0,0 +1,74 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// e2e 테스트는 localhost:8080 서버의 stream1~stream3을 시청합니다
+// 실제 카메라 없이 실행할 수 있도록 없는 스트림은 내장 테스트 패턴(testsrc://)으로 만들고 끝나면 삭제합니다
+
+const streamsAPI = "http://localhost:8080/api/v1/streams"
+
+// testPatternSource는 e2e 스트림의 소스 (내장 H.264 테스트 패턴)
+const testPatternSource = "testsrc://bars?size=640x480&fps=25"
+
+var testStreams = []string{"stream1", "stream2", "stream3"}
+
+func TestMain(m *testing.M) {
+	created := ensureTestStreams()
+	code := m.Run()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	for _, id := range created {
+		req, _ := http.NewRequest(http.MethodDelete, streamsAPI+"/"+id, nil)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	os.Exit(code)
+}
+
+// ensureTestStreams는 없는 테스트 스트림을 생성하고 생성한 스트림 ID를 반환합니다
+// 서버에 연결할 수 없으면 아무것도 하지 않습니다 (각 테스트에서 연결 실패로 보고)
+func ensureTestStreams() []string {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	var created []string
+	for _, id := range testStreams {
+		resp, err := client.Get(streamsAPI + "/" + id)
+		if err != nil {
+			return created
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			continue
+		}
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"id":               id,
+			"name":             id,
+			"source":           testPatternSource,
+			"source_on_demand": false,
+		})
+		resp, err = client.Post(streamsAPI, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return created
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusCreated {
+			created = append(created, id)
+		}
+	}
+
+	// 소스가 첫 키프레임을 보낼 때까지 대기
+	if len(created) > 0 {
+		time.Sleep(2 * time.Second)
+	}
+	return created
+}
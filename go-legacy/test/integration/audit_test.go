@@ -0,0 +1,239 @@
+package integration
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// auditUser는 CSV 이스케이프가 필요한 API 키 이름 (쉼표, 따옴표, 줄바꿈)
+const auditUser = "ops, \"night\"\nshift"
+
+// auditList는 GET /api/v1/audit 응답
+type auditList struct {
+	Entries []database.AuditEntry `json:"entries"`
+	Count   int                   `json:"count"`
+	Total   int                   `json:"total"`
+}
+
+// TestAudit은 감사 로그의 클라이언트 주소(신뢰 프록시), 조회 필터, CSV 내보내기를 테스트합니다
+func TestAudit(t *testing.T) {
+	s := startTestServer(t, testServerOptions{
+		Server: `  trusted_proxies: ["127.0.0.1"]`,
+		Extra: `audit:
+  enabled: true
+auth:
+  enabled: true
+  api_keys:
+    - name: admin
+      key: admin-key
+      role: admin
+    - name: "ops, \"night\"\nshift"
+      key: ops-key
+      role: admin
+    - name: operator
+      key: operator-key
+      role: operator
+`,
+	})
+
+	camera := mockRTSPCamera(t)
+	admin, ops := bearer("admin-key"), bearer("ops-key")
+	forwarded := func(header http.Header, ip string) http.Header {
+		h := http.Header{}
+		for key, values := range header {
+			h[key] = values
+		}
+		h.Set("X-Forwarded-For", ip)
+		return h
+	}
+
+	// 프록시(127.0.0.1) 뒤에서 cam1 생성
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: "cam1", Name: "cam1", Source: camera.URL("cam1"),
+	}, forwarded(ops, "203.0.113.7"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	// 관리자가 cam2 생성 후 삭제
+	resp, body = s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: "cam2", Name: "cam2", Source: camera.URL("cam2"), SourceOnDemand: true,
+	}, admin)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	resp, body = s.request(t, http.MethodDelete, "/api/v1/streams/cam2", nil, admin)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	// WebSocket 시그널링으로 cam1 시청 시작 (프록시 뒤)
+	watchWebRTC(t, s, "cam1", forwarded(nil, "198.51.100.9"))
+
+	query := func(t *testing.T, params url.Values) auditList {
+		t.Helper()
+		resp, body := s.request(t, http.MethodGet, "/api/v1/audit?"+params.Encode(), nil, admin)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var list auditList
+		require.NoError(t, json.Unmarshal(body, &list))
+		return list
+	}
+
+	// 감사 로그는 비동기로 기록됨
+	require.Eventually(t, func() bool {
+		return query(t, url.Values{"action": {"view.start"}}).Total == 1
+	}, 10*time.Second, 200*time.Millisecond, "view.start not recorded")
+
+	// 시청 종료(view.end)는 피어 종료 시점에 기록되므로 아래 개수 비교는 stream.* 항목만 사용
+	all := query(t, url.Values{"action": {"stream"}})
+	require.Equal(t, 3, all.Total)
+
+	t.Run("RemoteAddr", func(t *testing.T) {
+		list := query(t, url.Values{"user": {auditUser}})
+		require.Equal(t, 1, list.Total)
+		entry := list.Entries[0]
+		assert.Equal(t, "stream.create", entry.Action)
+		assert.Equal(t, "admin", entry.Role)
+		assert.Equal(t, "203.0.113.7", entry.RemoteAddr)
+
+		list = query(t, url.Values{"action": {"view.start"}})
+		require.Equal(t, 1, list.Total)
+		entry = list.Entries[0]
+		assert.Equal(t, "cam1", entry.StreamID)
+		assert.Equal(t, "webrtc", entry.Protocol)
+		assert.Equal(t, "admin", entry.User)
+		assert.Equal(t, "198.51.100.9", entry.RemoteAddr)
+
+		list = query(t, url.Values{"stream": {"cam2"}, "action": {"stream.create"}})
+		require.Equal(t, 1, list.Total)
+		assert.Equal(t, "127.0.0.1", list.Entries[0].RemoteAddr)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		list := query(t, url.Values{"stream": {"cam2"}})
+		require.Equal(t, 2, list.Total)
+		assert.Equal(t, "stream.delete", list.Entries[0].Action) // 최신 항목 먼저
+		assert.Equal(t, "stream.create", list.Entries[1].Action)
+
+		list = query(t, url.Values{"user": {"admin"}, "action": {"stream"}})
+		assert.Equal(t, 2, list.Total)
+
+		// action은 정확히 일치하거나 "."으로 구분된 접두어 ("view" → view.start, view.end)
+		list = query(t, url.Values{"action": {"stream.create"}})
+		assert.Equal(t, 2, list.Total)
+		list = query(t, url.Values{"action": {"stream.c"}})
+		assert.Equal(t, 0, list.Total)
+
+		// 시간 범위 (end는 제외)
+		first := all.Entries[len(all.Entries)-1].Time
+		list = query(t, url.Values{"end": {first.Add(-time.Second).Format(time.RFC3339)}})
+		assert.Equal(t, 0, list.Total)
+		list = query(t, url.Values{"start": {time.Now().Add(time.Minute).Format(time.RFC3339)}})
+		assert.Equal(t, 0, list.Total)
+		list = query(t, url.Values{"action": {"stream"}, "start": {first.Add(-time.Second).Format(time.RFC3339)}})
+		assert.Equal(t, all.Total, list.Total)
+
+		// 페이지
+		list = query(t, url.Values{"action": {"stream"}, "limit": {"1"}, "offset": {"1"}})
+		assert.Equal(t, 1, list.Count)
+		assert.Equal(t, all.Total, list.Total)
+		assert.Equal(t, all.Entries[1].ID, list.Entries[0].ID)
+
+		for _, bad := range []string{"start=yesterday", "limit=-1", "offset=x"} {
+			resp, body := s.request(t, http.MethodGet, "/api/v1/audit?"+bad, nil, admin)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, bad+": "+string(body))
+		}
+
+		resp, _ := s.request(t, http.MethodGet, "/api/v1/audit", nil, bearer("operator-key"))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodGet, "/api/v1/audit?format=csv&action=stream", nil, admin)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "attachment; filename=\"audit_")
+
+		records, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"id", "time", "user", "role", "action", "stream_id", "protocol",
+			"remote_addr", "session_id", "bytes", "duration_ms", "details",
+		}, records[0])
+
+		// limit 없으면 전체
+		assert.Len(t, records, all.Total+1)
+
+		// 쉼표, 따옴표, 줄바꿈이 있는 값은 따옴표로 감싸고 따옴표는 두 번 씀
+		assert.Contains(t, string(body), "\"ops, \"\"night\"\"\nshift\"")
+
+		var found bool
+		for _, record := range records[1:] {
+			if record[2] != auditUser {
+				continue
+			}
+			found = true
+			assert.Equal(t, "stream.create", record[4])
+			assert.Equal(t, "cam1", record[5])
+			assert.Equal(t, "203.0.113.7", record[7])
+			assert.Equal(t, "source="+camera.URL("cam1"), record[11])
+		}
+		assert.True(t, found, "cam1 create entry missing from CSV")
+
+		// 필터와 limit도 적용
+		resp, body = s.request(t, http.MethodGet, "/api/v1/audit?format=csv&stream=cam2&limit=1", nil, admin)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		records, err = csv.NewReader(strings.NewReader(string(body))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "stream.delete", records[1][4])
+	})
+}
+
+// watchWebRTC는 admin 키로 WebSocket 시그널링에 접속해 스트림의 WebRTC Answer를 받을 때까지 진행합니다
+func watchWebRTC(t *testing.T, s *testServer, streamID string, header http.Header) {
+	t.Helper()
+
+	wsURL := strings.Replace(s.URL, "http://", "ws://", 1) + "/ws?api_key=admin-key"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	require.NoError(t, err)
+	defer pc.Close()
+
+	_, err = pc.AddTransceiverFromKind(webrtc.RTPCodecTypeVideo, webrtc.RTPTransceiverInit{
+		Direction: webrtc.RTPTransceiverDirectionRecvonly,
+	})
+	require.NoError(t, err)
+
+	offer, err := pc.CreateOffer(nil)
+	require.NoError(t, err)
+	require.NoError(t, pc.SetLocalDescription(offer))
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{
+		"type":     "offer",
+		"streamId": streamID,
+		"payload":  map[string]string{"sdp": offer.SDP, "streamId": streamID},
+	}))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for {
+		var msg struct {
+			Type    string          `json:"type"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.NotEqual(t, "error", msg.Type, string(msg.Payload))
+		if msg.Type == "answer" {
+			return
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// TestConfigReload는 설정 파일 다시 로드 API를 테스트합니다 (파일을 수정하지 않으므로 변경 없음)
+func TestConfigReload(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/config/reload", nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var result struct {
+		Changed        []string `json:"changed"`
+		StreamsAdded   []string `json:"streams_added"`
+		StreamsRemoved []string `json:"streams_removed"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Empty(t, result.Changed)
+	assert.Empty(t, result.StreamsAdded)
+	assert.Empty(t, result.StreamsRemoved)
+
+	resp, _ = s.request(t, http.MethodGet, "/api/v1/config/reload", nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// configPaths는 testsrc 온디맨드 경로를 정의하는 paths 설정을 만듭니다
+func configPaths(ids ...string) string {
+	var b strings.Builder
+	b.WriteString("paths:\n")
+	for _, id := range ids {
+		fmt.Fprintf(&b, "  %s:\n    source: testsrc://bars?size=320x240&fps=10\n    sourceOnDemand: true\n", id)
+	}
+	return b.String()
+}
+
+// TestConfigPathsSync는 config.yaml에서 삭제된 경로가 다시 로드와 재시작에서 똑같이 삭제되고
+// API로 생성한 스트림은 유지되는지 테스트합니다
+func TestConfigPathsSync(t *testing.T) {
+	dir := t.TempDir()
+	s := startTestServerIn(t, dir, testServerOptions{Extra: configPaths("cfg-a", "cfg-b", "cfg-c")})
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: "api-a", Name: "API stream", Source: "testsrc://bars", SourceOnDemand: true,
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	// 출처는 API로 지정할 수 없음
+	resp, body = s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: "api-b", Name: "api-b", Source: "testsrc://bars", SourceOnDemand: true, Origin: "config",
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	origin := func(t *testing.T, s *testServer, id string) (string, bool) {
+		t.Helper()
+		resp, body := s.request(t, http.MethodGet, "/api/v1/streams/"+id, nil, nil)
+		if resp.StatusCode == http.StatusNotFound {
+			return "", false
+		}
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var stream struct {
+			Origin string `json:"origin"`
+		}
+		require.NoError(t, json.Unmarshal(body, &stream))
+		return stream.Origin, true
+	}
+
+	for id, want := range map[string]string{"cfg-a": "config", "cfg-b": "config", "cfg-c": "config", "api-a": "", "api-b": ""} {
+		got, ok := origin(t, s, id)
+		require.True(t, ok, id)
+		assert.Equal(t, want, got, id)
+	}
+
+	// 설정 파일로 정의된 스트림을 API로 수정해도 출처는 유지
+	resp, body = s.request(t, http.MethodPut, "/api/v1/streams/cfg-a", database.Stream{
+		Name: "Lobby", Source: "testsrc://bars?size=320x240&fps=10", SourceOnDemand: true,
+	}, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	t.Run("ReloadRemove", func(t *testing.T) {
+		configPath := filepath.Join(dir, "config.yaml")
+		data, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		updated := strings.Replace(string(data), configPaths("cfg-a", "cfg-b", "cfg-c"), configPaths("cfg-a", "cfg-c"), 1)
+		require.NotEqual(t, string(data), updated)
+		require.NoError(t, os.WriteFile(configPath, []byte(updated), 0o600))
+
+		// 파일 감시가 먼저 적용했을 수 있으므로 결과 대신 상태로 확인
+		resp, body := s.request(t, http.MethodPost, "/api/v1/config/reload", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		_, exists := origin(t, s, "cfg-b")
+		assert.False(t, exists, "cfg-b should be deleted by reload")
+		for _, id := range []string{"cfg-a", "cfg-c", "api-a", "api-b"} {
+			_, exists := origin(t, s, id)
+			assert.True(t, exists, id)
+		}
+	})
+
+	// 재시작: 서버는 이 테스트 범위에서 실행해야 하므로 하위 테스트를 사용하지 않음
+	require.Equal(t, 0, s.stop(t, syscall.SIGTERM))
+
+	// 재시작 전에 cfg-c를 설정에서 삭제 (다시 로드 없이)
+	s = startTestServerIn(t, dir, testServerOptions{Extra: configPaths("cfg-a")})
+
+	_, exists := origin(t, s, "cfg-c")
+	assert.False(t, exists, "cfg-c should be deleted on restart like on reload")
+	_, exists = origin(t, s, "cfg-b")
+	assert.False(t, exists)
+
+	// 남은 설정 경로는 DB 데이터(API 수정) 유지
+	resp, body = s.request(t, http.MethodGet, "/api/v1/streams/cfg-a", nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Contains(t, string(body), `"name":"Lobby"`)
+	assert.Contains(t, string(body), `"origin":"config"`)
+
+	// API 스트림은 paths에 없어도 유지
+	for _, id := range []string{"api-a", "api-b"} {
+		got, exists := origin(t, s, id)
+		assert.True(t, exists, id)
+		assert.Empty(t, got, id)
+	}
+
+	// paths 없이 재시작하면 남은 설정 경로도 삭제
+	require.Equal(t, 0, s.stop(t, syscall.SIGTERM))
+	s = startTestServerIn(t, dir, testServerOptions{})
+
+	_, exists = origin(t, s, "cfg-a")
+	assert.False(t, exists, "cfg-a should be deleted when paths is empty")
+	_, exists = origin(t, s, "api-a")
+	assert.True(t, exists)
+}
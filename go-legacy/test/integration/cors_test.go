@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowedOriginsWarning은 허용 Origin이 제한될 때 시작 로그에 남는 경고입니다
+const allowedOriginsWarning = "CORS/WebSocket only allow server.allowed_origins"
+
+// TestAllowedOrigins는 server.allowed_origins에 따른 CORS 허용과 시작 경고를 테스트합니다
+func TestAllowedOrigins(t *testing.T) {
+	dashboard := http.Header{"Origin": []string{"http://dashboard.example"}}
+
+	serverLog := func(t *testing.T, s *testServer) string {
+		log, err := os.ReadFile(filepath.Join(s.Dir, "server.log"))
+		require.NoError(t, err)
+		return string(log)
+	}
+
+	t.Run("Restricted", func(t *testing.T) {
+		s := startTestServer(t, testServerOptions{
+			Server: "  allowed_origins: [\"http://localhost:8107\"]",
+		})
+
+		resp, _ := s.request(t, http.MethodGet, "/api/v1/streams", nil, dashboard)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+		resp, _ = s.request(t, http.MethodGet, "/api/v1/streams", nil, http.Header{"Origin": []string{"http://localhost:8107"}})
+		assert.Equal(t, "http://localhost:8107", resp.Header.Get("Access-Control-Allow-Origin"))
+
+		assert.Contains(t, serverLog(t, s), allowedOriginsWarning)
+	})
+
+	t.Run("AllowAll", func(t *testing.T) {
+		s := startTestServer(t, testServerOptions{
+			Server: "  allowed_origins: [\"*\"]",
+		})
+
+		resp, _ := s.request(t, http.MethodGet, "/api/v1/streams", nil, dashboard)
+		assert.Equal(t, "http://dashboard.example", resp.Header.Get("Access-Control-Allow-Origin"))
+
+		assert.NotContains(t, serverLog(t, s), allowedOriginsWarning)
+	})
+}
@@ -0,0 +1,168 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// hikvisionMotionAlert는 Hikvision 카메라가 보내는 모션 알람 본문입니다
+const hikvisionMotionAlert = `<?xml version="1.0" encoding="UTF-8"?>
+<EventNotificationAlert version="2.0" xmlns="http://www.hikvision.com/ver20/XMLSchema">
+	<ipAddress>192.168.1.64</ipAddress>
+	<channelID>1</channelID>
+	<dateTime>2025-11-17T14:02:05+09:00</dateTime>
+	<eventType>VMD</eventType>
+	<eventState>active</eventState>
+	<eventDescription>Motion alarm</eventDescription>
+</EventNotificationAlert>`
+
+// postAlarm은 카메라 HTTP 알람을 흉내 내서 이벤트를 보냅니다
+func postAlarm(t *testing.T, s *testServer, streamID, contentType, body string) (*http.Response, map[string]interface{}) {
+	t.Helper()
+
+	resp, data := s.request(t, http.MethodPost, "/api/v1/streams/"+streamID+"/events", []byte(body),
+		http.Header{"Content-Type": {contentType}})
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &result), string(data))
+	return resp, result
+}
+
+// listEvents는 스트림의 이벤트 목록을 조회합니다
+func listEvents(t *testing.T, s *testServer, streamID string, query url.Values) (events []map[string]interface{}, total int) {
+	t.Helper()
+
+	resp, body := s.request(t, http.MethodGet, "/api/v1/streams/"+streamID+"/events?"+query.Encode(), nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var result struct {
+		Events []map[string]interface{} `json:"events"`
+		Total  int                      `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	return result.Events, result.Total
+}
+
+// TestEventIngestion은 시뮬레이션한 카메라 HTTP 알람의 정규화, 중복 제거, 조회를 테스트합니다
+func TestEventIngestion(t *testing.T) {
+	s := startTestServer(t, testServerOptions{Extra: "events:\n  enabled: true\n"})
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID:             "test-events",
+		Name:           "Test Events",
+		Source:         "rtsp://test.com/events",
+		SourceOnDemand: true,
+		RTSPTransport:  "tcp",
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	resp, result := postAlarm(t, s, "test-events", "application/json",
+		`{"type":"motion","active":true,"source":"simulated","details":"zone=1"}`)
+
+	t.Run("JSONAlarm", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "%v", result)
+		assert.Equal(t, "test-events", result["stream_id"])
+		assert.Equal(t, "motion", result["type"])
+		assert.Equal(t, true, result["active"])
+		assert.Equal(t, "simulated", result["source"])
+		assert.Equal(t, "zone=1", result["details"])
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		resp, result := postAlarm(t, s, "test-events", "application/json",
+			`{"type":"motion","active":true,"source":"simulated"}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode, "%v", result)
+		assert.Equal(t, false, result["stored"])
+	})
+
+	t.Run("Inactive", func(t *testing.T) {
+		resp, result := postAlarm(t, s, "test-events", "application/json",
+			`{"type":"motion","state":"inactive","source":"simulated"}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "%v", result)
+		assert.Equal(t, false, result["active"])
+	})
+
+	t.Run("HikvisionXML", func(t *testing.T) {
+		resp, result := postAlarm(t, s, "test-events", "application/xml", hikvisionMotionAlert)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "%v", result)
+		assert.Equal(t, "motion", result["type"])
+		assert.Equal(t, "VMD", result["topic"])
+		assert.Equal(t, "http", result["source"])
+		assert.Contains(t, result["details"], "channel=1")
+	})
+
+	t.Run("VendorLineCrossing", func(t *testing.T) {
+		resp, result := postAlarm(t, s, "test-events", "application/json",
+			`{"type":"CrossLineDetection","source":"simulated"}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "%v", result)
+		assert.Equal(t, "line_crossing", result["type"])
+	})
+
+	t.Run("List", func(t *testing.T) {
+		events, total := listEvents(t, s, "test-events", url.Values{"type": {"motion"}})
+
+		// 최신순: Hikvision 발생, 해제, JSON 발생 (중복 알림은 저장되지 않음)
+		require.Equal(t, 3, total)
+		require.Len(t, events, 3)
+		assert.Equal(t, "http", events[0]["source"])
+		assert.Equal(t, false, events[1]["active"])
+		assert.Equal(t, "simulated", events[2]["source"])
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		resp, result := postAlarm(t, s, "test-events", "text/plain", "motion detected")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%v", result)
+
+		resp, result = postAlarm(t, s, "test-events", "application/json", `{"active":true}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%v", result)
+	})
+
+	t.Run("UnknownStream", func(t *testing.T) {
+		resp, result := postAlarm(t, s, "test-events-missing", "application/json", `{"type":"motion"}`)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "%v", result)
+	})
+}
+
+// TestONVIFEvents는 mock ONVIF 장치의 PullPoint 구독으로 모션 이벤트가 수집되는지 테스트합니다
+// 구독 대상은 30초마다 갱신되므로 최대 40초 동안 기다립니다
+func TestONVIFEvents(t *testing.T) {
+	s := startTestServer(t, testServerOptions{Extra: `onvif:
+  enabled: true
+events:
+  enabled: true
+  onvif: true
+`})
+
+	device := mockONVIFDevice(t)
+	defer device.Close()
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/onvif/import", map[string]interface{}{
+		"username": mockONVIFUser,
+		"password": mockONVIFPassword,
+		"devices": []map[string]interface{}{
+			{"address": device.URL, "name": "test-onvif-events"},
+		},
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var events []map[string]interface{}
+	require.Eventually(t, func() bool {
+		events, _ = listEvents(t, s, "test-onvif-events", url.Values{})
+		return len(events) > 0
+	}, 40*time.Second, time.Second, "No ONVIF event received (subscriptions: %d)", device.Subscriptions())
+
+	event := events[0]
+	assert.Equal(t, "motion", event["type"])
+	assert.Equal(t, true, event["active"])
+	assert.Equal(t, "onvif", event["source"])
+	assert.Equal(t, "RuleEngine/CellMotionDetector/Motion", event["topic"])
+	assert.Contains(t, event["details"], "Rule=MyMotionDetectorRule")
+	assert.GreaterOrEqual(t, device.Subscriptions(), 1)
+}
@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// getFailoverStream은 스트림을 조회합니다
+func getFailoverStream(t *testing.T, s *testServer, id string) StreamResponse {
+	t.Helper()
+
+	resp, body := s.request(t, http.MethodGet, "/api/v1/streams/"+id, nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var stream StreamResponse
+	require.NoError(t, json.Unmarshal(body, &stream))
+	return stream
+}
+
+// activeSource는 runtime_info.active_source를 반환합니다 (없으면 nil)
+func activeSource(t *testing.T, s *testServer, id string) map[string]interface{} {
+	stream := getFailoverStream(t, s, id)
+	if stream.RuntimeInfo == nil {
+		return nil
+	}
+	info, _ := stream.RuntimeInfo["active_source"].(map[string]interface{})
+	return info
+}
+
+// waitActiveSource는 활성 소스가 index로 연결될 때까지 기다립니다
+func waitActiveSource(t *testing.T, s *testServer, id string, index int, timeout time.Duration) map[string]interface{} {
+	deadline := time.Now().Add(timeout)
+	var info map[string]interface{}
+	for time.Now().Before(deadline) {
+		info = activeSource(t, s, id)
+		if info != nil && info["index"] == float64(index) && info["connected"] == true {
+			return info
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	require.Failf(t, "active source not switched", "want index %d, got %v", index, info)
+	return nil
+}
+
+// TestSourceFailover는 주 소스 장애 시 예비 소스 전환과 주 소스 복귀를 테스트합니다
+func TestSourceFailover(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	// 주 카메라는 enabled 전까지 503으로 응답
+	var enabled atomic.Bool
+	camera := mockMJPEGCamera(t)
+	defer func() {
+		camera.CloseClientConnections()
+		camera.Close()
+	}()
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !enabled.Load() {
+			http.Error(w, "camera offline", http.StatusServiceUnavailable)
+			return
+		}
+		camera.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer func() {
+		primary.CloseClientConnections()
+		primary.Close()
+	}()
+
+	backup := camera.URL + "/backup"
+	backupWithAuth := strings.Replace(backup, "http://", "http://viewer:secret@", 1)
+
+	const id = "test-failover"
+
+	t.Run("Validation", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: "test-failover-bad", Name: "x", Source: primary.URL, SourceOnDemand: true,
+			BackupSources: []string{"ftp://127.0.0.1/x"},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: "test-failover-bad", Name: "x", Source: primary.URL, SourceOnDemand: true,
+			BackupSources: []string{primary.URL},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: "test-failover-bad", Name: "x", Source: primary.URL, SourceOnDemand: true, FailbackAfter: -1,
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+	})
+
+	t.Run("FailoverToBackup", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: id, Name: id, Source: primary.URL, BackupSources: []string{backupWithAuth}, FailbackAfter: 2,
+		}, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+		var created StreamResponse
+		require.NoError(t, json.Unmarshal(body, &created))
+		assert.Equal(t, []string{strings.Replace(backup, "http://", "http://viewer:***@", 1)}, created.BackupSources)
+
+		info := waitActiveSource(t, s, id, 1, 20*time.Second)
+		assert.NotContains(t, info["url"], "secret")
+
+		stream := getFailoverStream(t, s, id)
+		assert.Equal(t, 2, stream.FailbackAfter)
+		require.Len(t, stream.BackupSources, 1)
+		assert.NotContains(t, stream.BackupSources[0], "secret")
+	})
+
+	t.Run("FailbackToPrimary", func(t *testing.T) {
+		enabled.Store(true)
+
+		info := waitActiveSource(t, s, id, 0, 30*time.Second)
+		assert.Equal(t, float64(1), info["failbacks"])
+	})
+
+	t.Run("UpdateKeepsBackups", func(t *testing.T) {
+		// 예비 소스를 생략하면 유지, 마스킹된 비밀번호는 기존 값으로 복원
+		resp, body := s.request(t, http.MethodPut, "/api/v1/streams/"+id, database.Stream{
+			Name: id, Source: primary.URL, SourceOnDemand: true, RTSPTransport: "tcp", FailbackAfter: 2,
+		}, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		stream := getFailoverStream(t, s, id)
+		require.Len(t, stream.BackupSources, 1)
+
+		resp, body = s.request(t, http.MethodGet, "/api/v1/streams/"+id+"?reveal=true", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Contains(t, string(body), backupWithAuth)
+
+		resp, body = s.request(t, http.MethodPut, "/api/v1/streams/"+id, database.Stream{
+			Name: id, Source: primary.URL, SourceOnDemand: true, RTSPTransport: "tcp", BackupSources: stream.BackupSources,
+		}, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		resp, body = s.request(t, http.MethodGet, "/api/v1/streams/"+id+"?reveal=true", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Contains(t, string(body), backupWithAuth)
+
+		// 빈 목록은 예비 소스 제거
+		resp, body = s.request(t, http.MethodPut, "/api/v1/streams/"+id, map[string]interface{}{
+			"name": id, "source": primary.URL, "source_on_demand": true, "backup_sources": []string{},
+		}, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Empty(t, getFailoverStream(t, s, id).BackupSources)
+	})
+}
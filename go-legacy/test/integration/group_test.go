@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// TestStreamGroups는 스트림 그룹 CRUD와 HLS master 플레이리스트를 테스트합니다
+func TestStreamGroups(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	const groupID = "test-group"
+
+	for _, id := range []string{"test-group-main", "test-group-sub"} {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID:             id,
+			Name:           id,
+			Source:         "rtsp://test.com/" + id,
+			SourceOnDemand: true,
+			RTSPTransport:  "tcp",
+		}, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	}
+
+	group := database.StreamGroup{
+		ID:   groupID,
+		Name: "Test Group",
+		Renditions: []database.StreamRendition{
+			{Name: "main", StreamID: "test-group-main", Bandwidth: 8000000, Width: 3840, Height: 2160},
+			{Name: "sub", StreamID: "test-group-sub", Bandwidth: 1000000, Width: 720, Height: 480},
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/groups", group, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/groups", group, nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, string(body))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, invalid := range []database.StreamGroup{
+			{ID: "test-group-empty"},
+			{ID: "test-group-missing", Renditions: []database.StreamRendition{
+				{Name: "main", StreamID: "test-group-missing", Bandwidth: 1000},
+			}},
+			{ID: "test-group-dup", Renditions: []database.StreamRendition{
+				{Name: "main", StreamID: "test-group-main", Bandwidth: 1000},
+				{Name: "main", StreamID: "test-group-sub", Bandwidth: 1000},
+			}},
+			{ID: "test-group-bw", Renditions: []database.StreamRendition{
+				{Name: "main", StreamID: "test-group-main"},
+			}},
+		} {
+			resp, body := s.request(t, http.MethodPost, "/api/v1/groups", invalid, nil)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodGet, "/api/v1/groups/"+groupID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var got database.StreamGroup
+		require.NoError(t, json.Unmarshal(body, &got))
+		require.Len(t, got.Renditions, 2)
+		assert.Equal(t, "main", got.Renditions[0].Name)
+		assert.Equal(t, "sub", got.Renditions[1].Name)
+
+		resp, _ = s.request(t, http.MethodGet, "/api/v1/groups/test-group-none", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("MasterPlaylist", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodGet, "/hls/groups/"+groupID+"/master.m3u8", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "application/vnd.apple.mpegurl", resp.Header.Get("Content-Type"))
+
+		playlist := string(body)
+		assert.True(t, strings.HasPrefix(playlist, "#EXTM3U\n#EXT-X-VERSION:9\n"))
+		// variant는 스트림의 미디어 플레이리스트를 가리킴 (index.m3u8은 multivariant 플레이리스트)
+		assert.Contains(t, playlist, "#EXT-X-STREAM-INF:BANDWIDTH=8000000,RESOLUTION=3840x2160\n../../test-group-main/main_stream.m3u8")
+		assert.Contains(t, playlist, "#EXT-X-STREAM-INF:BANDWIDTH=1000000,RESOLUTION=720x480\n../../test-group-sub/main_stream.m3u8")
+		assert.NotContains(t, playlist, "index.m3u8")
+		assert.Less(t, strings.Index(playlist, "test-group-main"), strings.Index(playlist, "test-group-sub"), "default rendition must be first")
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		update := database.StreamGroup{
+			Name: "Test Group Sub First",
+			Renditions: []database.StreamRendition{
+				{Name: "sub", StreamID: "test-group-sub", Bandwidth: 1000000},
+				{Name: "main", StreamID: "test-group-main", Bandwidth: 8000000},
+			},
+		}
+		resp, body := s.request(t, http.MethodPut, "/api/v1/groups/"+groupID, update, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodGet, "/api/v1/groups/"+groupID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var got database.StreamGroup
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, "Test Group Sub First", got.Name)
+		require.Len(t, got.Renditions, 2)
+		assert.Equal(t, "sub", got.Renditions[0].Name)
+	})
+
+	t.Run("StreamDeleteRemovesRendition", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodDelete, "/api/v1/streams/test-group-main", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodGet, "/api/v1/groups/"+groupID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var got database.StreamGroup
+		require.NoError(t, json.Unmarshal(body, &got))
+		require.Len(t, got.Renditions, 1)
+		assert.Equal(t, "test-group-sub", got.Renditions[0].StreamID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodDelete, "/api/v1/groups/"+groupID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		resp, _ = s.request(t, http.MethodDelete, "/api/v1/groups/"+groupID, nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readHookEnv는 훅 명령이 기록한 환경 변수(env 출력) 중 MTX_, RTSP_PORT 항목을 읽습니다
+func readHookEnv(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	var data []byte
+	require.Eventually(t, func() bool {
+		var err error
+		data, err = os.ReadFile(path)
+		return err == nil && len(data) > 0
+	}, 15*time.Second, 100*time.Millisecond, "hook did not run: %s", filepath.Base(path))
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok && (strings.HasPrefix(key, "MTX_") || key == "RTSP_PORT") {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// TestPathHooks는 경로 훅이 셸 명령으로 실행되어 이벤트 환경 변수를 받는지와
+// 송출하지 않는 runOnDemand 명령이 runOnDemandStartTimeout에 종료되는지 테스트합니다
+func TestPathHooks(t *testing.T) {
+	dir := t.TempDir()
+	hooksDir := filepath.Join(dir, "hooks")
+	require.NoError(t, os.Mkdir(hooksDir, 0o755))
+	hookFile := func(name string) string { return filepath.Join(hooksDir, name) }
+	camera := mockRTSPCamera(t)
+
+	s := startTestServerIn(t, dir, testServerOptions{
+		RTSP: true,
+		Extra: fmt.Sprintf(`paths:
+  cam1:
+    source: %s
+    runOnReady: 'env > %s'
+    runOnNotReady: 'env > %s'
+  hang:
+    runOnDemand: 'env > %s; echo $$ > %s; exec sleep 60'
+    runOnDemandStartTimeout: 2s
+`, camera.URL("cam1"), hookFile("ready.env"), hookFile("notready.env"), hookFile("demand.env"), hookFile("demand.pid")),
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		env := readHookEnv(t, hookFile("ready.env"))
+		assert.Equal(t, "cam1", env["MTX_PATH"])
+		assert.Equal(t, "", env["MTX_QUERY"])
+		assert.Equal(t, "rtspSource", env["MTX_SOURCE_TYPE"])
+		assert.Equal(t, strconv.Itoa(s.RTSPPort), env["RTSP_PORT"])
+
+		// 준비 해제 시 runOnNotReady 1회 실행
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams/cam1/stop", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		env = readHookEnv(t, hookFile("notready.env"))
+		assert.Equal(t, "cam1", env["MTX_PATH"])
+		assert.Equal(t, "rtspSource", env["MTX_SOURCE_TYPE"])
+	})
+
+	t.Run("DemandTimeout", func(t *testing.T) {
+		u, err := base.ParseURL(fmt.Sprintf("rtsp://127.0.0.1:%d/hang?camera=front", s.RTSPPort))
+		require.NoError(t, err)
+
+		c := gortsplib.Client{ReadTimeout: 10 * time.Second}
+		require.NoError(t, c.Start(u.Scheme, u.Host))
+		defer c.Close()
+
+		// 시청 요청으로 명령 시작, 2초 안에 송출하지 않으면 DESCRIBE 실패
+		start := time.Now()
+		_, _, err = c.Describe(u)
+		assert.Error(t, err)
+		assert.InDelta(t, 2*time.Second, time.Since(start), float64(time.Second))
+
+		env := readHookEnv(t, hookFile("demand.env"))
+		assert.Equal(t, "hang", env["MTX_PATH"])
+		assert.Equal(t, "camera=front", env["MTX_QUERY"])
+
+		data, err := os.ReadFile(hookFile("demand.pid"))
+		require.NoError(t, err)
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		require.NoError(t, err)
+
+		// 시간 초과 시 명령 종료
+		require.Eventually(t, func() bool {
+			return syscall.Kill(pid, 0) != nil
+		}, 2*time.Second, 100*time.Millisecond, "runOnDemand command still running after the start timeout")
+	})
+}
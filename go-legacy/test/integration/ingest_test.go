@@ -0,0 +1,271 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/gortmplib"
+	"github.com/bluenviron/gortsplib/v5/pkg/format"
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/mpegts"
+	srt "github.com/datarhei/gosrt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// srtPayloadSize는 SRT 패킷 하나에 담는 MPEG-TS 크기 (TS 패킷 7개)
+const srtPayloadSize = 1316
+
+// ingestPublisher는 테스트 영상을 RTMP/SRT로 송출합니다
+type ingestPublisher struct {
+	closer func()
+	done   chan error
+	cancel context.CancelFunc
+}
+
+// startPublisher는 테스트 영상을 실시간 속도(10fps)로 반복해서 write에 전달합니다
+func startPublisher(t *testing.T, closer func(), write func(pts time.Duration, au [][]byte) error) *ingestPublisher {
+	t.Helper()
+
+	clip := loadCameraClip(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &ingestPublisher{closer: closer, done: make(chan error, 1), cancel: cancel}
+
+	go func() {
+		const frameDuration = 100 * time.Millisecond
+		ticker := time.NewTicker(frameDuration)
+		defer ticker.Stop()
+
+		for frame := 0; ; frame++ {
+			select {
+			case <-ctx.Done():
+				p.done <- nil
+				return
+			case <-ticker.C:
+			}
+
+			if err := write(time.Duration(frame)*frameDuration, clip[frame%len(clip)]); err != nil {
+				p.done <- err
+				return
+			}
+		}
+	}()
+
+	t.Cleanup(p.close)
+	return p
+}
+
+// close는 송출을 중단하고 연결을 닫습니다
+func (p *ingestPublisher) close() {
+	p.cancel()
+	p.closer()
+}
+
+// publishRTMP는 스트림 키로 RTMP 송출을 시작합니다
+func publishRTMP(t *testing.T, port int, key string) (*ingestPublisher, error) {
+	t.Helper()
+
+	u, err := url.Parse(fmt.Sprintf("rtmp://127.0.0.1:%d/live/%s", port, key))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	client := &gortmplib.Client{URL: u, Publish: true}
+	err = client.Initialize(ctx)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	clip := loadCameraClip(t)
+	track := &format.H264{
+		PayloadTyp:        96,
+		SPS:               clip[0][0],
+		PPS:               clip[0][1],
+		PacketizationMode: 1,
+	}
+	w := &gortmplib.Writer{Conn: client, Tracks: []format.Format{track}}
+	if err := w.Initialize(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return startPublisher(t, client.Close, func(pts time.Duration, au [][]byte) error {
+		return w.WriteH264(track, pts, pts, au)
+	}), nil
+}
+
+// publishSRT는 streamid로 SRT(MPEG-TS) 송출을 시작합니다
+// 서버가 연결 요청을 거부하면 오류를 반환합니다
+func publishSRT(t *testing.T, port int, streamID string) (*ingestPublisher, error) {
+	t.Helper()
+
+	config := srt.DefaultConfig()
+	config.StreamId = streamID
+	config.ConnectionTimeout = 10 * time.Second
+
+	conn, err := srt.Dial("srt", fmt.Sprintf("127.0.0.1:%d", port), config)
+	if err != nil {
+		return nil, err
+	}
+
+	// SRT 패킷 단위로 보내도록 버퍼링 (access unit마다 Flush)
+	bw := bufio.NewWriterSize(conn, srtPayloadSize)
+	track := &mpegts.Track{Codec: &mpegts.CodecH264{}}
+	w := &mpegts.Writer{W: bw, Tracks: []*mpegts.Track{track}}
+	if err := w.Initialize(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return startPublisher(t, func() { conn.Close() }, func(pts time.Duration, au [][]byte) error {
+		ts := int64(pts) * 90000 / int64(time.Second)
+		if err := w.WriteH264(track, ts, ts, au); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}), nil
+}
+
+// requireHLSSegment는 스트림의 HLS 플레이리스트와 첫 세그먼트를 받을 수 있을 때까지 기다립니다
+func requireHLSSegment(t *testing.T, s *testServer, streamID string) {
+	t.Helper()
+
+	var multivariant string
+	require.Eventually(t, func() bool {
+		resp, body := s.request(t, http.MethodGet, "/hls/"+streamID+"/index.m3u8", nil, nil)
+		multivariant = string(body)
+		return resp.StatusCode == http.StatusOK
+	}, 15*time.Second, 500*time.Millisecond, "HLS playlist not available")
+	require.True(t, strings.HasPrefix(multivariant, "#EXTM3U"), multivariant)
+
+	// 미디어 플레이리스트에 세그먼트가 생길 때까지 (1초 GOP)
+	media := regexp.MustCompile(`(?m)^([^#\s][^?\s]*\.m3u8)`).FindStringSubmatch(multivariant)
+	require.NotNil(t, media, multivariant)
+
+	var playlist string
+	require.Eventually(t, func() bool {
+		resp, body := s.request(t, http.MethodGet, "/hls/"+streamID+"/"+media[1], nil, nil)
+		playlist = string(body)
+		return resp.StatusCode == http.StatusOK && strings.Contains(playlist, "#EXTINF")
+	}, 15*time.Second, 500*time.Millisecond, "HLS media playlist has no segments")
+
+	segment := regexp.MustCompile(`(?m)^#EXTINF:[^\n]*\n([^?\s]+)`).FindStringSubmatch(playlist)
+	require.NotNil(t, segment, playlist)
+	resp, body := s.request(t, http.MethodGet, "/hls/"+streamID+"/"+segment[1], nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.NotEmpty(t, body)
+}
+
+// TestRTMPIngest는 RTMP로 송출한 테스트 영상을 HLS로 재생할 수 있는지와 스트림 키 인증을 테스트합니다
+func TestRTMPIngest(t *testing.T) {
+	port := freePort(t)
+	s := startTestServer(t, testServerOptions{
+		Extra: fmt.Sprintf(`ingest:
+  rtmp:
+    enabled: true
+    port: %d
+  read_timeout: 5
+  stream_keys:
+    "secret-key": "cam1"
+  require_stream_key: true
+`, port),
+	})
+
+	t.Run("PublishToHLS", func(t *testing.T) {
+		publisher, err := publishRTMP(t, port, "secret-key")
+		require.NoError(t, err)
+
+		// 스트림 키에 매핑된 스트림으로 수신
+		requireHLSSegment(t, s, "cam1")
+
+		// 같은 스트림에 두 번째 송출은 거부
+		second, err := publishRTMP(t, port, "secret-key")
+		if err == nil {
+			assertPublisherClosed(t, second)
+		}
+
+		// 첫 송출은 계속 유지
+		select {
+		case err := <-publisher.done:
+			t.Fatalf("first publisher stopped: %v", err)
+		default:
+		}
+	})
+
+	t.Run("BadKey", func(t *testing.T) {
+		// 등록되지 않은 키는 require_stream_key로 거부
+		publisher, err := publishRTMP(t, port, "wrong-key")
+		if err == nil {
+			assertPublisherClosed(t, publisher)
+		}
+
+		resp, _ := s.request(t, http.MethodGet, "/api/v1/streams/wrong-key", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+// TestSRTIngest는 SRT로 송출한 테스트 영상을 HLS로 재생할 수 있는지와 스트림 키 인증을 테스트합니다
+func TestSRTIngest(t *testing.T) {
+	port := freePort(t)
+	s := startTestServer(t, testServerOptions{
+		Extra: fmt.Sprintf(`ingest:
+  srt:
+    enabled: true
+    port: %d
+  read_timeout: 5
+  stream_keys:
+    "secret-key": "cam1"
+  require_stream_key: true
+`, port),
+	})
+
+	t.Run("PublishToHLS", func(t *testing.T) {
+		publisher, err := publishSRT(t, port, "publish:secret-key")
+		require.NoError(t, err)
+
+		// 스트림 키에 매핑된 스트림으로 수신
+		requireHLSSegment(t, s, "cam1")
+
+		// 같은 스트림에 두 번째 송출은 연결 단계에서 거부
+		_, err = publishSRT(t, port, "publish:secret-key")
+		assert.Error(t, err)
+
+		// 첫 송출은 계속 유지
+		select {
+		case err := <-publisher.done:
+			t.Fatalf("first publisher stopped: %v", err)
+		default:
+		}
+	})
+
+	t.Run("BadKey", func(t *testing.T) {
+		// 등록되지 않은 키는 require_stream_key로 거부
+		_, err := publishSRT(t, port, "publish:wrong-key")
+		assert.Error(t, err)
+
+		resp, _ := s.request(t, http.MethodGet, "/api/v1/streams/wrong-key", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+// assertPublisherClosed는 서버가 송출 연결을 닫는지 확인합니다 (인증 실패 지연 포함)
+func assertPublisherClosed(t *testing.T, p *ingestPublisher) {
+	t.Helper()
+
+	select {
+	case err := <-p.done:
+		require.Error(t, err)
+		var netErr net.Error
+		assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "publish timed out instead of being closed: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("server did not close the rejected publish")
+	}
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// TestMetrics는 Prometheus 메트릭 엔드포인트와 stats uptime을 테스트합니다
+func TestMetrics(t *testing.T) {
+	port := freePort(t)
+	s := startTestServer(t, testServerOptions{
+		Extra: fmt.Sprintf("metrics:\n  enabled: true\n  port: %d\n", port),
+	})
+	metricsURL := fmt.Sprintf("http://127.0.0.1:%d/metrics", port)
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID:             "test-metrics",
+		Name:           "Test Metrics",
+		Source:         "rtsp://test.com/stream",
+		SourceOnDemand: true,
+		RTSPTransport:  "tcp",
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	t.Run("Exposition", func(t *testing.T) {
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Get(metricsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		text := string(body)
+
+		assert.Contains(t, text, "# TYPE cctv_stream_packets_received_total counter")
+		assert.Contains(t, text, `cctv_stream_packets_received_total{stream_id="test-metrics"} 0`)
+		assert.Contains(t, text, `cctv_stream_subscribers{stream_id="test-metrics"} 0`)
+		assert.Contains(t, text, `cctv_stream_source_reconnects_total{stream_id="test-metrics"} 0`)
+		assert.Contains(t, text, "cctv_webrtc_peers ")
+		assert.Contains(t, text, "go_goroutines ")
+		assert.Contains(t, text, "process_start_time_seconds ")
+	})
+
+	t.Run("StatsUptime", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodGet, "/api/v1/stats", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var stats map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &stats))
+		assert.Regexp(t, `^\d+h \d+m \d+s$`, stats["uptime"])
+		assert.Contains(t, stats, "uptime_seconds")
+	})
+}
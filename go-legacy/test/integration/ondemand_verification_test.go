@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
 )
 
 // TestOnDemandVerification는 온디맨드 스트림이 제대로 동작하는지 검증합니다
@@ -19,7 +20,13 @@ func TestOnDemandVerification(t *testing.T) {
 		t.Skip("Server is not running")
 	}
 
-	streamID := "CCTV-TEST1"
+	cleanupTestStreams(t)
+	defer cleanupTestStreams(t)
+
+	streamID := "test-ondemand-verify"
+	createStream(t, database.Stream{
+		ID: streamID, Name: streamID, Source: testPatternSource, SourceOnDemand: true,
+	})
 
 	t.Log("=== 온디맨드 스트림 검증 시작 ===")
 	t.Logf("테스트 대상: %s", streamID)
@@ -130,7 +137,15 @@ func TestOnDemandMultipleStreams(t *testing.T) {
 		t.Skip("Server is not running")
 	}
 
-	streams := []string{"CCTV-TEST1", "CCTV-TEST2", "CCTV-TEST3"}
+	cleanupTestStreams(t)
+	defer cleanupTestStreams(t)
+
+	streams := []string{"test-ondemand-multi-1", "test-ondemand-multi-2", "test-ondemand-multi-3"}
+	for _, streamID := range streams {
+		createStream(t, database.Stream{
+			ID: streamID, Name: streamID, Source: testPatternSource, SourceOnDemand: true,
+		})
+	}
 
 	t.Log("=== 다중 온디맨드 스트림 검증 시작 ===")
 	t.Logf("테스트 대상: %v", streams)
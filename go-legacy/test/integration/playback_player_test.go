@@ -0,0 +1,227 @@
+package integration
+
+import (
+	"net/http"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/playback"
+	"github.com/yourusername/cctv3/internal/recorder"
+	"go.uber.org/zap"
+)
+
+// playerAU는 재생기가 보낸 access unit
+type playerAU struct {
+	ts  uint32
+	at  time.Time
+	idr bool
+}
+
+// playerSink는 재생기 RTP 패킷을 access unit으로 조립합니다
+type playerSink struct {
+	mutex   sync.Mutex
+	decoder *rtph264.Decoder
+	aus     []playerAU
+}
+
+func newPlayerSink(t *testing.T) *playerSink {
+	decoder := &rtph264.Decoder{PacketizationMode: 1}
+	require.NoError(t, decoder.Init())
+	return &playerSink{decoder: decoder}
+}
+
+func (s *playerSink) OnPacket(pkt *rtp.Packet) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	au, err := s.decoder.Decode(pkt)
+	if err != nil {
+		return nil
+	}
+	s.aus = append(s.aus, playerAU{ts: pkt.Timestamp, at: time.Now(), idr: h264.IsRandomAccess(au)})
+	return nil
+}
+
+// received는 지금까지 받은 access unit 목록을 반환합니다
+func (s *playerSink) received() []playerAU {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]playerAU(nil), s.aus...)
+}
+
+// waitAUs는 access unit을 n개 이상 받을 때까지 기다립니다
+func (s *playerSink) waitAUs(t *testing.T, n int) []playerAU {
+	t.Helper()
+
+	var aus []playerAU
+	require.Eventually(t, func() bool {
+		aus = s.received()
+		return len(aus) >= n
+	}, 10*time.Second, 10*time.Millisecond, "player sent fewer than %d access units", n)
+	return aus
+}
+
+// recordTestPattern은 1초 GOP(10fps) 테스트 패턴을 2초 세그먼트로 녹화하고
+// 세그먼트가 minSegments개 이상 완료되면 서버를 종료합니다
+func recordTestPattern(t *testing.T, minSegments int) (string, []*recorder.Segment) {
+	t.Helper()
+
+	camera := mockRTSPCamera(t)
+	s := startTestServer(t, testServerOptions{Extra: recordingConfig})
+
+	const id = "cam1"
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: id, Name: id, Source: camera.URL(id),
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	recordPath := filepath.Join(s.Dir, "recordings")
+	require.Eventually(t, func() bool {
+		segments, _ := recorder.ListSegments(recordPath, id)
+		return len(segments) > minSegments
+	}, 30*time.Second, 250*time.Millisecond, "recording segments not created")
+
+	// 종료 시 기록 중인 세그먼트도 마무리됨
+	require.Equal(t, 0, s.stop(t, syscall.SIGTERM))
+
+	segments, err := recorder.ListSegments(recordPath, id)
+	require.NoError(t, err)
+	return recordPath, segments
+}
+
+// TestPlaybackPlayer는 녹화 재생기(WebRTC 재생)의 seek, 일시정지/재개, 재생 속도를 테스트합니다
+func TestPlaybackPlayer(t *testing.T) {
+	recordPath, segments := recordTestPattern(t, 4)
+	manager := playback.NewManager(recordPath, zap.NewNop())
+	origin := segments[0].Start
+
+	newPlayer := func(t *testing.T, start time.Time, rate float64) (*playback.Player, *playerSink) {
+		sink := newPlayerSink(t)
+		player, err := manager.NewPlayer(playback.PlayerConfig{
+			StreamID: "cam1",
+			Start:    start,
+			Rate:     rate,
+			Sink:     sink,
+			Logger:   zap.NewNop(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "H264", player.Codec())
+		t.Cleanup(player.Close)
+		return player, sink
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := manager.NewPlayer(playback.PlayerConfig{
+			StreamID: "cam1", Start: origin, Rate: 32, Sink: newPlayerSink(t), Logger: zap.NewNop(),
+		})
+		assert.Error(t, err)
+
+		_, err = manager.NewPlayer(playback.PlayerConfig{
+			StreamID: "missing", Start: origin, Sink: newPlayerSink(t), Logger: zap.NewNop(),
+		})
+		assert.ErrorIs(t, err, playback.ErrNoSegmentsFound)
+
+		player, _ := newPlayer(t, origin, 1)
+		_, err = player.SetRate(0.01)
+		assert.Error(t, err)
+		assert.Equal(t, 1.0, player.State().Rate)
+	})
+
+	t.Run("SeekToKeyframe", func(t *testing.T) {
+		// 두 번째 GOP 중간에서 시작하면 그 GOP의 키프레임(1초 지점)부터 전송
+		start := origin.Add(1500 * time.Millisecond)
+		player, sink := newPlayer(t, start, 1)
+		player.Start()
+
+		aus := sink.waitAUs(t, 1)
+		assert.True(t, aus[0].idr, "playback did not start on a keyframe")
+		assert.WithinDuration(t, origin.Add(time.Second), player.State().Position, 150*time.Millisecond)
+
+		// 일시정지 상태에서 다음 세그먼트의 GOP 중간으로 이동 (재개 후 첫 프레임 확인)
+		player.Pause()
+		n := len(sink.received())
+		target := origin.Add(3500 * time.Millisecond)
+		state := player.Seek(target)
+		assert.Equal(t, target, state.Position)
+		assert.True(t, state.Paused)
+		player.Resume()
+
+		aus = sink.waitAUs(t, n+1)
+		assert.True(t, aus[n].idr, "playback after seek did not start on a keyframe")
+		assert.WithinDuration(t, origin.Add(3*time.Second), player.State().Position, 150*time.Millisecond)
+
+		// 타임스탬프는 seek 이후에도 증가
+		assert.Greater(t, int32(aus[n].ts-aus[n-1].ts), int32(0))
+	})
+
+	t.Run("PauseResume", func(t *testing.T) {
+		player, sink := newPlayer(t, origin.Add(time.Second), 1)
+		player.Start()
+		sink.waitAUs(t, 5)
+
+		state := player.Pause()
+		assert.True(t, state.Paused)
+		paused := sink.received()
+		position := player.State().Position
+
+		time.Sleep(time.Second)
+		assert.Len(t, sink.received(), len(paused), "packets sent while paused")
+		assert.Equal(t, position, player.State().Position)
+
+		state = player.Resume()
+		assert.False(t, state.Paused)
+
+		aus := sink.waitAUs(t, len(paused)+3)
+		last, resumed := paused[len(paused)-1], aus[len(paused)]
+
+		// RTP 타임스탬프는 wall clock을 따라가므로 일시정지 구간만큼 벌어짐
+		delta := time.Duration(int32(resumed.ts-last.ts)) * time.Second / 90000
+		assert.GreaterOrEqual(t, delta, time.Second)
+		assert.InDelta(t, resumed.at.Sub(last.at), delta, float64(50*time.Millisecond))
+
+		// 재개 후에는 다시 프레임 간격(100ms)
+		for i := len(paused) + 1; i < len(aus); i++ {
+			step := time.Duration(int32(aus[i].ts-aus[i-1].ts)) * time.Second / 90000
+			assert.InDelta(t, 100*time.Millisecond, step, float64(20*time.Millisecond), "frame %d", i)
+		}
+	})
+
+	t.Run("Rate", func(t *testing.T) {
+		player, sink := newPlayer(t, origin.Add(time.Second), 2)
+		assert.Equal(t, 2.0, player.State().Rate)
+		player.Start()
+
+		// 10fps 녹화를 2배속으로: 프레임 간격 50ms (RTP 4500)
+		aus := sink.waitAUs(t, 21)
+		for i := 1; i < len(aus); i++ {
+			step := int32(aus[i].ts - aus[i-1].ts)
+			assert.InDelta(t, 4500, step, 900, "frame %d", i)
+		}
+		assert.InDelta(t, time.Second, aus[20].at.Sub(aus[0].at), float64(200*time.Millisecond))
+
+		// 재생 위치는 실제 시간의 2배로 진행
+		begin := player.State().Position
+		time.Sleep(time.Second)
+		assert.InDelta(t, 2*time.Second, player.State().Position.Sub(begin), float64(300*time.Millisecond))
+
+		// 속도 변경 후 1배속 간격
+		state, err := player.SetRate(1)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, state.Rate)
+		n := len(sink.received())
+		aus = sink.waitAUs(t, n+4)
+		for i := n + 1; i < len(aus); i++ {
+			step := int32(aus[i].ts - aus[i-1].ts)
+			assert.InDelta(t, 9000, step, 1800, "frame %d", i)
+		}
+	})
+}
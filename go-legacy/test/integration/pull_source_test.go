@@ -0,0 +1,300 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/mpegts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// TestPullSource는 소스 종류별(RTSP, HLS, MJPEG, UDP MPEG-TS)로 패킷이 스트림에 도착하는지와
+// 소스가 끊긴 뒤 재연결되는지, 재시도 한도(rtsp.client.retry_count)에서 멈추는지 테스트합니다
+// 테스트 서버 설정: timeout 5초, retry_count 3, retry_delay 1초
+func TestPullSource(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	t.Run("RTSP", func(t *testing.T) {
+		camera := mockRTSPCamera(t)
+		createPullStream(t, s, "pull-rtsp", camera.URL("stream"))
+		waitPackets(t, s, "pull-rtsp", 0)
+
+		// 카메라가 연결을 닫으면 retry_delay 후 다시 연결
+		camera.Stop()
+		stalled := waitPacketsStopped(t, s, "pull-rtsp")
+		camera.Start(t)
+		waitPackets(t, s, "pull-rtsp", stalled)
+	})
+
+	t.Run("HLS", func(t *testing.T) {
+		var failPlaylist atomic.Bool
+		server := httptest.NewServer(hlsHandler(t, &failPlaylist))
+		defer server.Close()
+
+		createPullStream(t, s, "pull-hls", server.URL+"/live/index.m3u8")
+		waitPackets(t, s, "pull-hls", 0)
+
+		// 플레이리스트 갱신이 실패하면 연결을 끊고 retry_delay 후 다시 시작
+		failPlaylist.Store(true)
+		require.Eventually(t, func() bool { return !failPlaylist.Load() }, 10*time.Second, 100*time.Millisecond, "playlist failure was not served")
+		stalled := waitPacketsStopped(t, s, "pull-hls")
+		waitPackets(t, s, "pull-hls", stalled)
+	})
+
+	t.Run("MJPEG", func(t *testing.T) {
+		// 첫 연결은 20프레임 후 서버가 닫음, 이후 연결은 계속 송출
+		var connections atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			first := connections.Add(1) == 1
+			serveMJPEG(w, r, func(frame int) bool { return !first || frame < 20 })
+		}))
+		defer func() {
+			server.CloseClientConnections()
+			server.Close()
+		}()
+
+		createPullStream(t, s, "pull-mjpeg", server.URL+"/video.mjpg")
+		waitPackets(t, s, "pull-mjpeg", 0)
+
+		require.Eventually(t, func() bool { return connections.Load() == 2 }, 10*time.Second, 100*time.Millisecond, "MJPEG source did not reconnect")
+		waitPackets(t, s, "pull-mjpeg", packetsReceived(t, s, "pull-mjpeg"))
+	})
+
+	t.Run("UDP", func(t *testing.T) {
+		port := freePort(t)
+		conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", port))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var paused atomic.Bool
+		stop := make(chan struct{})
+		defer close(stop)
+		go sendMPEGTS(conn, loadCameraClip(t), &paused, stop)
+
+		createPullStream(t, s, "pull-udp", fmt.Sprintf("udp://127.0.0.1:%d", port))
+		waitPackets(t, s, "pull-udp", 0)
+
+		// timeout(5초) 동안 데이터가 없으면 소켓을 닫고 retry_delay 후 다시 수신
+		paused.Store(true)
+		stalled := waitPacketsStopped(t, s, "pull-udp")
+		time.Sleep(6 * time.Second)
+		paused.Store(false)
+		waitPackets(t, s, "pull-udp", stalled)
+	})
+
+	t.Run("GiveUp", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			http.Error(w, "camera offline", http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		created := time.Now()
+		createPullStream(t, s, "pull-lost", server.URL+"/video.mjpg")
+
+		// retry_count(3)번 시도, 시도 사이 retry_delay(1초)
+		require.Eventually(t, func() bool { return attempts.Load() == 3 }, 15*time.Second, 100*time.Millisecond, "source did not retry retry_count times")
+		assert.GreaterOrEqual(t, time.Since(created), 2*time.Second, "retries did not wait retry_delay")
+
+		// 한도에 도달하면 더 이상 시도하지 않음
+		time.Sleep(2500 * time.Millisecond)
+		assert.Equal(t, int32(3), attempts.Load())
+		assert.Equal(t, float64(0), packetsReceived(t, s, "pull-lost"))
+	})
+}
+
+// createPullStream은 즉시 시작하는 스트림을 만듭니다
+func createPullStream(t *testing.T, s *testServer, id, source string) {
+	t.Helper()
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: id, Name: id, Source: source,
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+}
+
+// packetsReceived는 스트림이 받은 RTP 패킷 수를 반환합니다 (실행 중이 아니면 0)
+func packetsReceived(t *testing.T, s *testServer, id string) float64 {
+	t.Helper()
+
+	resp, body := s.request(t, http.MethodGet, "/api/v1/streams/"+id, nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var stream StreamResponse
+	require.NoError(t, json.Unmarshal(body, &stream))
+	if stream.RuntimeInfo == nil {
+		return 0
+	}
+	n, _ := stream.RuntimeInfo["packets_received"].(float64)
+	return n
+}
+
+// waitPackets는 수신 패킷 수가 after보다 늘어날 때까지 기다리고 그 값을 반환합니다
+func waitPackets(t *testing.T, s *testServer, id string, after float64) float64 {
+	t.Helper()
+
+	var n float64
+	require.Eventually(t, func() bool {
+		n = packetsReceived(t, s, id)
+		return n > after
+	}, 15*time.Second, 200*time.Millisecond, "%s received no packets", id)
+	return n
+}
+
+// waitPacketsStopped는 수신 패킷 수가 더 이상 늘지 않을 때까지 기다리고 그 값을 반환합니다
+func waitPacketsStopped(t *testing.T, s *testServer, id string) float64 {
+	t.Helper()
+
+	last := -1.0
+	require.Eventually(t, func() bool {
+		n := packetsReceived(t, s, id)
+		stopped := n == last
+		last = n
+		return stopped
+	}, 15*time.Second, 500*time.Millisecond, "%s kept receiving packets", id)
+	return last
+}
+
+// clipMPEGTS는 테스트 영상을 MPEG-TS로 씁니다 (offset번째 반복, 1초 단위 타임스탬프)
+func clipMPEGTS(clip [][][]byte, w *mpegts.Writer, track *mpegts.Track, offset int, each func() error) error {
+	for i, au := range clip {
+		pts := int64(offset*len(clip)+i) * 90000 / 10
+		if err := w.WriteH264(track, pts, pts, au); err != nil {
+			return err
+		}
+		if each != nil {
+			if err := each(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hlsHandler는 1초 세그먼트 3개를 유지하는 라이브 HLS(MPEG-TS) 서버입니다
+// fail이 설정되면 다음 플레이리스트 요청 한 번을 404로 응답합니다
+func hlsHandler(t *testing.T, fail *atomic.Bool) http.Handler {
+	clip := loadCameraClip(t)
+	start := time.Now()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/live/index.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		if fail.CompareAndSwap(true, false) {
+			http.NotFound(w, r)
+			return
+		}
+
+		first := int(time.Since(start) / time.Second)
+		var b strings.Builder
+		fmt.Fprintf(&b, "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:1\n#EXT-X-MEDIA-SEQUENCE:%d\n", first)
+		for seq := first; seq < first+3; seq++ {
+			fmt.Fprintf(&b, "#EXTINF:1.0,\nseg%d.ts\n", seq)
+		}
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Write([]byte(b.String()))
+	})
+	mux.HandleFunc("/live/", func(w http.ResponseWriter, r *http.Request) {
+		seq, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/live/seg"), ".ts"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		var buf bytes.Buffer
+		track := &mpegts.Track{Codec: &mpegts.CodecH264{}}
+		tw := &mpegts.Writer{W: &buf, Tracks: []*mpegts.Track{track}}
+		if err := tw.Initialize(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := clipMPEGTS(clip, tw, track, seq, nil); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "video/mp2t")
+		w.Write(buf.Bytes())
+	})
+	return mux
+}
+
+// serveMJPEG는 단색 JPEG(YCbCr 4:2:0)를 10fps multipart/x-mixed-replace로 송출합니다 (more가 false를 반환하면 종료)
+func serveMJPEG(w http.ResponseWriter, r *http.Request, more func(frame int) bool) {
+	var frame bytes.Buffer
+	if err := jpeg.Encode(&frame, image.NewRGBA(image.Rect(0, 0, 64, 48)), nil); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mw.Boundary())
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for i := 0; more(i); i++ {
+		part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"image/jpeg"}})
+		if err != nil {
+			return
+		}
+		if _, err := part.Write(frame.Bytes()); err != nil {
+			return
+		}
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendMPEGTS는 테스트 영상을 실시간 속도로 UDP MPEG-TS 송출합니다 (paused 동안은 보내지 않음)
+// 수신 소켓이 닫혀 있는 동안의 전송 오류는 무시합니다
+func sendMPEGTS(conn net.Conn, clip [][][]byte, paused *atomic.Bool, stop chan struct{}) {
+	var buf bytes.Buffer
+	track := &mpegts.Track{Codec: &mpegts.CodecH264{}}
+	w := &mpegts.Writer{W: &buf, Tracks: []*mpegts.Track{track}}
+	if err := w.Initialize(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for offset := 0; ; offset++ {
+		err := clipMPEGTS(clip, w, track, offset, func() error {
+			select {
+			case <-stop:
+				return net.ErrClosed
+			case <-ticker.C:
+			}
+
+			// 데이터그램마다 TS 패킷 7개
+			for !paused.Load() && buf.Len() > 0 {
+				conn.Write(buf.Next(srtPayloadSize))
+			}
+			buf.Reset()
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+}
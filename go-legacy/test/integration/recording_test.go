@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/bluenviron/mediacommon/v2/pkg/formats/pmp4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/recorder"
+)
+
+// recordingConfig는 2초 세그먼트로 녹화하는 설정
+const recordingConfig = `record:
+  enabled: true
+  path: recordings
+  segment_duration: 2
+  part_duration: 500
+`
+
+// recordingEntry는 GET /api/v1/recordings 응답의 녹화 구간
+type recordingEntry struct {
+	Start    time.Time `json:"start"`
+	Duration float64   `json:"duration"`
+	URL      string    `json:"url"`
+}
+
+// TestRecordingPlayback은 RTSP 카메라 스트림을 녹화하고 구간 목록, 세그먼트 병합 재생, 키프레임 시작, Range 요청을 테스트합니다
+func TestRecordingPlayback(t *testing.T) {
+	camera := mockRTSPCamera(t)
+	s := startTestServer(t, testServerOptions{Extra: recordingConfig})
+
+	// 1초(10프레임)마다 IDR
+	const id = "cam1"
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: id, Name: id, Source: camera.URL(id),
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	// 완료된 세그먼트 2개 + 기록 중인 세그먼트 1개
+	recordPath := filepath.Join(s.Dir, "recordings")
+	var segments []*recorder.Segment
+	require.Eventually(t, func() bool {
+		segments, _ = recorder.ListSegments(recordPath, id)
+		return len(segments) >= 3
+	}, 20*time.Second, 250*time.Millisecond, "recording segments not created")
+
+	t.Run("Segments", func(t *testing.T) {
+		for i := 1; i < len(segments); i++ {
+			gap := segments[i].Start.Sub(segments[i-1].Start)
+			assert.InDelta(t, 2*time.Second, gap, float64(500*time.Millisecond), "segment %d", i)
+		}
+	})
+
+	var entries []recordingEntry
+	t.Run("List", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodGet, "/api/v1/recordings/"+id, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var list struct {
+			Recordings []recordingEntry `json:"recordings"`
+			Count      int              `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(body, &list))
+
+		// 연속된 세그먼트는 하나의 구간으로 병합
+		require.Equal(t, 1, list.Count, string(body))
+		entries = list.Recordings
+		assert.GreaterOrEqual(t, entries[0].Duration, 4.0)
+		assert.WithinDuration(t, segments[0].Start, entries[0].Start, 100*time.Millisecond)
+		assert.Contains(t, entries[0].URL, "/playback/"+id+"?")
+
+		// 녹화 이전 구간
+		before := url.QueryEscape(segments[0].Start.Add(-time.Hour).Format(time.RFC3339))
+		resp, body = s.request(t, http.MethodGet,
+			"/api/v1/recordings/"+id+"?end="+before, nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodGet, "/api/v1/recordings/missing", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, string(body))
+	})
+	require.NotEmpty(t, entries)
+
+	// 두 번째 GOP 중간(1.5초)부터 첫 세그먼트 경계(2초)를 넘어 2초
+	start := entries[0].Start.Add(1500 * time.Millisecond)
+	clipPath := fmt.Sprintf("/playback/%s?start=%s&duration=2&format=mp4",
+		id, url.QueryEscape(start.Format(time.RFC3339Nano)))
+
+	var clip []byte
+	t.Run("MergeRange", func(t *testing.T) {
+		var resp *http.Response
+		resp, clip = s.request(t, http.MethodGet, clipPath, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(clip))
+		assert.Equal(t, "video/mp4", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+
+		var pres pmp4.Presentation
+		require.NoError(t, pres.Unmarshal(bytes.NewReader(clip)))
+		require.Len(t, pres.Tracks, 1)
+		track := pres.Tracks[0]
+		require.NotEmpty(t, track.Samples)
+
+		// 클립은 start 이전의 키프레임(1초 지점)에서 시작
+		first := track.Samples[0]
+		assert.False(t, first.IsNonSyncSample)
+		payload, err := first.GetPayload()
+		require.NoError(t, err)
+		var au h264.AVCC
+		require.NoError(t, au.Unmarshal(payload))
+		assert.True(t, h264.IsRandomAccess(au), "first sample is not an IDR")
+
+		// 키프레임은 start보다 최대 1 GOP 앞 (edit list로 건너뜀)
+		offset := time.Duration(track.TimeOffset) * time.Second / time.Duration(track.TimeScale)
+		assert.InDelta(t, -500*time.Millisecond, offset, float64(150*time.Millisecond))
+
+		// 세그먼트 경계를 넘어 요청 길이 + 키프레임 이전 구간만큼 포함
+		var total time.Duration
+		for _, sample := range track.Samples {
+			total += time.Duration(sample.Duration) * time.Second / time.Duration(track.TimeScale)
+		}
+		assert.InDelta(t, 2500*time.Millisecond, total, float64(300*time.Millisecond))
+		assert.InDelta(t, 25, len(track.Samples), 3)
+	})
+	require.NotEmpty(t, clip)
+
+	t.Run("Range", func(t *testing.T) {
+		header := http.Header{"Range": {"bytes=0-99"}}
+		resp, body := s.request(t, http.MethodGet, clipPath, nil, header)
+		require.Equal(t, http.StatusPartialContent, resp.StatusCode, string(body))
+		assert.Equal(t, fmt.Sprintf("bytes 0-99/%d", len(clip)), resp.Header.Get("Content-Range"))
+		assert.Equal(t, clip[:100], body)
+
+		// 끝부분 탐색
+		header = http.Header{"Range": {fmt.Sprintf("bytes=%d-", len(clip)-50)}}
+		resp, body = s.request(t, http.MethodGet, clipPath, nil, header)
+		require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Equal(t, clip[len(clip)-50:], body)
+
+		header = http.Header{"Range": {fmt.Sprintf("bytes=%d-", len(clip)+10)}}
+		resp, _ = s.request(t, http.MethodGet, clipPath, nil, header)
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+
+		// 전체 요청과 Range 요청은 한 번 생성한 클립 파일을 함께 사용
+		clips, err := filepath.Glob(filepath.Join(s.Dir, "tmp", "playback-clips-*", "*.mp4"))
+		require.NoError(t, err)
+		assert.Len(t, clips, 1)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodGet, "/playback/"+id+"?start=bad&duration=2", nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		before := url.QueryEscape(segments[0].Start.Add(-time.Hour).Format(time.RFC3339))
+		resp, body = s.request(t, http.MethodGet, "/playback/"+id+"?start="+before+"&duration=2", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, string(body))
+	})
+}
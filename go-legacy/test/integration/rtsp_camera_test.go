@@ -0,0 +1,181 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/bars.h264는 320x240, 10fps, 1초 GOP 테스트 패턴 10프레임 (H.264 Baseline, Annex-B)
+const cameraClipPath = "testdata/bars.h264"
+
+// loadCameraClip은 테스트 영상을 access unit 목록으로 읽습니다
+// 각 access unit은 슬라이스 NAL 유닛으로 끝납니다 (IDR 앞에는 SPS/PPS)
+func loadCameraClip(t *testing.T) [][][]byte {
+	t.Helper()
+
+	data, err := os.ReadFile(cameraClipPath)
+	require.NoError(t, err)
+
+	var nalus h264.AnnexB
+	require.NoError(t, nalus.Unmarshal(data))
+
+	var clip [][][]byte
+	var au [][]byte
+	for _, nalu := range nalus {
+		au = append(au, nalu)
+		if typ := h264.NALUType(nalu[0] & 0x1F); typ == h264.NALUTypeIDR || typ == h264.NALUTypeNonIDR {
+			clip = append(clip, au)
+			au = nil
+		}
+	}
+	require.NotEmpty(t, clip)
+	return clip
+}
+
+// rtspCamera는 테스트 영상을 반복 송출하는 RTSP 카메라입니다 (모든 경로에서 같은 영상)
+type rtspCamera struct {
+	Port int
+
+	clip    [][][]byte
+	forma   *format.H264
+	media   *description.Media
+	mutex   sync.Mutex
+	server  *gortsplib.Server
+	stream  atomic.Pointer[gortsplib.ServerStream]
+	stopped chan struct{}
+	done    chan struct{}
+}
+
+// mockRTSPCamera는 빈 포트에서 RTSP 카메라를 시작합니다 (테스트가 끝나면 종료)
+func mockRTSPCamera(t *testing.T) *rtspCamera {
+	t.Helper()
+
+	clip := loadCameraClip(t)
+	forma := &format.H264{
+		PayloadTyp:        96,
+		SPS:               clip[0][0],
+		PPS:               clip[0][1],
+		PacketizationMode: 1,
+	}
+	c := &rtspCamera{
+		Port:  freePort(t),
+		clip:  clip,
+		forma: forma,
+		media: &description.Media{Type: description.MediaTypeVideo, Formats: []format.Format{forma}},
+	}
+	c.Start(t)
+	t.Cleanup(c.Stop)
+	return c
+}
+
+// URL은 카메라의 스트림 주소를 반환합니다
+func (c *rtspCamera) URL(path string) string {
+	return fmt.Sprintf("rtsp://127.0.0.1:%d/%s", c.Port, path)
+}
+
+// Start는 같은 포트에서 송출을 시작합니다 (Stop 이후 재시작 가능)
+func (c *rtspCamera) Start(t *testing.T) {
+	t.Helper()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	require.Nil(t, c.server, "camera already running")
+
+	c.server = &gortsplib.Server{
+		Handler:     c,
+		RTSPAddress: fmt.Sprintf("127.0.0.1:%d", c.Port),
+	}
+	require.NoError(t, c.server.Start())
+
+	stream := &gortsplib.ServerStream{
+		Server: c.server,
+		Desc:   &description.Session{Medias: []*description.Media{c.media}},
+	}
+	require.NoError(t, stream.Initialize())
+	c.stream.Store(stream)
+
+	c.stopped = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.run(stream, c.stopped, c.done)
+}
+
+// Stop은 송출을 멈추고 연결을 모두 닫습니다
+func (c *rtspCamera) Stop() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.server == nil {
+		return
+	}
+	close(c.stopped)
+	<-c.done
+	c.stream.Swap(nil).Close()
+	c.server.Close()
+	c.server = nil
+}
+
+// run은 테스트 영상을 실시간 속도(10fps)로 반복 송출합니다
+func (c *rtspCamera) run(stream *gortsplib.ServerStream, stopped, done chan struct{}) {
+	defer close(done)
+
+	encoder, err := c.forma.CreateEncoder()
+	if err != nil {
+		return
+	}
+
+	const frameDuration = 100 * time.Millisecond
+	ticker := time.NewTicker(frameDuration)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		select {
+		case <-stopped:
+			return
+		case <-ticker.C:
+		}
+
+		packets, err := encoder.Encode(c.clip[frame%len(c.clip)])
+		if err != nil {
+			return
+		}
+		for _, pkt := range packets {
+			pkt.Timestamp += uint32(frame * 90000 / 10)
+			stream.WritePacketRTP(c.media, pkt)
+		}
+	}
+}
+
+// OnDescribe는 DESCRIBE 요청에 스트림을 반환합니다 (gortsplib.ServerHandlerOnDescribe)
+func (c *rtspCamera) OnDescribe(ctx *gortsplib.ServerHandlerOnDescribeCtx) (*base.Response, *gortsplib.ServerStream, error) {
+	return c.serve()
+}
+
+// OnSetup은 SETUP 요청에 스트림을 반환합니다 (gortsplib.ServerHandlerOnSetup)
+func (c *rtspCamera) OnSetup(ctx *gortsplib.ServerHandlerOnSetupCtx) (*base.Response, *gortsplib.ServerStream, error) {
+	return c.serve()
+}
+
+// serve는 송출 중인 스트림을 반환합니다 (시작/종료 중이면 503)
+func (c *rtspCamera) serve() (*base.Response, *gortsplib.ServerStream, error) {
+	stream := c.stream.Load()
+	if stream == nil {
+		return &base.Response{StatusCode: base.StatusServiceUnavailable}, nil, nil
+	}
+	return &base.Response{StatusCode: base.StatusOK}, stream, nil
+}
+
+// OnPlay는 PLAY 요청을 허용합니다 (gortsplib.ServerHandlerOnPlay)
+func (c *rtspCamera) OnPlay(ctx *gortsplib.ServerHandlerOnPlayCtx) (*base.Response, error) {
+	return &base.Response{StatusCode: base.StatusOK}, nil
+}
@@ -0,0 +1,257 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// 테스트 전용 서버 프로세스
+// 녹화, 인증처럼 localhost:8107 서버(인증 비활성화)로 확인할 수 없는 동작은
+// 서버를 빌드해 임시 디렉토리와 빈 포트로 직접 실행해서 테스트합니다
+
+var (
+	serverBinaryOnce sync.Once
+	serverBinaryPath string
+	serverBinaryErr  error
+)
+
+// buildServerBinary는 cmd/server를 한 번만 빌드합니다
+func buildServerBinary(t *testing.T) string {
+	t.Helper()
+
+	serverBinaryOnce.Do(func() {
+		serverBinaryPath = filepath.Join(os.TempDir(), "cctv3-integration-server")
+		cmd := exec.Command("go", "build", "-o", serverBinaryPath, "../../cmd/server")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			serverBinaryErr = fmt.Errorf("go build failed: %v\n%s", err, out)
+		}
+	})
+	if serverBinaryErr != nil {
+		t.Skipf("Cannot build server: %v", serverBinaryErr)
+	}
+	return serverBinaryPath
+}
+
+// freePort는 사용 가능한 TCP 포트를 반환합니다
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// testServerOptions는 테스트 서버 설정
+type testServerOptions struct {
+	RTSP            bool   // 내장 RTSP 서버 활성화 (빈 포트 사용)
+	ShutdownTimeout int    // server.shutdown_timeout (초, 0이면 5)
+	Server          string // server 섹션에 추가할 설정 (2칸 들여쓰기)
+	Extra           string // 추가 최상위 설정 (record, auth 등)
+}
+
+// testServer는 실행 중인 테스트 서버 프로세스
+type testServer struct {
+	URL      string
+	RTSPPort int
+	Dir      string
+
+	cmd      *exec.Cmd
+	done     chan struct{}
+	exitCode int
+}
+
+// startTestServer는 임시 디렉토리에서 서버를 실행하고 /health가 응답할 때까지 기다립니다
+// 테스트가 끝나면 서버를 종료합니다
+func startTestServer(t *testing.T, opts testServerOptions) *testServer {
+	t.Helper()
+	return startTestServerIn(t, t.TempDir(), opts)
+}
+
+// startTestServerIn은 dir에서 서버를 실행합니다 (같은 dir로 다시 실행하면 데이터베이스 유지)
+func startTestServerIn(t *testing.T, dir string, opts testServerOptions) *testServer {
+	t.Helper()
+
+	binary := buildServerBinary(t)
+
+	httpPort := freePort(t)
+	rtspPort := freePort(t)
+	if opts.ShutdownTimeout == 0 {
+		opts.ShutdownTimeout = 5
+	}
+
+	config := fmt.Sprintf(`server:
+  http_port: %d
+  ws_port: %d
+  shutdown_timeout: %d
+%s
+database:
+  path: data/streams.db
+rtsp:
+  server:
+    enabled: %t
+    port: %d
+  client:
+    timeout: 5
+    retry_count: 3
+    retry_delay: 1
+    tcp_transport: true
+  pool:
+    max_streams: 50
+webrtc:
+  settings:
+    max_peers: 50
+hls:
+  enabled: true
+  segment_duration: 1
+  segment_count: 3
+  cleanup_threshold: 6
+  output_dir: hls
+logging:
+  level: debug
+  output: console
+%s
+`, httpPort, httpPort, opts.ShutdownTimeout, opts.Server, opts.RTSP, rtspPort, opts.Extra)
+
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+
+	logFile, err := os.OpenFile(filepath.Join(dir, "server.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+
+	// 서버의 임시 파일(재생 클립 등)은 테스트 디렉토리에 생성
+	tmpDir := filepath.Join(dir, "tmp")
+	require.NoError(t, os.MkdirAll(tmpDir, 0o755))
+
+	cmd := exec.Command(binary, "-config", configPath)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TMPDIR="+tmpDir)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	require.NoError(t, cmd.Start())
+
+	s := &testServer{
+		URL:      fmt.Sprintf("http://127.0.0.1:%d", httpPort),
+		RTSPPort: rtspPort,
+		Dir:      dir,
+		cmd:      cmd,
+		done:     make(chan struct{}),
+	}
+	go func() {
+		cmd.Wait()
+		logFile.Close()
+		s.exitCode = cmd.ProcessState.ExitCode()
+		close(s.done)
+	}()
+
+	t.Cleanup(func() {
+		s.stop(t, syscall.SIGKILL)
+		if t.Failed() {
+			if log, err := os.ReadFile(filepath.Join(dir, "server.log")); err == nil {
+				t.Logf("server log:\n%s", tail(log, 60))
+			}
+		}
+	})
+
+	deadline := time.Now().Add(20 * time.Second)
+	client := &http.Client{Timeout: time.Second}
+	for time.Now().Before(deadline) {
+		select {
+		case <-s.done:
+			log, _ := os.ReadFile(filepath.Join(dir, "server.log"))
+			require.FailNowf(t, "server exited during startup", "exit code %d\n%s", s.exitCode, tail(log, 30))
+		default:
+		}
+
+		resp, err := client.Get(s.URL + "/health")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return s
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.FailNow(t, "server did not become healthy")
+	return nil
+}
+
+// request는 테스트 서버에 요청합니다 (header의 값은 모든 요청에 추가)
+func (s *testServer) request(t *testing.T, method, path string, body interface{}, header http.Header) (*http.Response, []byte) {
+	t.Helper()
+
+	// []byte 본문은 그대로 보내고 (header로 Content-Type 지정), 그 외에는 JSON으로 보냅니다
+	var reader io.Reader
+	switch body := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(body)
+	default:
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
+
+	resp, err := (&http.Client{Timeout: 15 * time.Second}).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, data
+}
+
+// stop은 서버에 시그널을 보내고 종료 코드를 반환합니다 (이미 종료되었으면 그 종료 코드)
+func (s *testServer) stop(t *testing.T, sig os.Signal) int {
+	t.Helper()
+
+	select {
+	case <-s.done:
+		return s.exitCode
+	default:
+	}
+
+	s.cmd.Process.Signal(sig)
+	select {
+	case <-s.done:
+	case <-time.After(30 * time.Second):
+		s.cmd.Process.Kill()
+		<-s.done
+		require.Fail(t, "server did not exit within 30s")
+	}
+	return s.exitCode
+}
+
+// bearer는 API 키 인증 헤더를 만듭니다
+func bearer(key string) http.Header {
+	return http.Header{"Authorization": []string{"Bearer " + key}}
+}
+
+// tail은 로그의 마지막 n줄을 반환합니다
+func tail(log []byte, n int) string {
+	lines := bytes.Split(bytes.TrimRight(log, "\n"), []byte("\n"))
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return string(bytes.Join(lines, []byte("\n")))
+}
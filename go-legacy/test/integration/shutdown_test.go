@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// TestGracefulShutdown은 종료 시그널 처리와 종료 코드를 테스트합니다
+func TestGracefulShutdown(t *testing.T) {
+	t.Run("Drained", func(t *testing.T) {
+		s := startTestServer(t, testServerOptions{})
+		camera := mockRTSPCamera(t)
+
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: "cam1", Name: "cam1", Source: camera.URL("cam1"),
+		}, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+		time.Sleep(time.Second)
+
+		assert.Equal(t, 0, s.stop(t, syscall.SIGTERM))
+
+		log, err := os.ReadFile(filepath.Join(s.Dir, "server.log"))
+		require.NoError(t, err)
+		assert.Contains(t, string(log), "Server drained")
+		assert.Contains(t, string(log), "Database closed")
+		assert.Contains(t, string(log), "Server stopped gracefully")
+	})
+
+	t.Run("DrainTimeout", func(t *testing.T) {
+		s := startTestServer(t, testServerOptions{ShutdownTimeout: 1})
+
+		// 본문을 끝까지 보내지 않는 요청으로 API 서버 종료 대기를 막음
+		conn, err := net.Dial("tcp", s.URL[len("http://"):])
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = fmt.Fprintf(conn, "POST /api/v1/streams HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n{")
+		require.NoError(t, err)
+		time.Sleep(200 * time.Millisecond)
+
+		start := time.Now()
+		assert.Equal(t, 2, s.stop(t, syscall.SIGTERM))
+		assert.Less(t, time.Since(start), 10*time.Second)
+
+		log, err := os.ReadFile(filepath.Join(s.Dir, "server.log"))
+		require.NoError(t, err)
+		assert.Contains(t, string(log), "Shutdown deadline exceeded")
+		assert.NotContains(t, string(log), "Server stopped gracefully")
+	})
+
+	t.Run("SecondSignal", func(t *testing.T) {
+		s := startTestServer(t, testServerOptions{ShutdownTimeout: 30})
+
+		conn, err := net.Dial("tcp", s.URL[len("http://"):])
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = fmt.Fprintf(conn, "POST /api/v1/streams HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n{")
+		require.NoError(t, err)
+		time.Sleep(200 * time.Millisecond)
+
+		require.NoError(t, s.cmd.Process.Signal(syscall.SIGTERM))
+		time.Sleep(500 * time.Millisecond)
+		assert.Equal(t, 3, s.stop(t, syscall.SIGINT))
+	})
+}
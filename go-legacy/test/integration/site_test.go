@@ -0,0 +1,208 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// siteResponse는 GET /api/v1/sites/:id 응답입니다
+type siteResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	MaxStreams  int      `json:"max_streams"`
+	StreamCount int      `json:"stream_count"`
+	Streams     []string `json:"streams"`
+}
+
+// siteOperationResponse는 사이트 일괄 시작/정지 응답입니다
+type siteOperationResponse struct {
+	Started int `json:"started"`
+	Limited int `json:"limited"`
+	Stopped int `json:"stopped"`
+	Results []struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"results"`
+}
+
+// getSite는 사이트와 소속 스트림을 조회합니다
+func getSite(t *testing.T, s *testServer, id string) siteResponse {
+	t.Helper()
+
+	resp, body := s.request(t, http.MethodGet, "/api/v1/sites/"+id, nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var got siteResponse
+	require.NoError(t, json.Unmarshal(body, &got))
+	return got
+}
+
+// TestSitesAndTags는 사이트/태그 CRUD, 목록 필터, 사이트 일괄 시작/정지와 동시 연결 제한을 테스트합니다
+func TestSitesAndTags(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	const siteID = "test-site"
+
+	getStream := func(t *testing.T, id string) StreamResponse {
+		resp, body := s.request(t, http.MethodGet, "/api/v1/streams/"+id, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var got StreamResponse
+		require.NoError(t, json.Unmarshal(body, &got))
+		return got
+	}
+
+	t.Run("CreateSite", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/sites", map[string]interface{}{
+			"id": siteID, "name": "Test Site", "max_streams": 2,
+		}, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/sites", map[string]interface{}{"id": siteID}, nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/sites", map[string]interface{}{"id": "test/site"}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/sites", map[string]interface{}{"id": "test-site-neg", "max_streams": -1}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+	})
+
+	// 항상 연결 스트림은 생성 즉시 시작되므로 요청 시 시작 스트림으로 만들고 사이트 시작으로 연결
+	t.Run("CreateStreams", func(t *testing.T) {
+		for _, stream := range []database.Stream{
+			{ID: "test-site-a", Source: "rtsp://127.0.0.1:1/a", SourceOnDemand: true, SiteID: siteID, Tags: []string{"test-ptz", " test-outdoor", "test-ptz"}},
+			{ID: "test-site-b", Source: "rtsp://127.0.0.1:1/b", SourceOnDemand: true, SiteID: siteID, Tags: []string{"test-outdoor"}},
+			{ID: "test-site-c", Source: "rtsp://127.0.0.1:1/c", SourceOnDemand: true},
+		} {
+			stream.Name = stream.ID
+			stream.RTSPTransport = "tcp"
+			resp, body := s.request(t, http.MethodPost, "/api/v1/streams", stream, nil)
+			require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+		}
+
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: "test-site-unknown", Name: "x", Source: "rtsp://127.0.0.1:1/x", SourceOnDemand: true, SiteID: "test-site-none",
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		stream := getStream(t, "test-site-a")
+		assert.Equal(t, siteID, stream.SiteID)
+		assert.Equal(t, []string{"test-outdoor", "test-ptz"}, stream.Tags)
+	})
+
+	t.Run("AssignStreams", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/sites/"+siteID+"/streams", map[string]interface{}{
+			"streams": []string{"test-site-c"},
+		}, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/sites/"+siteID+"/streams", map[string]interface{}{
+			"streams": []string{"test-site-none"},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		got := getSite(t, s, siteID)
+		assert.Equal(t, 3, got.StreamCount)
+		assert.Equal(t, []string{"test-site-a", "test-site-b", "test-site-c"}, got.Streams)
+
+		// 수정 요청에 site_id가 없으면 소속 유지
+		resp, body = s.request(t, http.MethodPut, "/api/v1/streams/test-site-c", database.Stream{
+			Name: "test-site-c", Source: "rtsp://127.0.0.1:1/c", SourceOnDemand: true, RTSPTransport: "tcp",
+		}, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, siteID, getStream(t, "test-site-c").SiteID)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		assert.Equal(t, []string{"test-site-a", "test-site-b", "test-site-c"}, queryStreamList(t, s, "site="+siteID+"&sort=name").streamIDs())
+		assert.Equal(t, []string{"test-site-a", "test-site-b"}, queryStreamList(t, s, "tag=test-outdoor&sort=name").streamIDs())
+		assert.Equal(t, []string{"test-site-a"}, queryStreamList(t, s, "tag=test-outdoor,test-ptz").streamIDs())
+		assert.Empty(t, queryStreamList(t, s, "site=test-site-none").streamIDs())
+
+		resp, body := s.request(t, http.MethodGet, "/api/v1/streams?tag=a/b", nil, nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodPut, "/api/v1/tags/test-ptz", map[string]string{"description": "PTZ cameras"}, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodGet, "/api/v1/tags", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var list struct {
+			Tags []database.Tag `json:"tags"`
+		}
+		require.NoError(t, json.Unmarshal(body, &list))
+		counts := map[string]int{}
+		for _, tag := range list.Tags {
+			counts[tag.Name] = tag.StreamCount
+			if tag.Name == "test-ptz" {
+				assert.Equal(t, "PTZ cameras", tag.Description)
+			}
+		}
+		assert.Equal(t, 1, counts["test-ptz"])
+		assert.Equal(t, 2, counts["test-outdoor"])
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/tags", map[string]string{"name": "test-ptz"}, nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, string(body))
+	})
+
+	t.Run("StartStopWithLimit", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/sites/"+siteID+"/start", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var started siteOperationResponse
+		require.NoError(t, json.Unmarshal(body, &started))
+		assert.Equal(t, 2, started.Started, string(body))
+		assert.Equal(t, 1, started.Limited, string(body))
+
+		var limited string
+		for _, result := range started.Results {
+			if result.Status == "limited" {
+				limited = result.ID
+			}
+		}
+		require.NotEmpty(t, limited)
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/streams/"+limited+"/start", nil, nil)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, string(body))
+
+		resp, body = s.request(t, http.MethodPost, "/api/v1/sites/"+siteID+"/stop", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var stopped siteOperationResponse
+		require.NoError(t, json.Unmarshal(body, &stopped))
+		assert.Equal(t, 2, stopped.Stopped, string(body))
+
+		// 정지 후에는 제한에 걸렸던 스트림도 시작 가능
+		resp, body = s.request(t, http.MethodPost, "/api/v1/streams/"+limited+"/start", nil, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		s.request(t, http.MethodPost, "/api/v1/streams/"+limited+"/stop", nil, nil)
+	})
+
+	t.Run("Unassign", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodDelete, "/api/v1/sites/"+siteID+"/streams/test-site-c", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Empty(t, getStream(t, "test-site-c").SiteID)
+
+		resp, body = s.request(t, http.MethodDelete, "/api/v1/sites/"+siteID+"/streams/test-site-c", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, string(body))
+	})
+
+	t.Run("DeleteSite", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodDelete, "/api/v1/sites/"+siteID, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		// 소속 스트림은 남고 사이트 지정만 해제
+		assert.Empty(t, getStream(t, "test-site-a").SiteID)
+		resp, _ = s.request(t, http.MethodGet, "/api/v1/sites/"+siteID, nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// mockMJPEGCamera는 320x240 JPEG 프레임을 multipart/x-mixed-replace로 보내는 테스트용 카메라입니다
+func mockMJPEGCamera(t *testing.T) *httptest.Server {
+	img := image.NewRGBA(image.Rect(0, 0, 320, 240))
+	for y := 0; y < 240; y++ {
+		for x := 0; x < 320; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var frame bytes.Buffer
+	require.NoError(t, jpeg.Encode(&frame, img, &jpeg.Options{Quality: 80}))
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=frame")
+		flusher := w.(http.Flusher)
+
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			fmt.Fprintf(w, "--frame\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", frame.Len())
+			w.Write(frame.Bytes())
+			fmt.Fprint(w, "\r\n")
+			flusher.Flush()
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}))
+}
+
+// getSnapshot은 스트림 스냅샷을 요청합니다
+func getSnapshot(t *testing.T, s *testServer, streamID, query string, header http.Header) (*http.Response, []byte) {
+	t.Helper()
+	return s.request(t, http.MethodGet, "/api/v1/streams/"+streamID+"/snapshot.jpg"+query, nil, header)
+}
+
+// TestSnapshot은 MJPEG 카메라 스트림의 스냅샷 생성과 크기 조정을 테스트합니다
+func TestSnapshot(t *testing.T) {
+	s := startTestServer(t, testServerOptions{Extra: "snapshot:\n  enabled: true\n"})
+
+	camera := mockMJPEGCamera(t)
+	defer func() {
+		// 서버가 아직 스트림을 받고 있으므로 연결을 먼저 끊음
+		camera.CloseClientConnections()
+		camera.Close()
+	}()
+
+	createStream := func(t *testing.T, stream database.Stream) {
+		t.Helper()
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", stream, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	}
+
+	t.Run("NotRunning", func(t *testing.T) {
+		createStream(t, database.Stream{
+			ID:             "test-snapshot-idle",
+			Name:           "Test Snapshot Idle",
+			Source:         "rtsp://test.com/idle",
+			SourceOnDemand: true,
+			RTSPTransport:  "tcp",
+		})
+
+		resp, body := getSnapshot(t, s, "test-snapshot-idle", "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, string(body))
+	})
+
+	createStream(t, database.Stream{
+		ID:     "test-snapshot",
+		Name:   "Test Snapshot",
+		Source: camera.URL + "/video.mjpg",
+	})
+
+	var original []byte
+	require.Eventually(t, func() bool {
+		resp, body := getSnapshot(t, s, "test-snapshot", "", nil)
+		original = body
+		return resp.StatusCode == http.StatusOK
+	}, 15*time.Second, 500*time.Millisecond, "snapshot was not available")
+
+	t.Run("Original", func(t *testing.T) {
+		config, err := jpeg.DecodeConfig(bytes.NewReader(original))
+		require.NoError(t, err)
+		assert.Equal(t, 320, config.Width)
+		assert.Equal(t, 240, config.Height)
+	})
+
+	t.Run("Resize", func(t *testing.T) {
+		resp, body := getSnapshot(t, s, "test-snapshot", "?width=160", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(body))
+		require.NoError(t, err)
+		assert.Equal(t, 160, config.Width)
+		assert.Equal(t, 120, config.Height)
+
+		// 비율 유지: 100x100 상자에 맞추면 100x75
+		resp, body = getSnapshot(t, s, "test-snapshot", "?width=100&height=100", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		config, err = jpeg.DecodeConfig(bytes.NewReader(body))
+		require.NoError(t, err)
+		assert.Equal(t, 100, config.Width)
+		assert.Equal(t, 75, config.Height)
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		resp, _ := getSnapshot(t, s, "test-snapshot", "", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		lastModified := resp.Header.Get("Last-Modified")
+		require.NotEmpty(t, lastModified)
+
+		resp, _ = getSnapshot(t, s, "test-snapshot", "", http.Header{
+			"If-Modified-Since": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)},
+		})
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	})
+
+	t.Run("InvalidSize", func(t *testing.T) {
+		resp, body := getSnapshot(t, s, "test-snapshot", "?width=-1", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		resp, body = getSnapshot(t, s, "test-snapshot", "?height=abc", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+	})
+}
@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/status"
+)
+
+// statusEvent는 수신한 상태 이벤트입니다 (data는 이벤트 종류별 구조)
+type statusEvent struct {
+	Type     string                 `json:"type"`
+	StreamID string                 `json:"stream_id"`
+	Time     time.Time              `json:"time"`
+	Data     map[string]interface{} `json:"data"`
+}
+
+// subscribeStatusEvents는 SSE로 상태 이벤트를 구독합니다 (테스트 종료 시 연결 해제)
+func subscribeStatusEvents(t *testing.T, s *testServer, query string) <-chan statusEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/api/v1/events"+query, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make(chan statusEvent, 64)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+
+		var eventType string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				eventType = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				var event statusEvent
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+					continue
+				}
+				if event.Type != eventType {
+					continue
+				}
+				events <- event
+			}
+		}
+	}()
+
+	return events
+}
+
+// waitStatusEvent는 조건을 만족하는 상태 이벤트가 올 때까지 기다립니다
+func waitStatusEvent(t *testing.T, events <-chan statusEvent, timeout time.Duration, match func(statusEvent) bool) statusEvent {
+	t.Helper()
+
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "status event stream closed")
+			if match(event) {
+				return event
+			}
+		case <-deadline:
+			t.Fatalf("expected status event not received within %s", timeout)
+			return statusEvent{}
+		}
+	}
+}
+
+// TestStatusEvents는 SSE와 WebSocket subscribe로 스트림 상태 변화를 받는지 테스트합니다
+func TestStatusEvents(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	camera := mockMJPEGCamera(t)
+	defer func() {
+		camera.CloseClientConnections()
+		camera.Close()
+	}()
+
+	const streamID = "test-status-events"
+	events := subscribeStatusEvents(t, s, "?streams="+streamID)
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID:     streamID,
+		Name:   "Test Status Events",
+		Source: camera.URL + "/video.mjpg",
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	t.Run("Online", func(t *testing.T) {
+		event := waitStatusEvent(t, events, 15*time.Second, func(e statusEvent) bool {
+			return e.Type == status.EventStreamState && e.Data["state"] == status.StateOnline
+		})
+		assert.Equal(t, streamID, event.StreamID)
+		assert.NotEmpty(t, event.Data["source_type"])
+		assert.False(t, event.Time.IsZero())
+	})
+
+	t.Run("Bitrate", func(t *testing.T) {
+		event := waitStatusEvent(t, events, 15*time.Second, func(e statusEvent) bool {
+			return e.Type == status.EventStreamBitrate
+		})
+		assert.Equal(t, streamID, event.StreamID)
+		assert.Greater(t, event.Data["received_bps"], 0.0)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		// 다른 스트림만 구독하면 초기 상태에도 이 스트림이 없음
+		other := subscribeStatusEvents(t, s, "?streams=test-status-other")
+		select {
+		case event := <-other:
+			t.Fatalf("unexpected status event for filtered stream: %+v", event)
+		case <-time.After(time.Second):
+		}
+	})
+
+	t.Run("WebSocketSubscribe", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(strings.Replace(s.URL, "http://", "ws://", 1)+"/ws", nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		require.NoError(t, conn.WriteJSON(map[string]interface{}{
+			"type":    "subscribe",
+			"payload": map[string]interface{}{"streams": []string{streamID}, "types": []string{status.EventStreamState}},
+		}))
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		var msg struct {
+			Type     string      `json:"type"`
+			StreamID string      `json:"streamId"`
+			Payload  statusEvent `json:"payload"`
+		}
+		require.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, "status", msg.Type)
+		assert.Equal(t, streamID, msg.StreamID)
+		assert.Equal(t, status.EventStreamState, msg.Payload.Type)
+		assert.Equal(t, status.StateOnline, msg.Payload.Data["state"])
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		camera.CloseClientConnections()
+
+		event := waitStatusEvent(t, events, 15*time.Second, func(e statusEvent) bool {
+			return e.Type == status.EventStreamState && e.Data["state"] == status.StateOffline
+		})
+		assert.Equal(t, streamID, event.StreamID)
+	})
+}
@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// streamListPage는 GET /api/v1/streams 응답입니다
+type streamListPage struct {
+	Streams []struct {
+		ID             string `json:"id"`
+		Name           string `json:"name"`
+		SourceOnDemand bool   `json:"source_on_demand"`
+	} `json:"streams"`
+	Count     int `json:"count"`
+	ItemCount int `json:"itemCount"`
+	PageCount int `json:"pageCount"`
+}
+
+// queryStreamList는 쿼리 조건으로 스트림 목록을 조회합니다
+func queryStreamList(t *testing.T, s *testServer, query string) streamListPage {
+	t.Helper()
+
+	resp, body := s.request(t, http.MethodGet, "/api/v1/streams?"+query, nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var page streamListPage
+	require.NoError(t, json.Unmarshal(body, &page))
+	return page
+}
+
+// streamIDs는 목록의 스트림 ID를 순서대로 반환합니다
+func (p streamListPage) streamIDs() []string {
+	ids := make([]string, 0, len(p.Streams))
+	for _, stream := range p.Streams {
+		ids = append(ids, stream.ID)
+	}
+	return ids
+}
+
+// TestStreamListQuery는 스트림 목록의 페이지, 필터, 정렬을 테스트합니다
+func TestStreamListQuery(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	for _, stream := range []database.Stream{
+		{ID: "test-list-b", Name: "Test List B", Source: "rtsp://test.com/b", SourceOnDemand: true, RTSPTransport: "tcp"},
+		{ID: "test-list-a", Name: "Test List A", Source: "rtsp://test.com/a", SourceOnDemand: true, RTSPTransport: "tcp"},
+		{ID: "test-list-c", Name: "Test List C", Source: "rtsp://test.com/c", SourceOnDemand: true, RTSPTransport: "tcp"},
+	} {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", stream, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	}
+
+	t.Run("Unpaginated", func(t *testing.T) {
+		page := queryStreamList(t, s, "name=test-list-")
+		assert.Equal(t, 3, page.Count)
+		assert.Equal(t, 3, page.ItemCount)
+		assert.Equal(t, 1, page.PageCount)
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		first := queryStreamList(t, s, "name=test-list-&sort=name&itemsPerPage=2&page=0")
+		assert.Equal(t, []string{"test-list-a", "test-list-b"}, first.streamIDs())
+		assert.Equal(t, 3, first.ItemCount)
+		assert.Equal(t, 2, first.PageCount)
+
+		second := queryStreamList(t, s, "name=test-list-&sort=name&itemsPerPage=2&page=1")
+		assert.Equal(t, []string{"test-list-c"}, second.streamIDs())
+
+		beyond := queryStreamList(t, s, "name=test-list-&sort=name&itemsPerPage=2&page=5")
+		assert.Empty(t, beyond.Streams)
+		assert.Equal(t, 3, beyond.ItemCount)
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		page := queryStreamList(t, s, "name=test-list-&sort=name&order=desc")
+		assert.Equal(t, []string{"test-list-c", "test-list-b", "test-list-a"}, page.streamIDs())
+
+		// 기본 정렬은 생성 시각 내림차순
+		page = queryStreamList(t, s, "name=test-list-")
+		assert.Equal(t, []string{"test-list-c", "test-list-a", "test-list-b"}, page.streamIDs())
+
+		page = queryStreamList(t, s, "name=test-list-&sort=bitrate&itemsPerPage=2")
+		assert.Len(t, page.Streams, 2)
+		assert.Equal(t, 3, page.ItemCount)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		page := queryStreamList(t, s, "name=TEST-LIST-A")
+		assert.Equal(t, []string{"test-list-a"}, page.streamIDs())
+
+		page = queryStreamList(t, s, "name=test-list-&on_demand=false")
+		assert.Empty(t, page.Streams)
+
+		// 요청 시 시작 스트림은 시청 전까지 연결되지 않음
+		page = queryStreamList(t, s, "name=test-list-&status=online")
+		assert.Empty(t, page.Streams)
+		assert.Equal(t, 0, page.ItemCount)
+
+		page = queryStreamList(t, s, "name=test-list-&status=offline")
+		assert.Equal(t, 3, page.ItemCount)
+
+		page = queryStreamList(t, s, "name=test-list-&codec=H265")
+		assert.Empty(t, page.Streams)
+
+		// LIKE 특수 문자는 그대로 비교
+		page = queryStreamList(t, s, "name=test%25list")
+		assert.Empty(t, page.Streams)
+	})
+
+	t.Run("PathsList", func(t *testing.T) {
+		resp, body := s.request(t, http.MethodGet, "/v3/config/paths/list?name=test-list-&sort=name&itemsPerPage=2&page=1", nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var result struct {
+			PageCount int `json:"pageCount"`
+			ItemCount int `json:"itemCount"`
+			Items     []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, 2, result.PageCount)
+		assert.Equal(t, 3, result.ItemCount)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, "test-list-c", result.Items[0].Name)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, query := range []string{
+			"sort=size",
+			"order=up",
+			"status=broken",
+			"on_demand=maybe",
+			"itemsPerPage=0",
+			"page=-1",
+		} {
+			resp, body := s.request(t, http.MethodGet, "/api/v1/streams?"+query, nil, nil)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query+": "+string(body))
+		}
+	})
+}
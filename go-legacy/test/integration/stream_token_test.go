@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+const tokenAuthConfig = `auth:
+  enabled: true
+  api_keys:
+    - name: admin
+      key: admin-key
+      role: admin
+  tokens:
+    secret: integration-token-secret
+`
+
+// issueToken은 IP가 지정된 스트림 재생 토큰을 발급합니다
+func issueToken(t *testing.T, s *testServer, streamID, ip string) string {
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams/"+streamID+"/token", map[string]interface{}{"ip": ip}, bearer("admin-key"))
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	return result.Token
+}
+
+// TestStreamTokenIPBinding은 토큰 IP 제한이 X-Forwarded-For 위조로 우회되지 않는지 테스트합니다
+func TestStreamTokenIPBinding(t *testing.T) {
+	spoofed := http.Header{"X-Forwarded-For": []string{"10.9.9.9"}}
+
+	t.Run("ForwardedHeaderIgnoredByDefault", func(t *testing.T) {
+		s := startTestServer(t, testServerOptions{Extra: tokenAuthConfig})
+		camera := mockRTSPCamera(t)
+
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: "cam1", Name: "cam1", Source: camera.URL("cam1"),
+		}, bearer("admin-key"))
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+		playlist := "/hls/cam1/index.m3u8?token="
+
+		bound := url.QueryEscape(issueToken(t, s, "cam1", "10.9.9.9"))
+		resp, _ = s.request(t, http.MethodGet, playlist+bound, nil, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		// 신뢰하지 않는 클라이언트의 X-Forwarded-For는 무시
+		resp, _ = s.request(t, http.MethodGet, playlist+bound, nil, spoofed)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		resp, _ = s.request(t, http.MethodGet, playlist+bound, nil, http.Header{"X-Real-IP": []string{"10.9.9.9"}})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		// 접속 주소로 발급한 토큰은 사용 가능
+		local := url.QueryEscape(issueToken(t, s, "cam1", "127.0.0.1"))
+		resp, _ = s.request(t, http.MethodGet, playlist+local, nil, spoofed)
+		assert.NotEqual(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("TrustedProxy", func(t *testing.T) {
+		s := startTestServer(t, testServerOptions{
+			Server: "  trusted_proxies: [127.0.0.1]",
+			Extra:  tokenAuthConfig,
+		})
+		camera := mockRTSPCamera(t)
+
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: "cam1", Name: "cam1", Source: camera.URL("cam1"),
+		}, bearer("admin-key"))
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+		bound := url.QueryEscape(issueToken(t, s, "cam1", "10.9.9.9"))
+		resp, _ = s.request(t, http.MethodGet, "/hls/cam1/index.m3u8?token="+bound, nil, spoofed)
+		assert.NotEqual(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp, _ = s.request(t, http.MethodGet, "/hls/cam1/index.m3u8?token="+bound, nil, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+// TestGroupMasterPlaylistToken은 스트림 재생 토큰으로 그룹 master 플레이리스트를 받을 수 있는지 테스트합니다
+// 토큰의 스트림만 variant로 포함되고, variant는 미디어 플레이리스트와 CODECS를 가집니다
+func TestGroupMasterPlaylistToken(t *testing.T) {
+	s := startTestServer(t, testServerOptions{Extra: tokenAuthConfig})
+	camera := mockRTSPCamera(t)
+
+	for _, id := range []string{"main", "sub"} {
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: id, Name: id, Source: camera.URL(id),
+		}, bearer("admin-key"))
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	}
+	resp, body := s.request(t, http.MethodPost, "/api/v1/groups", database.StreamGroup{
+		ID: "lobby", Name: "lobby",
+		Renditions: []database.StreamRendition{
+			{Name: "main", StreamID: "main", Bandwidth: 2000000, Width: 320, Height: 240},
+			{Name: "sub", StreamID: "sub", Bandwidth: 500000},
+		},
+	}, bearer("admin-key"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	resp, _ = s.request(t, http.MethodGet, "/hls/groups/lobby/master.m3u8", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	token := url.QueryEscape(issueToken(t, s, "sub", ""))
+
+	var playlist string
+	require.Eventually(t, func() bool {
+		resp, body := s.request(t, http.MethodGet, "/hls/groups/lobby/master.m3u8?token="+token, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		playlist = string(body)
+		return strings.Contains(playlist, "CODECS=")
+	}, 10*time.Second, 200*time.Millisecond)
+
+	// 테스트 영상은 Baseline 프로파일 (profile_idc 66, constraint 0xE0, level 3.0)
+	assert.Contains(t, playlist, "#EXT-X-STREAM-INF:BANDWIDTH=500000,CODECS=\"avc1.42e01e\"\n../../sub/main_stream.m3u8?token=")
+	assert.NotContains(t, playlist, "../../main/")
+}
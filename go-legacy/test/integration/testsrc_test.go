@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// testPatternSource는 실제 카메라 대신 사용하는 내장 테스트 패턴 소스
+const testPatternSource = "testsrc://bars?size=640x480&fps=25"
+
+// TestTestPatternSource는 내장 테스트 패턴(testsrc://) 소스를 테스트합니다
+func TestTestPatternSource(t *testing.T) {
+	s := startTestServer(t, testServerOptions{})
+
+	getStream := func(t *testing.T, id string) StreamResponse {
+		resp, body := s.request(t, http.MethodGet, "/api/v1/streams/"+id, nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var got StreamResponse
+		require.NoError(t, json.Unmarshal(body, &got))
+		return got
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		for _, source := range []string{
+			"testsrc://bars?fps=0",
+			"testsrc://bars?size=641x480",
+			"testsrc://bars?bitrate=fast",
+			"testsrc://noise",
+		} {
+			resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+				ID: "test-testsrc-bad", Name: "x", Source: source, SourceOnDemand: true,
+			}, nil)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%s: %s", source, string(body))
+		}
+	})
+
+	t.Run("GeneratesH264", func(t *testing.T) {
+		const id = "test-testsrc"
+		resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID: id, Name: id, Source: "testsrc://bars?size=320x240&fps=15&bitrate=400k",
+		}, nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+		// 연결 직후 첫 샘플
+		var first StreamResponse
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			first = getStream(t, id)
+			if first.RuntimeInfo != nil && first.RuntimeInfo["packets_received"].(float64) > 0 {
+				break
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+		require.NotNil(t, first.RuntimeInfo)
+		assert.Equal(t, "H264", first.RuntimeInfo["codec"])
+		require.Greater(t, first.RuntimeInfo["packets_received"].(float64), float64(0))
+
+		// 필러로 지정한 비트레이트(400kbit/s = 50KB/s)에 맞춰 생성
+		time.Sleep(2 * time.Second)
+		second := getStream(t, id)
+		received := second.RuntimeInfo["bytes_received"].(float64) - first.RuntimeInfo["bytes_received"].(float64)
+		assert.InDelta(t, 100_000, received, 30_000, "bytes received in 2s")
+	})
+}
+
+// TestTestPatternBitstream은 내장 RTSP 서버로 받은 테스트 패턴이 올바른 H.264인지 테스트합니다
+// SPS/PPS, GOP마다 IDR, SPS의 해상도를 확인합니다
+func TestTestPatternBitstream(t *testing.T) {
+	const gop = 10
+
+	s := startTestServer(t, testServerOptions{RTSP: true})
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+		ID: "bars", Name: "bars", Source: fmt.Sprintf("testsrc://bars?size=320x240&fps=20&gop=%d", gop),
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	u, err := base.ParseURL(fmt.Sprintf("rtsp://127.0.0.1:%d/bars", s.RTSPPort))
+	require.NoError(t, err)
+
+	c := gortsplib.Client{ReadTimeout: 10 * time.Second}
+	require.NoError(t, c.Start(u.Scheme, u.Host))
+	defer c.Close()
+
+	var desc *description.Session
+	require.Eventually(t, func() bool {
+		desc, _, err = c.Describe(u)
+		return err == nil
+	}, 10*time.Second, 500*time.Millisecond)
+
+	var forma *format.H264
+	medi := desc.FindFormat(&forma)
+	require.NotNil(t, medi, "no H264 track")
+
+	decoder, err := forma.CreateDecoder()
+	require.NoError(t, err)
+
+	aus := make(chan [][]byte, 100)
+	_, err = c.Setup(desc.BaseURL, medi, 0, 0)
+	require.NoError(t, err)
+	c.OnPacketRTP(medi, forma, func(pkt *rtp.Packet) {
+		au, err := decoder.Decode(pkt)
+		if err != nil {
+			if !errors.Is(err, rtph264.ErrMorePacketsNeeded) && !errors.Is(err, rtph264.ErrNonStartingPacketAndNoPrevious) {
+				t.Logf("decode error: %v", err)
+			}
+			return
+		}
+		select {
+		case aus <- au:
+		default:
+		}
+	})
+	_, err = c.Play(nil)
+	require.NoError(t, err)
+
+	// 첫 IDR은 한 GOP 안에, 다음 IDR은 정확히 GOP 간격으로
+	var idrs []int
+	var sps, pps []byte
+	timeout := time.After(10 * time.Second)
+	for i := 0; len(idrs) < 2; i++ {
+		var au [][]byte
+		select {
+		case au = <-aus:
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for IDR frames", "IDR frames at %v", idrs)
+		}
+
+		if !h264.IsRandomAccess(au) {
+			if len(idrs) == 0 {
+				require.Less(t, i, gop, "no IDR within the first GOP")
+			}
+			continue
+		}
+
+		idrs = append(idrs, i)
+		for _, nalu := range au {
+			switch h264.NALUType(nalu[0] & 0x1f) {
+			case h264.NALUTypeSPS:
+				sps = nalu
+			case h264.NALUTypePPS:
+				pps = nalu
+			}
+		}
+		require.NotEmpty(t, sps, "IDR access unit without SPS")
+		require.NotEmpty(t, pps, "IDR access unit without PPS")
+	}
+	assert.Equal(t, gop, idrs[1]-idrs[0], "IDR interval")
+
+	var parsed h264.SPS
+	require.NoError(t, parsed.Unmarshal(sps))
+	assert.Equal(t, 320, parsed.Width())
+	assert.Equal(t, 240, parsed.Height())
+	assert.Equal(t, uint8(66), parsed.ProfileIdc, "Baseline profile")
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+)
+
+// TestTranscodeProfiles는 트랜스코딩 프로필 CRUD와 파생 스트림 목록을 테스트합니다
+// (트랜스코더 실행은 ffmpeg가 필요하므로 여기서는 확인하지 않음)
+func TestTranscodeProfiles(t *testing.T) {
+	s := startTestServer(t, testServerOptions{RTSP: true, Extra: "transcode:\n  enabled: true\n"})
+
+	request := func(t *testing.T, method, path string, body interface{}) (*http.Response, map[string]interface{}) {
+		t.Helper()
+		resp, data := s.request(t, method, path, body, nil)
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &result), string(data))
+		return resp, result
+	}
+
+	const name = "test_h264_480p"
+
+	t.Run("Create", func(t *testing.T) {
+		resp, result := request(t, http.MethodPost, "/api/v1/transcode/profiles", map[string]interface{}{
+			"name":       name,
+			"codec":      "H264",
+			"resolution": "480p",
+			"bitrate":    "1000k",
+			"gop":        50,
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, result)
+		assert.Equal(t, "h264", result["codec"])
+
+		resp, result = request(t, http.MethodPost, "/api/v1/transcode/profiles", map[string]interface{}{
+			"name":  name,
+			"codec": "h264",
+		})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode, result)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, profile := range []map[string]interface{}{
+			{"name": "bad~name", "codec": "h264"},
+			{"name": "test_vp8", "codec": "vp8"},
+			{"name": "test_odd", "codec": "h264", "resolution": "641x360"},
+			{"name": "test_rate", "codec": "h264", "bitrate": "fast"},
+		} {
+			resp, result := request(t, http.MethodPost, "/api/v1/transcode/profiles", profile)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, result)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		resp, result := request(t, http.MethodPut, "/api/v1/transcode/profiles/"+name, map[string]interface{}{
+			"codec":      "hevc",
+			"resolution": "854x480",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode, result)
+		assert.Equal(t, "h265", result["codec"])
+		assert.Equal(t, "854x480", result["resolution"])
+
+		resp, result = request(t, http.MethodPut, "/api/v1/transcode/profiles/test_missing", map[string]interface{}{
+			"codec": "h264",
+		})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, result)
+	})
+
+	t.Run("DerivedStreams", func(t *testing.T) {
+		resp, result := request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID:             "test-transcode",
+			Name:           "Test Transcode",
+			Source:         "rtsp://test.com/stream",
+			SourceOnDemand: true,
+			RTSPTransport:  "tcp",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode, result)
+
+		resp, result = request(t, http.MethodGet, "/api/v1/streams/test-transcode/transcodes", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, result)
+
+		var ids []string
+		for _, item := range result["transcodes"].([]interface{}) {
+			derived := item.(map[string]interface{})
+			ids = append(ids, derived["id"].(string))
+			assert.Equal(t, false, derived["running"])
+		}
+		assert.Contains(t, ids, "test-transcode~"+name)
+
+		resp, result = request(t, http.MethodGet, "/api/v1/streams/test-missing/transcodes", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, result)
+	})
+
+	t.Run("ReservedStreamID", func(t *testing.T) {
+		resp, result := request(t, http.MethodPost, "/api/v1/streams", database.Stream{
+			ID:     "test-cam~h264",
+			Name:   "Test Reserved",
+			Source: "rtsp://test.com/stream",
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, result)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		resp, result := request(t, http.MethodDelete, "/api/v1/transcode/profiles/"+name, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, result)
+
+		resp, result = request(t, http.MethodDelete, "/api/v1/transcode/profiles/"+name, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, result)
+	})
+}
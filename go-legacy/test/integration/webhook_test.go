@@ -0,0 +1,209 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cctv3/internal/database"
+	"github.com/yourusername/cctv3/internal/webhook"
+)
+
+// webhookRequest는 서버가 웹훅 수신 측으로 보낸 요청입니다
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookReceiver는 요청을 채널로 전달하는 로컬 수신 서버를 만듭니다
+// 처음 failures번의 요청에는 500으로 응답합니다
+func newWebhookReceiver(failures int32) (*httptest.Server, <-chan webhookRequest) {
+	requests := make(chan webhookRequest, 16)
+	var count atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- webhookRequest{header: r.Header.Clone(), body: body}
+
+		if count.Add(1) <= failures {
+			http.Error(w, "temporarily unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	return server, requests
+}
+
+// createWebhook은 웹훅을 등록하고 응답(서명 키 포함)을 반환합니다
+func createWebhook(t *testing.T, s *testServer, w database.Webhook) database.Webhook {
+	t.Helper()
+
+	resp, body := s.request(t, http.MethodPost, "/api/v1/webhooks", w, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var created database.Webhook
+	require.NoError(t, json.Unmarshal(body, &created))
+	return created
+}
+
+// waitDelivery는 전송 기록이 조건을 만족할 때까지 기다립니다
+func waitDelivery(t *testing.T, s *testServer, webhookID, deliveryID int64, timeout time.Duration, done func(*database.WebhookDelivery) bool) *database.WebhookDelivery {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, body := s.request(t, http.MethodGet, fmt.Sprintf("/api/v1/webhooks/%d/deliveries", webhookID), nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var result struct {
+			Deliveries []*database.WebhookDelivery `json:"deliveries"`
+		}
+		require.NoError(t, json.Unmarshal(body, &result))
+
+		for _, delivery := range result.Deliveries {
+			if delivery.ID == deliveryID && done(delivery) {
+				return delivery
+			}
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("delivery %d did not reach the expected state within %s: %s", deliveryID, timeout, body)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// delivered는 전송이 완료되었는지 반환합니다
+func delivered(d *database.WebhookDelivery) bool {
+	return d.Status == database.DeliveryDelivered
+}
+
+// receiveWebhook은 수신 서버로 요청이 올 때까지 기다립니다
+func receiveWebhook(t *testing.T, requests <-chan webhookRequest, timeout time.Duration) webhookRequest {
+	t.Helper()
+
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(timeout):
+		t.Fatalf("no webhook request received within %s", timeout)
+		return webhookRequest{}
+	}
+}
+
+// TestWebhooks는 웹훅 등록, HMAC 서명, 전송 기록, 실패 시 재시도를 테스트합니다
+func TestWebhooks(t *testing.T) {
+	// 재시도 대기 시간 1초
+	s := startTestServer(t, testServerOptions{Extra: "webhooks:\n  enabled: true\n  retry_backoff: 1\n"})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, invalid := range []database.Webhook{
+			{Name: "test-invalid-url", URL: "ftp://example.com/hook"},
+			{Name: "test-invalid-event", URL: "http://example.com/hook", Events: []string{"stream.unknown"}},
+			{URL: "http://example.com/hook"},
+		} {
+			resp, body := s.request(t, http.MethodPost, "/api/v1/webhooks", invalid, nil)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+		}
+
+		resp, body := s.request(t, http.MethodGet, "/api/v1/webhooks/999999", nil, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, string(body))
+	})
+
+	t.Run("SignedDelivery", func(t *testing.T) {
+		receiver, requests := newWebhookReceiver(0)
+		defer receiver.Close()
+
+		const secret = "test-webhook-secret"
+		created := createWebhook(t, s, database.Webhook{
+			Name:    "test-signed",
+			URL:     receiver.URL + "/hook",
+			Secret:  secret,
+			Events:  []string{webhook.EventStreamOnline, webhook.EventStreamOffline},
+			Enabled: true,
+		})
+		assert.Equal(t, secret, created.Secret)
+
+		// 조회 응답에는 서명 키가 포함되지 않음
+		resp, body := s.request(t, http.MethodGet, fmt.Sprintf("/api/v1/webhooks/%d", created.ID), nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.NotContains(t, string(body), secret)
+
+		resp, body = s.request(t, http.MethodPost, fmt.Sprintf("/api/v1/webhooks/%d/test", created.ID), nil, nil)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode, string(body))
+
+		var queued database.WebhookDelivery
+		require.NoError(t, json.Unmarshal(body, &queued))
+
+		req := receiveWebhook(t, requests, 5*time.Second)
+		assert.Equal(t, webhook.EventTest, req.header.Get(webhook.HeaderEvent))
+		assert.Equal(t, strconv.FormatInt(queued.ID, 10), req.header.Get(webhook.HeaderDelivery))
+
+		timestamp, err := strconv.ParseInt(req.header.Get(webhook.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.Sign(secret, timestamp, req.body), req.header.Get(webhook.HeaderSignature))
+		assert.NotEqual(t, webhook.Sign("wrong-secret", timestamp, req.body), req.header.Get(webhook.HeaderSignature))
+
+		var payload struct {
+			Event string                 `json:"event"`
+			Time  time.Time              `json:"time"`
+			Data  map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(req.body, &payload))
+		assert.Equal(t, webhook.EventTest, payload.Event)
+		assert.False(t, payload.Time.IsZero())
+
+		delivery := waitDelivery(t, s, created.ID, queued.ID, 5*time.Second, delivered)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+	})
+
+	t.Run("RetryAfterFailure", func(t *testing.T) {
+		receiver, requests := newWebhookReceiver(1)
+		defer receiver.Close()
+
+		created := createWebhook(t, s, database.Webhook{
+			Name:    "test-retry",
+			URL:     receiver.URL,
+			Enabled: true,
+		})
+		assert.NotEmpty(t, created.Secret, "secret should be generated")
+
+		resp, body := s.request(t, http.MethodPost, fmt.Sprintf("/api/v1/webhooks/%d/test", created.ID), nil, nil)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode, string(body))
+
+		var queued database.WebhookDelivery
+		require.NoError(t, json.Unmarshal(body, &queued))
+
+		first := receiveWebhook(t, requests, 5*time.Second)
+		pending := waitDelivery(t, s, created.ID, queued.ID, 5*time.Second, func(d *database.WebhookDelivery) bool {
+			return d.Attempts == 1
+		})
+		assert.Equal(t, database.DeliveryPending, pending.Status)
+		assert.Equal(t, http.StatusInternalServerError, pending.LastStatusCode)
+		assert.Contains(t, pending.LastError, "500")
+		assert.True(t, pending.NextAttempt.After(pending.UpdatedAt))
+
+		// 재시도는 같은 전송 ID와 본문으로 다시 보냄 (백오프 대기 후)
+		second := receiveWebhook(t, requests, time.Minute)
+		assert.Equal(t, first.header.Get(webhook.HeaderDelivery), second.header.Get(webhook.HeaderDelivery))
+		assert.Equal(t, first.body, second.body)
+
+		delivery := waitDelivery(t, s, created.ID, queued.ID, 5*time.Second, delivered)
+		assert.Equal(t, 2, delivery.Attempts)
+
+		resp, body = s.request(t, http.MethodGet,
+			fmt.Sprintf("/api/v1/webhooks/deliveries?webhook_id=%d&status=delivered", created.ID), nil, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Contains(t, string(body), fmt.Sprintf(`"id":%d`, queued.ID))
+	})
+}
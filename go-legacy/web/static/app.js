@@ -66,7 +66,8 @@ class WebRTCPlayer {
             this.updateStatus('connecting');
 
             // WebSocket 연결
-            const wsUrl = `ws://${window.location.host}/ws`;
+            // 페이지 URL의 ?token= / ?api_key= 를 시그널링 연결에 전달 (임베드용)
+            const wsUrl = `ws://${window.location.host}/ws${window.location.search}`;
             this.ws = new WebSocket(wsUrl);
 
             this.ws.onopen = () => {
@@ -18,7 +18,8 @@ class WebSocketManager {
         WebSocketManager.instance = this;
 
         this.ws = null;
-        this.serverUrl = `ws://${window.location.host}/ws`;
+        // 페이지 URL의 ?token= / ?api_key= 를 시그널링 연결에 전달 (임베드용)
+        this.serverUrl = `ws://${window.location.host}/ws${window.location.search}`;
         this.connected = false;
         this.reconnecting = false;
         this.reconnectDelay = 3000;